@@ -179,6 +179,8 @@ func main() {
 		fsParams := storage.NewMemoryParams("tmp", "testdata", logger)
 		storer := storage.NewMemory(fsParams)
 		loader := uploader.NewUploader(dbConnection, logger, storer)
+		// Fixture files are known to be safe, so they don't wait for a virus scan
+		loader.SetTrusted(true)
 
 		tdgs.E2eBasicScenario.Run(dbConnection, loader, logger, storer)
 		logger.Info("Success! Created e2e test data.")
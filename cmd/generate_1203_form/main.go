@@ -41,29 +41,31 @@ func main() {
 		log.Fatal("Usage: generate_1203_form -shipment <29cb984e-c70d-46f0-926d-cd89e07a6ec3>")
 	}
 
-	formLayout := paperwork.Form1203Layout
+	formLayout, err := paperwork.LoadFormLayoutAsset(paperwork.Form1203LayoutName)
+	noErr(err)
 
-	templateImage, err := os.Open(formLayout.TemplateImagePath)
+	template, err := os.Open(formLayout.TemplatePath())
 	noErr(err)
-	defer templateImage.Close()
+	defer template.Close()
 
 	// Define the data here that you want to populate the form with. Data will only be populated
 	// in the form if the field name exist BOTH in the fields map and your data below
 	parsedID := uuid.Must(uuid.FromString(*shipmentID))
 
-	// Build our form with a template image and field placement
-	formFiller := paperwork.NewFormFiller()
+	// Build our form with the layout's template and field placement
+	formFiller := paperwork.NewFillerForLayout(formLayout)
 
 	gbl, err := models.FetchGovBillOfLadingFormValues(db, parsedID)
 	noErr(err)
 
-	// This is very useful for getting field positioning right initially
-	if *debug {
-		formFiller.Debug()
+	// This is very useful for getting field positioning right initially. Fields of fillable PDFs are
+	// positioned by the PDF, so there's nothing to debug.
+	if imageFormFiller, ok := formFiller.(*paperwork.FormFiller); ok && *debug {
+		imageFormFiller.Debug()
 	}
 
 	// Populate form fields with provided data
-	err = formFiller.AppendPage(templateImage, formLayout.FieldsLayout, gbl)
+	err = formFiller.AppendPage(template, formLayout.FieldsLayout, gbl)
 	noErr(err)
 
 	filename := fmt.Sprintf("form-1203-%s.pdf", time.Now().Format(time.RFC3339))
@@ -161,6 +161,8 @@ func processInvoice(db *pop.Connection, shipment models.Shipment, invoiceModel m
 	if err != nil {
 		return nil, err
 	}
+	icn := invoice858C.ISA.InterchangeControlNumber
+	invoiceModel.InterchangeControlNumber = &icn
 
 	if sendToGex {
 		fmt.Println("Sending to GEX. . .")
@@ -57,14 +57,6 @@ func main() {
 	// in the form if the field name exist BOTH in the fields map and your data below
 	parsedID := uuid.Must(uuid.FromString(*moveID))
 
-	// Build our form with a template image and field placement
-	formFiller := paperwork.NewFormFiller()
-
-	// This is very useful for getting field positioning right initially
-	if *debug {
-		formFiller.Debug()
-	}
-
 	logger, err := logging.Config(*env, true)
 	if err != nil {
 		log.Fatalf("Failed to initialize Zap logging due to %v", err)
@@ -86,12 +78,23 @@ func main() {
 		log.Fatalf("%s", errors.Wrap(err, "Error calculating obligations "))
 	}
 
-	page1Data, page2Data, err := models.FormatValuesShipmentSummaryWorksheet(ssfd)
+	page1Data, page2Data, continuationData, err := models.FormatValuesShipmentSummaryWorksheet(ssfd)
 	noErr(err)
 
 	// page 1
-	page1Layout := paperwork.ShipmentSummaryPage1Layout
-	page1Template, err := os.Open(page1Layout.TemplateImagePath)
+	page1Layout, err := paperwork.LoadFormLayoutAsset(paperwork.ShipmentSummaryPage1LayoutName)
+	noErr(err)
+
+	// Build our form with the layout's template and field placement
+	formFiller := paperwork.NewFillerForLayout(page1Layout)
+
+	// This is very useful for getting field positioning right initially. Fields of fillable PDFs are
+	// positioned by the PDF, so there's nothing to debug.
+	if imageFormFiller, ok := formFiller.(*paperwork.FormFiller); ok && *debug {
+		imageFormFiller.Debug()
+	}
+
+	page1Template, err := os.Open(page1Layout.TemplatePath())
 	noErr(err)
 	defer page1Template.Close()
 
@@ -99,14 +102,27 @@ func main() {
 	noErr(err)
 
 	// page 2
-	page2Layout := paperwork.ShipmentSummaryPage2Layout
-	page2Template, err := os.Open(page2Layout.TemplateImagePath)
+	page2Layout, err := paperwork.LoadFormLayoutAsset(paperwork.ShipmentSummaryPage2LayoutName)
+	noErr(err)
+	page2Template, err := os.Open(page2Layout.TemplatePath())
 	noErr(err)
 	defer page2Template.Close()
 
 	err = formFiller.AppendPage(page2Template, page2Layout.FieldsLayout, page2Data)
 	noErr(err)
 
+	// continuation pages
+	continuationLayout, err := paperwork.LoadFormLayoutAsset(paperwork.ShipmentSummaryContinuationLayoutName)
+	noErr(err)
+	for _, continuationPageData := range continuationData {
+		continuationTemplate, err := os.Open(continuationLayout.TemplatePath())
+		noErr(err)
+		defer continuationTemplate.Close()
+
+		err = formFiller.AppendPage(continuationTemplate, continuationLayout.FieldsLayout, continuationPageData)
+		noErr(err)
+	}
+
 	filename := fmt.Sprintf("shipment-summary-worksheet-%s.pdf", time.Now().Format(time.RFC3339))
 
 	output, err := os.Create(filename)
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/namsral/flag"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/internal/pkg/tariff400ngloader"
+)
+
+// Loads a year of 400NG tariff rates from a directory of spreadsheets, one per table:
+// linehaul_rates, shorthaul_rates, service_areas, full_pack_rates, full_unpack_rates and item_rates, plus optionally
+// zip3s and zip5_rate_areas. Each may be an .xlsx or .csv file whose header row names the table's columns. Zip3s
+// and zip5 rate areas don't have effective dates, so they can only be loaded with the tariff year in effect today.
+//
+// The rates are validated and compared with the tariff year before them, and are only saved with -commit.
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	verbose := flag.Bool("verbose", false, "Sets debug logging level")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	dir := flag.String("dir", "", "Directory of tariff spreadsheets to load")
+	effectiveDateLower := flag.String("effective-date-lower", "", "First date the rates are effective, as YYYY-MM-DD")
+	effectiveDateUpper := flag.String("effective-date-upper", "", "Date the rates stop being effective, as YYYY-MM-DD")
+	report := flag.String("report", "", "Where to write the diff report, instead of stdout")
+	commit := flag.Bool("commit", false, "Save the rates, instead of only validating and reporting on them")
+	flag.Parse()
+
+	zapConfig := zap.NewDevelopmentConfig()
+	zapConfig.Level.SetLevel(zap.InfoLevel)
+	if *verbose {
+		zapConfig.Level.SetLevel(zap.DebugLevel)
+	}
+	logger, err := zapConfig.Build()
+	if err != nil {
+		log.Fatalf("Failed to initialize Zap logging due to %v", err)
+	}
+
+	if *dir == "" {
+		logger.Fatal("-dir is required")
+	}
+	lower, err := time.Parse("2006-01-02", *effectiveDateLower)
+	if err != nil {
+		logger.Fatal("-effective-date-lower must be a date like 2019-05-15", zap.Error(err))
+	}
+	upper, err := time.Parse("2006-01-02", *effectiveDateUpper)
+	if err != nil {
+		logger.Fatal("-effective-date-upper must be a date like 2020-05-15", zap.Error(err))
+	}
+
+	//DB connection
+	err = pop.AddLookupPaths(*config)
+	if err != nil {
+		logger.Panic("Error initializing db connection", zap.Error(err))
+	}
+	db, err := pop.Connect(*env)
+	if err != nil {
+		logger.Panic("Error initializing db connection", zap.Error(err))
+	}
+
+	year, err := tariff400ngloader.ReadTariffYear(*dir, lower, upper)
+	if err != nil {
+		logger.Fatal("Error reading tariff spreadsheets", zap.Error(err))
+	}
+
+	loader := tariff400ngloader.NewLoader(db, logger)
+	problems, err := loader.Validate(year)
+	if err != nil {
+		logger.Fatal("Error validating tariff", zap.Error(err))
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, problem)
+		}
+		logger.Fatal("Tariff is not valid", zap.Int("problems", len(problems)))
+	}
+
+	current, err := loader.FetchPreviousTariffYear(year)
+	if err != nil {
+		logger.Fatal("Error fetching current tariff", zap.Error(err))
+	}
+	diff := tariff400ngloader.Diff(current, year)
+
+	out := os.Stdout
+	if *report != "" {
+		out, err = os.Create(*report)
+		if err != nil {
+			logger.Fatal("Error creating report", zap.Error(err))
+		}
+		defer out.Close()
+	}
+	if err = diff.Write(out); err != nil {
+		logger.Fatal("Error writing report", zap.Error(err))
+	}
+
+	if !*commit {
+		logger.Info("Tariff is valid; run again with -commit to save it")
+		return
+	}
+	if err = loader.Save(year); err != nil {
+		logger.Fatal("Error saving tariff", zap.Error(err))
+	}
+	logger.Info("Saved tariff",
+		zap.String("effective_date_lower", *effectiveDateLower),
+		zap.String("effective_date_upper", *effectiveDateUpper))
+}
@@ -55,6 +55,8 @@ import (
 	"github.com/transcom/mymove/pkg/services"
 	"github.com/transcom/mymove/pkg/services/invoice"
 	"github.com/transcom/mymove/pkg/storage"
+	"github.com/transcom/mymove/pkg/uploader"
+	"github.com/transcom/mymove/pkg/webhooks"
 )
 
 // initServeFlags - Order matters!
@@ -102,9 +104,15 @@ func initServeFlags(flag *pflag.FlagSet) {
 	// Storage
 	cli.InitStorageFlags(flag)
 
+	// Antivirus
+	cli.InitAntivirusFlags(flag)
+
 	// Email
 	cli.InitEmailFlags(flag)
 
+	// Webhooks
+	cli.InitWebhookFlags(flag)
+
 	// Honeycomb Config
 	cli.InitHoneycombFlags(flag)
 
@@ -196,10 +204,18 @@ func checkServeConfig(v *viper.Viper, logger logger) error {
 		return err
 	}
 
+	if err := cli.CheckAntivirus(v); err != nil {
+		return err
+	}
+
 	if err := cli.CheckEmail(v); err != nil {
 		return err
 	}
 
+	if err := cli.CheckWebhook(v); err != nil {
+		return err
+	}
+
 	if err := cli.CheckHoneycomb(v); err != nil {
 		return err
 	}
@@ -408,7 +424,7 @@ func serveFunction(cmd *cobra.Command, args []string) error {
 	}
 
 	var session *awssession.Session
-	if v.GetString(cli.EmailBackendFlag) == "ses" || v.GetString(cli.StorageBackendFlag) == "s3" {
+	if v.GetString(cli.EmailBackendFlag) == "ses" || v.GetString(cli.StorageBackendFlag) == "s3" || v.GetString(cli.StorageEncryptionFlag) == "kms" {
 		c, errorConfig := cli.GetAWSConfig(v, v.GetBool(cli.VerboseFlag))
 		if errorConfig != nil {
 			logger.Fatal(errors.Wrap(errorConfig, "error creating aws config").Error())
@@ -467,9 +483,13 @@ func serveFunction(cmd *cobra.Command, args []string) error {
 
 	handlerContext.SetAppNames(appnames)
 
-	// Email
-	notificationSender := notifications.InitEmail(v, session, logger)
-	handlerContext.SetNotificationSender(notificationSender)
+	// Email. Handlers write notifications to the outbox, and the outbox worker sends them.
+	emailSender := notifications.InitEmail(v, session, logger)
+	handlerContext.SetNotificationSender(notifications.NewNotificationOutbox(dbConnection))
+	outboxWorker := notifications.InitOutboxWorker(v, dbConnection, logger, emailSender)
+	outboxCtx, cancelOutbox := context.WithCancel(context.Background())
+	defer cancelOutbox()
+	go outboxWorker.Run(outboxCtx, v.GetDuration(cli.NotificationPollIntervalFlag))
 
 	build := v.GetString(cli.BuildRootFlag)
 
@@ -478,7 +498,7 @@ func serveFunction(cmd *cobra.Command, args []string) error {
 
 	// Get route planner for handlers to calculate transit distances
 	// routePlanner := route.NewBingPlanner(logger, bingMapsEndpoint, bingMapsKey)
-	routePlanner := route.InitRoutePlanner(v, logger)
+	routePlanner := route.InitRoutePlanner(v, logger, dbConnection)
 	handlerContext.SetPlanner(routePlanner)
 
 	// Set SendProductionInvoice for ediinvoice
@@ -488,6 +508,18 @@ func serveFunction(cmd *cobra.Command, args []string) error {
 	storer := storage.InitStorage(v, session, logger)
 	handlerContext.SetFileStorer(storer)
 
+	// Uploads are quarantined until the scan worker has scanned them for viruses
+	scanWorker := uploader.InitScanWorker(v, dbConnection, logger, storer)
+	scanCtx, cancelScan := context.WithCancel(context.Background())
+	defer cancelScan()
+	go scanWorker.Run(scanCtx, v.GetDuration(cli.UploadScanIntervalFlag))
+
+	// Handlers and the award queue write webhook events to TSPs' subscriptions, and the webhook worker sends them
+	webhookWorker := webhooks.InitDeliveryWorker(v, dbConnection, logger)
+	webhookCtx, cancelWebhooks := context.WithCancel(context.Background())
+	defer cancelWebhooks()
+	go webhookWorker.Run(webhookCtx, v.GetDuration(cli.WebhookPollIntervalFlag))
+
 	certificates, rootCAs, err := certs.InitDoDCertificates(v, logger)
 	if certificates == nil || rootCAs == nil || err != nil {
 		logger.Fatal("Failed to initialize DOD certificates", zap.Error(err))
@@ -771,7 +803,7 @@ func serveFunction(cmd *cobra.Command, args []string) error {
 	adminAPIMux.Use(userAuthMiddleware)
 	adminAPIMux.Use(authentication.AdminAuthMiddleware(logger))
 	adminAPIMux.Use(middleware.NoCache(logger))
-	adminAPIMux.Handle(pat.New("/*"), adminapi.NewAdminAPIHandler(handlerContext))
+	adminAPIMux.Handle(pat.New("/*"), adminapi.NewAdminAPIHandler(handlerContext, logger))
 
 	authContext := authentication.NewAuthContext(logger, loginGovProvider, loginGovCallbackProtocol, loginGovCallbackPort)
 	authMux := goji.SubMux()
@@ -802,7 +834,12 @@ func serveFunction(cmd *cobra.Command, args []string) error {
 	}
 
 	storageBackend := v.GetString(cli.StorageBackendFlag)
-	if storageBackend == "local" {
+	if encryptedStorer, ok := storer.(*storage.Encrypted); ok {
+		localStorageWebRoot := v.GetString(cli.LocalStorageWebRootFlag)
+
+		// Encrypted files are decrypted by the app, so they are always served from here
+		root.Handle(pat.Get(path.Join("/", localStorageWebRoot, "/*")), storage.NewEncryptedHandler(encryptedStorer))
+	} else if storageBackend == "local" {
 		localStorageRoot := v.GetString(cli.LocalStorageRootFlag)
 		localStorageWebRoot := v.GetString(cli.LocalStorageWebRootFlag)
 
@@ -890,6 +927,11 @@ func serveFunction(cmd *cobra.Command, args []string) error {
 
 	logger.Info("received signal for graceful shutdown of server", zap.Any("signal", sig))
 
+	// stop sending notifications and webhooks and scanning uploads before the database connections are closed
+	cancelOutbox()
+	cancelScan()
+	cancelWebhooks()
+
 	// flush message that we received signal
 	logger.Sync()
 
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gobuffalo/pop"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	ediinvoice "github.com/transcom/mymove/pkg/edi/invoice"
+	"github.com/transcom/mymove/pkg/logging"
+	"github.com/transcom/mymove/pkg/services/invoice"
+)
+
+// Call this from command line with go run cmd/process_edi_997/main.go --edi <filepath>
+// Updates the SUBMITTED invoice acknowledged by the 997 to ACCEPTED or REJECTED
+func main() {
+	flag := pflag.CommandLine
+	flag.String("edi", "", "The filepath to a 997 functional acknowledgment received from GEX")
+	flag.String("env", "development", "The database environment to connect to")
+	flag.Parse(os.Args[1:])
+
+	v := viper.New()
+	v.BindPFlags(flag)
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	logger, err := logging.Config("development", true)
+	if err != nil {
+		log.Fatalf("Failed to initialize Zap logging due to %v", err)
+	}
+
+	ediFile := v.GetString("edi")
+	if ediFile == "" {
+		logger.Fatal("Usage: go run cmd/process_edi_997/main.go --edi <edi filepath>")
+	}
+
+	file, err := os.Open(ediFile) // #nosec
+	if err != nil {
+		logger.Fatal("Could not open 997", zap.Error(err))
+	}
+	defer file.Close()
+
+	ack, err := ediinvoice.Parse997(file)
+	if err != nil {
+		logger.Fatal("Could not parse 997", zap.Error(err))
+	}
+
+	db, err := pop.Connect(v.GetString("env"))
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	invoiceModel, verrs, err := invoice.ProcessInvoiceAcknowledgment{DB: db, Logger: logger}.Call(ack)
+	if err != nil {
+		logger.Fatal("Could not process 997", zap.Error(err))
+	}
+	if verrs.HasAny() {
+		logger.Fatal(verrs.Error())
+	}
+
+	logger.Info("Invoice updated from 997",
+		zap.String("invoice_number", invoiceModel.InvoiceNumber),
+		zap.String("status", string(invoiceModel.Status)))
+}
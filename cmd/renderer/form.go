@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/transcom/mymove/pkg/paperwork"
+)
+
+// renderForm fills the form layout in layoutFile with sample data and a debug overlay showing where each field
+// is, and writes the PDF to outputFile, or stdout if outputFile is empty. Sample data is read from dataFile as a
+// YAML or JSON mapping of field names to values; fields without sample data show their names. Layouts with a
+// fillable PDF template are filled without an overlay, and stay fillable so their fields can be inspected.
+func renderForm(layoutFile, dataFile, outputFile string) error {
+	layout, err := paperwork.LoadFormLayout(layoutFile)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{}
+	for name := range layout.FieldsLayout {
+		data[name] = name
+	}
+	if len(dataFile) > 0 {
+		content, readFileErr := ioutil.ReadFile(dataFile)
+		if readFileErr != nil {
+			return errors.Wrap(readFileErr, "error reading data file")
+		}
+		sample := map[string]string{}
+		if err = yaml.Unmarshal(content, &sample); err != nil {
+			return errors.Wrap(err, "error parsing data file")
+		}
+		for name, value := range sample {
+			if _, ok := layout.FieldsLayout[name]; !ok {
+				return errors.Errorf("data file has a value for %s, which is not in the form layout", name)
+			}
+			data[name] = value
+		}
+	}
+
+	formFiller := paperwork.NewFillerForLayout(layout)
+	if imageFormFiller, ok := formFiller.(*paperwork.FormFiller); ok {
+		imageFormFiller.Debug()
+	}
+
+	template, err := os.Open(layout.TemplatePath())
+	if err != nil {
+		return errors.Wrap(err, "error opening template")
+	}
+	defer template.Close()
+
+	if err = formFiller.AppendPage(template, layout.FieldsLayout, data); err != nil {
+		return err
+	}
+
+	output := os.Stdout
+	if len(outputFile) > 0 {
+		output, err = os.Create(outputFile)
+		if err != nil {
+			return errors.Wrap(err, "error creating output file")
+		}
+		defer output.Close()
+	}
+	return formFiller.Output(output)
+}
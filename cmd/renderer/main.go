@@ -14,12 +14,26 @@ func main() {
 
 	templateFile := ""
 	variablesFile := ""
+	layoutFile := ""
+	dataFile := ""
+	outputFile := ""
 
 	flag.StringVar(&templateFile, "t", "", "template file")
 	flag.StringVar(&variablesFile, "v", "", "variables file")
+	flag.StringVar(&layoutFile, "form", "", "form layout file to render with a debug overlay, instead of a template")
+	flag.StringVar(&dataFile, "data", "", "sample data file for the form, mapping field names to values")
+	flag.StringVar(&outputFile, "o", "", "file to write the rendered form to, instead of stdout")
 
 	flag.Parse()
 
+	// Render a PDF form layout to check where its fields are
+	if len(layoutFile) > 0 {
+		if err := renderForm(layoutFile, dataFile, outputFile); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// If no template file given, then error out
 	if len(templateFile) == 0 {
 		log.Fatal(errors.New("error: no template file given"))
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/namsral/flag"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/services"
+	"github.com/transcom/mymove/pkg/services/shipment"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// Prices a shipment as if its tariff date, discounts or fuel price were different, and prints the what-if price of
+// each line item next to the stored one. Nothing is saved, so it is safe to run while looking into a dispute.
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	verbose := flag.Bool("verbose", false, "Sets debug logging level")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	shipmentID := flag.String("shipment-id", "", "ID of the shipment to reprice")
+	date := flag.String("date", "", "Price using rates and fuel prices for this date instead of the shipment's, as YYYY-MM-DD")
+	linehaulDiscount := flag.String("linehaul-discount", "", "Linehaul discount percentage to use instead of the TSP's, e.g. 57.5")
+	sitDiscount := flag.String("sit-discount", "", "SIT discount percentage to use instead of the TSP's, e.g. 50")
+	fuelPrice := flag.String("fuel-price", "", "EIA diesel price per gallon in dollars to base the fuel surcharge on, e.g. 3.125")
+	flag.Parse()
+
+	zapConfig := zap.NewDevelopmentConfig()
+	zapConfig.Level.SetLevel(zap.InfoLevel)
+	if *verbose {
+		zapConfig.Level.SetLevel(zap.DebugLevel)
+	}
+	logger, err := zapConfig.Build()
+	if err != nil {
+		log.Fatalf("Failed to initialize Zap logging due to %v", err)
+	}
+
+	id, err := uuid.FromString(*shipmentID)
+	if err != nil {
+		logger.Fatal("-shipment-id must be a UUID", zap.Error(err))
+	}
+
+	overrides := rateengine.PricingOverrides{}
+	if *date != "" {
+		d, err := time.Parse("2006-01-02", *date)
+		if err != nil {
+			logger.Fatal("-date must be a date like 2019-05-15", zap.Error(err))
+		}
+		overrides.Date = &d
+	}
+	if *linehaulDiscount != "" {
+		percent, err := strconv.ParseFloat(*linehaulDiscount, 64)
+		if err != nil {
+			logger.Fatal("-linehaul-discount must be a percentage", zap.Error(err))
+		}
+		discount := unit.NewDiscountRateFromPercent(percent)
+		overrides.LinehaulDiscount = &discount
+	}
+	if *sitDiscount != "" {
+		percent, err := strconv.ParseFloat(*sitDiscount, 64)
+		if err != nil {
+			logger.Fatal("-sit-discount must be a percentage", zap.Error(err))
+		}
+		discount := unit.NewDiscountRateFromPercent(percent)
+		overrides.SITDiscount = &discount
+	}
+	if *fuelPrice != "" {
+		dollars, err := strconv.ParseFloat(*fuelPrice, 64)
+		if err != nil {
+			logger.Fatal("-fuel-price must be an amount in dollars", zap.Error(err))
+		}
+		price := unit.Millicents(math.Round(dollars * 100000))
+		overrides.FuelPricePerGallon = &price
+	}
+	if !overrides.HasAny() {
+		logger.Info("No overrides given; the shipment will be priced as it is")
+	}
+
+	//DB connection
+	err = pop.AddLookupPaths(*config)
+	if err != nil {
+		logger.Panic("Error initializing db connection", zap.Error(err))
+	}
+	db, err := pop.Connect(*env)
+	if err != nil {
+		logger.Panic("Error initializing db connection", zap.Error(err))
+	}
+
+	pricer := shipment.NewShipmentWhatIfPricer(db, rateengine.NewRateEngine(db, logger))
+	whatIf, err := pricer.PriceShipmentWhatIf(id, overrides)
+	if err != nil {
+		logger.Fatal("Error pricing shipment", zap.Error(err))
+	}
+
+	if err = writeWhatIf(os.Stdout, whatIf); err != nil {
+		logger.Fatal("Error writing what-if", zap.Error(err))
+	}
+}
+
+func writeWhatIf(out io.Writer, whatIf services.ShipmentWhatIf) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Code\tItem\tLocation\tStored\tWhat-if\tDifference\t")
+	for _, c := range whatIf.LineItems {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			c.Code, c.Item, c.Location, formatCents(c.StoredAmountCents), formatCents(c.WhatIfAmountCents),
+			c.DifferenceCents().ToDollarString())
+	}
+	fmt.Fprintf(w, "Total\t\t\t%s\t%s\t%s\t\n",
+		whatIf.StoredTotalCents.ToDollarString(), whatIf.WhatIfTotalCents.ToDollarString(),
+		whatIf.DifferenceCents().ToDollarString())
+	return w.Flush()
+}
+
+func formatCents(c *unit.Cents) string {
+	if c == nil {
+		return "-"
+	}
+	return c.ToDollarString()
+}
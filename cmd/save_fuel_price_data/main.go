@@ -1,34 +1,85 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/facebookgo/clock"
+	"github.com/gobuffalo/pop"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/cli"
 	"github.com/transcom/mymove/pkg/logging"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/route"
 	"github.com/transcom/mymove/pkg/services/fuelprice"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+const (
+	// backfillStartFlag is the first date to backfill fuel prices from
+	backfillStartFlag string = "backfill-start"
+	// backfillEndFlag is the last date to backfill fuel prices to
+	backfillEndFlag string = "backfill-end"
+	// gapCheckMonthsFlag is the number of months before this one to check for missing fuel prices
+	gapCheckMonthsFlag string = "gap-check-months"
+	// correctPubDateFlag is a date in the month of the fuel price to correct
+	correctPubDateFlag string = "correct-pub-date"
+	// correctPriceFlag is the corrected fuel price
+	correctPriceFlag string = "correct-price"
 )
 
 func checkConfig(v *viper.Viper, logger logger) error {
 
 	logger.Debug("checking config")
 
-	err := cli.CheckEIA(v)
+	err := cli.CheckFuelPrice(v)
 	if err != nil {
 		return err
 	}
 
+	if v.GetString(cli.FuelPriceSourceFlag) == "eia" {
+		err = cli.CheckEIA(v)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = cli.CheckDatabase(v, logger)
 	if err != nil {
 		return err
 	}
 
+	if v.GetString(backfillStartFlag) != "" || v.GetString(backfillEndFlag) != "" {
+		if _, err = parseDateFlag(v, backfillStartFlag); err != nil {
+			return err
+		}
+		if _, err = parseDateFlag(v, backfillEndFlag); err != nil {
+			return err
+		}
+	}
+
+	if v.GetString(correctPubDateFlag) != "" {
+		if _, err = parseDateFlag(v, correctPubDateFlag); err != nil {
+			return err
+		}
+		if v.GetFloat64(correctPriceFlag) <= 0 {
+			return fmt.Errorf("%s must be a positive amount in dollars", correctPriceFlag)
+		}
+		// Corrections reprice shipments, which needs their distances
+		err = cli.CheckRoute(v)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -40,6 +91,19 @@ func initFlags(flag *pflag.FlagSet) {
 	// EIA Open Data API
 	cli.InitEIAFlags(flag)
 
+	// Fuel price source and alerts
+	cli.InitFuelPriceFlags(flag)
+
+	// Route Planner, for repricing shipments after a correction
+	cli.InitRouteFlags(flag)
+
+	// Backfill, gap detection and correction
+	flag.String(backfillStartFlag, "", "Store missing fuel prices for every month from this date, as YYYY-MM-DD, instead of for the last 12 months")
+	flag.String(backfillEndFlag, "", "Store missing fuel prices for every month up to this date, as YYYY-MM-DD")
+	flag.Int(gapCheckMonthsFlag, 12, "Number of months before this one to check for missing fuel prices")
+	flag.String(correctPubDateFlag, "", "Correct the fuel price published in the month of this date, as YYYY-MM-DD, and reprice the shipments it affects")
+	flag.Float64(correctPriceFlag, 0, "Corrected diesel price per gallon in dollars, e.g. 3.163")
+
 	// Verbose
 	cli.InitVerboseFlags(flag)
 
@@ -47,7 +111,54 @@ func initFlags(flag *pflag.FlagSet) {
 	flag.SortFlags = false
 }
 
+func parseDateFlag(v *viper.Viper, name string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", v.GetString(name))
+	if err != nil {
+		return date, fmt.Errorf("%s must be a date like 2019-05-15", name)
+	}
+	return date, nil
+}
+
+func priceSource(v *viper.Viper) fuelprice.PriceSource {
+	if v.GetString(cli.FuelPriceSourceFlag) == "csv" {
+		return fuelprice.NewCSVPriceSource(v.GetString(cli.FuelPriceCSVFileFlag))
+	}
+	return fuelprice.NewEIAPriceSource(fuelprice.FetchFuelPriceData, v.GetString(cli.EIAKeyFlag), v.GetString(cli.EIAURLFlag))
+}
+
+// alertFuelPriceGaps logs the months before this one that are missing fuel prices, and emails them to the alert
+// address if there is one
+func alertFuelPriceGaps(v *viper.Viper, db *pop.Connection, logger *zap.Logger, now time.Time) error {
+	lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	firstMonth := lastMonth.AddDate(0, 1-v.GetInt(gapCheckMonthsFlag), 0)
+	missing, err := models.FetchMissingFuelPriceMonths(db, firstMonth, lastMonth)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	for _, month := range missing {
+		logger.Error("Fuel price missing", zap.String("month", month.Format("2006-01")))
+	}
+
+	alertEmail := v.GetString(cli.FuelPriceAlertEmailFlag)
+	if alertEmail == "" {
+		return nil
+	}
+	outbox := notifications.NewNotificationOutbox(db)
+	return outbox.SendNotification(context.Background(), notifications.NewFuelPriceGaps(alertEmail, missing))
+}
+
 // Command: go run github.com/transcom/mymove/cmd/save_fuel_price_data
+//
+// With no flags, stores the fuel prices missing for the last 12 months. With --backfill-start and --backfill-end,
+// stores the fuel prices missing for every month in between instead. Either way, it then alerts on months before
+// this one that are still missing prices.
+//
+// With --correct-pub-date and --correct-price, it instead corrects a stored fuel price and reprices the delivered
+// shipments whose fuel surcharge is based on it.
 func main() {
 
 	flag := pflag.CommandLine
@@ -80,20 +191,44 @@ func main() {
 		logger.Fatal("Connecting to DB", zap.Error(err))
 	}
 
+	if v.GetString(correctPubDateFlag) != "" {
+		pubDate, _ := parseDateFlag(v, correctPubDateFlag)
+		planner := route.InitRoutePlanner(v, logger, dbConnection)
+		corrector := fuelprice.NewFuelPriceCorrector(dbConnection, logger, planner)
+		correction, verrs, err := corrector.CorrectFuelPrice(pubDate, unit.Dollars(v.GetFloat64(correctPriceFlag)))
+		if err != nil || verrs.HasAny() {
+			log.Fatal(err, verrs)
+		}
+		for _, id := range correction.InvoicedShipmentIDs {
+			logger.Warn("Shipment already invoiced, so not repriced", zap.String("shipment_id", id.String()))
+		}
+		return
+	}
+
 	clock := clock.New()
-	eiaKey := v.GetString(cli.EIAKeyFlag)
-	eiaURL := v.GetString(cli.EIAURLFlag)
 	fuelPrices := fuelprice.NewDieselFuelPriceStorer(
 		dbConnection,
 		logger,
 		clock,
-		fuelprice.FetchFuelPriceData,
-		eiaKey,
-		eiaURL,
+		priceSource(v),
 	)
 
-	verrs, err := fuelPrices.StoreFuelPrices(12)
-	if err != nil || verrs.HasAny() {
-		log.Fatal(err, verrs)
+	if v.GetString(backfillStartFlag) != "" {
+		start, _ := parseDateFlag(v, backfillStartFlag)
+		end, _ := parseDateFlag(v, backfillEndFlag)
+		_, verrs, err := fuelPrices.Backfill(start, end)
+		if err != nil || verrs.HasAny() {
+			log.Fatal(err, verrs)
+		}
+	} else {
+		verrs, err := fuelPrices.StoreFuelPrices(12)
+		if err != nil || verrs.HasAny() {
+			log.Fatal(err, verrs)
+		}
+	}
+
+	err = alertFuelPriceGaps(v, dbConnection, logger, clock.Now())
+	if err != nil {
+		logger.Fatal("Checking for missing fuel prices", zap.Error(err))
 	}
 }
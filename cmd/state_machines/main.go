@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/namsral/flag"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/statemachine"
+)
+
+// Prints the declared lifecycles of moves, PPMs, reimbursements and shipments, either as a JSON transition table or
+// as Graphviz for documentation, e.g.
+//
+//	state-machines -format dot -machine Shipment | dot -Tsvg > shipment.svg
+func main() {
+	format := flag.String("format", "json", "Output format: json for the transition tables, or dot for Graphviz")
+	name := flag.String("machine", "", "Only print the machine with this name, e.g. Shipment")
+	flag.Parse()
+
+	var machines []*statemachine.Machine
+	for _, machine := range models.StateMachines() {
+		if *name == "" || machine.Name() == *name {
+			machines = append(machines, machine)
+		}
+	}
+	if len(machines) == 0 {
+		log.Fatalf("No state machine named %q", *name)
+	}
+
+	switch *format {
+	case "json":
+		tables := make([]statemachine.Table, len(machines))
+		for i, machine := range machines {
+			tables[i] = machine.Table()
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(tables); err != nil {
+			log.Fatal(err)
+		}
+	case "dot":
+		for _, machine := range machines {
+			if err := machine.WriteDOT(os.Stdout); err != nil {
+				log.Fatal(err)
+			}
+		}
+	default:
+		log.Fatalf("Unknown format %q, must be json or dot", *format)
+	}
+}
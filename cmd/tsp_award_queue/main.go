@@ -2,13 +2,16 @@ package main
 
 import (
 	"context"
+	"io"
 	"log"
+	"os"
 
 	"github.com/gobuffalo/pop"
 	"github.com/namsral/flag"
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/awardqueue"
+	"github.com/transcom/mymove/pkg/cli"
 	"github.com/transcom/mymove/pkg/logging/hnyzap"
 )
 
@@ -17,9 +20,17 @@ var logger *zap.Logger
 func main() {
 	config := flag.String("config-dir", "config", "The location of server config files")
 	env := flag.String("env", "development", "The environment to run in, configures the database, presenetly.")
+	tspHost := flag.String(cli.HTTPTSPServerNameFlag, cli.HTTPTSPServerNameLocal, "Hostname of the TSP app, used in the links emailed to TSP users.")
 	debugLogging := flag.Bool("debug_logging", false, "log messages at the debug level.")
+	simulate := flag.Bool("simulate", false, "Report the offers the award queue would make, without changing the database.")
+	reportFormat := flag.String("report-format", "csv", "Format of the simulation report, either csv or json.")
+	reportFile := flag.String("report-file", "", "File to write the simulation report to. Defaults to stdout.")
 	flag.Parse()
 
+	if *reportFormat != "csv" && *reportFormat != "json" {
+		log.Fatalf("invalid report-format %s, expecting csv or json", *reportFormat)
+	}
+
 	// Set up logger for the system
 	var err error
 	if *debugLogging {
@@ -44,9 +55,38 @@ func main() {
 		log.Panic(err)
 	}
 
-	awardQueue := awardqueue.NewAwardQueue(dbConnection, &honeyZapLogger)
+	awardQueue := awardqueue.NewAwardQueue(dbConnection, &honeyZapLogger, *tspHost)
+	if *simulate {
+		report, simulateErr := awardQueue.Simulate(context.Background())
+		if simulateErr != nil {
+			log.Panic(simulateErr)
+		}
+		if writeErr := writeReport(report, *reportFormat, *reportFile); writeErr != nil {
+			log.Panic(writeErr)
+		}
+		return
+	}
+
 	err = awardQueue.Run(context.Background())
 	if err != nil {
 		log.Panic(err)
 	}
 }
+
+// writeReport writes the simulation report to reportFile, or stdout if it is empty
+func writeReport(report *awardqueue.SimulationReport, reportFormat string, reportFile string) error {
+	var w io.Writer = os.Stdout
+	if reportFile != "" {
+		f, err := os.Create(reportFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if reportFormat == "json" {
+		return report.WriteJSON(w)
+	}
+	return report.WriteCSV(w)
+}
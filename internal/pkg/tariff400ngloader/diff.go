@@ -0,0 +1,230 @@
+package tariff400ngloader
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// tableRow is a row of a tariff table, identified by what it prices
+type tableRow struct {
+	key   string
+	value string
+	// amount is the row's rate, so the report can show how much it changed
+	amount int
+}
+
+// TableDiff is the difference between a table in two tariff years
+type TableDiff struct {
+	Table     string
+	Added     []string
+	Removed   []string
+	Changed   []string
+	Unchanged int
+}
+
+// DiffReport is the difference between the currently loaded tariff year and a new one
+type DiffReport struct {
+	Current TariffYear
+	New     TariffYear
+	Tables  []TableDiff
+}
+
+// Diff compares a new tariff year with the current one. Zip3s and zip5 rate areas are only compared if the new year
+// replaces them.
+func Diff(current TariffYear, new TariffYear) DiffReport {
+	report := DiffReport{Current: current, New: new}
+	report.Tables = append(report.Tables,
+		diffTable("linehaul rates", linehaulRows(current), linehaulRows(new)),
+		diffTable("shorthaul rates", shorthaulRows(current), shorthaulRows(new)),
+		diffTable("service areas", serviceAreaRows(current), serviceAreaRows(new)),
+		diffTable("full pack rates", fullPackRows(current), fullPackRows(new)),
+		diffTable("full unpack rates", fullUnpackRows(current), fullUnpackRows(new)),
+		diffTable("item rates", itemRateRows(current), itemRateRows(new)),
+	)
+	if new.Zip3s != nil {
+		report.Tables = append(report.Tables, diffTable("zip3s", zip3Rows(current), zip3Rows(new)))
+	}
+	if new.Zip5RateAreas != nil {
+		report.Tables = append(report.Tables, diffTable("zip5 rate areas", zip5RateAreaRows(current), zip5RateAreaRows(new)))
+	}
+	return report
+}
+
+func diffTable(table string, current []tableRow, new []tableRow) TableDiff {
+	diff := TableDiff{Table: table}
+	currentRows := map[string]tableRow{}
+	for _, r := range current {
+		currentRows[r.key] = r
+	}
+	newKeys := map[string]bool{}
+	for _, r := range new {
+		newKeys[r.key] = true
+		old, found := currentRows[r.key]
+		switch {
+		case !found:
+			diff.Added = append(diff.Added, fmt.Sprintf("%s: %s", r.key, r.value))
+		case old.value == r.value:
+			diff.Unchanged++
+		case old.amount != 0 && old.amount != r.amount:
+			change := float64(r.amount-old.amount) / float64(old.amount) * 100
+			diff.Changed = append(diff.Changed, fmt.Sprintf("%s: %s -> %s (%+.1f%%)", r.key, old.value, r.value, change))
+		default:
+			diff.Changed = append(diff.Changed, fmt.Sprintf("%s: %s -> %s", r.key, old.value, r.value))
+		}
+	}
+	for _, r := range current {
+		if !newKeys[r.key] {
+			diff.Removed = append(diff.Removed, fmt.Sprintf("%s: %s", r.key, r.value))
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+// HasChanges reports whether the new tariff year differs from the current one
+func (r DiffReport) HasChanges() bool {
+	for _, table := range r.Tables {
+		if len(table.Added) > 0 || len(table.Removed) > 0 || len(table.Changed) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the report as text, with a summary of each table followed by every row that was added, removed or
+// changed
+func (r DiffReport) Write(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "400NG tariff effective %s to %s", formatDate(r.New.EffectiveDateLower), formatDate(r.New.EffectiveDateUpper))
+	if r.Current.EffectiveDateLower.IsZero() {
+		b.WriteString(", compared with no currently loaded tariff\n\n")
+	} else {
+		fmt.Fprintf(&b, ", compared with the tariff effective %s to %s\n\n", formatDate(r.Current.EffectiveDateLower), formatDate(r.Current.EffectiveDateUpper))
+	}
+
+	for _, table := range r.Tables {
+		fmt.Fprintf(&b, "%-18s %6d added %6d removed %6d changed %6d unchanged\n",
+			table.Table, len(table.Added), len(table.Removed), len(table.Changed), table.Unchanged)
+	}
+	for _, table := range r.Tables {
+		for _, section := range []struct {
+			name string
+			rows []string
+		}{{"added", table.Added}, {"removed", table.Removed}, {"changed", table.Changed}} {
+			if len(section.rows) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "\n%s %s:\n", table.Table, section.name)
+			for _, row := range section.rows {
+				fmt.Fprintf(&b, "  %s\n", row)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func linehaulRows(year TariffYear) []tableRow {
+	rows := make([]tableRow, len(year.LinehaulRates))
+	for i, rate := range year.LinehaulRates {
+		rows[i] = tableRow{
+			key:    fmt.Sprintf("%s %s miles %s lbs", rate.Type, band{rate.DistanceMilesLower, rate.DistanceMilesUpper}, band{rate.WeightLbsLower.Int(), rate.WeightLbsUpper.Int()}),
+			value:  rate.RateCents.ToDollarString(),
+			amount: rate.RateCents.Int(),
+		}
+	}
+	return rows
+}
+
+func shorthaulRows(year TariffYear) []tableRow {
+	rows := make([]tableRow, len(year.ShorthaulRates))
+	for i, rate := range year.ShorthaulRates {
+		rows[i] = tableRow{
+			key:    fmt.Sprintf("%s cwt-miles", band{rate.CwtMilesLower, rate.CwtMilesUpper}),
+			value:  rate.RateCents.ToDollarString(),
+			amount: rate.RateCents.Int(),
+		}
+	}
+	return rows
+}
+
+func serviceAreaRows(year TariffYear) []tableRow {
+	rows := make([]tableRow, len(year.ServiceAreas))
+	for i, serviceArea := range year.ServiceAreas {
+		rows[i] = tableRow{
+			key: "service area " + serviceArea.ServiceArea,
+			value: fmt.Sprintf("%s, schedule %d, linehaul factor %s, service charge %s, 185A %s, 185B %s, SIT P&D schedule %d",
+				serviceArea.Name, serviceArea.ServicesSchedule, serviceArea.LinehaulFactor, serviceArea.ServiceChargeCents.ToDollarString(),
+				serviceArea.SIT185ARateCents.ToDollarString(), serviceArea.SIT185BRateCents.ToDollarString(), serviceArea.SITPDSchedule),
+		}
+	}
+	return rows
+}
+
+func fullPackRows(year TariffYear) []tableRow {
+	rows := make([]tableRow, len(year.FullPackRates))
+	for i, rate := range year.FullPackRates {
+		rows[i] = tableRow{
+			key:    fmt.Sprintf("schedule %d %s lbs", rate.Schedule, band{rate.WeightLbsLower.Int(), rate.WeightLbsUpper.Int()}),
+			value:  rate.RateCents.ToDollarString(),
+			amount: rate.RateCents.Int(),
+		}
+	}
+	return rows
+}
+
+func fullUnpackRows(year TariffYear) []tableRow {
+	rows := make([]tableRow, len(year.FullUnpackRates))
+	for i, rate := range year.FullUnpackRates {
+		rows[i] = tableRow{
+			key:    fmt.Sprintf("schedule %d", rate.Schedule),
+			value:  fmt.Sprintf("%d millicents", rate.RateMillicents),
+			amount: rate.RateMillicents,
+		}
+	}
+	return rows
+}
+
+func itemRateRows(year TariffYear) []tableRow {
+	rows := make([]tableRow, len(year.ItemRates))
+	for i, rate := range year.ItemRates {
+		schedule := "any schedule"
+		if rate.Schedule != nil {
+			schedule = fmt.Sprintf("schedule %d", *rate.Schedule)
+		}
+		rows[i] = tableRow{
+			key:    fmt.Sprintf("%s %s %s lbs", rate.Code, schedule, band{rate.WeightLbsLower.Int(), rate.WeightLbsUpper.Int()}),
+			value:  rate.RateCents.ToDollarString(),
+			amount: rate.RateCents.Int(),
+		}
+	}
+	return rows
+}
+
+func zip3Rows(year TariffYear) []tableRow {
+	rows := make([]tableRow, len(year.Zip3s))
+	for i, zip3 := range year.Zip3s {
+		rows[i] = tableRow{
+			key: "zip3 " + zip3.Zip3,
+			value: fmt.Sprintf("%s, %s, service area %s, rate area %s, region %s",
+				zip3.BasepointCity, zip3.State, zip3.ServiceArea, zip3.RateArea, zip3.Region),
+		}
+	}
+	return rows
+}
+
+func zip5RateAreaRows(year TariffYear) []tableRow {
+	rows := make([]tableRow, len(year.Zip5RateAreas))
+	for i, zip5RateArea := range year.Zip5RateAreas {
+		rows[i] = tableRow{
+			key:   "zip5 " + zip5RateArea.Zip5,
+			value: "rate area " + zip5RateArea.RateArea,
+		}
+	}
+	return rows
+}
@@ -0,0 +1,217 @@
+package tariff400ngloader
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/gobuffalo/pop"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// Loader loads tariff years into the tariff400ng tables
+type Loader struct {
+	db     *pop.Connection
+	logger *zap.Logger
+	clock  clock.Clock
+}
+
+// NewLoader returns a new Loader
+func NewLoader(db *pop.Connection, logger *zap.Logger) Loader {
+	return Loader{
+		db:     db,
+		logger: logger,
+		clock:  clock.New(),
+	}
+}
+
+// checkZipsReplaceable checks that a tariff year can replace the zip3s and zip5 rate areas, if it has them. They
+// aren't versioned by date, so only the year in effect today can replace them; otherwise loading a past or future
+// year would change how today's rates are looked up.
+func (l Loader) checkZipsReplaceable(year TariffYear) error {
+	if year.Zip3s == nil && year.Zip5RateAreas == nil {
+		return nil
+	}
+	now := l.clock.Now()
+	if !now.Before(year.EffectiveDateLower) && now.Before(year.EffectiveDateUpper) {
+		return nil
+	}
+	return errors.Errorf("zip3s and zip5 rate areas can only be replaced by the tariff in effect today, not one effective %s to %s",
+		formatDate(year.EffectiveDateLower), formatDate(year.EffectiveDateUpper))
+}
+
+// ratedTables are the tariff400ng tables that have effective date ranges
+var ratedTables = []pop.TableNameAble{
+	&pop.Model{Value: models.Tariff400ngLinehaulRate{}},
+	&pop.Model{Value: models.Tariff400ngShorthaulRate{}},
+	&pop.Model{Value: models.Tariff400ngServiceArea{}},
+	&pop.Model{Value: models.Tariff400ngFullPackRate{}},
+	&pop.Model{Value: models.Tariff400ngFullUnpackRate{}},
+	&pop.Model{Value: models.Tariff400ngItemRate{}},
+}
+
+// effectiveDateRange is the range of dates that a set of rates is effective for
+type effectiveDateRange struct {
+	Lower time.Time `db:"effective_date_lower"`
+	Upper time.Time `db:"effective_date_upper"`
+}
+
+// FetchTariffYear fetches the tariff rates that are effective on date, along with the zip3s and zip5 rate areas.
+// The year's effective dates are those of its linehaul rates, and are zero if there are no rates effective on date.
+func (l Loader) FetchTariffYear(date time.Time) (TariffYear, error) {
+	year := TariffYear{}
+	effective := "effective_date_lower <= ? AND ? < effective_date_upper"
+	queries := []interface{}{
+		&year.LinehaulRates,
+		&year.ShorthaulRates,
+		&year.ServiceAreas,
+		&year.FullPackRates,
+		&year.FullUnpackRates,
+		&year.ItemRates,
+	}
+	for _, rates := range queries {
+		if err := l.db.Where(effective, date, date).All(rates); err != nil {
+			return year, errors.Wrap(err, "could not fetch current tariff rates")
+		}
+	}
+	if err := l.db.All(&year.Zip3s); err != nil {
+		return year, errors.Wrap(err, "could not fetch zip3s")
+	}
+	if err := l.db.All(&year.Zip5RateAreas); err != nil {
+		return year, errors.Wrap(err, "could not fetch zip5 rate areas")
+	}
+
+	if len(year.LinehaulRates) > 0 {
+		year.EffectiveDateLower = year.LinehaulRates[0].EffectiveDateLower
+		year.EffectiveDateUpper = year.LinehaulRates[0].EffectiveDateUpper
+	}
+	return year, nil
+}
+
+// FetchPreviousTariffYear fetches the tariff year that was in effect the day before year takes effect
+func (l Loader) FetchPreviousTariffYear(year TariffYear) (TariffYear, error) {
+	return l.FetchTariffYear(year.EffectiveDateLower.AddDate(0, 0, -1))
+}
+
+// Validate checks that a tariff year is complete and fits in with the tariffs already loaded, and returns a
+// description of each problem found. A tariff year can replace one with exactly the same effective dates, but can't
+// otherwise overlap another year or leave a gap between years.
+func (l Loader) Validate(year TariffYear) ([]string, error) {
+	reference := referenceData{itemCodes: map[string]bool{}}
+	var items []models.Tariff400ngItem
+	if err := l.db.All(&items); err != nil {
+		return nil, errors.Wrap(err, "could not fetch 400NG items")
+	}
+	for _, item := range items {
+		reference.itemCodes[item.Code] = true
+	}
+	if err := l.db.All(&reference.zip3s); err != nil {
+		return nil, errors.Wrap(err, "could not fetch zip3s")
+	}
+	if err := l.db.All(&reference.zip5RateAreas); err != nil {
+		return nil, errors.Wrap(err, "could not fetch zip5 rate areas")
+	}
+	problems := validateYear(year, reference)
+	if err := l.checkZipsReplaceable(year); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	for _, table := range ratedTables {
+		var ranges []effectiveDateRange
+		// #nosec G201 the table names are those of our models
+		query := fmt.Sprintf("SELECT DISTINCT effective_date_lower, effective_date_upper FROM %s ORDER BY effective_date_lower", table.TableName())
+		if err := l.db.RawQuery(query).All(&ranges); err != nil {
+			return nil, errors.Wrapf(err, "could not fetch effective dates of %s", table.TableName())
+		}
+		problems = append(problems, checkEffectiveDates(table.TableName(), year, ranges)...)
+	}
+	return problems, nil
+}
+
+// checkEffectiveDates checks that a tariff year neither overlaps nor leaves a gap next to the effective date ranges
+// already in a table, other than one with the same range that it replaces
+func checkEffectiveDates(table string, year TariffYear, ranges []effectiveDateRange) []string {
+	var problems []string
+	var before, after *effectiveDateRange
+	for i, r := range ranges {
+		if r.Lower.Equal(year.EffectiveDateLower) && r.Upper.Equal(year.EffectiveDateUpper) {
+			continue
+		}
+		switch {
+		case !r.Upper.After(year.EffectiveDateLower):
+			before = &ranges[i]
+		case !r.Lower.Before(year.EffectiveDateUpper):
+			if after == nil {
+				after = &ranges[i]
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s: rates effective %s to %s overlap the new tariff",
+				table, formatDate(r.Lower), formatDate(r.Upper)))
+		}
+	}
+	if before != nil && !before.Upper.Equal(year.EffectiveDateLower) {
+		problems = append(problems, fmt.Sprintf("%s: gap between rates ending %s and the new tariff",
+			table, formatDate(before.Upper)))
+	}
+	if after != nil && !after.Lower.Equal(year.EffectiveDateUpper) {
+		problems = append(problems, fmt.Sprintf("%s: gap between the new tariff and rates starting %s",
+			table, formatDate(after.Lower)))
+	}
+	return problems
+}
+
+// Save loads a tariff year in a single transaction, replacing any rates with the same effective dates. Zip3s and
+// zip5 rate areas are replaced if the year has them, which is only allowed for the year in effect today.
+func (l Loader) Save(year TariffYear) error {
+	if err := l.checkZipsReplaceable(year); err != nil {
+		return err
+	}
+	return l.db.Transaction(func(tx *pop.Connection) error {
+		for _, table := range ratedTables {
+			// #nosec G201 the table names are those of our models
+			query := fmt.Sprintf("DELETE FROM %s WHERE effective_date_lower = ? AND effective_date_upper = ?", table.TableName())
+			replaced, err := tx.RawQuery(query, year.EffectiveDateLower, year.EffectiveDateUpper).ExecWithCount()
+			if err != nil {
+				return errors.Wrapf(err, "could not delete replaced %s", table.TableName())
+			}
+			if replaced > 0 {
+				l.logger.Info("Replacing tariff rates", zap.String("table", table.TableName()), zap.Int("rows", replaced))
+			}
+		}
+
+		tables := []interface{}{
+			&year.LinehaulRates,
+			&year.ShorthaulRates,
+			&year.ServiceAreas,
+			&year.FullPackRates,
+			&year.FullUnpackRates,
+			&year.ItemRates,
+		}
+		if year.Zip3s != nil {
+			if err := tx.RawQuery("DELETE FROM tariff400ng_zip3s").Exec(); err != nil {
+				return errors.Wrap(err, "could not delete replaced zip3s")
+			}
+			tables = append(tables, &year.Zip3s)
+		}
+		if year.Zip5RateAreas != nil {
+			if err := tx.RawQuery("DELETE FROM tariff400ng_zip5_rate_areas").Exec(); err != nil {
+				return errors.Wrap(err, "could not delete replaced zip5 rate areas")
+			}
+			tables = append(tables, &year.Zip5RateAreas)
+		}
+
+		for _, rows := range tables {
+			verrs, err := tx.ValidateAndCreate(rows)
+			if err != nil {
+				return errors.Wrap(err, "could not save tariff rates")
+			}
+			if verrs.HasAny() {
+				return errors.Errorf("invalid tariff rates: %s", verrs)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,429 @@
+package tariff400ngloader
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tealeg/xlsx"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// Names of the files a tariff year is read from, without their .csv or .xlsx extension. Each file has a header row
+// naming its columns after the columns of the table it is loaded into; the effective dates come from the command line.
+const (
+	linehaulRatesFile   = "linehaul_rates"
+	shorthaulRatesFile  = "shorthaul_rates"
+	serviceAreasFile    = "service_areas"
+	fullPackRatesFile   = "full_pack_rates"
+	fullUnpackRatesFile = "full_unpack_rates"
+	itemRatesFile       = "item_rates"
+	zip3sFile           = "zip3s"
+	zip5RateAreasFile   = "zip5_rate_areas"
+)
+
+// Item rates that don't depend on weight cover every weight
+const (
+	defaultWeightLbsLower = unit.Pound(0)
+	defaultWeightLbsUpper = unit.Pound(math.MaxInt32)
+)
+
+// The linehaul rate type used when a linehaul rates file doesn't have a type column
+const defaultLinehaulType = "ConusLinehaul"
+
+// TariffYear is a year of 400NG tariff rates, effective from EffectiveDateLower up to but not including
+// EffectiveDateUpper. Zip3s and Zip5RateAreas aren't versioned by date, and are nil unless they are being replaced,
+// which only the year in effect today can do.
+type TariffYear struct {
+	EffectiveDateLower time.Time
+	EffectiveDateUpper time.Time
+	LinehaulRates      models.Tariff400ngLinehaulRates
+	ShorthaulRates     models.Tariff400ngShorthaulRates
+	ServiceAreas       models.Tariff400ngServiceAreas
+	FullPackRates      models.Tariff400ngFullPackRates
+	FullUnpackRates    models.Tariff400ngFullUnpackRates
+	ItemRates          models.Tariff400ngItemRates
+	Zip3s              models.Tariff400ngZip3s
+	Zip5RateAreas      models.Tariff400ngZip5RateAreas
+}
+
+// row is a row of a tariff spreadsheet, keyed by column name
+type row struct {
+	file   string
+	number int
+	values map[string]string
+}
+
+// errorf returns an error locating a problem in the spreadsheet
+func (r row) errorf(format string, args ...interface{}) error {
+	return errors.Errorf("%s row %d: %s", r.file, r.number, fmt.Sprintf(format, args...))
+}
+
+func (r row) string(column string) (string, error) {
+	value, ok := r.values[column]
+	if !ok || value == "" {
+		return "", r.errorf("missing %s", column)
+	}
+	return value, nil
+}
+
+func (r row) int(column string) (int, error) {
+	value, err := r.string(column)
+	if err != nil {
+		return 0, err
+	}
+	// Spreadsheets often store whole numbers as floats
+	f, err := strconv.ParseFloat(strings.Replace(value, ",", "", -1), 64)
+	if err != nil || f != math.Trunc(f) {
+		return 0, r.errorf("%s must be a whole number, got %q", column, value)
+	}
+	return int(f), nil
+}
+
+// optionalInt returns the value of a column, or nil if it is empty or missing
+func (r row) optionalInt(column string) (*int, error) {
+	if r.values[column] == "" {
+		return nil, nil
+	}
+	i, err := r.int(column)
+	return &i, err
+}
+
+// readRows reads the first sheet of an xlsx file, or a CSV file, skipping blank rows
+func readRows(path string) ([]row, error) {
+	var records [][]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx":
+		xlFile, err := xlsx.OpenFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read %s", path)
+		}
+		if len(xlFile.Sheets) == 0 {
+			return nil, errors.Errorf("%s has no sheets", path)
+		}
+		for _, xlRow := range xlFile.Sheets[0].Rows {
+			record := make([]string, len(xlRow.Cells))
+			for i, cell := range xlRow.Cells {
+				record[i] = cell.String()
+			}
+			records = append(records, record)
+		}
+	case ".csv":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read %s", path)
+		}
+		defer f.Close()
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = -1
+		records, err = reader.ReadAll()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read %s", path)
+		}
+	default:
+		return nil, errors.Errorf("%s must be an xlsx or CSV file", path)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.Errorf("%s has no header row", path)
+	}
+	header := make([]string, len(records[0]))
+	for i, column := range records[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+	}
+
+	var rows []row
+	for i, record := range records[1:] {
+		r := row{file: filepath.Base(path), number: i + 2, values: map[string]string{}}
+		blank := true
+		for j, value := range record {
+			value = strings.TrimSpace(value)
+			if j < len(header) && value != "" {
+				r.values[header[j]] = value
+				blank = false
+			}
+		}
+		if !blank {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+// findTableFile returns the path of the xlsx or CSV file for a table in dir, or an empty string if there isn't one
+func findTableFile(dir string, name string) (string, error) {
+	var found string
+	for _, ext := range []string{".xlsx", ".csv"} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			if found != "" {
+				return "", errors.Errorf("%s has both %s and %s", dir, filepath.Base(found), filepath.Base(path))
+			}
+			found = path
+		}
+	}
+	return found, nil
+}
+
+// ReadTariffYear reads a year of tariff rates from the spreadsheets in dir. The rate tables are required; zip3s and
+// zip5 rate areas are only read if dir has files for them.
+func ReadTariffYear(dir string, effectiveDateLower time.Time, effectiveDateUpper time.Time) (TariffYear, error) {
+	year := TariffYear{
+		EffectiveDateLower: effectiveDateLower,
+		EffectiveDateUpper: effectiveDateUpper,
+	}
+	if !effectiveDateLower.Before(effectiveDateUpper) {
+		return year, errors.Errorf("effective date range %s to %s is empty", formatDate(effectiveDateLower), formatDate(effectiveDateUpper))
+	}
+
+	tables := []struct {
+		name     string
+		required bool
+		parse    func(*TariffYear, row) error
+	}{
+		{linehaulRatesFile, true, parseLinehaulRate},
+		{shorthaulRatesFile, true, parseShorthaulRate},
+		{serviceAreasFile, true, parseServiceArea},
+		{fullPackRatesFile, true, parseFullPackRate},
+		{fullUnpackRatesFile, true, parseFullUnpackRate},
+		{itemRatesFile, true, parseItemRate},
+		{zip3sFile, false, parseZip3},
+		{zip5RateAreasFile, false, parseZip5RateArea},
+	}
+
+	var problems []string
+	for _, table := range tables {
+		path, err := findTableFile(dir, table.name)
+		if err != nil {
+			return year, err
+		}
+		if path == "" {
+			if table.required {
+				problems = append(problems, fmt.Sprintf("%s has no %s.xlsx or %s.csv", dir, table.name, table.name))
+			}
+			continue
+		}
+		rows, err := readRows(path)
+		if err != nil {
+			return year, err
+		}
+		if len(rows) == 0 {
+			problems = append(problems, fmt.Sprintf("%s has no rows", filepath.Base(path)))
+		}
+		for _, r := range rows {
+			if err := table.parse(&year, r); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+	if year.Zip3s == nil && year.Zip5RateAreas != nil {
+		problems = append(problems, "zip5 rate areas can only be replaced along with zip3s")
+	}
+
+	if len(problems) > 0 {
+		return year, errors.Errorf("could not read tariff year:\n%s", strings.Join(problems, "\n"))
+	}
+	return year, nil
+}
+
+// ints reads several whole number columns from a row
+func (r row) ints(columns ...string) ([]int, error) {
+	values := make([]int, len(columns))
+	for i, column := range columns {
+		value, err := r.int(column)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func parseLinehaulRate(year *TariffYear, r row) error {
+	values, err := r.ints("distance_miles_lower", "distance_miles_upper", "weight_lbs_lower", "weight_lbs_upper", "rate_cents")
+	if err != nil {
+		return err
+	}
+	linehaulType := r.values["type"]
+	if linehaulType == "" {
+		linehaulType = defaultLinehaulType
+	}
+	year.LinehaulRates = append(year.LinehaulRates, models.Tariff400ngLinehaulRate{
+		DistanceMilesLower: values[0],
+		DistanceMilesUpper: values[1],
+		WeightLbsLower:     unit.Pound(values[2]),
+		WeightLbsUpper:     unit.Pound(values[3]),
+		RateCents:          unit.Cents(values[4]),
+		Type:               linehaulType,
+		EffectiveDateLower: year.EffectiveDateLower,
+		EffectiveDateUpper: year.EffectiveDateUpper,
+	})
+	return nil
+}
+
+func parseShorthaulRate(year *TariffYear, r row) error {
+	values, err := r.ints("cwt_miles_lower", "cwt_miles_upper", "rate_cents")
+	if err != nil {
+		return err
+	}
+	year.ShorthaulRates = append(year.ShorthaulRates, models.Tariff400ngShorthaulRate{
+		CwtMilesLower:      values[0],
+		CwtMilesUpper:      values[1],
+		RateCents:          unit.Cents(values[2]),
+		EffectiveDateLower: year.EffectiveDateLower,
+		EffectiveDateUpper: year.EffectiveDateUpper,
+	})
+	return nil
+}
+
+func parseServiceArea(year *TariffYear, r row) error {
+	serviceArea, err := r.string("service_area")
+	if err != nil {
+		return err
+	}
+	name, err := r.string("name")
+	if err != nil {
+		return err
+	}
+	values, err := r.ints("services_schedule", "linehaul_factor", "service_charge_cents", "sit_185a_rate_cents", "sit_185b_rate_cents", "sit_pd_schedule")
+	if err != nil {
+		return err
+	}
+	year.ServiceAreas = append(year.ServiceAreas, models.Tariff400ngServiceArea{
+		ServiceArea:        serviceArea,
+		Name:               name,
+		ServicesSchedule:   values[0],
+		LinehaulFactor:     unit.Cents(values[1]),
+		ServiceChargeCents: unit.Cents(values[2]),
+		SIT185ARateCents:   unit.Cents(values[3]),
+		SIT185BRateCents:   unit.Cents(values[4]),
+		SITPDSchedule:      values[5],
+		EffectiveDateLower: year.EffectiveDateLower,
+		EffectiveDateUpper: year.EffectiveDateUpper,
+	})
+	return nil
+}
+
+func parseFullPackRate(year *TariffYear, r row) error {
+	values, err := r.ints("schedule", "weight_lbs_lower", "weight_lbs_upper", "rate_cents")
+	if err != nil {
+		return err
+	}
+	year.FullPackRates = append(year.FullPackRates, models.Tariff400ngFullPackRate{
+		Schedule:           values[0],
+		WeightLbsLower:     unit.Pound(values[1]),
+		WeightLbsUpper:     unit.Pound(values[2]),
+		RateCents:          unit.Cents(values[3]),
+		EffectiveDateLower: year.EffectiveDateLower,
+		EffectiveDateUpper: year.EffectiveDateUpper,
+	})
+	return nil
+}
+
+func parseFullUnpackRate(year *TariffYear, r row) error {
+	values, err := r.ints("schedule", "rate_millicents")
+	if err != nil {
+		return err
+	}
+	year.FullUnpackRates = append(year.FullUnpackRates, models.Tariff400ngFullUnpackRate{
+		Schedule:           values[0],
+		RateMillicents:     values[1],
+		EffectiveDateLower: year.EffectiveDateLower,
+		EffectiveDateUpper: year.EffectiveDateUpper,
+	})
+	return nil
+}
+
+func parseItemRate(year *TariffYear, r row) error {
+	code, err := r.string("code")
+	if err != nil {
+		return err
+	}
+	schedule, err := r.optionalInt("schedule")
+	if err != nil {
+		return err
+	}
+	rate, err := r.int("rate_cents")
+	if err != nil {
+		return err
+	}
+	weightLower, weightUpper := defaultWeightLbsLower, defaultWeightLbsUpper
+	if lower, err := r.optionalInt("weight_lbs_lower"); err != nil {
+		return err
+	} else if lower != nil {
+		weightLower = unit.Pound(*lower)
+	}
+	if upper, err := r.optionalInt("weight_lbs_upper"); err != nil {
+		return err
+	} else if upper != nil {
+		weightUpper = unit.Pound(*upper)
+	}
+	year.ItemRates = append(year.ItemRates, models.Tariff400ngItemRate{
+		Code:               code,
+		Schedule:           schedule,
+		WeightLbsLower:     weightLower,
+		WeightLbsUpper:     weightUpper,
+		RateCents:          unit.Cents(rate),
+		EffectiveDateLower: year.EffectiveDateLower,
+		EffectiveDateUpper: year.EffectiveDateUpper,
+	})
+	return nil
+}
+
+func parseZip3(year *TariffYear, r row) error {
+	var values []string
+	for _, column := range []string{"zip3", "basepoint_city", "state", "service_area", "rate_area", "region"} {
+		value, err := r.string(column)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+	year.Zip3s = append(year.Zip3s, models.Tariff400ngZip3{
+		Zip3:          padZip(values[0], 3),
+		BasepointCity: values[1],
+		State:         values[2],
+		ServiceArea:   values[3],
+		RateArea:      values[4],
+		Region:        values[5],
+	})
+	return nil
+}
+
+func parseZip5RateArea(year *TariffYear, r row) error {
+	zip5, err := r.string("zip5")
+	if err != nil {
+		return err
+	}
+	rateArea, err := r.string("rate_area")
+	if err != nil {
+		return err
+	}
+	year.Zip5RateAreas = append(year.Zip5RateAreas, models.Tariff400ngZip5RateArea{
+		Zip5:     padZip(zip5, 5),
+		RateArea: rateArea,
+	})
+	return nil
+}
+
+// padZip restores the leading zeros of zip codes that a spreadsheet has stored as numbers
+func padZip(zip string, length int) string {
+	if len(zip) < length {
+		if _, err := strconv.Atoi(zip); err == nil {
+			return strings.Repeat("0", length-len(zip)) + zip
+		}
+	}
+	return zip
+}
+
+func formatDate(date time.Time) string {
+	return date.Format("2006-01-02")
+}
@@ -0,0 +1,296 @@
+package tariff400ngloader
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/testingsuite"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+type Tariff400ngLoaderSuite struct {
+	testingsuite.PopTestSuite
+	logger *zap.Logger
+}
+
+func (suite *Tariff400ngLoaderSuite) SetupTest() {
+	suite.DB().TruncateAll()
+}
+
+func TestTariff400ngLoaderSuite(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	hs := &Tariff400ngLoaderSuite{
+		PopTestSuite: testingsuite.NewPopTestSuite(testingsuite.CurrentPackage()),
+		logger:       logger,
+	}
+
+	suite.Run(t, hs)
+}
+
+var (
+	may2019 = time.Date(2019, time.May, 15, 0, 0, 0, 0, time.UTC)
+	may2020 = time.Date(2020, time.May, 15, 0, 0, 0, 0, time.UTC)
+	may2021 = time.Date(2021, time.May, 15, 0, 0, 0, 0, time.UTC)
+)
+
+var testItemCodes = map[string]bool{"105A": true, "105C": true, "210A": true}
+
+func (suite *Tariff400ngLoaderSuite) makeItems() {
+	for code := range testItemCodes {
+		testdatagen.MakeTariff400ngItem(suite.DB(), testdatagen.Assertions{
+			Tariff400ngItem: models.Tariff400ngItem{Code: code},
+		})
+	}
+}
+
+func (suite *Tariff400ngLoaderSuite) TestReadTariffYear() {
+	year, err := ReadTariffYear("./testdata/valid", may2019, may2020)
+	suite.NoError(err)
+
+	suite.Len(year.LinehaulRates, 4)
+	suite.Equal(unit.Cents(1250), year.LinehaulRates[0].RateCents)
+	suite.Equal(defaultLinehaulType, year.LinehaulRates[0].Type)
+	suite.Equal(may2019, year.LinehaulRates[0].EffectiveDateLower)
+	suite.Equal(may2020, year.LinehaulRates[0].EffectiveDateUpper)
+	suite.Len(year.ShorthaulRates, 2)
+	suite.Len(year.ServiceAreas, 2)
+	suite.Equal("Birmingham, AL", year.ServiceAreas[0].Name)
+	suite.Len(year.FullPackRates, 4)
+	suite.Len(year.FullUnpackRates, 2)
+
+	suite.Len(year.ItemRates, 4)
+	suite.Nil(year.ItemRates[0].Schedule)
+	suite.Equal(defaultWeightLbsLower, year.ItemRates[0].WeightLbsLower)
+	suite.Equal(defaultWeightLbsUpper, year.ItemRates[0].WeightLbsUpper)
+	suite.Equal(2, *year.ItemRates[3].Schedule)
+	suite.Equal(unit.Pound(2000), year.ItemRates[3].WeightLbsLower)
+	suite.Equal(defaultWeightLbsUpper, year.ItemRates[3].WeightLbsUpper)
+
+	suite.Len(year.Zip3s, 2)
+	suite.Len(year.Zip5RateAreas, 2)
+}
+
+func (suite *Tariff400ngLoaderSuite) TestReadTariffYearErrors() {
+	_, err := ReadTariffYear("./testdata/valid", may2020, may2019)
+	suite.Error(err)
+
+	_, err = ReadTariffYear("./testdata", may2019, may2020)
+	if suite.Error(err) {
+		suite.Contains(err.Error(), "has no linehaul_rates.xlsx or linehaul_rates.csv")
+	}
+}
+
+func (suite *Tariff400ngLoaderSuite) TestPadZip() {
+	suite.Equal("010", padZip("10", 3))
+	suite.Equal("01001", padZip("1001", 5))
+	suite.Equal("90210", padZip("90210", 5))
+	suite.Equal("AB", padZip("AB", 3))
+}
+
+func (suite *Tariff400ngLoaderSuite) TestCheckBands() {
+	suite.Empty(checkBands([]band{{1000, 2000}, {0, 1000}, {2000, 3000}}, false))
+	suite.Empty(checkBands([]band{{0, 999}, {1000, 1999}}, true))
+
+	suite.Equal([]string{"gap between bands 0-1000 and 1100-2000"}, checkBands([]band{{0, 1000}, {1100, 2000}}, false))
+	suite.Equal([]string{"bands 0-1000 and 999-2000 overlap"}, checkBands([]band{{0, 1000}, {999, 2000}}, false))
+	suite.Equal([]string{"bands 0-1000 and 1000-1999 overlap"}, checkBands([]band{{0, 1000}, {1000, 1999}}, true))
+	suite.Equal([]string{"band 10-10 is empty"}, checkBands([]band{{10, 10}}, false))
+}
+
+func (suite *Tariff400ngLoaderSuite) TestValidateYear() {
+	year, err := ReadTariffYear("./testdata/valid", may2019, may2020)
+	suite.NoError(err)
+	suite.Empty(validateYear(year, referenceData{itemCodes: testItemCodes}))
+
+	year, err = ReadTariffYear("./testdata/invalid", may2019, may2020)
+	suite.NoError(err)
+	suite.Equal([]string{
+		"ConusLinehaul linehaul rate distances: gap between bands 0-800 and 900-1600",
+		"ConusLinehaul linehaul rates: no rate for 900-1600 miles and 2000-3000 lbs",
+		"shorthaul rate cwt-miles: bands 0-16001 and 16000-32001 overlap",
+		"item rate 210A is not for a known 400NG item",
+		"item rate 210A is not for a known 400NG item",
+		"item 210A schedule 2 rate weights: gap between bands 0-1999 and 2100-2147483647",
+		"zip3 902: no service area 80",
+		"zip3 902: rate areas are by zip5, but there are no zip5 rate areas for it",
+	}, validateYear(year, referenceData{itemCodes: map[string]bool{"105A": true}}))
+}
+
+func (suite *Tariff400ngLoaderSuite) TestValidateYearUsesLoadedZips() {
+	year, err := ReadTariffYear("./testdata/valid", may2019, may2020)
+	suite.NoError(err)
+	reference := referenceData{
+		itemCodes:     testItemCodes,
+		zip3s:         year.Zip3s,
+		zip5RateAreas: year.Zip5RateAreas,
+	}
+	year.Zip3s, year.Zip5RateAreas = nil, nil
+	suite.Empty(validateYear(year, reference))
+
+	year.ServiceAreas = year.ServiceAreas[:1]
+	suite.Equal([]string{"zip3 902: no service area 80"}, validateYear(year, reference))
+}
+
+func (suite *Tariff400ngLoaderSuite) TestCheckEffectiveDates() {
+	year := TariffYear{EffectiveDateLower: may2019, EffectiveDateUpper: may2020}
+	may2018 := time.Date(2018, time.May, 15, 0, 0, 0, 0, time.UTC)
+
+	// Adjoining years and the year being replaced are fine
+	suite.Empty(checkEffectiveDates("rates", year, []effectiveDateRange{
+		{may2018, may2019}, {may2019, may2020}, {may2020, may2021},
+	}))
+
+	suite.Equal([]string{"rates: rates effective 2018-05-15 to 2019-06-01 overlap the new tariff"},
+		checkEffectiveDates("rates", year, []effectiveDateRange{
+			{may2018, time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		}))
+	suite.Equal([]string{
+		"rates: gap between rates ending 2019-05-01 and the new tariff",
+		"rates: gap between the new tariff and rates starting 2020-06-01",
+	}, checkEffectiveDates("rates", year, []effectiveDateRange{
+		{may2018, time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC), may2021},
+	}))
+}
+
+func (suite *Tariff400ngLoaderSuite) TestDiff() {
+	current, err := ReadTariffYear("./testdata/valid", may2019, may2020)
+	suite.NoError(err)
+	next, err := ReadTariffYear("./testdata/valid", may2020, may2021)
+	suite.NoError(err)
+
+	report := Diff(current, next)
+	suite.False(report.HasChanges())
+
+	next.LinehaulRates[0].RateCents = 1375
+	next.ShorthaulRates = next.ShorthaulRates[:1]
+	next.ItemRates = append(next.ItemRates, models.Tariff400ngItemRate{
+		Code:           "105B",
+		WeightLbsLower: defaultWeightLbsLower,
+		WeightLbsUpper: defaultWeightLbsUpper,
+		RateCents:      4650,
+	})
+	next.Zip3s, next.Zip5RateAreas = nil, nil
+
+	report = Diff(current, next)
+	suite.True(report.HasChanges())
+	suite.Len(report.Tables, 6)
+	suite.Equal([]string{"ConusLinehaul 0-800 miles 1000-2000 lbs: $12.50 -> $13.75 (+10.0%)"}, report.Tables[0].Changed)
+	suite.Equal(3, report.Tables[0].Unchanged)
+	suite.Equal([]string{"16001-32001 cwt-miles: $655.81"}, report.Tables[1].Removed)
+	suite.Equal([]string{"105B any schedule 0-2147483647 lbs: $46.50"}, report.Tables[5].Added)
+
+	var b bytes.Buffer
+	suite.NoError(report.Write(&b))
+	suite.Contains(b.String(), "400NG tariff effective 2020-05-15 to 2021-05-15, compared with the tariff effective 2019-05-15 to 2020-05-15")
+	suite.Contains(b.String(), "linehaul rates changed:\n  ConusLinehaul 0-800 miles 1000-2000 lbs: $12.50 -> $13.75 (+10.0%)\n")
+	suite.NotContains(b.String(), "zip3s")
+}
+
+// newLoaderOn returns a Loader whose clock is set to now
+func (suite *Tariff400ngLoaderSuite) newLoaderOn(now time.Time) Loader {
+	loader := NewLoader(suite.DB(), suite.logger)
+	mockClock := clock.NewMock()
+	mockClock.Add(now.Sub(mockClock.Now()))
+	loader.clock = mockClock
+	return loader
+}
+
+func (suite *Tariff400ngLoaderSuite) TestSaveAndFetchTariffYear() {
+	suite.makeItems()
+	loader := suite.newLoaderOn(may2019.AddDate(0, 1, 0))
+
+	year, err := ReadTariffYear("./testdata/valid", may2019, may2020)
+	suite.NoError(err)
+	problems, err := loader.Validate(year)
+	suite.NoError(err)
+	suite.Empty(problems)
+	suite.NoError(loader.Save(year))
+
+	fetched, err := loader.FetchTariffYear(may2019.AddDate(0, 1, 0))
+	suite.NoError(err)
+	suite.True(may2019.Equal(fetched.EffectiveDateLower))
+	suite.True(may2020.Equal(fetched.EffectiveDateUpper))
+	suite.Len(fetched.LinehaulRates, 4)
+	suite.Len(fetched.ItemRates, 4)
+	suite.Len(fetched.Zip3s, 2)
+	suite.Len(fetched.Zip5RateAreas, 2)
+
+	// The next year is compared with the one before it, and doesn't need to replace the zips
+	next, err := ReadTariffYear("./testdata/valid", may2020, may2021)
+	suite.NoError(err)
+	next.Zip3s, next.Zip5RateAreas = nil, nil
+	next.LinehaulRates[0].RateCents = 1375
+	problems, err = loader.Validate(next)
+	suite.NoError(err)
+	suite.Empty(problems)
+	previous, err := loader.FetchPreviousTariffYear(next)
+	suite.NoError(err)
+	suite.Len(Diff(previous, next).Tables[0].Changed, 1)
+
+	// Saving the same year again replaces it
+	suite.NoError(loader.Save(year))
+	count, err := suite.DB().Count(&models.Tariff400ngLinehaulRate{})
+	suite.NoError(err)
+	suite.Equal(4, count)
+}
+
+func (suite *Tariff400ngLoaderSuite) TestSaveOnlyReplacesZipsInCurrentYear() {
+	suite.makeItems()
+	current, err := ReadTariffYear("./testdata/valid", may2019, may2020)
+	suite.NoError(err)
+	suite.NoError(suite.newLoaderOn(may2019.AddDate(0, 1, 0)).Save(current))
+
+	// A year that isn't in effect yet can't replace the zips today's rates are looked up with
+	loader := suite.newLoaderOn(may2020.AddDate(0, -1, 0))
+	next, err := ReadTariffYear("./testdata/valid", may2020, may2021)
+	suite.NoError(err)
+	problems, err := loader.Validate(next)
+	suite.NoError(err)
+	suite.Contains(problems, "zip3s and zip5 rate areas can only be replaced by the tariff in effect today, not one effective 2020-05-15 to 2021-05-15")
+	suite.Error(loader.Save(next))
+	count, err := suite.DB().Count(&models.Tariff400ngLinehaulRate{})
+	suite.NoError(err)
+	suite.Equal(4, count)
+
+	// It can be saved without them
+	next.Zip3s, next.Zip5RateAreas = nil, nil
+	suite.NoError(loader.Save(next))
+	count, err = suite.DB().Count(&models.Tariff400ngZip3{})
+	suite.NoError(err)
+	suite.Equal(2, count)
+
+	// Nor can a past year
+	loader = suite.newLoaderOn(may2021)
+	current, err = ReadTariffYear("./testdata/valid", may2019, may2020)
+	suite.NoError(err)
+	suite.Error(loader.Save(current))
+}
+
+func (suite *Tariff400ngLoaderSuite) TestValidateOverlappingTariffYear() {
+	suite.makeItems()
+	loader := suite.newLoaderOn(may2019.AddDate(0, 1, 0))
+
+	year, err := ReadTariffYear("./testdata/valid", may2019, may2020)
+	suite.NoError(err)
+	suite.NoError(loader.Save(year))
+
+	overlapping, err := ReadTariffYear("./testdata/valid", may2019.AddDate(0, 6, 0), may2021)
+	suite.NoError(err)
+	problems, err := loader.Validate(overlapping)
+	suite.NoError(err)
+	suite.Contains(problems, "tariff400ng_linehaul_rates: rates effective 2019-05-15 to 2020-05-15 overlap the new tariff")
+	suite.Contains(problems, "tariff400ng_item_rates: rates effective 2019-05-15 to 2020-05-15 overlap the new tariff")
+}
@@ -0,0 +1,318 @@
+package tariff400ngloader
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// referenceData is what a tariff year is checked against that isn't part of the year itself
+type referenceData struct {
+	// itemCodes are the codes of the accessorials in tariff400ng_items
+	itemCodes map[string]bool
+	// zip3s and zip5RateAreas are used when the tariff year doesn't replace them
+	zip3s         models.Tariff400ngZip3s
+	zip5RateAreas models.Tariff400ngZip5RateAreas
+}
+
+// band is a range of weights, distances or cwt-miles. Most bands are lower inclusive and upper exclusive, but item
+// rate bands include their upper bound.
+type band struct {
+	lower int
+	upper int
+}
+
+func (b band) String() string {
+	return fmt.Sprintf("%d-%d", b.lower, b.upper)
+}
+
+// checkBands checks that bands are contiguous, without any gaps or overlaps, and returns a description of each
+// problem found
+func checkBands(bands []band, inclusiveUpper bool) []string {
+	sorted := append([]band(nil), bands...)
+	sortBands(sorted)
+
+	var problems []string
+	for i, b := range sorted {
+		if b.upper < b.lower || (b.upper == b.lower && !inclusiveUpper) {
+			problems = append(problems, fmt.Sprintf("band %s is empty", b))
+		}
+		if i == 0 {
+			continue
+		}
+		previous := sorted[i-1]
+		next := previous.upper
+		if inclusiveUpper {
+			next++
+		}
+		switch {
+		case b.lower > next:
+			problems = append(problems, fmt.Sprintf("gap between bands %s and %s", previous, b))
+		case b.lower < next:
+			problems = append(problems, fmt.Sprintf("bands %s and %s overlap", previous, b))
+		}
+	}
+	return problems
+}
+
+// validator collects the problems found in a tariff year
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// addBandProblems checks the bands of a table and records any problems, prefixed with where they were found
+func (v *validator) addBandProblems(context string, bands []band, inclusiveUpper bool) {
+	for _, problem := range checkBands(bands, inclusiveUpper) {
+		v.addf("%s: %s", context, problem)
+	}
+}
+
+// validateModel records the model's own validation errors
+func (v *validator) validateModel(context string, model interface {
+	Validate(*pop.Connection) (*validate.Errors, error)
+}) {
+	verrs, err := model.Validate(nil)
+	if err != nil {
+		v.addf("%s: %s", context, err)
+	} else if verrs.HasAny() {
+		v.addf("%s: %s", context, verrs)
+	}
+}
+
+// validateYear checks that a tariff year is complete and consistent, and returns a description of each problem found.
+// Rate bands must be contiguous, every service area schedule must have rates, and every zip3 must have a service area.
+func validateYear(year TariffYear, reference referenceData) []string {
+	v := validator{}
+	v.validateLinehaulRates(year.LinehaulRates)
+	v.validateShorthaulRates(year.ShorthaulRates)
+	v.validateFullPackRates(year.FullPackRates)
+	v.validateFullUnpackRates(year.FullUnpackRates)
+	v.validateItemRates(year.ItemRates, reference.itemCodes)
+	v.validateServiceAreas(year)
+
+	zip3s, zip5RateAreas := year.Zip3s, year.Zip5RateAreas
+	if zip3s == nil {
+		zip3s = reference.zip3s
+	}
+	if zip5RateAreas == nil {
+		zip5RateAreas = reference.zip5RateAreas
+	}
+	v.validateZips(year.ServiceAreas, zip3s, zip5RateAreas)
+	return v.problems
+}
+
+func (v *validator) validateLinehaulRates(rates models.Tariff400ngLinehaulRates) {
+	// Each type of linehaul rate is a grid of distance bands by weight bands
+	type cell struct {
+		distance band
+		weight   band
+	}
+	grids := map[string]map[cell]int{}
+	for i := range rates {
+		rate := &rates[i]
+		v.validateModel("linehaul rate", rate)
+		if grids[rate.Type] == nil {
+			grids[rate.Type] = map[cell]int{}
+		}
+		grids[rate.Type][cell{
+			distance: band{rate.DistanceMilesLower, rate.DistanceMilesUpper},
+			weight:   band{rate.WeightLbsLower.Int(), rate.WeightLbsUpper.Int()},
+		}]++
+	}
+
+	linehaulTypes := make([]string, 0, len(grids))
+	for linehaulType := range grids {
+		linehaulTypes = append(linehaulTypes, linehaulType)
+	}
+	sort.Strings(linehaulTypes)
+	for _, linehaulType := range linehaulTypes {
+		grid := grids[linehaulType]
+		distances, weights := map[band]bool{}, map[band]bool{}
+		for c, count := range grid {
+			distances[c.distance] = true
+			weights[c.weight] = true
+			if count > 1 {
+				v.addf("%s linehaul rates: %d rates for %s miles and %s lbs", linehaulType, count, c.distance, c.weight)
+			}
+		}
+		v.addBandProblems(linehaulType+" linehaul rate distances", bandSet(distances), false)
+		v.addBandProblems(linehaulType+" linehaul rate weights", bandSet(weights), false)
+		for _, distance := range bandSet(distances) {
+			for _, weight := range bandSet(weights) {
+				if grid[cell{distance, weight}] == 0 {
+					v.addf("%s linehaul rates: no rate for %s miles and %s lbs", linehaulType, distance, weight)
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateShorthaulRates(rates models.Tariff400ngShorthaulRates) {
+	var bands []band
+	for i := range rates {
+		v.validateModel("shorthaul rate", &rates[i])
+		bands = append(bands, band{rates[i].CwtMilesLower, rates[i].CwtMilesUpper})
+	}
+	v.addBandProblems("shorthaul rate cwt-miles", bands, false)
+}
+
+func (v *validator) validateFullPackRates(rates models.Tariff400ngFullPackRates) {
+	schedules := map[int][]band{}
+	for i := range rates {
+		rate := &rates[i]
+		v.validateModel("full pack rate", rate)
+		schedules[rate.Schedule] = append(schedules[rate.Schedule], band{rate.WeightLbsLower.Int(), rate.WeightLbsUpper.Int()})
+	}
+	var scheduleNumbers []int
+	for schedule := range schedules {
+		scheduleNumbers = append(scheduleNumbers, schedule)
+	}
+	sort.Ints(scheduleNumbers)
+	for _, schedule := range scheduleNumbers {
+		v.addBandProblems(fmt.Sprintf("schedule %d full pack rate weights", schedule), schedules[schedule], false)
+	}
+}
+
+func (v *validator) validateFullUnpackRates(rates models.Tariff400ngFullUnpackRates) {
+	seen := map[int]bool{}
+	for i := range rates {
+		rate := &rates[i]
+		v.validateModel("full unpack rate", rate)
+		if seen[rate.Schedule] {
+			v.addf("schedule %d has more than one full unpack rate", rate.Schedule)
+		}
+		seen[rate.Schedule] = true
+	}
+}
+
+func (v *validator) validateItemRates(rates models.Tariff400ngItemRates, itemCodes map[string]bool) {
+	type itemSchedule struct {
+		code     string
+		schedule string
+	}
+	groups := map[itemSchedule][]band{}
+	for i := range rates {
+		rate := &rates[i]
+		v.validateModel("item rate "+rate.Code, rate)
+		if itemCodes != nil && !itemCodes[rate.Code] {
+			v.addf("item rate %s is not for a known 400NG item", rate.Code)
+		}
+		key := itemSchedule{code: rate.Code, schedule: "any schedule"}
+		if rate.Schedule != nil {
+			key.schedule = fmt.Sprintf("schedule %d", *rate.Schedule)
+		}
+		groups[key] = append(groups[key], band{rate.WeightLbsLower.Int(), rate.WeightLbsUpper.Int()})
+	}
+
+	keys := make([]itemSchedule, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].code == keys[j].code {
+			return keys[i].schedule < keys[j].schedule
+		}
+		return keys[i].code < keys[j].code
+	})
+	for _, key := range keys {
+		v.addBandProblems(fmt.Sprintf("item %s %s rate weights", key.code, key.schedule), groups[key], true)
+	}
+}
+
+func (v *validator) validateServiceAreas(year TariffYear) {
+	packSchedules := map[int]bool{}
+	for _, rate := range year.FullPackRates {
+		packSchedules[rate.Schedule] = true
+	}
+	unpackSchedules := map[int]bool{}
+	for _, rate := range year.FullUnpackRates {
+		unpackSchedules[rate.Schedule] = true
+	}
+
+	seen := map[string]bool{}
+	for i := range year.ServiceAreas {
+		serviceArea := &year.ServiceAreas[i]
+		context := "service area " + serviceArea.ServiceArea
+		v.validateModel(context, serviceArea)
+		if seen[serviceArea.ServiceArea] {
+			v.addf("%s is listed more than once", context)
+		}
+		seen[serviceArea.ServiceArea] = true
+		if !packSchedules[serviceArea.ServicesSchedule] {
+			v.addf("%s: no full pack rates for services schedule %d", context, serviceArea.ServicesSchedule)
+		}
+		if !unpackSchedules[serviceArea.ServicesSchedule] {
+			v.addf("%s: no full unpack rate for services schedule %d", context, serviceArea.ServicesSchedule)
+		}
+	}
+}
+
+// zip3RateArea is the rate area of zip3s whose rate areas are given by their zip5s
+const zip3RateArea = "ZIP"
+
+func (v *validator) validateZips(serviceAreas models.Tariff400ngServiceAreas, zip3s models.Tariff400ngZip3s, zip5RateAreas models.Tariff400ngZip5RateAreas) {
+	servicedAreas := map[string]bool{}
+	for _, serviceArea := range serviceAreas {
+		servicedAreas[serviceArea.ServiceArea] = true
+	}
+	zip5Prefixes := map[string]bool{}
+	seenZip5s := map[string]bool{}
+	for i := range zip5RateAreas {
+		zip5RateArea := &zip5RateAreas[i]
+		v.validateModel("zip5 "+zip5RateArea.Zip5, zip5RateArea)
+		if seenZip5s[zip5RateArea.Zip5] {
+			v.addf("zip5 %s is listed more than once", zip5RateArea.Zip5)
+		}
+		seenZip5s[zip5RateArea.Zip5] = true
+		if len(zip5RateArea.Zip5) >= 3 {
+			zip5Prefixes[zip5RateArea.Zip5[:3]] = true
+		}
+	}
+
+	if len(zip3s) == 0 {
+		v.addf("there are no zip3s")
+	}
+	seenZip3s := map[string]bool{}
+	for i := range zip3s {
+		zip3 := &zip3s[i]
+		context := "zip3 " + zip3.Zip3
+		v.validateModel(context, zip3)
+		if seenZip3s[zip3.Zip3] {
+			v.addf("%s is listed more than once", context)
+		}
+		seenZip3s[zip3.Zip3] = true
+		if !servicedAreas[zip3.ServiceArea] {
+			v.addf("%s: no service area %s", context, zip3.ServiceArea)
+		}
+		if zip3.RateArea == zip3RateArea && !zip5Prefixes[zip3.Zip3] {
+			v.addf("%s: rate areas are by zip5, but there are no zip5 rate areas for it", context)
+		}
+	}
+}
+
+// bandSet returns the bands in a set, in order
+func bandSet(set map[band]bool) []band {
+	bands := make([]band, 0, len(set))
+	for b := range set {
+		bands = append(bands, b)
+	}
+	sortBands(bands)
+	return bands
+}
+
+func sortBands(bands []band) {
+	sort.Slice(bands, func(i, j int) bool {
+		if bands[i].lower == bands[j].lower {
+			return bands[i].upper < bands[j].upper
+		}
+		return bands[i].lower < bands[j].lower
+	})
+}
@@ -0,0 +1,2 @@
+-- 16B was imported with a trailing space, so it couldn't be found by its code
+UPDATE tariff400ng_items SET code = '16B' WHERE code = '16B ';
@@ -13,6 +13,27 @@ type ShipmentLineItemFetcher struct {
 	mock.Mock
 }
 
+// GetShipmentLineItemByID provides a mock function with given fields: shipmentLineItemID, session
+func (_m *ShipmentLineItemFetcher) GetShipmentLineItemByID(shipmentLineItemID uuid.UUID, session *auth.Session) (models.ShipmentLineItem, error) {
+	ret := _m.Called(shipmentLineItemID, session)
+
+	var r0 models.ShipmentLineItem
+	if rf, ok := ret.Get(0).(func(uuid.UUID, *auth.Session) models.ShipmentLineItem); ok {
+		r0 = rf(shipmentLineItemID, session)
+	} else {
+		r0 = ret.Get(0).(models.ShipmentLineItem)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uuid.UUID, *auth.Session) error); ok {
+		r1 = rf(shipmentLineItemID, session)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetShipmentLineItemsByShipmentID provides a mock function with given fields: shipmentID, session
 func (_m *ShipmentLineItemFetcher) GetShipmentLineItemsByShipmentID(shipmentID uuid.UUID, session *auth.Session) ([]models.ShipmentLineItem, error) {
 	ret := _m.Called(shipmentID, session)
@@ -0,0 +1,45 @@
+package antivirus
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/cli"
+)
+
+// Result is the outcome of scanning a file
+type Result struct {
+	Infected bool
+	// Signature names what was found in an infected file
+	Signature string
+}
+
+// Scanner inspects files for viruses and other malicious content
+//go:generate mockery -name Scanner
+type Scanner interface {
+	Scan(ctx context.Context, data io.Reader) (Result, error)
+}
+
+// NoopScanner reports every file as clean. It is intended only for use in development, where a ClamAV daemon
+// may not be running.
+type NoopScanner struct{}
+
+// Scan reads the file and reports it as clean
+func (NoopScanner) Scan(ctx context.Context, data io.Reader) (Result, error) {
+	return Result{}, nil
+}
+
+// InitScanner initializes the antivirus scanner from command line flags
+func InitScanner(v *viper.Viper, logger Logger) Scanner {
+	if v.GetString(cli.AntivirusScannerFlag) == "clamd" {
+		address := v.GetString(cli.ClamdAddressFlag)
+		logger.Info("Using clamd antivirus scanner", zap.String("address", address))
+		return NewClamdScanner(address, v.GetDuration(cli.ClamdTimeoutFlag))
+	}
+
+	logger.Info("Not scanning uploads for viruses")
+	return NoopScanner{}
+}
@@ -0,0 +1,107 @@
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// clamdChunkSize is the most bytes sent to clamd in one INSTREAM chunk
+const clamdChunkSize = 64 * 1024
+
+// ClamdScanner scans files with a ClamAV daemon, streaming them over its INSTREAM protocol
+type ClamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner creates a ClamdScanner that connects to clamd at address, either a unix:// socket path or a
+// tcp://host:port. Each scan must finish within timeout.
+func NewClamdScanner(address string, timeout time.Duration) *ClamdScanner {
+	network := "unix"
+	if strings.HasPrefix(address, "tcp://") {
+		network = "tcp"
+	}
+	address = strings.TrimPrefix(strings.TrimPrefix(address, "unix://"), "tcp://")
+
+	return &ClamdScanner{
+		network: network,
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// Scan streams data to clamd and parses its verdict
+func (c *ClamdScanner) Scan(ctx context.Context, data io.Reader) (Result, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "could not connect to clamd")
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return Result{}, errors.Wrap(err, "could not set clamd deadline")
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Result{}, errors.Wrap(err, "could not start clamd stream")
+	}
+
+	chunk := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := data.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return Result{}, errors.Wrap(err, "could not stream to clamd")
+			}
+			if _, err := conn.Write(chunk[:n]); err != nil {
+				return Result{}, errors.Wrap(err, "could not stream to clamd")
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return Result{}, errors.Wrap(readErr, "could not read file")
+		}
+	}
+
+	// A zero length chunk ends the stream
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return Result{}, errors.Wrap(err, "could not end clamd stream")
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "could not read clamd reply")
+	}
+	return parseClamdReply(string(bytes.TrimRight(reply, "\x00")))
+}
+
+// parseClamdReply parses replies like "stream: OK" and "stream: Eicar-Signature FOUND"
+func parseClamdReply(reply string) (Result, error) {
+	reply = strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case reply == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return Result{Infected: true, Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	default:
+		return Result{}, errors.Errorf("clamd could not scan file: %s", reply)
+	}
+}
@@ -0,0 +1,117 @@
+package antivirus
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd accepts one INSTREAM connection on a unix socket and replies with reply. It returns the socket address,
+// a channel that receives the streamed content, and a function that shuts it down.
+func fakeClamd(t *testing.T, reply string) (string, <-chan string, func()) {
+	dir, err := ioutil.TempDir("", "clamd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(dir, "clamd.ctl")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := func() {
+		listener.Close()
+		os.RemoveAll(dir)
+	}
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		command, err := r.ReadString(0)
+		if err != nil || command != "zINSTREAM\x00" {
+			received <- "unexpected command " + command
+			return
+		}
+
+		var content strings.Builder
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&content, r, int64(size)); err != nil {
+				return
+			}
+		}
+		received <- content.String()
+		conn.Write([]byte(reply + "\x00"))
+	}()
+
+	return "unix://" + socket, received, cleanup
+}
+
+func TestClamdScannerClean(t *testing.T) {
+	address, received, cleanup := fakeClamd(t, "stream: OK")
+	defer cleanup()
+	scanner := NewClamdScanner(address, time.Second)
+
+	content := strings.Repeat("a perfectly normal file ", 10000)
+	result, err := scanner.Scan(context.Background(), strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("failed to scan: %s", err)
+	}
+	if result.Infected {
+		t.Errorf("expected clean file, got %#v", result)
+	}
+	if got := <-received; got != content {
+		t.Errorf("clamd received %d bytes, expected %d", len(got), len(content))
+	}
+}
+
+func TestClamdScannerInfected(t *testing.T) {
+	address, _, cleanup := fakeClamd(t, "stream: Eicar-Signature FOUND")
+	defer cleanup()
+	scanner := NewClamdScanner(address, time.Second)
+
+	result, err := scanner.Scan(context.Background(), strings.NewReader("infected"))
+	if err != nil {
+		t.Fatalf("failed to scan: %s", err)
+	}
+	if !result.Infected || result.Signature != "Eicar-Signature" {
+		t.Errorf("expected infected file, got %#v", result)
+	}
+}
+
+func TestClamdScannerError(t *testing.T) {
+	address, _, cleanup := fakeClamd(t, "INSTREAM size limit exceeded. ERROR")
+	defer cleanup()
+	scanner := NewClamdScanner(address, time.Second)
+
+	if _, err := scanner.Scan(context.Background(), strings.NewReader("too big")); err == nil {
+		t.Error("expected an error from clamd")
+	}
+}
+
+func TestClamdScannerUnavailable(t *testing.T) {
+	scanner := NewClamdScanner("unix:///nonexistent/clamd.ctl", time.Second)
+
+	if _, err := scanner.Scan(context.Background(), strings.NewReader("file")); err == nil {
+		t.Error("expected an error when clamd is not running")
+	}
+}
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/transcom/mymove/pkg/antivirus"
+)
+
+// EICAR is the standard antivirus test file, which FakeScanner reports as infected
+const EICAR = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// FakeScanner is used for testing to stub out a ClamAV daemon. It reports files containing the EICAR test string
+// as infected.
+type FakeScanner struct {
+	err     error
+	Scanned int
+}
+
+// Scan reads the file and looks for the EICAR test string
+func (fake *FakeScanner) Scan(ctx context.Context, data io.Reader) (antivirus.Result, error) {
+	if fake.err != nil {
+		return antivirus.Result{}, fake.err
+	}
+
+	content, err := ioutil.ReadAll(data)
+	if err != nil {
+		return antivirus.Result{}, err
+	}
+	fake.Scanned++
+
+	if bytes.Contains(content, []byte(EICAR)) {
+		return antivirus.Result{Infected: true, Signature: "Eicar-Signature"}, nil
+	}
+	return antivirus.Result{}, nil
+}
+
+// NewFakeScanner creates a new FakeScanner for testing purposes. If err is not nil every scan fails with it, as
+// if the daemon were unavailable.
+func NewFakeScanner(err error) *FakeScanner {
+	return &FakeScanner{err: err}
+}
@@ -19,21 +19,26 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/webhooks"
 )
 
 const awardQueueLockID = 1
-const numQualBands = 4
-
-// Minimum Performance Score (MPS) is the lowest BVS a TSP can have and still be assigned shipments.
-// TODO: This will eventually need to be configurable; implement as something other than a constant.
-//       Setting to zero for now to make sure that no TSPs are accidentally excluded.
-const mps = 0
 
 // AwardQueue encapsulates the TSP award queue process
 type AwardQueue struct {
 	db *pop.Connection
 	//logger *hnyzap.Logger
-	logger Logger
+	logger  Logger
+	tspHost string
+	// notificationRoutes sends the listed notification types through other channels instead of the outbox
+	notificationRoutes map[notifications.NotificationType][]notifications.NotificationSender
+}
+
+// notificationSender sends the award queue's notifications through its routes, queueing any without a route
+// in the outbox as part of the current transaction
+func (aq *AwardQueue) notificationSender() notifications.NotificationSender {
+	return notifications.NewRoutingNotificationSender(notifications.NewNotificationOutbox(aq.db), aq.notificationRoutes)
 }
 
 func (aq *AwardQueue) findAllUnassignedShipments() (models.Shipments, error) {
@@ -66,6 +71,12 @@ func (aq *AwardQueue) attemptShipmentOffer(ctx context.Context, shipment models.
 		return nil, errors.Wrap(err, "Cannot find TDL in database")
 	}
 
+	// Offer the shipment using the policy the TSPs' quality bands were assigned with
+	policy, err := models.FetchAwardQueuePolicyForTSPPerformances(aq.db, tdl.ID, *shipment.BookDate, *shipment.RequestedPickupDate)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot find award queue policy")
+	}
+
 	var shipmentOffer *models.ShipmentOffer
 
 	// We need to loop here, because if a TSP has a blackout date we need to try again.
@@ -73,7 +84,7 @@ func (aq *AwardQueue) attemptShipmentOffer(ctx context.Context, shipment models.
 	// have blackout dates (imagine a 1-TSP-TDL, with a blackout date) we will keep awarding
 	// administrative shipments forever.
 	firstEligibleTSPPerformance, err := models.NextEligibleTSPPerformance(aq.db, tdl.ID, *shipment.BookDate,
-		*shipment.RequestedPickupDate)
+		*shipment.RequestedPickupDate, policy)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +112,7 @@ func (aq *AwardQueue) attemptShipmentOffer(ctx context.Context, shipment models.
 			}
 
 			var createShipmentOfferErr error
-			shipmentOffer, createShipmentOfferErr = models.CreateShipmentOffer(aq.db, shipment.ID, tsp.ID, tspPerformance.ID, isAdministrativeShipment)
+			shipmentOffer, createShipmentOfferErr = models.CreateShipmentOffer(aq.db, shipment.ID, tsp.ID, tspPerformance.ID, isAdministrativeShipment, policy.StoredID())
 			if createShipmentOfferErr == nil {
 				var tspPerformanceErr error
 				if tspPerformance, tspPerformanceErr = models.IncrementTSPPerformanceOfferCount(aq.db, tspPerformance.ID); tspPerformanceErr == nil {
@@ -114,8 +125,10 @@ func (aq *AwardQueue) attemptShipmentOffer(ctx context.Context, shipment models.
 						}
 
 						aq.logger.TraceInfo(ctx, "Shipment offered to TSP!",
+							zap.String("shipment_offer_id", shipmentOffer.ID.String()),
 							zap.Int("quality_band", qb),
-							zap.Int("offer_count", tspPerformance.OfferCount))
+							zap.Int("offer_count", tspPerformance.OfferCount),
+							zap.Int("award_queue_policy_version", policy.Version))
 						foundAvailableTSP = true
 
 						// Award the shipment
@@ -123,6 +136,18 @@ func (aq *AwardQueue) attemptShipmentOffer(ctx context.Context, shipment models.
 							aq.logger.TraceError(ctx, "Failed to set shipment as awarded", zap.Error(awardShipmentErr))
 							return nil, awardShipmentErr
 						}
+
+						event := webhooks.NewShipmentEvent(models.WebhookEventTypeSHIPMENTAWARDED, shipment.ID, models.ShipmentStatusAWARDED)
+						if publishErr := webhooks.Publish(aq.db, event); publishErr != nil {
+							aq.logger.TraceError(ctx, "Failed to publish shipment awarded webhook", zap.Error(publishErr))
+							return nil, publishErr
+						}
+
+						notification := notifications.NewShipmentAwarded(aq.db, aq.logger, aq.tspHost, shipment.ID, tsp.ID)
+						if notifyErr := aq.notificationSender().SendNotification(ctx, notification); notifyErr != nil {
+							aq.logger.TraceError(ctx, "Failed to notify TSP users of awarded shipment", zap.Error(notifyErr))
+							return nil, notifyErr
+						}
 					}
 				} else {
 					aq.logger.TraceError(ctx, "Failed to increment offer count", zap.Error(tspPerformanceErr))
@@ -136,7 +161,7 @@ func (aq *AwardQueue) attemptShipmentOffer(ctx context.Context, shipment models.
 			aq.logger.TraceInfo(ctx, "Selected TSP has blackouts. Checking for another TSP.")
 
 			tspPerformance, err = models.NextEligibleTSPPerformance(aq.db, tdl.ID, *shipment.BookDate,
-				*shipment.RequestedPickupDate)
+				*shipment.RequestedPickupDate, policy)
 			if err != nil {
 				return nil, err
 			}
@@ -179,18 +204,18 @@ func (aq *AwardQueue) assignShipments(ctx context.Context) {
 }
 
 // getTSPsPerBand determines how many TSPs should be assigned to each Quality Band
-// If the number of TSPs in the TDL does not divide evenly into 4 bands, the remainder
+// If the number of TSPs in the TDL does not divide evenly into the bands, the remainder
 // is divided from the top band down.
 //
-// count is the number of TSPs to distribute.
-func getTSPsPerBand(count int) []int {
-	bands := make([]int, numQualBands)
-	base := int(math.Floor(float64(count) / float64(numQualBands)))
+// count is the number of TSPs to distribute, numBands is the number of quality bands.
+func getTSPsPerBand(count int, numBands int) []int {
+	bands := make([]int, numBands)
+	base := int(math.Floor(float64(count) / float64(numBands)))
 	for i := range bands {
 		bands[i] = base
 	}
 
-	for i := 0; i < count%numQualBands; i++ {
+	for i := 0; i < count%numBands; i++ {
 		bands[i]++
 	}
 	return bands
@@ -231,18 +256,27 @@ func (aq *AwardQueue) assignPerformanceBandsForTSPPerformanceGroup(ctx context.C
 		zap.String("rate_cycle_end", perfGroup.RateCycleEnd.String()),
 	)
 
-	perfs, err := models.FetchTSPPerformancesForQualityBandAssignment(aq.db, perfGroup, mps)
+	policy, err := models.FetchActiveAwardQueuePolicy(aq.db, perfGroup.TrafficDistributionListID, perfGroup.PerformancePeriodStart)
+	if err != nil {
+		return err
+	}
+	aq.logger.TraceInfo(ctx, "Using award queue policy",
+		zap.Int("award_queue_policy_version", policy.Version),
+		zap.Float64("minimum_performance_score", policy.MinimumPerformanceScore),
+		zap.Ints("offers_per_quality_band", policy.OffersPerQualityBand))
+
+	perfs, err := models.FetchTSPPerformancesForQualityBandAssignment(aq.db, perfGroup, policy.MinimumPerformanceScore)
 	if err != nil {
 		return err
 	}
 
 	perfsIndex := 0
-	bands := getTSPsPerBand(len(perfs))
+	bands := getTSPsPerBand(len(perfs), policy.NumQualityBands())
 	for band, count := range bands {
 		for i := 0; i < count; i++ {
 			performance := perfs[perfsIndex]
 			aq.logger.TraceInfo(ctx, "Assigning tspPerformance to band", zap.String("tsp_performance_id", performance.ID.String()), zap.Int("band", band+1))
-			err := models.AssignQualityBandToTSPPerformance(ctx, aq.db, band+1, performance.ID)
+			err := models.AssignQualityBandToTSPPerformance(ctx, aq.db, band+1, performance.ID, policy.StoredID())
 			if err != nil {
 				return err
 			}
@@ -305,14 +339,20 @@ func waitForLock(ctx context.Context, db *pop.Connection, id int) error {
 	return db.RawQuery("SELECT pg_advisory_xact_lock($1)", id).Exec()
 }
 
-// NewAwardQueue creates a new AwardQueue
-func NewAwardQueue(db *pop.Connection, logger Logger) *AwardQueue {
+// NewAwardQueue creates a new AwardQueue. TSP users are sent links to their awarded shipments on tspHost.
+func NewAwardQueue(db *pop.Connection, logger Logger, tspHost string) *AwardQueue {
 	return &AwardQueue{
-		db:     db,
-		logger: logger,
+		db:      db,
+		logger:  logger,
+		tspHost: tspHost,
 	}
 }
 
+// SetNotificationRoutes sends the given notification types through other channels instead of the outbox
+func (aq *AwardQueue) SetNotificationRoutes(routes map[notifications.NotificationType][]notifications.NotificationSender) {
+	aq.notificationRoutes = routes
+}
+
 // validateShipmentForAward ensures that a given shipment has all required
 // fields to be processed by the Award Queue.
 func validateShipmentForAward(shipment models.Shipment) error {
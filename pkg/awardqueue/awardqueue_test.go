@@ -2,7 +2,10 @@ package awardqueue
 
 import (
 	"context"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -15,16 +18,18 @@ import (
 
 	"github.com/go-openapi/swag"
 	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/pop/slices"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/transcom/mymove/pkg/logging/hnyzap"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
 	"github.com/transcom/mymove/pkg/testdatagen"
 )
 
 func (suite *AwardQueueSuite) Test_CheckAllTSPsBlackedOut() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 
 	tsp := testdatagen.MakeDefaultTSP(suite.DB())
 
@@ -85,7 +90,7 @@ func (suite *AwardQueueSuite) Test_CheckAllTSPsBlackedOut() {
 
 func (suite *AwardQueueSuite) Test_CheckShipmentDuringBlackOut() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 
 	tsp := testdatagen.MakeDefaultTSP(suite.DB())
 
@@ -190,7 +195,7 @@ func (suite *AwardQueueSuite) Test_CheckShipmentDuringBlackOut() {
 
 func (suite *AwardQueueSuite) Test_ShipmentWithinBlackoutDates() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 	// Creates a TSP with a blackout date connected to both.
 	testTSP1 := testdatagen.MakeDefaultTSP(suite.DB())
 
@@ -275,7 +280,7 @@ func (suite *AwardQueueSuite) Test_ShipmentWithinBlackoutDates() {
 
 func (suite *AwardQueueSuite) Test_FindAllUnassignedShipments() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 	_, err := queue.findAllUnassignedShipments()
 
 	if err != nil {
@@ -287,7 +292,7 @@ func (suite *AwardQueueSuite) Test_FindAllUnassignedShipments() {
 // it actually gets offered.
 func (suite *AwardQueueSuite) Test_OfferSingleShipment() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 
 	// Make a shipment
 	calendar := dates.NewUSCalendar()
@@ -343,11 +348,75 @@ func (suite *AwardQueueSuite) Test_OfferSingleShipment() {
 	suite.Equal(tspp.ID, offer.TransportationServiceProviderPerformanceID)
 }
 
+// Test that offering a shipment notifies the TSP's users, through the outbox unless the
+// notification is routed to another channel.
+func (suite *AwardQueueSuite) Test_OfferShipmentNotifiesTSPUsers() {
+	calendar := dates.NewUSCalendar()
+	market := testdatagen.DefaultMarket
+	sourceGBLOC := testdatagen.DefaultSrcGBLOC
+	pickupDate := dates.NextWorkday(*calendar, testdatagen.DateInsidePeakRateCycle)
+
+	makeShipment := func() models.Shipment {
+		return testdatagen.MakeShipment(suite.DB(), testdatagen.Assertions{
+			Shipment: models.Shipment{
+				RequestedPickupDate: &pickupDate,
+				ActualPickupDate:    &pickupDate,
+				ActualDeliveryDate:  &pickupDate,
+				SourceGBLOC:         &sourceGBLOC,
+				Market:              &market,
+				Status:              models.ShipmentStatusSUBMITTED,
+			},
+		})
+	}
+	shipment := makeShipment()
+
+	tspUser := testdatagen.MakeDefaultTspUser(suite.DB())
+	_, err := testdatagen.MakeTSPPerformance(suite.DB(), testdatagen.Assertions{
+		TransportationServiceProviderPerformance: models.TransportationServiceProviderPerformance{
+			TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+			TrafficDistributionListID:       *shipment.TrafficDistributionListID,
+			QualityBand:                     swag.Int(1),
+		},
+	})
+	suite.NoError(err)
+
+	// By default the email is queued in the outbox
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
+	_, err = queue.attemptShipmentOffer(context.Background(), shipment)
+	suite.NoError(err)
+
+	var deliveries models.NotificationDeliveries
+	err = suite.DB().Where("notification_type = ?", notifications.ShipmentAwardedNotification).All(&deliveries)
+	suite.NoError(err)
+	suite.Len(deliveries, 1)
+	suite.Equal(tspUser.Email, deliveries[0].RecipientEmail)
+
+	// A routed notification goes through its channel instead
+	dir, err := ioutil.TempDir("", "awardqueue")
+	suite.NoError(err)
+	defer os.RemoveAll(dir)
+
+	queue.SetNotificationRoutes(map[notifications.NotificationType][]notifications.NotificationSender{
+		notifications.ShipmentAwardedNotification: {notifications.NewFileNotificationSender(dir, "milmovelocal", suite.logger)},
+	})
+	shipment = makeShipment()
+	_, err = queue.attemptShipmentOffer(context.Background(), shipment)
+	suite.NoError(err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "shipment_awarded-*.eml"))
+	suite.NoError(err)
+	suite.Len(files, 1)
+
+	err = suite.DB().Where("notification_type = ?", notifications.ShipmentAwardedNotification).All(&deliveries)
+	suite.NoError(err)
+	suite.Len(deliveries, 1)
+}
+
 // Test that a shipment does NOT get offered because it is not in a TDL with
 // any enabled TSPs.
 func (suite *AwardQueueSuite) Test_FailOfferingSingleShipment() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 
 	// Make a shipment in a new TDL, which inherently has no TSPs
 	market := "dHHG"
@@ -405,7 +474,7 @@ func (suite *AwardQueueSuite) Test_FailOfferingSingleShipment() {
 
 func (suite *AwardQueueSuite) TestAssignShipmentsSingleTSP() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 
 	const shipmentsToMake = 10
 
@@ -476,7 +545,7 @@ func (suite *AwardQueueSuite) TestAssignShipmentsToMultipleTSPs() {
 
 	suite.DB().TruncateAll()
 
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 
 	const shipmentsToMake = 17
 
@@ -577,7 +646,7 @@ func (suite *AwardQueueSuite) Test_GetTSPsPerBandWithRemainder() {
 	t := suite.T()
 	// Check bands should expect differing num of TSPs when not divisible by 4
 	// Remaining TSPs should be divided among bands in descending order
-	tspPerBandList := getTSPsPerBand(10)
+	tspPerBandList := getTSPsPerBand(10, 4)
 	expectedBandList := []int{3, 3, 2, 2}
 	if !equalSlice(tspPerBandList, expectedBandList) {
 		t.Errorf("Failed to correctly divide TSP counts. Expected to find %d, found %d", expectedBandList, tspPerBandList)
@@ -587,16 +656,76 @@ func (suite *AwardQueueSuite) Test_GetTSPsPerBandWithRemainder() {
 func (suite *AwardQueueSuite) Test_GetTSPsPerBandNoRemainder() {
 	t := suite.T()
 	// Check bands should expect correct num of TSPs when num of TSPs is divisible by 4
-	tspPerBandList := getTSPsPerBand(8)
+	tspPerBandList := getTSPsPerBand(8, 4)
 	expectedBandList := []int{2, 2, 2, 2}
 	if !equalSlice(tspPerBandList, expectedBandList) {
 		t.Errorf("Failed to correctly divide TSP counts. Expected to find %d, found %d", expectedBandList, tspPerBandList)
 	}
 }
 
+func (suite *AwardQueueSuite) Test_GetTSPsPerBandFromPolicy() {
+	// Policies can split TSPs into fewer than 4 bands
+	suite.Equal([]int{3, 2, 2}, getTSPsPerBand(7, 3))
+	suite.Equal([]int{7}, getTSPsPerBand(7, 1))
+}
+
+func (suite *AwardQueueSuite) Test_OfferShipmentRecordsPolicy() {
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
+
+	calendar := dates.NewUSCalendar()
+	market := testdatagen.DefaultMarket
+	sourceGBLOC := testdatagen.DefaultSrcGBLOC
+	pickupDate := dates.NextWorkday(*calendar, testdatagen.DateInsidePeakRateCycle)
+
+	shipment := testdatagen.MakeShipment(suite.DB(), testdatagen.Assertions{
+		Shipment: models.Shipment{
+			RequestedPickupDate: &pickupDate,
+			ActualPickupDate:    &pickupDate,
+			ActualDeliveryDate:  &pickupDate,
+			SourceGBLOC:         &sourceGBLOC,
+			Market:              &market,
+			BookDate:            &testdatagen.DateInsidePerformancePeriod,
+			Status:              models.ShipmentStatusSUBMITTED,
+		},
+	})
+	tdl := *shipment.TrafficDistributionList
+
+	policy := testdatagen.MakeAwardQueuePolicy(suite.DB(), testdatagen.Assertions{
+		AwardQueuePolicy: models.AwardQueuePolicy{
+			TrafficDistributionListID: &tdl.ID,
+		},
+	})
+
+	tsp := testdatagen.MakeDefaultTSP(suite.DB())
+	_, err := testdatagen.MakeTSPPerformance(suite.DB(), testdatagen.Assertions{
+		TransportationServiceProviderPerformance: models.TransportationServiceProviderPerformance{
+			TransportationServiceProvider:   tsp,
+			TransportationServiceProviderID: tsp.ID,
+			TrafficDistributionListID:       tdl.ID,
+			QualityBand:                     swag.Int(1),
+			AwardQueuePolicyID:              &policy.ID,
+		},
+	})
+	suite.NoError(err)
+
+	// A newer policy that takes effect after the TSPs were banded doesn't change how the shipment is offered
+	testdatagen.MakeAwardQueuePolicy(suite.DB(), testdatagen.Assertions{
+		AwardQueuePolicy: models.AwardQueuePolicy{
+			TrafficDistributionListID: &tdl.ID,
+			OffersPerQualityBand:      slices.Int{1, 1},
+		},
+	})
+
+	offer, err := queue.attemptShipmentOffer(context.Background(), shipment)
+	suite.NoError(err)
+	if suite.NotNil(offer) && suite.NotNil(offer.AwardQueuePolicyID) {
+		suite.Equal(policy.ID, *offer.AwardQueuePolicyID)
+	}
+}
+
 func (suite *AwardQueueSuite) Test_AssignTSPsToBands() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 	tspsToMake := 5
 
 	tdl := testdatagen.MakeDefaultTDL(suite.DB())
@@ -604,7 +733,7 @@ func (suite *AwardQueueSuite) Test_AssignTSPsToBands() {
 	var lastTSPP models.TransportationServiceProviderPerformance
 	for i := 0; i < tspsToMake; i++ {
 		tsp := testdatagen.MakeDefaultTSP(suite.DB())
-		score := float64(mps + i + 1)
+		score := float64(i + 1)
 		rate := unit.NewDiscountRateFromPercent(45.3)
 
 		var err error
@@ -638,7 +767,7 @@ func (suite *AwardQueueSuite) Test_AssignTSPsToBands() {
 		RateCycleEnd:              lastTSPP.RateCycleEnd,
 	}
 
-	perfs, err := models.FetchTSPPerformancesForQualityBandAssignment(suite.DB(), perfGroup, mps)
+	perfs, err := models.FetchTSPPerformancesForQualityBandAssignment(suite.DB(), perfGroup, 0)
 	if err != nil {
 		t.Errorf("Failed to fetch TSPPerformances: %v", err)
 	}
@@ -660,7 +789,7 @@ func (suite *AwardQueueSuite) Test_AssignTSPsToBands() {
 // rate cycles get awarded shipments appropriately
 func (suite *AwardQueueSuite) Test_AwardTSPsInDifferentRateCycles() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.DB(), suite.logger)
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
 
 	sm := testdatagen.MakeDefaultServiceMember(suite.DB())
 	twoMonths, _ := time.ParseDuration("2 months")
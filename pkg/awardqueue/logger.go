@@ -8,6 +8,7 @@ import (
 
 // Logger is an interface that describes the logging requirements of this package.
 type Logger interface {
+	Debug(msg string, fields ...zap.Field)
 	Info(msg string, fields ...zap.Field)
 	Error(msg string, fields ...zap.Field)
 	TraceInfo(ctx context.Context, msg string, fields ...zap.Field)
@@ -0,0 +1,266 @@
+package awardqueue
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	beeline "github.com/honeycombio/beeline-go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// errRollbackSimulation is returned from the simulation transaction so that nothing it wrote is kept
+var errRollbackSimulation = errors.New("rolling back award queue simulation")
+
+// SimulatedOffer is an offer the award queue would make for a shipment
+type SimulatedOffer struct {
+	ShipmentID                      uuid.UUID `json:"shipment_id"`
+	TrafficDistributionListID       uuid.UUID `json:"traffic_distribution_list_id"`
+	TransportationServiceProviderID uuid.UUID `json:"transportation_service_provider_id"`
+	StandardCarrierAlphaCode        string    `json:"scac"`
+	QualityBand                     *int      `json:"quality_band"`
+	// SkippedForBlackout is true for the administrative offers made to TSPs with blackout dates on the pickup date
+	SkippedForBlackout bool `json:"skipped_for_blackout"`
+}
+
+// UnawardedShipment is a shipment the award queue would fail to offer to any TSP
+type UnawardedShipment struct {
+	ShipmentID uuid.UUID `json:"shipment_id"`
+	Error      string    `json:"error"`
+}
+
+// OfferDistribution totals the simulated offers for a TSP in a quality band of a TDL
+type OfferDistribution struct {
+	TrafficDistributionListID       uuid.UUID `json:"traffic_distribution_list_id"`
+	SourceRateArea                  string    `json:"source_rate_area"`
+	DestinationRegion               string    `json:"destination_region"`
+	CodeOfService                   string    `json:"code_of_service"`
+	TransportationServiceProviderID uuid.UUID `json:"transportation_service_provider_id"`
+	StandardCarrierAlphaCode        string    `json:"scac"`
+	QualityBand                     *int      `json:"quality_band"`
+	Offers                          int       `json:"offers"`
+	BlackoutSkips                   int       `json:"blackout_skips"`
+}
+
+// SimulationReport describes what a run of the award queue would do
+type SimulationReport struct {
+	Offers       []SimulatedOffer    `json:"offers"`
+	Unawarded    []UnawardedShipment `json:"unawarded"`
+	Distribution []OfferDistribution `json:"distribution"`
+}
+
+// WriteJSON writes the full report as JSON
+func (r SimulationReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the offer distribution as CSV, one row per TDL, quality band and TSP
+func (r SimulationReport) WriteCSV(w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	err := csvWriter.Write([]string{
+		"traffic_distribution_list_id",
+		"source_rate_area",
+		"destination_region",
+		"code_of_service",
+		"quality_band",
+		"transportation_service_provider_id",
+		"scac",
+		"offers",
+		"blackout_skips",
+	})
+	if err != nil {
+		return err
+	}
+	for _, d := range r.Distribution {
+		qualityBand := ""
+		if d.QualityBand != nil {
+			qualityBand = strconv.Itoa(*d.QualityBand)
+		}
+		err = csvWriter.Write([]string{
+			d.TrafficDistributionListID.String(),
+			d.SourceRateArea,
+			d.DestinationRegion,
+			d.CodeOfService,
+			qualityBand,
+			d.TransportationServiceProviderID.String(),
+			d.StandardCarrierAlphaCode,
+			strconv.Itoa(d.Offers),
+			strconv.Itoa(d.BlackoutSkips),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// Simulate runs the award queue inside a transaction that is always rolled back, and reports the offers it
+// would have made. The database is left unchanged.
+func (aq *AwardQueue) Simulate(ctx context.Context) (*SimulationReport, error) {
+	ctx, span := beeline.StartSpan(ctx, "awardqueue_simulation")
+	defer span.Send()
+
+	originalDB := aq.db
+	defer func() { aq.db = originalDB }()
+
+	report := &SimulationReport{}
+	err := aq.db.Transaction(func(tx *pop.Connection) error {
+		aq.db = tx
+
+		aq.logger.Info("Waiting to acquire advisory lock...")
+		if err := waitForLock(ctx, tx, awardQueueLockID); err != nil {
+			return err
+		}
+		aq.logger.Info("Acquired pg_advisory_xact_lock")
+
+		if err := aq.assignPerformanceBands(ctx); err != nil {
+			return err
+		}
+
+		shipments, err := aq.findAllUnassignedShipments()
+		if err != nil {
+			return err
+		}
+		for _, shipment := range shipments {
+			if _, err := aq.attemptShipmentOffer(ctx, shipment); err != nil {
+				report.Unawarded = append(report.Unawarded, UnawardedShipment{ShipmentID: shipment.ID, Error: err.Error()})
+				continue
+			}
+			offers, err := aq.simulatedOffersForShipment(shipment)
+			if err != nil {
+				return err
+			}
+			report.Offers = append(report.Offers, offers...)
+		}
+
+		if report.Distribution, err = aq.offerDistribution(report.Offers); err != nil {
+			return err
+		}
+		return errRollbackSimulation
+	})
+	if err != errRollbackSimulation {
+		return nil, err
+	}
+
+	aq.logger.Info("Simulated award queue run",
+		zap.Int("offers", len(report.Offers)),
+		zap.Int("shipments_unawarded", len(report.Unawarded)))
+	return report, nil
+}
+
+// simulatedOffersForShipment reads back the offers the award queue made for a shipment, including the
+// administrative offers made to TSPs with blackout dates
+func (aq *AwardQueue) simulatedOffersForShipment(shipment models.Shipment) ([]SimulatedOffer, error) {
+	var shipmentOffers models.ShipmentOffers
+	err := aq.db.Where("shipment_id = ?", shipment.ID).Order("created_at ASC").All(&shipmentOffers)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]SimulatedOffer, len(shipmentOffers))
+	for i, shipmentOffer := range shipmentOffers {
+		var tspp models.TransportationServiceProviderPerformance
+		if err := aq.db.Find(&tspp, shipmentOffer.TransportationServiceProviderPerformanceID); err != nil {
+			return nil, errors.Wrap(err, "Cannot find TSP performance for simulated offer")
+		}
+		var tsp models.TransportationServiceProvider
+		if err := aq.db.Find(&tsp, shipmentOffer.TransportationServiceProviderID); err != nil {
+			return nil, errors.Wrap(err, "Cannot find TSP for simulated offer")
+		}
+		offers[i] = SimulatedOffer{
+			ShipmentID:                      shipment.ID,
+			TrafficDistributionListID:       tspp.TrafficDistributionListID,
+			TransportationServiceProviderID: tsp.ID,
+			StandardCarrierAlphaCode:        tsp.StandardCarrierAlphaCode,
+			QualityBand:                     tspp.QualityBand,
+			SkippedForBlackout:              shipmentOffer.AdministrativeShipment,
+		}
+	}
+	return offers, nil
+}
+
+// offerDistribution totals simulated offers by TDL, quality band and TSP
+func (aq *AwardQueue) offerDistribution(offers []SimulatedOffer) ([]OfferDistribution, error) {
+	type distributionKey struct {
+		tdlID       uuid.UUID
+		qualityBand int
+		tspID       uuid.UUID
+	}
+	totals := map[distributionKey]*OfferDistribution{}
+	tdls := map[uuid.UUID]models.TrafficDistributionList{}
+
+	for _, offer := range offers {
+		key := distributionKey{tdlID: offer.TrafficDistributionListID, tspID: offer.TransportationServiceProviderID}
+		if offer.QualityBand != nil {
+			key.qualityBand = *offer.QualityBand
+		}
+
+		total, ok := totals[key]
+		if !ok {
+			tdl, ok := tdls[key.tdlID]
+			if !ok {
+				if err := aq.db.Find(&tdl, key.tdlID); err != nil {
+					return nil, errors.Wrap(err, "Cannot find TDL for simulated offer")
+				}
+				tdls[key.tdlID] = tdl
+			}
+			total = &OfferDistribution{
+				TrafficDistributionListID:       tdl.ID,
+				SourceRateArea:                  tdl.SourceRateArea,
+				DestinationRegion:               tdl.DestinationRegion,
+				CodeOfService:                   tdl.CodeOfService,
+				TransportationServiceProviderID: offer.TransportationServiceProviderID,
+				StandardCarrierAlphaCode:        offer.StandardCarrierAlphaCode,
+				QualityBand:                     offer.QualityBand,
+			}
+			totals[key] = total
+		}
+
+		if offer.SkippedForBlackout {
+			total.BlackoutSkips++
+		} else {
+			total.Offers++
+		}
+	}
+
+	distribution := make([]OfferDistribution, 0, len(totals))
+	for _, total := range totals {
+		distribution = append(distribution, *total)
+	}
+	sortOfferDistribution(distribution)
+	return distribution, nil
+}
+
+// sortOfferDistribution orders the distribution by TDL, then quality band, then SCAC
+func sortOfferDistribution(distribution []OfferDistribution) {
+	qualityBand := func(d OfferDistribution) int {
+		if d.QualityBand == nil {
+			return 0
+		}
+		return *d.QualityBand
+	}
+	tdlName := func(d OfferDistribution) string {
+		return d.SourceRateArea + "/" + d.DestinationRegion + "/" + d.CodeOfService
+	}
+	sort.Slice(distribution, func(i, j int) bool {
+		a, b := distribution[i], distribution[j]
+		if tdlName(a) != tdlName(b) {
+			return tdlName(a) < tdlName(b)
+		}
+		if qualityBand(a) != qualityBand(b) {
+			return qualityBand(a) < qualityBand(b)
+		}
+		return a.StandardCarrierAlphaCode < b.StandardCarrierAlphaCode
+	})
+}
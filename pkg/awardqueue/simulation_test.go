@@ -0,0 +1,118 @@
+package awardqueue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/dates"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *AwardQueueSuite) Test_SimulateReportsOffersWithoutSaving() {
+	queue := NewAwardQueue(suite.DB(), suite.logger, "tsplocal")
+
+	market := testdatagen.DefaultMarket
+	sourceGBLOC := testdatagen.DefaultSrcGBLOC
+	calendar := dates.NewUSCalendar()
+	blackoutStartDate := testdatagen.DateInsidePeakRateCycle
+	blackoutEndDate := dates.NextWorkday(*calendar, blackoutStartDate.AddDate(0, 1, 0))
+	pickupDate := dates.NextWorkday(*calendar, blackoutStartDate.AddDate(0, 0, 1))
+
+	shipment := testdatagen.MakeShipment(suite.DB(), testdatagen.Assertions{
+		Shipment: models.Shipment{
+			RequestedPickupDate: &pickupDate,
+			ActualPickupDate:    &pickupDate,
+			ActualDeliveryDate:  &pickupDate,
+			SourceGBLOC:         &sourceGBLOC,
+			Market:              &market,
+			BookDate:            &testdatagen.DateInsidePerformancePeriod,
+			Status:              models.ShipmentStatusSUBMITTED,
+		},
+	})
+	tdl := *shipment.TrafficDistributionList
+
+	blackoutTSP := testdatagen.MakeDefaultTSP(suite.DB())
+	availableTSP := testdatagen.MakeDefaultTSP(suite.DB())
+	for band, tsp := range []models.TransportationServiceProvider{blackoutTSP, availableTSP} {
+		_, err := testdatagen.MakeTSPPerformance(suite.DB(), testdatagen.Assertions{
+			TransportationServiceProviderPerformance: models.TransportationServiceProviderPerformance{
+				TransportationServiceProvider:   tsp,
+				TransportationServiceProviderID: tsp.ID,
+				TrafficDistributionListID:       tdl.ID,
+				QualityBand:                     swag.Int(band + 1),
+			},
+		})
+		suite.NoError(err)
+	}
+	testdatagen.MakeBlackoutDate(suite.DB(), testdatagen.Assertions{
+		BlackoutDate: models.BlackoutDate{
+			TransportationServiceProviderID: blackoutTSP.ID,
+			StartBlackoutDate:               blackoutStartDate,
+			EndBlackoutDate:                 blackoutEndDate,
+			TrafficDistributionListID:       &tdl.ID,
+			SourceGBLOC:                     &sourceGBLOC,
+			Market:                          &market,
+		},
+	})
+
+	report, err := queue.Simulate(context.Background())
+	suite.NoError(err)
+	suite.Empty(report.Unawarded)
+	if suite.Len(report.Offers, 2) {
+		suite.Equal(blackoutTSP.ID, report.Offers[0].TransportationServiceProviderID)
+		suite.True(report.Offers[0].SkippedForBlackout)
+		suite.Equal(availableTSP.ID, report.Offers[1].TransportationServiceProviderID)
+		suite.False(report.Offers[1].SkippedForBlackout)
+	}
+	if suite.Len(report.Distribution, 2) {
+		suite.Equal(1, *report.Distribution[0].QualityBand)
+		suite.Equal(1, report.Distribution[0].BlackoutSkips)
+		suite.Equal(2, *report.Distribution[1].QualityBand)
+		suite.Equal(1, report.Distribution[1].Offers)
+	}
+
+	// Nothing the simulation did was saved
+	count, err := suite.DB().Where("shipment_id = ?", shipment.ID).Count(&models.ShipmentOffer{})
+	suite.NoError(err)
+	suite.Equal(0, count)
+	suite.NoError(suite.DB().Find(&shipment, shipment.ID))
+	suite.Equal(models.ShipmentStatusSUBMITTED, shipment.Status)
+	suite.verifyOfferCount(availableTSP, 0)
+}
+
+func (suite *AwardQueueSuite) Test_SimulationReportWriters() {
+	tdlID := uuid.Must(uuid.NewV4())
+	tspID := uuid.Must(uuid.NewV4())
+	report := SimulationReport{
+		Distribution: []OfferDistribution{
+			{
+				TrafficDistributionListID:       tdlID,
+				SourceRateArea:                  "US87",
+				DestinationRegion:               "6",
+				CodeOfService:                   "D",
+				TransportationServiceProviderID: tspID,
+				StandardCarrierAlphaCode:        "ABCD",
+				QualityBand:                     swag.Int(1),
+				Offers:                          3,
+				BlackoutSkips:                   1,
+			},
+		},
+	}
+
+	var csvOutput bytes.Buffer
+	suite.NoError(report.WriteCSV(&csvOutput))
+	expected := "traffic_distribution_list_id,source_rate_area,destination_region,code_of_service,quality_band,transportation_service_provider_id,scac,offers,blackout_skips\n" +
+		tdlID.String() + ",US87,6,D,1," + tspID.String() + ",ABCD,3,1\n"
+	suite.Equal(expected, csvOutput.String())
+
+	var jsonOutput bytes.Buffer
+	suite.NoError(report.WriteJSON(&jsonOutput))
+	var decoded SimulationReport
+	suite.NoError(json.Unmarshal(jsonOutput.Bytes(), &decoded))
+	suite.Equal(report.Distribution, decoded.Distribution)
+}
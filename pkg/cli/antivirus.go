@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// AntivirusScannerFlag is the Antivirus Scanner Flag
+	AntivirusScannerFlag string = "antivirus-scanner"
+	// ClamdAddressFlag is the Clamd Address Flag
+	ClamdAddressFlag string = "clamd-address"
+	// ClamdTimeoutFlag is the Clamd Timeout Flag
+	ClamdTimeoutFlag string = "clamd-timeout"
+	// UploadScanIntervalFlag is the Upload Scan Interval Flag
+	UploadScanIntervalFlag string = "upload-scan-interval"
+)
+
+// InitAntivirusFlags initializes Antivirus command line flags
+func InitAntivirusFlags(flag *pflag.FlagSet) {
+	flag.String(AntivirusScannerFlag, "none", "Scanner used to check uploads for viruses, either none or clamd.")
+	flag.String(ClamdAddressFlag, "unix:///var/run/clamav/clamd.ctl", "Address of the ClamAV daemon, either unix:///path/to/socket or tcp://host:port")
+	flag.Duration(ClamdTimeoutFlag, time.Minute, "The longest a single file scan can take")
+	flag.Duration(UploadScanIntervalFlag, 5*time.Second, "How often to check for uploads waiting to be scanned")
+}
+
+// CheckAntivirus validates Antivirus command line flags
+func CheckAntivirus(v *viper.Viper) error {
+	antivirusScanner := v.GetString(AntivirusScannerFlag)
+	if !stringSliceContains([]string{"none", "clamd"}, antivirusScanner) {
+		return fmt.Errorf("invalid antivirus-scanner %s, expecting none or clamd", antivirusScanner)
+	}
+
+	if antivirusScanner == "clamd" {
+		clamdAddress := v.GetString(ClamdAddressFlag)
+		if !strings.HasPrefix(clamdAddress, "unix://") && !strings.HasPrefix(clamdAddress, "tcp://") {
+			return fmt.Errorf("invalid clamd-address %s, expecting unix:// or tcp://", clamdAddress)
+		}
+		if v.GetDuration(ClamdTimeoutFlag) <= 0 {
+			return errors.New("clamd-timeout must be greater than 0")
+		}
+	}
+
+	if v.GetDuration(UploadScanIntervalFlag) <= 0 {
+		return errors.New("upload-scan-interval must be greater than 0")
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package cli
+
+func (suite *cliTestSuite) TestConfigAntivirus() {
+	suite.Setup(InitAntivirusFlags, []string{})
+	suite.NoError(CheckAntivirus(suite.viper))
+
+	suite.viper.Set(AntivirusScannerFlag, "norton")
+	suite.Error(CheckAntivirus(suite.viper))
+
+	suite.viper.Set(AntivirusScannerFlag, "clamd")
+	suite.NoError(CheckAntivirus(suite.viper))
+
+	suite.viper.Set(ClamdAddressFlag, "localhost:3310")
+	suite.Error(CheckAntivirus(suite.viper))
+
+	suite.viper.Set(ClamdAddressFlag, "tcp://localhost:3310")
+	suite.NoError(CheckAntivirus(suite.viper))
+}
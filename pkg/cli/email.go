@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/ses"
 	"github.com/pkg/errors"
@@ -12,24 +13,39 @@ import (
 const (
 	// EmailBackendFlag is the Email Backend Flag
 	EmailBackendFlag string = "email-backend"
+	// EmailFileDirFlag is the Email File Directory Flag
+	EmailFileDirFlag string = "email-file-dir"
 	// AWSSESRegionFlag is the AWS SES Region Flag
 	AWSSESRegionFlag string = "aws-ses-region"
 	// AWSSESDomainFlag is the AWS SES Domain Flag
 	AWSSESDomainFlag string = "aws-ses-domain"
+	// NotificationPollIntervalFlag is the Notification Poll Interval Flag
+	NotificationPollIntervalFlag string = "notification-poll-interval"
+	// NotificationMaxAttemptsFlag is the Notification Max Attempts Flag
+	NotificationMaxAttemptsFlag string = "notification-max-attempts"
+	// NotificationInitialBackoffFlag is the Notification Initial Backoff Flag
+	NotificationInitialBackoffFlag string = "notification-initial-backoff"
+	// NotificationMaxBackoffFlag is the Notification Max Backoff Flag
+	NotificationMaxBackoffFlag string = "notification-max-backoff"
 )
 
 // InitEmailFlags initializes Email command line flags
 func InitEmailFlags(flag *pflag.FlagSet) {
-	flag.String(EmailBackendFlag, "local", "Email backend to use, either 'ses' or 'local'")
+	flag.String(EmailBackendFlag, "local", "Email backend to use, either 'ses', 'file' or 'local'")
+	flag.String(EmailFileDirFlag, "", "Directory the 'file' email backend writes emails to")
 	flag.String(AWSSESRegionFlag, "", "AWS region used for SES")
 	flag.String(AWSSESDomainFlag, "", "Domain used for SES")
+	flag.Duration(NotificationPollIntervalFlag, 30*time.Second, "How often the notification outbox is checked for emails to send")
+	flag.Int(NotificationMaxAttemptsFlag, 10, "Number of attempts to send an email before giving up on it")
+	flag.Duration(NotificationInitialBackoffFlag, time.Minute, "Wait before retrying an email the first time, doubled after each further failure")
+	flag.Duration(NotificationMaxBackoffFlag, 6*time.Hour, "Longest wait between attempts to send an email")
 }
 
 // CheckEmail validates Email command line flags
 func CheckEmail(v *viper.Viper) error {
 	emailBackend := v.GetString(EmailBackendFlag)
-	if !stringSliceContains([]string{"local", "ses"}, emailBackend) {
-		return fmt.Errorf("invalid email backend %s, expecting local or ses", emailBackend)
+	if !stringSliceContains([]string{"local", "ses", "file"}, emailBackend) {
+		return fmt.Errorf("invalid email backend %s, expecting local, ses or file", emailBackend)
 	}
 
 	if emailBackend == "ses" {
@@ -42,5 +58,20 @@ func CheckEmail(v *viper.Viper) error {
 		}
 	}
 
+	if emailBackend == "file" && len(v.GetString(EmailFileDirFlag)) == 0 {
+		return fmt.Errorf("%s is required for the file email backend", EmailFileDirFlag)
+	}
+
+	if v.GetDuration(NotificationPollIntervalFlag) <= 0 {
+		return fmt.Errorf("invalid %s %s, expecting a positive duration", NotificationPollIntervalFlag, v.GetDuration(NotificationPollIntervalFlag))
+	}
+	if v.GetInt(NotificationMaxAttemptsFlag) < 1 {
+		return fmt.Errorf("invalid %s %d, expecting at least 1", NotificationMaxAttemptsFlag, v.GetInt(NotificationMaxAttemptsFlag))
+	}
+	initialBackoff := v.GetDuration(NotificationInitialBackoffFlag)
+	if initialBackoff <= 0 || initialBackoff > v.GetDuration(NotificationMaxBackoffFlag) {
+		return fmt.Errorf("invalid %s %s, expecting a positive duration no longer than %s", NotificationInitialBackoffFlag, initialBackoff, NotificationMaxBackoffFlag)
+	}
+
 	return nil
 }
@@ -4,3 +4,22 @@ func (suite *cliTestSuite) TestConfigEmail() {
 	suite.Setup(InitEmailFlags, []string{})
 	suite.NoError(CheckEmail(suite.viper))
 }
+
+func (suite *cliTestSuite) TestConfigEmailFileBackend() {
+	suite.Setup(InitEmailFlags, []string{})
+	suite.viper.Set(EmailBackendFlag, "file")
+	suite.Error(CheckEmail(suite.viper))
+
+	suite.viper.Set(EmailFileDirFlag, "tmp/emails")
+	suite.NoError(CheckEmail(suite.viper))
+}
+
+func (suite *cliTestSuite) TestConfigEmailNotificationRetries() {
+	suite.Setup(InitEmailFlags, []string{})
+	suite.viper.Set(NotificationMaxAttemptsFlag, 0)
+	suite.Error(CheckEmail(suite.viper))
+
+	suite.viper.Set(NotificationMaxAttemptsFlag, 3)
+	suite.viper.Set(NotificationInitialBackoffFlag, "12h")
+	suite.Error(CheckEmail(suite.viper))
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// FuelPriceSourceFlag is the Fuel Price Source Flag
+	FuelPriceSourceFlag string = "fuel-price-source"
+	// FuelPriceCSVFileFlag is the Fuel Price CSV File Flag
+	FuelPriceCSVFileFlag string = "fuel-price-csv-file"
+	// FuelPriceAlertEmailFlag is the Fuel Price Alert Email Flag
+	FuelPriceAlertEmailFlag string = "fuel-price-alert-email"
+)
+
+// InitFuelPriceFlags initializes Fuel Price command line flags
+func InitFuelPriceFlags(flag *pflag.FlagSet) {
+	flag.String(FuelPriceSourceFlag, "eia", "Where to get diesel fuel prices from, either eia or csv")
+	flag.String(FuelPriceCSVFileFlag, "", "CSV file of weekly diesel fuel prices with pub_date and price_per_gallon columns, for the csv source")
+	flag.String(FuelPriceAlertEmailFlag, "", "Email address to alert when months are missing fuel prices")
+}
+
+// CheckFuelPrice validates Fuel Price command line flags
+func CheckFuelPrice(v *viper.Viper) error {
+	source := v.GetString(FuelPriceSourceFlag)
+	if !stringSliceContains([]string{"eia", "csv"}, source) {
+		return fmt.Errorf("invalid %s %s, expecting eia or csv", FuelPriceSourceFlag, source)
+	}
+
+	if source == "csv" && len(v.GetString(FuelPriceCSVFileFlag)) == 0 {
+		return fmt.Errorf("%s is required for the csv source", FuelPriceCSVFileFlag)
+	}
+	return nil
+}
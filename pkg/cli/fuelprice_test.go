@@ -0,0 +1,15 @@
+package cli
+
+func (suite *cliTestSuite) TestConfigFuelPrice() {
+	suite.Setup(InitFuelPriceFlags, []string{})
+	suite.NoError(CheckFuelPrice(suite.viper))
+
+	suite.Setup(InitFuelPriceFlags, []string{"--fuel-price-source", "csv", "--fuel-price-csv-file", "fuel_prices.csv"})
+	suite.NoError(CheckFuelPrice(suite.viper))
+
+	suite.Setup(InitFuelPriceFlags, []string{"--fuel-price-source", "csv"})
+	suite.Error(CheckFuelPrice(suite.viper))
+
+	suite.Setup(InitFuelPriceFlags, []string{"--fuel-price-source", "spreadsheet"})
+	suite.Error(CheckFuelPrice(suite.viper))
+}
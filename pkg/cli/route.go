@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -14,6 +16,10 @@ const (
 	HEREMapsAppIDFlag string = "here-maps-app-id"
 	// HEREMapsAppCodeFlag is the HERE Maps App Code Flag
 	HEREMapsAppCodeFlag string = "here-maps-app-code"
+	// RoutePlannerFlag is the Route Planner Flag
+	RoutePlannerFlag string = "route-planner"
+	// RouteRoadFactorFlag is the Route Road Factor Flag
+	RouteRoadFactorFlag string = "route-road-factor"
 )
 
 // InitRouteFlags initializes Route command line flags
@@ -22,6 +28,8 @@ func InitRouteFlags(flag *pflag.FlagSet) {
 	flag.String(HEREMapsRoutingEndpointFlag, "", "URL for the HERE maps routing endpoint")
 	flag.String(HEREMapsAppIDFlag, "", "HERE maps App ID for this application")
 	flag.String(HEREMapsAppCodeFlag, "", "HERE maps App API code")
+	flag.String(RoutePlannerFlag, "here", "Route planner to use, either here or composite. The composite planner caches HERE distances and falls back to a great-circle estimate.")
+	flag.Float64(RouteRoadFactorFlag, 1.2, "Multiplier applied to great-circle distances by the composite route planner to estimate road distances")
 }
 
 // CheckRoute validates Route command line flags
@@ -37,5 +45,13 @@ func CheckRoute(v *viper.Viper) error {
 			return err
 		}
 	}
+
+	if routePlanner := v.GetString(RoutePlannerFlag); !stringSliceContains([]string{"here", "composite"}, routePlanner) {
+		return fmt.Errorf("invalid %s %s, expecting here or composite", RoutePlannerFlag, routePlanner)
+	}
+
+	if roadFactor := v.GetFloat64(RouteRoadFactorFlag); roadFactor < 1 {
+		return fmt.Errorf("invalid %s %f, expecting a value of at least 1", RouteRoadFactorFlag, roadFactor)
+	}
 	return nil
 }
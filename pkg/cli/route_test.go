@@ -3,4 +3,13 @@ package cli
 func (suite *cliTestSuite) TestConfigRoute() {
 	suite.Setup(InitRouteFlags, []string{})
 	suite.NoError(CheckRoute(suite.viper))
+
+	suite.Setup(InitRouteFlags, []string{"--route-planner", "composite", "--route-road-factor", "1.3"})
+	suite.NoError(CheckRoute(suite.viper))
+
+	suite.Setup(InitRouteFlags, []string{"--route-planner", "bing"})
+	suite.Error(CheckRoute(suite.viper))
+
+	suite.Setup(InitRouteFlags, []string{"--route-road-factor", "0.5"})
+	suite.Error(CheckRoute(suite.viper))
 }
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/hex"
 	"fmt"
 	"path/filepath"
 
@@ -23,6 +24,14 @@ const (
 	AWSS3RegionFlag string = "aws-s3-region"
 	// AWSS3KeyNamespaceFlag is the AWS S3 Key Namespace Flag
 	AWSS3KeyNamespaceFlag string = "aws-s3-key-namespace"
+	// StorageEncryptionFlag is the Storage Encryption Flag
+	StorageEncryptionFlag string = "storage-encryption"
+	// StorageEncryptionKeyFileFlag is the Storage Encryption Key File Flag
+	StorageEncryptionKeyFileFlag string = "storage-encryption-key-file"
+	// StorageEncryptionKMSKeyIDFlag is the Storage Encryption KMS Key ID Flag
+	StorageEncryptionKMSKeyIDFlag string = "storage-encryption-kms-key-id"
+	// StorageURLSigningKeyFlag is the Storage URL Signing Key Flag
+	StorageURLSigningKeyFlag string = "storage-url-signing-key"
 )
 
 // InitStorageFlags initializes Storage command line flags
@@ -33,6 +42,10 @@ func InitStorageFlags(flag *pflag.FlagSet) {
 	flag.String(AWSS3BucketNameFlag, "", "S3 bucket used for file storage")
 	flag.String(AWSS3RegionFlag, "", "AWS region used for S3 file storage")
 	flag.String(AWSS3KeyNamespaceFlag, "", "Key prefix for all objects written to S3")
+	flag.String(StorageEncryptionFlag, "none", "Encryption for stored files, either none, local or kms.")
+	flag.String(StorageEncryptionKeyFileFlag, "", "File of hex-encoded 256-bit master keys used when storage-encryption is local. The first key encrypts new files.")
+	flag.String(StorageEncryptionKMSKeyIDFlag, "", "KMS key used when storage-encryption is kms")
+	flag.String(StorageURLSigningKeyFlag, "", "Hex-encoded secret used to sign URLs for encrypted files")
 }
 
 // CheckStorage validates Storage command line flags
@@ -55,5 +68,32 @@ func CheckStorage(v *viper.Viper) error {
 		}
 	}
 
+	storageEncryption := v.GetString(StorageEncryptionFlag)
+	if !stringSliceContains([]string{"none", "local", "kms"}, storageEncryption) {
+		return fmt.Errorf("invalid storage-encryption %s, expecting none, local or kms", storageEncryption)
+	}
+
+	if storageEncryption == "none" {
+		return nil
+	}
+
+	if storageEncryption == "local" {
+		if len(v.GetString(StorageEncryptionKeyFileFlag)) == 0 {
+			return fmt.Errorf("must provide %s when storage-encryption is local", StorageEncryptionKeyFileFlag)
+		}
+	} else if storageEncryption == "kms" {
+		if len(v.GetString(StorageEncryptionKMSKeyIDFlag)) == 0 {
+			return fmt.Errorf("must provide %s when storage-encryption is kms", StorageEncryptionKMSKeyIDFlag)
+		}
+	}
+
+	urlSigningKey, err := hex.DecodeString(v.GetString(StorageURLSigningKeyFlag))
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("%s is invalid", StorageURLSigningKeyFlag))
+	}
+	if len(urlSigningKey) < 32 {
+		return fmt.Errorf("%s must be at least 32 bytes when storage-encryption is %s", StorageURLSigningKeyFlag, storageEncryption)
+	}
+
 	return nil
 }
@@ -4,3 +4,24 @@ func (suite *cliTestSuite) TestConfigStorage() {
 	suite.Setup(InitStorageFlags, []string{})
 	suite.NoError(CheckStorage(suite.viper))
 }
+
+func (suite *cliTestSuite) TestConfigStorageEncryption() {
+	suite.Setup(InitStorageFlags, []string{})
+	suite.viper.Set(StorageEncryptionFlag, "rot13")
+	suite.Error(CheckStorage(suite.viper))
+
+	suite.viper.Set(StorageEncryptionFlag, "local")
+	suite.Error(CheckStorage(suite.viper))
+
+	suite.viper.Set(StorageEncryptionKeyFileFlag, "config/storage/devlocal-master.key")
+	suite.Error(CheckStorage(suite.viper))
+
+	suite.viper.Set(StorageURLSigningKeyFlag, "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	suite.NoError(CheckStorage(suite.viper))
+
+	suite.viper.Set(StorageEncryptionFlag, "kms")
+	suite.Error(CheckStorage(suite.viper))
+
+	suite.viper.Set(StorageEncryptionKMSKeyIDFlag, "alias/app-devlocal-storage")
+	suite.NoError(CheckStorage(suite.viper))
+}
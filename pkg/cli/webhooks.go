@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// WebhookPollIntervalFlag is the Webhook Poll Interval Flag
+	WebhookPollIntervalFlag string = "webhook-poll-interval"
+	// WebhookTimeoutFlag is the Webhook Timeout Flag
+	WebhookTimeoutFlag string = "webhook-timeout"
+	// WebhookMaxAttemptsFlag is the Webhook Max Attempts Flag
+	WebhookMaxAttemptsFlag string = "webhook-max-attempts"
+	// WebhookInitialBackoffFlag is the Webhook Initial Backoff Flag
+	WebhookInitialBackoffFlag string = "webhook-initial-backoff"
+	// WebhookMaxBackoffFlag is the Webhook Max Backoff Flag
+	WebhookMaxBackoffFlag string = "webhook-max-backoff"
+)
+
+// InitWebhookFlags initializes Webhook command line flags
+func InitWebhookFlags(flag *pflag.FlagSet) {
+	flag.Duration(WebhookPollIntervalFlag, 10*time.Second, "How often to check for webhook events to send to TSPs")
+	flag.Duration(WebhookTimeoutFlag, 10*time.Second, "The longest to wait for a TSP's endpoint to respond to a webhook")
+	flag.Int(WebhookMaxAttemptsFlag, 10, "Number of attempts to send a webhook event before giving up on it")
+	flag.Duration(WebhookInitialBackoffFlag, time.Minute, "Wait before retrying a webhook event the first time, doubled after each further failure")
+	flag.Duration(WebhookMaxBackoffFlag, 6*time.Hour, "Longest wait between attempts to send a webhook event")
+}
+
+// CheckWebhook validates Webhook command line flags
+func CheckWebhook(v *viper.Viper) error {
+	if v.GetDuration(WebhookPollIntervalFlag) <= 0 {
+		return fmt.Errorf("invalid %s %s, expecting a positive duration", WebhookPollIntervalFlag, v.GetDuration(WebhookPollIntervalFlag))
+	}
+	if v.GetDuration(WebhookTimeoutFlag) <= 0 {
+		return fmt.Errorf("invalid %s %s, expecting a positive duration", WebhookTimeoutFlag, v.GetDuration(WebhookTimeoutFlag))
+	}
+	if v.GetInt(WebhookMaxAttemptsFlag) < 1 {
+		return fmt.Errorf("invalid %s %d, expecting at least 1", WebhookMaxAttemptsFlag, v.GetInt(WebhookMaxAttemptsFlag))
+	}
+	initialBackoff := v.GetDuration(WebhookInitialBackoffFlag)
+	if initialBackoff <= 0 || initialBackoff > v.GetDuration(WebhookMaxBackoffFlag) {
+		return fmt.Errorf("invalid %s %s, expecting a positive duration no longer than %s", WebhookInitialBackoffFlag, initialBackoff, WebhookMaxBackoffFlag)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package cli
+
+func (suite *cliTestSuite) TestConfigWebhook() {
+	suite.Setup(InitWebhookFlags, []string{})
+	suite.NoError(CheckWebhook(suite.viper))
+
+	suite.viper.Set(WebhookMaxAttemptsFlag, 0)
+	suite.Error(CheckWebhook(suite.viper))
+
+	suite.viper.Set(WebhookMaxAttemptsFlag, 3)
+	suite.viper.Set(WebhookInitialBackoffFlag, "12h")
+	suite.Error(CheckWebhook(suite.viper))
+
+	suite.viper.Set(WebhookInitialBackoffFlag, "30s")
+	suite.viper.Set(WebhookTimeoutFlag, "0s")
+	suite.Error(CheckWebhook(suite.viper))
+}
@@ -0,0 +1,193 @@
+package ediinvoice
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	edisegment "github.com/transcom/mymove/pkg/edi/segment"
+)
+
+// ackCodeAccepted and ackCodeAcceptedWithErrors are the AK501/AK901 codes that mean the receiver accepted the
+// transaction set/functional group. Any other code (R, M, W, X, P) means at least part of it was rejected.
+const ackCodeAccepted = "A"
+const ackCodeAcceptedWithErrors = "E"
+
+// transactionSetSyntaxErrors maps AK502-AK506 codes to their X12 descriptions
+var transactionSetSyntaxErrors = map[string]string{
+	"1":  "Transaction Set Not Supported",
+	"2":  "Transaction Set Trailer Missing",
+	"3":  "Transaction Set Control Number in Header and Trailer Do Not Match",
+	"4":  "Number of Included Segments Does Not Match Actual Count",
+	"5":  "One or More Segments in Error",
+	"6":  "Missing or Invalid Transaction Set Identifier",
+	"7":  "Missing or Invalid Transaction Set Control Number",
+	"23": "Transaction Set Control Number Not Unique within the Functional Group",
+}
+
+// functionalGroupSyntaxErrors maps AK905-AK909 codes to their X12 descriptions
+var functionalGroupSyntaxErrors = map[string]string{
+	"1": "Functional Group Not Supported",
+	"2": "Functional Group Version Not Supported",
+	"3": "Functional Group Trailer Missing",
+	"4": "Group Control Number in the Functional Group Header and Trailer Do Not Agree",
+	"5": "Number of Included Transaction Sets Does Not Match Actual Count",
+	"6": "Group Control Number Violates Syntax",
+}
+
+// TransactionSetResponse holds the AK2/AK5 loop of a 997 for a single acknowledged transaction set
+type TransactionSetResponse struct {
+	AK2 edisegment.AK2
+	AK5 edisegment.AK5
+}
+
+// FunctionalAck997 holds all the segments of a parsed 997 functional acknowledgment
+type FunctionalAck997 struct {
+	ISA                     edisegment.ISA
+	GS                      edisegment.GS
+	ST                      edisegment.ST
+	AK1                     edisegment.AK1
+	TransactionSetResponses []TransactionSetResponse
+	AK9                     edisegment.AK9
+	SE                      edisegment.SE
+	GE                      edisegment.GE
+	IEA                     edisegment.IEA
+}
+
+// Accepted returns true if the acknowledged functional group and all of its transaction sets were accepted
+func (ack FunctionalAck997) Accepted() bool {
+	if !isAcceptedCode(ack.AK9.FunctionalGroupAcknowledgeCode) {
+		return false
+	}
+	for _, response := range ack.TransactionSetResponses {
+		if !isAcceptedCode(response.AK5.TransactionSetAcknowledgmentCode) {
+			return false
+		}
+	}
+	return true
+}
+
+// RejectionReasons returns human readable descriptions of every error code reported in the 997
+func (ack FunctionalAck997) RejectionReasons() []string {
+	var reasons []string
+	for _, response := range ack.TransactionSetResponses {
+		for _, code := range response.AK5.TransactionSetSyntaxErrorCodes {
+			reasons = append(reasons, fmt.Sprintf("Transaction set %s: %s",
+				response.AK2.TransactionSetControlNumber, describeCode(transactionSetSyntaxErrors, code)))
+		}
+	}
+	for _, code := range ack.AK9.FunctionalGroupSyntaxErrorCodes {
+		reasons = append(reasons, fmt.Sprintf("Functional group: %s", describeCode(functionalGroupSyntaxErrors, code)))
+	}
+	return reasons
+}
+
+func isAcceptedCode(code string) bool {
+	return code == ackCodeAccepted || code == ackCodeAcceptedWithErrors
+}
+
+func describeCode(descriptions map[string]string, code string) string {
+	if description, ok := descriptions[code]; ok {
+		return description
+	}
+	return fmt.Sprintf("Unknown error code %s", code)
+}
+
+// Parse997 reads an EDI X12 997 functional acknowledgment from the given reader
+func Parse997(r io.Reader) (FunctionalAck997, error) {
+	ack := FunctionalAck997{}
+
+	segments, err := readSegments(r)
+	if err != nil {
+		return ack, &ParseError{Err: errors.Wrap(err, "Failed to read 997")}
+	}
+
+	// The next segment we expect; the AK2 loop may repeat and may contain AK3/AK4 error details before its AK5
+	expected := "ISA"
+	segmentsInTransactionSet := 0
+	for i, record := range segments {
+		position := i + 1
+		id, elements := record[0], record[1:]
+		allowed := id == expected ||
+			(expected == "AK2" && id == "AK9") ||
+			(expected == "AK5" && (id == "AK3" || id == "AK4"))
+		if !allowed {
+			return ack, newParseError(position, id, "expected %s segment", expected)
+		}
+		if id != "ISA" && id != "GS" && id != "GE" && id != "IEA" {
+			segmentsInTransactionSet++
+		}
+
+		switch id {
+		case "ISA":
+			err = ack.ISA.Parse(elements)
+			expected = "GS"
+		case "GS":
+			err = ack.GS.Parse(elements)
+			expected = "ST"
+		case "ST":
+			err = ack.ST.Parse(elements)
+			expected = "AK1"
+		case "AK1":
+			err = ack.AK1.Parse(elements)
+			expected = "AK2"
+		case "AK2":
+			response := TransactionSetResponse{}
+			err = response.AK2.Parse(elements)
+			ack.TransactionSetResponses = append(ack.TransactionSetResponses, response)
+			expected = "AK5"
+		case "AK3", "AK4":
+			// We don't need the segment and element level error details, the AK5 codes summarize them
+		case "AK5":
+			err = ack.TransactionSetResponses[len(ack.TransactionSetResponses)-1].AK5.Parse(elements)
+			expected = "AK2"
+		case "AK9":
+			err = ack.AK9.Parse(elements)
+			expected = "SE"
+		case "SE":
+			err = ack.SE.Parse(elements)
+			expected = "GE"
+		case "GE":
+			err = ack.GE.Parse(elements)
+			expected = "IEA"
+		case "IEA":
+			err = ack.IEA.Parse(elements)
+			expected = "done"
+		}
+		if err != nil {
+			return ack, &ParseError{Position: position, SegmentID: id, Err: err}
+		}
+	}
+	if expected != "done" {
+		return ack, newParseError(0, "", "997 is incomplete, missing IEA segment")
+	}
+
+	return ack, validate997(ack, segmentsInTransactionSet)
+}
+
+func validate997(ack FunctionalAck997, segmentsInTransactionSet int) error {
+	if ack.GS.FunctionalIdentifierCode != "FA" {
+		return errors.Errorf("997 GS01 should be FA, got %s", ack.GS.FunctionalIdentifierCode)
+	}
+	if ack.ST.TransactionSetIdentifierCode != "997" {
+		return errors.Errorf("997 ST01 should be 997, got %s", ack.ST.TransactionSetIdentifierCode)
+	}
+	if ack.ISA.InterchangeControlNumber != ack.IEA.InterchangeControlNumber {
+		return errors.Errorf("997 ISA control number %d does not match IEA control number %d",
+			ack.ISA.InterchangeControlNumber, ack.IEA.InterchangeControlNumber)
+	}
+	if ack.GS.GroupControlNumber != ack.GE.GroupControlNumber {
+		return errors.Errorf("997 GS control number %d does not match GE control number %d",
+			ack.GS.GroupControlNumber, ack.GE.GroupControlNumber)
+	}
+	if ack.ST.TransactionSetControlNumber != ack.SE.TransactionSetControlNumber {
+		return errors.Errorf("997 ST control number %s does not match SE control number %s",
+			ack.ST.TransactionSetControlNumber, ack.SE.TransactionSetControlNumber)
+	}
+	if ack.SE.NumberOfIncludedSegments != segmentsInTransactionSet {
+		return errors.Errorf("997 SE segment count is %d, but the transaction set has %d segments",
+			ack.SE.NumberOfIncludedSegments, segmentsInTransactionSet)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package ediinvoice_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	ediinvoice "github.com/transcom/mymove/pkg/edi/invoice"
+)
+
+func (suite *InvoiceSuite) TestParse997() {
+	suite.T().Run("accepted 997", func(t *testing.T) {
+		file, err := os.Open("./testdata/997_accepted.edi")
+		suite.NoError(err)
+		defer file.Close()
+
+		ack, err := ediinvoice.Parse997(file)
+		suite.NoError(err)
+		suite.Equal(int64(995), ack.ISA.InterchangeControlNumber)
+		suite.Equal("SI", ack.AK1.FunctionalIdentifierCode)
+		suite.Equal(int64(100001251), ack.AK1.GroupControlNumber)
+		suite.Len(ack.TransactionSetResponses, 1)
+		suite.Equal("858", ack.TransactionSetResponses[0].AK2.TransactionSetIdentifierCode)
+		suite.Equal("A", ack.AK9.FunctionalGroupAcknowledgeCode)
+		suite.True(ack.Accepted())
+		suite.Empty(ack.RejectionReasons())
+	})
+
+	suite.T().Run("rejected 997 with segment terminators and AK3/AK4 details", func(t *testing.T) {
+		file, err := os.Open("./testdata/997_rejected.edi")
+		suite.NoError(err)
+		defer file.Close()
+
+		ack, err := ediinvoice.Parse997(file)
+		suite.NoError(err)
+		suite.Equal(int64(100001252), ack.AK1.GroupControlNumber)
+		suite.Equal("R", ack.TransactionSetResponses[0].AK5.TransactionSetAcknowledgmentCode)
+		suite.Equal([]string{"5"}, ack.TransactionSetResponses[0].AK5.TransactionSetSyntaxErrorCodes)
+		suite.False(ack.Accepted())
+		suite.Equal([]string{"Transaction set 0001: One or More Segments in Error"}, ack.RejectionReasons())
+	})
+
+	suite.T().Run("segment count mismatch", func(t *testing.T) {
+		file, err := os.Open("./testdata/997_bad_segment_count.edi")
+		suite.NoError(err)
+		defer file.Close()
+
+		_, err = ediinvoice.Parse997(file)
+		suite.Error(err)
+		suite.Contains(err.Error(), "SE segment count")
+	})
+
+	suite.T().Run("out of order segments", func(t *testing.T) {
+		edi := strings.Join([]string{
+			"ISA*00*0000000000*00*0000000000*12*8004171844     *ZZ*MYMOVE         *190724*1300*U*00401*000000995*0*T*|",
+			"GS*FA*8004171844*MYMOVE*20190724*1300*995*X*004010",
+			"ST*997*0001",
+			"AK2*858*0001",
+		}, "\n")
+
+		_, err := ediinvoice.Parse997(strings.NewReader(edi))
+		suite.Error(err)
+		suite.Equal("segment 4 (AK2): expected AK1 segment", err.Error())
+	})
+
+	suite.T().Run("missing trailer", func(t *testing.T) {
+		edi := strings.Join([]string{
+			"ISA*00*0000000000*00*0000000000*12*8004171844     *ZZ*MYMOVE         *190724*1300*U*00401*000000995*0*T*|",
+			"GS*FA*8004171844*MYMOVE*20190724*1300*995*X*004010",
+		}, "\n")
+
+		_, err := ediinvoice.Parse997(strings.NewReader(edi))
+		suite.Error(err)
+	})
+}
@@ -161,6 +161,11 @@ func (suite *InvoiceSuite) TestEDIString() {
 		}
 
 		suite.Equal(helperLoadExpectedEDI(suite, "expected_invoice.edi.golden"), actualEDIString)
+
+		// The generated invoice should parse back into the same segments
+		parsedTransactions, err := ediinvoice.ParseInvoice858C(strings.NewReader(actualEDIString))
+		suite.NoError(err, "Failed to parse generated invoice 858C")
+		suite.Equal(generatedTransactions.Segments(), parsedTransactions.Segments())
 	})
 }
 
@@ -0,0 +1,332 @@
+package ediinvoice
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/edi"
+	edisegment "github.com/transcom/mymove/pkg/edi/segment"
+)
+
+// ParseError describes a problem found while parsing an EDI file, along with where it was found
+type ParseError struct {
+	// Position is the 1-based index of the segment in the interchange, or 0 if the error isn't tied to a segment
+	Position  int
+	SegmentID string
+	Err       error
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	if e.Position == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("segment %d (%s): %s", e.Position, e.SegmentID, e.Err.Error())
+}
+
+// Cause returns the underlying error so errors.Cause works with ParseError
+func (e *ParseError) Cause() error {
+	return e.Err
+}
+
+func newParseError(position int, segmentID string, format string, args ...interface{}) *ParseError {
+	return &ParseError{Position: position, SegmentID: segmentID, Err: errors.Errorf(format, args...)}
+}
+
+// newSegmentFuncs creates an empty segment for each segment ID that can appear inside an 858C transaction set
+var newSegmentFuncs = map[string]func() edisegment.Segment{
+	"BX":  func() edisegment.Segment { return &edisegment.BX{} },
+	"N9":  func() edisegment.Segment { return &edisegment.N9{} },
+	"N1":  func() edisegment.Segment { return &edisegment.N1{} },
+	"N3":  func() edisegment.Segment { return &edisegment.N3{} },
+	"N4":  func() edisegment.Segment { return &edisegment.N4{} },
+	"FA1": func() edisegment.Segment { return &edisegment.FA1{} },
+	"FA2": func() edisegment.Segment { return &edisegment.FA2{} },
+	"L10": func() edisegment.Segment { return &edisegment.L10{} },
+	"HL":  func() edisegment.Segment { return &edisegment.HL{} },
+	"LX":  func() edisegment.Segment { return &edisegment.LX{} },
+	"L0":  func() edisegment.Segment { return &edisegment.L0{} },
+	"L1":  func() edisegment.Segment { return &edisegment.L1{} },
+	"MEA": func() edisegment.Segment { return &edisegment.MEA{} },
+	"NTE": func() edisegment.Segment { return &edisegment.NTE{} },
+}
+
+// headingSegmentIDs are the segments that may only appear in the heading, before the first HL loop
+var headingSegmentIDs = map[string]bool{
+	"BX":  true,
+	"N9":  true,
+	"N1":  true,
+	"N3":  true,
+	"N4":  true,
+	"FA1": true,
+	"FA2": true,
+	"L10": true,
+}
+
+// readSegments reads every segment from an EDI file, stripping the optional "~" segment terminator
+func readSegments(r io.Reader) ([][]string, error) {
+	ediReader := edi.NewReader(r)
+	// Segments have varying numbers of elements
+	ediReader.FieldsPerRecord = -1
+	ediReader.LazyQuotes = true
+	records, err := ediReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var segments [][]string
+	for _, record := range records {
+		if len(record) == 0 {
+			continue
+		}
+		// Only trim the ends of the segment, ISA elements are padded with significant spaces
+		record[0] = strings.TrimSpace(record[0])
+		last := len(record) - 1
+		record[last] = strings.TrimSuffix(strings.TrimRight(record[last], " \r\t"), "~")
+		if record[0] == "" {
+			continue
+		}
+		segments = append(segments, record)
+	}
+	return segments, nil
+}
+
+// ParseInvoice858C reads an EDI X12 858C interchange (ISA/GS/ST...SE/GE/IEA) into an Invoice858C, validating the
+// envelope control numbers, segment counts and loop structure
+func ParseInvoice858C(r io.Reader) (Invoice858C, error) {
+	invoice := Invoice858C{}
+
+	segments, err := readSegments(r)
+	if err != nil {
+		return invoice, &ParseError{Err: errors.Wrap(err, "Failed to read 858C")}
+	}
+	if len(segments) < 4 {
+		return invoice, newParseError(0, "", "858C is incomplete, it has only %d segments", len(segments))
+	}
+
+	// Envelope headers
+	if err = parseSegmentAt(segments, 0, "ISA", &invoice.ISA); err != nil {
+		return invoice, err
+	}
+	if err = parseSegmentAt(segments, 1, "GS", &invoice.GS); err != nil {
+		return invoice, err
+	}
+
+	// Envelope trailers
+	last := len(segments) - 1
+	if err = parseSegmentAt(segments, last, "IEA", &invoice.IEA); err != nil {
+		return invoice, err
+	}
+	if err = parseSegmentAt(segments, last-1, "GE", &invoice.GE); err != nil {
+		return invoice, err
+	}
+
+	// Transaction sets
+	numTransactionSets := 0
+	for i := 2; i < last-1; {
+		var transactionSet []edisegment.Segment
+		transactionSet, i, err = parseTransactionSet(segments, i, last-1)
+		if err != nil {
+			return invoice, err
+		}
+		invoice.Shipment = append(invoice.Shipment, transactionSet...)
+		numTransactionSets++
+	}
+	if numTransactionSets == 0 {
+		return invoice, newParseError(3, segments[2][0], "858C has no transaction sets")
+	}
+
+	if err = validateEnvelope(invoice, numTransactionSets, len(segments)); err != nil {
+		return invoice, err
+	}
+	return invoice, nil
+}
+
+// parseSegmentAt parses the segment at index into segment, making sure it has the expected segment ID
+func parseSegmentAt(segments [][]string, index int, id string, segment edisegment.Segment) error {
+	record := segments[index]
+	if record[0] != id {
+		return newParseError(index+1, record[0], "expected %s segment", id)
+	}
+	if err := segment.Parse(record[1:]); err != nil {
+		return &ParseError{Position: index + 1, SegmentID: id, Err: err}
+	}
+	return nil
+}
+
+// parseTransactionSet parses the ST...SE transaction set starting at start. It returns the parsed segments and
+// the index of the segment following the SE.
+func parseTransactionSet(segments [][]string, start int, end int) ([]edisegment.Segment, int, error) {
+	st := edisegment.ST{}
+	if err := parseSegmentAt(segments, start, "ST", &st); err != nil {
+		return nil, start, err
+	}
+	if st.TransactionSetIdentifierCode != "858" {
+		return nil, start, newParseError(start+1, "ST", "expected transaction set 858, got %s", st.TransactionSetIdentifierCode)
+	}
+	transactionSet := []edisegment.Segment{&st}
+	structure := newLoopValidator()
+
+	for i := start + 1; i < end; i++ {
+		id := segments[i][0]
+		position := i + 1
+
+		if id == "SE" {
+			se := edisegment.SE{}
+			if err := parseSegmentAt(segments, i, "SE", &se); err != nil {
+				return nil, i, err
+			}
+			if se.TransactionSetControlNumber != st.TransactionSetControlNumber {
+				return nil, i, newParseError(position, id, "control number %s does not match ST control number %s",
+					se.TransactionSetControlNumber, st.TransactionSetControlNumber)
+			}
+			// The count includes the ST and SE segments
+			if actual := len(transactionSet) + 1; se.NumberOfIncludedSegments != actual {
+				return nil, i, newParseError(position, id, "segment count is %d, but the transaction set has %d segments",
+					se.NumberOfIncludedSegments, actual)
+			}
+			if err := structure.finish(); err != nil {
+				return nil, i, &ParseError{Position: position, SegmentID: id, Err: err}
+			}
+			return append(transactionSet, &se), i + 1, nil
+		}
+
+		newSegment, ok := newSegmentFuncs[id]
+		if !ok {
+			return nil, i, newParseError(position, id, "unexpected segment in 858C transaction set")
+		}
+		segment := newSegment()
+		if err := segment.Parse(segments[i][1:]); err != nil {
+			return nil, i, &ParseError{Position: position, SegmentID: id, Err: err}
+		}
+		if err := structure.add(id, segment); err != nil {
+			return nil, i, &ParseError{Position: position, SegmentID: id, Err: err}
+		}
+		transactionSet = append(transactionSet, segment)
+	}
+
+	return nil, end, newParseError(start+1, "ST", "transaction set %s is missing its SE segment", st.TransactionSetControlNumber)
+}
+
+// loopValidator checks the order of segments within a transaction set: the heading (BX and its N9/N1/FA1 loops)
+// comes first, followed by HL loops that each hold LX/L0/L1 line item details
+type loopValidator struct {
+	previousID   string
+	seenBX       bool
+	inHL         bool
+	hlIDs        map[string]bool
+	hlHasL0      bool
+	hlHasL1      bool
+	nextLXNumber int
+}
+
+func newLoopValidator() *loopValidator {
+	return &loopValidator{hlIDs: map[string]bool{}, nextLXNumber: 1}
+}
+
+func (v *loopValidator) add(id string, segment edisegment.Segment) error {
+	defer func() { v.previousID = id }()
+
+	if !v.seenBX {
+		if id != "BX" {
+			return errors.New("expected BX segment to begin the transaction set heading")
+		}
+		v.seenBX = true
+		return nil
+	}
+
+	if headingSegmentIDs[id] {
+		if v.inHL {
+			return errors.Errorf("%s segment is only allowed in the heading, before the first HL loop", id)
+		}
+		switch id {
+		case "BX":
+			return errors.New("duplicate BX segment")
+		case "N3", "N4":
+			if v.previousID != "N1" && v.previousID != "N3" {
+				return errors.Errorf("%s segment must be part of an N1 loop", id)
+			}
+		case "FA2":
+			if v.previousID != "FA1" && v.previousID != "FA2" {
+				return errors.New("FA2 segment must follow an FA1 segment")
+			}
+		}
+		return nil
+	}
+
+	switch id {
+	case "HL":
+		if err := v.finishHL(); err != nil {
+			return err
+		}
+		hl := segment.(*edisegment.HL)
+		if hl.HierarchicalParentIDNumber != "" && !v.hlIDs[hl.HierarchicalParentIDNumber] {
+			return errors.Errorf("HL parent %s does not refer to a preceding HL segment", hl.HierarchicalParentIDNumber)
+		}
+		v.hlIDs[hl.HierarchicalIDNumber] = true
+		v.inHL = true
+		v.hlHasL0 = false
+		v.hlHasL1 = false
+	case "LX":
+		if !v.inHL {
+			return errors.New("LX segment must be inside an HL loop")
+		}
+		lx := segment.(*edisegment.LX)
+		if lx.AssignedNumber != v.nextLXNumber {
+			return errors.Errorf("LX assigned number is %d, expected %d", lx.AssignedNumber, v.nextLXNumber)
+		}
+		v.nextLXNumber++
+	case "L0":
+		if !v.inHL {
+			return errors.New("L0 segment must be inside an HL loop")
+		}
+		v.hlHasL0 = true
+	case "L1":
+		if !v.hlHasL0 {
+			return errors.New("L1 segment must follow an L0 segment in its HL loop")
+		}
+		v.hlHasL1 = true
+	}
+	return nil
+}
+
+func (v *loopValidator) finishHL() error {
+	if v.inHL && (!v.hlHasL0 || !v.hlHasL1) {
+		return errors.New("previous HL loop is missing its L0 or L1 segment")
+	}
+	return nil
+}
+
+func (v *loopValidator) finish() error {
+	if !v.seenBX {
+		return errors.New("transaction set is missing its BX segment")
+	}
+	return v.finishHL()
+}
+
+// validateEnvelope checks that the GS/GE and ISA/IEA trailers agree with their headers and contents
+func validateEnvelope(invoice Invoice858C, numTransactionSets int, numSegments int) error {
+	gePosition := numSegments - 1
+	ieaPosition := numSegments
+	if invoice.GE.GroupControlNumber != invoice.GS.GroupControlNumber {
+		return newParseError(gePosition, "GE", "group control number %d does not match GS group control number %d",
+			invoice.GE.GroupControlNumber, invoice.GS.GroupControlNumber)
+	}
+	if invoice.GE.NumberOfTransactionSetsIncluded != numTransactionSets {
+		return newParseError(gePosition, "GE", "number of transaction sets is %d, but the group has %d",
+			invoice.GE.NumberOfTransactionSetsIncluded, numTransactionSets)
+	}
+	if invoice.IEA.InterchangeControlNumber != invoice.ISA.InterchangeControlNumber {
+		return newParseError(ieaPosition, "IEA", "interchange control number %d does not match ISA control number %d",
+			invoice.IEA.InterchangeControlNumber, invoice.ISA.InterchangeControlNumber)
+	}
+	// Invoice858C only holds a single functional group
+	if invoice.IEA.NumberOfIncludedFunctionalGroups != 1 {
+		return newParseError(ieaPosition, "IEA", "number of functional groups is %d, expected 1",
+			invoice.IEA.NumberOfIncludedFunctionalGroups)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package ediinvoice_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	ediinvoice "github.com/transcom/mymove/pkg/edi/invoice"
+	edisegment "github.com/transcom/mymove/pkg/edi/segment"
+)
+
+func (suite *InvoiceSuite) TestParseInvoice858C() {
+	goldenEDI := helperLoadExpectedEDI(suite, "expected_invoice.edi.golden")
+
+	suite.T().Run("golden invoice round trips", func(t *testing.T) {
+		invoice, err := ediinvoice.ParseInvoice858C(strings.NewReader(goldenEDI))
+		suite.NoError(err)
+
+		suite.Equal(int64(2), invoice.ISA.InterchangeControlNumber)
+		suite.Equal("MYMOVE         ", invoice.ISA.InterchangeSenderID)
+		suite.Equal(int64(2), invoice.GS.GroupControlNumber)
+		if suite.Len(invoice.Shipment, 45) {
+			bx, ok := invoice.Shipment[1].(*edisegment.BX)
+			suite.True(ok)
+			suite.Equal("J", bx.TransactionMethodTypeCode)
+			suite.Equal("KKFA7000001", bx.ShipmentIdentificationNumber)
+		}
+
+		ediString, err := invoice.EDIString()
+		suite.NoError(err)
+		suite.Equal(goldenEDI, ediString)
+	})
+
+	var errorTestCases = []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{"SE segment count", "SE*45*0001", "SE*44*0001",
+			"segment 47 (SE): segment count is 44, but the transaction set has 45 segments"},
+		{"SE control number", "SE*45*0001", "SE*45*0002",
+			"segment 47 (SE): control number 0002 does not match ST control number 0001"},
+		{"GE control number", "GE*1*2", "GE*1*3",
+			"segment 48 (GE): group control number 3 does not match GS group control number 2"},
+		{"GE transaction set count", "GE*1*2", "GE*2*2",
+			"segment 48 (GE): number of transaction sets is 2, but the group has 1"},
+		{"IEA control number", "IEA*1*000000002", "IEA*1*000000003",
+			"segment 49 (IEA): interchange control number 3 does not match ISA control number 2"},
+		{"heading segment inside HL loop", "HL*303**SS\nL0*1*1.000*FR********\n", "HL*303**SS\nN9*DY*SC**\n",
+			"segment 18 (N9): N9 segment is only allowed in the heading, before the first HL loop"},
+		{"L1 without L0", "HL*304**SS\nL0*1*2000.000*CF********\n", "HL*304**SS\n",
+			"segment 39 (L1): L1 segment must follow an L0 segment in its HL loop"},
+		{"HL with unknown parent", "HL*303**SS", "HL*303*999*SS",
+			"segment 17 (HL): HL parent 999 does not refer to a preceding HL segment"},
+		{"unknown segment", "FA1*DZ", "ZZZ*DZ",
+			"segment 14 (ZZZ): unexpected segment in 858C transaction set"},
+	}
+
+	for _, testCase := range errorTestCases {
+		suite.T().Run(testCase.name, func(t *testing.T) {
+			edi := strings.Replace(goldenEDI, testCase.old, testCase.new, 1)
+
+			_, err := ediinvoice.ParseInvoice858C(strings.NewReader(edi))
+			if suite.Error(err) {
+				suite.Equal(testCase.expected, err.Error())
+				_, ok := err.(*ediinvoice.ParseError)
+				suite.True(ok)
+				suite.NotNil(errors.Cause(err))
+			}
+		})
+	}
+}
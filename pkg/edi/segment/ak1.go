@@ -0,0 +1,35 @@
+package edisegment
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AK1 represents the AK1 EDI segment
+type AK1 struct {
+	FunctionalIdentifierCode string
+	GroupControlNumber       int64
+}
+
+// StringArray converts AK1 to an array of strings
+func (s *AK1) StringArray() []string {
+	return []string{
+		"AK1",
+		s.FunctionalIdentifierCode,
+		strconv.FormatInt(s.GroupControlNumber, 10),
+	}
+}
+
+// Parse parses an X12 string that's split into an array into the AK1 struct
+func (s *AK1) Parse(elements []string) error {
+	numElements := len(elements)
+	// AK103 (version/release) is optional and we don't need it
+	if numElements != 2 && numElements != 3 {
+		return fmt.Errorf("AK1: Wrong number of elements, expected 2 or 3, got %d", numElements)
+	}
+
+	var err error
+	s.FunctionalIdentifierCode = elements[0]
+	s.GroupControlNumber, err = strconv.ParseInt(elements[1], 10, 64)
+	return err
+}
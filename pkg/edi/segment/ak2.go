@@ -0,0 +1,33 @@
+package edisegment
+
+import (
+	"fmt"
+)
+
+// AK2 represents the AK2 EDI segment
+type AK2 struct {
+	TransactionSetIdentifierCode string
+	TransactionSetControlNumber  string
+}
+
+// StringArray converts AK2 to an array of strings
+func (s *AK2) StringArray() []string {
+	return []string{
+		"AK2",
+		s.TransactionSetIdentifierCode,
+		s.TransactionSetControlNumber,
+	}
+}
+
+// Parse parses an X12 string that's split into an array into the AK2 struct
+func (s *AK2) Parse(elements []string) error {
+	numElements := len(elements)
+	// AK203 (implementation convention reference) is optional and we don't need it
+	if numElements != 2 && numElements != 3 {
+		return fmt.Errorf("AK2: Wrong number of elements, expected 2 or 3, got %d", numElements)
+	}
+
+	s.TransactionSetIdentifierCode = elements[0]
+	s.TransactionSetControlNumber = elements[1]
+	return nil
+}
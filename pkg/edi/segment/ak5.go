@@ -0,0 +1,37 @@
+package edisegment
+
+import (
+	"fmt"
+)
+
+// AK5 represents the AK5 EDI segment
+type AK5 struct {
+	TransactionSetAcknowledgmentCode string
+	TransactionSetSyntaxErrorCodes   []string
+}
+
+// StringArray converts AK5 to an array of strings
+func (s *AK5) StringArray() []string {
+	return append([]string{
+		"AK5",
+		s.TransactionSetAcknowledgmentCode,
+	}, s.TransactionSetSyntaxErrorCodes...)
+}
+
+// Parse parses an X12 string that's split into an array into the AK5 struct
+func (s *AK5) Parse(elements []string) error {
+	numElements := len(elements)
+	// AK502 through AK506 are optional syntax error codes
+	if numElements < 1 || numElements > 6 {
+		return fmt.Errorf("AK5: Wrong number of elements, expected 1 to 6, got %d", numElements)
+	}
+
+	s.TransactionSetAcknowledgmentCode = elements[0]
+	s.TransactionSetSyntaxErrorCodes = nil
+	for _, code := range elements[1:] {
+		if code != "" {
+			s.TransactionSetSyntaxErrorCodes = append(s.TransactionSetSyntaxErrorCodes, code)
+		}
+	}
+	return nil
+}
@@ -60,14 +60,15 @@ func (s *L0) Parse(parts []string) error {
 	if err != nil {
 		return err
 	}
-	s.BilledRatedAsQuantity, err = strconv.ParseFloat(parts[1], 64)
+	// Weight based line items leave the quantity empty and unit based line items leave the weight empty
+	s.BilledRatedAsQuantity, err = parseOptionalFloat(parts[1])
 	if err != nil {
 		return err
 	}
 	s.BilledRatedAsQualifier = parts[2]
 
 	if numElements == 11 {
-		s.Weight, err = strconv.ParseFloat(parts[3], 64)
+		s.Weight, err = parseOptionalFloat(parts[3])
 		if err != nil {
 			return err
 		}
@@ -29,3 +29,11 @@ func NxToFloat(s string, x int) (float64, error) {
 func FloatToNx(n float64, x int) string {
 	return strconv.FormatFloat(n*math.Pow10(x), 'f', 0, 64)
 }
+
+// parseOptionalFloat parses a float element that may be left empty, treating empty as 0
+func parseOptionalFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -9,12 +9,15 @@ import (
 	"github.com/transcom/mymove/pkg/gen/adminapi"
 	adminops "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations"
 	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/rateengine"
+	awardqueuepolicy "github.com/transcom/mymove/pkg/services/award_queue_policy"
 	"github.com/transcom/mymove/pkg/services/query"
+	"github.com/transcom/mymove/pkg/services/shipment"
 	"github.com/transcom/mymove/pkg/services/user"
 )
 
 // NewAdminAPIHandler returns a handler for the admin API
-func NewAdminAPIHandler(context handlers.HandlerContext) http.Handler {
+func NewAdminAPIHandler(context handlers.HandlerContext, logger Logger) http.Handler {
 
 	// Wire up the handlers to the publicAPIMux
 	adminSpec, err := loads.Analyzed(adminapi.SwaggerJSON, "")
@@ -28,8 +31,26 @@ func NewAdminAPIHandler(context handlers.HandlerContext) http.Handler {
 	adminAPI.OfficeIndexOfficeUsersHandler = IndexOfficeUsersHandler{
 		HandlerContext:        context,
 		NewQueryFilter:        query.NewQueryFilter,
+		NewQueryOrder:         query.NewQueryOrder,
+		NewPagination:         query.NewPagination,
 		OfficeUserListFetcher: user.NewOfficeUserListFetcher(queryBuilder),
 	}
 
+	adminAPI.AwardQueueIndexAwardQueuePoliciesHandler = IndexAwardQueuePoliciesHandler{
+		HandlerContext:              context,
+		NewQueryFilter:              query.NewQueryFilter,
+		AwardQueuePolicyListFetcher: awardqueuepolicy.NewAwardQueuePolicyListFetcher(queryBuilder),
+	}
+
+	adminAPI.AwardQueueCreateAwardQueuePolicyHandler = CreateAwardQueuePolicyHandler{
+		HandlerContext:          context,
+		AwardQueuePolicyCreator: awardqueuepolicy.NewAwardQueuePolicyCreator(context.DB()),
+	}
+
+	adminAPI.ShipmentsPriceShipmentWhatIfHandler = PriceShipmentWhatIfHandler{
+		HandlerContext:       context,
+		ShipmentWhatIfPricer: shipment.NewShipmentWhatIfPricer(context.DB(), rateengine.NewRateEngine(context.DB(), logger)),
+	}
+
 	return adminAPI.Serve(nil)
 }
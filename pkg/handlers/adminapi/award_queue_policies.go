@@ -0,0 +1,101 @@
+package adminapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gobuffalo/pop/slices"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	awardqueueop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/award_queue"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/services"
+)
+
+func payloadForAwardQueuePolicyModel(p models.AwardQueuePolicy) *adminmessages.AwardQueuePolicy {
+	offers := make([]int64, len(p.OffersPerQualityBand))
+	for i, o := range p.OffersPerQualityBand {
+		offers[i] = int64(o)
+	}
+	return &adminmessages.AwardQueuePolicy{
+		ID:                        handlers.FmtUUID(p.ID),
+		Version:                   swag.Int64(int64(p.Version)),
+		TrafficDistributionListID: handlers.FmtUUIDPtr(p.TrafficDistributionListID),
+		EffectiveStartDate:        handlers.FmtDate(p.EffectiveStartDate),
+		EffectiveEndDate:          handlers.FmtDate(p.EffectiveEndDate),
+		MinimumPerformanceScore:   swag.Float64(p.MinimumPerformanceScore),
+		OffersPerQualityBand:      offers,
+		CreatedAt:                 handlers.FmtDateTime(p.CreatedAt),
+	}
+}
+
+// IndexAwardQueuePoliciesHandler returns a list of award queue policies via GET /award_queue_policies
+type IndexAwardQueuePoliciesHandler struct {
+	handlers.HandlerContext
+	services.NewQueryFilter
+	services.AwardQueuePolicyListFetcher
+}
+
+// Handle retrieves a list of award queue policies
+func (h IndexAwardQueuePoliciesHandler) Handle(params awardqueueop.IndexAwardQueuePoliciesParams) middleware.Responder {
+	logger := h.LoggerFromRequest(params.HTTPRequest)
+
+	queryFilters := []services.QueryFilter{}
+	if params.TrafficDistributionListID != nil {
+		queryFilters = append(queryFilters, h.NewQueryFilter("traffic_distribution_list_id", "=", params.TrafficDistributionListID.String()))
+	}
+
+	policies, err := h.AwardQueuePolicyListFetcher.FetchAwardQueuePolicyList(queryFilters)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	payload := make(adminmessages.AwardQueuePolicies, len(policies))
+	for i, p := range policies {
+		payload[i] = payloadForAwardQueuePolicyModel(p)
+	}
+
+	return awardqueueop.NewIndexAwardQueuePoliciesOK().WithPayload(payload)
+}
+
+// CreateAwardQueuePolicyHandler creates a new award queue policy version via POST /award_queue_policies
+type CreateAwardQueuePolicyHandler struct {
+	handlers.HandlerContext
+	services.AwardQueuePolicyCreator
+}
+
+// Handle creates an award queue policy
+func (h CreateAwardQueuePolicyHandler) Handle(params awardqueueop.CreateAwardQueuePolicyParams) middleware.Responder {
+	logger := h.LoggerFromRequest(params.HTTPRequest)
+	payload := params.AwardQueuePolicy
+
+	offers := make(slices.Int, len(payload.OffersPerQualityBand))
+	for i, o := range payload.OffersPerQualityBand {
+		offers[i] = int(o)
+	}
+	policy := models.AwardQueuePolicy{
+		EffectiveStartDate:      time.Time(*payload.EffectiveStartDate),
+		EffectiveEndDate:        time.Time(*payload.EffectiveEndDate),
+		MinimumPerformanceScore: *payload.MinimumPerformanceScore,
+		OffersPerQualityBand:    offers,
+	}
+	if payload.TrafficDistributionListID != nil {
+		tdlID := uuid.FromStringOrNil(payload.TrafficDistributionListID.String())
+		policy.TrafficDistributionListID = &tdlID
+	}
+
+	verrs, err := h.AwardQueuePolicyCreator.CreateAwardQueuePolicy(&policy)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(logger, verrs, err)
+	}
+
+	logger.Info("Created award queue policy",
+		zap.String("award_queue_policy_id", policy.ID.String()),
+		zap.Int("award_queue_policy_version", policy.Version))
+
+	return awardqueueop.NewCreateAwardQueuePolicyCreated().WithPayload(payloadForAwardQueuePolicyModel(policy))
+}
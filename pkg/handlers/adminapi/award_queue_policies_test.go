@@ -0,0 +1,137 @@
+package adminapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gobuffalo/pop/slices"
+	"github.com/gobuffalo/validate"
+	"github.com/stretchr/testify/mock"
+
+	awardqueueop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/award_queue"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	awardqueuepolicy "github.com/transcom/mymove/pkg/services/award_queue_policy"
+	"github.com/transcom/mymove/pkg/services/mocks"
+	"github.com/transcom/mymove/pkg/services/query"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestIndexAwardQueuePoliciesHandler() {
+	tdl := testdatagen.MakeDefaultTDL(suite.DB())
+	testdatagen.MakeDefaultAwardQueuePolicy(suite.DB())
+	tdlPolicy := testdatagen.MakeAwardQueuePolicy(suite.DB(), testdatagen.Assertions{
+		AwardQueuePolicy: models.AwardQueuePolicy{
+			TrafficDistributionListID: &tdl.ID,
+		},
+	})
+
+	requestUser := testdatagen.MakeDefaultUser(suite.DB())
+	req := httptest.NewRequest("GET", "/award_queue_policies", nil)
+	req = suite.AuthenticateUserRequest(req, requestUser)
+
+	suite.T().Run("integration test ok response", func(t *testing.T) {
+		params := awardqueueop.IndexAwardQueuePoliciesParams{
+			HTTPRequest:               req,
+			TrafficDistributionListID: handlers.FmtUUID(tdl.ID),
+		}
+
+		handler := IndexAwardQueuePoliciesHandler{
+			HandlerContext:              handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
+			NewQueryFilter:              query.NewQueryFilter,
+			AwardQueuePolicyListFetcher: awardqueuepolicy.NewAwardQueuePolicyListFetcher(query.NewQueryBuilder(suite.DB())),
+		}
+
+		response := handler.Handle(params)
+
+		suite.IsType(&awardqueueop.IndexAwardQueuePoliciesOK{}, response)
+		okResponse := response.(*awardqueueop.IndexAwardQueuePoliciesOK)
+		suite.Len(okResponse.Payload, 1)
+		suite.Equal(tdlPolicy.ID.String(), okResponse.Payload[0].ID.String())
+		suite.Equal([]int64{5, 3, 2, 1}, okResponse.Payload[0].OffersPerQualityBand)
+	})
+
+	suite.T().Run("unsuccesful response when fetch fails", func(t *testing.T) {
+		params := awardqueueop.IndexAwardQueuePoliciesParams{
+			HTTPRequest: req,
+		}
+		expectedError := models.ErrFetchNotFound
+		fetcher := &mocks.AwardQueuePolicyListFetcher{}
+		fetcher.On("FetchAwardQueuePolicyList",
+			mock.Anything,
+		).Return(nil, expectedError).Once()
+		handler := IndexAwardQueuePoliciesHandler{
+			HandlerContext:              handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
+			NewQueryFilter:              query.NewQueryFilter,
+			AwardQueuePolicyListFetcher: fetcher,
+		}
+
+		response := handler.Handle(params)
+
+		expectedResponse := &handlers.ErrResponse{
+			Code: http.StatusNotFound,
+			Err:  expectedError,
+		}
+		suite.Equal(expectedResponse, response)
+	})
+}
+
+func (suite *HandlerSuite) TestCreateAwardQueuePolicyHandler() {
+	requestUser := testdatagen.MakeDefaultUser(suite.DB())
+	req := httptest.NewRequest("POST", "/award_queue_policies", nil)
+	req = suite.AuthenticateUserRequest(req, requestUser)
+
+	startDate := strfmt.Date(testdatagen.PerformancePeriodStart)
+	endDate := strfmt.Date(testdatagen.PerformancePeriodEnd)
+	payload := &adminmessages.CreateAwardQueuePolicyPayload{
+		EffectiveStartDate:      &startDate,
+		EffectiveEndDate:        &endDate,
+		MinimumPerformanceScore: swag.Float64(10),
+		OffersPerQualityBand:    []int64{5, 3, 2, 1},
+	}
+
+	suite.T().Run("integration test created response", func(t *testing.T) {
+		params := awardqueueop.CreateAwardQueuePolicyParams{
+			HTTPRequest:      req,
+			AwardQueuePolicy: payload,
+		}
+		handler := CreateAwardQueuePolicyHandler{
+			HandlerContext:          handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
+			AwardQueuePolicyCreator: awardqueuepolicy.NewAwardQueuePolicyCreator(suite.DB()),
+		}
+
+		response := handler.Handle(params)
+
+		suite.IsType(&awardqueueop.CreateAwardQueuePolicyCreated{}, response)
+		created := response.(*awardqueueop.CreateAwardQueuePolicyCreated)
+		suite.Equal(int64(1), *created.Payload.Version)
+		suite.Equal(10.0, *created.Payload.MinimumPerformanceScore)
+	})
+
+	suite.T().Run("validation errors", func(t *testing.T) {
+		params := awardqueueop.CreateAwardQueuePolicyParams{
+			HTTPRequest:      req,
+			AwardQueuePolicy: payload,
+		}
+		verrs := validate.NewErrors()
+		verrs.Add("offers_per_quality_band", "OffersPerQualityBand must have between 1 and 4 quality bands")
+		creator := &mocks.AwardQueuePolicyCreator{}
+		creator.On("CreateAwardQueuePolicy",
+			mock.MatchedBy(func(p *models.AwardQueuePolicy) bool {
+				return suite.Equal(slices.Int{5, 3, 2, 1}, p.OffersPerQualityBand)
+			}),
+		).Return(verrs, nil).Once()
+		handler := CreateAwardQueuePolicyHandler{
+			HandlerContext:          handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
+			AwardQueuePolicyCreator: creator,
+		}
+
+		response := handler.Handle(params)
+
+		suite.IsType(&handlers.ValidationErrorsResponse{}, response)
+	})
+}
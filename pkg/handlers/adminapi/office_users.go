@@ -23,16 +23,23 @@ func payloadForOfficeUserModel(o models.OfficeUser) *adminmessages.OfficeUser {
 type IndexOfficeUsersHandler struct {
 	handlers.HandlerContext
 	services.NewQueryFilter
+	services.NewQueryOrder
+	services.NewPagination
 	services.OfficeUserListFetcher
 }
 
-// Handle retrieves a list of office users
+// Handle retrieves a filtered, sorted page of office users
 func (h IndexOfficeUsersHandler) Handle(params officeuserop.IndexOfficeUsersParams) middleware.Responder {
 	logger := h.LoggerFromRequest(params.HTTPRequest)
-	// Here is where NewQueryFilter will be used to create Filters from the 'filter' query param
-	queryFilters := []services.QueryFilter{}
 
-	officeUsers, err := h.OfficeUserListFetcher.FetchOfficeUserList(queryFilters)
+	queryFilters, err := parseQueryFilters(h.NewQueryFilter, params.Filter)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+	queryOrders := parseQueryOrders(h.NewQueryOrder, params.Sort)
+	pagination := h.NewPagination(params.Page, params.PerPage)
+
+	officeUsers, count, err := h.OfficeUserListFetcher.FetchOfficeUserList(queryFilters, queryOrders, pagination)
 	if err != nil {
 		return handlers.ResponseForError(logger, err)
 	}
@@ -42,5 +49,9 @@ func (h IndexOfficeUsersHandler) Handle(params officeuserop.IndexOfficeUsersPara
 		payload[i] = payloadForOfficeUserModel(s)
 	}
 
-	return officeuserop.NewIndexOfficeUsersOK().WithPayload(payload)
+	return officeuserop.NewIndexOfficeUsersOK().
+		WithXTotalCount(int64(count)).
+		WithXPage(int64(pagination.Page())).
+		WithXPerPage(int64(pagination.PerPage())).
+		WithPayload(payload)
 }
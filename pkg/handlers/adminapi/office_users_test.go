@@ -24,6 +24,18 @@ func newMockQueryFilterBuilder(filter *mocks.QueryFilter) services.NewQueryFilte
 	}
 }
 
+func newMockQueryOrderBuilder(order *mocks.QueryOrder) services.NewQueryOrder {
+	return func(column string, descending bool) services.QueryOrder {
+		return order
+	}
+}
+
+func newMockPaginationBuilder(pagination *mocks.Pagination) services.NewPagination {
+	return func(page *int64, perPage *int64) services.Pagination {
+		return pagination
+	}
+}
+
 func (suite *HandlerSuite) TestIndexOfficeUsersHandler() {
 	// replace this with generated UUID when filter param is built out
 	uuidString := "d874d002-5582-4a91-97d3-786e8f66c763"
@@ -50,6 +62,8 @@ func (suite *HandlerSuite) TestIndexOfficeUsersHandler() {
 		handler := IndexOfficeUsersHandler{
 			HandlerContext:        handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
 			NewQueryFilter:        query.NewQueryFilter,
+			NewQueryOrder:         query.NewQueryOrder,
+			NewPagination:         query.NewPagination,
 			OfficeUserListFetcher: user.NewOfficeUserListFetcher(queryBuilder),
 		}
 
@@ -59,10 +73,67 @@ func (suite *HandlerSuite) TestIndexOfficeUsersHandler() {
 		okResponse := response.(*officeuserop.IndexOfficeUsersOK)
 		suite.Len(okResponse.Payload, 2)
 		suite.Equal(uuidString, okResponse.Payload[0].ID.String())
+		suite.Equal(int64(2), okResponse.XTotalCount)
+		suite.Equal(int64(1), okResponse.XPage)
+	})
+
+	suite.T().Run("integration test filters, sorts and pages", func(t *testing.T) {
+		perPage := int64(1)
+		params := officeuserop.IndexOfficeUsersParams{
+			HTTPRequest: req,
+			Filter:      []string{"id:in:" + uuidString + ",00000000-0000-0000-0000-000000000000", "middle_initials:isnull"},
+			Sort:        []string{"-last_name"},
+			PerPage:     &perPage,
+		}
+
+		queryBuilder := query.NewQueryBuilder(suite.DB())
+		handler := IndexOfficeUsersHandler{
+			HandlerContext:        handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
+			NewQueryFilter:        query.NewQueryFilter,
+			NewQueryOrder:         query.NewQueryOrder,
+			NewPagination:         query.NewPagination,
+			OfficeUserListFetcher: user.NewOfficeUserListFetcher(queryBuilder),
+		}
+
+		response := handler.Handle(params)
+
+		suite.IsType(&officeuserop.IndexOfficeUsersOK{}, response)
+		okResponse := response.(*officeuserop.IndexOfficeUsersOK)
+		suite.Len(okResponse.Payload, 1)
+		suite.Equal(uuidString, okResponse.Payload[0].ID.String())
+		suite.Equal(int64(1), okResponse.XTotalCount)
+		suite.Equal(int64(1), okResponse.XPerPage)
+	})
+
+	suite.T().Run("bad request for unknown filter column", func(t *testing.T) {
+		params := officeuserop.IndexOfficeUsersParams{
+			HTTPRequest: req,
+			Filter:      []string{"password:eq:hunter2"},
+		}
+
+		queryBuilder := query.NewQueryBuilder(suite.DB())
+		handler := IndexOfficeUsersHandler{
+			HandlerContext:        handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
+			NewQueryFilter:        query.NewQueryFilter,
+			NewQueryOrder:         query.NewQueryOrder,
+			NewPagination:         query.NewPagination,
+			OfficeUserListFetcher: user.NewOfficeUserListFetcher(queryBuilder),
+		}
+
+		response := handler.Handle(params)
+
+		suite.IsType(&handlers.ErrResponse{}, response)
+		suite.Equal(http.StatusBadRequest, response.(*handlers.ErrResponse).Code)
 	})
 
 	queryFilter := mocks.QueryFilter{}
 	newQueryFilter := newMockQueryFilterBuilder(&queryFilter)
+	queryOrder := mocks.QueryOrder{}
+	newQueryOrder := newMockQueryOrderBuilder(&queryOrder)
+	pagination := mocks.Pagination{}
+	pagination.On("Page").Return(1)
+	pagination.On("PerPage").Return(25)
+	newPagination := newMockPaginationBuilder(&pagination)
 
 	suite.T().Run("successful response", func(t *testing.T) {
 		officeUser := models.OfficeUser{ID: id}
@@ -72,10 +143,14 @@ func (suite *HandlerSuite) TestIndexOfficeUsersHandler() {
 		officeUserListFetcher := &mocks.OfficeUserListFetcher{}
 		officeUserListFetcher.On("FetchOfficeUserList",
 			mock.Anything,
-		).Return(models.OfficeUsers{officeUser}, nil).Once()
+			mock.Anything,
+			mock.Anything,
+		).Return(models.OfficeUsers{officeUser}, 1, nil).Once()
 		handler := IndexOfficeUsersHandler{
 			HandlerContext:        handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
 			NewQueryFilter:        newQueryFilter,
+			NewQueryOrder:         newQueryOrder,
+			NewPagination:         newPagination,
 			OfficeUserListFetcher: officeUserListFetcher,
 		}
 
@@ -95,10 +170,14 @@ func (suite *HandlerSuite) TestIndexOfficeUsersHandler() {
 		officeUserListFetcher := &mocks.OfficeUserListFetcher{}
 		officeUserListFetcher.On("FetchOfficeUserList",
 			mock.Anything,
-		).Return(nil, expectedError).Once()
+			mock.Anything,
+			mock.Anything,
+		).Return(nil, 0, expectedError).Once()
 		handler := IndexOfficeUsersHandler{
 			HandlerContext:        handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
 			NewQueryFilter:        newQueryFilter,
+			NewQueryOrder:         newQueryOrder,
+			NewPagination:         newPagination,
 			OfficeUserListFetcher: officeUserListFetcher,
 		}
 
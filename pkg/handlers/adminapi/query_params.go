@@ -0,0 +1,79 @@
+package adminapi
+
+import (
+	"strings"
+
+	"github.com/transcom/mymove/pkg/services"
+)
+
+// filterOperators maps the operators accepted in filter query params to query comparators
+var filterOperators = map[string]string{
+	"eq":     "=",
+	"neq":    "!=",
+	"lt":     "<",
+	"lte":    "<=",
+	"gt":     ">",
+	"gte":    ">=",
+	"in":     "IN",
+	"ilike":  "ILIKE",
+	"isnull": "IS NULL",
+}
+
+// parseQueryFilters parses filter query params of the form column:operator:value into query filters. Columns are
+// checked against the model by the query builder.
+func parseQueryFilters(newQueryFilter services.NewQueryFilter, rawFilters []string) ([]services.QueryFilter, error) {
+	queryFilters := make([]services.QueryFilter, 0, len(rawFilters))
+	invalidFilters := make([]string, 0)
+	for _, rawFilter := range rawFilters {
+		// Split into at most 3 parts so values can contain colons
+		parts := strings.SplitN(rawFilter, ":", 3)
+		if len(parts) < 2 {
+			invalidFilters = append(invalidFilters, rawFilter)
+			continue
+		}
+		column, operator := parts[0], strings.ToLower(parts[1])
+		comparator, ok := filterOperators[operator]
+		if !ok {
+			invalidFilters = append(invalidFilters, rawFilter)
+			continue
+		}
+
+		switch operator {
+		case "isnull":
+			if len(parts) == 3 {
+				invalidFilters = append(invalidFilters, rawFilter)
+				continue
+			}
+			queryFilters = append(queryFilters, newQueryFilter(column, comparator, nil))
+		case "in":
+			if len(parts) != 3 || parts[2] == "" {
+				invalidFilters = append(invalidFilters, rawFilter)
+				continue
+			}
+			queryFilters = append(queryFilters, newQueryFilter(column, comparator, strings.Split(parts[2], ",")))
+		default:
+			if len(parts) != 3 {
+				invalidFilters = append(invalidFilters, rawFilter)
+				continue
+			}
+			queryFilters = append(queryFilters, newQueryFilter(column, comparator, parts[2]))
+		}
+	}
+	if len(invalidFilters) != 0 {
+		return nil, services.InvalidInputError{InvalidFields: invalidFilters}
+	}
+	return queryFilters, nil
+}
+
+// parseQueryOrders parses sort query params into query orders. A leading - sorts the column in descending order.
+func parseQueryOrders(newQueryOrder services.NewQueryOrder, sort []string) []services.QueryOrder {
+	queryOrders := make([]services.QueryOrder, 0, len(sort))
+	for _, column := range sort {
+		if strings.HasPrefix(column, "-") {
+			queryOrders = append(queryOrders, newQueryOrder(strings.TrimPrefix(column, "-"), true))
+		} else {
+			queryOrders = append(queryOrders, newQueryOrder(column, false))
+		}
+	}
+	return queryOrders
+}
@@ -0,0 +1,49 @@
+package adminapi
+
+import (
+	"github.com/transcom/mymove/pkg/services"
+	"github.com/transcom/mymove/pkg/services/query"
+)
+
+func (suite *HandlerSuite) TestParseQueryFilters() {
+	filters, err := parseQueryFilters(query.NewQueryFilter, []string{
+		"last_name:ilike:smi%",
+		"email:eq:a:b@example.com",
+		"id:in:1,2,3",
+		"middle_initials:isnull",
+		"created_at:GTE:2019-07-01",
+	})
+	suite.NoError(err)
+	if suite.Len(filters, 5) {
+		suite.Equal("last_name", filters[0].Column())
+		suite.Equal("ILIKE", filters[0].Comparator())
+		suite.Equal("smi%", filters[0].Value())
+		// Values can contain colons
+		suite.Equal("a:b@example.com", filters[1].Value())
+		suite.Equal([]string{"1", "2", "3"}, filters[2].Value())
+		suite.Equal("IS NULL", filters[3].Comparator())
+		suite.Nil(filters[3].Value())
+		suite.Equal(">=", filters[4].Comparator())
+	}
+
+	_, err = parseQueryFilters(query.NewQueryFilter, []string{
+		"last_name",
+		"last_name:like:smi%",
+		"last_name:eq",
+		"id:in:",
+		"middle_initials:isnull:true",
+	})
+	suite.Error(err)
+	suite.IsType(services.InvalidInputError{}, err)
+	suite.Len(err.(services.InvalidInputError).InvalidFields, 5)
+}
+
+func (suite *HandlerSuite) TestParseQueryOrders() {
+	orders := parseQueryOrders(query.NewQueryOrder, []string{"-created_at", "last_name"})
+	if suite.Len(orders, 2) {
+		suite.Equal("created_at", orders[0].Column())
+		suite.True(orders[0].Descending())
+		suite.Equal("last_name", orders[1].Column())
+		suite.False(orders[1].Descending())
+	}
+}
@@ -0,0 +1,128 @@
+package adminapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	shipmentop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/shipments"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/services"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func pricingOverridesFromPayload(payload *adminmessages.ShipmentPricingOverrides) rateengine.PricingOverrides {
+	overrides := rateengine.PricingOverrides{}
+	if payload == nil {
+		return overrides
+	}
+	if payload.Date != nil {
+		date := time.Time(*payload.Date)
+		overrides.Date = &date
+	}
+	if payload.LinehaulDiscount != nil {
+		discount := unit.DiscountRate(*payload.LinehaulDiscount)
+		overrides.LinehaulDiscount = &discount
+	}
+	if payload.SitDiscount != nil {
+		discount := unit.DiscountRate(*payload.SitDiscount)
+		overrides.SITDiscount = &discount
+	}
+	if payload.FuelPricePerGallonMillicents != nil {
+		price := unit.Millicents(*payload.FuelPricePerGallonMillicents)
+		overrides.FuelPricePerGallon = &price
+	}
+	return overrides
+}
+
+func payloadForPricingOverrides(o rateengine.PricingOverrides) *adminmessages.ShipmentPricingOverrides {
+	payload := adminmessages.ShipmentPricingOverrides{
+		Date:                         handlers.FmtDatePtr(o.Date),
+		FuelPricePerGallonMillicents: handlers.FmtMilliCentsPtr(o.FuelPricePerGallon),
+	}
+	if o.LinehaulDiscount != nil {
+		payload.LinehaulDiscount = swag.Float64(o.LinehaulDiscount.Float64())
+	}
+	if o.SITDiscount != nil {
+		payload.SitDiscount = swag.Float64(o.SITDiscount.Float64())
+	}
+	return &payload
+}
+
+func payloadForPriceExplanationModel(e *models.PriceExplanation) *adminmessages.PriceExplanation {
+	if e == nil {
+		return nil
+	}
+	payload := adminmessages.PriceExplanation{
+		Description: handlers.FmtString(e.Description),
+		AmountCents: handlers.FmtCost(e.AmountCents),
+		Detail:      e.Detail,
+		Children:    make([]*adminmessages.PriceExplanation, len(e.Children)),
+	}
+	if e.Source != nil {
+		payload.Source = &adminmessages.PriceExplanationSource{
+			Table:       handlers.FmtString(e.Source.Table),
+			ID:          handlers.FmtUUID(e.Source.ID),
+			Description: e.Source.Description,
+		}
+	}
+	for i := range e.Children {
+		payload.Children[i] = payloadForPriceExplanationModel(&e.Children[i])
+	}
+	return &payload
+}
+
+func payloadForShipmentWhatIf(w services.ShipmentWhatIf) *adminmessages.ShipmentWhatIf {
+	lineItems := make([]*adminmessages.LineItemPriceComparison, len(w.LineItems))
+	for i, c := range w.LineItems {
+		lineItems[i] = &adminmessages.LineItemPriceComparison{
+			ShipmentLineItemID: handlers.FmtUUIDPtr(c.ShipmentLineItemID),
+			Code:               handlers.FmtString(c.Code),
+			Item:               handlers.FmtString(c.Item),
+			Location:           handlers.FmtString(string(c.Location)),
+			StoredAmountCents:  handlers.FmtCost(c.StoredAmountCents),
+			WhatIfAmountCents:  handlers.FmtCost(c.WhatIfAmountCents),
+			DifferenceCents:    handlers.FmtInt64(c.DifferenceCents().Int64()),
+			WhatIfExplanation:  payloadForPriceExplanationModel(c.WhatIfExplanation),
+		}
+	}
+	return &adminmessages.ShipmentWhatIf{
+		ShipmentID:       handlers.FmtUUID(w.ShipmentID),
+		Overrides:        payloadForPricingOverrides(w.Overrides),
+		LineItems:        lineItems,
+		StoredTotalCents: handlers.FmtInt64(w.StoredTotalCents.Int64()),
+		WhatIfTotalCents: handlers.FmtInt64(w.WhatIfTotalCents.Int64()),
+		DifferenceCents:  handlers.FmtInt64(w.DifferenceCents().Int64()),
+	}
+}
+
+// PriceShipmentWhatIfHandler prices a shipment against overridden inputs via POST /shipments/{shipmentId}/what_if
+type PriceShipmentWhatIfHandler struct {
+	handlers.HandlerContext
+	services.ShipmentWhatIfPricer
+}
+
+// Handle prices a shipment against overridden inputs, without saving anything
+func (h PriceShipmentWhatIfHandler) Handle(params shipmentop.PriceShipmentWhatIfParams) middleware.Responder {
+	logger := h.LoggerFromRequest(params.HTTPRequest)
+	shipmentID := uuid.FromStringOrNil(params.ShipmentID.String())
+	overrides := pricingOverridesFromPayload(params.Overrides)
+
+	whatIf, err := h.ShipmentWhatIfPricer.PriceShipmentWhatIf(shipmentID, overrides)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	logger.Info("Priced shipment what-if",
+		zap.String("shipment_id", shipmentID.String()),
+		zap.Int("stored_total_cents", whatIf.StoredTotalCents.Int()),
+		zap.Int("what_if_total_cents", whatIf.WhatIfTotalCents.Int()))
+
+	return shipmentop.NewPriceShipmentWhatIfOK().WithPayload(payloadForShipmentWhatIf(whatIf))
+}
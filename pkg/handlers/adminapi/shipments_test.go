@@ -0,0 +1,109 @@
+package adminapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+
+	shipmentop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/shipments"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/services"
+	"github.com/transcom/mymove/pkg/services/mocks"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *HandlerSuite) TestPriceShipmentWhatIfHandler() {
+	shipmentID := uuid.Must(uuid.NewV4())
+	requestUser := testdatagen.MakeDefaultUser(suite.DB())
+	req := httptest.NewRequest("POST", "/shipments/"+shipmentID.String()+"/what_if", nil)
+	req = suite.AuthenticateUserRequest(req, requestUser)
+
+	date := strfmt.Date(testdatagen.PerformancePeriodStart)
+	params := shipmentop.PriceShipmentWhatIfParams{
+		HTTPRequest: req,
+		ShipmentID:  strfmt.UUID(shipmentID.String()),
+		Overrides: &adminmessages.ShipmentPricingOverrides{
+			Date:             &date,
+			LinehaulDiscount: swag.Float64(0.5),
+		},
+	}
+
+	suite.T().Run("ok response with a comparison per line item", func(t *testing.T) {
+		lineItemID := uuid.Must(uuid.NewV4())
+		stored := unit.Cents(10000)
+		whatIf := unit.Cents(12500)
+		discount := unit.DiscountRate(0.5)
+		result := services.ShipmentWhatIf{
+			ShipmentID: shipmentID,
+			Overrides:  rateengine.PricingOverrides{LinehaulDiscount: &discount},
+			LineItems: []services.LineItemPriceComparison{
+				{
+					ShipmentLineItemID: &lineItemID,
+					Code:               "LHS",
+					Item:               "Linehaul Transportation",
+					Location:           models.ShipmentLineItemLocationNEITHER,
+					StoredAmountCents:  &stored,
+					WhatIfAmountCents:  &whatIf,
+					WhatIfExplanation:  &models.PriceExplanation{Description: "Linehaul", AmountCents: &whatIf},
+				},
+			},
+			StoredTotalCents: stored,
+			WhatIfTotalCents: whatIf,
+		}
+		pricer := &mocks.ShipmentWhatIfPricer{}
+		pricer.On("PriceShipmentWhatIf",
+			shipmentID,
+			mock.MatchedBy(func(o rateengine.PricingOverrides) bool {
+				return o.Date != nil && o.Date.Equal(testdatagen.PerformancePeriodStart) &&
+					o.LinehaulDiscount != nil && *o.LinehaulDiscount == discount &&
+					o.SITDiscount == nil && o.FuelPricePerGallon == nil
+			}),
+		).Return(result, nil).Once()
+		handler := PriceShipmentWhatIfHandler{
+			HandlerContext:       handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
+			ShipmentWhatIfPricer: pricer,
+		}
+
+		response := handler.Handle(params)
+
+		suite.IsType(&shipmentop.PriceShipmentWhatIfOK{}, response)
+		payload := response.(*shipmentop.PriceShipmentWhatIfOK).Payload
+		suite.Equal(int64(2500), *payload.DifferenceCents)
+		suite.Len(payload.LineItems, 1)
+		suite.Equal(lineItemID.String(), payload.LineItems[0].ShipmentLineItemID.String())
+		suite.Equal(int64(2500), *payload.LineItems[0].DifferenceCents)
+		suite.Equal("Linehaul", *payload.LineItems[0].WhatIfExplanation.Description)
+		suite.Equal(0.5, *payload.Overrides.LinehaulDiscount)
+		pricer.AssertExpectations(t)
+	})
+
+	suite.T().Run("not found response when shipment doesn't exist", func(t *testing.T) {
+		expectedError := models.ErrFetchNotFound
+		pricer := &mocks.ShipmentWhatIfPricer{}
+		pricer.On("PriceShipmentWhatIf",
+			shipmentID,
+			mock.Anything,
+		).Return(services.ShipmentWhatIf{}, expectedError).Once()
+		handler := PriceShipmentWhatIfHandler{
+			HandlerContext:       handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
+			ShipmentWhatIfPricer: pricer,
+		}
+
+		response := handler.Handle(params)
+
+		expectedResponse := &handlers.ErrResponse{
+			Code: http.StatusNotFound,
+			Err:  expectedError,
+		}
+		suite.Equal(expectedResponse, response)
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/services"
 	uploaderpkg "github.com/transcom/mymove/pkg/uploader"
 )
 
@@ -87,6 +88,9 @@ func ResponseForError(logger Logger, err error) middleware.Responder {
 	case *pq.Error:
 		skipLogger.Info(SQLErrMessage, zap.Error(e))
 		return newErrResponse(http.StatusInternalServerError, errors.New(SQLErrMessage))
+	case services.InvalidInputError:
+		skipLogger.Info("invalid query input", zap.Error(e))
+		return newErrResponse(http.StatusBadRequest, err)
 	default:
 		return responseForBaseError(skipLogger, err)
 	}
@@ -108,12 +112,24 @@ func responseForBaseError(logger Logger, err error) middleware.Responder {
 	case models.ErrWriteConflict:
 		skipLogger.Info("conflict", zap.Error(err))
 		return newErrResponse(http.StatusConflict, err)
+	case models.ErrStaleVersion:
+		skipLogger.Info("stale version", zap.Error(err))
+		return newErrResponse(http.StatusPreconditionFailed, err)
 	case models.ErrUserUnauthorized:
 		skipLogger.Info("unauthorized", zap.Error(err))
 		return newErrResponse(http.StatusUnauthorized, err)
 	case uploaderpkg.ErrZeroLengthFile:
 		skipLogger.Info("uploaded zero length file", zap.Error(err))
 		return newErrResponse(http.StatusBadRequest, err)
+	case uploaderpkg.ErrInvalidFileContent:
+		skipLogger.Info("uploaded invalid file", zap.Error(err))
+		return newErrResponse(http.StatusBadRequest, err)
+	case uploaderpkg.ErrNoThumbnail:
+		skipLogger.Info("upload has no thumbnail", zap.Error(err))
+		return newErrResponse(http.StatusNotFound, err)
+	case uploaderpkg.ErrUploadNotClean:
+		skipLogger.Info("upload has not passed its virus scan", zap.Error(err))
+		return newErrResponse(http.StatusConflict, err)
 	case models.ErrInvalidPatchGate:
 		skipLogger.Info("invalid patch gate", zap.Error(err))
 		return newErrResponse(http.StatusBadRequest, err)
@@ -123,6 +139,9 @@ func responseForBaseError(logger Logger, err error) middleware.Responder {
 	case models.ErrDestroyForbidden:
 		skipLogger.Info("invalid deletion", zap.Error(err))
 		return newErrResponse(http.StatusBadRequest, err)
+	case models.ErrInvalidQueryParams:
+		skipLogger.Info("invalid query params", zap.Error(err))
+		return newErrResponse(http.StatusBadRequest, err)
 	default:
 		skipLogger.Error("unexpected error", zap.Error(err))
 		return newErrResponse(http.StatusInternalServerError, err)
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// noVersion is a version that no record has
+const noVersion = -1
+
+// ETag returns the entity tag of a record at version, which clients send back in If-Match to update it
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// IfMatchVersions returns the versions of a record that a request's If-Match header expects, to pass to the
+// model's ExpectVersions. It returns nil, so that any version can be updated, if there is no header or it is "*".
+// The header may list several ETags, weak or strong. Tags that didn't come from ETag can't match the record, so a
+// header without any that did expects a version no record has.
+func IfMatchVersions(ifMatch *string) []int {
+	if ifMatch == nil {
+		return nil
+	}
+	header := strings.TrimSpace(*ifMatch)
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	var versions []int
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if !strings.HasPrefix(tag, `"`) {
+			continue
+		}
+		unquoted, err := strconv.Unquote(tag)
+		if err != nil {
+			continue
+		}
+		if v, err := strconv.Atoi(unquoted); err == nil && v >= 0 {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return []int{noVersion}
+	}
+	return versions
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIfMatchVersions(t *testing.T) {
+	var ifMatchTests = []struct {
+		ifMatch string
+		want    []int
+	}{
+		{`"0"`, []int{0}},
+		{` "12" `, []int{12}},
+		{`W/"12"`, []int{12}},
+		{`"12", "13"`, []int{12, 13}},
+		{`"12",W/"13" , "abc"`, []int{12, 13}},
+		{`12`, []int{noVersion}},
+		{`'1'`, []int{noVersion}},
+		{`"-1"`, []int{noVersion}},
+		{`"abc"`, []int{noVersion}},
+		{`"abc", W/"def"`, []int{noVersion}},
+	}
+	for _, tt := range ifMatchTests {
+		ifMatch := tt.ifMatch
+		if got := IfMatchVersions(&ifMatch); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.ifMatch, got, tt.want)
+		}
+	}
+
+	for _, ifMatch := range []string{"*", ""} {
+		if got := IfMatchVersions(&ifMatch); got != nil {
+			t.Errorf("%q: got %v, want nil", ifMatch, got)
+		}
+	}
+	if got := IfMatchVersions(nil); got != nil {
+		t.Errorf("no header: got %v, want nil", got)
+	}
+
+	if got := IfMatchVersions(FmtString(ETag(7))); !reflect.DeepEqual(got, []int{7}) {
+		t.Errorf("ETag round trip: got %v", got)
+	}
+}
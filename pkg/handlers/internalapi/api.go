@@ -73,6 +73,7 @@ func NewInternalAPIHandler(context handlers.HandlerContext) http.Handler {
 	internalAPI.DocumentsCreateDocumentHandler = CreateDocumentHandler{context}
 	internalAPI.DocumentsShowDocumentHandler = ShowDocumentHandler{context}
 	internalAPI.UploadsCreateUploadHandler = CreateUploadHandler{context}
+	internalAPI.UploadsShowUploadHandler = ShowUploadHandler{context}
 	internalAPI.UploadsDeleteUploadHandler = DeleteUploadHandler{context}
 	internalAPI.UploadsDeleteUploadsHandler = DeleteUploadsHandler{context}
 
@@ -88,6 +89,8 @@ func NewInternalAPIHandler(context handlers.HandlerContext) http.Handler {
 	internalAPI.OfficeApprovePPMHandler = ApprovePPMHandler{context}
 	internalAPI.OfficeApproveReimbursementHandler = ApproveReimbursementHandler{context}
 	internalAPI.OfficeCancelMoveHandler = CancelMoveHandler{context}
+	internalAPI.OfficeIndexMoveNotificationDeliveriesHandler = IndexMoveNotificationDeliveriesHandler{context}
+	internalAPI.OfficeIndexMoveTimelineHandler = IndexMoveTimelineHandler{context}
 
 	internalAPI.EntitlementsIndexEntitlementsHandler = IndexEntitlementsHandler{context}
 	internalAPI.EntitlementsValidateEntitlementHandler = ValidateEntitlementHandler{context}
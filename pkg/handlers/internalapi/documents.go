@@ -19,9 +19,13 @@ import (
 func payloadForDocumentModel(storer storage.FileStorer, document models.Document) (*internalmessages.DocumentPayload, error) {
 	uploads := make([]*internalmessages.UploadPayload, len(document.Uploads))
 	for i, upload := range document.Uploads {
-		url, err := storer.PresignedURL(upload.StorageKey, upload.ContentType)
-		if err != nil {
-			return nil, err
+		var url string
+		if upload.IsClean() {
+			var err error
+			url, err = storer.PresignedURL(upload.StorageKey, upload.ContentType)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		uploadPayload := payloadForUploadModel(upload, url)
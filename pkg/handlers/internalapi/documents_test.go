@@ -100,7 +100,35 @@ func (suite *HandlerSuite) TestShowDocumentHandler() {
 
 	uploadPayload := documentPayload.Uploads[0]
 	expectedURL := fmt.Sprintf("https://example.com/dir/%s?contentType=application/pdf&signed=test", upload.StorageKey)
-	if (*uploadPayload.URL).String() != expectedURL {
+	if uploadPayload.URL.String() != expectedURL {
 		t.Errorf("wrong URL for upload, expected %s, got %s", expectedURL, uploadPayload.URL)
 	}
 }
+
+func (suite *HandlerSuite) TestShowDocumentHandlerWithholdsQuarantinedUploads() {
+	upload := testdatagen.MakeUpload(suite.DB(), testdatagen.Assertions{
+		Upload: models.Upload{
+			Status: models.UploadStatusINFECTED,
+		},
+	})
+
+	var document models.Document
+	suite.NoError(suite.DB().Eager("ServiceMember.User").Find(&document, upload.DocumentID))
+
+	params := documentop.NewShowDocumentParams()
+	params.DocumentID = strfmt.UUID(upload.DocumentID.String())
+	params.HTTPRequest = suite.AuthenticateRequest(&http.Request{}, document.ServiceMember)
+
+	context := handlers.NewHandlerContext(suite.DB(), suite.TestLogger())
+	context.SetFileStorer(storageTest.NewFakeS3Storage(true))
+	handler := ShowDocumentHandler{context}
+	response := handler.Handle(params)
+
+	showResponse, ok := response.(*documentop.ShowDocumentOK)
+	suite.True(ok, "Request failed: %#v", response)
+	if suite.Len(showResponse.Payload.Uploads, 1) {
+		uploadPayload := showResponse.Payload.Uploads[0]
+		suite.Equal(string(models.UploadStatusINFECTED), *uploadPayload.Status)
+		suite.Empty(uploadPayload.URL)
+	}
+}
@@ -229,6 +229,7 @@ func (h UpdateMoveDocumentHandler) Handle(params movedocop.UpdateMoveDocumentPar
 			// (because the document has been toggled between OK and HAS_ISSUE and back)
 			// then don't complete it again.
 			if ppm.Status != models.PPMStatusCOMPLETED {
+				ppm.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 				completeErr := ppm.Complete()
 				if completeErr != nil {
 					return handlers.ResponseForError(logger, completeErr)
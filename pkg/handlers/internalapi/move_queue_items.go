@@ -2,7 +2,6 @@ package internalapi
 
 import (
 	"encoding/json"
-	"sort"
 	"strings"
 	"time"
 
@@ -75,20 +74,39 @@ type QueueSitData struct {
 	Location        string    `json:"location"`
 }
 
-// Implementation of a type and methods in order to use sort.Interface directly.
-// This allows us to call sortQueueItemsByLastModifiedDate in the ShowQueueHandler which will
-// sort the slice by the LastModfiedDate. Doing it this way allows us to avoid having reflect called
-// which should act to speed the sort up.
-type MoveQueueItems []models.MoveQueueItem
-
-func (mqi MoveQueueItems) Less(i, j int) bool {
-	return mqi[i].LastModifiedDate.Before(mqi[j].LastModifiedDate)
-}
-func (mqi MoveQueueItems) Len() int      { return len(mqi) }
-func (mqi MoveQueueItems) Swap(i, j int) { mqi[i], mqi[j] = mqi[j], mqi[i] }
-
-func sortQueueItemsByLastModifiedDate(moveQueueItems []models.MoveQueueItem) {
-	sort.Sort(MoveQueueItems(moveQueueItems))
+// moveQueueParams converts the query params for a queue into the filters, sorting and paging for its moves
+func moveQueueParams(params queueop.ShowQueueParams) models.MoveQueueParams {
+	queueParams := models.MoveQueueParams{
+		OriginGBLOCs: params.OriginGbloc,
+		Statuses:     params.Status,
+		PpmStatuses:  params.PpmStatus,
+		HhgStatuses:  params.HhgStatus,
+		Ranks:        params.Rank,
+		Search:       params.Search,
+	}
+	if params.MoveDateStart != nil {
+		moveDateFrom := time.Time(*params.MoveDateStart)
+		queueParams.MoveDateFrom = &moveDateFrom
+	}
+	if params.MoveDateEnd != nil {
+		moveDateTo := time.Time(*params.MoveDateEnd)
+		queueParams.MoveDateTo = &moveDateTo
+	}
+	for _, column := range params.Sort {
+		queueParams.Sort = append(queueParams.Sort, models.MoveQueueSort{
+			Column:     strings.TrimPrefix(column, "-"),
+			Descending: strings.HasPrefix(column, "-"),
+		})
+	}
+	if params.Page != nil {
+		page := int(*params.Page)
+		queueParams.Page = &page
+	}
+	if params.PerPage != nil {
+		perPage := int(*params.PerPage)
+		queueParams.PerPage = &perPage
+	}
+	return queueParams
 }
 
 // Handle retrieves a list of all MoveQueueItems in the system in the moves queue
@@ -101,15 +119,12 @@ func (h ShowQueueHandler) Handle(params queueop.ShowQueueParams) middleware.Resp
 
 	lifecycleState := params.QueueType
 
-	MoveQueueItems, err := models.GetMoveQueueItems(h.DB(), lifecycleState)
+	MoveQueueItems, totalCount, err := models.FetchMoveQueueItems(h.DB(), lifecycleState, moveQueueParams(params))
 	if err != nil {
 		logger.Error("Loading Queue", zap.String("State", lifecycleState), zap.Error(err))
 		return handlers.ResponseForError(logger, err)
 	}
 
-	// Sorting the slice by LastModifiedDate so that the API results follow suit.
-	sortQueueItemsByLastModifiedDate(MoveQueueItems)
-
 	MoveQueueItemPayloads := make([]*internalmessages.MoveQueueItem, len(MoveQueueItems))
 	for i, MoveQueueItem := range MoveQueueItems {
 		var sits []QueueSitData
@@ -166,5 +181,5 @@ func (h ShowQueueHandler) Handle(params queueop.ShowQueueParams) middleware.Resp
 		MoveQueueItemPayloads[i] = MoveQueueItemPayload
 
 	}
-	return queueop.NewShowQueueOK().WithPayload(MoveQueueItemPayloads)
+	return queueop.NewShowQueueOK().WithPayload(MoveQueueItemPayloads).WithXTotalCount(int64(totalCount))
 }
@@ -2,6 +2,7 @@ package internalapi
 
 import (
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"time"
 
@@ -351,3 +352,48 @@ func (suite *HandlerSuite) TestShowQueueActiveQueueSITHandler() {
 	suite.Equal(len(moveQueueItem.StorageInTransits), 2)
 
 }
+
+func (suite *HandlerSuite) TestShowQueueHandlerFiltersSortsAndPages() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.DB())
+	for _, lastName := range []string{"Adams", "Baker", "Clark"} {
+		testdatagen.MakePPM(suite.DB(), testdatagen.Assertions{
+			ServiceMember: models.ServiceMember{
+				LastName: models.StringPointer(lastName),
+			},
+			PersonallyProcuredMove: models.PersonallyProcuredMove{
+				Status: models.PPMStatusAPPROVED,
+			},
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/queues/ppm", nil)
+	req = suite.AuthenticateOfficeRequest(req, officeUser)
+
+	page := int64(1)
+	perPage := int64(2)
+	params := queueop.ShowQueueParams{
+		HTTPRequest: req,
+		QueueType:   "ppm",
+		PpmStatus:   []string{string(models.PPMStatusAPPROVED)},
+		Sort:        []string{"-customer_name"},
+		Page:        &page,
+		PerPage:     &perPage,
+	}
+
+	showHandler := ShowQueueHandler{handlers.NewHandlerContext(suite.DB(), suite.TestLogger())}
+	showResponse := showHandler.Handle(params)
+
+	suite.IsType(&queueop.ShowQueueOK{}, showResponse)
+	okResponse := showResponse.(*queueop.ShowQueueOK)
+	suite.Equal(int64(3), okResponse.XTotalCount)
+	if suite.Len(okResponse.Payload, 2) {
+		suite.Contains(*okResponse.Payload[0].CustomerName, "Clark")
+		suite.Contains(*okResponse.Payload[1].CustomerName, "Baker")
+	}
+
+	// Sorting on a column that isn't in the queue is a bad request
+	params.Sort = []string{"ssn"}
+	showResponse = showHandler.Handle(params)
+	suite.IsType(&handlers.ErrResponse{}, showResponse)
+	suite.Equal(http.StatusBadRequest, showResponse.(*handlers.ErrResponse).Code)
+}
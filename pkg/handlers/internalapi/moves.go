@@ -9,8 +9,10 @@ import (
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/swag"
+	"github.com/gobuffalo/pop"
 	"github.com/gofrs/uuid"
 	beeline "github.com/honeycombio/beeline-go"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/assets"
@@ -92,7 +94,7 @@ func (h ShowMoveHandler) Handle(params moveop.ShowMoveParams) middleware.Respond
 	if err != nil {
 		return handlers.ResponseForError(logger, err)
 	}
-	return moveop.NewShowMoveOK().WithPayload(movePayload)
+	return moveop.NewShowMoveOK().WithPayload(movePayload).WithETag(handlers.ETag(move.Version))
 }
 
 // PatchMoveHandler patches a move via PATCH /moves/{moveId}
@@ -124,7 +126,20 @@ func (h PatchMoveHandler) Handle(params moveop.PatchMoveParams) middleware.Respo
 		move.SelectedMoveType = &stringSelectedMoveType
 	}
 
+	move.ExpectVersions(handlers.IfMatchVersions(params.IfMatch))
 	verrs, err := h.DB().ValidateAndUpdate(move)
+	if errors.Cause(err) == models.ErrStaleVersion {
+		logger.Info("Move has changed since the version in If-Match", zap.Error(err))
+		current, err := models.FetchMove(h.DB(), session, moveID)
+		if err != nil {
+			return handlers.ResponseForError(logger, err)
+		}
+		currentPayload, err := payloadForMoveModel(h.FileStorer(), orders, *current)
+		if err != nil {
+			return handlers.ResponseForError(logger, err)
+		}
+		return moveop.NewPatchMovePreconditionFailed().WithPayload(currentPayload).WithETag(handlers.ETag(current.Version))
+	}
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
@@ -132,7 +147,7 @@ func (h PatchMoveHandler) Handle(params moveop.PatchMoveParams) middleware.Respo
 	if err != nil {
 		return handlers.ResponseForError(logger, err)
 	}
-	return moveop.NewPatchMoveCreated().WithPayload(movePayload)
+	return moveop.NewPatchMoveCreated().WithPayload(movePayload).WithETag(handlers.ETag(move.Version))
 }
 
 // SubmitMoveHandler approves a move via POST /moves/{moveId}/submit
@@ -157,6 +172,7 @@ func (h SubmitMoveHandler) Handle(params moveop.SubmitMoveForApprovalParams) mid
 	}
 
 	submitDate := time.Time(*params.SubmitMoveForApprovalPayload.PpmSubmitDate)
+	move.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	err = move.Submit(submitDate)
 	span.AddField("move-status", string(move.Status))
 	if err != nil {
@@ -166,23 +182,16 @@ func (h SubmitMoveHandler) Handle(params moveop.SubmitMoveForApprovalParams) mid
 		return handlers.ResponseForError(logger, err)
 	}
 
-	// Transaction to save move and dependencies
-	verrs, err := models.SaveMoveDependencies(h.DB(), move)
+	// Transaction to save move and dependencies, and queue the email to the service member
+	verrs, err := models.SaveMoveDependenciesInTransaction(h.DB(), move, func(tx *pop.Connection) error {
+		return notifications.EnqueueNotification(ctx, tx, notifications.NewMoveSubmitted(tx, logger, session, moveID))
+	})
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
 
-	err = h.NotificationSender().SendNotification(
-		ctx,
-		notifications.NewMoveSubmitted(h.DB(), logger, session, moveID),
-	)
-	if err != nil {
-		logger.Error("problem sending email to user", zap.Error(err))
-		return handlers.ResponseForError(logger, err)
-	}
-
 	if len(move.Shipments) > 0 {
-		go awardqueue.NewAwardQueue(h.DB(), h.HoneyZapLogger()).Run(ctx)
+		go awardqueue.NewAwardQueue(h.DB(), h.HoneyZapLogger(), h.AppNames().TspServername).Run(ctx)
 	}
 
 	movePayload, err := payloadForMoveModel(h.FileStorer(), move.Orders, *move)
@@ -253,20 +262,26 @@ func (h ShowShipmentSummaryWorksheetHandler) Handle(params moveop.ShowShipmentSu
 		return handlers.ResponseForError(logger, err)
 	}
 
-	page1Data, page2Data, err := models.FormatValuesShipmentSummaryWorksheet(ssfd)
+	page1Data, page2Data, continuationData, err := models.FormatValuesShipmentSummaryWorksheet(ssfd)
 
 	if err != nil {
 		return handlers.ResponseForError(logger, err)
 	}
 
-	formFiller := paperwork.NewFormFiller()
-
 	// page 1
-	page1Layout := paperwork.ShipmentSummaryPage1Layout
-	page1Template, err := assets.Asset(page1Layout.TemplateImagePath)
+	page1Layout, err := paperwork.LoadFormLayoutAsset(paperwork.ShipmentSummaryPage1LayoutName)
+	if err != nil {
+		logger.Error("Error loading form layout", zap.Error(err))
+		return moveop.NewShowShipmentSummaryWorksheetInternalServerError()
+	}
+
+	// The worksheet's pages are all images or all fillable PDFs, so page 1 chooses the filler
+	formFiller := paperwork.NewFillerForLayout(page1Layout)
+
+	page1Template, err := assets.Asset(page1Layout.TemplatePath())
 
 	if err != nil {
-		logger.Error("Error reading template file", zap.String("asset", page1Layout.TemplateImagePath), zap.Error(err))
+		logger.Error("Error reading template file", zap.String("asset", page1Layout.TemplatePath()), zap.Error(err))
 		return moveop.NewShowShipmentSummaryWorksheetInternalServerError()
 	}
 
@@ -278,11 +293,15 @@ func (h ShowShipmentSummaryWorksheetHandler) Handle(params moveop.ShowShipmentSu
 	}
 
 	// page 2
-	page2Layout := paperwork.ShipmentSummaryPage2Layout
-	page2Template, err := assets.Asset(page2Layout.TemplateImagePath)
+	page2Layout, err := paperwork.LoadFormLayoutAsset(paperwork.ShipmentSummaryPage2LayoutName)
+	if err != nil {
+		logger.Error("Error loading form layout", zap.Error(err))
+		return moveop.NewShowShipmentSummaryWorksheetInternalServerError()
+	}
+	page2Template, err := assets.Asset(page2Layout.TemplatePath())
 
 	if err != nil {
-		logger.Error("Error reading template file", zap.String("asset", page2Layout.TemplateImagePath), zap.Error(err))
+		logger.Error("Error reading template file", zap.String("asset", page2Layout.TemplatePath()), zap.Error(err))
 		return moveop.NewShowShipmentSummaryWorksheetInternalServerError()
 	}
 
@@ -293,6 +312,28 @@ func (h ShowShipmentSummaryWorksheetHandler) Handle(params moveop.ShowShipmentSu
 		return moveop.NewShowShipmentSummaryWorksheetInternalServerError()
 	}
 
+	// continuation pages listing the shipments that don't fit on page 1
+	continuationLayout, err := paperwork.LoadFormLayoutAsset(paperwork.ShipmentSummaryContinuationLayoutName)
+	if err != nil {
+		logger.Error("Error loading form layout", zap.Error(err))
+		return moveop.NewShowShipmentSummaryWorksheetInternalServerError()
+	}
+	continuationTemplate, err := assets.Asset(continuationLayout.TemplatePath())
+
+	if err != nil {
+		logger.Error("Error reading template file", zap.String("asset", continuationLayout.TemplatePath()), zap.Error(err))
+		return moveop.NewShowShipmentSummaryWorksheetInternalServerError()
+	}
+
+	for _, continuationPageData := range continuationData {
+		continuationReader := bytes.NewReader(continuationTemplate)
+		err = formFiller.AppendPage(continuationReader, continuationLayout.FieldsLayout, continuationPageData)
+		if err != nil {
+			logger.Error("Error appending page to PDF", zap.Error(err))
+			return moveop.NewShowShipmentSummaryWorksheetInternalServerError()
+		}
+	}
+
 	buf := new(bytes.Buffer)
 	err = formFiller.Output(buf)
 	if err != nil {
@@ -130,6 +130,40 @@ func (suite *HandlerSuite) TestPatchMoveHandlerNoType() {
 	suite.Assertions.Equal(move.ID.String(), okResponse.Payload.ID.String())
 }
 
+func (suite *HandlerSuite) TestPatchMoveHandlerIfMatch() {
+	// Given: a move at version 0
+	move := testdatagen.MakeDefaultMove(suite.DB())
+
+	req := httptest.NewRequest("PATCH", "/moves/some_id", nil)
+	req = suite.AuthenticateRequest(req, move.Orders.ServiceMember)
+
+	var newType = internalmessages.SelectedMoveTypeHHGPPM
+	params := moveop.PatchMoveParams{
+		HTTPRequest:      req,
+		MoveID:           strfmt.UUID(move.ID.String()),
+		PatchMovePayload: &internalmessages.PatchMovePayload{SelectedMoveType: &newType},
+		IfMatch:          swag.String(handlers.ETag(0)),
+	}
+	handler := PatchMoveHandler{handlers.NewHandlerContext(suite.DB(), suite.TestLogger())}
+
+	// When: the move is patched with its current ETag
+	response := handler.Handle(params)
+
+	// Then: the patch succeeds and the move has a new ETag
+	suite.Assertions.IsType(&moveop.PatchMoveCreated{}, response)
+	suite.Equal(handlers.ETag(1), response.(*moveop.PatchMoveCreated).ETag)
+
+	// When: the move is patched again with the ETag it had before
+	newType = internalmessages.SelectedMoveTypeHHG
+	response = handler.Handle(params)
+
+	// Then: the patch is refused with the current move and its ETag
+	suite.Assertions.IsType(&moveop.PatchMovePreconditionFailed{}, response)
+	failedResponse := response.(*moveop.PatchMovePreconditionFailed)
+	suite.Equal(handlers.ETag(1), failedResponse.ETag)
+	suite.Equal(internalmessages.SelectedMoveTypeHHGPPM, *failedResponse.Payload.SelectedMoveType)
+}
+
 func (suite *HandlerSuite) TestShowMoveHandler() {
 
 	// Given: a set of orders, a move, user and servicemember
@@ -0,0 +1,59 @@
+package internalapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/gofrs/uuid"
+
+	officeop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/office"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForNotificationDeliveryModel(n models.NotificationDelivery) *internalmessages.NotificationDeliveryPayload {
+	return &internalmessages.NotificationDeliveryPayload{
+		ID:               handlers.FmtUUID(n.ID),
+		MoveID:           handlers.FmtUUIDPtr(n.MoveID),
+		NotificationType: handlers.FmtString(n.NotificationType),
+		RecipientEmail:   handlers.FmtString(n.RecipientEmail),
+		Subject:          handlers.FmtString(n.Subject),
+		Status:           internalmessages.NotificationDeliveryStatus(n.Status),
+		Attempts:         handlers.FmtInt64(int64(n.Attempts)),
+		NextAttemptAt:    handlers.FmtDateTime(n.NextAttemptAt),
+		LastError:        n.LastError,
+		SentAt:           handlers.FmtDateTimePtr(n.SentAt),
+		CreatedAt:        handlers.FmtDateTime(n.CreatedAt),
+	}
+}
+
+// IndexMoveNotificationDeliveriesHandler lists the notifications sent about a move
+type IndexMoveNotificationDeliveriesHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the delivery status of each notification about a move, for office users
+func (h IndexMoveNotificationDeliveriesHandler) Handle(params officeop.IndexMoveNotificationDeliveriesParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return officeop.NewIndexMoveNotificationDeliveriesForbidden()
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	moveID, _ := uuid.FromString(params.MoveID.String())
+
+	// Fetch the move to check that it exists and the user can see it
+	if _, err := models.FetchMove(h.DB(), session, moveID); err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	deliveries, err := models.FetchNotificationDeliveriesForMove(h.DB(), moveID)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	payload := make(internalmessages.NotificationDeliveries, len(deliveries))
+	for i, delivery := range deliveries {
+		payload[i] = payloadForNotificationDeliveryModel(delivery)
+	}
+	return officeop.NewIndexMoveNotificationDeliveriesOK().WithPayload(payload)
+}
@@ -0,0 +1,55 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+
+	officeop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/office"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestIndexMoveNotificationDeliveriesHandler() {
+	move := testdatagen.MakeDefaultMove(suite.DB())
+	lastError := "SES is down"
+	delivery := testdatagen.MakeNotificationDelivery(suite.DB(), testdatagen.Assertions{
+		NotificationDelivery: models.NotificationDelivery{
+			Status:    models.NotificationDeliveryStatusDEADLETTER,
+			Attempts:  10,
+			LastError: &lastError,
+		},
+		Move: move,
+	})
+	// Deliveries for other moves aren't listed
+	testdatagen.MakeDefaultNotificationDelivery(suite.DB())
+
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.DB())
+	req := httptest.NewRequest("GET", "/moves/some_id/notification_deliveries", nil)
+	req = suite.AuthenticateOfficeRequest(req, officeUser)
+	params := officeop.IndexMoveNotificationDeliveriesParams{
+		HTTPRequest: req,
+		MoveID:      strfmt.UUID(move.ID.String()),
+	}
+
+	handler := IndexMoveNotificationDeliveriesHandler{handlers.NewHandlerContext(suite.DB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	suite.Assertions.IsType(&officeop.IndexMoveNotificationDeliveriesOK{}, response)
+	payload := response.(*officeop.IndexMoveNotificationDeliveriesOK).Payload
+	if suite.Len(payload, 1) {
+		suite.Equal(delivery.ID.String(), payload[0].ID.String())
+		suite.Equal(delivery.RecipientEmail, *payload[0].RecipientEmail)
+		suite.Equal(internalmessages.NotificationDeliveryStatusDEADLETTER, payload[0].Status)
+		suite.Equal(int64(10), *payload[0].Attempts)
+		suite.Equal(lastError, *payload[0].LastError)
+	}
+
+	// Service members can't see delivery status
+	req = suite.AuthenticateRequest(req, move.Orders.ServiceMember)
+	params.HTTPRequest = req
+	response = handler.Handle(params)
+	suite.Assertions.IsType(&officeop.IndexMoveNotificationDeliveriesForbidden{}, response)
+}
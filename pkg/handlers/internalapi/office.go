@@ -5,8 +5,11 @@ import (
 	"time"
 
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
 	beeline "github.com/honeycombio/beeline-go"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	officeop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/office"
@@ -42,13 +45,14 @@ func (h ApproveMoveHandler) Handle(params officeop.ApproveMoveParams) middleware
 		return officeop.NewApprovePPMBadRequest()
 	}
 
+	move.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	err = move.Approve()
 	if err != nil {
 		logger.Info("Attempted to approve move, got invalid transition", zap.Error(err), zap.String("move_status", string(move.Status)))
 		return handlers.ResponseForError(logger, err)
 	}
 
-	verrs, err := h.DB().ValidateAndUpdate(move)
+	verrs, err := models.SaveWithStateTransitions(h.DB(), move)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
@@ -87,28 +91,21 @@ func (h CancelMoveHandler) Handle(params officeop.CancelMoveParams) middleware.R
 	}
 
 	// Canceling move will result in canceled associated PPMs
+	move.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	err = move.Cancel(*params.CancelMove.CancelReason)
 	if err != nil {
 		logger.Error("Attempted to cancel move, got invalid transition", zap.Error(err), zap.String("move_status", string(move.Status)))
 		return handlers.ResponseForError(logger, err)
 	}
 
-	// Save move, orders, and PPMs statuses
-	verrs, err := models.SaveMoveDependencies(h.DB(), move)
+	// Save move, orders, and PPMs statuses, and queue the email to the service member
+	verrs, err := models.SaveMoveDependenciesInTransaction(h.DB(), move, func(tx *pop.Connection) error {
+		return notifications.EnqueueNotification(ctx, tx, notifications.NewMoveCanceled(tx, logger, session, moveID))
+	})
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
 
-	err = h.NotificationSender().SendNotification(
-		ctx,
-		notifications.NewMoveCanceled(h.DB(), logger, session, moveID),
-	)
-
-	if err != nil {
-		logger.Error("problem sending email to user", zap.Error(err))
-		return handlers.ResponseForError(logger, err)
-	}
-
 	movePayload, err := payloadForMoveModel(h.FileStorer(), move.Orders, *move)
 	if err != nil {
 		return handlers.ResponseForError(logger, err)
@@ -143,24 +140,34 @@ func (h ApprovePPMHandler) Handle(params officeop.ApprovePPMParams) middleware.R
 	if params.ApprovePersonallyProcuredMovePayload.ApproveDate != nil {
 		approveDate = time.Time(*params.ApprovePersonallyProcuredMovePayload.ApproveDate)
 	}
+	ppm.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	err = ppm.Approve(approveDate)
 	if err != nil {
 		logger.Error("Attempted to approve PPM, got invalid transition", zap.Error(err), zap.String("move_status", string(ppm.Status)))
 		return handlers.ResponseForError(logger, err)
 	}
 
-	verrs, err := h.DB().ValidateAndUpdate(ppm)
-	if err != nil || verrs.HasAny() {
-		return handlers.ResponseForVErrors(logger, verrs, err)
-	}
+	// Save the PPM and queue the email to the service member together
+	responseVErrors := validate.NewErrors()
+	var responseError error
+	h.DB().Transaction(func(tx *pop.Connection) error {
+		transactionError := errors.New("Rollback The transaction")
 
-	err = h.NotificationSender().SendNotification(
-		ctx,
-		notifications.NewMoveApproved(h.DB(), logger, session, h.HandlerContext.AppNames().MilServername, moveID),
-	)
-	if err != nil {
-		logger.Error("problem sending email to user", zap.Error(err))
-		return handlers.ResponseForError(logger, err)
+		if verrs, err := tx.ValidateAndUpdate(ppm); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = err
+			return transactionError
+		}
+
+		notification := notifications.NewMoveApproved(tx, logger, session, h.HandlerContext.AppNames().MilServername, moveID)
+		if err := notifications.EnqueueNotification(ctx, tx, notification); err != nil {
+			responseError = err
+			return transactionError
+		}
+		return nil
+	})
+	if responseError != nil || responseVErrors.HasAny() {
+		return handlers.ResponseForVErrors(logger, responseVErrors, responseError)
 	}
 
 	ppmPayload, err := payloadForPPMModel(h.FileStorer(), *ppm)
@@ -191,13 +198,14 @@ func (h ApproveReimbursementHandler) Handle(params officeop.ApproveReimbursement
 		return handlers.ResponseForError(logger, err)
 	}
 
+	reimbursement.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	err = reimbursement.Approve()
 	if err != nil {
 		logger.Error("Attempted to approve, got invalid transition", zap.Error(err), zap.String("reimbursement_status", string(reimbursement.Status)))
 		return handlers.ResponseForError(logger, err)
 	}
 
-	verrs, err := h.DB().ValidateAndUpdate(reimbursement)
+	verrs, err := models.SaveWithStateTransitions(h.DB(), reimbursement)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
@@ -8,6 +8,8 @@ import (
 	"github.com/go-openapi/swag"
 	"github.com/gofrs/uuid"
 	"github.com/honeycombio/beeline-go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
 	ordersop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/orders"
 	"github.com/transcom/mymove/pkg/gen/internalmessages"
@@ -150,7 +152,7 @@ func (h ShowOrdersHandler) Handle(params ordersop.ShowOrdersParams) middleware.R
 	if err != nil {
 		return handlers.ResponseForError(logger, err)
 	}
-	return ordersop.NewShowOrdersOK().WithPayload(orderPayload)
+	return ordersop.NewShowOrdersOK().WithPayload(orderPayload).WithETag(handlers.ETag(order.Version))
 }
 
 // UpdateOrdersHandler updates an order via PUT /orders/{orderId}
@@ -203,7 +205,20 @@ func (h UpdateOrdersHandler) Handle(params ordersop.UpdateOrdersParams) middlewa
 		order.DepartmentIndicator = handlers.FmtString(string(*payload.DepartmentIndicator))
 	}
 
+	order.ExpectVersions(handlers.IfMatchVersions(params.IfMatch))
 	verrs, err := models.SaveOrder(h.DB(), &order)
+	if errors.Cause(err) == models.ErrStaleVersion {
+		logger.Info("Orders have changed since the version in If-Match", zap.Error(err))
+		current, err := models.FetchOrderForUser(h.DB(), session, orderID)
+		if err != nil {
+			return handlers.ResponseForError(logger, err)
+		}
+		currentPayload, err := payloadForOrdersModel(h.FileStorer(), current)
+		if err != nil {
+			return handlers.ResponseForError(logger, err)
+		}
+		return ordersop.NewUpdateOrdersPreconditionFailed().WithPayload(currentPayload).WithETag(handlers.ETag(current.Version))
+	}
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
@@ -212,5 +227,5 @@ func (h UpdateOrdersHandler) Handle(params ordersop.UpdateOrdersParams) middlewa
 	if err != nil {
 		return handlers.ResponseForError(logger, err)
 	}
-	return ordersop.NewUpdateOrdersOK().WithPayload(orderPayload)
+	return ordersop.NewUpdateOrdersOK().WithPayload(orderPayload).WithETag(handlers.ETag(order.Version))
 }
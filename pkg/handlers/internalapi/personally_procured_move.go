@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	ppmop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/ppm"
@@ -256,7 +257,20 @@ func (h PatchPersonallyProcuredMoveHandler) Handle(params ppmop.PatchPersonallyP
 		}
 	}
 
+	ppm.ExpectVersions(handlers.IfMatchVersions(params.IfMatch))
 	verrs, err := models.SavePersonallyProcuredMove(h.DB(), ppm)
+	if errors.Cause(err) == models.ErrStaleVersion {
+		logger.Info("PPM has changed since the version in If-Match", zap.Error(err))
+		current, err := models.FetchPersonallyProcuredMove(h.DB(), session, ppmID)
+		if err != nil {
+			return handlers.ResponseForError(logger, err)
+		}
+		currentPayload, err := payloadForPPMModel(h.FileStorer(), *current)
+		if err != nil {
+			return handlers.ResponseForError(logger, err)
+		}
+		return ppmop.NewPatchPersonallyProcuredMovePreconditionFailed().WithPayload(currentPayload).WithETag(handlers.ETag(current.Version))
+	}
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
@@ -265,7 +279,7 @@ func (h PatchPersonallyProcuredMoveHandler) Handle(params ppmop.PatchPersonallyP
 	if err != nil {
 		return handlers.ResponseForError(logger, err)
 	}
-	return ppmop.NewPatchPersonallyProcuredMoveOK().WithPayload(ppmPayload)
+	return ppmop.NewPatchPersonallyProcuredMoveOK().WithPayload(ppmPayload).WithETag(handlers.ETag(ppm.Version))
 }
 
 // ppmNeedsEstimatesRecalculated determines whether the fields that comprise
@@ -331,6 +345,7 @@ func (h SubmitPersonallyProcuredMoveHandler) Handle(params ppmop.SubmitPersonall
 	if params.SubmitPersonallyProcuredMovePayload.SubmitDate != nil {
 		submitDate = time.Time(*params.SubmitPersonallyProcuredMovePayload.SubmitDate)
 	}
+	ppm.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	err = ppm.Submit(submitDate)
 
 	verrs, err := models.SavePersonallyProcuredMove(h.DB(), ppm)
@@ -453,6 +468,7 @@ func (h RequestPPMPaymentHandler) Handle(params ppmop.RequestPPMPaymentParams) m
 		return handlers.ResponseForError(logger, err)
 	}
 
+	ppm.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	err = ppm.RequestPayment()
 	if err != nil {
 		return handlers.ResponseForError(logger, err)
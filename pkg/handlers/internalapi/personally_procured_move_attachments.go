@@ -41,6 +41,8 @@ func (h CreatePersonallyProcuredMoveAttachmentsHandler) Handle(params ppmop.Crea
 
 	// Init our tools
 	loader := uploader.NewUploader(h.DB(), logger, h.FileStorer())
+	// The merged PDF is only built from uploads that have passed their scans
+	loader.SetTrusted(true)
 	generator, err := paperwork.NewGenerator(h.DB(), logger, loader)
 	if err != nil {
 		logger.Error("failed to initialize generator", zap.Error(err))
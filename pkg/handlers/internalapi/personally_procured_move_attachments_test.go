@@ -83,6 +83,7 @@ func (suite *HandlerSuite) TestCreatePPMAttachmentsHandlerTests() {
 
 			// Create upload for expense document model
 			loader := uploader.NewUploader(suite.DB(), suite.TestLogger(), context.FileStorer())
+			loader.SetTrusted(true)
 			loader.CreateUploadForDocument(&expDoc.MoveDocument.DocumentID, *officeUser.UserID, f, uploader.AllowedTypesServiceMember)
 
 			request := httptest.NewRequest("POST", "/fake/path", nil)
@@ -101,10 +102,11 @@ func (suite *HandlerSuite) TestCreatePPMAttachmentsHandlerTests() {
 			suite.IsNotErrResponse(response)
 			createdResponse := response.(*ppmop.CreatePPMAttachmentsOK)
 			createdPDFPayload := createdResponse.Payload
-			suite.NotNil(createdPDFPayload.URL)
+			suite.NotEmpty(createdPDFPayload.URL)
+			suite.Equal(models.UploadStatusCLEAN, models.UploadStatus(*createdPDFPayload.Status))
 
 			// Extract upload key from returned URL
-			attachmentsURL := string(*createdPDFPayload.URL)
+			attachmentsURL := string(createdPDFPayload.URL)
 			uploadKey := uploadKeyRe.FindStringSubmatch(attachmentsURL)[1]
 
 			merged, err := context.FileStorer().Fetch(uploadKey)
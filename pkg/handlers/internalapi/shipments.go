@@ -8,6 +8,7 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"github.com/honeycombio/beeline-go"
@@ -19,6 +20,7 @@ import (
 	"github.com/transcom/mymove/pkg/logging"
 	"github.com/transcom/mymove/pkg/models"
 	invoiceop "github.com/transcom/mymove/pkg/services/invoice"
+	"github.com/transcom/mymove/pkg/webhooks"
 )
 
 func payloadForInvoiceModel(a *models.Invoice) *internalmessages.Invoice {
@@ -34,6 +36,7 @@ func payloadForInvoiceModel(a *models.Invoice) *internalmessages.Invoice {
 		ApproverLastName:  a.Approver.LastName,
 		Status:            internalmessages.InvoiceStatus(a.Status),
 		InvoicedDate:      *handlers.FmtDateTime(a.InvoicedDate),
+		RejectionReasons:  a.RejectionReasons,
 	}
 }
 
@@ -348,8 +351,21 @@ func (h PatchShipmentHandler) Handle(params shipmentop.PatchShipmentParams) midd
 		patchShipmentWithPremoveSurveyFields(shipment, params.Shipment)
 	}
 
+	shipment.ExpectVersions(handlers.IfMatchVersions(params.IfMatch))
 	verrs, err := models.SaveShipmentAndAddresses(h.DB(), shipment)
 
+	if errors.Cause(err) == models.ErrStaleVersion {
+		logger.Info("Shipment has changed since the version in If-Match", zap.Error(err))
+		current, err := models.FetchShipment(h.DB(), session, shipmentID)
+		if err != nil {
+			return handlers.ResponseForError(logger, err)
+		}
+		currentPayload, err := payloadForShipmentModel(*current)
+		if err != nil {
+			logger.Error("Error in shipment payload: ", zap.Error(err))
+		}
+		return shipmentop.NewPatchShipmentPreconditionFailed().WithPayload(currentPayload).WithETag(handlers.ETag(current.Version))
+	}
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
@@ -359,7 +375,7 @@ func (h PatchShipmentHandler) Handle(params shipmentop.PatchShipmentParams) midd
 		logger.Error("Error in shipment payload: ", zap.Error(err))
 	}
 
-	return shipmentop.NewPatchShipmentOK().WithPayload(shipmentPayload)
+	return shipmentop.NewPatchShipmentOK().WithPayload(shipmentPayload).WithETag(handlers.ETag(shipment.Version))
 }
 
 func updateShipmentDatesWithPayload(h handlers.HandlerContext, shipment *models.Shipment, payload *internalmessages.Shipment) error {
@@ -414,7 +430,7 @@ func (h GetShipmentHandler) Handle(params shipmentop.GetShipmentParams) middlewa
 		logger.Error("Error in shipment payload: ", zap.Error(err))
 	}
 
-	return shipmentop.NewGetShipmentOK().WithPayload(shipmentPayload)
+	return shipmentop.NewGetShipmentOK().WithPayload(shipmentPayload).WithETag(handlers.ETag(shipment.Version))
 }
 
 // ApproveHHGHandler approves an HHG
@@ -446,16 +462,22 @@ func (h ApproveHHGHandler) Handle(params shipmentop.ApproveHHGParams) middleware
 	if params.ApproveShipmentPayload.ApproveDate != nil {
 		approveDate = time.Time(*params.ApproveShipmentPayload.ApproveDate)
 	}
+	shipment.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	err = shipment.Approve(approveDate)
 	if err != nil {
 		logger.Error("Attempted to approve HHG, got invalid transition", zap.Error(err), zap.String("shipment_status", string(shipment.Status)))
 		return handlers.ResponseForError(logger, err)
 	}
-	verrs, err := h.DB().ValidateAndUpdate(shipment)
+	verrs, err := models.SaveWithStateTransitions(h.DB(), shipment)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
 
+	err = webhooks.Publish(h.DB(), webhooks.NewShipmentEvent(models.WebhookEventTypeSHIPMENTAPPROVED, shipment.ID, shipment.Status))
+	if err != nil {
+		logger.Error("problem publishing shipment approved webhook", zap.Error(err))
+	}
+
 	shipmentPayload, err := payloadForShipmentModel(*shipment)
 	if err != nil {
 		logger.Error("Error in shipment payload: ", zap.Error(err))
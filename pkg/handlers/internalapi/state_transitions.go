@@ -0,0 +1,58 @@
+package internalapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/gofrs/uuid"
+
+	officeop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/office"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForStateTransitionModel(t models.StateTransition) *internalmessages.StateTransitionPayload {
+	return &internalmessages.StateTransitionPayload{
+		ID:             handlers.FmtUUID(t.ID),
+		EntityType:     internalmessages.StateTransitionEntityType(t.EntityType),
+		EntityID:       handlers.FmtUUID(t.EntityID),
+		PreviousStatus: handlers.FmtString(t.PreviousStatus),
+		NewStatus:      handlers.FmtString(t.NewStatus),
+		Reason:         t.Reason,
+		ActorUserID:    handlers.FmtUUIDPtr(t.ActorUserID),
+		Application:    t.Application,
+		RequestID:      t.RequestID,
+		CreatedAt:      handlers.FmtDateTime(t.CreatedAt),
+	}
+}
+
+// IndexMoveTimelineHandler lists the status changes to a move
+type IndexMoveTimelineHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the status changes to a move and the records that belong to it, for office users
+func (h IndexMoveTimelineHandler) Handle(params officeop.IndexMoveTimelineParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return officeop.NewIndexMoveTimelineForbidden()
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	moveID, _ := uuid.FromString(params.MoveID.String())
+
+	// Fetch the move to check that it exists and the user can see it
+	if _, err := models.FetchMove(h.DB(), session, moveID); err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	transitions, err := models.FetchStateTransitionsForMove(h.DB(), moveID)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	payload := make(internalmessages.StateTransitions, len(transitions))
+	for i, transition := range transitions {
+		payload[i] = payloadForStateTransitionModel(transition)
+	}
+	return officeop.NewIndexMoveTimelineOK().WithPayload(payload)
+}
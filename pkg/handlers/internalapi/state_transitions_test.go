@@ -0,0 +1,50 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	officeop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/office"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestIndexMoveTimelineHandler() {
+	move := testdatagen.MakeDefaultMove(suite.DB())
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.DB())
+	move.SetTransitionActor(models.TransitionActor{UserID: officeUser.UserID})
+	suite.NoError(move.Submit(time.Now()))
+	verrs, err := models.SaveWithStateTransitions(suite.DB(), &move)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	req := httptest.NewRequest("GET", "/moves/some_id/timeline", nil)
+	req = suite.AuthenticateOfficeRequest(req, officeUser)
+	params := officeop.IndexMoveTimelineParams{
+		HTTPRequest: req,
+		MoveID:      strfmt.UUID(move.ID.String()),
+	}
+
+	handler := IndexMoveTimelineHandler{handlers.NewHandlerContext(suite.DB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	suite.Assertions.IsType(&officeop.IndexMoveTimelineOK{}, response)
+	payload := response.(*officeop.IndexMoveTimelineOK).Payload
+	if suite.Len(payload, 1) {
+		suite.Equal(internalmessages.StateTransitionEntityTypeMOVE, payload[0].EntityType)
+		suite.Equal(move.ID.String(), payload[0].EntityID.String())
+		suite.Equal(string(models.MoveStatusDRAFT), *payload[0].PreviousStatus)
+		suite.Equal(string(models.MoveStatusSUBMITTED), *payload[0].NewStatus)
+		suite.Equal(officeUser.UserID.String(), payload[0].ActorUserID.String())
+	}
+
+	// Service members can't see the timeline
+	req = suite.AuthenticateRequest(req, move.Orders.ServiceMember)
+	params.HTTPRequest = req
+	response = handler.Handle(params)
+	suite.Assertions.IsType(&officeop.IndexMoveTimelineForbidden{}, response)
+}
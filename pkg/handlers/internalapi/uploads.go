@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 	"github.com/gofrs/uuid"
 	"github.com/honeycombio/beeline-go"
@@ -18,18 +19,54 @@ import (
 	uploaderpkg "github.com/transcom/mymove/pkg/uploader"
 )
 
+// payloadForUploadModel builds an UploadPayload. url is empty for uploads that haven't passed their virus scan.
 func payloadForUploadModel(upload models.Upload, url string) *internalmessages.UploadPayload {
 	return &internalmessages.UploadPayload{
-		ID:          handlers.FmtUUID(upload.ID),
-		Filename:    swag.String(upload.Filename),
-		ContentType: swag.String(upload.ContentType),
-		URL:         handlers.FmtURI(url),
-		Bytes:       &upload.Bytes,
-		CreatedAt:   handlers.FmtDateTime(upload.CreatedAt),
-		UpdatedAt:   handlers.FmtDateTime(upload.UpdatedAt),
+		ID:           handlers.FmtUUID(upload.ID),
+		Filename:     swag.String(upload.Filename),
+		ContentType:  swag.String(upload.ContentType),
+		URL:          strfmt.URI(url),
+		Bytes:        &upload.Bytes,
+		HasThumbnail: upload.ThumbnailStorageKey != nil,
+		Status:       swag.String(string(upload.Status)),
+		CreatedAt:    handlers.FmtDateTime(upload.CreatedAt),
+		UpdatedAt:    handlers.FmtDateTime(upload.UpdatedAt),
 	}
 }
 
+// ShowUploadHandler returns an upload with a URL for one variant of its file
+type ShowUploadHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns an upload
+func (h ShowUploadHandler) Handle(params uploadop.ShowUploadParams) middleware.Responder {
+
+	ctx, span := beeline.StartSpan(params.HTTPRequest.Context(), reflect.TypeOf(h).Name())
+	defer span.Send()
+
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+
+	uploadID, _ := uuid.FromString(params.UploadID.String())
+	upload, err := models.FetchUpload(ctx, h.DB(), session, uploadID)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	uploader := uploaderpkg.NewUploader(h.DB(), logger, h.FileStorer())
+	var url string
+	if swag.StringValue(params.Variant) == "thumbnail" {
+		url, err = uploader.ThumbnailPresignedURL(&upload)
+	} else {
+		url, err = uploader.PresignedURL(&upload)
+	}
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	return uploadop.NewShowUploadOK().WithPayload(payloadForUploadModel(upload, url))
+}
+
 // CreateUploadHandler creates a new upload via POST /documents/{documentID}/uploads
 type CreateUploadHandler struct {
 	handlers.HandlerContext
@@ -92,10 +129,14 @@ func (h CreateUploadHandler) Handle(params uploadop.CreateUploadParams) middlewa
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
 
-	url, err := uploader.PresignedURL(newUpload)
-	if err != nil {
-		logger.Error("failed to get presigned url", zap.Error(err))
-		return uploadop.NewCreateUploadInternalServerError()
+	// New uploads are quarantined until they are scanned, so usually have no URL yet
+	var url string
+	if newUpload.IsClean() {
+		url, err = uploader.PresignedURL(newUpload)
+		if err != nil {
+			logger.Error("failed to get presigned url", zap.Error(err))
+			return uploadop.NewCreateUploadInternalServerError()
+		}
 	}
 	uploadPayload := payloadForUploadModel(*newUpload, url)
 	return uploadop.NewCreateUploadCreated().WithPayload(uploadPayload)
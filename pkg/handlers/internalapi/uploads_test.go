@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 	"github.com/gofrs/uuid"
 
 	uploadop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/uploads"
@@ -60,6 +61,11 @@ func (suite *HandlerSuite) TestCreateUploadsHandlerSuccess() {
 	if upload.Checksum != expectedChecksum {
 		t.Errorf("Did not calculate the correct MD5: expected %s, got %s", expectedChecksum, upload.Checksum)
 	}
+
+	// New uploads are quarantined until they are scanned
+	suite.Equal(models.UploadStatusPENDING, upload.Status)
+	suite.Equal(string(models.UploadStatusPENDING), *uploadPayload.Status)
+	suite.Empty(uploadPayload.URL)
 }
 
 func (suite *HandlerSuite) TestCreateUploadsHandlerFailsWithWrongUser() {
@@ -221,3 +227,58 @@ func (suite *HandlerSuite) TestDeleteUploadsHandlerSuccess() {
 	err := suite.DB().Find(&queriedUpload, upload1.ID)
 	suite.NotNil(err)
 }
+
+func (suite *HandlerSuite) TestShowUploadHandlerThumbnail() {
+	thumbnailKey := "user/uploads/receipt-thumbnail"
+	upload := testdatagen.MakeUpload(suite.DB(), testdatagen.Assertions{
+		Upload: models.Upload{
+			Filename:            "receipt.jpg",
+			ContentType:         "image/jpeg",
+			ThumbnailStorageKey: &thumbnailKey,
+		},
+	})
+	var document models.Document
+	suite.NoError(suite.DB().Eager("ServiceMember").Find(&document, upload.DocumentID))
+
+	context := handlers.NewHandlerContext(suite.DB(), suite.TestLogger())
+	context.SetFileStorer(storageTest.NewFakeS3Storage(true))
+	handler := ShowUploadHandler{context}
+
+	params := uploadop.NewShowUploadParams()
+	params.HTTPRequest = suite.AuthenticateRequest(&http.Request{}, document.ServiceMember)
+	params.UploadID = strfmt.UUID(upload.ID.String())
+	params.Variant = swag.String("thumbnail")
+
+	response := handler.Handle(params)
+	showResponse, ok := response.(*uploadop.ShowUploadOK)
+	if suite.True(ok, "Wrong response type %T", response) {
+		suite.True(showResponse.Payload.HasThumbnail)
+		suite.Contains(showResponse.Payload.URL.String(), thumbnailKey)
+	}
+
+	params.Variant = swag.String("original")
+	response = handler.Handle(params)
+	showResponse, ok = response.(*uploadop.ShowUploadOK)
+	if suite.True(ok, "Wrong response type %T", response) {
+		suite.Contains(showResponse.Payload.URL.String(), upload.StorageKey)
+	}
+}
+
+func (suite *HandlerSuite) TestShowUploadHandlerNoThumbnail() {
+	upload := testdatagen.MakeDefaultUpload(suite.DB())
+	var document models.Document
+	suite.NoError(suite.DB().Eager("ServiceMember").Find(&document, upload.DocumentID))
+
+	context := handlers.NewHandlerContext(suite.DB(), suite.TestLogger())
+	context.SetFileStorer(storageTest.NewFakeS3Storage(true))
+	handler := ShowUploadHandler{context}
+
+	params := uploadop.NewShowUploadParams()
+	params.HTTPRequest = suite.AuthenticateRequest(&http.Request{}, document.ServiceMember)
+	params.UploadID = strfmt.UUID(upload.ID.String())
+	params.Variant = swag.String("thumbnail")
+
+	response := handler.Handle(params)
+	suite.Assertions.IsType(&handlers.ErrResponse{}, response)
+	suite.Equal(http.StatusNotFound, response.(*handlers.ErrResponse).Code)
+}
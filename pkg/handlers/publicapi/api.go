@@ -9,8 +9,8 @@ import (
 	"github.com/transcom/mymove/pkg/gen/restapi"
 	publicops "github.com/transcom/mymove/pkg/gen/restapi/apioperations"
 	"github.com/transcom/mymove/pkg/handlers"
-	"github.com/transcom/mymove/pkg/paperwork"
 	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/webhooks"
 
 	accesscodeservice "github.com/transcom/mymove/pkg/services/accesscode"
 	paperworkservice "github.com/transcom/mymove/pkg/services/paperwork"
@@ -43,6 +43,7 @@ func NewPublicAPIHandler(context handlers.HandlerContext, logger Logger) http.Ha
 	// Shipments
 	publicAPI.ShipmentsIndexShipmentsHandler = IndexShipmentsHandler{context}
 	publicAPI.ShipmentsGetShipmentHandler = GetShipmentHandler{context}
+	publicAPI.ShipmentsIndexShipmentTimelineHandler = IndexShipmentTimelineHandler{context}
 	publicAPI.ShipmentsPatchShipmentHandler = PatchShipmentHandler{context}
 	publicAPI.ShipmentsAcceptShipmentHandler = AcceptShipmentHandler{context}
 	publicAPI.ShipmentsTransportShipmentHandler = TransportShipmentHandler{context}
@@ -58,10 +59,11 @@ func NewPublicAPIHandler(context handlers.HandlerContext, logger Logger) http.Ha
 	publicAPI.ShipmentsGetShipmentInvoicesHandler = GetShipmentInvoicesHandler{context}
 
 	publicAPI.ShipmentsCompletePmSurveyHandler = CompletePmSurveyHandler{context}
-	publicAPI.ShipmentsCreateGovBillOfLadingHandler = CreateGovBillOfLadingHandler{context, paperworkservice.NewFormCreator(context.FileStorer().TempFileSystem(), paperwork.NewFormFiller())}
+	publicAPI.ShipmentsCreateGovBillOfLadingHandler = CreateGovBillOfLadingHandler{context, paperworkservice.NewFormCreator(context.FileStorer().TempFileSystem(), nil)}
 
 	// Accessorials
 	publicAPI.AccessorialsGetShipmentLineItemsHandler = GetShipmentLineItemsHandler{context, shipmentlineitemservice.NewShipmentLineItemFetcher(context.DB())}
+	publicAPI.AccessorialsGetShipmentLineItemPriceExplanationHandler = GetShipmentLineItemPriceExplanationHandler{context, shipmentlineitemservice.NewShipmentLineItemFetcher(context.DB())}
 	publicAPI.AccessorialsUpdateShipmentLineItemHandler = UpdateShipmentLineItemHandler{context}
 	publicAPI.AccessorialsCreateShipmentLineItemHandler = CreateShipmentLineItemHandler{context}
 	publicAPI.AccessorialsDeleteShipmentLineItemHandler = DeleteShipmentLineItemHandler{context}
@@ -104,7 +106,7 @@ func NewPublicAPIHandler(context handlers.HandlerContext, logger Logger) http.Ha
 	}
 	publicAPI.StorageInTransitsApproveStorageInTransitHandler = ApproveStorageInTransitHandler{
 		context,
-		sitservice.NewStorageInTransitApprover(context.DB()),
+		sitservice.NewStorageInTransitApprover(context.DB(), context.HoneyZapLogger()),
 	}
 	publicAPI.StorageInTransitsDenyStorageInTransitHandler = DenyStorageInTransitHandler{
 		context,
@@ -116,7 +118,11 @@ func NewPublicAPIHandler(context handlers.HandlerContext, logger Logger) http.Ha
 	}
 	publicAPI.StorageInTransitsReleaseStorageInTransitHandler = ReleaseStorageInTransitHandler{
 		context,
-		sitservice.NewStorageInTransitInReleaser(context.DB()),
+		sitservice.NewStorageInTransitInReleaser(context.DB(), context.Planner()),
+	}
+	publicAPI.StorageInTransitsAttemptDeliveryStorageInTransitHandler = AttemptDeliveryStorageInTransitHandler{
+		context,
+		sitservice.NewStorageInTransitDeliveryAttempter(context.DB(), context.Planner()),
 	}
 
 	// Access Codes
@@ -130,5 +136,13 @@ func NewPublicAPIHandler(context handlers.HandlerContext, logger Logger) http.Ha
 		postalcodeservice.NewPostalCodeValidator(context.DB()),
 	}
 
+	// Webhook Subscriptions
+	publicAPI.WebhookSubscriptionsIndexWebhookSubscriptionsHandler = IndexWebhookSubscriptionsHandler{context}
+	publicAPI.WebhookSubscriptionsCreateWebhookSubscriptionHandler = CreateWebhookSubscriptionHandler{context, webhooks.NewDestinationChecker()}
+	publicAPI.WebhookSubscriptionsGetWebhookSubscriptionHandler = GetWebhookSubscriptionHandler{context}
+	publicAPI.WebhookSubscriptionsPatchWebhookSubscriptionHandler = PatchWebhookSubscriptionHandler{context, webhooks.NewDestinationChecker()}
+	publicAPI.WebhookSubscriptionsDeleteWebhookSubscriptionHandler = DeleteWebhookSubscriptionHandler{context}
+	publicAPI.WebhookSubscriptionsIndexWebhookDeliveriesHandler = IndexWebhookDeliveriesHandler{context}
+
 	return publicAPI.Serve(nil)
 }
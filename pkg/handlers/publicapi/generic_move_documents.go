@@ -4,6 +4,7 @@ import (
 	"reflect"
 
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 	"github.com/gofrs/uuid"
 	beeline "github.com/honeycombio/beeline-go"
@@ -19,17 +20,23 @@ import (
 func payloadForDocumentModel(storer storage.FileStorer, document models.Document) (*apimessages.DocumentPayload, error) {
 	uploads := make([]*apimessages.UploadPayload, len(document.Uploads))
 	for i, upload := range document.Uploads {
-		url, err := storer.PresignedURL(upload.StorageKey, upload.ContentType)
-		if err != nil {
-			return nil, err
+		// Uploads that haven't passed their virus scan can't be fetched
+		var url string
+		if upload.IsClean() {
+			var err error
+			url, err = storer.PresignedURL(upload.StorageKey, upload.ContentType)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		uploadPayload := &apimessages.UploadPayload{
 			ID:          handlers.FmtUUID(upload.ID),
 			Filename:    swag.String(upload.Filename),
 			ContentType: swag.String(upload.ContentType),
-			URL:         handlers.FmtURI(url),
+			URL:         strfmt.URI(url),
 			Bytes:       &upload.Bytes,
+			Status:      swag.String(string(upload.Status)),
 			CreatedAt:   handlers.FmtDateTime(upload.CreatedAt),
 			UpdatedAt:   handlers.FmtDateTime(upload.UpdatedAt),
 		}
@@ -34,6 +34,7 @@ func payloadForInvoiceModel(a *models.Invoice) *apimessages.Invoice {
 		ApproverLastName:  a.Approver.LastName,
 		Status:            apimessages.InvoiceStatus(a.Status),
 		InvoicedDate:      *handlers.FmtDateTime(a.InvoicedDate),
+		RejectionReasons:  a.RejectionReasons,
 	}
 }
 
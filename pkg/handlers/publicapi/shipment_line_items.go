@@ -18,6 +18,7 @@ import (
 	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/rateengine"
 	"github.com/transcom/mymove/pkg/unit"
+	"github.com/transcom/mymove/pkg/webhooks"
 )
 
 func payloadForShipmentLineItemModels(s []models.ShipmentLineItem) apimessages.ShipmentLineItems {
@@ -101,6 +102,52 @@ func (h GetShipmentLineItemsHandler) Handle(params accessorialop.GetShipmentLine
 	return accessorialop.NewGetShipmentLineItemsOK().WithPayload(payload)
 }
 
+func payloadForPriceExplanationModel(e models.PriceExplanation) *apimessages.PriceExplanation {
+	payload := apimessages.PriceExplanation{
+		Description: handlers.FmtString(e.Description),
+		AmountCents: handlers.FmtCost(e.AmountCents),
+		Detail:      e.Detail,
+		Children:    make([]*apimessages.PriceExplanation, len(e.Children)),
+	}
+	if e.Source != nil {
+		payload.Source = &apimessages.PriceExplanationSource{
+			Table:       handlers.FmtString(e.Source.Table),
+			ID:          handlers.FmtUUID(e.Source.ID),
+			Description: e.Source.Description,
+		}
+	}
+	for i, child := range e.Children {
+		payload.Children[i] = payloadForPriceExplanationModel(child)
+	}
+	return &payload
+}
+
+// GetShipmentLineItemPriceExplanationHandler returns the explanation of how a shipment line item was priced
+type GetShipmentLineItemPriceExplanationHandler struct {
+	handlers.HandlerContext
+	shipmentLineItemFetcher services.ShipmentLineItemFetcher
+}
+
+// Handle returns the price explanation of a specified shipment line item
+func (h GetShipmentLineItemPriceExplanationHandler) Handle(params accessorialop.GetShipmentLineItemPriceExplanationParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+	shipmentLineItemID := uuid.Must(uuid.FromString(params.ShipmentLineItemID.String()))
+
+	shipmentLineItem, err := h.shipmentLineItemFetcher.GetShipmentLineItemByID(shipmentLineItemID, session)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error fetching shipment line item %s", shipmentLineItemID), zap.Error(err))
+		return handlers.ResponseForError(logger, err)
+	}
+
+	// Line items priced before explanations were recorded, and unpriced ones, have nothing to explain
+	if shipmentLineItem.PriceExplanation == nil {
+		return accessorialop.NewGetShipmentLineItemPriceExplanationNotFound()
+	}
+
+	payload := payloadForPriceExplanationModel(*shipmentLineItem.PriceExplanation)
+	return accessorialop.NewGetShipmentLineItemPriceExplanationOK().WithPayload(payload)
+}
+
 // CreateShipmentLineItemHandler creates a shipment_line_item for a provided shipment_id
 type CreateShipmentLineItemHandler struct {
 	handlers.HandlerContext
@@ -449,7 +496,15 @@ func (h ApproveShipmentLineItemHandler) Handle(params accessorialop.ApproveShipm
 	}
 
 	// Save the shipment line item
-	h.DB().ValidateAndUpdate(&shipmentLineItem)
+	verrs, err := h.DB().ValidateAndUpdate(&shipmentLineItem)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(logger, verrs, err)
+	}
+
+	err = webhooks.Publish(h.DB(), webhooks.NewPreApprovalRequestEvent(shipmentLineItem))
+	if err != nil {
+		logger.Error("problem publishing pre-approval request approved webhook", zap.Error(err))
+	}
 
 	payload := payloadForShipmentLineItemModel(&shipmentLineItem)
 	return accessorialop.NewApproveShipmentLineItemOK().WithPayload(payload)
@@ -219,6 +219,82 @@ func (suite *HandlerSuite) TestGetShipmentLineItemsHandler() {
 	suite.Assertions.IsType(&handlers.ErrResponse{}, response)
 }
 
+func (suite *HandlerSuite) TestGetShipmentLineItemPriceExplanationHandler() {
+	officeUserID, _ := uuid.NewV4()
+	userIDForOfficeUser, _ := uuid.NewV4()
+	officeUser := models.OfficeUser{ID: officeUserID, UserID: &userIDForOfficeUser}
+	shipmentLineItemID, _ := uuid.NewV4()
+	rateID, _ := uuid.NewV4()
+
+	path := fmt.Sprintf("/shipments/accessorials/%s/price_explanation", shipmentLineItemID)
+	req := httptest.NewRequest("GET", path, nil)
+	req = suite.AuthenticateOfficeRequest(req, officeUser)
+
+	shipmentLineItemFetcher := &mocks.ShipmentLineItemFetcher{}
+	handler := GetShipmentLineItemPriceExplanationHandler{
+		handlers.NewHandlerContext(suite.DB(), suite.TestLogger()),
+		shipmentLineItemFetcher,
+	}
+	params := accessorialop.GetShipmentLineItemPriceExplanationParams{
+		HTTPRequest:        req,
+		ShipmentLineItemID: strfmt.UUID(shipmentLineItemID.String()),
+	}
+
+	amount := unit.Cents(4550)
+	returnShipmentLineItem := models.ShipmentLineItem{
+		ID: shipmentLineItemID,
+		PriceExplanation: &models.PriceExplanation{
+			Description: "105B Pack Reg Crate",
+			AmountCents: &amount,
+			Children: []models.PriceExplanation{
+				{
+					Description: "105B rate",
+					Detail:      "$22.75",
+					Source:      &models.PriceExplanationSource{Table: "tariff400ng_item_rates", ID: rateID},
+				},
+				{Description: "Quantity", Detail: "2.0000"},
+			},
+		},
+	}
+
+	// Happy path
+	shipmentLineItemFetcher.On("GetShipmentLineItemByID",
+		shipmentLineItemID,
+		auth.SessionFromRequestContext(params.HTTPRequest),
+	).Return(returnShipmentLineItem, nil).Once()
+
+	response := handler.Handle(params)
+	if suite.Assertions.IsType(&accessorialop.GetShipmentLineItemPriceExplanationOK{}, response) {
+		payload := response.(*accessorialop.GetShipmentLineItemPriceExplanationOK).Payload
+		suite.Equal("105B Pack Reg Crate", *payload.Description)
+		suite.Equal(int64(4550), *payload.AmountCents)
+		suite.Len(payload.Children, 2)
+		suite.Equal("tariff400ng_item_rates", *payload.Children[0].Source.Table)
+		suite.Equal(strfmt.UUID(rateID.String()), *payload.Children[0].Source.ID)
+		suite.Nil(payload.Children[1].Source)
+		suite.Empty(payload.Children[1].Children)
+	}
+
+	// Line item that has not been priced
+	shipmentLineItemFetcher.On("GetShipmentLineItemByID",
+		shipmentLineItemID,
+		auth.SessionFromRequestContext(params.HTTPRequest),
+	).Return(models.ShipmentLineItem{ID: shipmentLineItemID}, nil).Once()
+
+	response = handler.Handle(params)
+	suite.Assertions.IsType(&accessorialop.GetShipmentLineItemPriceExplanationNotFound{}, response)
+
+	// Error 403
+	expectedError := models.ErrFetchForbidden
+	shipmentLineItemFetcher.On("GetShipmentLineItemByID",
+		shipmentLineItemID,
+		auth.SessionFromRequestContext(params.HTTPRequest),
+	).Return(models.ShipmentLineItem{}, expectedError).Once()
+
+	response = handler.Handle(params)
+	suite.Equal(&handlers.ErrResponse{Code: http.StatusForbidden, Err: expectedError}, response)
+}
+
 func (suite *HandlerSuite) TestGetShipmentLineItemTSPHandler() {
 	numTspUsers := 1
 	numShipments := 1
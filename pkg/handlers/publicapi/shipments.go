@@ -184,7 +184,7 @@ func (h GetShipmentHandler) Handle(params shipmentop.GetShipmentParams) middlewa
 	}
 
 	sp := payloadForShipmentModel(*shipment)
-	return shipmentop.NewGetShipmentOK().WithPayload(sp)
+	return shipmentop.NewGetShipmentOK().WithPayload(sp).WithETag(handlers.ETag(shipment.Version))
 }
 
 // GetShipmentInvoicesHandler returns all invoices for a shipment
@@ -247,7 +247,7 @@ func (h AcceptShipmentHandler) Handle(params shipmentop.AcceptShipmentParams) mi
 	}
 
 	// Accept the shipment
-	shipment, shipmentOffer, verrs, err := models.AcceptShipmentForTSP(h.DB(), tspUser.TransportationServiceProviderID, shipmentID)
+	shipment, shipmentOffer, verrs, err := models.AcceptShipmentForTSP(h.DB(), tspUser.TransportationServiceProviderID, shipmentID, handlers.TransitionActorFromRequest(params.HTTPRequest))
 	if err != nil || verrs.HasAny() {
 		if err == models.ErrFetchNotFound {
 			logger.Error("DB Query", zap.Error(err))
@@ -292,6 +292,7 @@ func (h TransportShipmentHandler) Handle(params shipmentop.TransportShipmentPara
 		return shipmentop.NewTransportShipmentBadRequest()
 	}
 
+	shipment.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	actualPackDate := (time.Time)(*params.Payload.ActualPackDate)
 
 	err = shipment.Pack(actualPackDate)
@@ -316,7 +317,7 @@ func (h TransportShipmentHandler) Handle(params shipmentop.TransportShipmentPara
 		shipment.TareWeight = handlers.PoundPtrFromInt64Ptr(params.Payload.TareWeight)
 	}
 
-	verrs, err := h.DB().ValidateAndUpdate(shipment)
+	verrs, err := models.SaveWithStateTransitions(h.DB(), shipment)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
@@ -351,6 +352,7 @@ func (h DeliverShipmentHandler) Handle(params shipmentop.DeliverShipmentParams)
 		return shipmentop.NewDeliverShipmentBadRequest()
 	}
 
+	shipment.SetTransitionActor(handlers.TransitionActorFromRequest(params.HTTPRequest))
 	actualDeliveryDate := (time.Time)(*params.Payload.ActualDeliveryDate)
 
 	verrs, err := h.shipmentDeliverAndPricer.DeliverAndPriceShipment(actualDeliveryDate, shipment)
@@ -562,6 +564,7 @@ func (h PatchShipmentHandler) Handle(params shipmentop.PatchShipmentParams) midd
 	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
 
 	// authorization
+	var fetchShipment func() (*models.Shipment, error)
 	if session.IsTspUser() {
 		// Check that the TSP user can access the shipment
 		tspUser, fetchTspByIDErr := models.FetchTspUserByID(h.DB(), session.TspUserID)
@@ -569,13 +572,19 @@ func (h PatchShipmentHandler) Handle(params shipmentop.PatchShipmentParams) midd
 			logger.Error("Error retrieving authenticated TSP user", zap.Error(fetchTspByIDErr))
 			return shipmentop.NewGetShipmentForbidden()
 		}
-		shipment, err = models.FetchShipmentByTSP(h.DB(), tspUser.TransportationServiceProviderID, shipmentID)
+		fetchShipment = func() (*models.Shipment, error) {
+			return models.FetchShipmentByTSP(h.DB(), tspUser.TransportationServiceProviderID, shipmentID)
+		}
+		shipment, err = fetchShipment()
 		if err != nil {
 			logger.Error("Error fetching shipment for TSP user", zap.Error(err))
 			return shipmentop.NewPatchShipmentBadRequest()
 		}
 	} else if session.IsOfficeUser() {
-		shipment, err = models.FetchShipment(h.DB(), session, shipmentID)
+		fetchShipment = func() (*models.Shipment, error) {
+			return models.FetchShipment(h.DB(), session, shipmentID)
+		}
+		shipment, err = fetchShipment()
 		if err != nil {
 			logger.Error("Error fetching shipment for office user", zap.Error(err))
 			return shipmentop.NewPatchShipmentBadRequest()
@@ -585,14 +594,23 @@ func (h PatchShipmentHandler) Handle(params shipmentop.PatchShipmentParams) midd
 	}
 
 	patchShipmentWithPayload(shipment, params.Update)
+	shipment.ExpectVersions(handlers.IfMatchVersions(params.IfMatch))
 	verrs, err := models.SaveShipmentAndAddresses(h.DB(), shipment)
 
+	if errors.Cause(err) == models.ErrStaleVersion {
+		logger.Info("Shipment has changed since the version in If-Match", zap.Error(err))
+		current, err := fetchShipment()
+		if err != nil {
+			return handlers.ResponseForError(logger, err)
+		}
+		return shipmentop.NewPatchShipmentPreconditionFailed().WithPayload(payloadForShipmentModel(*current)).WithETag(handlers.ETag(current.Version))
+	}
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
 	}
 
 	shipmentPayload := payloadForShipmentModel(*shipment)
-	return shipmentop.NewPatchShipmentOK().WithPayload(shipmentPayload)
+	return shipmentop.NewPatchShipmentOK().WithPayload(shipmentPayload).WithETag(handlers.ETag(shipment.Version))
 }
 
 // CreateGovBillOfLadingHandler creates a GBL PDF & uploads it as a document associated to a move doc, shipment and move
@@ -641,7 +659,11 @@ func (h CreateGovBillOfLadingHandler) Handle(params shipmentop.CreateGovBillOfLa
 		logger.Error("Failed retrieving the GBL data.", zap.Error(err))
 		return shipmentop.NewCreateGovBillOfLadingExpectationFailed()
 	}
-	formLayout := paperwork.Form1203Layout
+	formLayout, err := paperwork.LoadFormLayoutAsset(paperwork.Form1203LayoutName)
+	if err != nil {
+		logger.Error("Error loading form layout", zap.Error(err))
+		return shipmentop.NewCreateGovBillOfLadingInternalServerError()
+	}
 
 	template, err := paperworkservice.MakeFormTemplate(gbl, gbl.GBLNumber1, formLayout, services.GBL)
 	if err != nil {
@@ -655,6 +677,8 @@ func (h CreateGovBillOfLadingHandler) Handle(params shipmentop.CreateGovBillOfLa
 	}
 
 	uploader := uploaderpkg.NewUploader(h.DB(), logger, h.FileStorer())
+	// The GBL is generated by the app, so it doesn't need to be scanned
+	uploader.SetTrusted(true)
 	upload, verrs, err := uploader.CreateUpload(*tspUser.UserID, &gblFile, uploaderpkg.AllowedTypesPDF)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(logger, verrs, err)
@@ -6,7 +6,6 @@ import (
 	"net/http/httptest"
 	"time"
 
-	"github.com/transcom/mymove/pkg/paperwork"
 	"github.com/transcom/mymove/pkg/rateengine"
 
 	"github.com/transcom/mymove/pkg/dates"
@@ -379,7 +378,7 @@ func (suite *HandlerSuite) TestCreateGovBillOfLadingHandler() {
 	shipment.Move.Orders.TAC = nil
 	suite.MustSave(&shipment.Move.Orders)
 
-	formCreator := paperworkservice.NewFormCreator(context.FileStorer().TempFileSystem(), paperwork.NewFormFiller())
+	formCreator := paperworkservice.NewFormCreator(context.FileStorer().TempFileSystem(), nil)
 	// And: the create gbl handler is called
 	handler := CreateGovBillOfLadingHandler{context, formCreator}
 	response := handler.Handle(params)
@@ -0,0 +1,71 @@
+package publicapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	shipmentop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/shipments"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForStateTransitionModel(t models.StateTransition) *apimessages.StateTransitionPayload {
+	return &apimessages.StateTransitionPayload{
+		ID:             handlers.FmtUUID(t.ID),
+		EntityType:     apimessages.StateTransitionEntityType(t.EntityType),
+		EntityID:       handlers.FmtUUID(t.EntityID),
+		PreviousStatus: handlers.FmtString(t.PreviousStatus),
+		NewStatus:      handlers.FmtString(t.NewStatus),
+		Reason:         t.Reason,
+		ActorUserID:    handlers.FmtUUIDPtr(t.ActorUserID),
+		Application:    t.Application,
+		RequestID:      t.RequestID,
+		CreatedAt:      handlers.FmtDateTime(t.CreatedAt),
+	}
+}
+
+// IndexShipmentTimelineHandler lists the status changes to a shipment
+type IndexShipmentTimelineHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the status changes to a shipment and its SIT requests, for the TSP it was awarded to and office users
+func (h IndexShipmentTimelineHandler) Handle(params shipmentop.IndexShipmentTimelineParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+
+	shipmentID, _ := uuid.FromString(params.ShipmentID.String())
+
+	if session.IsTspUser() {
+		// Check that the TSP user can access the shipment
+		tspUser, err := models.FetchTspUserByID(h.DB(), session.TspUserID)
+		if err != nil {
+			logger.Error("Error retrieving authenticated TSP user", zap.Error(err))
+			return shipmentop.NewIndexShipmentTimelineForbidden()
+		}
+		_, err = models.FetchShipmentByTSP(h.DB(), tspUser.TransportationServiceProviderID, shipmentID)
+		if err != nil {
+			logger.Error("Error fetching shipment for TSP user", zap.Error(err))
+			return shipmentop.NewIndexShipmentTimelineForbidden()
+		}
+	} else if session.IsOfficeUser() {
+		_, err := models.FetchShipment(h.DB(), session, shipmentID)
+		if err != nil {
+			return handlers.ResponseForError(logger, err)
+		}
+	} else {
+		return shipmentop.NewIndexShipmentTimelineForbidden()
+	}
+
+	transitions, err := models.FetchStateTransitionsForShipment(h.DB(), shipmentID)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	payload := make(apimessages.StateTransitions, len(transitions))
+	for i, transition := range transitions {
+		payload[i] = payloadForStateTransitionModel(transition)
+	}
+	return shipmentop.NewIndexShipmentTimelineOK().WithPayload(payload)
+}
@@ -0,0 +1,76 @@
+package publicapi
+
+import (
+	"fmt"
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	shipmentop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/shipments"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestIndexShipmentTimelineHandler() {
+	numTspUsers := 1
+	numShipments := 1
+	numShipmentOfferSplit := []int{1}
+	status := []models.ShipmentStatus{models.ShipmentStatusAPPROVED}
+	tspUsers, shipments, _, err := testdatagen.CreateShipmentOfferData(suite.DB(), numTspUsers, numShipments, numShipmentOfferSplit, status, models.SelectedMoveTypeHHG)
+	suite.NoError(err)
+
+	tspUser := tspUsers[0]
+	shipment := shipments[0]
+	context := handlers.NewHandlerContext(suite.DB(), suite.TestLogger())
+
+	// Transport the shipment, so that it has a status change
+	req := httptest.NewRequest("POST", fmt.Sprintf("/shipments/%s/transport", shipment.ID.String()), nil)
+	req = suite.AuthenticateTspRequest(req, tspUser)
+	actualPackDate := shipment.BookDate.AddDate(0, 0, 1)
+	actualPickupDate := actualPackDate.AddDate(0, 0, 1)
+	transportResponse := TransportShipmentHandler{context}.Handle(shipmentop.TransportShipmentParams{
+		HTTPRequest: req,
+		ShipmentID:  *handlers.FmtUUID(shipment.ID),
+		Payload: &apimessages.TransportPayload{
+			ActualPackDate:   handlers.FmtDatePtr(&actualPackDate),
+			ActualPickupDate: handlers.FmtDatePtr(&actualPickupDate),
+			NetWeight:        swag.Int64(2000),
+			GrossWeight:      swag.Int64(3000),
+			TareWeight:       swag.Int64(1000),
+		},
+	})
+	suite.Assertions.IsType(&shipmentop.TransportShipmentOK{}, transportResponse)
+
+	handler := IndexShipmentTimelineHandler{context}
+	req = httptest.NewRequest("GET", fmt.Sprintf("/shipments/%s/timeline", shipment.ID.String()), nil)
+	req = suite.AuthenticateTspRequest(req, tspUser)
+	params := shipmentop.IndexShipmentTimelineParams{
+		HTTPRequest: req,
+		ShipmentID:  *handlers.FmtUUID(shipment.ID),
+	}
+
+	response := handler.Handle(params)
+	suite.Assertions.IsType(&shipmentop.IndexShipmentTimelineOK{}, response)
+	payload := response.(*shipmentop.IndexShipmentTimelineOK).Payload
+	if suite.Len(payload, 1) {
+		suite.Equal(apimessages.StateTransitionEntityTypeSHIPMENT, payload[0].EntityType)
+		suite.Equal(shipment.ID.String(), payload[0].EntityID.String())
+		suite.Equal(string(models.ShipmentStatusAPPROVED), *payload[0].PreviousStatus)
+		suite.Equal(string(models.ShipmentStatusINTRANSIT), *payload[0].NewStatus)
+		suite.Equal(tspUser.UserID.String(), payload[0].ActorUserID.String())
+	}
+
+	// Office users can see the timeline too
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.DB())
+	params.HTTPRequest = suite.AuthenticateOfficeRequest(req, officeUser)
+	response = handler.Handle(params)
+	suite.Assertions.IsType(&shipmentop.IndexShipmentTimelineOK{}, response)
+
+	// TSPs that weren't awarded the shipment can't
+	otherTspUser := testdatagen.MakeDefaultTspUser(suite.DB())
+	params.HTTPRequest = suite.AuthenticateTspRequest(req, otherTspUser)
+	response = handler.Handle(params)
+	suite.Assertions.IsType(&shipmentop.IndexShipmentTimelineForbidden{}, response)
+}
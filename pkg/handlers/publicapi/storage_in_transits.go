@@ -7,12 +7,14 @@ import (
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/gen/apimessages"
 	sitop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/storage_in_transits"
 	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/trace"
 )
 
 func payloadForStorageInTransitModel(s *models.StorageInTransit) *apimessages.StorageInTransit {
@@ -40,6 +42,7 @@ func payloadForStorageInTransitModel(s *models.StorageInTransit) *apimessages.St
 		ActualStartDate:     handlers.FmtDatePtr(s.ActualStartDate),
 		OutDate:             handlers.FmtDatePtr(s.OutDate),
 		SitNumber:           s.SITNumber,
+		WeightLbs:           handlers.FmtPoundPtr(s.WeightLbs),
 	}
 }
 
@@ -123,7 +126,7 @@ func (h ApproveStorageInTransitHandler) Handle(params sitop.ApproveStorageInTran
 		return handlers.ResponseForError(logger, err)
 	}
 
-	storageInTransit, verrs, err := h.storageInTransitApprover.ApproveStorageInTransit(*payload, shipmentID, session, storageInTransitID)
+	storageInTransit, verrs, err := h.storageInTransitApprover.ApproveStorageInTransit(*payload, shipmentID, session, trace.FromContext(params.HTTPRequest.Context()), storageInTransitID)
 
 	if err != nil || verrs.HasAny() {
 		logger.Error(fmt.Sprintf("SIT approval failed for ID: %s on shipment: %s", storageInTransitID, shipmentID), zap.Error(err), zap.Error(verrs))
@@ -157,7 +160,7 @@ func (h DenyStorageInTransitHandler) Handle(params sitop.DenyStorageInTransitPar
 		return handlers.ResponseForError(logger, err)
 	}
 
-	storageInTransit, verrs, err := h.storageInTransitDenier.DenyStorageInTransit(*payload, shipmentID, session, storageInTransitID)
+	storageInTransit, verrs, err := h.storageInTransitDenier.DenyStorageInTransit(*payload, shipmentID, session, trace.FromContext(params.HTTPRequest.Context()), storageInTransitID)
 
 	if err != nil || verrs.HasAny() {
 		logger.Error(fmt.Sprintf("SIT denial failed for ID: %s on shipment: %s", storageInTransitID, shipmentID), zap.Error(err), zap.Error(verrs))
@@ -187,7 +190,7 @@ func (h InSitStorageInTransitHandler) Handle(params sitop.InSitStorageInTransitP
 	}
 	inSitPayload := params.StorageInTransitInSitPayload
 
-	storageInTransit, verrs, err := h.storageInTransitInSITPlacer.PlaceIntoSITStorageInTransit(*inSitPayload, shipmentID, session, storageInTransitID)
+	storageInTransit, verrs, err := h.storageInTransitInSITPlacer.PlaceIntoSITStorageInTransit(*inSitPayload, shipmentID, session, trace.FromContext(params.HTTPRequest.Context()), storageInTransitID)
 
 	if err != nil || verrs.HasAny() {
 		logger.Error(fmt.Sprintf("Place into SIT failed for ID: %s on shipment: %s", storageInTransitID, shipmentID), zap.Error(err), zap.Error(verrs))
@@ -219,7 +222,7 @@ func (h ReleaseStorageInTransitHandler) Handle(params sitop.ReleaseStorageInTran
 		return handlers.ResponseForError(logger, err)
 	}
 
-	storageInTransit, verrs, err := h.releaseStorageInTransit.ReleaseStorageInTransit(*payload, shipmentID, session, storageInTransitID)
+	storageInTransit, verrs, err := h.releaseStorageInTransit.ReleaseStorageInTransit(*payload, shipmentID, session, trace.FromContext(params.HTTPRequest.Context()), storageInTransitID)
 
 	if err != nil || verrs.HasAny() {
 		logger.Error(fmt.Sprintf("Release SIT failed for ID: %s on shipment: %s", storageInTransitID, shipmentID), zap.Error(err), zap.Error(verrs))
@@ -231,6 +234,36 @@ func (h ReleaseStorageInTransitHandler) Handle(params sitop.ReleaseStorageInTran
 
 }
 
+// AttemptDeliveryStorageInTransitHandler records a failed attempt to pick up or deliver an existing storage in transit
+type AttemptDeliveryStorageInTransitHandler struct {
+	handlers.HandlerContext
+	storageInTransitDeliveryAttempter services.StorageInTransitDeliveryAttempter
+}
+
+// Handle handles the handling
+// This is meant to add the charges for a failed pickup or delivery attempt and return the storage in transit in a payload.
+func (h AttemptDeliveryStorageInTransitHandler) Handle(params sitop.AttemptDeliveryStorageInTransitParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+	shipmentID, err := uuid.FromString(params.ShipmentID.String())
+	storageInTransitID, err := uuid.FromString(params.StorageInTransitID.String())
+	payload := params.StorageInTransitDeliveryAttemptPayload
+
+	if err != nil {
+		logger.Error("UUID Parsing", zap.Error(err))
+		return handlers.ResponseForError(logger, err)
+	}
+
+	storageInTransit, verrs, err := h.storageInTransitDeliveryAttempter.AttemptDeliveryStorageInTransit(*payload, shipmentID, session, storageInTransitID)
+
+	if err != nil || verrs.HasAny() {
+		logger.Error(fmt.Sprintf("Attempted delivery of SIT failed for ID: %s on shipment: %s", storageInTransitID, shipmentID), zap.Error(err), zap.Error(verrs))
+		return handlers.ResponseForVErrors(logger, verrs, err)
+	}
+
+	returnPayload := payloadForStorageInTransitModel(storageInTransit)
+	return sitop.NewAttemptDeliveryStorageInTransitOK().WithPayload(returnPayload)
+}
+
 // PatchStorageInTransitHandler updates an existing Storage In Transit entry
 type PatchStorageInTransitHandler struct {
 	handlers.HandlerContext
@@ -251,8 +284,12 @@ func (h PatchStorageInTransitHandler) Handle(params sitop.PatchStorageInTransitP
 		return handlers.ResponseForError(logger, err)
 	}
 
-	storageInTransit, verrs, err := h.patchStorageInTransit.PatchStorageInTransit(*payload, shipmentID, storageInTransitID, session)
+	storageInTransit, verrs, err := h.patchStorageInTransit.PatchStorageInTransit(*payload, shipmentID, storageInTransitID, handlers.IfMatchVersions(params.IfMatch), session)
 
+	if errors.Cause(err) == models.ErrStaleVersion {
+		logger.Info("Storage in transit has changed since the version in If-Match", zap.Error(err))
+		return sitop.NewPatchStorageInTransitPreconditionFailed().WithPayload(payloadForStorageInTransitModel(storageInTransit)).WithETag(handlers.ETag(storageInTransit.Version))
+	}
 	if err != nil || verrs.HasAny() {
 		logger.Error(fmt.Sprintf("Patch SIT failed for ID: %s on shipment: %s", storageInTransitID, shipmentID), zap.Error(err), zap.Error(verrs))
 		return handlers.ResponseForVErrors(logger, verrs, err)
@@ -260,7 +297,7 @@ func (h PatchStorageInTransitHandler) Handle(params sitop.PatchStorageInTransitP
 
 	storageInTransitPayload := payloadForStorageInTransitModel(storageInTransit)
 
-	return sitop.NewPatchStorageInTransitOK().WithPayload(storageInTransitPayload)
+	return sitop.NewPatchStorageInTransitOK().WithPayload(storageInTransitPayload).WithETag(handlers.ETag(storageInTransit.Version))
 }
 
 // GetStorageInTransitHandler gets a single Storage In Transit based on its own ID
@@ -288,7 +325,7 @@ func (h GetStorageInTransitHandler) Handle(params sitop.GetStorageInTransitParam
 	}
 
 	storageInTransitPayload := payloadForStorageInTransitModel(storageInTransit)
-	return sitop.NewGetStorageInTransitOK().WithPayload(storageInTransitPayload)
+	return sitop.NewGetStorageInTransitOK().WithPayload(storageInTransitPayload).WithETag(handlers.ETag(storageInTransit.Version))
 }
 
 // DeleteStorageInTransitHandler deletes a Storage in Transit based on the provided ID
@@ -241,9 +241,10 @@ func (suite *HandlerSuite) TestApproveStorageInTransitHandler() {
 
 	storageInTransitApprover := &mocks.StorageInTransitApprover{}
 
+	context := handlers.NewHandlerContext(suite.DB(), suite.TestLogger())
+	context.SetNotificationSender(suite.TestNotificationSender())
 	handler := ApproveStorageInTransitHandler{
-		handlers.NewHandlerContext(suite.DB(),
-			suite.TestLogger()),
+		context,
 		storageInTransitApprover,
 	}
 	// Happy path
@@ -251,6 +252,7 @@ func (suite *HandlerSuite) TestApproveStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(&storageInTransit, validate.NewErrors(), nil).Once()
 
@@ -266,6 +268,7 @@ func (suite *HandlerSuite) TestApproveStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(nil, validate.NewErrors(), expectedError).Once()
 
@@ -282,6 +285,7 @@ func (suite *HandlerSuite) TestApproveStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(nil, validate.NewErrors(), expectedError).Once()
 
@@ -335,6 +339,7 @@ func (suite *HandlerSuite) TestDenyStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(&storageInTransit, validate.NewErrors(), nil).Once()
 
@@ -350,6 +355,7 @@ func (suite *HandlerSuite) TestDenyStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(nil, validate.NewErrors(), expectedError).Once()
 
@@ -366,6 +372,7 @@ func (suite *HandlerSuite) TestDenyStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(nil, validate.NewErrors(), expectedError).Once()
 
@@ -420,6 +427,7 @@ func (suite *HandlerSuite) TestInSitStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(&storageInTransit, validate.NewErrors(), nil).Once()
 
@@ -435,6 +443,7 @@ func (suite *HandlerSuite) TestInSitStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(nil, validate.NewErrors(), expectedError).Once()
 
@@ -451,6 +460,7 @@ func (suite *HandlerSuite) TestInSitStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(nil, validate.NewErrors(), expectedError).Once()
 
@@ -503,6 +513,7 @@ func (suite *HandlerSuite) TestReleaseStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(&storageInTransit, validate.NewErrors(), nil).Once()
 
@@ -518,6 +529,7 @@ func (suite *HandlerSuite) TestReleaseStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(nil, validate.NewErrors(), expectedError).Once()
 
@@ -534,6 +546,7 @@ func (suite *HandlerSuite) TestReleaseStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		auth.SessionFromRequestContext(params.HTTPRequest),
+		"",
 		storageInTransitID,
 	).Return(nil, validate.NewErrors(), expectedError).Once()
 
@@ -582,6 +595,7 @@ func (suite *HandlerSuite) TestPatchStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		storageInTransitID,
+		[]int(nil),
 		auth.SessionFromRequestContext(params.HTTPRequest),
 	).Return(&storageInTransit, validate.NewErrors(), nil).Once()
 
@@ -602,6 +616,7 @@ func (suite *HandlerSuite) TestPatchStorageInTransitHandler() {
 		payload,
 		shipmentID,
 		storageInTransitID,
+		[]int(nil),
 		auth.SessionFromRequestContext(params.HTTPRequest),
 	).Return(nil, validate.NewErrors(), expectedError).Once()
 
@@ -646,6 +661,7 @@ func (suite *HandlerSuite) TestDeleteStorageInTransitHandler() {
 	storageInTransitDeleter.On("DeleteStorageInTransit",
 		shipmentID,
 		storageInTransitID,
+		[]int(nil),
 		auth.SessionFromRequestContext(params.HTTPRequest),
 	).Return(&storageInTransit, nil).Once()
 
@@ -665,6 +681,7 @@ func (suite *HandlerSuite) TestDeleteStorageInTransitHandler() {
 	storageInTransitDeleter.On("DeleteStorageInTransit",
 		shipmentID,
 		storageInTransitID,
+		[]int(nil),
 		auth.SessionFromRequestContext(params.HTTPRequest),
 	).Return(nil, expectedError).Once()
 
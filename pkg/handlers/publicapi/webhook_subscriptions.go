@@ -0,0 +1,285 @@
+package publicapi
+
+import (
+	"context"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	webhookop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/webhook_subscriptions"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/webhooks"
+)
+
+// webhookDeliveryLogLimit is the most deliveries returned in a subscription's delivery log
+const webhookDeliveryLogLimit = 100
+
+func payloadForWebhookSubscriptionModel(s models.WebhookSubscription) *apimessages.WebhookSubscriptionPayload {
+	eventTypes := make([]apimessages.WebhookEventType, len(s.EventTypes))
+	for i, eventType := range s.EventTypes {
+		eventTypes[i] = apimessages.WebhookEventType(eventType)
+	}
+	return &apimessages.WebhookSubscriptionPayload{
+		ID:         handlers.FmtUUID(s.ID),
+		URL:        handlers.FmtString(s.URL),
+		EventTypes: eventTypes,
+		Active:     handlers.FmtBool(s.Active),
+		CreatedAt:  handlers.FmtDateTime(s.CreatedAt),
+		UpdatedAt:  handlers.FmtDateTime(s.UpdatedAt),
+	}
+}
+
+func payloadForWebhookDeliveryModel(d models.WebhookDelivery) *apimessages.WebhookDeliveryPayload {
+	var lastResponseStatus *int64
+	if d.LastResponseStatus != nil {
+		lastResponseStatus = handlers.FmtInt64(int64(*d.LastResponseStatus))
+	}
+	return &apimessages.WebhookDeliveryPayload{
+		ID:                 handlers.FmtUUID(d.ID),
+		EventID:            handlers.FmtUUID(d.EventID),
+		EventType:          apimessages.WebhookEventType(d.EventType),
+		ShipmentID:         handlers.FmtUUIDPtr(d.ShipmentID),
+		Status:             apimessages.WebhookDeliveryStatus(d.Status),
+		Attempts:           handlers.FmtInt64(int64(d.Attempts)),
+		NextAttemptAt:      handlers.FmtDateTime(d.NextAttemptAt),
+		LastResponseStatus: lastResponseStatus,
+		LastError:          d.LastError,
+		DeliveredAt:        handlers.FmtDateTimePtr(d.DeliveredAt),
+		CreatedAt:          handlers.FmtDateTime(d.CreatedAt),
+	}
+}
+
+func eventTypesFromPayload(eventTypes []apimessages.WebhookEventType) []string {
+	s := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		s[i] = string(eventType)
+	}
+	return s
+}
+
+// tspIDForSession returns the TSP of the TSP user of session. Webhook subscriptions belong to TSPs, so only TSP
+// users can manage them.
+func tspIDForSession(db *pop.Connection, session *auth.Session) (uuid.UUID, error) {
+	if !session.IsTspUser() {
+		return uuid.Nil, models.ErrFetchForbidden
+	}
+	tspUser, err := models.FetchTspUserByID(db, session.TspUserID)
+	if err != nil {
+		return uuid.Nil, models.ErrFetchForbidden
+	}
+	return tspUser.TransportationServiceProviderID, nil
+}
+
+// validateWebhookDestination validates a subscription and checks that its URL only resolves to public addresses,
+// so that subscriptions can't be used to make requests into our own network
+func validateWebhookDestination(ctx context.Context, db *pop.Connection, checker webhooks.DestinationChecker, subscription *models.WebhookSubscription) (*validate.Errors, error) {
+	verrs, err := subscription.Validate(db)
+	if err != nil || verrs.HasAny() {
+		return verrs, err
+	}
+	if err := checker.CheckURL(ctx, subscription.URL); err != nil {
+		verrs.Add("url", "URL must only resolve to public addresses")
+	}
+	return verrs, nil
+}
+
+// IndexWebhookSubscriptionsHandler lists the TSP's webhook subscriptions
+type IndexWebhookSubscriptionsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the webhook subscriptions of the TSP user's TSP
+func (h IndexWebhookSubscriptionsHandler) Handle(params webhookop.IndexWebhookSubscriptionsParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+
+	tspID, err := tspIDForSession(h.DB(), session)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	subscriptions, err := models.FetchWebhookSubscriptionsForTSP(h.DB(), tspID)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	payload := make(apimessages.WebhookSubscriptions, len(subscriptions))
+	for i, subscription := range subscriptions {
+		payload[i] = payloadForWebhookSubscriptionModel(subscription)
+	}
+	return webhookop.NewIndexWebhookSubscriptionsOK().WithPayload(payload)
+}
+
+// CreateWebhookSubscriptionHandler subscribes an endpoint to the TSP's shipment events
+type CreateWebhookSubscriptionHandler struct {
+	handlers.HandlerContext
+	destinationChecker webhooks.DestinationChecker
+}
+
+// Handle creates a webhook subscription with a new secret, which is only ever returned here
+func (h CreateWebhookSubscriptionHandler) Handle(params webhookop.CreateWebhookSubscriptionParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+
+	tspID, err := tspIDForSession(h.DB(), session)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	payload := params.WebhookSubscription
+	subscription := models.WebhookSubscription{
+		TransportationServiceProviderID: tspID,
+		URL:                             *payload.URL,
+		EventTypes:                      eventTypesFromPayload(payload.EventTypes),
+		Secret:                          secret,
+		Active:                          true,
+	}
+	verrs, err := validateWebhookDestination(params.HTTPRequest.Context(), h.DB(), h.destinationChecker, &subscription)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(logger, verrs, err)
+	}
+	verrs, err = h.DB().ValidateAndCreate(&subscription)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(logger, verrs, err)
+	}
+	logger.Info("Created webhook subscription",
+		zap.String("webhook_subscription_id", subscription.ID.String()),
+		zap.String("transportation_service_provider_id", tspID.String()))
+
+	subscriptionPayload := payloadForWebhookSubscriptionModel(subscription)
+	subscriptionPayload.Secret = &subscription.Secret
+	return webhookop.NewCreateWebhookSubscriptionCreated().WithPayload(subscriptionPayload)
+}
+
+// GetWebhookSubscriptionHandler gets one of the TSP's webhook subscriptions
+type GetWebhookSubscriptionHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle gets a webhook subscription of the TSP user's TSP
+func (h GetWebhookSubscriptionHandler) Handle(params webhookop.GetWebhookSubscriptionParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+	subscriptionID, _ := uuid.FromString(params.WebhookSubscriptionID.String())
+
+	tspID, err := tspIDForSession(h.DB(), session)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	subscription, err := models.FetchWebhookSubscriptionForTSP(h.DB(), tspID, subscriptionID)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+	return webhookop.NewGetWebhookSubscriptionOK().WithPayload(payloadForWebhookSubscriptionModel(*subscription))
+}
+
+// PatchWebhookSubscriptionHandler updates one of the TSP's webhook subscriptions
+type PatchWebhookSubscriptionHandler struct {
+	handlers.HandlerContext
+	destinationChecker webhooks.DestinationChecker
+}
+
+// Handle changes the URL, event types or active flag of a webhook subscription of the TSP user's TSP
+func (h PatchWebhookSubscriptionHandler) Handle(params webhookop.PatchWebhookSubscriptionParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+	subscriptionID, _ := uuid.FromString(params.WebhookSubscriptionID.String())
+
+	tspID, err := tspIDForSession(h.DB(), session)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	subscription, err := models.FetchWebhookSubscriptionForTSP(h.DB(), tspID, subscriptionID)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	payload := params.WebhookSubscription
+	if payload.URL != nil && *payload.URL != subscription.URL {
+		subscription.URL = *payload.URL
+		verrs, err := validateWebhookDestination(params.HTTPRequest.Context(), h.DB(), h.destinationChecker, subscription)
+		if err != nil || verrs.HasAny() {
+			return handlers.ResponseForVErrors(logger, verrs, err)
+		}
+	}
+	if payload.EventTypes != nil {
+		subscription.EventTypes = eventTypesFromPayload(payload.EventTypes)
+	}
+	if payload.Active != nil {
+		subscription.Active = *payload.Active
+	}
+
+	verrs, err := h.DB().ValidateAndUpdate(subscription)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(logger, verrs, err)
+	}
+	return webhookop.NewPatchWebhookSubscriptionOK().WithPayload(payloadForWebhookSubscriptionModel(*subscription))
+}
+
+// DeleteWebhookSubscriptionHandler deletes one of the TSP's webhook subscriptions
+type DeleteWebhookSubscriptionHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle deletes a webhook subscription of the TSP user's TSP, along with its delivery log
+func (h DeleteWebhookSubscriptionHandler) Handle(params webhookop.DeleteWebhookSubscriptionParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+	subscriptionID, _ := uuid.FromString(params.WebhookSubscriptionID.String())
+
+	tspID, err := tspIDForSession(h.DB(), session)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	subscription, err := models.FetchWebhookSubscriptionForTSP(h.DB(), tspID, subscriptionID)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	if err := h.DB().Destroy(subscription); err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+	logger.Info("Deleted webhook subscription", zap.String("webhook_subscription_id", subscription.ID.String()))
+	return webhookop.NewDeleteWebhookSubscriptionOK().WithPayload(payloadForWebhookSubscriptionModel(*subscription))
+}
+
+// IndexWebhookDeliveriesHandler lists the delivery log of one of the TSP's webhook subscriptions
+type IndexWebhookDeliveriesHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the most recent deliveries to a webhook subscription of the TSP user's TSP
+func (h IndexWebhookDeliveriesHandler) Handle(params webhookop.IndexWebhookDeliveriesParams) middleware.Responder {
+	session, logger := h.SessionAndLoggerFromRequest(params.HTTPRequest)
+	subscriptionID, _ := uuid.FromString(params.WebhookSubscriptionID.String())
+
+	tspID, err := tspIDForSession(h.DB(), session)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	subscription, err := models.FetchWebhookSubscriptionForTSP(h.DB(), tspID, subscriptionID)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	deliveries, err := models.FetchWebhookDeliveriesForSubscription(h.DB(), subscription.ID, webhookDeliveryLogLimit)
+	if err != nil {
+		return handlers.ResponseForError(logger, err)
+	}
+
+	payload := make(apimessages.WebhookDeliveries, len(deliveries))
+	for i, delivery := range deliveries {
+		payload[i] = payloadForWebhookDeliveryModel(delivery)
+	}
+	return webhookop.NewIndexWebhookDeliveriesOK().WithPayload(payload)
+}
@@ -0,0 +1,131 @@
+package publicapi
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	webhookop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/webhook_subscriptions"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/webhooks"
+)
+
+func (suite *HandlerSuite) TestWebhookSubscriptionHandlers() {
+	tspUser := testdatagen.MakeDefaultTspUser(suite.DB())
+	checker := webhooks.DestinationChecker{
+		LookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			if host == "intranet.example.com" {
+				return []net.IPAddr{{IP: net.ParseIP("10.0.0.5")}}, nil
+			}
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+		},
+	}
+	context := handlers.NewHandlerContext(suite.DB(), suite.TestLogger())
+
+	// A TSP user subscribes an endpoint, and is given the secret the events are signed with
+	req := httptest.NewRequest("POST", "/webhook_subscriptions", nil)
+	req = suite.AuthenticateTspRequest(req, tspUser)
+	createParams := webhookop.CreateWebhookSubscriptionParams{
+		HTTPRequest: req,
+		WebhookSubscription: &apimessages.CreateWebhookSubscriptionPayload{
+			URL:        swag.String("https://tsp.example.com/milmove/events"),
+			EventTypes: []apimessages.WebhookEventType{apimessages.WebhookEventTypeSHIPMENTAWARDED},
+		},
+	}
+	response := CreateWebhookSubscriptionHandler{context, checker}.Handle(createParams)
+	suite.Assertions.IsType(&webhookop.CreateWebhookSubscriptionCreated{}, response)
+	created := response.(*webhookop.CreateWebhookSubscriptionCreated).Payload
+	suite.True(*created.Active)
+	suite.NotEmpty(*created.Secret)
+	subscriptionID := *created.ID
+
+	// Only https endpoints can be subscribed
+	createParams.WebhookSubscription.URL = swag.String("http://tsp.example.com/milmove/events")
+	response = CreateWebhookSubscriptionHandler{context, checker}.Handle(createParams)
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+
+	// Nor endpoints on private networks
+	createParams.WebhookSubscription.URL = swag.String("https://intranet.example.com/milmove/events")
+	response = CreateWebhookSubscriptionHandler{context, checker}.Handle(createParams)
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+	createParams.WebhookSubscription.URL = swag.String("https://169.254.169.254/latest/meta-data")
+	response = CreateWebhookSubscriptionHandler{context, checker}.Handle(createParams)
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+
+	// The secret isn't returned again
+	req = httptest.NewRequest("GET", "/webhook_subscriptions", nil)
+	req = suite.AuthenticateTspRequest(req, tspUser)
+	response = IndexWebhookSubscriptionsHandler{context}.Handle(webhookop.IndexWebhookSubscriptionsParams{HTTPRequest: req})
+	suite.Assertions.IsType(&webhookop.IndexWebhookSubscriptionsOK{}, response)
+	subscriptions := response.(*webhookop.IndexWebhookSubscriptionsOK).Payload
+	if suite.Len(subscriptions, 1) {
+		suite.Equal(subscriptionID, *subscriptions[0].ID)
+		suite.Nil(subscriptions[0].Secret)
+	}
+
+	// The TSP can pause the subscription and add event types
+	req = httptest.NewRequest("PATCH", "/webhook_subscriptions/id", nil)
+	req = suite.AuthenticateTspRequest(req, tspUser)
+	response = PatchWebhookSubscriptionHandler{context, checker}.Handle(webhookop.PatchWebhookSubscriptionParams{
+		HTTPRequest:           req,
+		WebhookSubscriptionID: subscriptionID,
+		WebhookSubscription: &apimessages.PatchWebhookSubscriptionPayload{
+			EventTypes: []apimessages.WebhookEventType{
+				apimessages.WebhookEventTypeSHIPMENTAWARDED,
+				apimessages.WebhookEventTypeSTORAGEINTRANSITAPPROVED,
+			},
+			Active: swag.Bool(false),
+		},
+	})
+	suite.Assertions.IsType(&webhookop.PatchWebhookSubscriptionOK{}, response)
+	patched := response.(*webhookop.PatchWebhookSubscriptionOK).Payload
+	suite.False(*patched.Active)
+	suite.Len(patched.EventTypes, 2)
+	suite.Equal(*created.URL, *patched.URL)
+
+	// The delivery log shows the events sent to the subscription
+	subscription, err := models.FetchWebhookSubscriptionForTSP(suite.DB(), tspUser.TransportationServiceProviderID, uuid.Must(uuid.FromString(subscriptionID.String())))
+	suite.NoError(err)
+	delivery := testdatagen.MakeWebhookDelivery(suite.DB(), testdatagen.Assertions{WebhookSubscription: *subscription})
+	req = httptest.NewRequest("GET", "/webhook_subscriptions/id/deliveries", nil)
+	req = suite.AuthenticateTspRequest(req, tspUser)
+	response = IndexWebhookDeliveriesHandler{context}.Handle(webhookop.IndexWebhookDeliveriesParams{
+		HTTPRequest:           req,
+		WebhookSubscriptionID: subscriptionID,
+	})
+	suite.Assertions.IsType(&webhookop.IndexWebhookDeliveriesOK{}, response)
+	deliveries := response.(*webhookop.IndexWebhookDeliveriesOK).Payload
+	if suite.Len(deliveries, 1) {
+		suite.Equal(delivery.ID.String(), deliveries[0].ID.String())
+		suite.Equal(apimessages.WebhookDeliveryStatusPENDING, deliveries[0].Status)
+	}
+
+	// Other TSPs can't see the subscription, and office users can't manage subscriptions
+	otherTspUser := testdatagen.MakeDefaultTspUser(suite.DB())
+	req = httptest.NewRequest("GET", "/webhook_subscriptions/id", nil)
+	req = suite.AuthenticateTspRequest(req, otherTspUser)
+	getParams := webhookop.GetWebhookSubscriptionParams{HTTPRequest: req, WebhookSubscriptionID: subscriptionID}
+	suite.CheckResponseForbidden(GetWebhookSubscriptionHandler{context}.Handle(getParams))
+
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.DB())
+	getParams.HTTPRequest = suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/webhook_subscriptions/id", nil), officeUser)
+	suite.CheckResponseForbidden(GetWebhookSubscriptionHandler{context}.Handle(getParams))
+
+	// The TSP can delete the subscription
+	req = httptest.NewRequest("DELETE", "/webhook_subscriptions/id", nil)
+	req = suite.AuthenticateTspRequest(req, tspUser)
+	response = DeleteWebhookSubscriptionHandler{context}.Handle(webhookop.DeleteWebhookSubscriptionParams{
+		HTTPRequest:           req,
+		WebhookSubscriptionID: subscriptionID,
+	})
+	suite.Assertions.IsType(&webhookop.DeleteWebhookSubscriptionOK{}, response)
+
+	getParams.HTTPRequest = suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/webhook_subscriptions/id", nil), tspUser)
+	suite.CheckResponseNotFound(GetWebhookSubscriptionHandler{context}.Handle(getParams))
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/trace"
+)
+
+// TransitionActorFromRequest returns the user making a request, and the request's trace ID, to record against the
+// status changes the request makes
+func TransitionActorFromRequest(r *http.Request) models.TransitionActor {
+	return models.NewTransitionActor(auth.SessionFromRequestContext(r), trace.FromContext(r.Context()))
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/pop/slices"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// maxQualityBands is the most quality bands a TDL can be split into, as defined in DTR 402
+const maxQualityBands = 4
+
+// AwardQueuePolicy holds the award queue settings for a date range, either for every TDL or for a single TDL.
+// Policies are never edited in place: a new version is created instead, and when several policies cover the
+// same date the highest version wins.
+type AwardQueuePolicy struct {
+	ID                        uuid.UUID  `json:"id" db:"id"`
+	CreatedAt                 time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt                 time.Time  `json:"updated_at" db:"updated_at"`
+	Version                   int        `json:"version" db:"version"`
+	TrafficDistributionListID *uuid.UUID `json:"traffic_distribution_list_id" db:"traffic_distribution_list_id"`
+	EffectiveStartDate        time.Time  `json:"effective_start_date" db:"effective_start_date"`
+	EffectiveEndDate          time.Time  `json:"effective_end_date" db:"effective_end_date"`
+	// MinimumPerformanceScore (MPS) is the lowest BVS a TSP can have and still be assigned shipments
+	MinimumPerformanceScore float64 `json:"minimum_performance_score" db:"minimum_performance_score"`
+	// OffersPerQualityBand is the number of shipments offered per round to each quality band, starting with
+	// band 1. Its length is the number of quality bands TSPs are split into.
+	OffersPerQualityBand slices.Int `json:"offers_per_quality_band" db:"offers_per_quality_band"`
+}
+
+// AwardQueuePolicies is a slice of AwardQueuePolicy objects
+type AwardQueuePolicies []AwardQueuePolicy
+
+// DefaultAwardQueuePolicy returns the policy used when no stored policy covers a date. It does not exclude
+// any TSPs, and offers one shipment per round to each of four quality bands (the ratios used for the B&M pilot).
+func DefaultAwardQueuePolicy() AwardQueuePolicy {
+	return AwardQueuePolicy{
+		Version:                 0,
+		MinimumPerformanceScore: 0,
+		OffersPerQualityBand:    slices.Int{1, 1, 1, 1},
+	}
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (a *AwardQueuePolicy) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.IntIsGreaterThan{Field: a.Version, Name: "Version", Compared: 0},
+		&validators.TimeIsPresent{Field: a.EffectiveStartDate, Name: "EffectiveStartDate"},
+		&validators.TimeIsPresent{Field: a.EffectiveEndDate, Name: "EffectiveEndDate"},
+		&validators.TimeIsBeforeTime{FirstTime: a.EffectiveStartDate, FirstName: "EffectiveStartDate",
+			SecondTime: a.EffectiveEndDate, SecondName: "EffectiveEndDate"},
+		&performanceScoreIsValid{Field: a.MinimumPerformanceScore, Name: "MinimumPerformanceScore"},
+		&offersPerQualityBandIsValid{Field: a.OffersPerQualityBand, Name: "OffersPerQualityBand"},
+	), nil
+}
+
+type performanceScoreIsValid struct {
+	Field float64
+	Name  string
+}
+
+// IsValid checks that the score is between 0 and 100, the range of Best Value Scores (see DTR 403)
+func (v *performanceScoreIsValid) IsValid(errors *validate.Errors) {
+	if v.Field < 0 || v.Field > 100 {
+		errors.Add(validators.GenerateKey(v.Name), fmt.Sprintf("%s must be between 0 and 100, got %v", v.Name, v.Field))
+	}
+}
+
+type offersPerQualityBandIsValid struct {
+	Field slices.Int
+	Name  string
+}
+
+// IsValid checks that there are between 1 and 4 quality bands, each offered at least one shipment per round
+func (v *offersPerQualityBandIsValid) IsValid(errors *validate.Errors) {
+	if len(v.Field) < 1 || len(v.Field) > maxQualityBands {
+		errors.Add(validators.GenerateKey(v.Name), fmt.Sprintf("%s must have between 1 and %d quality bands", v.Name, maxQualityBands))
+		return
+	}
+	for i, offers := range v.Field {
+		if offers < 1 {
+			errors.Add(validators.GenerateKey(v.Name), fmt.Sprintf("%s for quality band %d must be at least 1", v.Name, i+1))
+		}
+	}
+}
+
+// NumQualityBands returns the number of quality bands TSPs are split into under this policy
+func (a AwardQueuePolicy) NumQualityBands() int {
+	return len(a.OffersPerQualityBand)
+}
+
+// OffersForQualityBand returns the number of shipments offered per round to a 1-based quality band
+func (a AwardQueuePolicy) OffersForQualityBand(qualityBand int) int {
+	return a.OffersPerQualityBand[qualityBand-1]
+}
+
+// StoredID returns the ID of the policy, or nil for the default policy, which isn't stored in the database
+func (a AwardQueuePolicy) StoredID() *uuid.UUID {
+	if a.ID == uuid.Nil {
+		return nil
+	}
+	id := a.ID
+	return &id
+}
+
+// FetchActiveAwardQueuePolicy returns the policy in effect for a TDL on a date. A policy for the TDL takes
+// precedence over one for all TDLs, and the default policy is returned if neither covers the date.
+func FetchActiveAwardQueuePolicy(db *pop.Connection, tdlID uuid.UUID, date time.Time) (AwardQueuePolicy, error) {
+	var policies AwardQueuePolicies
+	err := db.
+		Where("traffic_distribution_list_id = ? OR traffic_distribution_list_id IS NULL", tdlID).
+		Where("? BETWEEN effective_start_date AND effective_end_date", date).
+		Order("traffic_distribution_list_id IS NULL ASC, version DESC").
+		All(&policies)
+	if err != nil {
+		return AwardQueuePolicy{}, err
+	}
+	if len(policies) == 0 {
+		return DefaultAwardQueuePolicy(), nil
+	}
+	return policies[0], nil
+}
+
+// FetchAwardQueuePolicyForTSPPerformances returns the policy that the quality bands of a TDL's TSP performances
+// covering a book date and requested pickup date were assigned with, so that shipments are offered to the bands
+// using the same policy. TSP performances banded before policies were stored with them use the default policy.
+func FetchAwardQueuePolicyForTSPPerformances(db *pop.Connection, tdlID uuid.UUID, bookDate time.Time, requestedPickupDate time.Time) (AwardQueuePolicy, error) {
+	var performance TransportationServiceProviderPerformance
+	err := db.
+		Where("traffic_distribution_list_id = ?", tdlID).
+		Where("? BETWEEN performance_period_start AND performance_period_end", bookDate).
+		Where("? BETWEEN rate_cycle_start AND rate_cycle_end", requestedPickupDate).
+		Where("quality_band IS NOT NULL").
+		First(&performance)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return DefaultAwardQueuePolicy(), nil
+		}
+		return AwardQueuePolicy{}, err
+	}
+	if performance.AwardQueuePolicyID == nil {
+		return DefaultAwardQueuePolicy(), nil
+	}
+
+	var policy AwardQueuePolicy
+	err = db.Find(&policy, *performance.AwardQueuePolicyID)
+	return policy, err
+}
+
+// NextAwardQueuePolicyVersion returns the version number the next stored policy should have
+func NextAwardQueuePolicyVersion(db *pop.Connection) (int, error) {
+	var version int
+	err := db.RawQuery("SELECT COALESCE(MAX(version), 0) + 1 FROM award_queue_policies").First(&version)
+	return version, err
+}
@@ -0,0 +1,124 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/gobuffalo/pop/slices"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) Test_AwardQueuePolicyValidations() {
+	policy := &AwardQueuePolicy{
+		MinimumPerformanceScore: 100.5,
+		OffersPerQualityBand:    slices.Int{5, 3, 2, 1, 1},
+	}
+
+	var expErrors = map[string][]string{
+		"version":                   {"0 is not greater than 0."},
+		"effective_start_date":      {"EffectiveStartDate can not be blank.", "EffectiveStartDate must be before EffectiveEndDate."},
+		"effective_end_date":        {"EffectiveEndDate can not be blank."},
+		"minimum_performance_score": {"MinimumPerformanceScore must be between 0 and 100, got 100.5"},
+		"offers_per_quality_band":   {"OffersPerQualityBand must have between 1 and 4 quality bands"},
+	}
+
+	suite.verifyValidationErrors(policy, expErrors)
+
+	policy = &AwardQueuePolicy{
+		Version:              1,
+		EffectiveStartDate:   testdatagen.PerformancePeriodStart,
+		EffectiveEndDate:     testdatagen.PerformancePeriodEnd,
+		OffersPerQualityBand: slices.Int{5, 0},
+	}
+	expErrors = map[string][]string{
+		"offers_per_quality_band": {"OffersPerQualityBand for quality band 2 must be at least 1"},
+	}
+	suite.verifyValidationErrors(policy, expErrors)
+}
+
+func (suite *ModelSuite) Test_FetchActiveAwardQueuePolicy() {
+	tdl := testdatagen.MakeDefaultTDL(suite.DB())
+	otherTDL := testdatagen.MakeTDL(suite.DB(), testdatagen.Assertions{
+		TrafficDistributionList: TrafficDistributionList{
+			SourceRateArea:    "US28",
+			DestinationRegion: "5",
+			CodeOfService:     "2",
+		},
+	})
+	date := testdatagen.DateInsidePerformancePeriod
+
+	// Without any stored policies, the default policy is used
+	policy, err := FetchActiveAwardQueuePolicy(suite.DB(), tdl.ID, date)
+	suite.NoError(err)
+	suite.Equal(0, policy.Version)
+	suite.Nil(policy.StoredID())
+	suite.Equal(4, policy.NumQualityBands())
+
+	global := testdatagen.MakeDefaultAwardQueuePolicy(suite.DB())
+	newerGlobal := testdatagen.MakeAwardQueuePolicy(suite.DB(), testdatagen.Assertions{
+		AwardQueuePolicy: AwardQueuePolicy{
+			OffersPerQualityBand: slices.Int{3, 2, 1},
+		},
+	})
+	tdlPolicy := testdatagen.MakeAwardQueuePolicy(suite.DB(), testdatagen.Assertions{
+		AwardQueuePolicy: AwardQueuePolicy{
+			TrafficDistributionListID: &tdl.ID,
+			MinimumPerformanceScore:   10,
+		},
+	})
+	suite.True(global.Version < newerGlobal.Version)
+
+	// The TDL's own policy takes precedence
+	policy, err = FetchActiveAwardQueuePolicy(suite.DB(), tdl.ID, date)
+	suite.NoError(err)
+	suite.Equal(tdlPolicy.ID, policy.ID)
+
+	// Otherwise the newest policy for all TDLs is used
+	policy, err = FetchActiveAwardQueuePolicy(suite.DB(), otherTDL.ID, date)
+	suite.NoError(err)
+	suite.Equal(newerGlobal.ID, policy.ID)
+	suite.Equal(3, policy.NumQualityBands())
+	suite.Equal(2, policy.OffersForQualityBand(2))
+
+	// Policies only apply between their effective dates
+	policy, err = FetchActiveAwardQueuePolicy(suite.DB(), tdl.ID, testdatagen.DateOutsidePerformancePeriod.Add(time.Hour*24))
+	suite.NoError(err)
+	suite.Equal(0, policy.Version)
+}
+
+func (suite *ModelSuite) Test_FetchAwardQueuePolicyForTSPPerformances() {
+	tdl := testdatagen.MakeDefaultTDL(suite.DB())
+	tsp := testdatagen.MakeDefaultTSP(suite.DB())
+	perf, err := testdatagen.MakeTSPPerformance(suite.DB(), testdatagen.Assertions{
+		TransportationServiceProviderPerformance: TransportationServiceProviderPerformance{
+			TransportationServiceProvider:   tsp,
+			TransportationServiceProviderID: tsp.ID,
+			TrafficDistributionListID:       tdl.ID,
+			QualityBand:                     swag.Int(1),
+		},
+	})
+	suite.NoError(err)
+	bookDate := testdatagen.DateInsidePerformancePeriod
+	pickupDate := testdatagen.DateInsidePeakRateCycle
+
+	// TSP performances banded without a stored policy use the default policy
+	policy, err := FetchAwardQueuePolicyForTSPPerformances(suite.DB(), tdl.ID, bookDate, pickupDate)
+	suite.NoError(err)
+	suite.Nil(policy.StoredID())
+
+	// Otherwise the policy stored with the bands is used, even once a newer policy is in effect
+	bandPolicy := testdatagen.MakeDefaultAwardQueuePolicy(suite.DB())
+	perf.AwardQueuePolicyID = &bandPolicy.ID
+	suite.MustSave(&perf)
+	testdatagen.MakeAwardQueuePolicy(suite.DB(), testdatagen.Assertions{
+		AwardQueuePolicy: AwardQueuePolicy{
+			TrafficDistributionListID: &tdl.ID,
+		},
+	})
+
+	policy, err = FetchAwardQueuePolicyForTSPPerformances(suite.DB(), tdl.ID, bookDate, pickupDate)
+	suite.NoError(err)
+	suite.Equal(bandPolicy.ID, policy.ID)
+}
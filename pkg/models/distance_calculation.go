@@ -62,3 +62,68 @@ func (d *DistanceCalculation) Validate(tx *pop.Connection) (*validate.Errors, er
 		&validators.IntIsPresent{Field: d.DistanceMiles, Name: "DistanceMiles"},
 	), nil
 }
+
+// DistanceSource is the route planner that produced a distance
+type DistanceSource string
+
+const (
+	// DistanceSourceHERE captures enum value "HERE"
+	DistanceSourceHERE DistanceSource = "HERE"
+	// DistanceSourceBING captures enum value "BING"
+	DistanceSourceBING DistanceSource = "BING"
+	// DistanceSourceGREATCIRCLE captures enum value "GREAT_CIRCLE"
+	// This source is an offline estimate of the great-circle distance multiplied by a road factor.
+	DistanceSourceGREATCIRCLE DistanceSource = "GREAT_CIRCLE"
+	// DistanceSourceTESTING captures enum value "TESTING"
+	DistanceSourceTESTING DistanceSource = "TESTING"
+)
+
+// Zip5DistanceCalculation caches the distance in miles between an origin and destination zip5
+type Zip5DistanceCalculation struct {
+	ID              uuid.UUID      `json:"id" db:"id"`
+	OriginZip5      string         `json:"origin_zip5" db:"origin_zip5"`
+	DestinationZip5 string         `json:"destination_zip5" db:"destination_zip5"`
+	DistanceMiles   int            `json:"distance_miles" db:"distance_miles"`
+	Source          DistanceSource `json:"source" db:"source"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (z *Zip5DistanceCalculation) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringLengthInRange{Field: z.OriginZip5, Name: "OriginZip5", Min: 5, Max: 5},
+		&validators.StringLengthInRange{Field: z.DestinationZip5, Name: "DestinationZip5", Min: 5, Max: 5},
+		&validators.IntIsPresent{Field: z.DistanceMiles, Name: "DistanceMiles"},
+		&validators.StringIsPresent{Field: string(z.Source), Name: "Source"},
+	), nil
+}
+
+// FetchZip5DistanceCalculation returns the cached distance between two zip5s, or nil if there isn't one
+func FetchZip5DistanceCalculation(db *pop.Connection, originZip5 string, destinationZip5 string) (*Zip5DistanceCalculation, error) {
+	var calculations []Zip5DistanceCalculation
+	err := db.Where("origin_zip5 = ? AND destination_zip5 = ?", originZip5, destinationZip5).All(&calculations)
+	if err != nil || len(calculations) == 0 {
+		return nil, err
+	}
+	return &calculations[0], nil
+}
+
+// SaveZip5DistanceCalculation creates or updates the cached distance between two zip5s
+func SaveZip5DistanceCalculation(db *pop.Connection, originZip5 string, destinationZip5 string, distanceMiles int, source DistanceSource) (*Zip5DistanceCalculation, *validate.Errors, error) {
+	calculation, err := FetchZip5DistanceCalculation(db, originZip5, destinationZip5)
+	if err != nil {
+		return nil, validate.NewErrors(), err
+	}
+	if calculation == nil {
+		calculation = &Zip5DistanceCalculation{
+			OriginZip5:      originZip5,
+			DestinationZip5: destinationZip5,
+		}
+	}
+	calculation.DistanceMiles = distanceMiles
+	calculation.Source = source
+
+	verrs, err := db.ValidateAndSave(calculation)
+	return calculation, verrs, err
+}
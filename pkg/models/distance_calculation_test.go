@@ -59,3 +59,39 @@ func (suite *ModelSuite) Test_NewDistanceCalculationCallsPlanner() {
 	// And it should not have been saved
 	suite.Equal(uuid.Nil, distanceCalculation.ID)
 }
+
+func (suite *ModelSuite) Test_Zip5DistanceCalculationValidations() {
+	calculation := &models.Zip5DistanceCalculation{}
+
+	var expErrors = map[string][]string{
+		"origin_zip5":      {"OriginZip5 not in range(5, 5)"},
+		"destination_zip5": {"DestinationZip5 not in range(5, 5)"},
+		"distance_miles":   {"DistanceMiles can not be blank."},
+		"source":           {"Source can not be blank."},
+	}
+
+	suite.verifyValidationErrors(calculation, expErrors)
+}
+
+func (suite *ModelSuite) Test_SaveZip5DistanceCalculation() {
+	calculation, err := models.FetchZip5DistanceCalculation(suite.DB(), "94103", "98438")
+	suite.NoError(err)
+	suite.Nil(calculation)
+
+	calculation, verrs, err := models.SaveZip5DistanceCalculation(suite.DB(), "94103", "98438", 900, models.DistanceSourceGREATCIRCLE)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	// Saving the same pair again updates the cached distance instead of adding a new row
+	updated, verrs, err := models.SaveZip5DistanceCalculation(suite.DB(), "94103", "98438", 812, models.DistanceSourceHERE)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+	suite.Equal(calculation.ID, updated.ID)
+
+	fetched, err := models.FetchZip5DistanceCalculation(suite.DB(), "94103", "98438")
+	suite.NoError(err)
+	if suite.NotNil(fetched) {
+		suite.Equal(812, fetched.DistanceMiles)
+		suite.Equal(models.DistanceSourceHERE, fetched.Source)
+	}
+}
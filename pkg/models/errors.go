@@ -2,6 +2,8 @@ package models
 
 import (
 	"errors"
+
+	"github.com/transcom/mymove/pkg/statemachine"
 )
 
 // These are errors that are returned by various model functions
@@ -12,6 +14,9 @@ var ErrCreateViolatesUniqueConstraint = errors.New("CREATE_VIOLATES_UNIQUE")
 // ErrFetchNotFound means that the requested record does not exist
 var ErrFetchNotFound = errors.New("FETCH_NOT_FOUND")
 
+// ErrFetchAmbiguous means that more than one record matched a lookup that should identify a single record
+var ErrFetchAmbiguous = errors.New("FETCH_AMBIGUOUS")
+
 // ErrUserUnauthorized means that the user is not authorized to access a record
 var ErrUserUnauthorized = errors.New("USER_UNAUTHORIZED")
 
@@ -24,6 +29,9 @@ var ErrWriteForbidden = errors.New("WRITE_FORBIDDEN")
 // ErrWriteConflict means that the record creation or update cannot be completed due to a conflict with other records
 var ErrWriteConflict = errors.New("WRITE_CONFLICT")
 
+// ErrStaleVersion means that a record was updated by someone else after the version the update was based on
+var ErrStaleVersion = errors.New("STALE_VERSION")
+
 // ErrDestroyForbidden means that a model cannot be destroyed in its current state
 var ErrDestroyForbidden = errors.New("DESTROY_FORBIDDEN")
 
@@ -33,8 +41,12 @@ var ErrLocatorGeneration = errors.New("LOCATOR_ERRORS")
 // ErrInvalidPatchGate means that an attempt to patch a model was not given the correct set of fields
 var ErrInvalidPatchGate = errors.New("INVALID_PATCH_GATE")
 
-// ErrInvalidTransition is an error representing an invalid state transition.
-var ErrInvalidTransition = errors.New("INVALID_TRANSITION")
+// ErrInvalidTransition is an error representing an invalid state transition. It is the error the state machines
+// return, so that an invalid transition is handled the same way whichever model refused it.
+var ErrInvalidTransition = statemachine.ErrInvalidTransition
+
+// ErrInvalidQueryParams means that a list was requested with filters, sorting or paging that can't be applied
+var ErrInvalidQueryParams = errors.New("INVALID_QUERY_PARAMS")
 
 // recordNotFoundErrorString is the error string returned when no matching rows exist in the database
 // This is ugly, but the best we can do with go's Postgresql adapter
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"math"
 	"time"
 
 	"github.com/facebookgo/clock"
@@ -43,6 +44,20 @@ func (f FuelEIADieselPrices) String() string {
 	return string(jf)
 }
 
+// FuelSurchargeBaselineRate calculates the fuel surcharge percentage for a diesel price per gallon in dollars
+func FuelSurchargeBaselineRate(pricePerGallon float64) int64 {
+	// Calculate fuel surcharge based on price per gallon based on government-provided calculation
+	// Rate formula found at https://www.sddc.army.mil/res/PublicationsAndPolicies/TR-12%20FRA%20Policy.docx
+	// Formula to get baseline rate: (fuelprice - baseline)/.13 rounded up; if the fuel price is greater than or equal to baseline, the baseline rate is 0
+	fuelPriceBaseline := 2.5
+	dividendValue := .13
+	diffPriceAndBaseline := pricePerGallon - fuelPriceBaseline
+	if diffPriceAndBaseline <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(diffPriceAndBaseline / dividendValue))
+}
+
 // FetchMostRecentFuelPrices queries and fetches all fuel_eia_diesel_prices for past specified number of months, including this month
 func FetchMostRecentFuelPrices(dbConnection *pop.Connection, clock clock.Clock, numMonths int) ([]FuelEIADieselPrice, error) {
 	today := clock.Now().UTC()
@@ -58,6 +73,47 @@ func FetchMostRecentFuelPrices(dbConnection *pop.Connection, clock clock.Clock,
 	return fuelPrices, nil
 }
 
+// FetchMissingFuelPriceMonths returns the first day of each month from start to end, inclusive, that has no
+// fuel_eia_diesel_prices published in it
+func FetchMissingFuelPriceMonths(dbConnection *pop.Connection, start time.Time, end time.Time) ([]time.Time, error) {
+	firstMonth := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	lastMonth := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var fuelPrices FuelEIADieselPrices
+	err := dbConnection.Where("pub_date >= $1 AND pub_date < $2", firstMonth, lastMonth.AddDate(0, 1, 0)).All(&fuelPrices)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetch fuel prices query failed")
+	}
+
+	monthsInDB := map[time.Time]bool{}
+	for _, fuelPrice := range fuelPrices {
+		monthsInDB[time.Date(fuelPrice.PubDate.Year(), fuelPrice.PubDate.Month(), 1, 0, 0, 0, 0, time.UTC)] = true
+	}
+
+	missing := []time.Time{}
+	for month := firstMonth; !month.After(lastMonth); month = month.AddDate(0, 1, 0) {
+		if !monthsInDB[month] {
+			missing = append(missing, month)
+		}
+	}
+	return missing, nil
+}
+
+// FetchFuelPriceForPubMonth fetches the fuel_eia_diesel_prices published in the month of pubDate
+func FetchFuelPriceForPubMonth(dbConnection *pop.Connection, pubDate time.Time) (FuelEIADieselPrice, error) {
+	month := time.Date(pubDate.Year(), pubDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var fuelPrice FuelEIADieselPrice
+	err := dbConnection.Where("pub_date >= $1 AND pub_date < $2", month, month.AddDate(0, 1, 0)).First(&fuelPrice)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return fuelPrice, ErrFetchNotFound
+		}
+		return fuelPrice, err
+	}
+	return fuelPrice, nil
+}
+
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
 // This method is not required and may be deleted.
 func (f *FuelEIADieselPrice) Validate(tx *pop.Connection) (*validate.Errors, error) {
@@ -176,3 +176,28 @@ func (suite *ModelSuite) TestMakeDefaultFuelEIADieselPriceForDate() {
 	shipmentDate := time.Now()
 	testdatagen.MakeDefaultFuelEIADieselPriceForDate(suite.DB(), shipmentDate)
 }
+
+func (suite *ModelSuite) TestFetchMissingFuelPriceMonths() {
+	for _, month := range []time.Month{time.January, time.March} {
+		assertions := testdatagen.Assertions{}
+		assertions.FuelEIADieselPrice.PubDate = time.Date(2019, month, 7, 0, 0, 0, 0, time.UTC)
+		assertions.FuelEIADieselPrice.RateStartDate = time.Date(2019, month, 15, 0, 0, 0, 0, time.UTC)
+		testdatagen.MakeFuelEIADieselPriceForDate(suite.DB(), assertions.FuelEIADieselPrice.RateStartDate, assertions)
+	}
+
+	missing, err := models.FetchMissingFuelPriceMonths(suite.DB(),
+		time.Date(2019, time.January, 20, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, time.April, 2, 0, 0, 0, 0, time.UTC))
+	suite.NoError(err)
+	suite.Equal([]time.Time{
+		time.Date(2019, time.February, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC),
+	}, missing)
+
+	fuelPrice, err := models.FetchFuelPriceForPubMonth(suite.DB(), time.Date(2019, time.March, 28, 0, 0, 0, 0, time.UTC))
+	suite.NoError(err)
+	suite.Equal(time.Date(2019, time.March, 15, 0, 0, 0, 0, time.UTC), fuelPrice.RateStartDate.UTC())
+
+	_, err = models.FetchFuelPriceForPubMonth(suite.DB(), time.Date(2019, time.February, 1, 0, 0, 0, 0, time.UTC))
+	suite.Equal(models.ErrFetchNotFound, err)
+}
@@ -3,12 +3,16 @@ package models
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-openapi/swag"
 	"github.com/gobuffalo/pop"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/paperwork/gblrules"
 	"github.com/transcom/mymove/pkg/unit"
 )
 
@@ -61,7 +65,7 @@ type GovBillOfLadingFormValues struct {
 	ResponsibleDestinationOffice string `db:"responsible_destination_office"`
 	// From Shipment.DestinationGBLOC
 	DestinationGbloc string `db:"destination_gbloc"`
-	// Hardcoded: "US Bank PowerTrack Minneapolis, MN 800-417-1844 PowerTrack@usbank.com". (TODO: there will be other options)
+	// From gblrules.BillTo, e.g. "US Bank PowerTrack Minneapolis, MN 800-417-1844 PowerTrack@usbank.com"
 	BillChargesToName      string  `db:"bill_chargest_to_name"`
 	BillChargesToAddressID Address `db:"bill_charges_to_address_id"`
 	BillChargesToAddress   Address `belongs_to:"address"`
@@ -71,12 +75,12 @@ type GovBillOfLadingFormValues struct {
 	TAC                 string                          `db:"tac"`
 	SAC                 string                          `db:"sac"`
 	DepartmentIndicator *internalmessages.DeptIndicator `db:"department_indicator"`
-	// (func to "getRemarks" with hardcoded values - See description - 16 cases account for. For now: "Direct Delivery Requested"). If any of ForUsePayingOffice... are true, must explain here
+	// From gblrules.Remarks, one per line. If any of ForUsePayingOffice... are true, must explain here
 	Remarks string `db:"remarks"`
 	// TGBL shipment case - see description ("LOT").
 	PackagesNumber int64
 	PackagesKind   string
-	// Hardcoded for now - “Household Goods. Containers: 0 Shipment is released at full replacement protection of $6.00 times the net weight in pounds of the HHG shipment or the gross weight of the UB shipment or $5,000, whichever is greater.”
+	// From gblrules.Description - “Household Goods. Containers: 0 Shipment is released at full replacement protection of $6.00 times the net weight in pounds of the HHG shipment or the gross weight of the UB shipment or $5,000, whichever is greater.”
 	DescriptionOfShipment string
 	// TSP enters weight values
 	WeightGrossPounds *int64
@@ -101,7 +105,7 @@ type GovBillOfLadingFormValues struct {
 	SignatureOfAgentOrDriver *SignedCertification
 	// TSP enters - enter if the signature above is the agent's authorized representative
 	PerInitials string
-	// TSP enters - if any are checked, they must be explained in Remarks
+	// TSP enters - if any are checked, they must be explained in Remarks (see gblrules.PayingOfficerFlags)
 	ForUsePayingOfficerUnauthorizedItems *bool
 	ForUsePayingOfficerExcessDistance    *bool
 	ForUsePayingOfficerExcessValuation   *bool
@@ -193,14 +197,21 @@ func FetchGovBillOfLadingFormValues(db *pop.Connection, shipmentID uuid.UUID) (G
 		return gbl, err
 	}
 
-	// These values are hardcoded for now
+	facts, err := FetchGBLRuleFacts(db, shipmentID)
+	if err != nil {
+		return gbl, err
+	}
+	facts.PayingOfficer = gblrules.PayingOfficerFlags{
+		UnauthorizedItems: swag.BoolValue(gbl.ForUsePayingOfficerUnauthorizedItems),
+		ExcessDistance:    swag.BoolValue(gbl.ForUsePayingOfficerExcessDistance),
+		ExcessValuation:   swag.BoolValue(gbl.ForUsePayingOfficerExcessValuation),
+		ExcessWeight:      swag.BoolValue(gbl.ForUsePayingOfficerExcessWeight),
+		Other:             swag.BoolValue(gbl.ForUsePayingOfficerOther),
+	}
 	gbl.DateIssued = time.Now()
-	gbl.BillChargesToName = "US Bank PowerTrack\n" +
-		"Minneapolis, MN\n" +
-		"800-417-1844\n" +
-		"PowerTrack@usbank.com"
-	gbl.DescriptionOfShipment = "Household Goods. Containers: 0 Shipment is released at full replacement protection of $6.00 times the net weight in pounds of the HHG shipment or the gross weight of the UB shipment or $5,000, whichever is greater."
-	gbl.Remarks = "Direct Delivery Requested"
+	gbl.BillChargesToName = gblrules.BillTo(facts)
+	gbl.DescriptionOfShipment = gblrules.Description(facts)
+	gbl.Remarks = strings.Join(gblrules.Remarks(facts), "\n")
 	if gbl.LineHaulTransportationRate != nil {
 		// Field has the following format:
 		// Domestic shipments: "400NG-2006 15%" using the linehaul rate
@@ -213,3 +224,81 @@ func FetchGovBillOfLadingFormValues(db *pop.Connection, shipmentID uuid.UUID) (G
 
 	return gbl, nil
 }
+
+// FetchGBLRuleFacts gathers the facts about a shipment, its orders, SIT and pre-approval line items that the GBL
+// rules look at
+func FetchGBLRuleFacts(db *pop.Connection, shipmentID uuid.UUID) (gblrules.Facts, error) {
+	var facts gblrules.Facts
+	var shipment Shipment
+	err := db.Eager("ServiceMember", "Move.Orders", "StorageInTransits").Find(&shipment, shipmentID)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return facts, ErrFetchNotFound
+		}
+		return facts, err
+	}
+	lineItems, err := FetchLineItemsByShipmentID(db, &shipmentID)
+	if err != nil {
+		return facts, err
+	}
+
+	orders := shipment.Move.Orders
+	if shipment.ServiceMember.Affiliation != nil {
+		facts.Affiliation = string(*shipment.ServiceMember.Affiliation)
+	}
+	facts.HasDependents = orders.HasDependents
+	if shipment.ServiceMember.Rank != nil {
+		allowance, err := GetEntitlement(*shipment.ServiceMember.Rank, orders.HasDependents, orders.SpouseHasProGear)
+		if err != nil {
+			return facts, err
+		}
+		facts.WeightAllowance = unit.Pound(allowance)
+	}
+
+	// The premove survey's estimates replace the member's own
+	facts.WeightEstimate = firstPound(shipment.PmSurveyWeightEstimate, shipment.WeightEstimate)
+	facts.ProgearWeightEstimate = firstPound(shipment.PmSurveyProgearWeightEstimate, shipment.ProgearWeightEstimate)
+	facts.SpouseProgearWeightEstimate = firstPound(shipment.PmSurveySpouseProgearWeightEstimate, shipment.SpouseProgearWeightEstimate)
+
+	facts.HasSecondaryPickupAddress = shipment.HasSecondaryPickupAddress
+	facts.HasDeliveryAddress = shipment.HasDeliveryAddress
+	facts.HasPartialSITDeliveryAddress = shipment.HasPartialSITDeliveryAddress
+
+	for _, sit := range shipment.StorageInTransits {
+		if sit.Status == StorageInTransitStatusDENIED {
+			continue
+		}
+		startDate := sit.EstimatedStartDate
+		if sit.AuthorizedStartDate != nil {
+			startDate = *sit.AuthorizedStartDate
+		}
+		facts.StorageInTransits = append(facts.StorageInTransits, gblrules.StorageInTransit{
+			Location:   string(sit.Location),
+			Authorized: sit.Status != StorageInTransitStatusREQUESTED,
+			StartDate:  startDate,
+		})
+	}
+
+	for _, lineItem := range lineItems {
+		if !lineItem.Tariff400ngItem.RequiresPreApproval {
+			continue
+		}
+		facts.Accessorials = append(facts.Accessorials, gblrules.Accessorial{
+			Code:     lineItem.Tariff400ngItem.Code,
+			Item:     lineItem.Tariff400ngItem.Item,
+			Approved: lineItem.Status != ShipmentLineItemStatusSUBMITTED,
+		})
+	}
+
+	return facts, nil
+}
+
+// firstPound returns the first weight that is set, or zero
+func firstPound(weights ...*unit.Pound) unit.Pound {
+	for _, weight := range weights {
+		if weight != nil {
+			return *weight
+		}
+	}
+	return 0
+}
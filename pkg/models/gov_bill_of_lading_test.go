@@ -5,7 +5,9 @@ import (
 
 	"github.com/transcom/mymove/pkg/dates"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/paperwork/gblrules"
 	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
 )
 
 func (suite *ModelSuite) TestFetchGovBillOfLadingExtractor() {
@@ -58,4 +60,69 @@ func (suite *ModelSuite) TestFetchGovBillOfLadingExtractor() {
 
 	suite.Equal(SourceTransOffice.Gbloc, gbl.IssuingOfficeGBLOC)
 	suite.Equal(DestinationTransOffice.Gbloc, gbl.DestinationGbloc)
+	suite.Equal(gblrules.DefaultBillTo, gbl.BillChargesToName)
+	suite.Contains(gbl.Remarks, "Direct Delivery Requested")
+	suite.Contains(gbl.DescriptionOfShipment, "Household Goods. Containers: 0 ")
+}
+
+func (suite *ModelSuite) TestFetchGBLRuleFacts() {
+	coastGuard := models.AffiliationCOASTGUARD
+	progear := unit.Pound(1200)
+	shipment := testdatagen.MakeShipment(suite.DB(), testdatagen.Assertions{
+		Shipment: models.Shipment{
+			HasSecondaryPickupAddress: true,
+			ProgearWeightEstimate:     &progear,
+		},
+		ServiceMember: models.ServiceMember{
+			Affiliation: &coastGuard,
+		},
+	})
+
+	authorizedStart := time.Date(testdatagen.TestYear, time.May, 20, 0, 0, 0, 0, time.UTC)
+	testdatagen.MakeStorageInTransit(suite.DB(), testdatagen.Assertions{
+		StorageInTransit: models.StorageInTransit{
+			Shipment:            shipment,
+			ShipmentID:          shipment.ID,
+			Location:            models.StorageInTransitLocationORIGIN,
+			Status:              models.StorageInTransitStatusAPPROVED,
+			AuthorizedStartDate: &authorizedStart,
+		},
+	})
+	testdatagen.MakeStorageInTransit(suite.DB(), testdatagen.Assertions{
+		StorageInTransit: models.StorageInTransit{
+			Shipment:   shipment,
+			ShipmentID: shipment.ID,
+			Status:     models.StorageInTransitStatusDENIED,
+		},
+	})
+
+	crate := testdatagen.MakeTariff400ngItem(suite.DB(), testdatagen.Assertions{
+		Tariff400ngItem: models.Tariff400ngItem{RequiresPreApproval: true},
+	})
+	testdatagen.MakeShipmentLineItem(suite.DB(), testdatagen.Assertions{
+		ShipmentLineItem: models.ShipmentLineItem{
+			Shipment:        shipment,
+			Tariff400ngItem: crate,
+			Status:          models.ShipmentLineItemStatusAPPROVED,
+		},
+	})
+
+	facts, err := models.FetchGBLRuleFacts(suite.DB(), shipment.ID)
+	suite.NoError(err)
+
+	suite.Equal(string(coastGuard), facts.Affiliation)
+	suite.True(facts.HasSecondaryPickupAddress)
+	suite.Equal(progear, facts.ProgearWeightEstimate)
+	suite.True(facts.WeightAllowance > 0)
+	// The denied SIT request is left out
+	suite.FatalFalse(len(facts.StorageInTransits) != 1)
+	suite.Equal(gblrules.LocationOrigin, facts.StorageInTransits[0].Location)
+	suite.True(facts.StorageInTransits[0].Authorized)
+	suite.True(authorizedStart.Equal(facts.StorageInTransits[0].StartDate))
+	suite.Equal([]gblrules.Accessorial{
+		{Code: crate.Code, Item: crate.Item, Approved: true},
+	}, facts.Accessorials)
+
+	_, err = models.FetchGBLRuleFacts(suite.DB(), shipment.MoveID)
+	suite.Equal(models.ErrFetchNotFound, err)
 }
@@ -28,23 +28,31 @@ const (
 	// This status indicates that the invoice was successfully submitted, but the updating of the invoice
 	// and associated shipment line items failed.
 	InvoiceStatusUPDATEFAILURE InvoiceStatus = "UPDATE_FAILURE"
+	// InvoiceStatusACCEPTED captures enum value "ACCEPTED"
+	// This status indicates that a 997 functional acknowledgment accepting the invoice was received from GEX.
+	InvoiceStatusACCEPTED InvoiceStatus = "ACCEPTED"
+	// InvoiceStatusREJECTED captures enum value "REJECTED"
+	// This status indicates that a 997 functional acknowledgment rejecting the invoice was received from GEX.
+	InvoiceStatusREJECTED InvoiceStatus = "REJECTED"
 )
 
 // Invoice is a collection of line item charges to be sent for payment
 type Invoice struct {
-	ID                uuid.UUID         `json:"id" db:"id"`
-	ApproverID        uuid.UUID         `json:"approver_id" db:"approver_id"`
-	Approver          OfficeUser        `belongs_to:"office_user"`
-	Status            InvoiceStatus     `json:"status" db:"status"`
-	InvoiceNumber     string            `json:"invoice_number" db:"invoice_number"`
-	InvoicedDate      time.Time         `json:"invoiced_date" db:"invoiced_date"`
-	ShipmentID        uuid.UUID         `json:"shipment_id" db:"shipment_id"`
-	Shipment          Shipment          `belongs_to:"shipments"`
-	ShipmentLineItems ShipmentLineItems `has_many:"shipment_line_items"`
-	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time         `json:"updated_at" db:"updated_at"`
-	UploadID          *uuid.UUID        `json:"upload_id" db:"upload_id"`
-	Upload            *Upload           `belongs_to:"uploads"`
+	ID                       uuid.UUID         `json:"id" db:"id"`
+	ApproverID               uuid.UUID         `json:"approver_id" db:"approver_id"`
+	Approver                 OfficeUser        `belongs_to:"office_user"`
+	Status                   InvoiceStatus     `json:"status" db:"status"`
+	InvoiceNumber            string            `json:"invoice_number" db:"invoice_number"`
+	InvoicedDate             time.Time         `json:"invoiced_date" db:"invoiced_date"`
+	ShipmentID               uuid.UUID         `json:"shipment_id" db:"shipment_id"`
+	Shipment                 Shipment          `belongs_to:"shipments"`
+	ShipmentLineItems        ShipmentLineItems `has_many:"shipment_line_items"`
+	CreatedAt                time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt                time.Time         `json:"updated_at" db:"updated_at"`
+	UploadID                 *uuid.UUID        `json:"upload_id" db:"upload_id"`
+	Upload                   *Upload           `belongs_to:"uploads"`
+	InterchangeControlNumber *int64            `json:"interchange_control_number" db:"interchange_control_number"`
+	RejectionReasons         *string           `json:"rejection_reasons" db:"rejection_reasons"`
 }
 
 // Invoices is an array of invoices
@@ -97,3 +105,22 @@ func FetchInvoicesForShipment(db *pop.Connection, shipmentID uuid.UUID) (Invoice
 	err := db.Where("shipment_id = ?", shipmentID).Eager("Approver").All(&invoices)
 	return invoices, err
 }
+
+// FetchInvoiceByInterchangeControlNumber fetches the invoice that was submitted with the given ICN.
+// The ICN sequence cycles, so rather than guess which invoice an ICN refers to, ErrFetchAmbiguous is
+// returned if more than one invoice was submitted with it.
+func FetchInvoiceByInterchangeControlNumber(db *pop.Connection, icn int64) (*Invoice, error) {
+	var invoices Invoices
+	err := db.Where("interchange_control_number = ?", icn).Limit(2).All(&invoices)
+	if err != nil {
+		return nil, err
+	}
+	switch len(invoices) {
+	case 0:
+		return nil, ErrFetchNotFound
+	case 1:
+		return &invoices[0], nil
+	default:
+		return nil, ErrFetchAmbiguous
+	}
+}
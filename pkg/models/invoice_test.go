@@ -102,3 +102,31 @@ func (suite *ModelSuite) TestFetchInvoicesForShipment() {
 		suite.Equal(extantInvoices[0].ID, invoice1.ID)
 	}
 }
+
+func (suite *ModelSuite) TestFetchInvoiceByInterchangeControlNumber() {
+	icn := int64(424242)
+	invoice := testdatagen.MakeInvoice(suite.DB(), testdatagen.Assertions{
+		Invoice: Invoice{
+			InterchangeControlNumber: &icn,
+		},
+	})
+
+	// The invoice submitted with the ICN is returned
+	extantInvoice, err := FetchInvoiceByInterchangeControlNumber(suite.DB(), icn)
+	if suite.NoError(err) {
+		suite.Equal(invoice.ID, extantInvoice.ID)
+	}
+
+	// An ICN no invoice was submitted with is not found
+	_, err = FetchInvoiceByInterchangeControlNumber(suite.DB(), icn+1)
+	suite.Equal(ErrFetchNotFound, err)
+
+	// Once the ICN has been reused, it no longer identifies an invoice
+	testdatagen.MakeInvoice(suite.DB(), testdatagen.Assertions{
+		Invoice: Invoice{
+			InterchangeControlNumber: &icn,
+		},
+	})
+	_, err = FetchInvoiceByInterchangeControlNumber(suite.DB(), icn)
+	suite.Equal(ErrFetchAmbiguous, err)
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/statemachine"
 	"github.com/transcom/mymove/pkg/unit"
 )
 
@@ -68,6 +69,7 @@ type Move struct {
 	Locator                 string                  `json:"locator" db:"locator"`
 	CreatedAt               time.Time               `json:"created_at" db:"created_at"`
 	UpdatedAt               time.Time               `json:"updated_at" db:"updated_at"`
+	Version                 int                     `json:"version" db:"version"`
 	OrdersID                uuid.UUID               `json:"orders_id" db:"orders_id"`
 	Orders                  Order                   `belongs_to:"orders"`
 	SelectedMoveType        *SelectedMoveType       `json:"selected_move_type" db:"selected_move_type"`
@@ -78,6 +80,8 @@ type Move struct {
 	SignedCertifications    SignedCertifications    `has_many:"signed_certifications" order_by:"created_at desc"`
 	CancelReason            *string                 `json:"cancel_reason" db:"cancel_reason"`
 	Show                    *bool                   `json:"show" db:"show"`
+	StateHistory            `db:"-" json:"-"`
+	VersionCheck            `db:"-" json:"-"`
 }
 
 // MoveOptions is used when creating new moves based on parameters
@@ -111,86 +115,172 @@ func (m *Move) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
 }
 
 // State Machine
-// Avoid calling Move.Status = ... ever. Use these methods to change the state.
+// The move lifecycle is declared in moveStateMachine, which is the only thing that sets Move.Status.
+// Use these methods to change the state.
 
-// Submit submits the Move
-func (m *Move) Submit(submitDate time.Time) error {
-	if m.Status != MoveStatusDRAFT {
-		return errors.Wrap(ErrInvalidTransition, "Submit")
-	}
-
-	m.Status = MoveStatusSUBMITTED
-
-	// Update PPM status too
-	for i := range m.PersonallyProcuredMoves {
-		ppm := &m.PersonallyProcuredMoves[i]
-		err := ppm.Submit(submitDate)
-		if err != nil {
-			return err
-		}
-	}
+const (
+	moveEventSubmit  statemachine.Event = "Submit"
+	moveEventApprove statemachine.Event = "Approve"
+	moveEventCancel  statemachine.Event = "Cancel"
+)
 
-	// Update HHG (Shipment) status too
-	for i := range m.Shipments {
-		err := m.Shipments[i].Submit(submitDate)
-		if err != nil {
-			return err
+var moveStateMachine = statemachine.MustNew(statemachine.Definition{
+	Name: "Move",
+	States: []statemachine.State{
+		statemachine.State(MoveStatusDRAFT),
+		statemachine.State(MoveStatusSUBMITTED),
+		statemachine.State(MoveStatusAPPROVED),
+		statemachine.State(MoveStatusCANCELED),
+	},
+	Transitions: []statemachine.Transition{
+		{
+			Event: moveEventSubmit,
+			From:  []statemachine.State{statemachine.State(MoveStatusDRAFT)},
+			To:    statemachine.State(MoveStatusSUBMITTED),
+			Effects: []statemachine.Effect{
+				{
+					Description: "submits its PPMs and shipments",
+					Apply: func(subject interface{}, args interface{}) error {
+						submitDate, err := timeArg(args)
+						if err != nil {
+							return err
+						}
+						m := subject.(*Move)
+						for i := range m.PersonallyProcuredMoves {
+							if err := m.PersonallyProcuredMoves[i].Submit(*submitDate); err != nil {
+								return err
+							}
+						}
+						for i := range m.Shipments {
+							if err := m.Shipments[i].Submit(*submitDate); err != nil {
+								return err
+							}
+						}
+						return nil
+					},
+				},
+				{
+					Description: "requests its PPMs' advances",
+					Apply: func(subject interface{}, args interface{}) error {
+						for _, ppm := range subject.(*Move).PersonallyProcuredMoves {
+							if ppm.Advance != nil {
+								if err := ppm.Advance.Request(); err != nil {
+									return err
+								}
+							}
+						}
+						return nil
+					},
+				},
+			},
+		},
+		{
+			Event: moveEventApprove,
+			From:  []statemachine.State{statemachine.State(MoveStatusSUBMITTED)},
+			To:    statemachine.State(MoveStatusAPPROVED),
+		},
+		{
+			// We can cancel any move that isn't already canceled
+			Event: moveEventCancel,
+			From: []statemachine.State{
+				statemachine.State(MoveStatusDRAFT),
+				statemachine.State(MoveStatusSUBMITTED),
+				statemachine.State(MoveStatusAPPROVED),
+			},
+			To: statemachine.State(MoveStatusCANCELED),
+			Effects: []statemachine.Effect{
+				{
+					Description: "sets the cancel reason, if one was given",
+					Apply: func(subject interface{}, args interface{}) error {
+						reason, err := stringArg(args)
+						if err != nil {
+							return err
+						}
+						if reason != "" {
+							subject.(*Move).CancelReason = &reason
+						}
+						return nil
+					},
+				},
+				{
+					Description: "cancels its PPMs",
+					Apply: func(subject interface{}, args interface{}) error {
+						// This will work only if you use the PPM in question rather than a var representing it
+						// i.e. you can't use _, ppm := range PPMs, has to be PPMS[i] as below
+						m := subject.(*Move)
+						for i := range m.PersonallyProcuredMoves {
+							if err := m.PersonallyProcuredMoves[i].Cancel(); err != nil {
+								return err
+							}
+						}
+						return nil
+					},
+				},
+				{
+					// TODO: Orders can exist after related moves are canceled
+					Description: "cancels its orders",
+					Apply: func(subject interface{}, args interface{}) error {
+						return subject.(*Move).Orders.Cancel()
+					},
+				},
+			},
+		},
+	},
+	GetState: func(subject interface{}) statemachine.State {
+		return statemachine.State(subject.(*Move).Status)
+	},
+	SetState: func(subject interface{}, to statemachine.State) {
+		subject.(*Move).Status = MoveStatus(to)
+	},
+	OnTransition: func(subject interface{}, from statemachine.State, to statemachine.State) {
+		m := subject.(*Move)
+		var reason *string
+		if MoveStatus(to) == MoveStatusCANCELED {
+			reason = m.CancelReason
 		}
-	}
+		m.recordTransition(string(from), string(to), reason)
+	},
+})
 
-	for _, ppm := range m.PersonallyProcuredMoves {
-		if ppm.Advance != nil {
-			err := ppm.Advance.Request()
-			if err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+// Submit submits the Move, along with its PPMs, shipments and advances
+func (m *Move) Submit(submitDate time.Time) error {
+	return moveStateMachine.Fire(m, moveEventSubmit, submitDate)
 }
 
 // Approve approves the Move
 func (m *Move) Approve() error {
-	if m.Status != MoveStatusSUBMITTED {
-		return errors.Wrap(ErrInvalidTransition, "Approve")
-	}
-
-	m.Status = MoveStatusAPPROVED
-	return nil
+	return moveStateMachine.Fire(m, moveEventApprove, nil)
 }
 
 // Cancel cancels the Move and its associated PPMs
 func (m *Move) Cancel(reason string) error {
-	// We can cancel any move that isn't already complete.
-	if m.Status == MoveStatusCANCELED {
-		return errors.Wrap(ErrInvalidTransition, "Cancel")
-	}
-
-	m.Status = MoveStatusCANCELED
-
-	// If a reason was submitted, add it to the move record.
-	if reason != "" {
-		m.CancelReason = &reason
-	}
+	return moveStateMachine.Fire(m, moveEventCancel, reason)
+}
 
-	// This will work only if you use the PPM in question rather than a var representing it
-	// i.e. you can't use _, ppm := range PPMs, has to be PPMS[i] as below
+// SetTransitionActor sets who is making the status changes to the move, its PPMs and their advances, and its
+// shipments
+func (m *Move) SetTransitionActor(actor TransitionActor) {
+	m.StateHistory.SetTransitionActor(actor)
 	for i := range m.PersonallyProcuredMoves {
-		err := m.PersonallyProcuredMoves[i].Cancel()
-		if err != nil {
-			return err
-		}
+		m.PersonallyProcuredMoves[i].SetTransitionActor(actor)
 	}
-
-	// TODO: Orders can exist after related moves are canceled
-	err := m.Orders.Cancel()
-	if err != nil {
-		return err
+	for i := range m.Shipments {
+		m.Shipments[i].SetTransitionActor(actor)
 	}
+}
+
+// BeforeUpdate will run before each update of a Move, and increments its version.
+func (m *Move) BeforeUpdate(tx *pop.Connection) error {
+	return m.bumpVersion(tx, "moves", m.ID, &m.Version)
+}
 
-	return nil
+// AfterSave will run after each create/update of a Move, and saves its status changes.
+func (m *Move) AfterSave(tx *pop.Connection) error {
+	return m.saveStateTransitions(tx, &m.ID, StateTransitionEntityTypeMOVE, m.ID)
 }
 
+// END State Machine
+
 // FetchMove fetches and validates a Move for this User
 func FetchMove(db *pop.Connection, session *auth.Session, id uuid.UUID) (*Move, error) {
 	var move Move
@@ -603,6 +693,13 @@ func createNewMove(db *pop.Connection,
 // SaveMoveDependencies safely saves a Move status, ppms' advances' statuses, orders statuses,
 // and shipment GBLOCs.
 func SaveMoveDependencies(db *pop.Connection, move *Move) (*validate.Errors, error) {
+	return SaveMoveDependenciesInTransaction(db, move, nil)
+}
+
+// SaveMoveDependenciesInTransaction saves the same records as SaveMoveDependencies, then calls inTransaction
+// with the same transaction, so that side effects such as queued notifications are committed or rolled back
+// along with the move.
+func SaveMoveDependenciesInTransaction(db *pop.Connection, move *Move, inTransaction func(tx *pop.Connection) error) (*validate.Errors, error) {
 	responseVErrors := validate.NewErrors()
 	var responseError error
 
@@ -681,6 +778,13 @@ func SaveMoveDependencies(db *pop.Connection, move *Move) (*validate.Errors, err
 			responseError = errors.Wrap(err, "Error Saving Move")
 			return transactionError
 		}
+
+		if inTransaction != nil {
+			if err := inTransaction(db); err != nil {
+				responseError = err
+				return transactionError
+			}
+		}
 		return nil
 	})
 
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/pop/slices"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// NotificationDeliveryStatus represents the delivery status of a notification to a single recipient
+type NotificationDeliveryStatus string
+
+const (
+	// NotificationDeliveryStatusPENDING is a notification waiting to be sent, or to be retried
+	NotificationDeliveryStatusPENDING NotificationDeliveryStatus = "PENDING"
+	// NotificationDeliveryStatusSENT is a notification that was handed off to the email backend
+	NotificationDeliveryStatusSENT NotificationDeliveryStatus = "SENT"
+	// NotificationDeliveryStatusDEADLETTER is a notification that failed too many times and won't be retried
+	NotificationDeliveryStatusDEADLETTER NotificationDeliveryStatus = "DEAD_LETTER"
+)
+
+var notificationDeliveryStatuses = []string{
+	string(NotificationDeliveryStatusPENDING),
+	string(NotificationDeliveryStatusSENT),
+	string(NotificationDeliveryStatusDEADLETTER),
+}
+
+// NotificationDelivery is a rendered notification to a single recipient, written to the outbox in the same
+// transaction as the change that caused it and sent later by the outbox worker
+type NotificationDelivery struct {
+	ID               uuid.UUID                  `json:"id" db:"id"`
+	CreatedAt        time.Time                  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time                  `json:"updated_at" db:"updated_at"`
+	NotificationType string                     `json:"notification_type" db:"notification_type"`
+	MoveID           *uuid.UUID                 `json:"move_id" db:"move_id"`
+	RecipientEmail   string                     `json:"recipient_email" db:"recipient_email"`
+	Subject          string                     `json:"subject" db:"subject"`
+	HTMLBody         string                     `json:"html_body" db:"html_body"`
+	TextBody         string                     `json:"text_body" db:"text_body"`
+	Attachments      slices.String              `json:"attachments" db:"attachments"`
+	Status           NotificationDeliveryStatus `json:"status" db:"status"`
+	Attempts         int                        `json:"attempts" db:"attempts"`
+	NextAttemptAt    time.Time                  `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError        *string                    `json:"last_error" db:"last_error"`
+	SentAt           *time.Time                 `json:"sent_at" db:"sent_at"`
+}
+
+// NotificationDeliveries is a slice of NotificationDelivery objects
+type NotificationDeliveries []NotificationDelivery
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (n *NotificationDelivery) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: n.NotificationType, Name: "NotificationType"},
+		&validators.StringIsPresent{Field: n.RecipientEmail, Name: "RecipientEmail"},
+		&validators.StringInclusion{Field: string(n.Status), Name: "Status", List: notificationDeliveryStatuses},
+		&validators.IntIsGreaterThan{Field: n.Attempts, Name: "Attempts", Compared: -1},
+		&validators.TimeIsPresent{Field: n.NextAttemptAt, Name: "NextAttemptAt"},
+	), nil
+}
+
+// ClaimDueNotificationDeliveries claims and returns up to limit pending deliveries that are due to be attempted.
+// Claiming moves their next attempt to leaseUntil in a single statement, so no locks are held while they're sent
+// and other workers won't attempt them again unless the claimant fails to record an outcome before then.
+func ClaimDueNotificationDeliveries(db *pop.Connection, now time.Time, leaseUntil time.Time, limit int) (NotificationDeliveries, error) {
+	var deliveries NotificationDeliveries
+	err := db.RawQuery(`
+		UPDATE notification_deliveries SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM notification_deliveries
+			WHERE status = $2 AND next_attempt_at <= $3
+			ORDER BY next_attempt_at ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		leaseUntil, NotificationDeliveryStatusPENDING, now, limit).All(&deliveries)
+	if err != nil {
+		return deliveries, errors.Wrap(err, "Claim notification deliveries query failed")
+	}
+	return deliveries, nil
+}
+
+// FetchNotificationDeliveriesForMove returns every notification delivery for a move, newest first
+func FetchNotificationDeliveriesForMove(db *pop.Connection, moveID uuid.UUID) (NotificationDeliveries, error) {
+	var deliveries NotificationDeliveries
+	err := db.Where("move_id = ?", moveID).Order("created_at DESC").All(&deliveries)
+	return deliveries, err
+}
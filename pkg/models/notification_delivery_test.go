@@ -0,0 +1,66 @@
+package models_test
+
+import (
+	"time"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) Test_NotificationDeliveryValidations() {
+	delivery := &NotificationDelivery{Status: "LOST", Attempts: -1}
+
+	var expErrors = map[string][]string{
+		"notification_type": {"NotificationType can not be blank."},
+		"recipient_email":   {"RecipientEmail can not be blank."},
+		"status":            {"Status is not in the list [PENDING, SENT, DEAD_LETTER]."},
+		"attempts":          {"-1 is not greater than -1."},
+		"next_attempt_at":   {"NextAttemptAt can not be blank."},
+	}
+
+	suite.verifyValidationErrors(delivery, expErrors)
+}
+
+func (suite *ModelSuite) Test_ClaimDueNotificationDeliveries() {
+	now := time.Now()
+	due := testdatagen.MakeDefaultNotificationDelivery(suite.DB())
+	testdatagen.MakeNotificationDelivery(suite.DB(), testdatagen.Assertions{
+		NotificationDelivery: NotificationDelivery{NextAttemptAt: now.Add(time.Hour)},
+	})
+	testdatagen.MakeNotificationDelivery(suite.DB(), testdatagen.Assertions{
+		NotificationDelivery: NotificationDelivery{Status: NotificationDeliveryStatusSENT},
+	})
+	testdatagen.MakeNotificationDelivery(suite.DB(), testdatagen.Assertions{
+		NotificationDelivery: NotificationDelivery{Status: NotificationDeliveryStatusDEADLETTER},
+	})
+
+	leaseUntil := now.Add(time.Hour)
+	deliveries, err := ClaimDueNotificationDeliveries(suite.DB(), now.Add(time.Minute), leaseUntil, 10)
+	suite.NoError(err)
+	if suite.Len(deliveries, 1) {
+		suite.Equal(due.ID, deliveries[0].ID)
+		suite.WithinDuration(leaseUntil, deliveries[0].NextAttemptAt, time.Second)
+	}
+
+	// A claimed delivery isn't claimed again until its lease is up
+	deliveries, err = ClaimDueNotificationDeliveries(suite.DB(), now.Add(time.Minute), leaseUntil, 10)
+	suite.NoError(err)
+	suite.Len(deliveries, 0)
+}
+
+func (suite *ModelSuite) Test_FetchNotificationDeliveriesForMove() {
+	move := testdatagen.MakeDefaultMove(suite.DB())
+	older := testdatagen.MakeNotificationDelivery(suite.DB(), testdatagen.Assertions{
+		NotificationDelivery: NotificationDelivery{CreatedAt: time.Now().Add(-time.Hour)},
+		Move:                 move,
+	})
+	newer := testdatagen.MakeNotificationDelivery(suite.DB(), testdatagen.Assertions{Move: move})
+	testdatagen.MakeDefaultNotificationDelivery(suite.DB())
+
+	deliveries, err := FetchNotificationDeliveriesForMove(suite.DB(), move.ID)
+	suite.NoError(err)
+	if suite.Len(deliveries, 2) {
+		suite.Equal(newer.ID, deliveries[0].ID)
+		suite.Equal(older.ID, deliveries[1].ID)
+	}
+}
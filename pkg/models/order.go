@@ -35,6 +35,7 @@ type Order struct {
 	ID                  uuid.UUID                          `json:"id" db:"id"`
 	CreatedAt           time.Time                          `json:"created_at" db:"created_at"`
 	UpdatedAt           time.Time                          `json:"updated_at" db:"updated_at"`
+	Version             int                                `json:"version" db:"version"`
 	ServiceMemberID     uuid.UUID                          `json:"service_member_id" db:"service_member_id"`
 	ServiceMember       ServiceMember                      `belongs_to:"service_members"`
 	IssueDate           time.Time                          `json:"issue_date" db:"issue_date"`
@@ -55,6 +56,7 @@ type Order struct {
 	TAC                 *string                            `json:"tac" db:"tac"`
 	SAC                 *string                            `json:"sac" db:"sac"`
 	DepartmentIndicator *string                            `json:"department_indicator" db:"department_indicator"`
+	VersionCheck        `db:"-" json:"-"`
 }
 
 // Orders is not required by pop and may be deleted
@@ -119,6 +121,11 @@ func (o *Order) Cancel() error {
 	return nil
 }
 
+// BeforeUpdate will run before each update of an Order, and increments its version.
+func (o *Order) BeforeUpdate(tx *pop.Connection) error {
+	return o.bumpVersion(tx, "orders", o.ID, &o.Version)
+}
+
 // AfterSave will run after each create/update of an Order.
 func (o *Order) AfterSave(tx *pop.Connection) error {
 	// Since the new duty station on the order can affect which TDL any shipment records
@@ -12,6 +12,7 @@ import (
 
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/statemachine"
 	"github.com/transcom/mymove/pkg/unit"
 )
 
@@ -40,6 +41,7 @@ type PersonallyProcuredMove struct {
 	Move                          Move                         `belongs_to:"move"`
 	CreatedAt                     time.Time                    `json:"created_at" db:"created_at"`
 	UpdatedAt                     time.Time                    `json:"updated_at" db:"updated_at"`
+	Version                       int                          `json:"version" db:"version"`
 	Size                          *internalmessages.TShirtSize `json:"size" db:"size"`
 	WeightEstimate                *unit.Pound                  `json:"weight_estimate" db:"weight_estimate"`
 	OriginalMoveDate              *time.Time                   `json:"original_move_date" db:"original_move_date"`
@@ -66,6 +68,8 @@ type PersonallyProcuredMove struct {
 	AdvanceWorksheet              Document                     `belongs_to:"documents"`
 	AdvanceWorksheetID            *uuid.UUID                   `json:"advance_worksheet_id" db:"advance_worksheet_id"`
 	TotalSITCost                  *unit.Cents                  `json:"total_sit_cost" db:"total_sit_cost"`
+	StateHistory                  `db:"-" json:"-"`
+	VersionCheck                  `db:"-" json:"-"`
 }
 
 // PersonallyProcuredMoves is a list of PPMs
@@ -92,68 +96,154 @@ func (p *PersonallyProcuredMove) ValidateUpdate(tx *pop.Connection) (*validate.E
 }
 
 // State Machinery
-// Avoid calling PersonallyProcuredMove.Status = ... ever. Use these methods to change the state.
+// The PPM lifecycle is declared in ppmStateMachine, which is the only thing that sets PersonallyProcuredMove.Status.
+// Use these methods to change the state.
 
-// Submit marks the PPM request for review
-func (p *PersonallyProcuredMove) Submit(submitDate time.Time) error {
-	if p.Status != PPMStatusDRAFT {
-		return errors.Wrap(ErrInvalidTransition, "Submit - status change")
-	}
+const (
+	ppmEventSubmit         statemachine.Event = "Submit"
+	ppmEventApprove        statemachine.Event = "Approve"
+	ppmEventRequestPayment statemachine.Event = "RequestPayment"
+	ppmEventComplete       statemachine.Event = "Complete"
+	ppmEventCancel         statemachine.Event = "Cancel"
+)
 
-	if p.SubmitDate != nil {
-		return errors.Wrap(ErrInvalidTransition, "Submit - submit date change")
-	}
+var ppmStateMachine = statemachine.MustNew(statemachine.Definition{
+	Name: "PersonallyProcuredMove",
+	States: []statemachine.State{
+		statemachine.State(PPMStatusDRAFT),
+		statemachine.State(PPMStatusSUBMITTED),
+		statemachine.State(PPMStatusAPPROVED),
+		statemachine.State(PPMStatusPAYMENTREQUESTED),
+		statemachine.State(PPMStatusCOMPLETED),
+		statemachine.State(PPMStatusCANCELED),
+	},
+	Transitions: []statemachine.Transition{
+		{
+			Event: ppmEventSubmit,
+			From:  []statemachine.State{statemachine.State(PPMStatusDRAFT)},
+			To:    statemachine.State(PPMStatusSUBMITTED),
+			Guards: []statemachine.Guard{{
+				Description: "submit date is not set",
+				Allow:       func(subject interface{}) bool { return subject.(*PersonallyProcuredMove).SubmitDate == nil },
+			}},
+			Effects: []statemachine.Effect{{
+				Description: "sets the submit date",
+				Apply: func(subject interface{}, args interface{}) error {
+					date, err := timeArg(args)
+					if err != nil {
+						return err
+					}
+					subject.(*PersonallyProcuredMove).SubmitDate = date
+					return nil
+				},
+			}},
+		},
+		{
+			Event: ppmEventApprove,
+			From: []statemachine.State{
+				statemachine.State(PPMStatusDRAFT),
+				statemachine.State(PPMStatusSUBMITTED),
+			},
+			To: statemachine.State(PPMStatusAPPROVED),
+			Guards: []statemachine.Guard{{
+				Description: "approve date is not set",
+				Allow:       func(subject interface{}) bool { return subject.(*PersonallyProcuredMove).ApproveDate == nil },
+			}},
+			Effects: []statemachine.Effect{{
+				Description: "sets the approve date",
+				Apply: func(subject interface{}, args interface{}) error {
+					date, err := timeArg(args)
+					if err != nil {
+						return err
+					}
+					subject.(*PersonallyProcuredMove).ApproveDate = date
+					return nil
+				},
+			}},
+		},
+		{
+			Event: ppmEventRequestPayment,
+			From: []statemachine.State{
+				statemachine.State(PPMStatusAPPROVED),
+				statemachine.State(PPMStatusPAYMENTREQUESTED),
+			},
+			To: statemachine.State(PPMStatusPAYMENTREQUESTED),
+		},
+		{
+			Event: ppmEventComplete,
+			From:  []statemachine.State{statemachine.State(PPMStatusPAYMENTREQUESTED)},
+			To:    statemachine.State(PPMStatusCOMPLETED),
+		},
+		{
+			// A PPM can be canceled until it is completed
+			Event: ppmEventCancel,
+			From: []statemachine.State{
+				statemachine.State(PPMStatusDRAFT),
+				statemachine.State(PPMStatusSUBMITTED),
+				statemachine.State(PPMStatusAPPROVED),
+				statemachine.State(PPMStatusPAYMENTREQUESTED),
+			},
+			To: statemachine.State(PPMStatusCANCELED),
+		},
+	},
+	GetState: func(subject interface{}) statemachine.State {
+		return statemachine.State(subject.(*PersonallyProcuredMove).Status)
+	},
+	SetState: func(subject interface{}, to statemachine.State) {
+		subject.(*PersonallyProcuredMove).Status = PPMStatus(to)
+	},
+	OnTransition: func(subject interface{}, from statemachine.State, to statemachine.State) {
+		subject.(*PersonallyProcuredMove).recordTransition(string(from), string(to), nil)
+	},
+})
 
-	p.Status = PPMStatusSUBMITTED
-	p.SubmitDate = &submitDate
-	return nil
+// Submit marks the PPM request for review
+func (p *PersonallyProcuredMove) Submit(submitDate time.Time) error {
+	return ppmStateMachine.Fire(p, ppmEventSubmit, submitDate)
 }
 
 // Approve approves the PPM to go forward.
 func (p *PersonallyProcuredMove) Approve(approveDate time.Time) error {
-	if !(p.Status == PPMStatusSUBMITTED || p.Status == PPMStatusDRAFT) {
-		return errors.Wrap(ErrInvalidTransition, "Approve - status change")
-	}
-
-	if p.ApproveDate != nil {
-		return errors.Wrap(ErrInvalidTransition, "Approve - approve date change")
-	}
-
-	p.Status = PPMStatusAPPROVED
-	p.ApproveDate = &approveDate
-	return nil
+	return ppmStateMachine.Fire(p, ppmEventApprove, approveDate)
 }
 
 // RequestPayment requests payment for the PPM
 func (p *PersonallyProcuredMove) RequestPayment() error {
-	if p.Status != PPMStatusPAYMENTREQUESTED && p.Status != PPMStatusAPPROVED {
-		return errors.Wrap(ErrInvalidTransition, "RequestPayment")
-	}
-
-	p.Status = PPMStatusPAYMENTREQUESTED
-	return nil
+	return ppmStateMachine.Fire(p, ppmEventRequestPayment, nil)
 }
 
 // Complete marks the PPM as completed
 func (p *PersonallyProcuredMove) Complete() error {
-	if p.Status != PPMStatusPAYMENTREQUESTED {
-		return errors.Wrap(ErrInvalidTransition, "Complete")
-	}
-
-	p.Status = PPMStatusCOMPLETED
-	return nil
+	return ppmStateMachine.Fire(p, ppmEventComplete, nil)
 }
 
 // Cancel marks the PPM as Canceled
 func (p *PersonallyProcuredMove) Cancel() error {
-	// The only type of PPM that can't be canceled is one that has been completed?
-	// Maybe you can cancel anything.
-	if p.Status == PPMStatusCOMPLETED || p.Status == PPMStatusCANCELED {
-		return errors.Wrap(ErrInvalidTransition, "Cancel")
+	return ppmStateMachine.Fire(p, ppmEventCancel, nil)
+}
+
+// CountsTowardEntitlement is true if the PPM's weight counts against the move's weight entitlement. Draft PPMs
+// haven't been submitted and canceled PPMs won't be moved, so they don't.
+func (p PersonallyProcuredMove) CountsTowardEntitlement() bool {
+	return p.Status != PPMStatusDRAFT && p.Status != PPMStatusCANCELED
+}
+
+// SetTransitionActor sets who is making the status changes to the PPM and its advance
+func (p *PersonallyProcuredMove) SetTransitionActor(actor TransitionActor) {
+	p.StateHistory.SetTransitionActor(actor)
+	if p.Advance != nil {
+		p.Advance.SetTransitionActor(actor)
 	}
+}
+
+// BeforeUpdate will run before each update of a PersonallyProcuredMove, and increments its version.
+func (p *PersonallyProcuredMove) BeforeUpdate(tx *pop.Connection) error {
+	return p.bumpVersion(tx, "personally_procured_moves", p.ID, &p.Version)
+}
 
-	p.Status = PPMStatusCANCELED
-	return nil
+// AfterSave will run after each create/update of a PersonallyProcuredMove, and saves its status changes.
+func (p *PersonallyProcuredMove) AfterSave(tx *pop.Connection) error {
+	return p.saveStateTransitions(tx, &p.MoveID, StateTransitionEntityTypePPM, p.ID)
 }
 
 // FetchMoveDocumentsForTypes returns all the linked move documents with the given document types
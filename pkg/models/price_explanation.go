@@ -0,0 +1,67 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// PriceExplanation is a node in the tree explaining how a charge was priced. The root explains the whole charge, and
+// its children explain the amounts, rates and rules it was computed from.
+type PriceExplanation struct {
+	Description string `json:"description"`
+	// AmountCents is set on nodes that are an amount of money
+	AmountCents *unit.Cents `json:"amount_cents,omitempty"`
+	// Detail is set on nodes that are a rate, quantity, discount or rule, e.g. "4,500 lbs" or "45.00%"
+	Detail   string                  `json:"detail,omitempty"`
+	Source   *PriceExplanationSource `json:"source,omitempty"`
+	Children []PriceExplanation      `json:"children,omitempty"`
+}
+
+// PriceExplanationSource identifies the database row a rate or value was taken from
+type PriceExplanationSource struct {
+	Table string    `json:"table"`
+	ID    uuid.UUID `json:"id"`
+	// Description describes the row, e.g. the weight and mileage bands of a linehaul rate
+	Description string `json:"description,omitempty"`
+}
+
+// Value stores the explanation as JSON
+func (e PriceExplanation) Value() (driver.Value, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal price explanation")
+	}
+	return string(b), nil
+}
+
+// Scan reads an explanation stored as JSON
+func (e *PriceExplanation) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.Errorf("cannot scan %T into a price explanation", src)
+	}
+	return errors.Wrap(json.Unmarshal(b, e), "could not unmarshal price explanation")
+}
+
+// Find returns the first node in the explanation, searching depth first, with the given description
+func (e *PriceExplanation) Find(description string) *PriceExplanation {
+	if e.Description == description {
+		return e
+	}
+	for i := range e.Children {
+		if found := e.Children[i].Find(description); found != nil {
+			return found
+		}
+	}
+	return nil
+}
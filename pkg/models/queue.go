@@ -1,10 +1,13 @@
 package models
 
 import (
+	"strings"
 	"time"
 
+	"github.com/go-openapi/swag"
 	"github.com/gobuffalo/pop"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/gen/internalmessages"
 )
@@ -37,13 +40,34 @@ type MoveQueueItem struct {
 	InvoiceApprovedDate        *time.Time                          `json:"invoice_approved_date" db:"invoice_approved_date"`
 }
 
-// GetMoveQueueItems gets all moveQueueItems for a specific lifecycleState
-func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueItem, error) {
-	var moveQueueItems []MoveQueueItem
-	var query string
+// moveQueueColumns are the columns every queue definition selects. Queues can be filtered and sorted on any of them.
+var moveQueueColumns = []string{
+	"id",
+	"created_at",
+	"edipi",
+	"rank",
+	"customer_name",
+	"locator",
+	"gbl_number",
+	"status",
+	"ppm_status",
+	"hhg_status",
+	"orders_type",
+	"move_date",
+	"submitted_date",
+	"last_modified_date",
+	"origin_duty_station_name",
+	"destination_duty_station_name",
+	"pm_survey_conducted_date",
+	"origin_gbloc",
+	"destination_gbloc",
+	"delivered_date",
+	"invoice_approved_date",
+}
 
-	if lifecycleState == "new" {
-		query = `
+// moveQueueDefinitions holds the query that selects the moves in each queue, keyed by lifecycle state
+var moveQueueDefinitions = map[string]string{
+	"new": `
 			SELECT moves.ID,
 				COALESCE(sm.edipi, '*missing*') as edipi,
 				COALESCE(sm.rank, '*missing*') as rank,
@@ -70,11 +94,18 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 				shipment.pm_survey_conducted_date as pm_survey_conducted_date,
 				json_agg(json_build_object('id', sits.id , 'location', sits.location, 'status', sits.status, 'actual_start_date', sits.actual_start_date, 'out_date', sits.out_date)) as sit_array,
 				json_agg(slis.status) as sli_array,
-				origin_duty_station.name as origin_duty_station_name
+				origin_duty_station.name as origin_duty_station_name,
+				destination_duty_station.name as destination_duty_station_name,
+				COALESCE(shipment.source_gbloc, origin_transportation_office.gbloc) as origin_gbloc,
+				shipment.destination_gbloc as destination_gbloc,
+				shipment.actual_delivery_date as delivered_date,
+				NULL::timestamp as invoice_approved_date
 			FROM moves
 			JOIN orders as ord ON moves.orders_id = ord.id
 			JOIN service_members AS sm ON ord.service_member_id = sm.id
 			JOIN duty_stations as origin_duty_station ON sm.duty_station_id = origin_duty_station.id
+			JOIN duty_stations as destination_duty_station ON ord.new_duty_station_id = destination_duty_station.id
+			LEFT JOIN transportation_offices as origin_transportation_office ON origin_duty_station.transportation_office_id = origin_transportation_office.id
 			LEFT JOIN shipments AS shipment ON moves.id = shipment.move_id
 			LEFT JOIN personally_procured_moves AS ppm ON moves.id = ppm.move_id
 			LEFT JOIN storage_in_transits as sits ON sits.shipment_id = shipment.id
@@ -83,10 +114,10 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 			OR ((shipment.status in ('SUBMITTED', 'AWARDED', 'ACCEPTED') OR ppm.status = 'SUBMITTED')
 				AND (NOT moves.status in ('CANCELED', 'DRAFT'))))
 			AND moves.show is true
-			GROUP BY moves.ID, rank, customer_name, edipi, locator, orders_type, move_date, moves.created_at, last_modified_date, moves.status, shipment.id, ppm.submit_date, ppm_status, origin_duty_station.name
-		`
-	} else if lifecycleState == "ppm" {
-		query = `
+			GROUP BY moves.ID, rank, customer_name, edipi, locator, orders_type, move_date, moves.created_at, last_modified_date, moves.status, shipment.id, ppm.submit_date, ppm_status, origin_duty_station.name,
+			destination_duty_station.name, origin_transportation_office.gbloc
+		`,
+	"ppm": `
 			SELECT moves.ID,
 				COALESCE(sm.edipi, '*missing*') as edipi,
 				COALESCE(sm.rank, '*missing*') as rank,
@@ -94,26 +125,33 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 				moves.locator as locator,
 				ord.orders_type as orders_type,
 				COALESCE(ppm.actual_move_date, ppm.original_move_date) as move_date,
+				ppm.submit_date as submitted_date,
 				moves.created_at as created_at,
 				ppm.updated_at as last_modified_date,
 				moves.status as status,
 				ppm.status as ppm_status,
+				shipment.status as hhg_status,
 				shipment.gbl_number as gbl_number,
+				shipment.pm_survey_conducted_date as pm_survey_conducted_date,
 				origin_duty_station.name as origin_duty_station_name,
-				destination_duty_station.name as destination_duty_station_name
+				destination_duty_station.name as destination_duty_station_name,
+				COALESCE(shipment.source_gbloc, origin_transportation_office.gbloc) as origin_gbloc,
+				shipment.destination_gbloc as destination_gbloc,
+				shipment.actual_delivery_date as delivered_date,
+				NULL::timestamp as invoice_approved_date
 			FROM moves
 			JOIN orders as ord ON moves.orders_id = ord.id
 			JOIN service_members AS sm ON ord.service_member_id = sm.id
 			JOIN personally_procured_moves AS ppm ON moves.id = ppm.move_id
 			JOIN duty_stations as origin_duty_station ON sm.duty_station_id = origin_duty_station.id
 			JOIN duty_stations as destination_duty_station ON ord.new_duty_station_id = destination_duty_station.id
+			LEFT JOIN transportation_offices as origin_transportation_office ON origin_duty_station.transportation_office_id = origin_transportation_office.id
 			LEFT JOIN shipments AS shipment ON moves.id = shipment.move_id
 			WHERE moves.show is true
 			and ppm.status in ('APPROVED', 'PAYMENT_REQUESTED', 'COMPLETED')
-		`
-	} else if lifecycleState == "hhg_active" {
-		// Move date is the Actual Pickup Date.
-		query = `
+		`,
+	// Move date is the Actual Pickup Date.
+	"hhg_active": `
 			SELECT moves.ID,
 				COALESCE(sm.edipi, '*missing*') as edipi,
 				COALESCE(sm.rank, '*missing*') as rank,
@@ -121,9 +159,11 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 				moves.locator as locator,
 				ord.orders_type as orders_type,
 				shipment.actual_pickup_date as move_date,
+				shipment.submit_date as submitted_date,
 				moves.created_at as created_at,
 				moves.updated_at as last_modified_date,
 				moves.status as status,
+				NULL::text as ppm_status,
 				shipment.status as hhg_status,
 				shipment.gbl_number as gbl_number,
 				origin_duty_station.name as origin_duty_station_name,
@@ -131,22 +171,27 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 				shipment.id as shipment_id,
 				shipment.pm_survey_conducted_date as pm_survey_conducted_date,
 				json_agg(json_build_object('id', sits.id , 'location', sits.location, 'status', sits.status, 'actual_start_date', sits.actual_start_date, 'out_date', sits.out_date)) as sit_array,
-				json_agg(slis.status) as sli_array
+				json_agg(slis.status) as sli_array,
+				COALESCE(shipment.source_gbloc, origin_transportation_office.gbloc) as origin_gbloc,
+				shipment.destination_gbloc as destination_gbloc,
+				shipment.actual_delivery_date as delivered_date,
+				NULL::timestamp as invoice_approved_date
 			FROM moves
 			JOIN orders as ord ON moves.orders_id = ord.id
 			JOIN service_members AS sm ON ord.service_member_id = sm.id
 			JOIN duty_stations as origin_duty_station ON sm.duty_station_id = origin_duty_station.id
 			JOIN duty_stations as destination_duty_station ON ord.new_duty_station_id = destination_duty_station.id
+			LEFT JOIN transportation_offices as origin_transportation_office ON origin_duty_station.transportation_office_id = origin_transportation_office.id
 			LEFT JOIN shipments as shipment ON moves.id = shipment.move_id
 			LEFT JOIN storage_in_transits as sits ON sits.shipment_id = shipment.id
 			LEFT JOIN shipment_line_items as slis ON slis.shipment_id = shipment.id
 			WHERE ((shipment.status IN ('IN_TRANSIT', 'APPROVED')) OR (shipment.status = 'ACCEPTED' AND shipment.pm_survey_conducted_date IS NOT NULL))
 			AND moves.show is true AND moves.status != 'CANCELED'
-			GROUP BY moves.ID, rank, customer_name, edipi, locator, orders_type, move_date, moves.created_at, last_modified_date, moves.status, origin_duty_station_name, destination_duty_station_name, shipment.id
-		`
-	} else if lifecycleState == "hhg_in_transit" {
-		// Move date is the Actual Pickup Date.
-		query = `
+			GROUP BY moves.ID, rank, customer_name, edipi, locator, orders_type, move_date, moves.created_at, last_modified_date, moves.status, origin_duty_station_name, destination_duty_station_name, shipment.id,
+			origin_transportation_office.gbloc
+		`,
+	// Move date is the Actual Pickup Date.
+	"hhg_in_transit": `
 			SELECT moves.ID,
 				COALESCE(sm.edipi, '*missing*') as edipi,
 				COALESCE(sm.rank, '*missing*') as rank,
@@ -154,21 +199,32 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 				moves.locator as locator,
 				ord.orders_type as orders_type,
 				shipment.actual_pickup_date as move_date,
+				shipment.submit_date as submitted_date,
 				moves.created_at as created_at,
 				moves.updated_at as last_modified_date,
 				moves.status as status,
+				NULL::text as ppm_status,
 				shipment.status as hhg_status,
-				shipment.gbl_number as gbl_number
+				shipment.gbl_number as gbl_number,
+				shipment.pm_survey_conducted_date as pm_survey_conducted_date,
+				COALESCE(origin_duty_station.name, '') as origin_duty_station_name,
+				COALESCE(destination_duty_station.name, '') as destination_duty_station_name,
+				COALESCE(shipment.source_gbloc, origin_transportation_office.gbloc) as origin_gbloc,
+				shipment.destination_gbloc as destination_gbloc,
+				shipment.actual_delivery_date as delivered_date,
+				NULL::timestamp as invoice_approved_date
 			FROM moves
 			JOIN orders as ord ON moves.orders_id = ord.id
 			JOIN service_members AS sm ON ord.service_member_id = sm.id
+			LEFT JOIN duty_stations as origin_duty_station ON sm.duty_station_id = origin_duty_station.id
+			LEFT JOIN duty_stations as destination_duty_station ON ord.new_duty_station_id = destination_duty_station.id
+			LEFT JOIN transportation_offices as origin_transportation_office ON origin_duty_station.transportation_office_id = origin_transportation_office.id
 			LEFT JOIN shipments as shipment ON moves.id = shipment.move_id
 			WHERE shipment.status = 'IN_TRANSIT'
 			and moves.show is true
-		`
-	} else if lifecycleState == "hhg_delivered" {
-		// Move date is the Actual Pickup Date.
-		query = `
+		`,
+	// Move date is the Actual Pickup Date.
+	"hhg_delivered": `
 			SELECT moves.ID,
 				COALESCE(sm.edipi, '*missing*') as edipi,
 				COALESCE(sm.rank, '*missing*') as rank,
@@ -176,21 +232,28 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 				moves.locator as locator,
 				ord.orders_type as orders_type,
 				shipment.actual_pickup_date as move_date,
+				shipment.submit_date as submitted_date,
 				moves.created_at as created_at,
 				moves.updated_at as last_modified_date,
 				moves.status as status,
+				NULL::text as ppm_status,
 				shipment.status as hhg_status,
 				shipment.gbl_number as gbl_number,
 				shipment.pm_survey_conducted_date as pm_survey_conducted_date,
 				json_agg(json_build_object('id', sits.id , 'location', sits.location, 'status', sits.status, 'actual_start_date', sits.actual_start_date, 'out_date', sits.out_date)) as sit_array,
 				json_agg(slis.status) as sli_array,
-				shipment.source_gbloc as origin_gbloc,
+				COALESCE(origin_duty_station.name, '') as origin_duty_station_name,
+				COALESCE(destination_duty_station.name, '') as destination_duty_station_name,
+				COALESCE(shipment.source_gbloc, origin_transportation_office.gbloc) as origin_gbloc,
 				shipment.destination_gbloc as destination_gbloc,
 				shipment.actual_delivery_date as delivered_date,
 				(case when invoice.status = 'SUBMITTED' then invoice.invoiced_date end) as invoice_approved_date
 			FROM moves
 			JOIN orders as ord ON moves.orders_id = ord.id
 			JOIN service_members AS sm ON ord.service_member_id = sm.id
+			LEFT JOIN duty_stations as origin_duty_station ON sm.duty_station_id = origin_duty_station.id
+			LEFT JOIN duty_stations as destination_duty_station ON ord.new_duty_station_id = destination_duty_station.id
+			LEFT JOIN transportation_offices as origin_transportation_office ON origin_duty_station.transportation_office_id = origin_transportation_office.id
 			LEFT JOIN shipments as shipment ON moves.id = shipment.move_id
 			LEFT JOIN storage_in_transits as sits ON sits.shipment_id = shipment.id
 			LEFT JOIN shipment_line_items as slis ON slis.shipment_id = shipment.id
@@ -198,11 +261,10 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 			WHERE shipment.status = 'DELIVERED'
 			and moves.show is true
 			GROUP BY moves.ID, rank, customer_name, edipi, locator, orders_type, move_date, moves.created_at, last_modified_date, moves.status,
-			shipment.id, origin_gbloc, destination_gbloc, invoice_approved_date, delivered_date
+			shipment.id, origin_gbloc, destination_gbloc, invoice_approved_date, delivered_date, origin_duty_station.name, destination_duty_station.name
 
-		`
-	} else if lifecycleState == "all" {
-		query = `
+		`,
+	"all": `
 			SELECT moves.ID,
 				COALESCE(sm.edipi, '*missing*') as edipi,
 				COALESCE(sm.rank, '*missing*') as rank,
@@ -216,13 +278,23 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 					ppm.actual_move_date,
 					ppm.original_move_date
 				) as move_date,
+				COALESCE(
+					shipment.submit_date,
+					ppm.submit_date
+				) as submitted_date,
 				moves.created_at as created_at,
 				moves.updated_at as last_modified_date,
 				moves.status as status,
 				ppm.status as ppm_status,
+				shipment.status as hhg_status,
 				shipment.gbl_number as gbl_number,
+				shipment.pm_survey_conducted_date as pm_survey_conducted_date,
 				origin_duty_station.name as origin_duty_station_name,
-				destination_duty_station.name as destination_duty_station_name
+				destination_duty_station.name as destination_duty_station_name,
+				COALESCE(shipment.source_gbloc, origin_transportation_office.gbloc) as origin_gbloc,
+				shipment.destination_gbloc as destination_gbloc,
+				shipment.actual_delivery_date as delivered_date,
+				NULL::timestamp as invoice_approved_date
 			FROM moves
 			JOIN orders as ord ON moves.orders_id = ord.id
 			JOIN service_members AS sm ON ord.service_member_id = sm.id
@@ -230,12 +302,152 @@ func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueIt
 			LEFT JOIN personally_procured_moves AS ppm ON moves.id = ppm.move_id
 			JOIN duty_stations as origin_duty_station ON sm.duty_station_id = origin_duty_station.id
 			JOIN duty_stations as destination_duty_station ON ord.new_duty_station_id = destination_duty_station.id
+			LEFT JOIN transportation_offices as origin_transportation_office ON origin_duty_station.transportation_office_id = origin_transportation_office.id
 			WHERE moves.show is true
-		`
-	} else {
-		return moveQueueItems, ErrFetchNotFound
-	}
+		`,
+}
+
+// MoveQueueSort orders a queue by one of its columns
+type MoveQueueSort struct {
+	Column     string
+	Descending bool
+}
+
+// MoveQueueParams narrows down, orders and pages the moves in a queue. Empty fields are ignored.
+type MoveQueueParams struct {
+	OriginGBLOCs []string
+	Statuses     []string
+	PpmStatuses  []string
+	HhgStatuses  []string
+	Ranks        []string
+	MoveDateFrom *time.Time
+	MoveDateTo   *time.Time
+	// Search matches the start of the customer's last name, "Last, First" name, or EDIPI
+	Search *string
+	// Sort defaults to the last modified date, oldest first
+	Sort    []MoveQueueSort
+	Page    *int
+	PerPage *int
+}
+
+// maxMoveQueuePerPage is the most queue items that can be requested at once
+const maxMoveQueuePerPage = 100
 
-	err := db.RawQuery(query).All(&moveQueueItems)
+// GetMoveQueueItems gets all moveQueueItems for a specific lifecycleState
+func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueItem, error) {
+	moveQueueItems, _, err := FetchMoveQueueItems(db, lifecycleState, MoveQueueParams{})
 	return moveQueueItems, err
 }
+
+// FetchMoveQueueItems gets the moveQueueItems for a specific lifecycleState that match params, along with the
+// total number of matching items across all pages
+func FetchMoveQueueItems(db *pop.Connection, lifecycleState string, params MoveQueueParams) ([]MoveQueueItem, int, error) {
+	var moveQueueItems []MoveQueueItem
+
+	definition, ok := moveQueueDefinitions[lifecycleState]
+	if !ok {
+		return moveQueueItems, 0, ErrFetchNotFound
+	}
+
+	orderBy, err := moveQueueOrderBy(params.Sort)
+	if err != nil {
+		return moveQueueItems, 0, err
+	}
+
+	where, args := moveQueueWhere(params)
+	sql := "SELECT * FROM (" + definition + ") AS queue" + where + " ORDER BY " + orderBy
+	query := db.RawQuery(sql, args...)
+
+	if params.Page == nil && params.PerPage == nil {
+		err = query.All(&moveQueueItems)
+		return moveQueueItems, len(moveQueueItems), err
+	}
+
+	page, perPage := 1, maxMoveQueuePerPage
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if params.PerPage != nil {
+		perPage = *params.PerPage
+	}
+	if page < 1 || perPage < 1 || perPage > maxMoveQueuePerPage {
+		return moveQueueItems, 0, errors.Wrapf(ErrInvalidQueryParams, "page %d per_page %d", page, perPage)
+	}
+
+	query = query.Paginate(page, perPage)
+	err = query.All(&moveQueueItems)
+	if err != nil {
+		return moveQueueItems, 0, err
+	}
+	return moveQueueItems, query.Paginator.TotalEntriesSize, nil
+}
+
+func moveQueueWhere(params MoveQueueParams) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	in := func(column string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		conditions = append(conditions, column+" IN ("+placeholders+")")
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+	in("origin_gbloc", params.OriginGBLOCs)
+	in("status", params.Statuses)
+	in("ppm_status", params.PpmStatuses)
+	in("hhg_status", params.HhgStatuses)
+	in("rank", params.Ranks)
+
+	if params.MoveDateFrom != nil {
+		conditions = append(conditions, "move_date >= ?")
+		args = append(args, *params.MoveDateFrom)
+	}
+	if params.MoveDateTo != nil {
+		conditions = append(conditions, "move_date <= ?")
+		args = append(args, *params.MoveDateTo)
+	}
+	if params.Search != nil && strings.TrimSpace(*params.Search) != "" {
+		search := likeEscaper.Replace(strings.TrimSpace(*params.Search)) + "%"
+		conditions = append(conditions, `(customer_name ILIKE ? ESCAPE '\' OR edipi LIKE ? ESCAPE '\')`)
+		args = append(args, search, search)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// likeEscaper escapes the LIKE wildcards in a search term, so it only matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func moveQueueOrderBy(sorts []MoveQueueSort) (string, error) {
+	if len(sorts) == 0 {
+		sorts = []MoveQueueSort{{Column: "last_modified_date"}}
+	}
+
+	var invalidColumns []string
+	orderBy := make([]string, 0, len(sorts)+1)
+	for _, sort := range sorts {
+		if !swag.ContainsStrings(moveQueueColumns, sort.Column) {
+			invalidColumns = append(invalidColumns, sort.Column)
+			continue
+		}
+		direction := "ASC"
+		if sort.Descending {
+			direction = "DESC"
+		}
+		orderBy = append(orderBy, sort.Column+" "+direction)
+	}
+	if len(invalidColumns) > 0 {
+		return "", errors.Wrapf(ErrInvalidQueryParams, "sort %s", strings.Join(invalidColumns, ", "))
+	}
+
+	// Break ties on the move ID so pages are stable
+	orderBy = append(orderBy, "id ASC")
+	return strings.Join(orderBy, ", "), nil
+}
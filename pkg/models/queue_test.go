@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/models"
 	. "github.com/transcom/mymove/pkg/models"
@@ -137,3 +138,106 @@ func (suite *ModelSuite) TestActivePPMQueue() {
 	suite.NoError(err)
 	suite.Len(moves, 3)
 }
+
+func (suite *ModelSuite) TestFetchMoveQueueItems() {
+	e4 := models.ServiceMemberRankE4
+	o3 := models.ServiceMemberRankO3
+	early := testdatagen.DateInsidePeakRateCycle
+	late := early.AddDate(0, 0, 14)
+
+	adams := testdatagen.MakePPM(suite.DB(), testdatagen.Assertions{
+		ServiceMember: models.ServiceMember{
+			LastName: models.StringPointer("Adams"),
+			Edipi:    models.StringPointer("1234567890"),
+			Rank:     &e4,
+		},
+		PersonallyProcuredMove: models.PersonallyProcuredMove{
+			Status:           models.PPMStatusAPPROVED,
+			OriginalMoveDate: &early,
+		},
+	})
+	baker := testdatagen.MakePPM(suite.DB(), testdatagen.Assertions{
+		ServiceMember: models.ServiceMember{
+			LastName: models.StringPointer("Baker"),
+			Rank:     &o3,
+		},
+		PersonallyProcuredMove: models.PersonallyProcuredMove{
+			Status:           models.PPMStatusCOMPLETED,
+			OriginalMoveDate: &late,
+		},
+	})
+
+	// Without params every move in the queue is returned
+	moves, count, err := FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{})
+	suite.NoError(err)
+	suite.Len(moves, 2)
+	suite.Equal(2, count)
+
+	// Filters
+	moves, count, err = FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{Ranks: []string{string(o3)}})
+	suite.NoError(err)
+	suite.Equal(1, count)
+	suite.Equal(baker.MoveID, moves[0].ID)
+
+	moves, _, err = FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{PpmStatuses: []string{string(models.PPMStatusAPPROVED)}})
+	suite.NoError(err)
+	suite.Len(moves, 1)
+	suite.Equal(adams.MoveID, moves[0].ID)
+
+	moveDateFrom := early.AddDate(0, 0, 1)
+	moves, _, err = FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{MoveDateFrom: &moveDateFrom})
+	suite.NoError(err)
+	suite.Len(moves, 1)
+	suite.Equal(baker.MoveID, moves[0].ID)
+
+	moves, _, err = FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{Search: models.StringPointer("bak")})
+	suite.NoError(err)
+	suite.Len(moves, 1)
+	suite.Equal(baker.MoveID, moves[0].ID)
+
+	moves, _, err = FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{Search: models.StringPointer("12345")})
+	suite.NoError(err)
+	suite.Len(moves, 1)
+	suite.Equal(adams.MoveID, moves[0].ID)
+
+	// Wildcards in the search term only match themselves
+	for _, wildcard := range []string{"%", "_", "%ak"} {
+		moves, _, err = FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{Search: models.StringPointer(wildcard)})
+		suite.NoError(err)
+		suite.Len(moves, 0, wildcard)
+	}
+
+	// Sorting and paging
+	perPage := 1
+	page := 2
+	moves, count, err = FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{
+		Sort:    []MoveQueueSort{{Column: "move_date", Descending: true}},
+		Page:    &page,
+		PerPage: &perPage,
+	})
+	suite.NoError(err)
+	suite.Equal(2, count)
+	suite.Len(moves, 1)
+	suite.Equal(adams.MoveID, moves[0].ID)
+
+	// Invalid params
+	_, _, err = FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{Sort: []MoveQueueSort{{Column: "sm.ssn"}}})
+	suite.Equal(ErrInvalidQueryParams, errors.Cause(err))
+
+	perPage = 1000
+	_, _, err = FetchMoveQueueItems(suite.DB(), "ppm", MoveQueueParams{PerPage: &perPage})
+	suite.Equal(ErrInvalidQueryParams, errors.Cause(err))
+
+	// Every queue can be filtered and sorted on every column
+	for lifecycleState := range map[string]bool{"new": true, "ppm": true, "hhg_active": true, "hhg_in_transit": true, "hhg_delivered": true, "all": true} {
+		_, _, err = FetchMoveQueueItems(suite.DB(), lifecycleState, MoveQueueParams{
+			OriginGBLOCs: []string{"LKNQ"},
+			Statuses:     []string{string(MoveStatusSUBMITTED)},
+			HhgStatuses:  []string{string(ShipmentStatusDELIVERED)},
+			Sort: []MoveQueueSort{
+				{Column: "origin_gbloc"}, {Column: "customer_name", Descending: true}, {Column: "invoice_approved_date"},
+			},
+		})
+		suite.NoError(err, lifecycleState)
+	}
+}
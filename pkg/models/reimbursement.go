@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/statemachine"
 	"github.com/transcom/mymove/pkg/unit"
 )
 
@@ -50,51 +51,106 @@ type Reimbursement struct {
 	MethodOfReceipt MethodOfReceipt     `json:"method_of_receipt" db:"method_of_receipt"`
 	Status          ReimbursementStatus `json:"status" db:"status"`
 	RequestedDate   *time.Time          `json:"requested_date" db:"requested_date"`
+	StateHistory    `db:"-" json:"-"`
 }
 
 // State Machine
-// Avoid calling Reimbursement.Status = ... ever. Use these methods to change the state.
+// The reimbursement lifecycle is declared in reimbursementStateMachine, which is the only thing that sets
+// Reimbursement.Status. Use these methods to change the state.
+
+const (
+	reimbursementEventRequest statemachine.Event = "Request"
+	reimbursementEventApprove statemachine.Event = "Approve"
+	reimbursementEventReject  statemachine.Event = "Reject"
+	reimbursementEventPay     statemachine.Event = "Pay"
+)
+
+var reimbursementStateMachine = statemachine.MustNew(statemachine.Definition{
+	Name: "Reimbursement",
+	States: []statemachine.State{
+		statemachine.State(ReimbursementStatusDRAFT),
+		statemachine.State(ReimbursementStatusREQUESTED),
+		statemachine.State(ReimbursementStatusAPPROVED),
+		statemachine.State(ReimbursementStatusREJECTED),
+		statemachine.State(ReimbursementStatusPAID),
+	},
+	Transitions: []statemachine.Transition{
+		{
+			Event: reimbursementEventRequest,
+			From:  []statemachine.State{statemachine.State(ReimbursementStatusDRAFT)},
+			To:    statemachine.State(ReimbursementStatusREQUESTED),
+			Effects: []statemachine.Effect{{
+				Description: "sets the requested date to today",
+				Apply: func(subject interface{}, args interface{}) error {
+					today := time.Now()
+					subject.(*Reimbursement).RequestedDate = &today
+					return nil
+				},
+			}},
+		},
+		{
+			Event: reimbursementEventApprove,
+			From:  []statemachine.State{statemachine.State(ReimbursementStatusREQUESTED)},
+			To:    statemachine.State(ReimbursementStatusAPPROVED),
+		},
+		{
+			Event: reimbursementEventReject,
+			From:  []statemachine.State{statemachine.State(ReimbursementStatusREQUESTED)},
+			To:    statemachine.State(ReimbursementStatusREJECTED),
+		},
+		{
+			Event: reimbursementEventPay,
+			From:  []statemachine.State{statemachine.State(ReimbursementStatusAPPROVED)},
+			To:    statemachine.State(ReimbursementStatusPAID),
+		},
+	},
+	GetState: func(subject interface{}) statemachine.State {
+		return statemachine.State(subject.(*Reimbursement).Status)
+	},
+	SetState: func(subject interface{}, to statemachine.State) {
+		subject.(*Reimbursement).Status = ReimbursementStatus(to)
+	},
+	OnTransition: func(subject interface{}, from statemachine.State, to statemachine.State) {
+		subject.(*Reimbursement).recordTransition(string(from), string(to), nil)
+	},
+})
 
 // Request officially requests the reimbursement.
 func (r *Reimbursement) Request() error {
-	if r.Status != ReimbursementStatusDRAFT {
-		return errors.Wrap(ErrInvalidTransition, "Request")
-	}
-
-	r.Status = ReimbursementStatusREQUESTED
-	today := time.Now()
-	r.RequestedDate = &today
-	return nil
+	return reimbursementStateMachine.Fire(r, reimbursementEventRequest, nil)
 }
 
 // Approve approves the Reimbursement
 func (r *Reimbursement) Approve() error {
-	if r.Status != ReimbursementStatusREQUESTED {
-		return errors.Wrap(ErrInvalidTransition, "Approve")
-	}
-
-	r.Status = ReimbursementStatusAPPROVED
-	return nil
+	return reimbursementStateMachine.Fire(r, reimbursementEventApprove, nil)
 }
 
 // Reject rejects the Reimbursement
 func (r *Reimbursement) Reject() error {
-	if r.Status != ReimbursementStatusREQUESTED {
-		return errors.Wrap(ErrInvalidTransition, "Reject")
-	}
-
-	r.Status = ReimbursementStatusREJECTED
-	return nil
+	return reimbursementStateMachine.Fire(r, reimbursementEventReject, nil)
 }
 
 // Pay pays the Reimbursement
 func (r *Reimbursement) Pay() error {
-	if r.Status != ReimbursementStatusAPPROVED {
-		return errors.Wrap(ErrInvalidTransition, "Pay")
-	}
+	return reimbursementStateMachine.Fire(r, reimbursementEventPay, nil)
+}
 
-	r.Status = ReimbursementStatusPAID
-	return nil
+// AfterSave will run after each create/update of a Reimbursement, and saves its status changes against the move
+// of the PPM it is an advance for.
+func (r *Reimbursement) AfterSave(tx *pop.Connection) error {
+	if len(r.pending) == 0 {
+		return nil
+	}
+	var moveID *uuid.UUID
+	var ppms PersonallyProcuredMoves
+	err := tx.Where("advance_id = ?", r.ID).All(&ppms)
+	if err != nil {
+		return errors.Wrap(err, "Could not fetch PPM for reimbursement")
+	}
+	if len(ppms) > 0 {
+		moveID = &ppms[0].MoveID
+	}
+	return r.saveStateTransitions(tx, moveID, StateTransitionEntityTypeREIMBURSEMENT, r.ID)
 }
 
 // END State Machine
@@ -12,6 +12,7 @@ import (
 
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/dates"
+	"github.com/transcom/mymove/pkg/statemachine"
 	"github.com/transcom/mymove/pkg/unit"
 )
 
@@ -74,6 +75,7 @@ type Shipment struct {
 	Market           *string        `json:"market" db:"market"`
 	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at" db:"updated_at"`
+	Version          int            `json:"version" db:"version"`
 
 	// associations
 	TrafficDistributionListID *uuid.UUID               `json:"traffic_distribution_list_id" db:"traffic_distribution_list_id"`
@@ -140,6 +142,9 @@ type Shipment struct {
 	PmSurveySpouseProgearWeightEstimate *unit.Pound `json:"pm_survey_spouse_progear_weight_estimate" db:"pm_survey_spouse_progear_weight_estimate"`
 	PmSurveyNotes                       *string     `json:"pm_survey_notes" db:"pm_survey_notes"`
 	PmSurveyMethod                      string      `json:"pm_survey_method" db:"pm_survey_method"`
+
+	StateHistory `db:"-" json:"-"`
+	VersionCheck `db:"-" json:"-"`
 }
 
 // Shipments is not required by pop and may be deleted
@@ -205,6 +210,12 @@ func (s *Shipment) Validate(tx *pop.Connection) (*validate.Errors, error) {
 	return validate.Validate(validations...), nil
 }
 
+// CountsTowardEntitlement is true if the shipment's weight counts against the move's weight entitlement. Draft
+// shipments haven't been submitted, so they don't.
+func (s Shipment) CountsTowardEntitlement() bool {
+	return s.Status != ShipmentStatusDRAFT
+}
+
 // CurrentTransportationServiceProviderID returns the id for the current TSP for a shipment
 // Assume that the last shipmentOffer contains the current TSP
 // This might be a bad assumption, but TSPs can't currently reject offers
@@ -218,109 +229,228 @@ func (s *Shipment) CurrentTransportationServiceProviderID() uuid.UUID {
 	return id
 }
 
-// State Machinery
-// Avoid calling Shipment.Status = ... ever. Use these methods to change the state.
+// State Machine
+// The shipment lifecycle is declared in shipmentStateMachine, which is the only thing that sets Shipment.Status.
+// Use these methods to change the state.
+
+const (
+	shipmentEventSubmit    statemachine.Event = "Submit"
+	shipmentEventAward     statemachine.Event = "Award"
+	shipmentEventAccept    statemachine.Event = "Accept"
+	shipmentEventReject    statemachine.Event = "Reject"
+	shipmentEventApprove   statemachine.Event = "Approve"
+	shipmentEventPack      statemachine.Event = "Pack"
+	shipmentEventTransport statemachine.Event = "Transport"
+	shipmentEventDeliver   statemachine.Event = "Deliver"
+)
+
+var shipmentStateMachine = statemachine.MustNew(statemachine.Definition{
+	Name: "Shipment",
+	States: []statemachine.State{
+		statemachine.State(ShipmentStatusDRAFT),
+		statemachine.State(ShipmentStatusSUBMITTED),
+		statemachine.State(ShipmentStatusAWARDED),
+		statemachine.State(ShipmentStatusACCEPTED),
+		statemachine.State(ShipmentStatusAPPROVED),
+		statemachine.State(ShipmentStatusINTRANSIT),
+		statemachine.State(ShipmentStatusDELIVERED),
+	},
+	Transitions: []statemachine.Transition{
+		{
+			Event: shipmentEventSubmit,
+			From:  []statemachine.State{statemachine.State(ShipmentStatusDRAFT)},
+			To:    statemachine.State(ShipmentStatusSUBMITTED),
+			Effects: []statemachine.Effect{{
+				Description: "sets the book date to now and the submit date",
+				Apply: func(subject interface{}, args interface{}) error {
+					submitDate, err := timeArg(args)
+					if err != nil {
+						return err
+					}
+					s := subject.(*Shipment)
+					now := time.Now()
+					s.BookDate = &now
+					s.SubmitDate = submitDate
+					return nil
+				},
+			}},
+		},
+		{
+			// Awarded by the award queue to a TSP
+			Event: shipmentEventAward,
+			From:  []statemachine.State{statemachine.State(ShipmentStatusSUBMITTED)},
+			To:    statemachine.State(ShipmentStatusAWARDED),
+		},
+		{
+			Event: shipmentEventAccept,
+			From:  []statemachine.State{statemachine.State(ShipmentStatusAWARDED)},
+			To:    statemachine.State(ShipmentStatusACCEPTED),
+		},
+		{
+			// Rejected by the TSP it was awarded to, so it goes back to the award queue
+			Event: shipmentEventReject,
+			From:  []statemachine.State{statemachine.State(ShipmentStatusAWARDED)},
+			To:    statemachine.State(ShipmentStatusSUBMITTED),
+		},
+		{
+			Event: shipmentEventApprove,
+			From:  []statemachine.State{statemachine.State(ShipmentStatusACCEPTED)},
+			To:    statemachine.State(ShipmentStatusAPPROVED),
+			Guards: []statemachine.Guard{{
+				Description: "approve date is not set",
+				Allow:       func(subject interface{}) bool { return subject.(*Shipment).ApproveDate == nil },
+			}},
+			Effects: []statemachine.Effect{{
+				Description: "sets the approve date",
+				Apply: func(subject interface{}, args interface{}) error {
+					date, err := timeArg(args)
+					if err != nil {
+						return err
+					}
+					subject.(*Shipment).ApproveDate = date
+					return nil
+				},
+			}},
+		},
+		{
+			// TODO: cgilmer 2018/10/18 - fold this into Transport when the fields are merged in the UI
+			Event: shipmentEventPack,
+			From:  []statemachine.State{statemachine.State(ShipmentStatusAPPROVED)},
+			To:    statemachine.State(ShipmentStatusAPPROVED),
+			Effects: []statemachine.Effect{{
+				Description: "sets the actual pack date",
+				Apply: func(subject interface{}, args interface{}) error {
+					date, err := timeArg(args)
+					if err != nil {
+						return err
+					}
+					subject.(*Shipment).ActualPackDate = date
+					return nil
+				},
+			}},
+		},
+		{
+			Event: shipmentEventTransport,
+			From:  []statemachine.State{statemachine.State(ShipmentStatusAPPROVED)},
+			To:    statemachine.State(ShipmentStatusINTRANSIT),
+			Effects: []statemachine.Effect{{
+				Description: "sets the actual pickup date",
+				Apply: func(subject interface{}, args interface{}) error {
+					date, err := timeArg(args)
+					if err != nil {
+						return err
+					}
+					subject.(*Shipment).ActualPickupDate = date
+					return nil
+				},
+			}},
+		},
+		{
+			Event: shipmentEventDeliver,
+			From:  []statemachine.State{statemachine.State(ShipmentStatusINTRANSIT)},
+			To:    statemachine.State(ShipmentStatusDELIVERED),
+			Effects: []statemachine.Effect{
+				{
+					Description: "sets the actual delivery date",
+					Apply: func(subject interface{}, args interface{}) error {
+						date, err := timeArg(args)
+						if err != nil {
+							return err
+						}
+						subject.(*Shipment).ActualDeliveryDate = date
+						return nil
+					},
+				},
+				{
+					Description: "delivers destination SIT that is in SIT",
+					Apply: func(subject interface{}, args interface{}) error {
+						deliveryDate, err := timeArg(args)
+						if err != nil {
+							return err
+						}
+						s := subject.(*Shipment)
+						for i := range s.StorageInTransits {
+							sit := &s.StorageInTransits[i]
+							if sit.Status == StorageInTransitStatusINSIT &&
+								sit.Location == StorageInTransitLocationDESTINATION {
+								if err := sit.Deliver(*deliveryDate); err != nil {
+									return err
+								}
+							}
+						}
+						return nil
+					},
+				},
+			},
+		},
+	},
+	GetState: func(subject interface{}) statemachine.State {
+		return statemachine.State(subject.(*Shipment).Status)
+	},
+	SetState: func(subject interface{}, to statemachine.State) {
+		subject.(*Shipment).Status = ShipmentStatus(to)
+	},
+	OnTransition: func(subject interface{}, from statemachine.State, to statemachine.State) {
+		subject.(*Shipment).recordTransition(string(from), string(to), nil)
+	},
+})
 
 // Submit marks the Shipment request for review
 func (s *Shipment) Submit(hhgSubmitDate time.Time) error {
-	if s.Status != ShipmentStatusDRAFT {
-		return errors.Wrap(ErrInvalidTransition, "Submit")
-	}
-	now := time.Now()
-	s.BookDate = &now
-	s.SubmitDate = &hhgSubmitDate
-	s.Status = ShipmentStatusSUBMITTED
-	return nil
+	return shipmentStateMachine.Fire(s, shipmentEventSubmit, hhgSubmitDate)
 }
 
 // Award marks the Shipment request as Awarded. Must be in an Submitted state.
 func (s *Shipment) Award() error {
-	if s.Status != ShipmentStatusSUBMITTED {
-		return errors.Wrap(ErrInvalidTransition, "Award")
-	}
-	s.Status = ShipmentStatusAWARDED
-	return nil
+	return shipmentStateMachine.Fire(s, shipmentEventAward, nil)
 }
 
 // Accept marks the Shipment request as Accepted. Must be in an Awarded state.
 func (s *Shipment) Accept() error {
-	if s.Status != ShipmentStatusAWARDED {
-		return errors.Wrap(ErrInvalidTransition, "Accept")
-	}
-	s.Status = ShipmentStatusACCEPTED
-	return nil
+	return shipmentStateMachine.Fire(s, shipmentEventAccept, nil)
 }
 
 // Reject returns the Shipment to the Submitted state. Must be in an Awarded state.
 func (s *Shipment) Reject() error {
-	if s.Status != ShipmentStatusAWARDED {
-		return errors.Wrap(ErrInvalidTransition, "Reject")
-	}
-	s.Status = ShipmentStatusSUBMITTED
-	return nil
+	return shipmentStateMachine.Fire(s, shipmentEventReject, nil)
 }
 
 // Approve marks the Shipment request as Approved. Must be in an Accepted state.
 func (s *Shipment) Approve(approveDate time.Time) error {
-	if s.Status != ShipmentStatusACCEPTED {
-		return errors.Wrap(ErrInvalidTransition, "Approve - status change")
-	}
-
-	if s.ApproveDate != nil {
-		return errors.Wrap(ErrInvalidTransition, "Approve - approve date change")
-	}
-	s.Status = ShipmentStatusAPPROVED
-	s.ApproveDate = &approveDate
-	return nil
+	return shipmentStateMachine.Fire(s, shipmentEventApprove, approveDate)
 }
 
 // Transport marks the Shipment request as In Transit. Must be in an Approved state.
 func (s *Shipment) Transport(actualPickupDate time.Time) error {
-	if s.Status != ShipmentStatusAPPROVED {
-		return errors.Wrap(ErrInvalidTransition, "In Transit")
-	}
-	s.Status = ShipmentStatusINTRANSIT
-	s.ActualPickupDate = &actualPickupDate
-	return nil
+	return shipmentStateMachine.Fire(s, shipmentEventTransport, actualPickupDate)
 }
 
 // Pack updates the Shipment actual pack date. Must be in an Approved state.
-// TODO: cgilmer 2018/10/18 - fold this into the Transport() state change when the fields are merged in the UI
 func (s *Shipment) Pack(actualPackDate time.Time) error {
-	if s.Status != ShipmentStatusAPPROVED {
-		return errors.Wrap(ErrInvalidTransition, "Approved")
-	}
-	s.ActualPackDate = &actualPackDate
-	return nil
+	return shipmentStateMachine.Fire(s, shipmentEventPack, actualPackDate)
 }
 
-// Deliver marks the Shipment request as Delivered. Must be IN TRANSIT state.
-func (s *Shipment) Deliver(actualDeliveryDate time.Time) (err error) {
-	// deliver Shipment
-	if s.Status != ShipmentStatusINTRANSIT {
-		return errors.Wrap(ErrInvalidTransition, "Deliver of shipment")
-	}
-	s.Status = ShipmentStatusDELIVERED
-	s.ActualDeliveryDate = &actualDeliveryDate
-
-	var sits []StorageInTransit
-	// deliver SITs
-	for _, sit := range s.StorageInTransits {
-		// only deliver DESTINATION Sits that are IN_SIT
-		if sit.Status == StorageInTransitStatusINSIT &&
-			sit.Location == StorageInTransitLocationDESTINATION {
-			err = sit.Deliver(actualDeliveryDate)
-			if err != nil {
-				return err
-			}
-			sits = append(sits, sit)
-		} else {
-			sits = append(sits, sit)
-		}
+// Deliver marks the Shipment request as Delivered, along with any destination SIT. Must be IN TRANSIT state.
+func (s *Shipment) Deliver(actualDeliveryDate time.Time) error {
+	return shipmentStateMachine.Fire(s, shipmentEventDeliver, actualDeliveryDate)
+}
+
+// SetTransitionActor sets who is making the status changes to the shipment and its SIT requests
+func (s *Shipment) SetTransitionActor(actor TransitionActor) {
+	s.StateHistory.SetTransitionActor(actor)
+	for i := range s.StorageInTransits {
+		s.StorageInTransits[i].SetTransitionActor(actor)
 	}
-	s.StorageInTransits = sits
+}
+
+// BeforeUpdate will run before each update of a Shipment, and increments its version.
+func (s *Shipment) BeforeUpdate(tx *pop.Connection) error {
+	return s.bumpVersion(tx, "shipments", s.ID, &s.Version)
+}
 
-	return err
+// AfterSave will run after each create/update of a Shipment, and saves its status changes.
+func (s *Shipment) AfterSave(tx *pop.Connection) error {
+	return s.saveStateTransitions(tx, &s.MoveID, StateTransitionEntityTypeSHIPMENT, s.ID)
 }
 
 // BeforeSave will run before each create/update of a Shipment.
@@ -728,18 +858,20 @@ func AwardShipment(db *pop.Connection, shipmentID uuid.UUID) error {
 		return err
 	}
 
-	verrs, err := db.ValidateAndUpdate(&shipment)
-	if err != nil {
-		return err
-	} else if verrs.HasAny() {
-		return fmt.Errorf("Validation failure: %s", verrs)
-	}
-
-	return nil
+	// The shipment and its state transition are saved together
+	return db.Transaction(func(tx *pop.Connection) error {
+		verrs, err := tx.ValidateAndUpdate(&shipment)
+		if err != nil {
+			return err
+		} else if verrs.HasAny() {
+			return fmt.Errorf("Validation failure: %s", verrs)
+		}
+		return nil
+	})
 }
 
-// AcceptShipmentForTSP accepts a shipment and shipment_offer
-func AcceptShipmentForTSP(db *pop.Connection, tspID uuid.UUID, shipmentID uuid.UUID) (*Shipment, *ShipmentOffer, *validate.Errors, error) {
+// AcceptShipmentForTSP accepts a shipment and shipment_offer on behalf of actor
+func AcceptShipmentForTSP(db *pop.Connection, tspID uuid.UUID, shipmentID uuid.UUID, actor TransitionActor) (*Shipment, *ShipmentOffer, *validate.Errors, error) {
 
 	// Get the Shipment and Shipment Offer
 	shipment, err := FetchShipmentByTSP(db, tspID, shipmentID)
@@ -769,6 +901,7 @@ func AcceptShipmentForTSP(db *pop.Connection, tspID uuid.UUID, shipmentID uuid.U
 	}
 
 	// Accept the Shipment and Shipment Offer
+	shipment.SetTransitionActor(actor)
 	err = shipment.Accept()
 	if err != nil {
 		return shipment, shipmentOffer, nil, err
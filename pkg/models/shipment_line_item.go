@@ -71,6 +71,8 @@ type ShipmentLineItem struct {
 	Time                *string                    `json:"time" db:"time"`
 	AddressID           *uuid.UUID                 `json:"address_id" db:"address_id"`
 	Address             Address                    `belongs_to:"addresses"`
+	PriceExplanation    *PriceExplanation          `json:"price_explanation" db:"price_explanation"`
+	StorageInTransitID  *uuid.UUID                 `json:"storage_in_transit_id" db:"storage_in_transit_id"`
 	CreatedAt           time.Time                  `json:"created_at" db:"created_at"`
 	UpdatedAt           time.Time                  `json:"updated_at" db:"updated_at"`
 }
@@ -179,6 +181,18 @@ func FetchLineItemsByShipmentID(dbConnection *pop.Connection, shipmentID *uuid.U
 	return shipmentLineItems, err
 }
 
+// FetchShipmentLineItemsByStorageInTransitID returns the line items for a storage in transit
+func FetchShipmentLineItemsByStorageInTransitID(dbConnection *pop.Connection, storageInTransitID uuid.UUID) ([]ShipmentLineItem, error) {
+	shipmentLineItems := []ShipmentLineItem{}
+
+	err := dbConnection.Where("storage_in_transit_id = ?", storageInTransitID).Eager("Tariff400ngItem").All(&shipmentLineItems)
+	if err != nil {
+		return shipmentLineItems, errors.Wrap(err, "Fetch storage in transit line items query failed")
+	}
+
+	return shipmentLineItems, nil
+}
+
 // FetchApprovedPreapprovalRequestsByShipment fetches approved pre-approval requests for a shipment
 func FetchApprovedPreapprovalRequestsByShipment(dbConnection *pop.Connection, shipment Shipment) ([]ShipmentLineItem, error) {
 	var items []ShipmentLineItem
@@ -219,6 +233,9 @@ func FetchShipmentLineItemByID(dbConnection *pop.Connection, shipmentLineItemID
 
 	err = dbConnection.Eager().Find(&shipmentLineItem, shipmentLineItemID)
 	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return shipmentLineItem, ErrFetchNotFound
+		}
 		return shipmentLineItem, errors.Wrap(err, "Shipment line items query failed")
 	}
 
@@ -26,6 +26,7 @@ type ShipmentOffer struct {
 	AdministrativeShipment                     bool                                     `json:"administrative_shipment" db:"administrative_shipment"`
 	Accepted                                   *bool                                    `json:"accepted" db:"accepted"`
 	RejectionReason                            *string                                  `json:"rejection_reason" db:"rejection_reason"`
+	AwardQueuePolicyID                         *uuid.UUID                               `json:"award_queue_policy_id" db:"award_queue_policy_id"`
 }
 
 // String is not required by pop and may be deleted
@@ -76,19 +77,22 @@ func (so *ShipmentOffer) Reject(rejectionReason string) error {
 	return nil
 }
 
-// CreateShipmentOffer connects a shipment to a transportation service provider. This
-// function assumes that the match has been validated by the caller.
+// CreateShipmentOffer connects a shipment to a transportation service provider, recording the award queue
+// policy that selected the TSP (nil for the default policy). This function assumes that the match has been
+// validated by the caller.
 func CreateShipmentOffer(tx *pop.Connection,
 	shipmentID uuid.UUID,
 	tspID uuid.UUID,
 	tsppID uuid.UUID,
-	administrativeShipment bool) (*ShipmentOffer, error) {
+	administrativeShipment bool,
+	awardQueuePolicyID *uuid.UUID) (*ShipmentOffer, error) {
 
 	shipmentOffer := ShipmentOffer{
 		ShipmentID:                                 shipmentID,
 		TransportationServiceProviderID:            tspID,
 		TransportationServiceProviderPerformanceID: tsppID,
 		AdministrativeShipment:                     administrativeShipment,
+		AwardQueuePolicyID:                         awardQueuePolicyID,
 	}
 	_, err := tx.ValidateAndSave(&shipmentOffer)
 
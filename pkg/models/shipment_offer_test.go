@@ -48,7 +48,7 @@ func (suite *ModelSuite) Test_CreateShipmentOffer() {
 		},
 	})
 
-	shipmentOffer, err := CreateShipmentOffer(suite.DB(), shipment.ID, tsp.ID, tspp.ID, false)
+	shipmentOffer, err := CreateShipmentOffer(suite.DB(), shipment.ID, tsp.ID, tspp.ID, false, nil)
 	suite.Nil(err, "error making ShipmentOffer")
 
 	expectedShipmentOffer := ShipmentOffer{}
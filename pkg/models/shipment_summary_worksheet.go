@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,14 +20,22 @@ import (
 	"github.com/transcom/mymove/pkg/unit"
 )
 
-// FormatValuesShipmentSummaryWorksheet returns the formatted pages for the Shipment Summary Worksheet
-func FormatValuesShipmentSummaryWorksheet(shipmentSummaryFormData ShipmentSummaryFormData) (ShipmentSummaryWorksheetPage1Values, ShipmentSummaryWorksheetPage2Values, error) {
+// Shipments that don't fit in the shipments section of page 1 are listed on continuation pages
+const (
+	shipmentsOnPage1             = 3
+	shipmentsPerContinuationPage = 30
+)
+
+// FormatValuesShipmentSummaryWorksheet returns the formatted pages for the Shipment Summary Worksheet, including
+// continuation pages for the shipments that don't fit on page 1
+func FormatValuesShipmentSummaryWorksheet(shipmentSummaryFormData ShipmentSummaryFormData) (ShipmentSummaryWorksheetPage1Values, ShipmentSummaryWorksheetPage2Values, []ShipmentSummaryWorksheetContinuationValues, error) {
 	page1 := FormatValuesShipmentSummaryWorksheetFormPage1(shipmentSummaryFormData)
+	continuationPages := FormatValuesShipmentSummaryWorksheetContinuationPages(shipmentSummaryFormData)
 	page2, err := FormatValuesShipmentSummaryWorksheetFormPage2(shipmentSummaryFormData)
 	if err != nil {
-		return page1, page2, err
+		return page1, page2, continuationPages, err
 	}
-	return page1, page2, nil
+	return page1, page2, continuationPages, nil
 }
 
 // ShipmentSummaryWorksheetPage1Values is an object representing a Shipment Summary Worksheet
@@ -68,6 +77,23 @@ type ShipmentSummaryWorksheetPage1Values struct {
 	ActualObligationSIT             string
 }
 
+// ShipmentSummaryWorksheetContinuationValues is an object representing a Shipment Summary Worksheet continuation
+// page, which lists the shipments that don't fit on page 1
+type ShipmentSummaryWorksheetContinuationValues struct {
+	ServiceMemberName               string
+	PreferredPhoneNumber            string
+	PreferredEmail                  string
+	DODId                           string
+	ServiceBranch                   string
+	RankGrade                       string
+	PreparationDate                 string
+	ShipmentNumberAndTypes          string
+	ShipmentPickUpDates             string
+	ShipmentWeights                 string
+	ShipmentCurrentShipmentStatuses string
+	PageNumber                      string
+}
+
 //ShipmentSummaryWorkSheetShipments is and object representing shipment line items on Shipment Summary Worksheet
 type ShipmentSummaryWorkSheetShipments struct {
 	ShipmentNumberAndTypes  string
@@ -174,6 +200,14 @@ func FetchDataShipmentSummaryWorksheetFormData(db *pop.Connection, session *auth
 		return ShipmentSummaryFormData{}, dbQErr
 	}
 
+	// Shipments are numbered in the order they were created
+	sort.SliceStable(move.Shipments, func(i, j int) bool {
+		return move.Shipments[i].CreatedAt.Before(move.Shipments[j].CreatedAt)
+	})
+	sort.SliceStable(move.PersonallyProcuredMoves, func(i, j int) bool {
+		return move.PersonallyProcuredMoves[i].CreatedAt.Before(move.PersonallyProcuredMoves[j].CreatedAt)
+	})
+
 	for i, ppm := range move.PersonallyProcuredMoves {
 		ppmDetails, err := FetchPersonallyProcuredMove(db, session, ppm.ID)
 		if err != nil {
@@ -205,10 +239,7 @@ func FetchDataShipmentSummaryWorksheetFormData(db *pop.Connection, session *auth
 		return ShipmentSummaryFormData{}, err
 	}
 
-	ppmRemainingEntitlement, err := CalculateRemainingPPMEntitlement(move, weightAllotment.TotalWeight)
-	if err != nil {
-		return ShipmentSummaryFormData{}, err
-	}
+	ppmRemainingEntitlement := CalculateRemainingPPMEntitlement(move, weightAllotment.TotalWeight)
 
 	signedCertification, err := FetchSignedCertificationsPPMPayment(db, session, moveID)
 	if err != nil {
@@ -269,39 +300,40 @@ func SSWGetEntitlement(rank ServiceMemberRank, hasDependents bool, spouseHasProG
 }
 
 // CalculateRemainingPPMEntitlement calculates the remaining PPM entitlement for PPM moves
-// a PPMs remaining entitlement weight is equal to total entitlement - hhg weight
-func CalculateRemainingPPMEntitlement(move Move, totalEntitlement unit.Pound) (unit.Pound, error) {
+// a PPMs remaining entitlement weight is equal to total entitlement - hhg weight, and is shared by all of the PPMs.
+// Only shipments and PPMs that count toward the entitlement are included, and those that haven't been weighed yet
+// don't count against it.
+func CalculateRemainingPPMEntitlement(move Move, totalEntitlement unit.Pound) unit.Pound {
 	var hhgActualWeight unit.Pound
-	if len(move.Shipments) > 0 {
-		if move.Shipments[0].NetWeight == nil {
-			return hhgActualWeight, errors.Errorf("Shipment %s does not have NetWeight", move.Shipments[0].ID)
+	for _, shipment := range move.Shipments {
+		if !shipment.CountsTowardEntitlement() || shipment.NetWeight == nil {
+			continue
 		}
-		hhgActualWeight = *move.Shipments[0].NetWeight
+		hhgActualWeight += *shipment.NetWeight
 	}
 
 	var ppmActualWeight unit.Pound
-	if len(move.PersonallyProcuredMoves) > 0 {
-		if move.PersonallyProcuredMoves[0].NetWeight == nil {
-			return ppmActualWeight, errors.Errorf("PPM %s does not have NetWeight", move.PersonallyProcuredMoves[0].ID)
+	for _, ppm := range move.PersonallyProcuredMoves {
+		if !ppm.CountsTowardEntitlement() || ppm.NetWeight == nil {
+			continue
 		}
-		ppmActualWeight = unit.Pound(*move.PersonallyProcuredMoves[0].NetWeight)
+		ppmActualWeight += *ppm.NetWeight
 	}
 
 	switch ppmRemainingEntitlement := totalEntitlement - hhgActualWeight; {
 	case ppmActualWeight < ppmRemainingEntitlement:
-		return ppmActualWeight, nil
+		return ppmActualWeight
 	case ppmRemainingEntitlement < 0:
-		return 0, nil
+		return 0
 	default:
-		return ppmRemainingEntitlement, nil
+		return ppmRemainingEntitlement
 	}
 }
 
-// FetchMovingExpensesShipmentSummaryWorksheet fetches moving expenses for the Shipment Summary Worksheet
+// FetchMovingExpensesShipmentSummaryWorksheet fetches moving expenses of all of a move's PPMs for the Shipment Summary Worksheet
 func FetchMovingExpensesShipmentSummaryWorksheet(move Move, db *pop.Connection, session *auth.Session) ([]MovingExpenseDocument, error) {
 	var movingExpenses []MovingExpenseDocument
-	if len(move.PersonallyProcuredMoves) > 0 {
-		ppm := move.PersonallyProcuredMoves[0]
+	for _, ppm := range move.PersonallyProcuredMoves {
 		moveDocuments, err := FetchMovingExpenseDocuments(db, session, ppm.ID, nil)
 		if err != nil {
 			return movingExpenses, err
@@ -346,7 +378,11 @@ func FormatValuesShipmentSummaryWorksheetFormPage1(data ShipmentSummaryFormData)
 	page1.WeightAllotmentProgearSpouse = FormatWeights(data.WeightAllotment.SpouseProGear)
 	page1.TotalWeightAllotment = FormatWeights(data.WeightAllotment.TotalWeight)
 
-	formattedShipments := FormatAllShipments(data.PersonallyProcuredMoves, data.Shipments)
+	shipmentLines := FormatShipmentLines(data.PersonallyProcuredMoves, data.Shipments)
+	if len(shipmentLines) > shipmentsOnPage1 {
+		shipmentLines = shipmentLines[:shipmentsOnPage1]
+	}
+	formattedShipments := joinShipmentLines(shipmentLines)
 	page1.ShipmentNumberAndTypes = formattedShipments.ShipmentNumberAndTypes
 	page1.ShipmentPickUpDates = formattedShipments.PickUpDates
 	page1.ShipmentCurrentShipmentStatuses = formattedShipments.CurrentShipmentStatuses
@@ -369,11 +405,49 @@ func FormatValuesShipmentSummaryWorksheetFormPage1(data ShipmentSummaryFormData)
 }
 
 func formatActualObligationAdvance(data ShipmentSummaryFormData) string {
-	if len(data.PersonallyProcuredMoves) > 0 && data.PersonallyProcuredMoves[0].Advance != nil {
-		advance := data.PersonallyProcuredMoves[0].Advance.RequestedAmount.ToDollarFloat()
-		return FormatDollars(advance)
+	var advance unit.Cents
+	for _, ppm := range data.PersonallyProcuredMoves {
+		if ppm.Advance != nil {
+			advance += ppm.Advance.RequestedAmount
+		}
 	}
-	return FormatDollars(0)
+	return FormatDollars(advance.ToDollarFloat())
+}
+
+// FormatValuesShipmentSummaryWorksheetContinuationPages formats the continuation pages of the Shipment Summary
+// Worksheet listing the shipments that don't fit on page 1. There are none if every shipment fits on page 1.
+func FormatValuesShipmentSummaryWorksheetContinuationPages(data ShipmentSummaryFormData) []ShipmentSummaryWorksheetContinuationValues {
+	shipmentLines := FormatShipmentLines(data.PersonallyProcuredMoves, data.Shipments)
+	if len(shipmentLines) <= shipmentsOnPage1 {
+		return nil
+	}
+	shipmentLines = shipmentLines[shipmentsOnPage1:]
+
+	sm := data.ServiceMember
+	pageCount := (len(shipmentLines) + shipmentsPerContinuationPage - 1) / shipmentsPerContinuationPage
+	pages := make([]ShipmentSummaryWorksheetContinuationValues, 0, pageCount)
+	for start := 0; start < len(shipmentLines); start += shipmentsPerContinuationPage {
+		end := start + shipmentsPerContinuationPage
+		if end > len(shipmentLines) {
+			end = len(shipmentLines)
+		}
+		formattedShipments := joinShipmentLines(shipmentLines[start:end])
+		pages = append(pages, ShipmentSummaryWorksheetContinuationValues{
+			ServiceMemberName:               FormatServiceMemberFullName(sm),
+			PreferredPhoneNumber:            derefStringTypes(sm.Telephone),
+			PreferredEmail:                  derefStringTypes(sm.PersonalEmail),
+			DODId:                           derefStringTypes(sm.Edipi),
+			ServiceBranch:                   FormatServiceMemberAffiliation(sm.Affiliation),
+			RankGrade:                       FormatRank(sm.Rank),
+			PreparationDate:                 FormatDate(data.PreparationDate),
+			ShipmentNumberAndTypes:          formattedShipments.ShipmentNumberAndTypes,
+			ShipmentPickUpDates:             formattedShipments.PickUpDates,
+			ShipmentWeights:                 formattedShipments.ShipmentWeights,
+			ShipmentCurrentShipmentStatuses: formattedShipments.CurrentShipmentStatuses,
+			PageNumber:                      fmt.Sprintf("Shipments continued %d of %d", len(pages)+1, pageCount),
+		})
+	}
+	return pages
 }
 
 //FormatRank formats the service member's rank for Shipment Summary Worksheet
@@ -458,35 +532,51 @@ func FormatServiceMemberFullName(serviceMember ServiceMember) string {
 
 //FormatAllShipments formats Shipment line items for the Shipment Summary Worksheet
 func FormatAllShipments(ppms PersonallyProcuredMoves, shipments Shipments) ShipmentSummaryWorkSheetShipments {
-	totalShipments := len(shipments) + len(ppms)
-	formattedShipments := ShipmentSummaryWorkSheetShipments{}
-	formattedNumberAndTypes := make([]string, totalShipments)
-	formattedPickUpDates := make([]string, totalShipments)
-	formattedShipmentWeights := make([]string, totalShipments)
-	formattedShipmentStatuses := make([]string, totalShipments)
-	var shipmentNumber int
+	return joinShipmentLines(FormatShipmentLines(ppms, shipments))
+}
 
+//FormatShipmentLines formats each shipment as its own line item for the Shipment Summary Worksheet.
+//HHG shipments are numbered first, followed by PPMs.
+func FormatShipmentLines(ppms PersonallyProcuredMoves, shipments Shipments) []ShipmentSummaryWorkSheetShipments {
+	lines := make([]ShipmentSummaryWorkSheetShipments, 0, len(shipments)+len(ppms))
 	for _, shipment := range shipments {
-		formattedNumberAndTypes[shipmentNumber] = FormatShipmentNumberAndType(shipmentNumber)
-		formattedPickUpDates[shipmentNumber] = FormatShipmentPickupDate(shipment)
-		formattedShipmentWeights[shipmentNumber] = FormatShipmentWeight(shipment)
-		formattedShipmentStatuses[shipmentNumber] = FormatCurrentShipmentStatus(shipment)
-		shipmentNumber++
+		lines = append(lines, ShipmentSummaryWorkSheetShipments{
+			ShipmentNumberAndTypes:  FormatShipmentNumberAndType(len(lines)),
+			PickUpDates:             FormatShipmentPickupDate(shipment),
+			ShipmentWeights:         FormatShipmentWeight(shipment),
+			CurrentShipmentStatuses: FormatCurrentShipmentStatus(shipment),
+		})
 	}
 	for _, ppm := range ppms {
-		formattedNumberAndTypes[shipmentNumber] = FormatPPMNumberAndType(shipmentNumber)
-		formattedPickUpDates[shipmentNumber] = FormatPPMPickupDate(ppm)
-		formattedShipmentWeights[shipmentNumber] = FormatPPMWeight(ppm)
-		formattedShipmentStatuses[shipmentNumber] = FormatCurrentPPMStatus(ppm)
-		shipmentNumber++
+		lines = append(lines, ShipmentSummaryWorkSheetShipments{
+			ShipmentNumberAndTypes:  FormatPPMNumberAndType(len(lines)),
+			PickUpDates:             FormatPPMPickupDate(ppm),
+			ShipmentWeights:         FormatPPMWeight(ppm),
+			CurrentShipmentStatuses: FormatCurrentPPMStatus(ppm),
+		})
+	}
+	return lines
+}
+
+// joinShipmentLines joins shipment line items into the columns of the shipments section of the worksheet
+func joinShipmentLines(lines []ShipmentSummaryWorkSheetShipments) ShipmentSummaryWorkSheetShipments {
+	formattedNumberAndTypes := make([]string, len(lines))
+	formattedPickUpDates := make([]string, len(lines))
+	formattedShipmentWeights := make([]string, len(lines))
+	formattedShipmentStatuses := make([]string, len(lines))
+	for i, line := range lines {
+		formattedNumberAndTypes[i] = line.ShipmentNumberAndTypes
+		formattedPickUpDates[i] = line.PickUpDates
+		formattedShipmentWeights[i] = line.ShipmentWeights
+		formattedShipmentStatuses[i] = line.CurrentShipmentStatuses
+	}
+
+	return ShipmentSummaryWorkSheetShipments{
+		ShipmentNumberAndTypes:  strings.Join(formattedNumberAndTypes, "\n\n"),
+		PickUpDates:             strings.Join(formattedPickUpDates, "\n\n"),
+		ShipmentWeights:         strings.Join(formattedShipmentWeights, "\n\n"),
+		CurrentShipmentStatuses: strings.Join(formattedShipmentStatuses, "\n\n"),
 	}
-
-	formattedShipments.ShipmentNumberAndTypes = strings.Join(formattedNumberAndTypes, "\n\n")
-	formattedShipments.PickUpDates = strings.Join(formattedPickUpDates, "\n\n")
-	formattedShipments.ShipmentWeights = strings.Join(formattedShipmentWeights, "\n\n")
-	formattedShipments.CurrentShipmentStatuses = strings.Join(formattedShipmentStatuses, "\n\n")
-
-	return formattedShipments
 }
 
 //FormatMovingExpenses formats moving expenses for Shipment Summary Worksheet
@@ -1,6 +1,7 @@
 package models_test
 
 import (
+	"strings"
 	"time"
 
 	"github.com/transcom/mymove/pkg/unit"
@@ -562,8 +563,7 @@ func (suite *ModelSuite) TestCalculatePPMEntitlementNoHHGPPMLessThanMaxEntitleme
 		PersonallyProcuredMoves: models.PersonallyProcuredMoves{models.PersonallyProcuredMove{NetWeight: &ppmWeight}},
 	}
 
-	ppmRemainingEntitlement, err := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
-	suite.NoError(err)
+	ppmRemainingEntitlement := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
 
 	suite.Equal(unit.Pound(ppmWeight), ppmRemainingEntitlement)
 }
@@ -575,8 +575,7 @@ func (suite *ModelSuite) TestCalculatePPMEntitlementNoHHGPPMGreaterThanMaxEntitl
 		PersonallyProcuredMoves: models.PersonallyProcuredMoves{models.PersonallyProcuredMove{NetWeight: &ppmWeight}},
 	}
 
-	ppmRemainingEntitlement, err := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
-	suite.NoError(err)
+	ppmRemainingEntitlement := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
 
 	suite.Equal(totalEntitlement, ppmRemainingEntitlement)
 }
@@ -590,8 +589,7 @@ func (suite *ModelSuite) TestCalculatePPMEntitlementPPMGreaterThanRemainingEntit
 		Shipments:               models.Shipments{models.Shipment{NetWeight: &hhg}},
 	}
 
-	ppmRemainingEntitlement, err := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
-	suite.NoError(err)
+	ppmRemainingEntitlement := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
 
 	suite.Equal(totalEntitlement-hhg, ppmRemainingEntitlement)
 }
@@ -605,12 +603,154 @@ func (suite *ModelSuite) TestCalculatePPMEntitlementPPMLessThanRemainingEntitlem
 		Shipments:               models.Shipments{models.Shipment{NetWeight: &hhg}},
 	}
 
-	ppmRemainingEntitlement, err := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
-	suite.NoError(err)
+	ppmRemainingEntitlement := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
 
 	suite.Equal(unit.Pound(ppmWeight), ppmRemainingEntitlement)
 }
 
+func (suite *ModelSuite) TestCalculatePPMEntitlementMultipleShipments() {
+	ppmWeight1 := unit.Pound(300)
+	ppmWeight2 := unit.Pound(400)
+	totalEntitlement := unit.Pound(1000)
+	hhg1 := unit.Pound(100)
+	hhg2 := unit.Pound(300)
+	move := models.Move{
+		PersonallyProcuredMoves: models.PersonallyProcuredMoves{
+			models.PersonallyProcuredMove{NetWeight: &ppmWeight1},
+			models.PersonallyProcuredMove{NetWeight: &ppmWeight2},
+		},
+		Shipments: models.Shipments{models.Shipment{NetWeight: &hhg1}, models.Shipment{NetWeight: &hhg2}},
+	}
+
+	ppmRemainingEntitlement := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
+
+	suite.Equal(totalEntitlement-hhg1-hhg2, ppmRemainingEntitlement)
+}
+
+func (suite *ModelSuite) TestCalculatePPMEntitlementIgnoresUndeliveredShipments() {
+	ppmWeight := unit.Pound(800)
+	totalEntitlement := unit.Pound(1000)
+	hhg := unit.Pound(300)
+	move := models.Move{
+		PersonallyProcuredMoves: models.PersonallyProcuredMoves{
+			models.PersonallyProcuredMove{NetWeight: &ppmWeight},
+		},
+		Shipments: models.Shipments{models.Shipment{NetWeight: &hhg}, models.Shipment{}},
+	}
+
+	ppmRemainingEntitlement := models.CalculateRemainingPPMEntitlement(move, totalEntitlement)
+
+	suite.Equal(totalEntitlement-hhg, ppmRemainingEntitlement)
+}
+
+func (suite *ModelSuite) TestCalculatePPMEntitlementCountsOnlyWeighedSubmittedRecords() {
+	ppmWeight := unit.Pound(300)
+	hhg := unit.Pound(500)
+	totalEntitlement := unit.Pound(1000)
+	ppm := models.PersonallyProcuredMove{NetWeight: &ppmWeight, Status: models.PPMStatusPAYMENTREQUESTED}
+	shipment := models.Shipment{NetWeight: &hhg, Status: models.ShipmentStatusDELIVERED}
+	overweightPPM := unit.Pound(900)
+
+	tests := []struct {
+		name      string
+		ppms      models.PersonallyProcuredMoves
+		shipments models.Shipments
+		expected  unit.Pound
+	}{
+		{
+			name:     "PPM without a weight",
+			ppms:     models.PersonallyProcuredMoves{ppm, {Status: models.PPMStatusAPPROVED}},
+			expected: ppmWeight,
+		},
+		{
+			name:      "shipment without a weight",
+			ppms:      models.PersonallyProcuredMoves{ppm},
+			shipments: models.Shipments{{Status: models.ShipmentStatusINTRANSIT}},
+			expected:  ppmWeight,
+		},
+		{
+			name:     "canceled PPM",
+			ppms:     models.PersonallyProcuredMoves{ppm, {NetWeight: &overweightPPM, Status: models.PPMStatusCANCELED}},
+			expected: ppmWeight,
+		},
+		{
+			name:     "draft PPM",
+			ppms:     models.PersonallyProcuredMoves{ppm, {NetWeight: &overweightPPM, Status: models.PPMStatusDRAFT}},
+			expected: ppmWeight,
+		},
+		{
+			name:      "draft shipment",
+			ppms:      models.PersonallyProcuredMoves{{NetWeight: &overweightPPM, Status: models.PPMStatusCOMPLETED}},
+			shipments: models.Shipments{shipment, {NetWeight: &hhg, Status: models.ShipmentStatusDRAFT}},
+			expected:  totalEntitlement - hhg,
+		},
+		{
+			name:      "canceled PPM without a weight",
+			ppms:      models.PersonallyProcuredMoves{ppm, {Status: models.PPMStatusCANCELED}},
+			shipments: models.Shipments{shipment},
+			expected:  ppmWeight,
+		},
+	}
+
+	for _, test := range tests {
+		move := models.Move{PersonallyProcuredMoves: test.ppms, Shipments: test.shipments}
+		suite.Equal(test.expected, models.CalculateRemainingPPMEntitlement(move, totalEntitlement), test.name)
+	}
+}
+
+func (suite *ModelSuite) TestFormatValuesShipmentSummaryWorksheetMultipleShipments() {
+	weight := unit.Pound(1000)
+	var shipments models.Shipments
+	for i := 0; i < 2; i++ {
+		shipments = append(shipments, models.Shipment{NetWeight: &weight, Status: models.ShipmentStatusDELIVERED})
+	}
+	var ppms models.PersonallyProcuredMoves
+	for i := 0; i < 33; i++ {
+		advance := models.BuildDraftReimbursement(1000, models.MethodOfReceiptMILPAY)
+		ppms = append(ppms, models.PersonallyProcuredMove{NetWeight: &weight, Advance: &advance})
+	}
+	ssd := models.ShipmentSummaryFormData{
+		ServiceMember: models.ServiceMember{
+			FirstName: models.StringPointer("Tom"),
+			LastName:  models.StringPointer("Smith"),
+			Edipi:     models.StringPointer("1234567890"),
+		},
+		Shipments:               shipments,
+		PersonallyProcuredMoves: ppms,
+		PreparationDate:         time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+	}
+
+	page1, _, continuationPages, err := models.FormatValuesShipmentSummaryWorksheet(ssd)
+	suite.NoError(err)
+
+	// The first three shipments are on page 1 and the other 32 are split over continuation pages
+	suite.Equal("01 - HHG (GBL)\n\n02 - HHG (GBL)\n\n03 - PPM", page1.ShipmentNumberAndTypes)
+	suite.Equal("$330.00", page1.ActualObligationAdvance)
+	suite.Require().Len(continuationPages, 2)
+	suite.Equal("Smith, Tom", continuationPages[0].ServiceMemberName)
+	suite.Equal("1234567890", continuationPages[0].DODId)
+	suite.Equal("01-Jan-2019", continuationPages[0].PreparationDate)
+	suite.Equal("Shipments continued 1 of 2", continuationPages[0].PageNumber)
+	suite.True(strings.HasPrefix(continuationPages[0].ShipmentNumberAndTypes, "04 - PPM\n\n"))
+	suite.True(strings.HasSuffix(continuationPages[0].ShipmentNumberAndTypes, "\n\n33 - PPM"))
+	suite.Equal("34 - PPM\n\n35 - PPM", continuationPages[1].ShipmentNumberAndTypes)
+	suite.Equal("1,000 lbs - FINAL\n\n1,000 lbs - FINAL", continuationPages[1].ShipmentWeights)
+	suite.Equal("Shipments continued 2 of 2", continuationPages[1].PageNumber)
+}
+
+func (suite *ModelSuite) TestFormatValuesShipmentSummaryWorksheetNoContinuationPages() {
+	ssd := models.ShipmentSummaryFormData{
+		Shipments:               models.Shipments{models.Shipment{}},
+		PersonallyProcuredMoves: models.PersonallyProcuredMoves{models.PersonallyProcuredMove{}, models.PersonallyProcuredMove{}},
+	}
+
+	page1, _, continuationPages, err := models.FormatValuesShipmentSummaryWorksheet(ssd)
+	suite.NoError(err)
+
+	suite.Equal("01 - HHG (GBL)\n\n02 - PPM\n\n03 - PPM", page1.ShipmentNumberAndTypes)
+	suite.Empty(continuationPages)
+}
+
 func (suite *ModelSuite) TestFormatSignature() {
 	signatureDate := time.Date(2019, time.January, 26, 14, 40, 0, 0, time.UTC)
 	sm := models.ServiceMember{
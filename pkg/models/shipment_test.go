@@ -112,7 +112,7 @@ func (suite *ModelSuite) Test_FetchUnofferedShipments() {
 		},
 	})
 	tspp, _ := testdatagen.MakeDefaultTSPPerformance(suite.DB())
-	CreateShipmentOffer(suite.DB(), shipment.ID, tspp.TransportationServiceProviderID, tspp.ID, false)
+	CreateShipmentOffer(suite.DB(), shipment.ID, tspp.TransportationServiceProviderID, tspp.ID, false, nil)
 	shipments, err := FetchUnofferedShipments(suite.DB())
 
 	// Expect only unassigned shipment returned
@@ -216,7 +216,7 @@ func (suite *ModelSuite) TestAcceptShipmentForTSP() {
 	suite.Nil(shipmentOffer.Accepted)
 	suite.Nil(shipmentOffer.RejectionReason)
 
-	newShipment, newShipmentOffer, _, err := AcceptShipmentForTSP(suite.DB(), tspUser.TransportationServiceProviderID, shipment.ID)
+	newShipment, newShipmentOffer, _, err := AcceptShipmentForTSP(suite.DB(), tspUser.TransportationServiceProviderID, shipment.ID, TransitionActor{})
 	suite.NoError(err)
 
 	suite.Equal(ShipmentStatusACCEPTED, newShipment.Status, "expected Accepted")
@@ -254,7 +254,7 @@ func createAndAcceptShipmentWithDeliveryAddress(suite *ModelSuite, hasDeliveryAd
 	shipment.DeliveryAddressID = &deliveryAddress.ID
 	suite.DB().ValidateAndSave(&shipment)
 
-	newShipment, _, _, err := AcceptShipmentForTSP(suite.DB(), tspUser.TransportationServiceProviderID, shipment.ID)
+	newShipment, _, _, err := AcceptShipmentForTSP(suite.DB(), tspUser.TransportationServiceProviderID, shipment.ID, TransitionActor{})
 
 	return shipment, *newShipment, err
 }
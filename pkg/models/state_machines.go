@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/statemachine"
+)
+
+// StateMachines returns the declared lifecycles of moves, PPMs, reimbursements and shipments, for documentation
+func StateMachines() []*statemachine.Machine {
+	return []*statemachine.Machine{
+		moveStateMachine,
+		ppmStateMachine,
+		reimbursementStateMachine,
+		shipmentStateMachine,
+	}
+}
+
+// timeArg returns the date an event was fired with
+func timeArg(args interface{}) (*time.Time, error) {
+	date, ok := args.(time.Time)
+	if !ok {
+		return nil, errors.Wrapf(statemachine.ErrInvalidTransition, "expected a date, got %T", args)
+	}
+	return &date, nil
+}
+
+// stringArg returns the string an event was fired with
+func stringArg(args interface{}) (string, error) {
+	s, ok := args.(string)
+	if !ok {
+		return "", errors.Wrapf(statemachine.ErrInvalidTransition, "expected a string, got %T", args)
+	}
+	return s, nil
+}
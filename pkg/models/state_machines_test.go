@@ -0,0 +1,43 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	. "github.com/transcom/mymove/pkg/models"
+)
+
+func (suite *ModelSuite) TestStateMachines() {
+	names := map[string]bool{}
+	for _, machine := range StateMachines() {
+		names[machine.Name()] = true
+		table := machine.Table()
+		suite.NotEmpty(table.States, machine.Name())
+		suite.NotEmpty(table.Transitions, machine.Name())
+	}
+	suite.Equal(map[string]bool{
+		"Move":                   true,
+		"PersonallyProcuredMove": true,
+		"Reimbursement":          true,
+		"Shipment":               true,
+	}, names)
+}
+
+func (suite *ModelSuite) TestInvalidTransitionErrors() {
+	now := time.Now()
+
+	shipment := Shipment{Status: ShipmentStatusDRAFT}
+	err := shipment.Deliver(now)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+	suite.Equal("Shipment: cannot Deliver from DRAFT: INVALID_TRANSITION", err.Error())
+	suite.Equal(ShipmentStatusDRAFT, shipment.Status)
+	suite.Nil(shipment.ActualDeliveryDate)
+
+	// Guards refuse transitions with the same error
+	ppm := PersonallyProcuredMove{Status: PPMStatusSUBMITTED, ApproveDate: &now}
+	err = ppm.Approve(now)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+	suite.Equal("PersonallyProcuredMove: cannot Approve from SUBMITTED unless approve date is not set: INVALID_TRANSITION", err.Error())
+	suite.Equal(PPMStatusSUBMITTED, ppm.Status)
+}
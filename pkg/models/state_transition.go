@@ -0,0 +1,193 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+)
+
+// StateTransitionEntityType is the kind of record whose status changed
+type StateTransitionEntityType string
+
+const (
+	// StateTransitionEntityTypeMOVE is a move
+	StateTransitionEntityTypeMOVE StateTransitionEntityType = "MOVE"
+	// StateTransitionEntityTypeSHIPMENT is an HHG shipment
+	StateTransitionEntityTypeSHIPMENT StateTransitionEntityType = "SHIPMENT"
+	// StateTransitionEntityTypePPM is a personally procured move
+	StateTransitionEntityTypePPM StateTransitionEntityType = "PPM"
+	// StateTransitionEntityTypeSTORAGEINTRANSIT is a storage in transit request
+	StateTransitionEntityTypeSTORAGEINTRANSIT StateTransitionEntityType = "STORAGE_IN_TRANSIT"
+	// StateTransitionEntityTypeREIMBURSEMENT is a reimbursement, such as a PPM advance
+	StateTransitionEntityTypeREIMBURSEMENT StateTransitionEntityType = "REIMBURSEMENT"
+)
+
+var stateTransitionEntityTypes = []string{
+	string(StateTransitionEntityTypeMOVE),
+	string(StateTransitionEntityTypeSHIPMENT),
+	string(StateTransitionEntityTypePPM),
+	string(StateTransitionEntityTypeSTORAGEINTRANSIT),
+	string(StateTransitionEntityTypeREIMBURSEMENT),
+}
+
+// StateTransition is an entry in the append-only history of status changes to a move and the records that belong
+// to it. It is written in the same transaction as the change.
+type StateTransition struct {
+	ID             uuid.UUID                 `json:"id" db:"id"`
+	CreatedAt      time.Time                 `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at" db:"updated_at"`
+	MoveID         *uuid.UUID                `json:"move_id" db:"move_id"`
+	EntityType     StateTransitionEntityType `json:"entity_type" db:"entity_type"`
+	EntityID       uuid.UUID                 `json:"entity_id" db:"entity_id"`
+	PreviousStatus string                    `json:"previous_status" db:"previous_status"`
+	NewStatus      string                    `json:"new_status" db:"new_status"`
+	Reason         *string                   `json:"reason" db:"reason"`
+	ActorUserID    *uuid.UUID                `json:"actor_user_id" db:"actor_user_id"`
+	Application    *string                   `json:"application" db:"application"`
+	RequestID      *string                   `json:"request_id" db:"request_id"`
+}
+
+// StateTransitions is a slice of StateTransition objects
+type StateTransitions []StateTransition
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (s *StateTransition) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringInclusion{Field: string(s.EntityType), Name: "EntityType", List: stateTransitionEntityTypes},
+		&validators.UUIDIsPresent{Field: s.EntityID, Name: "EntityID"},
+		&validators.StringIsPresent{Field: s.PreviousStatus, Name: "PreviousStatus"},
+		&validators.StringIsPresent{Field: s.NewStatus, Name: "NewStatus"},
+	), nil
+}
+
+// FetchStateTransitionsForMove returns the history of status changes to a move and its shipments, PPMs, SIT
+// requests and reimbursements, oldest first
+func FetchStateTransitionsForMove(db *pop.Connection, moveID uuid.UUID) (StateTransitions, error) {
+	var transitions StateTransitions
+	err := db.Where("move_id = ?", moveID).Order("created_at ASC").All(&transitions)
+	if err != nil {
+		return transitions, errors.Wrap(err, "Fetch state transitions query failed")
+	}
+	return transitions, nil
+}
+
+// FetchStateTransitionsForShipment returns the history of status changes to a shipment and its SIT requests,
+// oldest first
+func FetchStateTransitionsForShipment(db *pop.Connection, shipmentID uuid.UUID) (StateTransitions, error) {
+	var transitions StateTransitions
+	err := db.Where(`(entity_type = ? AND entity_id = ?)
+		OR (entity_type = ? AND entity_id IN (SELECT id FROM storage_in_transits WHERE shipment_id = ?))`,
+		StateTransitionEntityTypeSHIPMENT, shipmentID, StateTransitionEntityTypeSTORAGEINTRANSIT, shipmentID).
+		Order("created_at ASC").
+		All(&transitions)
+	if err != nil {
+		return transitions, errors.Wrap(err, "Fetch state transitions query failed")
+	}
+	return transitions, nil
+}
+
+// TransitionActor is who made a status change, and in which request. The zero value is the system, such as the
+// award queue or a scheduled task.
+type TransitionActor struct {
+	UserID      *uuid.UUID
+	Application string
+	RequestID   string
+}
+
+// NewTransitionActor returns the actor for the user of a session, making changes in the request with requestID
+func NewTransitionActor(session *auth.Session, requestID string) TransitionActor {
+	actor := TransitionActor{RequestID: requestID}
+	if session != nil {
+		actor.Application = string(session.ApplicationName)
+		if session.UserID != uuid.Nil {
+			userID := session.UserID
+			actor.UserID = &userID
+		}
+	}
+	return actor
+}
+
+type pendingStateTransition struct {
+	previousStatus string
+	newStatus      string
+	reason         *string
+}
+
+// StateHistory collects the status changes made to a model by its transition methods, and who made them, until
+// the model is saved. Models embed it and append its changes to state_transitions when they are saved.
+type StateHistory struct {
+	actor   TransitionActor
+	pending []pendingStateTransition
+}
+
+// SetTransitionActor sets who is making the model's status changes
+func (h *StateHistory) SetTransitionActor(actor TransitionActor) {
+	h.actor = actor
+}
+
+// recordTransition notes a status change, to be saved along with the model
+func (h *StateHistory) recordTransition(previousStatus string, newStatus string, reason *string) {
+	if previousStatus == newStatus {
+		return
+	}
+	h.pending = append(h.pending, pendingStateTransition{
+		previousStatus: previousStatus,
+		newStatus:      newStatus,
+		reason:         reason,
+	})
+}
+
+// saveStateTransitions appends the model's pending status changes to state_transitions. It is called from the
+// model's AfterSave, so when the model is saved in a transaction its history is too.
+func (h *StateHistory) saveStateTransitions(tx *pop.Connection, moveID *uuid.UUID, entityType StateTransitionEntityType, entityID uuid.UUID) error {
+	for _, pending := range h.pending {
+		transition := StateTransition{
+			MoveID:         moveID,
+			EntityType:     entityType,
+			EntityID:       entityID,
+			PreviousStatus: pending.previousStatus,
+			NewStatus:      pending.newStatus,
+			Reason:         pending.reason,
+			ActorUserID:    h.actor.UserID,
+		}
+		if application := h.actor.Application; application != "" {
+			transition.Application = &application
+		}
+		if requestID := h.actor.RequestID; requestID != "" {
+			transition.RequestID = &requestID
+		}
+		verrs, err := tx.ValidateAndCreate(&transition)
+		if err != nil {
+			return errors.Wrap(err, "Could not save state transition")
+		}
+		if verrs.HasAny() {
+			return errors.Errorf("Invalid state transition: %s", verrs.String())
+		}
+	}
+	h.pending = nil
+	return nil
+}
+
+// SaveWithStateTransitions saves model, and the state_transitions its AfterSave appends, in one transaction so that
+// neither is saved without the other
+func SaveWithStateTransitions(db *pop.Connection, model interface{}) (*validate.Errors, error) {
+	verrs := validate.NewErrors()
+	err := db.Transaction(func(tx *pop.Connection) error {
+		var err error
+		verrs, err = tx.ValidateAndSave(model)
+		if err == nil && verrs.HasAny() {
+			return errors.New("Rollback The transaction")
+		}
+		return err
+	})
+	if verrs.HasAny() {
+		return verrs, nil
+	}
+	return verrs, err
+}
@@ -0,0 +1,130 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestNewTransitionActor() {
+	userID := uuid.Must(uuid.NewV4())
+	actor := NewTransitionActor(&auth.Session{UserID: userID, ApplicationName: auth.OfficeApp}, "trace-id")
+	suite.Equal(userID, *actor.UserID)
+	suite.Equal(string(auth.OfficeApp), actor.Application)
+	suite.Equal("trace-id", actor.RequestID)
+
+	// Without a session, the change is made by the system
+	suite.Equal(TransitionActor{}, NewTransitionActor(nil, ""))
+}
+
+func (suite *ModelSuite) TestStateTransitionsSavedWithMove() {
+	ppm := testdatagen.MakeDefaultPPM(suite.DB())
+	move := ppm.Move
+	move.PersonallyProcuredMoves = PersonallyProcuredMoves{ppm}
+	user := testdatagen.MakeDefaultUser(suite.DB())
+	actor := TransitionActor{UserID: &user.ID, Application: string(auth.OfficeApp), RequestID: "trace-id"}
+
+	move.SetTransitionActor(actor)
+	suite.NoError(move.Submit(time.Now()))
+	verrs, err := SaveMoveDependencies(suite.DB(), &move)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	suite.NoError(move.Cancel("changed my mind"))
+	verrs, err = SaveMoveDependencies(suite.DB(), &move)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	transitions, err := FetchStateTransitionsForMove(suite.DB(), move.ID)
+	suite.NoError(err)
+
+	var moveTransitions, ppmTransitions StateTransitions
+	for _, transition := range transitions {
+		switch transition.EntityType {
+		case StateTransitionEntityTypeMOVE:
+			moveTransitions = append(moveTransitions, transition)
+		case StateTransitionEntityTypePPM:
+			ppmTransitions = append(ppmTransitions, transition)
+		}
+	}
+
+	if suite.Len(moveTransitions, 2) {
+		submitted := moveTransitions[0]
+		suite.Equal(move.ID, submitted.EntityID)
+		suite.Equal(string(MoveStatusDRAFT), submitted.PreviousStatus)
+		suite.Equal(string(MoveStatusSUBMITTED), submitted.NewStatus)
+		suite.Equal(user.ID, *submitted.ActorUserID)
+		suite.Equal(string(auth.OfficeApp), *submitted.Application)
+		suite.Equal("trace-id", *submitted.RequestID)
+		suite.Nil(submitted.Reason)
+
+		canceled := moveTransitions[1]
+		suite.Equal(string(MoveStatusSUBMITTED), canceled.PreviousStatus)
+		suite.Equal(string(MoveStatusCANCELED), canceled.NewStatus)
+		suite.Equal("changed my mind", *canceled.Reason)
+	}
+
+	// The move's PPM was submitted and canceled along with it, by the same actor
+	if suite.Len(ppmTransitions, 2) {
+		suite.Equal(ppm.ID, ppmTransitions[0].EntityID)
+		suite.Equal(string(PPMStatusSUBMITTED), ppmTransitions[0].NewStatus)
+		suite.Equal(string(PPMStatusCANCELED), ppmTransitions[1].NewStatus)
+		suite.Equal(user.ID, *ppmTransitions[1].ActorUserID)
+	}
+
+	// Saving again doesn't record the transitions twice
+	verrs, err = SaveMoveDependencies(suite.DB(), &move)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+	again, err := FetchStateTransitionsForMove(suite.DB(), move.ID)
+	suite.NoError(err)
+	suite.Len(again, len(transitions))
+}
+
+func (suite *ModelSuite) TestStateTransitionsForShipment() {
+	shipment := testdatagen.MakeShipment(suite.DB(), testdatagen.Assertions{
+		Shipment: Shipment{Status: ShipmentStatusSUBMITTED},
+	})
+	sit := testdatagen.MakeStorageInTransit(suite.DB(), testdatagen.Assertions{
+		StorageInTransit: StorageInTransit{
+			Shipment:   shipment,
+			ShipmentID: shipment.ID,
+		},
+	})
+
+	suite.NoError(shipment.Award())
+	verrs, err := SaveWithStateTransitions(suite.DB(), &shipment)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	suite.NoError(sit.Approve(time.Now(), StringPointer("approved")))
+	verrs, err = SaveWithStateTransitions(suite.DB(), &sit)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	transitions, err := FetchStateTransitionsForShipment(suite.DB(), shipment.ID)
+	suite.NoError(err)
+	if suite.Len(transitions, 2) {
+		suite.Equal(StateTransitionEntityTypeSHIPMENT, transitions[0].EntityType)
+		suite.Equal(string(ShipmentStatusAWARDED), transitions[0].NewStatus)
+		suite.Nil(transitions[0].ActorUserID)
+		suite.Equal(shipment.MoveID, *transitions[0].MoveID)
+
+		suite.Equal(StateTransitionEntityTypeSTORAGEINTRANSIT, transitions[1].EntityType)
+		suite.Equal(sit.ID, transitions[1].EntityID)
+		suite.Equal(string(StorageInTransitStatusREQUESTED), transitions[1].PreviousStatus)
+		suite.Equal(string(StorageInTransitStatusAPPROVED), transitions[1].NewStatus)
+		suite.Equal("approved", *transitions[1].Reason)
+		suite.Equal(shipment.MoveID, *transitions[1].MoveID)
+	}
+
+	// The history is append-only
+	transition := transitions[0]
+	transition.NewStatus = string(ShipmentStatusACCEPTED)
+	suite.Error(suite.DB().Update(&transition))
+	suite.Error(suite.DB().Destroy(&transition))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/unit"
 )
 
 // StorageInTransitStatus represents the status of a SIT request
@@ -57,6 +58,7 @@ type StorageInTransit struct {
 	ID                  uuid.UUID                `json:"id" db:"id"`
 	CreatedAt           time.Time                `json:"created_at" db:"created_at"`
 	UpdatedAt           time.Time                `json:"updated_at" db:"updated_at"`
+	Version             int                      `json:"version" db:"version"`
 	ShipmentID          uuid.UUID                `json:"shipment_id" db:"shipment_id"`
 	SITNumber           *string                  `json:"sit_number" db:"sit_number"`
 	Status              StorageInTransitStatus   `json:"status" db:"status"`
@@ -72,6 +74,7 @@ type StorageInTransit struct {
 	WarehouseAddressID  uuid.UUID                `json:"warehouse_address_id" db:"warehouse_address_id"`
 	WarehousePhone      *string                  `json:"warehouse_phone" db:"warehouse_phone"`
 	WarehouseEmail      *string                  `json:"warehouse_email" db:"warehouse_email"`
+	WeightLbs           *unit.Pound              `json:"weight_lbs" db:"weight_lbs"`
 
 	// distance
 	StorageInTransitDistanceID *uuid.UUID          `json:"storage_in_transit_distance_id" db:"storage_in_transit_distance_id"`
@@ -80,6 +83,9 @@ type StorageInTransit struct {
 	// Associations
 	Shipment         Shipment `belongs_to:"shipment"`
 	WarehouseAddress Address  `belongs_to:"address"`
+
+	StateHistory `db:"-" json:"-"`
+	VersionCheck `db:"-" json:"-"`
 }
 
 // StorageInTransits is not required by pop and may be deleted
@@ -105,9 +111,53 @@ func (s *StorageInTransit) Validate(tx *pop.Connection) (*validate.Errors, error
 		&validators.UUIDIsPresent{Field: s.WarehouseAddressID, Name: "WarehouseAddressID"},
 		&StringIsNilOrNotBlank{Field: s.WarehousePhone, Name: "WarehousePhone"},
 		&StringIsNilOrNotBlank{Field: s.WarehouseEmail, Name: "WarehouseEmail"},
+		&OptionalPoundIsNonNegative{Field: s.WeightLbs, Name: "WeightLbs"},
 	), nil
 }
 
+// IsSplitDelivery returns true if only part of a shipment with the given net weight was placed in SIT. The 1,000 lb
+// minimum weight for SIT charges doesn't apply to split deliveries.
+func (s StorageInTransit) IsSplitDelivery(netWeight unit.Pound) bool {
+	return s.WeightLbs != nil && *s.WeightLbs < netWeight
+}
+
+// Weight returns the weight placed in SIT, given the net weight of its shipment
+func (s StorageInTransit) Weight(netWeight unit.Pound) unit.Pound {
+	if s.IsSplitDelivery(netWeight) {
+		return *s.WeightLbs
+	}
+	return netWeight
+}
+
+// DaysInSIT returns the number of days a SIT has been in storage, counting the day it went in but not the day it came
+// out. It's zero if it hasn't gone in or come out yet.
+func (s StorageInTransit) DaysInSIT() int {
+	if s.ActualStartDate == nil || s.OutDate == nil {
+		return 0
+	}
+	days := int(s.OutDate.Sub(*s.ActualStartDate).Hours() / 24)
+	if days < 1 {
+		// Going in and coming out on the same day still counts as the first day
+		return 1
+	}
+	return days
+}
+
+// IsInAlaska returns true if either end of the SIT's pickup or delivery, given its shipment, is in Alaska. Those
+// over 50 miles are priced using the intra-Alaska linehaul rates.
+func (s StorageInTransit) IsInAlaska(shipment Shipment) bool {
+	if s.WarehouseAddress.State == "AK" {
+		return true
+	}
+	if s.Location == StorageInTransitLocationDESTINATION {
+		if shipment.DestinationAddressOnAcceptance != nil {
+			return shipment.DestinationAddressOnAcceptance.State == "AK"
+		}
+		return shipment.Move.Orders.NewDutyStation.Address.State == "AK"
+	}
+	return shipment.PickupAddress != nil && shipment.PickupAddress.State == "AK"
+}
+
 // FetchStorageInTransitsOnShipment retrieves Storage In Transit objects and their warehouse address using the shipment ID
 func FetchStorageInTransitsOnShipment(tx *pop.Connection, shipmentID uuid.UUID) (StorageInTransits, error) {
 	storageInTransits := StorageInTransits{}
@@ -190,6 +240,76 @@ func SaveStorageInTransitAndAddress(db *pop.Connection, storageInTransit *Storag
 	return responseVErrors, responseError
 }
 
+// Approve authorizes the SIT from authorizedStartDate. A delivered SIT can't be approved.
+func (s *StorageInTransit) Approve(authorizedStartDate time.Time, authorizationNotes *string) error {
+	if s.Status == StorageInTransitStatusDELIVERED {
+		return ErrWriteConflict
+	}
+
+	s.recordTransition(string(s.Status), string(StorageInTransitStatusAPPROVED), authorizationNotes)
+	s.Status = StorageInTransitStatusAPPROVED
+	s.AuthorizationNotes = authorizationNotes
+	s.AuthorizedStartDate = &authorizedStartDate
+
+	return nil
+}
+
+// Deny denies the SIT request. A delivered SIT can't be denied.
+func (s *StorageInTransit) Deny(authorizationNotes string) error {
+	if s.Status == StorageInTransitStatusDELIVERED {
+		return ErrWriteConflict
+	}
+
+	s.recordTransition(string(s.Status), string(StorageInTransitStatusDENIED), &authorizationNotes)
+	s.Status = StorageInTransitStatusDENIED
+	s.AuthorizationNotes = &authorizationNotes
+
+	return nil
+}
+
+// PlaceIntoSIT marks an approved SIT as in storage from actualStartDate
+func (s *StorageInTransit) PlaceIntoSIT(actualStartDate time.Time) error {
+	if s.Status != StorageInTransitStatusAPPROVED {
+		return ErrWriteConflict
+	}
+
+	s.recordTransition(string(s.Status), string(StorageInTransitStatusINSIT), nil)
+	s.Status = StorageInTransitStatusINSIT
+	s.ActualStartDate = &actualStartDate
+
+	return nil
+}
+
+// Release releases an ORIGIN SIT from storage on releasedOn. A delivered SIT can be released too, to undo a
+// mistaken delivery.
+func (s *StorageInTransit) Release(releasedOn time.Time) error {
+	if s.Location != StorageInTransitLocationORIGIN {
+		return ErrInvalidTransition
+	}
+	if !(s.Status == StorageInTransitStatusINSIT) &&
+		!(s.Status == StorageInTransitStatusDELIVERED) {
+		return ErrWriteConflict
+	}
+
+	s.recordTransition(string(s.Status), string(StorageInTransitStatusRELEASED), nil)
+	s.Status = StorageInTransitStatusRELEASED
+	s.OutDate = &releasedOn
+
+	return nil
+}
+
+// CanAttemptDelivery returns an error unless a pickup into, or delivery out of, the SIT can be attempted. An ORIGIN
+// SIT is picked up once it's approved, and a DESTINATION SIT is delivered while it's in SIT. A failed attempt doesn't
+// change the SIT's status.
+func (s StorageInTransit) CanAttemptDelivery() error {
+	isOriginPickup := s.Location == StorageInTransitLocationORIGIN && s.Status == StorageInTransitStatusAPPROVED
+	isDestinationDelivery := s.Location == StorageInTransitLocationDESTINATION && s.Status == StorageInTransitStatusINSIT
+	if !isOriginPickup && !isDestinationDelivery {
+		return ErrWriteConflict
+	}
+	return nil
+}
+
 // Deliver changes a sit status to Delivered status and sets the OutDate
 func (s *StorageInTransit) Deliver(deliveryDate time.Time) error {
 	// A SIT must be IN SIT and a DESTINATION SIT in order to be delivered
@@ -198,12 +318,35 @@ func (s *StorageInTransit) Deliver(deliveryDate time.Time) error {
 		return ErrWriteConflict
 	}
 
+	s.recordTransition(string(s.Status), string(StorageInTransitStatusDELIVERED), nil)
 	s.Status = StorageInTransitStatusDELIVERED
 	s.OutDate = &deliveryDate
 
 	return nil
 }
 
+// BeforeUpdate will run before each update of a StorageInTransit, and increments its version.
+func (s *StorageInTransit) BeforeUpdate(tx *pop.Connection) error {
+	return s.bumpVersion(tx, "storage_in_transits", s.ID, &s.Version)
+}
+
+// AfterSave will run after each create/update of a StorageInTransit, and saves its status changes against the
+// shipment's move.
+func (s *StorageInTransit) AfterSave(tx *pop.Connection) error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	moveID := s.Shipment.MoveID
+	if moveID == uuid.Nil {
+		var shipment Shipment
+		if err := tx.Find(&shipment, s.ShipmentID); err != nil {
+			return errors.Wrap(err, "Could not fetch shipment for SIT")
+		}
+		moveID = shipment.MoveID
+	}
+	return s.saveStateTransitions(tx, &moveID, StateTransitionEntityTypeSTORAGEINTRANSIT, s.ID)
+}
+
 func (s *StorageInTransit) SaveActualDeliveryDateAsOutDate(db *pop.Connection, session *auth.Session, newOutDate time.Time) (*validate.Errors, error) {
 	responseVErrors := validate.NewErrors()
 	var responseError error
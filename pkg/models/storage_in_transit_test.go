@@ -11,6 +11,7 @@ import (
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
 )
 
 func (suite *ModelSuite) TestStorageInTransitValidations() {
@@ -264,3 +265,68 @@ func (suite *ModelSuite) TestSaveActualDeliveryDateAsOutDate() {
 
 	suite.Equal(actualShipment.ActualDeliveryDate, savedStorageInTransit.OutDate)
 }
+
+func (suite *ModelSuite) TestStorageInTransitDaysAndWeight() {
+	in := time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)
+	out := time.Date(2019, time.June, 11, 0, 0, 0, 0, time.UTC)
+
+	sit := models.StorageInTransit{}
+	suite.Equal(0, sit.DaysInSIT())
+
+	sit.ActualStartDate = &in
+	suite.Equal(0, sit.DaysInSIT())
+
+	sit.OutDate = &out
+	suite.Equal(10, sit.DaysInSIT())
+
+	sit.OutDate = &in
+	suite.Equal(1, sit.DaysInSIT())
+
+	netWeight := unit.Pound(4000)
+	suite.False(sit.IsSplitDelivery(netWeight))
+	suite.Equal(netWeight, sit.Weight(netWeight))
+
+	sitWeight := unit.Pound(1500)
+	sit.WeightLbs = &sitWeight
+	suite.True(sit.IsSplitDelivery(netWeight))
+	suite.Equal(sitWeight, sit.Weight(netWeight))
+}
+
+func (suite *ModelSuite) TestStorageInTransitCanAttemptDelivery() {
+	tests := []struct {
+		location models.StorageInTransitLocation
+		status   models.StorageInTransitStatus
+		canTry   bool
+	}{
+		{models.StorageInTransitLocationORIGIN, models.StorageInTransitStatusAPPROVED, true},
+		{models.StorageInTransitLocationORIGIN, models.StorageInTransitStatusINSIT, false},
+		{models.StorageInTransitLocationDESTINATION, models.StorageInTransitStatusINSIT, true},
+		{models.StorageInTransitLocationDESTINATION, models.StorageInTransitStatusAPPROVED, false},
+		{models.StorageInTransitLocationDESTINATION, models.StorageInTransitStatusDELIVERED, false},
+	}
+	for _, test := range tests {
+		sit := models.StorageInTransit{Location: test.location, Status: test.status}
+		if test.canTry {
+			suite.NoError(sit.CanAttemptDelivery(), "%s %s", test.location, test.status)
+		} else {
+			suite.Equal(models.ErrWriteConflict, sit.CanAttemptDelivery(), "%s %s", test.location, test.status)
+		}
+	}
+}
+
+func (suite *ModelSuite) TestStorageInTransitIsInAlaska() {
+	pickup := models.Address{State: "CA"}
+	shipment := models.Shipment{PickupAddress: &pickup}
+	shipment.Move.Orders.NewDutyStation.Address.State = "AK"
+
+	origin := models.StorageInTransit{Location: models.StorageInTransitLocationORIGIN}
+	suite.False(origin.IsInAlaska(shipment))
+	origin.WarehouseAddress.State = "AK"
+	suite.True(origin.IsInAlaska(shipment))
+
+	destination := models.StorageInTransit{Location: models.StorageInTransitLocationDESTINATION}
+	suite.True(destination.IsInAlaska(shipment))
+	deliveryAddress := models.Address{State: "WA"}
+	shipment.DestinationAddressOnAcceptance = &deliveryAddress
+	suite.False(destination.IsInAlaska(shipment))
+}
@@ -38,6 +38,15 @@ func (t *Tariff400ngFullUnpackRate) Validate(tx *pop.Connection) (*validate.Erro
 // FetchTariff400ngFullUnpackRateMillicents returns the full unpack rate for a service
 // schedule.
 func FetchTariff400ngFullUnpackRateMillicents(tx *pop.Connection, serviceSchedule int, date time.Time) (int, error) {
+	rate, err := FetchTariff400ngFullUnpackRate(tx, serviceSchedule, date)
+	if err != nil {
+		return 0, err
+	}
+	return rate.RateMillicents, nil
+}
+
+// FetchTariff400ngFullUnpackRate returns the full unpack rate for a service schedule.
+func FetchTariff400ngFullUnpackRate(tx *pop.Connection, serviceSchedule int, date time.Time) (Tariff400ngFullUnpackRate, error) {
 	rate := Tariff400ngFullUnpackRate{}
 
 	sql := `SELECT *
@@ -52,7 +61,7 @@ func FetchTariff400ngFullUnpackRateMillicents(tx *pop.Connection, serviceSchedul
 	err := tx.RawQuery(sql, serviceSchedule, date).First(&rate)
 
 	if err != nil {
-		return 0, errors.Wrap(err, "could not find a matching Tariff400ngFullUnpackRate")
+		return rate, errors.Wrap(err, "could not find a matching Tariff400ngFullUnpackRate")
 	}
-	return rate.RateMillicents, nil
+	return rate, nil
 }
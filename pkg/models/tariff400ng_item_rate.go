@@ -25,6 +25,9 @@ type Tariff400ngItemRate struct {
 	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// Tariff400ngItemRates is a slice of Tariff400ngItemRate
+type Tariff400ngItemRates []Tariff400ngItemRate
+
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
 // This method is not required and may be deleted.
 func (t *Tariff400ngItemRate) Validate(tx *pop.Connection) (*validate.Errors, error) {
@@ -12,6 +12,13 @@ import (
 	"github.com/transcom/mymove/pkg/unit"
 )
 
+const (
+	// Tariff400ngLinehaulRateTypeCONUS is the type of linehaul rates within the continental US
+	Tariff400ngLinehaulRateTypeCONUS = "ConusLinehaul"
+	// Tariff400ngLinehaulRateTypeINTRAALASKA is the type of linehaul rates within Alaska
+	Tariff400ngLinehaulRateTypeINTRAALASKA = "IntraAlaskaLinehaul"
+)
+
 // Tariff400ngLinehaulRate describes the rate paids paid to transport various weights of goods
 // various distances.
 type Tariff400ngLinehaulRate struct {
@@ -60,12 +67,24 @@ func (t *Tariff400ngLinehaulRate) ValidateUpdate(tx *pop.Connection) (*validate.
 
 // FetchBaseLinehaulRate takes a move's distance and weight and queries the tariff400ng_linehaul_rates table to find a move's base linehaul rate.
 func FetchBaseLinehaulRate(tx *pop.Connection, mileage int, weight unit.Pound, date time.Time) (linehaulRate unit.Cents, err error) {
-	// TODO: change to a parameter once we're serving more move types
-	moveType := "ConusLinehaul"
-	var linehaulRates []unit.Cents
+	rate, err := FetchTariff400ngLinehaulRate(tx, mileage, weight, date)
+	if err != nil {
+		return 0, err
+	}
+	return rate.RateCents, nil
+}
+
+// FetchTariff400ngLinehaulRate returns the linehaul rate for a move's distance and weight
+func FetchTariff400ngLinehaulRate(tx *pop.Connection, mileage int, weight unit.Pound, date time.Time) (Tariff400ngLinehaulRate, error) {
+	return FetchTariff400ngLinehaulRateForType(tx, Tariff400ngLinehaulRateTypeCONUS, mileage, weight, date)
+}
+
+// FetchTariff400ngLinehaulRateForType returns the linehaul rate of the given type for a move's distance and weight
+func FetchTariff400ngLinehaulRateForType(tx *pop.Connection, moveType string, mileage int, weight unit.Pound, date time.Time) (Tariff400ngLinehaulRate, error) {
+	var linehaulRates Tariff400ngLinehaulRates
 
 	sql := `SELECT
-		rate_cents
+		*
 	FROM
 		tariff400ng_linehaul_rates
 	WHERE
@@ -77,15 +96,15 @@ func FetchBaseLinehaulRate(tx *pop.Connection, mileage int, weight unit.Pound, d
 	AND
 		(effective_date_lower <= $4 AND $4 < effective_date_upper);`
 
-	err = tx.RawQuery(sql, mileage, weight.Int(), moveType, date).All(&linehaulRates)
+	err := tx.RawQuery(sql, mileage, weight.Int(), moveType, date).All(&linehaulRates)
 
 	if err != nil {
-		return 0, fmt.Errorf("Error fetching linehaul rate: %s", err)
+		return Tariff400ngLinehaulRate{}, fmt.Errorf("Error fetching linehaul rate: %s", err)
 	}
 	if len(linehaulRates) != 1 {
-		return 0, fmt.Errorf("Wanted 1 rate, found %d rates for parameters: %v, %v, %v",
+		return Tariff400ngLinehaulRate{}, fmt.Errorf("Wanted 1 rate, found %d rates for parameters: %v, %v, %v",
 			len(linehaulRates), mileage, weight, date)
 	}
 
-	return linehaulRates[0], err
+	return linehaulRates[0], nil
 }
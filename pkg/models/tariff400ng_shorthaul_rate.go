@@ -56,10 +56,19 @@ func (t *Tariff400ngShorthaulRate) ValidateUpdate(tx *pop.Connection) (*validate
 // (cwtMiles is a unit capturing the movement of 100lbs by 1 mile.) The value returned
 // is in cents of 1 USD.
 func FetchShorthaulRateCents(tx *pop.Connection, cwtMiles int, date time.Time) (rateCents unit.Cents, err error) {
+	rate, err := FetchTariff400ngShorthaulRate(tx, cwtMiles, date)
+	if err != nil {
+		return 0, err
+	}
+	return rate.RateCents, nil
+}
+
+// FetchTariff400ngShorthaulRate returns the shorthaul rate for a given Centumweight-Miles
+func FetchTariff400ngShorthaulRate(tx *pop.Connection, cwtMiles int, date time.Time) (Tariff400ngShorthaulRate, error) {
 	sh := Tariff400ngShorthaulRates{}
 
 	sql := `SELECT
-		rate_cents
+		*
 	FROM
 		tariff400ng_shorthaul_rates
 	WHERE
@@ -67,14 +76,14 @@ func FetchShorthaulRateCents(tx *pop.Connection, cwtMiles int, date time.Time) (
 	AND
 		effective_date_lower <= $2 AND $2 < effective_date_upper`
 
-	err = tx.RawQuery(sql, cwtMiles, date).All(&sh)
+	err := tx.RawQuery(sql, cwtMiles, date).All(&sh)
 	if err != nil {
-		return 0, errors.Wrapf(err, "error fetching shorthaul rate for %d cwtmiles on %s", cwtMiles, date)
+		return Tariff400ngShorthaulRate{}, errors.Wrapf(err, "error fetching shorthaul rate for %d cwtmiles on %s", cwtMiles, date)
 	}
 	if len(sh) != 1 {
-		return 0, errors.Errorf("Wanted 1 shorthaul rate, found %d rates for parameters: %v cwtMiles, %v",
+		return Tariff400ngShorthaulRate{}, errors.Errorf("Wanted 1 shorthaul rate, found %d rates for parameters: %v cwtMiles, %v",
 			len(sh), cwtMiles, date)
 	}
 
-	return sh[0].RateCents, nil
+	return sh[0], nil
 }
@@ -17,17 +17,6 @@ import (
 	"github.com/transcom/mymove/pkg/unit"
 )
 
-var qualityBands = []int{1, 2, 3, 4}
-
-// OffersPerQualityBand is a map of the number of shipments to be offered per round to each quality band
-// TODO: change these back to [5, 3, 2, 1] after the B&M pilot
-var OffersPerQualityBand = map[int]int{
-	1: 1,
-	2: 1,
-	3: 1,
-	4: 1,
-}
-
 // TransportationServiceProviderPerformance is a combination of all TSP
 // performance metrics (BVS, Quality Band) for a performance period.
 type TransportationServiceProviderPerformance struct {
@@ -47,6 +36,7 @@ type TransportationServiceProviderPerformance struct {
 	LinehaulRate                    unit.DiscountRate             `db:"linehaul_rate"`
 	SITRate                         unit.DiscountRate             `db:"sit_rate"`
 	OfferCount                      int                           `db:"offer_count"`
+	AwardQueuePolicyID              *uuid.UUID                    `db:"award_queue_policy_id"`
 }
 
 // TransportationServiceProviderPerformances is a handy type for multiple TransportationServiceProviderPerformance structs
@@ -130,12 +120,13 @@ func NextTSPPerformanceInQualityBand(tx *pop.Connection, tdlID uuid.UUID,
 	return tspp, err
 }
 
-// GatherNextEligibleTSPPerformances returns a map of QualityBands to their next eligible TSPPerformance.
-func GatherNextEligibleTSPPerformances(tx *pop.Connection, tdlID uuid.UUID, bookDate time.Time, requestedPickupDate time.Time) (map[int]TransportationServiceProviderPerformance, error) {
+// GatherNextEligibleTSPPerformances returns a map of QualityBands to their next eligible TSPPerformance,
+// looking at each of the quality bands in the award queue policy.
+func GatherNextEligibleTSPPerformances(tx *pop.Connection, tdlID uuid.UUID, bookDate time.Time, requestedPickupDate time.Time, policy AwardQueuePolicy) (map[int]TransportationServiceProviderPerformance, error) {
 	tspPerformances := make(map[int]TransportationServiceProviderPerformance)
 	qualityBandsWithoutTSPs := 0
 
-	for _, qualityBand := range qualityBands {
+	for qualityBand := 1; qualityBand <= policy.NumQualityBands(); qualityBand++ {
 		tspPerformance, err := NextTSPPerformanceInQualityBand(tx, tdlID, qualityBand, bookDate, requestedPickupDate)
 		if err != nil {
 			if err.Error() == "sql: no rows in result set" {
@@ -149,33 +140,34 @@ func GatherNextEligibleTSPPerformances(tx *pop.Connection, tdlID uuid.UUID, book
 			tspPerformances[qualityBand] = tspPerformance
 		}
 	}
-	if qualityBandsWithoutTSPs >= len(qualityBands) {
+	if qualityBandsWithoutTSPs >= policy.NumQualityBands() {
 		return tspPerformances, fmt.Errorf("Could not find any TSPs to fill quality bands in TDL: %s", tdlID)
 	}
 	return tspPerformances, nil
 }
 
 // NextEligibleTSPPerformance wraps GatherNextEligibleTSPPerformances and DetermineNextTSPPerformance.
-func NextEligibleTSPPerformance(db *pop.Connection, tdlID uuid.UUID, bookDate time.Time, requestedPickupDate time.Time) (TransportationServiceProviderPerformance, error) {
+func NextEligibleTSPPerformance(db *pop.Connection, tdlID uuid.UUID, bookDate time.Time, requestedPickupDate time.Time, policy AwardQueuePolicy) (TransportationServiceProviderPerformance, error) {
 	var tspPerformance TransportationServiceProviderPerformance
-	tspPerformances, err := GatherNextEligibleTSPPerformances(db, tdlID, bookDate, requestedPickupDate)
+	tspPerformances, err := GatherNextEligibleTSPPerformances(db, tdlID, bookDate, requestedPickupDate, policy)
 	if err == nil {
-		return SelectNextTSPPerformance(tspPerformances), nil
+		return SelectNextTSPPerformance(tspPerformances, policy), nil
 	}
 	return tspPerformance, err
 }
 
-// SelectNextTSPPerformance returns the tspPerformance that is next to receive a shipment.
-func SelectNextTSPPerformance(tspPerformances map[int]TransportationServiceProviderPerformance) TransportationServiceProviderPerformance {
+// SelectNextTSPPerformance returns the tspPerformance that is next to receive a shipment, using the offers
+// per quality band from the award queue policy.
+func SelectNextTSPPerformance(tspPerformances map[int]TransportationServiceProviderPerformance, policy AwardQueuePolicy) TransportationServiceProviderPerformance {
 	bands := sortedMapIntKeys(tspPerformances)
 	// First time through, no rounds have yet occurred so rounds is set to the maximum rounds that have already occurred.
 	// Since the TSPs in quality band 1 will always have been offered the greatest number of shipments, we use that to calculate max.
-	maxRounds := float64(tspPerformances[bands[0]].OfferCount) / float64(OffersPerQualityBand[bands[0]])
+	maxRounds := float64(tspPerformances[bands[0]].OfferCount) / float64(policy.OffersForQualityBand(bands[0]))
 	previousRounds := math.Ceil(maxRounds)
 
 	for _, band := range bands {
 		tspPerformance := tspPerformances[band]
-		rounds := float64(tspPerformance.OfferCount) / float64(OffersPerQualityBand[band])
+		rounds := float64(tspPerformance.OfferCount) / float64(policy.OffersForQualityBand(band))
 
 		if rounds < previousRounds {
 			return tspPerformance
@@ -245,8 +237,9 @@ func FetchUnbandedTSPPerformanceGroups(db *pop.Connection) (TSPPerformanceGroups
 	return perfGroups, err
 }
 
-// AssignQualityBandToTSPPerformance sets the QualityBand value for a TransportationServiceProviderPerformance.
-func AssignQualityBandToTSPPerformance(ctx context.Context, db *pop.Connection, band int, id uuid.UUID) error {
+// AssignQualityBandToTSPPerformance sets the QualityBand value for a TransportationServiceProviderPerformance, along
+// with the award queue policy it was assigned under (nil for the default policy).
+func AssignQualityBandToTSPPerformance(ctx context.Context, db *pop.Connection, band int, id uuid.UUID, policyID *uuid.UUID) error {
 	_, span := beeline.StartSpan(ctx, "AssignQualityBandToTSPPerformance")
 	defer span.Send()
 	performance := TransportationServiceProviderPerformance{}
@@ -256,6 +249,7 @@ func AssignQualityBandToTSPPerformance(ctx context.Context, db *pop.Connection,
 	span.AddField("tsp_performance_id", performance.ID.String())
 
 	performance.QualityBand = &band
+	performance.AwardQueuePolicyID = policyID
 	span.AddField("tsp_performance_band", performance.QualityBand)
 	verrs, err := db.ValidateAndUpdate(&performance)
 	if err != nil {
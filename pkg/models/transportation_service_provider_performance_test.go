@@ -147,8 +147,9 @@ func (suite *ModelSuite) Test_AssignQualityBandToTSPPerformance() {
 		},
 	})
 	band := 1
+	policy := testdatagen.MakeDefaultAwardQueuePolicy(suite.DB())
 
-	err := AssignQualityBandToTSPPerformance(context.Background(), suite.DB(), band, perf.ID)
+	err := AssignQualityBandToTSPPerformance(context.Background(), suite.DB(), band, perf.ID, &policy.ID)
 	if err != nil {
 		t.Fatalf("Did not update quality band: %v", err)
 	}
@@ -163,6 +164,10 @@ func (suite *ModelSuite) Test_AssignQualityBandToTSPPerformance() {
 	} else if *performance.QualityBand != band {
 		t.Errorf("Wrong value for QualityBand: expected %d, got %d", band, *performance.QualityBand)
 	}
+
+	if performance.AwardQueuePolicyID == nil || *performance.AwardQueuePolicyID != policy.ID {
+		t.Errorf("Wrong value for AwardQueuePolicyID: expected %v, got %v", policy.ID, performance.AwardQueuePolicyID)
+	}
 }
 
 func (suite *ModelSuite) Test_BVSWithLowMPS() {
@@ -283,7 +288,7 @@ func (suite *ModelSuite) Test_SelectNextTSPPerformanceAllZeros() {
 		3: tspp3,
 		4: tspp4}
 
-	chosen := SelectNextTSPPerformance(choices)
+	chosen := SelectNextTSPPerformance(choices, DefaultAwardQueuePolicy())
 
 	if chosen != tspp1 {
 		t.Errorf("Wrong TSPPerformance selected: expected band %v, got %v", *tspp1.QualityBand, *chosen.QualityBand)
@@ -303,7 +308,7 @@ func (suite *ModelSuite) Test_SelectNextTSPPerformanceOneAssigned() {
 		3: tspp3,
 		4: tspp4}
 
-	chosen := SelectNextTSPPerformance(choices)
+	chosen := SelectNextTSPPerformance(choices, DefaultAwardQueuePolicy())
 
 	if chosen != tspp2 {
 		t.Errorf("Wrong TSPPerformance selected: expected band %v, got %v", *tspp1.QualityBand, *chosen.QualityBand)
@@ -323,7 +328,7 @@ func (suite *ModelSuite) Test_SelectNextTSPPerformanceOneFullRound() {
 		3: tspp3,
 		4: tspp4}
 
-	chosen := SelectNextTSPPerformance(choices)
+	chosen := SelectNextTSPPerformance(choices, DefaultAwardQueuePolicy())
 
 	if chosen != tspp2 {
 		t.Errorf("Wrong TSPPerformance selected: expected band %v, got %v", *tspp1.QualityBand, *chosen.QualityBand)
@@ -343,7 +348,7 @@ func (suite *ModelSuite) Test_SelectNextTSPPerformanceTwoFullRounds() {
 		3: tspp3,
 		4: tspp4}
 
-	chosen := SelectNextTSPPerformance(choices)
+	chosen := SelectNextTSPPerformance(choices, DefaultAwardQueuePolicy())
 
 	if chosen != tspp2 {
 		t.Errorf("Wrong TSPPerformance selected: expected band %v, got %v", *tspp1.QualityBand, *chosen.QualityBand)
@@ -363,7 +368,7 @@ func (suite *ModelSuite) Test_SelectNextTSPPerformanceFirstBandFilled() {
 		3: tspp3,
 		4: tspp4}
 
-	chosen := SelectNextTSPPerformance(choices)
+	chosen := SelectNextTSPPerformance(choices, DefaultAwardQueuePolicy())
 
 	if chosen != tspp2 {
 		t.Errorf("Wrong TSPPerformance selected: expected band %v, got %v", *tspp2.QualityBand, *chosen.QualityBand)
@@ -381,7 +386,7 @@ func (suite *ModelSuite) Test_SelectNextTSPPerformanceThreeBands() {
 		2: tspp2,
 		3: tspp3}
 
-	chosen := SelectNextTSPPerformance(choices)
+	chosen := SelectNextTSPPerformance(choices, DefaultAwardQueuePolicy())
 
 	if chosen != tspp2 {
 		t.Errorf("Wrong TSPPerformance selected: expected band %v, got %v", *tspp2.QualityBand, *chosen.QualityBand)
@@ -401,7 +406,7 @@ func (suite *ModelSuite) Test_SelectNextTSPPerformanceHalfOffered() {
 		3: tspp3,
 		4: tspp4}
 
-	chosen := SelectNextTSPPerformance(choices)
+	chosen := SelectNextTSPPerformance(choices, DefaultAwardQueuePolicy())
 
 	if chosen != tspp2 {
 		t.Errorf("Wrong TSPPerformance selected: expected band %v, got %v", *tspp3.QualityBand, *chosen.QualityBand)
@@ -421,7 +426,7 @@ func (suite *ModelSuite) Test_SelectNextTSPPerformancePartialRound() {
 		3: tspp3,
 		4: tspp4}
 
-	chosen := SelectNextTSPPerformance(choices)
+	chosen := SelectNextTSPPerformance(choices, DefaultAwardQueuePolicy())
 
 	if chosen != tspp2 {
 		t.Errorf("Wrong TSPPerformance selected: expected band %v, got %v", *tspp3.QualityBand, *chosen.QualityBand)
@@ -481,7 +486,7 @@ func (suite *ModelSuite) Test_GatherNextEligibleTSPPerformances() {
 	})
 
 	tsps, err := GatherNextEligibleTSPPerformances(suite.DB(), tdl.ID, testdatagen.DateInsidePerformancePeriod,
-		testdatagen.DateInsidePeakRateCycle)
+		testdatagen.DateInsidePeakRateCycle, DefaultAwardQueuePolicy())
 	expectedTSPorder := []uuid.UUID{tsp1.ID, tsp3.ID, tsp4.ID, tsp5.ID}
 
 	actualTSPorder := []uuid.UUID{
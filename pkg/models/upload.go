@@ -15,19 +15,50 @@ import (
 	"github.com/transcom/mymove/pkg/auth"
 )
 
+// UploadStatus represents whether an upload has been scanned and found safe to share
+type UploadStatus string
+
+const (
+	// UploadStatusPENDING is an upload that is quarantined until it has been scanned
+	UploadStatusPENDING UploadStatus = "PENDING"
+	// UploadStatusCLEAN is an upload that has been scanned and can be fetched
+	UploadStatusCLEAN UploadStatus = "CLEAN"
+	// UploadStatusINFECTED is an upload that failed its scan and stays quarantined
+	UploadStatusINFECTED UploadStatus = "INFECTED"
+	// UploadStatusSCANFAILED is an upload that couldn't be scanned after repeated attempts and stays quarantined
+	UploadStatusSCANFAILED UploadStatus = "SCAN_FAILED"
+)
+
+var uploadStatuses = []string{
+	string(UploadStatusPENDING),
+	string(UploadStatusCLEAN),
+	string(UploadStatusINFECTED),
+	string(UploadStatusSCANFAILED),
+}
+
 // An Upload represents an uploaded file, such as an image or PDF.
 type Upload struct {
-	ID          uuid.UUID  `db:"id"`
-	DocumentID  *uuid.UUID `db:"document_id"`
-	Document    Document   `belongs_to:"documents"`
-	UploaderID  uuid.UUID  `db:"uploader_id"`
-	Filename    string     `db:"filename"`
-	Bytes       int64      `db:"bytes"`
-	ContentType string     `db:"content_type"`
-	Checksum    string     `db:"checksum"`
-	StorageKey  string     `db:"storage_key"`
-	CreatedAt   time.Time  `db:"created_at"`
-	UpdatedAt   time.Time  `db:"updated_at"`
+	ID                   uuid.UUID    `db:"id"`
+	DocumentID           *uuid.UUID   `db:"document_id"`
+	Document             Document     `belongs_to:"documents"`
+	UploaderID           uuid.UUID    `db:"uploader_id"`
+	Filename             string       `db:"filename"`
+	Bytes                int64        `db:"bytes"`
+	ContentType          string       `db:"content_type"`
+	Checksum             string       `db:"checksum"`
+	StorageKey           string       `db:"storage_key"`
+	ContentHash          *string      `db:"content_hash"`
+	EncryptionKeyID      *string      `db:"encryption_key_id"`
+	Status               UploadStatus `db:"status"`
+	ScannedAt            *time.Time   `db:"scanned_at"`
+	ScanResult           *string      `db:"scan_result"`
+	ScanAttempts         int          `db:"scan_attempts"`
+	LastScanAttemptedAt  *time.Time   `db:"last_scan_attempted_at"`
+	LastScanError        *string      `db:"last_scan_error"`
+	ThumbnailStorageKey  *string      `db:"thumbnail_storage_key"`
+	ThumbnailContentHash *string      `db:"thumbnail_content_hash"`
+	CreatedAt            time.Time    `db:"created_at"`
+	UpdatedAt            time.Time    `db:"updated_at"`
 }
 
 // Uploads is not required by pop and may be deleted
@@ -35,13 +66,23 @@ type Uploads []Upload
 
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
 func (u *Upload) Validate(tx *pop.Connection) (*validate.Errors, error) {
-	return validate.Validate(
+	vs := []validate.Validator{
 		&validators.UUIDIsPresent{Field: u.UploaderID, Name: "UploaderID"},
 		&validators.StringIsPresent{Field: u.Filename, Name: "Filename"},
 		&Int64IsPresent{Field: u.Bytes, Name: "Bytes"},
 		&validators.StringIsPresent{Field: u.ContentType, Name: "ContentType"},
 		&validators.StringIsPresent{Field: u.Checksum, Name: "Checksum"},
-	), nil
+	}
+	// An upload without a status is quarantined when it is created
+	if u.Status != "" {
+		vs = append(vs, &validators.StringInclusion{Field: string(u.Status), Name: "Status", List: uploadStatuses})
+	}
+	return validate.Validate(vs...), nil
+}
+
+// IsClean returns true if the upload has been scanned and can be fetched
+func (u *Upload) IsClean() bool {
+	return u.Status == UploadStatusCLEAN
 }
 
 // BeforeCreate populates the StorageKey on a newly created Upload
@@ -55,6 +96,10 @@ func (u *Upload) BeforeCreate(tx *pop.Connection) error {
 		u.StorageKey = path.Join("user", u.UploaderID.String(), "uploads", u.ID.String())
 	}
 
+	if u.Status == "" {
+		u.Status = UploadStatusPENDING
+	}
+
 	return nil
 }
 
@@ -91,3 +136,38 @@ func FetchUpload(ctx context.Context, db *pop.Connection, session *auth.Session,
 func DeleteUpload(db *pop.Connection, upload *Upload) error {
 	return db.Destroy(upload)
 }
+
+// ClaimPendingUploads claims and returns up to limit uploads waiting to be scanned that haven't been attempted
+// since retryBefore, least recently attempted first. Claiming counts the attempt and records when it was made in a
+// single statement, so no locks are held while the uploads are scanned and other workers leave them alone until
+// they're due to be retried.
+func ClaimPendingUploads(db *pop.Connection, now time.Time, retryBefore time.Time, limit int) (Uploads, error) {
+	var uploads Uploads
+	err := db.RawQuery(`
+		UPDATE uploads SET scan_attempts = scan_attempts + 1, last_scan_attempted_at = $1
+		WHERE id IN (
+			SELECT id FROM uploads
+			WHERE status = $2 AND (last_scan_attempted_at IS NULL OR last_scan_attempted_at <= $3)
+			ORDER BY last_scan_attempted_at ASC NULLS FIRST, created_at ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		now, UploadStatusPENDING, retryBefore, limit).All(&uploads)
+	return uploads, err
+}
+
+// LockUploadContent takes a lock on the shared content with contentHash that is held until tx commits, so that
+// content isn't deleted while an upload that refers to it is being created
+func LockUploadContent(tx *pop.Connection, contentHash string) error {
+	return tx.RawQuery("SELECT pg_advisory_xact_lock(hashtext($1))", "upload_content:"+contentHash).Exec()
+}
+
+// CountUploadsWithContentHash returns the number of uploads whose files have the given content hash
+func CountUploadsWithContentHash(db *pop.Connection, contentHash string) (int, error) {
+	return db.Where("content_hash = ?", contentHash).Count(&Upload{})
+}
+
+// CountUploadsWithThumbnailContentHash returns the number of uploads whose thumbnails have the given content hash
+func CountUploadsWithThumbnailContentHash(db *pop.Connection, contentHash string) (int, error) {
+	return db.Where("thumbnail_content_hash = ?", contentHash).Count(&Upload{})
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -298,6 +299,20 @@ func (v *OptionalRegexMatch) IsValid(errors *validate.Errors) {
 	regexMatch.IsValid(errors)
 }
 
+// URLIsHTTPS validates that a field is an absolute https URL
+type URLIsHTTPS struct {
+	Name  string
+	Field string
+}
+
+// IsValid adds an error if the field can't be parsed as a URL or isn't https with a host
+func (v *URLIsHTTPS) IsValid(errors *validate.Errors) {
+	u, err := url.Parse(v.Field)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		errors.Add(validators.GenerateKey(v.Name), fmt.Sprintf("%s must be an https URL, is %q", v.Name, v.Field))
+	}
+}
+
 // ValidateableModel is here simply because `validateable` is private to `pop`
 type ValidateableModel interface {
 	Validate(*pop.Connection) (*validate.Errors, error)
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// VersionCheck gives a model optimistic concurrency control. Models with a version column embed it and call
+// bumpVersion from BeforeUpdate, so every update increments the version, and an update that expects a version
+// fails with ErrStaleVersion if someone else has updated the record since.
+type VersionCheck struct {
+	expectedVersions []int
+}
+
+// ExpectVersions makes the next update of the record fail with ErrStaleVersion unless the record is still at one
+// of versions. Nil versions expect nothing, so the update succeeds whatever the record's version.
+func (v *VersionCheck) ExpectVersions(versions []int) {
+	v.expectedVersions = versions
+}
+
+// bumpVersion increments the version of the row with id in table, checking it first if a version is expected,
+// and sets version to the new version so that the update that follows writes it back unchanged
+func (v *VersionCheck) bumpVersion(tx *pop.Connection, table string, id uuid.UUID, version *int) error {
+	// #nosec table is a constant from the calling model, not user input
+	query := fmt.Sprintf("UPDATE %s SET version = version + 1 WHERE id = $1", table)
+	args := []interface{}{id}
+	if v.expectedVersions != nil {
+		placeholders := make([]string, len(v.expectedVersions))
+		for i, expected := range v.expectedVersions {
+			args = append(args, expected)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		query += fmt.Sprintf(" AND version IN (%s)", strings.Join(placeholders, ", "))
+	}
+	query += " RETURNING version"
+
+	var newVersion int
+	err := tx.RawQuery(query, args...).First(&newVersion)
+	if err != nil {
+		if errors.Cause(err).Error() != recordNotFoundErrorString {
+			return errors.Wrapf(err, "Could not update version of %s %s", table, id)
+		}
+		if v.expectedVersions != nil {
+			return errors.Wrapf(ErrStaleVersion, "%s %s is no longer at version %v", table, id, v.expectedVersions)
+		}
+		// There is no row to update, so there is no version to bump
+		return nil
+	}
+	v.expectedVersions = nil
+	*version = newVersion
+	return nil
+}
@@ -0,0 +1,43 @@
+package models_test
+
+import (
+	"github.com/pkg/errors"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestVersionCheck() {
+	move := testdatagen.MakeDefaultMove(suite.DB())
+	suite.Equal(0, move.Version)
+
+	// Every update bumps the version, whether or not a version is expected
+	suite.MustSave(&move)
+	suite.Equal(1, move.Version)
+
+	move.ExpectVersions([]int{1})
+	suite.MustSave(&move)
+	suite.Equal(2, move.Version)
+
+	// Someone else updates the move after we fetched it
+	var stale Move
+	suite.NoError(suite.DB().Find(&stale, move.ID))
+	suite.MustSave(&move)
+
+	locator := "STALE1"
+	stale.Locator = locator
+	stale.ExpectVersions([]int{2})
+	verrs, err := suite.DB().ValidateAndUpdate(&stale)
+	suite.False(verrs.HasAny())
+	suite.Equal(ErrStaleVersion, errors.Cause(err))
+
+	// Any of several versions can be expected
+	move.ExpectVersions([]int{1, 3})
+	suite.MustSave(&move)
+	suite.Equal(4, move.Version)
+
+	var current Move
+	suite.NoError(suite.DB().Find(&current, move.ID))
+	suite.Equal(4, current.Version)
+	suite.NotEqual(locator, current.Locator)
+}
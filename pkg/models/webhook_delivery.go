@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// WebhookDeliveryStatus represents the delivery status of an event to a single webhook subscription
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPENDING is an event waiting to be delivered, or to be retried
+	WebhookDeliveryStatusPENDING WebhookDeliveryStatus = "PENDING"
+	// WebhookDeliveryStatusDELIVERED is an event the subscriber's endpoint accepted with a 2xx response
+	WebhookDeliveryStatusDELIVERED WebhookDeliveryStatus = "DELIVERED"
+	// WebhookDeliveryStatusDEADLETTER is an event that failed too many times and won't be retried
+	WebhookDeliveryStatusDEADLETTER WebhookDeliveryStatus = "DEAD_LETTER"
+)
+
+var webhookDeliveryStatuses = []string{
+	string(WebhookDeliveryStatusPENDING),
+	string(WebhookDeliveryStatusDELIVERED),
+	string(WebhookDeliveryStatusDEADLETTER),
+}
+
+// WebhookDelivery is an event to be sent to a single webhook subscription. It is written when the event happens
+// and sent later by the webhook worker, which records the outcome of each attempt on it, so the deliveries of a
+// subscription are its delivery log.
+type WebhookDelivery struct {
+	ID                    uuid.UUID             `json:"id" db:"id"`
+	CreatedAt             time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time             `json:"updated_at" db:"updated_at"`
+	WebhookSubscriptionID uuid.UUID             `json:"webhook_subscription_id" db:"webhook_subscription_id"`
+	WebhookSubscription   WebhookSubscription   `belongs_to:"webhook_subscription"`
+	EventID               uuid.UUID             `json:"event_id" db:"event_id"`
+	EventType             WebhookEventType      `json:"event_type" db:"event_type"`
+	ShipmentID            *uuid.UUID            `json:"shipment_id" db:"shipment_id"`
+	Payload               string                `json:"payload" db:"payload"`
+	Status                WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts              int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt         time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	LastResponseStatus    *int                  `json:"last_response_status" db:"last_response_status"`
+	LastError             *string               `json:"last_error" db:"last_error"`
+	DeliveredAt           *time.Time            `json:"delivered_at" db:"delivered_at"`
+}
+
+// WebhookDeliveries is a slice of WebhookDelivery objects
+type WebhookDeliveries []WebhookDelivery
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (w *WebhookDelivery) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: w.WebhookSubscriptionID, Name: "WebhookSubscriptionID"},
+		&validators.UUIDIsPresent{Field: w.EventID, Name: "EventID"},
+		&validators.StringInclusion{Field: string(w.EventType), Name: "EventType", List: WebhookEventTypes},
+		&validators.StringIsPresent{Field: w.Payload, Name: "Payload"},
+		&validators.StringInclusion{Field: string(w.Status), Name: "Status", List: webhookDeliveryStatuses},
+		&validators.IntIsGreaterThan{Field: w.Attempts, Name: "Attempts", Compared: -1},
+		&validators.TimeIsPresent{Field: w.NextAttemptAt, Name: "NextAttemptAt"},
+	), nil
+}
+
+// ClaimDueWebhookDeliveries claims and returns up to limit pending deliveries that are due to be attempted, with
+// their subscriptions. Claiming moves their next attempt to leaseUntil in a single statement, so no locks are held
+// while they're sent and other workers won't attempt them again unless the claimant fails to record an outcome
+// before then.
+func ClaimDueWebhookDeliveries(db *pop.Connection, now time.Time, leaseUntil time.Time, limit int) (WebhookDeliveries, error) {
+	var deliveries WebhookDeliveries
+	err := db.RawQuery(`
+		UPDATE webhook_deliveries SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $2 AND next_attempt_at <= $3
+			ORDER BY next_attempt_at ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		leaseUntil, WebhookDeliveryStatusPENDING, now, limit).All(&deliveries)
+	if err != nil {
+		return deliveries, errors.Wrap(err, "Claim webhook deliveries query failed")
+	}
+	for i := range deliveries {
+		if err := db.Load(&deliveries[i], "WebhookSubscription"); err != nil {
+			return deliveries, errors.Wrap(err, "Could not load webhook subscription")
+		}
+	}
+	return deliveries, nil
+}
+
+// FetchWebhookDeliveriesForSubscription returns the most recent limit deliveries to a webhook subscription, newest
+// first
+func FetchWebhookDeliveriesForSubscription(db *pop.Connection, subscriptionID uuid.UUID, limit int) (WebhookDeliveries, error) {
+	var deliveries WebhookDeliveries
+	err := db.Where("webhook_subscription_id = ?", subscriptionID).Order("created_at DESC").Limit(limit).All(&deliveries)
+	if err != nil {
+		return deliveries, errors.Wrap(err, "Fetch webhook deliveries query failed")
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/pop/slices"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// WebhookEventType is a shipment lifecycle event that TSPs can subscribe to
+type WebhookEventType string
+
+const (
+	// WebhookEventTypeSHIPMENTAWARDED is a shipment offered to the TSP by the award queue
+	WebhookEventTypeSHIPMENTAWARDED WebhookEventType = "SHIPMENT_AWARDED"
+	// WebhookEventTypeSHIPMENTAPPROVED is a shipment approved by the transportation office
+	WebhookEventTypeSHIPMENTAPPROVED WebhookEventType = "SHIPMENT_APPROVED"
+	// WebhookEventTypeSTORAGEINTRANSITAPPROVED is a SIT request approved by the transportation office
+	WebhookEventTypeSTORAGEINTRANSITAPPROVED WebhookEventType = "STORAGE_IN_TRANSIT_APPROVED"
+	// WebhookEventTypeSTORAGEINTRANSITDENIED is a SIT request denied by the transportation office
+	WebhookEventTypeSTORAGEINTRANSITDENIED WebhookEventType = "STORAGE_IN_TRANSIT_DENIED"
+	// WebhookEventTypePREAPPROVALREQUESTAPPROVED is a pre-approval request approved, or conditionally approved,
+	// by the transportation office
+	WebhookEventTypePREAPPROVALREQUESTAPPROVED WebhookEventType = "PRE_APPROVAL_REQUEST_APPROVED"
+)
+
+// WebhookEventTypes are every event type that can be subscribed to
+var WebhookEventTypes = []string{
+	string(WebhookEventTypeSHIPMENTAWARDED),
+	string(WebhookEventTypeSHIPMENTAPPROVED),
+	string(WebhookEventTypeSTORAGEINTRANSITAPPROVED),
+	string(WebhookEventTypeSTORAGEINTRANSITDENIED),
+	string(WebhookEventTypePREAPPROVALREQUESTAPPROVED),
+}
+
+// WebhookSubscription is an https endpoint a TSP has registered to be sent the events of its shipments. Each event
+// is signed with the subscription's secret so the TSP can check that it came from us.
+type WebhookSubscription struct {
+	ID                              uuid.UUID     `json:"id" db:"id"`
+	CreatedAt                       time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time     `json:"updated_at" db:"updated_at"`
+	TransportationServiceProviderID uuid.UUID     `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	URL                             string        `json:"url" db:"url"`
+	EventTypes                      slices.String `json:"event_types" db:"event_types"`
+	Secret                          string        `json:"-" db:"secret"`
+	Active                          bool          `json:"active" db:"active"`
+}
+
+// WebhookSubscriptions is a slice of WebhookSubscription objects
+type WebhookSubscriptions []WebhookSubscription
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (w *WebhookSubscription) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	vs := []validate.Validator{
+		&validators.UUIDIsPresent{Field: w.TransportationServiceProviderID, Name: "TransportationServiceProviderID"},
+		&URLIsHTTPS{Field: w.URL, Name: "URL"},
+		&validators.IntIsGreaterThan{Field: len(w.EventTypes), Name: "EventTypes", Compared: 0},
+		&validators.StringIsPresent{Field: w.Secret, Name: "Secret"},
+	}
+	for _, eventType := range w.EventTypes {
+		vs = append(vs, &validators.StringInclusion{Field: eventType, Name: "EventTypes", List: WebhookEventTypes})
+	}
+	return validate.Validate(vs...), nil
+}
+
+// FetchWebhookSubscriptionsForTSP returns every webhook subscription of a TSP, oldest first
+func FetchWebhookSubscriptionsForTSP(db *pop.Connection, tspID uuid.UUID) (WebhookSubscriptions, error) {
+	var subscriptions WebhookSubscriptions
+	err := db.Where("transportation_service_provider_id = ?", tspID).Order("created_at ASC").All(&subscriptions)
+	if err != nil {
+		return subscriptions, errors.Wrap(err, "Fetch webhook subscriptions query failed")
+	}
+	return subscriptions, nil
+}
+
+// FetchWebhookSubscriptionForTSP returns a webhook subscription, as long as it belongs to the TSP
+func FetchWebhookSubscriptionForTSP(db *pop.Connection, tspID uuid.UUID, id uuid.UUID) (*WebhookSubscription, error) {
+	var subscription WebhookSubscription
+	err := db.Find(&subscription, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	if subscription.TransportationServiceProviderID != tspID {
+		return nil, ErrFetchForbidden
+	}
+	return &subscription, nil
+}
+
+// FetchActiveWebhookSubscriptionsForEvent returns the active subscriptions of a TSP to an event type
+func FetchActiveWebhookSubscriptionsForEvent(db *pop.Connection, tspID uuid.UUID, eventType WebhookEventType) (WebhookSubscriptions, error) {
+	var subscriptions WebhookSubscriptions
+	err := db.Where("transportation_service_provider_id = ? AND active AND ? = ANY(event_types)", tspID, string(eventType)).
+		All(&subscriptions)
+	if err != nil {
+		return subscriptions, errors.Wrap(err, "Fetch webhook subscriptions query failed")
+	}
+	return subscriptions, nil
+}
@@ -0,0 +1,77 @@
+package models_test
+
+import (
+	"github.com/gobuffalo/pop/slices"
+	"github.com/gofrs/uuid"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) Test_WebhookSubscriptionValidations() {
+	subscription := &WebhookSubscription{URL: "http://tsp.example.com/events"}
+
+	var expErrors = map[string][]string{
+		"transportation_service_provider_id": {"TransportationServiceProviderID can not be blank."},
+		"url":                                {`URL must be an https URL, is "http://tsp.example.com/events"`},
+		"event_types":                        {"0 is not greater than 0."},
+		"secret":                             {"Secret can not be blank."},
+	}
+
+	suite.verifyValidationErrors(subscription, expErrors)
+
+	subscription = &WebhookSubscription{
+		TransportationServiceProviderID: testdatagen.MakeDefaultTSP(suite.DB()).ID,
+		URL:                             "https://tsp.example.com/events",
+		EventTypes:                      slices.String{string(WebhookEventTypeSHIPMENTAWARDED), "SHIPMENT_LOST"},
+		Secret:                          "secret",
+	}
+
+	expErrors = map[string][]string{
+		"event_types": {"EventTypes is not in the list [SHIPMENT_AWARDED, SHIPMENT_APPROVED, STORAGE_IN_TRANSIT_APPROVED, STORAGE_IN_TRANSIT_DENIED, PRE_APPROVAL_REQUEST_APPROVED]."},
+	}
+
+	suite.verifyValidationErrors(subscription, expErrors)
+}
+
+func (suite *ModelSuite) Test_FetchWebhookSubscriptions() {
+	subscription := testdatagen.MakeDefaultWebhookSubscription(suite.DB())
+	tspID := subscription.TransportationServiceProviderID
+	approvedOnly := testdatagen.MakeWebhookSubscription(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: WebhookSubscription{
+			TransportationServiceProviderID: tspID,
+			EventTypes:                      slices.String{string(WebhookEventTypeSHIPMENTAPPROVED)},
+		},
+	})
+	inactive := testdatagen.MakeWebhookSubscription(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: WebhookSubscription{TransportationServiceProviderID: tspID},
+	})
+	inactive.Active = false
+	suite.MustSave(&inactive)
+	otherTSP := testdatagen.MakeDefaultWebhookSubscription(suite.DB())
+
+	subscriptions, err := FetchWebhookSubscriptionsForTSP(suite.DB(), tspID)
+	suite.NoError(err)
+	suite.Len(subscriptions, 3)
+
+	// Only the TSP's active subscriptions to the event are sent it
+	subscriptions, err = FetchActiveWebhookSubscriptionsForEvent(suite.DB(), tspID, WebhookEventTypeSHIPMENTAWARDED)
+	suite.NoError(err)
+	if suite.Len(subscriptions, 1) {
+		suite.Equal(subscription.ID, subscriptions[0].ID)
+	}
+	subscriptions, err = FetchActiveWebhookSubscriptionsForEvent(suite.DB(), tspID, WebhookEventTypeSHIPMENTAPPROVED)
+	suite.NoError(err)
+	var ids []uuid.UUID
+	for _, s := range subscriptions {
+		ids = append(ids, s.ID)
+	}
+	suite.ElementsMatch([]uuid.UUID{subscription.ID, approvedOnly.ID}, ids)
+
+	// Other TSPs' subscriptions can't be fetched
+	_, err = FetchWebhookSubscriptionForTSP(suite.DB(), tspID, otherTSP.ID)
+	suite.Equal(ErrFetchForbidden, err)
+	fetched, err := FetchWebhookSubscriptionForTSP(suite.DB(), tspID, subscription.ID)
+	suite.NoError(err)
+	suite.Equal(subscription.URL, fetched.URL)
+}
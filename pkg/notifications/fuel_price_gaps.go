@@ -0,0 +1,44 @@
+package notifications
+
+import (
+	"context"
+	"time"
+)
+
+// FuelPriceGaps has notification content for months missing fuel prices
+type FuelPriceGaps struct {
+	recipientEmail string
+	months         []time.Time
+}
+
+// NewFuelPriceGaps returns a new fuel price gaps notification, sent to recipientEmail
+func NewFuelPriceGaps(recipientEmail string, months []time.Time) *FuelPriceGaps {
+	return &FuelPriceGaps{
+		recipientEmail: recipientEmail,
+		months:         months,
+	}
+}
+
+type fuelPriceGapsEmailData struct {
+	Months []string
+}
+
+func (m FuelPriceGaps) notificationType() NotificationType {
+	return FuelPriceGapsNotification
+}
+
+func (m FuelPriceGaps) emails(ctx context.Context) ([]emailContent, error) {
+	var emails []emailContent
+
+	data := fuelPriceGapsEmailData{}
+	for _, month := range m.months {
+		data.Months = append(data.Months, month.Format("January 2006"))
+	}
+
+	email, err := renderEmail(m.notificationType(), m.recipientEmail, data)
+	if err != nil {
+		return emails, err
+	}
+
+	return append(emails, email), nil
+}
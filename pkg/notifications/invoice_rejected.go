@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// InvoiceRejected has notification content for invoices rejected by GEX
+type InvoiceRejected struct {
+	db        *pop.Connection
+	logger    Logger
+	invoiceID uuid.UUID
+}
+
+// NewInvoiceRejected returns a new invoice rejected notification, sent to the office user who approved the invoice
+func NewInvoiceRejected(db *pop.Connection, logger Logger, invoiceID uuid.UUID) *InvoiceRejected {
+	return &InvoiceRejected{
+		db:        db,
+		logger:    logger,
+		invoiceID: invoiceID,
+	}
+}
+
+type invoiceRejectedEmailData struct {
+	InvoiceNumber    string
+	GBLNumber        string
+	RejectionReasons []string
+}
+
+func (m InvoiceRejected) notificationType() NotificationType {
+	return InvoiceRejectedNotification
+}
+
+func (m InvoiceRejected) emails(ctx context.Context) ([]emailContent, error) {
+	var emails []emailContent
+
+	var invoice models.Invoice
+	if err := m.db.Eager("Approver", "Shipment").Find(&invoice, m.invoiceID); err != nil {
+		return emails, err
+	}
+
+	data := invoiceRejectedEmailData{InvoiceNumber: invoice.InvoiceNumber}
+	if invoice.Shipment.GBLNumber != nil {
+		data.GBLNumber = *invoice.Shipment.GBLNumber
+	}
+	// Reasons are stored joined together when the 997 acknowledgment is processed
+	if invoice.RejectionReasons != nil && *invoice.RejectionReasons != "" {
+		data.RejectionReasons = strings.Split(*invoice.RejectionReasons, "; ")
+	}
+
+	email, err := renderEmail(m.notificationType(), invoice.Approver.Email, data)
+	if err != nil {
+		return emails, err
+	}
+
+	return append(emails, email), nil
+}
@@ -37,6 +37,19 @@ func NewMoveApproved(db *pop.Connection,
 	}
 }
 
+type moveApprovedEmailData struct {
+	HasPPM          bool
+	PPMInfoSheetURL string
+}
+
+func (m MoveApproved) notificationType() NotificationType {
+	return MoveApprovedNotification
+}
+
+func (m MoveApproved) notifiedMoveID() uuid.UUID {
+	return m.moveID
+}
+
 func (m MoveApproved) emails(ctx context.Context) ([]emailContent, error) {
 	var emails []emailContent
 
@@ -59,37 +72,21 @@ func (m MoveApproved) emails(ctx context.Context) ([]emailContent, error) {
 		return emails, fmt.Errorf("no email found for service member")
 	}
 
-	// Set up various text segments. Copy comes from here:
+	// Copy comes from here:
 	// https://docs.google.com/document/d/1bgE0Q_-_c93uruMP8dcNSHugXo8Pidz6YFojWBKn1Gg/edit#heading=h.h3ys1ur2qhpn
-	// TODO: we will want some sort of templating system
 	ppmInfoSheetURL := url.URL{
 		Scheme: "https",
 		Host:   m.host,
 		Path:   "downloads/ppm_info_sheet.pdf",
 	}
 
-	introText := `Your move has been approved and you are ready to move!`
-	if move.PersonallyProcuredMoves != nil {
-		introText = fmt.Sprintf("%s %s %s",
-			introText,
-			`Please review the PPM info sheet for more detailed instructions: `,
-			ppmInfoSheetURL.String())
-	}
-	nextStepsText := `Next steps:`
-
-	ppmText := ""
-	if move.PersonallyProcuredMoves != nil {
-		ppmText = `For your “Do-it-Yourself” shipment, you can begin your move whenever you are ready. Be sure to save your weight tickets and any receipts associated with your move for when you request payment later on in the process.`
-	}
-
 	// TODO: Add the PPPO contact info
-	closingText := `If you have any questions, contact your origin PPPO.`
-
-	smEmail := emailContent{
-		recipientEmail: *serviceMember.PersonalEmail,
-		subject:        "MOVE.MIL: Your move has been approved.",
-		htmlBody:       fmt.Sprintf("%s<br/>%s<br/>%s<br/>%s", introText, nextStepsText, ppmText, closingText),
-		textBody:       fmt.Sprintf("%s\n%s\n%s\n%s", introText, nextStepsText, ppmText, closingText),
+	smEmail, err := renderEmail(m.notificationType(), *serviceMember.PersonalEmail, moveApprovedEmailData{
+		HasPPM:          move.PersonallyProcuredMoves != nil,
+		PPMInfoSheetURL: ppmInfoSheetURL.String(),
+	})
+	if err != nil {
+		return emails, err
 	}
 
 	// TODO: Send email to trusted contacts when that's supported
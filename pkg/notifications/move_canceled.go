@@ -11,7 +11,7 @@ import (
 	"github.com/transcom/mymove/pkg/models"
 )
 
-// MoveCanceled has notification content for approved moves
+// MoveCanceled has notification content for canceled moves
 type MoveCanceled struct {
 	db      *pop.Connection
 	logger  Logger
@@ -19,7 +19,7 @@ type MoveCanceled struct {
 	session *auth.Session // TODO - remove this when we move permissions up to handlers and out of models
 }
 
-// NewMoveCanceled returns a new move approval notification
+// NewMoveCanceled returns a new move canceled notification
 func NewMoveCanceled(db *pop.Connection, logger Logger, session *auth.Session, moveID uuid.UUID) *MoveCanceled {
 
 	return &MoveCanceled{
@@ -30,6 +30,20 @@ func NewMoveCanceled(db *pop.Connection, logger Logger, session *auth.Session, m
 	}
 }
 
+type moveCanceledEmailData struct {
+	OriginDutyStation      string
+	DestinationDutyStation string
+	OriginPhoneLine        string
+}
+
+func (m MoveCanceled) notificationType() NotificationType {
+	return MoveCanceledNotification
+}
+
+func (m MoveCanceled) notifiedMoveID() uuid.UUID {
+	return m.moveID
+}
+
 func (m MoveCanceled) emails(ctx context.Context) ([]emailContent, error) {
 	var emails []emailContent
 
@@ -61,21 +75,15 @@ func (m MoveCanceled) emails(ctx context.Context) ([]emailContent, error) {
 		return emails, fmt.Errorf("missing new duty station for service member")
 	}
 
-	// Set up various text segments. Copy comes from here:
+	// Copy comes from here:
 	// https://docs.google.com/document/d/1gIQZprWzJJE_sAAyg5NViPwy9ckL5RK37gFq1fEfipU
-	// TODO: we will want some sort of templating system
-
-	introText := `Update on your move`
-	nextSteps := fmt.Sprintf("Upon review, the office has determined that MilMove can’t handle your move from %s to %s, and canceled it in the system. You’re still moving, but we’ll need to manage your move with a different system.",
-		dsTransportInfo.Name, orders.NewDutyStation.Name)
-	closingText := fmt.Sprintf("Please call the PPPO at %s at %s and they’ll help you figure out what to do next.",
-		dsTransportInfo.Name, dsTransportInfo.PhoneLine)
-
-	smEmail := emailContent{
-		recipientEmail: *serviceMember.PersonalEmail,
-		subject:        fmt.Sprintf("[MilMove] %s", introText),
-		htmlBody:       fmt.Sprintf("%s<br/><br/><br/>%s", nextSteps, closingText),
-		textBody:       fmt.Sprintf("%s\n\n\n%s", nextSteps, closingText),
+	smEmail, err := renderEmail(m.notificationType(), *serviceMember.PersonalEmail, moveCanceledEmailData{
+		OriginDutyStation:      dsTransportInfo.Name,
+		DestinationDutyStation: orders.NewDutyStation.Name,
+		OriginPhoneLine:        dsTransportInfo.PhoneLine,
+	})
+	if err != nil {
+		return emails, err
 	}
 
 	// TODO: Send email to trusted contacts when that's supported
@@ -31,6 +31,20 @@ func NewMoveSubmitted(db *pop.Connection, logger Logger, session *auth.Session,
 	}
 }
 
+type moveSubmittedEmailData struct {
+	OriginDutyStation      string
+	DestinationDutyStation string
+	OriginPhoneLine        string
+}
+
+func (m MoveSubmitted) notificationType() NotificationType {
+	return MoveSubmittedNotification
+}
+
+func (m MoveSubmitted) notifiedMoveID() uuid.UUID {
+	return m.moveID
+}
+
 func (m MoveSubmitted) emails(ctx context.Context) ([]emailContent, error) {
 	var emails []emailContent
 
@@ -53,10 +67,8 @@ func (m MoveSubmitted) emails(ctx context.Context) ([]emailContent, error) {
 		return emails, fmt.Errorf("no email found for service member")
 	}
 
-	submittedText := "Your move has been submitted to your local transportation office for review. "
-	processText := "This can take up to 3 business days. The office will email you once your move has been approved."
-	pppoText := "If you have questions or need expedited processing contact your local transportation office."
-	closingText := "You can check the status of your move at any time at https://my.move.mil/"
+	// Without an origin duty station the email falls back to generic copy
+	var data moveSubmittedEmailData
 	if serviceMember.DutyStationID != nil {
 		originDSTransportInfo, err := models.FetchDSContactInfo(m.db, serviceMember.DutyStationID)
 		if err != nil {
@@ -66,25 +78,16 @@ func (m MoveSubmitted) emails(ctx context.Context) ([]emailContent, error) {
 		if err != nil {
 			return emails, err
 		}
-
-		submittedText = fmt.Sprintf(
-			"Your move from %s to %s has been submitted to your local transportation office for review.",
-			originDSTransportInfo.Name,
-			destinationDutyStation.Name,
-		)
-
-		pppoText = fmt.Sprintf(
-			"In the meantime, if you have questions or need expedited processing, call the %s PPPO at %s.",
-			originDSTransportInfo.Name,
-			originDSTransportInfo.PhoneLine,
-		)
+		data = moveSubmittedEmailData{
+			OriginDutyStation:      originDSTransportInfo.Name,
+			DestinationDutyStation: destinationDutyStation.Name,
+			OriginPhoneLine:        originDSTransportInfo.PhoneLine,
+		}
 	}
 
-	smEmail := emailContent{
-		recipientEmail: *serviceMember.PersonalEmail,
-		subject:        "[MilMove] You’ve submitted your move details",
-		htmlBody:       fmt.Sprintf("%s<br/><br/>%s<br/><br/>%s<br/><br/><br/>%s", submittedText, processText, pppoText, closingText),
-		textBody:       fmt.Sprintf("%s\n\n%s\n\n%s\n\n\n%s", submittedText, processText, pppoText, closingText),
+	smEmail, err := renderEmail(m.notificationType(), *serviceMember.PersonalEmail, data)
+	if err != nil {
+		return emails, err
 	}
 
 	m.logger.Info("Generated move submitted email to service member",
@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+)
+
+// FileNotificationSender writes each email as a raw message file in a directory, standing in for SES in tests
+// and local development
+type FileNotificationSender struct {
+	dir    string
+	domain string
+	logger Logger
+}
+
+// NewFileNotificationSender returns a new FileNotificationSender writing to dir
+func NewFileNotificationSender(dir string, domain string, logger Logger) FileNotificationSender {
+	return FileNotificationSender{
+		dir:    dir,
+		domain: domain,
+		logger: logger,
+	}
+}
+
+// SendNotification writes one .eml file per email
+func (f FileNotificationSender) SendNotification(ctx context.Context, notification notification) error {
+	emails, err := notification.emails(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, email := range emails {
+		rawMessage, err := formatRawEmailMessage(email, f.domain)
+		if err != nil {
+			return err
+		}
+
+		id, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(f.dir, fmt.Sprintf("%s-%s.eml", notification.notificationType(), id))
+		if err := ioutil.WriteFile(path, rawMessage, 0600); err != nil {
+			return err
+		}
+
+		f.logger.Info("Wrote email to file",
+			zap.String("destinations", email.recipientEmail),
+			zap.String("path", path))
+	}
+
+	return nil
+}
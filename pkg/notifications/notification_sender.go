@@ -17,6 +17,7 @@ import (
 )
 
 type notification interface {
+	notificationType() NotificationType
 	emails(ctx context.Context) ([]emailContent, error)
 }
 
@@ -59,7 +60,36 @@ func (n NotificationSendingContext) SendNotification(ctx context.Context, notifi
 	return sendEmails(emails, n.svc, n.domain, n.logger)
 }
 
-// InitEmail initializes the email backend
+// RoutingNotificationSender sends each notification through the senders registered for its type, or through
+// the default sender if none are
+type RoutingNotificationSender struct {
+	defaultSender NotificationSender
+	routes        map[NotificationType][]NotificationSender
+}
+
+// NewRoutingNotificationSender returns a new RoutingNotificationSender
+func NewRoutingNotificationSender(defaultSender NotificationSender, routes map[NotificationType][]NotificationSender) RoutingNotificationSender {
+	return RoutingNotificationSender{
+		defaultSender: defaultSender,
+		routes:        routes,
+	}
+}
+
+// SendNotification sends a notification through every sender routed to its type, stopping at the first failure
+func (r RoutingNotificationSender) SendNotification(ctx context.Context, notification notification) error {
+	senders, ok := r.routes[notification.notificationType()]
+	if !ok {
+		senders = []NotificationSender{r.defaultSender}
+	}
+	for _, sender := range senders {
+		if err := sender.SendNotification(ctx, notification); err != nil {
+			return errors.Wrapf(err, "Failed to send %s notification", notification.notificationType())
+		}
+	}
+	return nil
+}
+
+// InitEmail initializes the email backend used to deliver notifications from the outbox
 func InitEmail(v *viper.Viper, sess *awssession.Session, logger Logger) NotificationSender {
 	if v.GetString(cli.EmailBackendFlag) == "ses" {
 		// Setup Amazon SES (email) service
@@ -75,6 +105,12 @@ func InitEmail(v *viper.Viper, sess *awssession.Session, logger Logger) Notifica
 	}
 
 	domain := "milmovelocal"
+	if v.GetString(cli.EmailBackendFlag) == "file" {
+		dir := v.GetString(cli.EmailFileDirFlag)
+		logger.Info("Using file email backend", zap.String("domain", domain), zap.String("dir", dir))
+		return NewFileNotificationSender(dir, domain, logger)
+	}
+
 	logger.Info("Using local email backend", zap.String("domain", domain))
 	return NewStubNotificationSender(domain, logger)
 }
@@ -4,11 +4,14 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-openapi/swag"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/testdatagen"
 	"github.com/transcom/mymove/pkg/testingsuite"
 )
@@ -22,7 +25,11 @@ type testNotification struct {
 	email emailContent
 }
 
-func (n testNotification) emails() ([]emailContent, error) {
+func (n testNotification) notificationType() NotificationType {
+	return MoveApprovedNotification
+}
+
+func (n testNotification) emails(ctx context.Context) ([]emailContent, error) {
 	return []emailContent{n.email}, nil
 }
 
@@ -89,6 +96,74 @@ func (suite *NotificationSuite) TestMoveSubmitted() {
 	suite.NotEmpty(email.textBody)
 }
 
+func (suite *NotificationSuite) TestShipmentAwarded() {
+	ctx := context.Background()
+
+	tspUser := testdatagen.MakeDefaultTspUser(suite.DB())
+	testdatagen.MakeTspUser(suite.DB(), testdatagen.Assertions{
+		TspUser: models.TspUser{
+			TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+			Email:                           "disabled_tsp@example.com",
+			Disabled:                        true,
+		},
+		User: models.User{LoginGovEmail: "disabled_tsp@example.com"},
+	})
+	shipment := testdatagen.MakeDefaultShipment(suite.DB())
+
+	notification := NewShipmentAwarded(suite.DB(), suite.logger, "tsplocal", shipment.ID, tspUser.TransportationServiceProviderID)
+	emails, err := notification.emails(ctx)
+	suite.NoError(err)
+
+	// Disabled TSP users aren't notified
+	suite.Len(emails, 1)
+	email := emails[0]
+	suite.Equal(tspUser.Email, email.recipientEmail)
+	suite.NotEmpty(email.subject)
+	suite.Contains(email.textBody, "https://tsplocal/shipments/"+shipment.ID.String())
+}
+
+func (suite *NotificationSuite) TestStorageInTransitApproved() {
+	ctx := context.Background()
+
+	storageInTransit := testdatagen.MakeStorageInTransit(suite.DB(), testdatagen.Assertions{
+		StorageInTransit: models.StorageInTransit{
+			Status:             models.StorageInTransitStatusAPPROVED,
+			AuthorizationNotes: swag.String("Approved for 30 days"),
+		},
+	})
+
+	notification := NewStorageInTransitApproved(suite.DB(), suite.logger, storageInTransit.ID)
+	emails, err := notification.emails(ctx)
+	suite.NoError(err)
+
+	suite.Len(emails, 1)
+	email := emails[0]
+	suite.Equal(*storageInTransit.Shipment.ServiceMember.PersonalEmail, email.recipientEmail)
+	suite.Contains(email.textBody, storageInTransit.WarehouseName)
+	suite.Contains(email.textBody, "Approved for 30 days")
+}
+
+func (suite *NotificationSuite) TestInvoiceRejected() {
+	ctx := context.Background()
+
+	invoice := testdatagen.MakeInvoice(suite.DB(), testdatagen.Assertions{
+		Invoice: models.Invoice{
+			Status:           models.InvoiceStatusSUBMISSIONFAILURE,
+			RejectionReasons: swag.String("Transaction set 0001: One or More Segments in Error; Missing ISA"),
+		},
+	})
+
+	notification := NewInvoiceRejected(suite.DB(), suite.logger, invoice.ID)
+	emails, err := notification.emails(ctx)
+	suite.NoError(err)
+
+	suite.Len(emails, 1)
+	email := emails[0]
+	suite.Equal(invoice.Approver.Email, email.recipientEmail)
+	suite.Contains(email.subject, invoice.InvoiceNumber)
+	suite.Contains(email.textBody, "- Transaction set 0001: One or More Segments in Error\n- Missing ISA")
+}
+
 func (suite *NotificationSuite) getTestEmailContent() emailContent {
 	return emailContent{
 		recipientEmail: "lucky@winner.com",
@@ -107,3 +182,20 @@ func TestNotificationSuite(t *testing.T) {
 	}
 	suite.Run(t, s)
 }
+
+func (suite *NotificationSuite) TestFuelPriceGaps() {
+	ctx := context.Background()
+
+	notification := NewFuelPriceGaps("fuel@example.com", []time.Time{
+		time.Date(2019, time.February, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC),
+	})
+	emails, err := notification.emails(ctx)
+	suite.NoError(err)
+
+	suite.Len(emails, 1)
+	email := emails[0]
+	suite.Equal("fuel@example.com", email.recipientEmail)
+	suite.Equal("[MilMove] Fuel prices are missing for 2 month(s)", email.subject)
+	suite.Contains(email.textBody, "- February 2019\n- April 2019")
+}
@@ -0,0 +1,120 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// moveNotification is implemented by notifications about a move, so their deliveries can be shown with the move
+type moveNotification interface {
+	notifiedMoveID() uuid.UUID
+}
+
+// EnqueueNotification renders a notification and writes one pending delivery per recipient to the outbox. Pass
+// the transaction that saves the change being notified about, so the notification is only sent if it commits.
+func EnqueueNotification(ctx context.Context, tx *pop.Connection, notification notification) error {
+	emails, err := notification.emails(ctx)
+	if err != nil {
+		return err
+	}
+
+	var moveID *uuid.UUID
+	if n, ok := notification.(moveNotification); ok {
+		id := n.notifiedMoveID()
+		moveID = &id
+	}
+
+	now := time.Now()
+	for _, email := range emails {
+		delivery := models.NotificationDelivery{
+			NotificationType: string(notification.notificationType()),
+			MoveID:           moveID,
+			RecipientEmail:   email.recipientEmail,
+			Subject:          email.subject,
+			HTMLBody:         email.htmlBody,
+			TextBody:         email.textBody,
+			Attachments:      email.attachments,
+			Status:           models.NotificationDeliveryStatusPENDING,
+			NextAttemptAt:    now,
+		}
+		verrs, err := tx.ValidateAndCreate(&delivery)
+		if err != nil {
+			return errors.Wrap(err, "Failed to enqueue notification")
+		}
+		if verrs.HasAny() {
+			return errors.Errorf("Invalid %s notification delivery: %s", notification.notificationType(), verrs.String())
+		}
+	}
+	return nil
+}
+
+// NotificationOutbox is a NotificationSender that writes notifications to the outbox instead of sending them
+type NotificationOutbox struct {
+	db *pop.Connection
+}
+
+// NewNotificationOutbox returns a new NotificationOutbox
+func NewNotificationOutbox(db *pop.Connection) NotificationOutbox {
+	return NotificationOutbox{db: db}
+}
+
+// SendNotification queues a notification to be sent by the outbox worker
+func (o NotificationOutbox) SendNotification(ctx context.Context, notification notification) error {
+	return EnqueueNotification(ctx, o.db, notification)
+}
+
+// storedNotification is a notification rendered earlier and read back from the outbox
+type storedNotification struct {
+	delivery models.NotificationDelivery
+}
+
+func (s storedNotification) notificationType() NotificationType {
+	return NotificationType(s.delivery.NotificationType)
+}
+
+func (s storedNotification) emails(ctx context.Context) ([]emailContent, error) {
+	return []emailContent{{
+		attachments:    s.delivery.Attachments,
+		recipientEmail: s.delivery.RecipientEmail,
+		subject:        s.delivery.Subject,
+		htmlBody:       s.delivery.HTMLBody,
+		textBody:       s.delivery.TextBody,
+	}}, nil
+}
+
+// RetryPolicy controls how failed deliveries are retried
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts after which a delivery is dead-lettered
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failure, doubled after each further failure
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries for about a day before giving up on a delivery
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     6 * time.Hour,
+	}
+}
+
+// backoff returns how long to wait after a delivery has failed attempts times
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return wait
+}
@@ -0,0 +1,172 @@
+package notifications
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *NotificationSuite) TestEnqueueNotification() {
+	ctx := context.Background()
+	move := testdatagen.MakeDefaultMove(suite.DB())
+	notification := NewMoveSubmitted(suite.DB(), suite.logger, &auth.Session{
+		ServiceMemberID: move.Orders.ServiceMember.ID,
+		ApplicationName: auth.MilApp,
+	}, move.ID)
+
+	err := NewNotificationOutbox(suite.DB()).SendNotification(ctx, notification)
+	suite.NoError(err)
+
+	deliveries, err := models.FetchNotificationDeliveriesForMove(suite.DB(), move.ID)
+	suite.NoError(err)
+	if suite.Len(deliveries, 1) {
+		delivery := deliveries[0]
+		suite.Equal(string(MoveSubmittedNotification), delivery.NotificationType)
+		suite.Equal(*move.Orders.ServiceMember.PersonalEmail, delivery.RecipientEmail)
+		suite.Equal(models.NotificationDeliveryStatusPENDING, delivery.Status)
+		suite.Equal(0, delivery.Attempts)
+		suite.NotEmpty(delivery.TextBody)
+	}
+}
+
+func (suite *NotificationSuite) TestOutboxWorkerDelivers() {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "notifications")
+	suite.NoError(err)
+	defer os.RemoveAll(dir)
+
+	mockClock := clock.NewMock()
+	delivery := testdatagen.MakeNotificationDelivery(suite.DB(), testdatagen.Assertions{
+		NotificationDelivery: models.NotificationDelivery{NextAttemptAt: mockClock.Now()},
+	})
+	worker := NewOutboxWorker(suite.DB(), suite.logger, NewFileNotificationSender(dir, "milmovelocal", suite.logger), mockClock, DefaultRetryPolicy())
+
+	attempted, err := worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(1, attempted)
+
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(models.NotificationDeliveryStatusSENT, delivery.Status)
+	suite.Equal(1, delivery.Attempts)
+	suite.NotNil(delivery.SentAt)
+
+	files, err := filepath.Glob(filepath.Join(dir, "move_submitted-*.eml"))
+	suite.NoError(err)
+	if suite.Len(files, 1) {
+		message, err := ioutil.ReadFile(files[0])
+		suite.NoError(err)
+		suite.Contains(string(message), "To: "+delivery.RecipientEmail)
+	}
+
+	// Sent deliveries aren't sent again
+	attempted, err = worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(0, attempted)
+}
+
+func (suite *NotificationSuite) TestOutboxWorkerRetries() {
+	ctx := context.Background()
+	mockClock := clock.NewMock()
+	delivery := testdatagen.MakeNotificationDelivery(suite.DB(), testdatagen.Assertions{
+		NotificationDelivery: models.NotificationDelivery{NextAttemptAt: mockClock.Now()},
+	})
+	retryPolicy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Hour}
+	sender := &recordingNotificationSender{err: errors.New("SES is down")}
+	worker := NewOutboxWorker(suite.DB(), suite.logger, sender, mockClock, retryPolicy)
+
+	// The first failure waits the initial backoff
+	_, err := worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(models.NotificationDeliveryStatusPENDING, delivery.Status)
+	suite.Equal(1, delivery.Attempts)
+	suite.Equal("SES is down", *delivery.LastError)
+	suite.WithinDuration(mockClock.Now().Add(time.Minute), delivery.NextAttemptAt, time.Second)
+
+	// Not retried before it's due
+	attempted, err := worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(0, attempted)
+
+	// The second failure waits twice as long
+	mockClock.Add(time.Minute)
+	_, err = worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(2, delivery.Attempts)
+	suite.WithinDuration(mockClock.Now().Add(2*time.Minute), delivery.NextAttemptAt, time.Second)
+
+	// The last attempt dead-letters it
+	mockClock.Add(2 * time.Minute)
+	_, err = worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(models.NotificationDeliveryStatusDEADLETTER, delivery.Status)
+	suite.Equal(3, delivery.Attempts)
+	suite.Len(sender.sent, 3)
+
+	mockClock.Add(time.Hour)
+	attempted, err = worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(0, attempted)
+}
+
+func (suite *NotificationSuite) TestOutboxWorkerLeavesClaimedDeliveries() {
+	ctx := context.Background()
+	mockClock := clock.NewMock()
+	delivery := testdatagen.MakeNotificationDelivery(suite.DB(), testdatagen.Assertions{
+		NotificationDelivery: models.NotificationDelivery{NextAttemptAt: mockClock.Now()},
+	})
+
+	// Another worker claims the delivery
+	claimed, err := models.ClaimDueNotificationDeliveries(suite.DB(), mockClock.Now(), mockClock.Now().Add(outboxLease), outboxBatchSize)
+	suite.NoError(err)
+	if suite.Len(claimed, 1) {
+		suite.Equal(delivery.ID, claimed[0].ID)
+	}
+
+	sender := &recordingNotificationSender{}
+	worker := NewOutboxWorker(suite.DB(), suite.logger, sender, mockClock, DefaultRetryPolicy())
+	attempted, err := worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(0, attempted)
+	suite.Len(sender.sent, 0)
+
+	// It's picked up again once the claim expires without an outcome being saved
+	mockClock.Add(outboxLease)
+	attempted, err = worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(1, attempted)
+	suite.Len(sender.sent, 1)
+
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(models.NotificationDeliveryStatusSENT, delivery.Status)
+}
+
+func (suite *NotificationSuite) TestRetryPolicyBackoff() {
+	retryPolicy := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Minute, MaxBackoff: 10 * time.Minute}
+	suite.Equal(time.Minute, retryPolicy.backoff(1))
+	suite.Equal(2*time.Minute, retryPolicy.backoff(2))
+	suite.Equal(8*time.Minute, retryPolicy.backoff(4))
+	suite.Equal(10*time.Minute, retryPolicy.backoff(5))
+	suite.Equal(10*time.Minute, retryPolicy.backoff(9))
+}
+
+type recordingNotificationSender struct {
+	sent []NotificationType
+	err  error
+}
+
+func (r *recordingNotificationSender) SendNotification(ctx context.Context, notification notification) error {
+	r.sent = append(r.sent, notification.notificationType())
+	return r.err
+}
@@ -0,0 +1,157 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/gobuffalo/pop"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/cli"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// outboxBatchSize is the most deliveries the worker claims and attempts at once
+const outboxBatchSize = 50
+
+// outboxLease is how long a claimed batch is left to the worker before other workers may attempt it again
+const outboxLease = 30 * time.Minute
+
+// OutboxWorker sends the deliveries in the notification outbox
+type OutboxWorker struct {
+	db          *pop.Connection
+	logger      Logger
+	sender      NotificationSender
+	clock       clock.Clock
+	retryPolicy RetryPolicy
+}
+
+// NewOutboxWorker returns an OutboxWorker that sends deliveries with sender
+func NewOutboxWorker(db *pop.Connection, logger Logger, sender NotificationSender, clock clock.Clock, retryPolicy RetryPolicy) *OutboxWorker {
+	return &OutboxWorker{
+		db:          db,
+		logger:      logger,
+		sender:      sender,
+		clock:       clock,
+		retryPolicy: retryPolicy,
+	}
+}
+
+// DeliverDue claims a batch of the pending deliveries that are due, attempts them and returns how many it claimed.
+// Nothing is locked while notifications are sent, and each outcome is saved on its own, so one delivery failing to
+// save doesn't affect the others. Deliveries are sent at least once: one whose outcome fails to save is sent
+// again when its claim expires.
+func (w *OutboxWorker) DeliverDue(ctx context.Context) (int, error) {
+	now := w.clock.Now()
+	deliveries, err := models.ClaimDueNotificationDeliveries(w.db, now, now.Add(outboxLease), outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			// The rest of the batch is picked up again when its claim expires
+			break
+		}
+		w.attempt(ctx, &delivery)
+		w.save(delivery)
+	}
+	return len(deliveries), nil
+}
+
+// save records the outcome of an attempt in its own transaction
+func (w *OutboxWorker) save(delivery models.NotificationDelivery) {
+	err := w.db.Transaction(func(tx *pop.Connection) error {
+		verrs, err := tx.ValidateAndUpdate(&delivery)
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return errors.New(verrs.String())
+		}
+		return nil
+	})
+	if err != nil {
+		w.logger.Error("Failed to save notification delivery",
+			zap.String("id", delivery.ID.String()),
+			zap.String("status", string(delivery.Status)),
+			zap.Error(err))
+	}
+}
+
+// attempt sends a delivery and records the outcome on it
+func (w *OutboxWorker) attempt(ctx context.Context, delivery *models.NotificationDelivery) {
+	now := w.clock.Now()
+	delivery.Attempts++
+
+	err := w.sender.SendNotification(ctx, storedNotification{delivery: *delivery})
+	if err == nil {
+		delivery.Status = models.NotificationDeliveryStatusSENT
+		delivery.SentAt = &now
+		delivery.LastError = nil
+		w.logger.Info("Sent notification",
+			zap.String("id", delivery.ID.String()),
+			zap.String("notification_type", delivery.NotificationType),
+			zap.Int("attempts", delivery.Attempts))
+		return
+	}
+
+	lastError := err.Error()
+	delivery.LastError = &lastError
+	if delivery.Attempts >= w.retryPolicy.MaxAttempts {
+		delivery.Status = models.NotificationDeliveryStatusDEADLETTER
+		w.logger.Error("Giving up on notification",
+			zap.String("id", delivery.ID.String()),
+			zap.String("notification_type", delivery.NotificationType),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(err))
+		return
+	}
+
+	delivery.NextAttemptAt = now.Add(w.retryPolicy.backoff(delivery.Attempts))
+	w.logger.Info("Failed to send notification, will retry",
+		zap.String("id", delivery.ID.String()),
+		zap.String("notification_type", delivery.NotificationType),
+		zap.Int("attempts", delivery.Attempts),
+		zap.Time("next_attempt_at", delivery.NextAttemptAt),
+		zap.Error(err))
+}
+
+// Run delivers due notifications every interval until ctx is canceled
+func (w *OutboxWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := w.clock.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep going while there's a backlog, then wait for the next tick
+		for {
+			attempted, err := w.DeliverDue(ctx)
+			if err != nil {
+				w.logger.Error("Failed to deliver notifications", zap.Error(err))
+				break
+			}
+			if attempted < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// InitOutboxWorker initializes the outbox worker from command line flags
+func InitOutboxWorker(v *viper.Viper, db *pop.Connection, logger Logger, sender NotificationSender) *OutboxWorker {
+	retryPolicy := RetryPolicy{
+		MaxAttempts:    v.GetInt(cli.NotificationMaxAttemptsFlag),
+		InitialBackoff: v.GetDuration(cli.NotificationInitialBackoffFlag),
+		MaxBackoff:     v.GetDuration(cli.NotificationMaxBackoffFlag),
+	}
+	return NewOutboxWorker(db, logger, sender, clock.New(), retryPolicy)
+}
@@ -0,0 +1,99 @@
+package notifications
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// ShipmentAwarded has notification content for shipments offered to a TSP by the award queue
+type ShipmentAwarded struct {
+	db         *pop.Connection
+	logger     Logger
+	host       string
+	shipmentID uuid.UUID
+	tspID      uuid.UUID
+}
+
+// NewShipmentAwarded returns a new shipment awarded notification, sent to the TSP's users
+func NewShipmentAwarded(db *pop.Connection, logger Logger, host string, shipmentID uuid.UUID, tspID uuid.UUID) *ShipmentAwarded {
+	return &ShipmentAwarded{
+		db:         db,
+		logger:     logger,
+		host:       host,
+		shipmentID: shipmentID,
+		tspID:      tspID,
+	}
+}
+
+type shipmentAwardedEmailData struct {
+	TSPName     string
+	PickupDate  string
+	ShipmentURL string
+}
+
+func (m ShipmentAwarded) notificationType() NotificationType {
+	return ShipmentAwardedNotification
+}
+
+func (m ShipmentAwarded) emails(ctx context.Context) ([]emailContent, error) {
+	var emails []emailContent
+
+	var shipment models.Shipment
+	if err := m.db.Find(&shipment, m.shipmentID); err != nil {
+		return emails, err
+	}
+
+	tsp, err := models.FetchTransportationServiceProvider(m.db, m.tspID)
+	if err != nil {
+		return emails, err
+	}
+
+	var tspUsers models.TspUsers
+	err = m.db.Where("transportation_service_provider_id = ? AND disabled = false", m.tspID).All(&tspUsers)
+	if err != nil {
+		return emails, err
+	}
+	if len(tspUsers) == 0 {
+		// Not being able to tell the TSP shouldn't stop the shipment being offered to them
+		m.logger.Error("No TSP users to notify of awarded shipment",
+			zap.String("shipment_id", shipment.ID.String()),
+			zap.String("tsp_scac", tsp.StandardCarrierAlphaCode))
+		return emails, nil
+	}
+
+	shipmentURL := url.URL{
+		Scheme: "https",
+		Host:   m.host,
+		Path:   "shipments/" + shipment.ID.String(),
+	}
+	data := shipmentAwardedEmailData{
+		TSPName:     tsp.StandardCarrierAlphaCode,
+		ShipmentURL: shipmentURL.String(),
+	}
+	if tsp.Name != nil {
+		data.TSPName = *tsp.Name
+	}
+	if shipment.RequestedPickupDate != nil {
+		data.PickupDate = shipment.RequestedPickupDate.Format("January 2, 2006")
+	}
+
+	for _, tspUser := range tspUsers {
+		email, err := renderEmail(m.notificationType(), tspUser.Email, data)
+		if err != nil {
+			return emails, err
+		}
+		emails = append(emails, email)
+	}
+
+	m.logger.Info("Generated shipment awarded emails to TSP users",
+		zap.String("shipment_id", shipment.ID.String()),
+		zap.Int("tsp_user_count", len(tspUsers)))
+
+	return emails, nil
+}
@@ -0,0 +1,74 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// StorageInTransitApproved has notification content for approved storage in transit requests
+type StorageInTransitApproved struct {
+	db                 *pop.Connection
+	logger             Logger
+	storageInTransitID uuid.UUID
+}
+
+// NewStorageInTransitApproved returns a new storage in transit approved notification
+func NewStorageInTransitApproved(db *pop.Connection, logger Logger, storageInTransitID uuid.UUID) *StorageInTransitApproved {
+	return &StorageInTransitApproved{
+		db:                 db,
+		logger:             logger,
+		storageInTransitID: storageInTransitID,
+	}
+}
+
+type storageInTransitApprovedEmailData struct {
+	Location            string
+	AuthorizedStartDate string
+	AuthorizationNotes  string
+	WarehouseName       string
+}
+
+func (m StorageInTransitApproved) notificationType() NotificationType {
+	return StorageInTransitApprovedNotification
+}
+
+func (m StorageInTransitApproved) emails(ctx context.Context) ([]emailContent, error) {
+	var emails []emailContent
+
+	storageInTransit, err := models.FetchStorageInTransitByID(m.db, m.storageInTransitID)
+	if err != nil {
+		return emails, err
+	}
+
+	var shipment models.Shipment
+	if err = m.db.Eager("ServiceMember").Find(&shipment, storageInTransit.ShipmentID); err != nil {
+		return emails, err
+	}
+
+	if shipment.ServiceMember.PersonalEmail == nil {
+		return emails, fmt.Errorf("no email found for service member")
+	}
+
+	data := storageInTransitApprovedEmailData{
+		Location:      string(storageInTransit.Location),
+		WarehouseName: storageInTransit.WarehouseName,
+	}
+	if storageInTransit.AuthorizedStartDate != nil {
+		data.AuthorizedStartDate = storageInTransit.AuthorizedStartDate.Format("January 2, 2006")
+	}
+	if storageInTransit.AuthorizationNotes != nil {
+		data.AuthorizationNotes = *storageInTransit.AuthorizationNotes
+	}
+
+	smEmail, err := renderEmail(m.notificationType(), *shipment.ServiceMember.PersonalEmail, data)
+	if err != nil {
+		return emails, err
+	}
+
+	return append(emails, smEmail), nil
+}
@@ -0,0 +1,144 @@
+package notifications
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"path"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/assets"
+)
+
+// NotificationType identifies a kind of notification and the templates used to render it
+type NotificationType string
+
+const (
+	// MoveApprovedNotification is sent to a service member when their move is approved
+	MoveApprovedNotification NotificationType = "move_approved"
+	// MoveCanceledNotification is sent to a service member when their move is canceled
+	MoveCanceledNotification NotificationType = "move_canceled"
+	// MoveSubmittedNotification is sent to a service member when they submit their move
+	MoveSubmittedNotification NotificationType = "move_submitted"
+	// ShipmentAwardedNotification is sent to TSP users when a shipment is offered to their TSP
+	ShipmentAwardedNotification NotificationType = "shipment_awarded"
+	// StorageInTransitApprovedNotification is sent to a service member when their SIT request is approved
+	StorageInTransitApprovedNotification NotificationType = "storage_in_transit_approved"
+	// InvoiceRejectedNotification is sent to the approving office user when GEX rejects an invoice
+	InvoiceRejectedNotification NotificationType = "invoice_rejected"
+	// FuelPriceGapsNotification is sent to the fuel price alert address when months are missing fuel prices
+	FuelPriceGapsNotification NotificationType = "fuel_price_gaps"
+)
+
+// emailSubjects holds the subject line template for each notification type. Every notification type needs a
+// subject here, along with <type>.html and <type>.txt body templates in pkg/notifications/templates.
+var emailSubjects = map[NotificationType]string{
+	MoveApprovedNotification:             "MOVE.MIL: Your move has been approved.",
+	MoveCanceledNotification:             "[MilMove] Update on your move",
+	MoveSubmittedNotification:            "[MilMove] You’ve submitted your move details",
+	ShipmentAwardedNotification:          "[MilMove] New shipment offered to {{.TSPName}}",
+	StorageInTransitApprovedNotification: "[MilMove] Your storage in transit request has been approved",
+	InvoiceRejectedNotification:          "[MilMove] Invoice {{.InvoiceNumber}} was rejected",
+	FuelPriceGapsNotification:            "[MilMove] Fuel prices are missing for {{len .Months}} month(s)",
+}
+
+// templateDir is where the body templates are embedded in pkg/assets
+const templateDir = "pkg/notifications/templates"
+
+type emailTemplate struct {
+	subject *texttemplate.Template
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+}
+
+// TemplateRegistry holds the parsed email templates for every notification type
+type TemplateRegistry struct {
+	emailTemplates map[NotificationType]emailTemplate
+}
+
+// NewTemplateRegistry parses the subject, HTML and text templates for every notification type, reading the
+// body templates with asset
+func NewTemplateRegistry(asset func(name string) ([]byte, error)) (*TemplateRegistry, error) {
+	registry := &TemplateRegistry{emailTemplates: map[NotificationType]emailTemplate{}}
+
+	for notificationType, subject := range emailSubjects {
+		name := string(notificationType)
+
+		subjectTemplate, err := texttemplate.New(name + ".subject").Parse(subject)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse subject template for %s", name)
+		}
+
+		htmlSource, err := asset(path.Join(templateDir, name+".html"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to find HTML template for %s", name)
+		}
+		htmlTemplate, err := htmltemplate.New(name + ".html").Option("missingkey=error").Parse(string(htmlSource))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse HTML template for %s", name)
+		}
+
+		textSource, err := asset(path.Join(templateDir, name+".txt"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to find text template for %s", name)
+		}
+		textTemplate, err := texttemplate.New(name + ".txt").Option("missingkey=error").Parse(string(textSource))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse text template for %s", name)
+		}
+
+		registry.emailTemplates[notificationType] = emailTemplate{
+			subject: subjectTemplate,
+			html:    htmlTemplate,
+			text:    textTemplate,
+		}
+	}
+
+	return registry, nil
+}
+
+// renderEmail renders the templates for a notification type into an email to a single recipient
+func (r *TemplateRegistry) renderEmail(notificationType NotificationType, recipientEmail string, data interface{}) (emailContent, error) {
+	email := emailContent{recipientEmail: recipientEmail}
+
+	t, ok := r.emailTemplates[notificationType]
+	if !ok {
+		return email, errors.Errorf("No email template for notification type %s", notificationType)
+	}
+
+	var subject, html, text bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return email, errors.Wrapf(err, "Failed to render subject for %s", notificationType)
+	}
+	if err := t.html.Execute(&html, data); err != nil {
+		return email, errors.Wrapf(err, "Failed to render HTML body for %s", notificationType)
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return email, errors.Wrapf(err, "Failed to render text body for %s", notificationType)
+	}
+
+	email.subject = subject.String()
+	email.htmlBody = strings.TrimSpace(html.String())
+	email.textBody = strings.TrimSpace(text.String())
+	return email, nil
+}
+
+var (
+	defaultRegistry     *TemplateRegistry
+	defaultRegistryErr  error
+	defaultRegistryOnce sync.Once
+)
+
+// renderEmail renders an email with the templates embedded in pkg/assets
+func renderEmail(notificationType NotificationType, recipientEmail string, data interface{}) (emailContent, error) {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry, defaultRegistryErr = NewTemplateRegistry(assets.Asset)
+	})
+	if defaultRegistryErr != nil {
+		return emailContent{recipientEmail: recipientEmail}, defaultRegistryErr
+	}
+	return defaultRegistry.renderEmail(notificationType, recipientEmail, data)
+}
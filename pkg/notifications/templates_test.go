@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/assets"
+)
+
+func (suite *NotificationSuite) TestTemplateRegistry() {
+	registry, err := NewTemplateRegistry(assets.Asset)
+	suite.NoError(err)
+
+	// Every notification type has a subject and both bodies
+	for notificationType := range emailSubjects {
+		suite.Contains(registry.emailTemplates, notificationType)
+	}
+
+	email, err := registry.renderEmail(InvoiceRejectedNotification, "approver@example.com", invoiceRejectedEmailData{
+		InvoiceNumber:    "ABBV190001",
+		GBLNumber:        "KKFA7000001",
+		RejectionReasons: []string{"Missing segment", "<bad> & worse"},
+	})
+	suite.NoError(err)
+	suite.Equal("approver@example.com", email.recipientEmail)
+	suite.Equal("[MilMove] Invoice ABBV190001 was rejected", email.subject)
+	suite.Contains(email.textBody, "for GBL KKFA7000001")
+	suite.Contains(email.textBody, "- <bad> & worse")
+	// HTML bodies are escaped, text bodies are not
+	suite.Contains(email.htmlBody, "<li>&lt;bad&gt; &amp; worse</li>")
+
+	_, err = registry.renderEmail(NotificationType("unknown"), "approver@example.com", nil)
+	suite.Error(err)
+}
+
+func (suite *NotificationSuite) TestTemplateRegistryMissingTemplate() {
+	missingText := func(name string) ([]byte, error) {
+		if name == "pkg/notifications/templates/move_approved.txt" {
+			return nil, fmt.Errorf("Asset %s not found", name)
+		}
+		return assets.Asset(name)
+	}
+
+	_, err := NewTemplateRegistry(missingText)
+	suite.Error(err)
+	suite.Contains(err.Error(), "text template for move_approved")
+}
+
+func (suite *NotificationSuite) TestRoutingNotificationSender() {
+	ctx := context.Background()
+	notification := testNotification{email: suite.getTestEmailContent()}
+
+	defaultSender := &recordingNotificationSender{}
+	email := &recordingNotificationSender{}
+	other := &recordingNotificationSender{}
+
+	router := NewRoutingNotificationSender(defaultSender, map[NotificationType][]NotificationSender{
+		MoveApprovedNotification: {email, other},
+	})
+	suite.NoError(router.SendNotification(ctx, notification))
+	suite.Empty(defaultSender.sent)
+	suite.Equal([]NotificationType{MoveApprovedNotification}, email.sent)
+	suite.Equal([]NotificationType{MoveApprovedNotification}, other.sent)
+
+	// Types without a route go to the default sender
+	router = NewRoutingNotificationSender(defaultSender, map[NotificationType][]NotificationSender{})
+	suite.NoError(router.SendNotification(ctx, notification))
+	suite.Equal([]NotificationType{MoveApprovedNotification}, defaultSender.sent)
+
+	failing := &recordingNotificationSender{err: errors.New("channel down")}
+	router = NewRoutingNotificationSender(defaultSender, map[NotificationType][]NotificationSender{
+		MoveApprovedNotification: {failing, other},
+	})
+	err := router.SendNotification(ctx, notification)
+	suite.Error(err)
+	suite.Contains(err.Error(), "channel down")
+	suite.Len(other.sent, 1)
+}
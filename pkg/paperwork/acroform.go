@@ -0,0 +1,810 @@
+package paperwork
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hhrutter/pdfcpu/pkg/api"
+	"github.com/hhrutter/pdfcpu/pkg/pdfcpu"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/pkg/errors"
+)
+
+// Field flags from the PDF spec, counting bits from 1
+const (
+	fieldFlagMultiline  = 1 << 12
+	fieldFlagRadio      = 1 << 15
+	fieldFlagPushbutton = 1 << 16
+)
+
+// Annotation flags from the PDF spec
+const (
+	annotationFlagHidden = 1 << 1
+)
+
+const (
+	// acroFormFontName is the resource name of the font that field appearances are drawn in
+	acroFormFontName = "Helv"
+	// acroFormPadding is the space in points between a field's border and its text
+	acroFormPadding = 2
+	// acroFormMinFontSize is the smallest font size in points that text is shrunk to so that it fits its field
+	acroFormMinFontSize = 5
+	// acroFormMaxFontSize is the font size in points for auto-sized fields that have plenty of room
+	acroFormMaxFontSize = 12
+	// acroFormLineSpacing is the height of a line of text in a multiline field relative to the font size
+	acroFormLineSpacing = 1.15
+)
+
+// AcroFormFiller fills the fields of fillable (AcroForm) PDF templates, named by a layout's template_pdf. Unlike
+// FormFiller, the text it fills in stays searchable and, unless the form is flattened, editable.
+type AcroFormFiller struct {
+	pdfConfig *pdfcpu.Configuration
+	flatten   bool
+	forms     []*pdfcpu.Context
+	// fieldNames are the names of the top level fields in forms, so that repeated forms get unique names
+	fieldNames map[string]bool
+	// measure is used to measure the width of text in points
+	measure *gofpdf.Fpdf
+}
+
+// NewAcroFormFiller creates an AcroFormFiller. If flatten is true, filled in fields are drawn onto their pages and
+// the forms are no longer fillable.
+func NewAcroFormFiller(flatten bool) *AcroFormFiller {
+	measure := gofpdf.New(pageOrientation, "pt", pageSize, fontDir)
+	measure.SetFont(fontFamily, fontStyle, acroFormMaxFontSize)
+
+	return &AcroFormFiller{
+		pdfConfig:  pdfcpu.NewDefaultConfiguration(),
+		flatten:    flatten,
+		fieldNames: map[string]bool{},
+		measure:    measure,
+	}
+}
+
+// acroField is a field that holds a value, along with the widget annotations that display it
+type acroField struct {
+	dict      pdfcpu.Dict
+	widgets   []pdfcpu.Dict
+	fieldType string
+	flags     int
+	quadding  int
+	da        string
+}
+
+// AppendPage fills a copy of templatePDF with data and adds its pages to the output. Each entry in fields maps a
+// data field to a PDF field; see AcroFormField.
+func (f *AcroFormFiller) AppendPage(templatePDF io.ReadSeeker, fields map[string]FieldPos, data interface{}) error {
+	ctx, err := api.ReadContext(templatePDF, f.pdfConfig)
+	if err != nil {
+		return errors.Wrap(err, "could not read template PDF")
+	}
+	if err = api.ValidateContext(ctx); err != nil {
+		return errors.Wrap(err, "invalid template PDF")
+	}
+
+	acroForm, err := acroFormDict(ctx.XRefTable)
+	if err != nil {
+		return err
+	}
+	topFields, err := arrayEntry(ctx.XRefTable, acroForm, "Fields")
+	if err != nil {
+		return err
+	}
+	formFields := map[string]*acroField{}
+	if err = collectFields(ctx.XRefTable, topFields, "", acroField{}, formFields); err != nil {
+		return err
+	}
+
+	font, err := ctx.XRefTable.IndRefForNewObject(pdfcpu.Dict{
+		"Type":     pdfcpu.Name("Font"),
+		"Subtype":  pdfcpu.Name("Type1"),
+		"BaseFont": pdfcpu.Name(fontFamily),
+		"Encoding": pdfcpu.Name("WinAnsiEncoding"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not add font to PDF")
+	}
+
+	for name, pos := range fields {
+		val, err := fieldValue(data, name)
+		if err != nil {
+			return err
+		}
+
+		pdfName := pos.pdfField
+		if pdfName == "" {
+			pdfName = name
+		}
+		field, ok := formFields[pdfName]
+		if !ok {
+			return errors.Errorf("form field %s is not in the template PDF", pdfName)
+		}
+
+		if err = f.fillField(ctx.XRefTable, field, val, pos, *font); err != nil {
+			return errors.Wrapf(err, "could not fill form field %s", pdfName)
+		}
+	}
+
+	if f.flatten {
+		if err = flattenForm(ctx.XRefTable); err != nil {
+			return err
+		}
+	} else {
+		// Viewers redraw the fields in their own style if they can, and editing them works as usual
+		acroForm.Update("NeedAppearances", pdfcpu.Boolean(true))
+		if err = f.uniquifyFieldNames(ctx.XRefTable, topFields, len(f.forms)+1); err != nil {
+			return err
+		}
+	}
+
+	f.forms = append(f.forms, ctx)
+	return nil
+}
+
+// Output merges the filled in forms and writes them to the provided writer
+func (f *AcroFormFiller) Output(output io.Writer) error {
+	if len(f.forms) == 0 {
+		return errors.New("no forms have been filled")
+	}
+
+	dest := f.forms[0]
+	for _, src := range f.forms[1:] {
+		var srcAcroForm pdfcpu.Dict
+		if !f.flatten {
+			var err error
+			if srcAcroForm, err = acroFormDict(src.XRefTable); err != nil {
+				return err
+			}
+		}
+
+		// Merging renumbers the objects in src, including those referenced by srcAcroForm
+		if err := pdfcpu.MergeXRefTables(src, dest); err != nil {
+			return errors.Wrap(err, "could not merge forms")
+		}
+
+		// Merging only carries over pages, so the fields need to be added to the merged form
+		if srcAcroForm != nil {
+			if err := mergeAcroForms(dest.XRefTable, srcAcroForm); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(f.forms) > 1 && dest.XRefTable.Version() < pdfcpu.V15 {
+		// Merged forms may have been read from object streams, which need PDF 1.5
+		v := pdfcpu.V15
+		dest.XRefTable.RootVersion = &v
+	}
+
+	return errors.Wrap(api.WriteContext(dest, output), "could not write PDF")
+}
+
+// acroFormDict returns the interactive form dictionary of a PDF
+func acroFormDict(xRefTable *pdfcpu.XRefTable) (pdfcpu.Dict, error) {
+	catalog, err := xRefTable.Catalog()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read PDF catalog")
+	}
+	obj, found := catalog.Find("AcroForm")
+	if !found {
+		return nil, errors.New("template PDF is not a fillable form")
+	}
+	d, err := xRefTable.DereferenceDict(obj)
+	if err != nil || d == nil {
+		return nil, errors.New("template PDF has an invalid form")
+	}
+	return d, nil
+}
+
+// collectFields walks a field hierarchy, recording each field that holds a value by its fully qualified name.
+// Fields inherit their type, flags, alignment and default appearance from their parents.
+func collectFields(xRefTable *pdfcpu.XRefTable, kids pdfcpu.Array, parentName string, parent acroField, fields map[string]*acroField) error {
+	for _, kid := range kids {
+		d, err := xRefTable.DereferenceDict(kid)
+		if err != nil || d == nil {
+			return errors.New("template PDF has an invalid form field")
+		}
+
+		name := parentName
+		if t, found := d.Find("T"); found {
+			partialName, err := xRefTable.DereferenceText(t)
+			if err != nil {
+				return errors.Wrap(err, "template PDF has an invalid form field name")
+			}
+			if name != "" {
+				name += "."
+			}
+			name += partialName
+		}
+
+		field := acroField{
+			dict:      d,
+			fieldType: parent.fieldType,
+			flags:     parent.flags,
+			quadding:  parent.quadding,
+			da:        parent.da,
+		}
+		if ft := d.NameEntry("FT"); ft != nil {
+			field.fieldType = *ft
+		}
+		if ff := d.IntEntry("Ff"); ff != nil {
+			field.flags = *ff
+		}
+		if q := d.IntEntry("Q"); q != nil {
+			field.quadding = *q
+		}
+		if da, found := d.Find("DA"); found {
+			if field.da, err = xRefTable.DereferenceText(da); err != nil {
+				return errors.Wrap(err, "template PDF has an invalid default appearance")
+			}
+		}
+
+		// A field's kids are either more fields or the widgets that display it, which don't have names
+		grandKids, err := arrayEntry(xRefTable, d, "Kids")
+		if err != nil {
+			return err
+		}
+		hasFieldKids := false
+		for _, grandKid := range grandKids {
+			gd, err := xRefTable.DereferenceDict(grandKid)
+			if err != nil || gd == nil {
+				return errors.New("template PDF has an invalid form field")
+			}
+			if _, found := gd.Find("T"); found {
+				hasFieldKids = true
+			}
+		}
+
+		switch {
+		case hasFieldKids:
+			if err := collectFields(xRefTable, grandKids, name, field, fields); err != nil {
+				return err
+			}
+		case len(grandKids) > 0:
+			for _, widget := range grandKids {
+				wd, _ := xRefTable.DereferenceDict(widget)
+				field.widgets = append(field.widgets, wd)
+			}
+			fields[name] = &field
+		default:
+			// The field and its only widget are merged into one dictionary
+			field.widgets = []pdfcpu.Dict{d}
+			fields[name] = &field
+		}
+	}
+	return nil
+}
+
+// fillField sets the value of a field and draws its new appearance
+func (f *AcroFormFiller) fillField(xRefTable *pdfcpu.XRefTable, field *acroField, val interface{}, pos FieldPos, font pdfcpu.IndirectRef) error {
+	switch {
+	case field.fieldType == "Btn" && field.flags&fieldFlagPushbutton != 0:
+		return errors.New("push buttons don't have a value")
+
+	case field.fieldType == "Btn" && field.flags&fieldFlagRadio != 0:
+		// Radio buttons are set to the name of the chosen button's on state
+		state := formatFieldValue(val)
+		if state == "" {
+			state = "Off"
+		}
+		field.dict.Update("V", pdfcpu.Name(state))
+		for _, widget := range field.widgets {
+			if hasAppearanceState(xRefTable, widget, state) {
+				widget.Update("AS", pdfcpu.Name(state))
+			} else {
+				widget.Update("AS", pdfcpu.Name("Off"))
+			}
+		}
+		return nil
+
+	case field.fieldType == "Btn":
+		// Check boxes are checked by a true or non-empty value
+		state := "Off"
+		if checked(val) {
+			state = onState(xRefTable, field.widgets)
+		}
+		field.dict.Update("V", pdfcpu.Name(state))
+		for _, widget := range field.widgets {
+			widget.Update("AS", pdfcpu.Name(state))
+		}
+		return nil
+
+	case field.fieldType == "Tx" || field.fieldType == "Ch":
+		value := encodeWinAnsi(formatFieldValue(val))
+		field.dict.Update("V", pdfcpu.StringLiteral(escapeString(value)))
+		for _, widget := range field.widgets {
+			if err := f.drawTextAppearance(xRefTable, field, widget, value, pos, font); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return errors.Errorf("unsupported field type %q", field.fieldType)
+	}
+}
+
+// drawTextAppearance replaces the normal appearance of a text widget with one showing value
+func (f *AcroFormFiller) drawTextAppearance(xRefTable *pdfcpu.XRefTable, field *acroField, widget pdfcpu.Dict, value string, pos FieldPos, font pdfcpu.IndirectRef) error {
+	llx, lly, urx, ury, err := rectEntry(xRefTable, widget, "Rect")
+	if err != nil {
+		return err
+	}
+	width, height := urx-llx, ury-lly
+
+	// Use the layout's font size, then the form's, and otherwise fit the text to the field
+	size := defaultAppearanceFontSize(field.da)
+	if pos.fontSize != nil {
+		size = *pos.fontSize
+	}
+	multiline := field.flags&fieldFlagMultiline != 0
+	if size <= 0 {
+		size = acroFormMaxFontSize
+		if !multiline && height-2*acroFormPadding < size {
+			size = height - 2*acroFormPadding
+		}
+	}
+
+	f.measure.SetFontSize(size)
+	var lines []string
+	if multiline {
+		lines = f.measure.SplitText(value, width-2*acroFormPadding)
+	} else {
+		value = strings.Replace(value, "\n", " ", -1)
+		// Shrink text that is too long to fit on one line, like FormFiller does
+		for size > acroFormMinFontSize && f.measure.GetStringWidth(value) > width-2*acroFormPadding {
+			size *= .95
+			f.measure.SetFontSize(size)
+		}
+		lines = []string{value}
+	}
+
+	quadding := field.quadding
+	vertical := "M"
+	if multiline {
+		vertical = "T"
+	}
+	if pos.alignStr != nil {
+		quadding = strings.Index("LCR", (*pos.alignStr)[:1])
+		vertical = (*pos.alignStr)[1:]
+	}
+
+	// Position the first baseline, approximating the font's ascent and descent
+	lineHeight := size * acroFormLineSpacing
+	textHeight := size + lineHeight*float64(len(lines)-1)
+	var y float64
+	switch vertical {
+	case "T":
+		y = height - acroFormPadding - size*0.8
+	case "B", "A":
+		y = acroFormPadding + size*0.2 + lineHeight*float64(len(lines)-1)
+	default:
+		y = (height-textHeight)/2 + size*0.2 + lineHeight*float64(len(lines)-1)
+	}
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "/Tx BMC\nq\n%g %g %g %g re W n\nBT\n0 g\n/%s %.2f Tf\n", 1.0, 1.0, width-2, height-2, acroFormFontName, size)
+	for _, line := range lines {
+		x := float64(acroFormPadding)
+		lineWidth := f.measure.GetStringWidth(line)
+		switch quadding {
+		case 1:
+			x = (width - lineWidth) / 2
+		case 2:
+			x = width - acroFormPadding - lineWidth
+		}
+		fmt.Fprintf(&content, "1 0 0 1 %.2f %.2f Tm (%s) Tj\n", x, y, escapeString(line))
+		y -= lineHeight
+	}
+	content.WriteString("ET\nQ\nEMC\n")
+
+	appearance, err := newStream(xRefTable, pdfcpu.Dict{
+		"Type":    pdfcpu.Name("XObject"),
+		"Subtype": pdfcpu.Name("Form"),
+		"BBox":    pdfcpu.NewNumberArray(0, 0, width, height),
+		"Resources": pdfcpu.Dict{
+			"Font": pdfcpu.Dict{acroFormFontName: font},
+		},
+	}, content.Bytes())
+	if err != nil {
+		return err
+	}
+	widget.Update("AP", pdfcpu.Dict{"N": appearance})
+	return nil
+}
+
+// flattenForm draws the appearance of every visible widget onto its page and removes the widgets and the form,
+// leaving the filled in values as ordinary page content
+func flattenForm(xRefTable *pdfcpu.XRefTable) error {
+	for page := 1; page <= xRefTable.PageCount; page++ {
+		pageDict, _, err := xRefTable.PageDict(page)
+		if err != nil {
+			return errors.Wrapf(err, "could not read page %d", page)
+		}
+		annots, err := xRefTable.DereferenceArray(pageDict["Annots"])
+		if err != nil {
+			return errors.Wrapf(err, "could not read annotations on page %d", page)
+		}
+
+		var keep pdfcpu.Array
+		xObjects := pdfcpu.Dict{}
+		var content bytes.Buffer
+		for _, annot := range annots {
+			d, err := xRefTable.DereferenceDict(annot)
+			if err != nil || d == nil || d.Subtype() == nil || *d.Subtype() != "Widget" {
+				keep = append(keep, annot)
+				continue
+			}
+			if flags := d.IntEntry("F"); flags != nil && *flags&annotationFlagHidden != 0 {
+				continue
+			}
+
+			appearance, found, err := normalAppearance(xRefTable, d)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+			llx, lly, urx, ury, err := rectEntry(xRefTable, d, "Rect")
+			if err != nil {
+				return err
+			}
+			appearanceDict, err := xRefTable.DereferenceStreamDict(appearance)
+			if err != nil || appearanceDict == nil {
+				return errors.New("template PDF has an invalid field appearance")
+			}
+			bllx, blly, burx, bury, err := rectEntry(xRefTable, appearanceDict.Dict, "BBox")
+			if err != nil {
+				return err
+			}
+			if burx == bllx || bury == blly {
+				continue
+			}
+
+			// Scale and move the appearance's bounding box onto the widget's rectangle
+			name := fmt.Sprintf("FlatField%d", len(xObjects))
+			xObjects[name] = appearance
+			sx, sy := (urx-llx)/(burx-bllx), (ury-lly)/(bury-blly)
+			fmt.Fprintf(&content, "q %g 0 0 %g %g %g cm /%s Do Q\n", sx, sy, llx-bllx*sx, lly-blly*sy, name)
+		}
+
+		if len(keep) > 0 {
+			pageDict.Update("Annots", keep)
+		} else {
+			pageDict.Delete("Annots")
+		}
+		if len(xObjects) == 0 {
+			continue
+		}
+
+		if err = addPageXObjects(xRefTable, pageDict, xObjects); err != nil {
+			return err
+		}
+		if err = appendPageContent(xRefTable, pageDict, content.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	catalog, err := xRefTable.Catalog()
+	if err != nil {
+		return errors.Wrap(err, "could not read PDF catalog")
+	}
+	catalog.Delete("AcroForm")
+	return nil
+}
+
+// normalAppearance returns a reference to the stream a widget is displayed with in its current state
+func normalAppearance(xRefTable *pdfcpu.XRefTable, widget pdfcpu.Dict) (pdfcpu.IndirectRef, bool, error) {
+	ap, err := xRefTable.DereferenceDict(widget["AP"])
+	if err != nil || ap == nil {
+		return pdfcpu.IndirectRef{}, false, nil
+	}
+	n, found := ap.Find("N")
+	if !found {
+		return pdfcpu.IndirectRef{}, false, nil
+	}
+
+	// Widgets with several states, like check boxes, have an appearance for each
+	if states, err := xRefTable.DereferenceDict(n); err == nil && states != nil {
+		state := widget.NameEntry("AS")
+		if state == nil {
+			return pdfcpu.IndirectRef{}, false, nil
+		}
+		n, found = states.Find(*state)
+		if !found {
+			return pdfcpu.IndirectRef{}, false, nil
+		}
+	}
+
+	ref, ok := n.(pdfcpu.IndirectRef)
+	if !ok {
+		return pdfcpu.IndirectRef{}, false, errors.New("template PDF has an invalid field appearance")
+	}
+	return ref, true, nil
+}
+
+// addPageXObjects adds form XObjects to a page's resources. Resources can be shared with other pages, so the page
+// is given its own copy.
+func addPageXObjects(xRefTable *pdfcpu.XRefTable, pageDict pdfcpu.Dict, xObjects pdfcpu.Dict) error {
+	resources, err := pageResources(xRefTable, pageDict)
+	if err != nil {
+		return err
+	}
+	pageResources := pdfcpu.Dict{}
+	for k, v := range resources {
+		pageResources[k] = v
+	}
+
+	existing, err := xRefTable.DereferenceDict(resources["XObject"])
+	if err != nil {
+		return errors.Wrap(err, "could not read page resources")
+	}
+	pageXObjects := pdfcpu.Dict{}
+	for k, v := range existing {
+		pageXObjects[k] = v
+	}
+	for k, v := range xObjects {
+		pageXObjects[k] = v
+	}
+	pageResources["XObject"] = pageXObjects
+
+	pageDict.Update("Resources", pageResources)
+	return nil
+}
+
+// pageResources returns a page's resources, which may be inherited from its ancestors in the page tree
+func pageResources(xRefTable *pdfcpu.XRefTable, pageDict pdfcpu.Dict) (pdfcpu.Dict, error) {
+	for d := pageDict; d != nil; {
+		if obj, found := d.Find("Resources"); found {
+			resources, err := xRefTable.DereferenceDict(obj)
+			return resources, errors.Wrap(err, "could not read page resources")
+		}
+		parent, err := xRefTable.DereferenceDict(d["Parent"])
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read page tree")
+		}
+		d = parent
+	}
+	return pdfcpu.Dict{}, nil
+}
+
+// appendPageContent draws content on top of a page, isolating it from the graphics state the page leaves behind
+func appendPageContent(xRefTable *pdfcpu.XRefTable, pageDict pdfcpu.Dict, content []byte) error {
+	save, err := newStream(xRefTable, pdfcpu.Dict{}, []byte("q\n"))
+	if err != nil {
+		return err
+	}
+	restore, err := newStream(xRefTable, pdfcpu.Dict{}, append([]byte("Q\n"), content...))
+	if err != nil {
+		return err
+	}
+
+	contents := pdfcpu.Array{save}
+	switch existing := pageDict["Contents"].(type) {
+	case nil:
+	case pdfcpu.IndirectRef:
+		// A reference to a stream or an array of streams
+		obj, err := xRefTable.Dereference(existing)
+		if err != nil {
+			return errors.Wrap(err, "could not read page contents")
+		}
+		if a, ok := obj.(pdfcpu.Array); ok {
+			contents = append(contents, a...)
+		} else {
+			contents = append(contents, existing)
+		}
+	case pdfcpu.Array:
+		contents = append(contents, existing...)
+	default:
+		return errors.New("template PDF has invalid page contents")
+	}
+	contents = append(contents, restore)
+
+	pageDict.Update("Contents", contents)
+	return nil
+}
+
+// mergeAcroForms adds the fields and default resources of src to the form of a merged PDF
+func mergeAcroForms(xRefTable *pdfcpu.XRefTable, src pdfcpu.Dict) error {
+	dest, err := acroFormDict(xRefTable)
+	if err != nil {
+		return err
+	}
+	destFields, err := arrayEntry(xRefTable, dest, "Fields")
+	if err != nil {
+		return err
+	}
+	srcFields, err := arrayEntry(xRefTable, src, "Fields")
+	if err != nil {
+		return err
+	}
+	dest.Update("Fields", append(append(pdfcpu.Array{}, destFields...), srcFields...))
+
+	// Fields look up the fonts in their default appearance in the form's default resources
+	srcResources, err := xRefTable.DereferenceDict(src["DR"])
+	if err != nil || srcResources == nil {
+		return nil
+	}
+	srcFonts, err := xRefTable.DereferenceDict(srcResources["Font"])
+	if err != nil || srcFonts == nil {
+		return nil
+	}
+	destResources, err := xRefTable.DereferenceDict(dest["DR"])
+	if err != nil {
+		return errors.Wrap(err, "could not read form resources")
+	}
+	if destResources == nil {
+		destResources = pdfcpu.Dict{}
+		dest.Update("DR", destResources)
+	}
+	destFonts, err := xRefTable.DereferenceDict(destResources["Font"])
+	if err != nil {
+		return errors.Wrap(err, "could not read form resources")
+	}
+	if destFonts == nil {
+		destFonts = pdfcpu.Dict{}
+		destResources.Update("Font", destFonts)
+	}
+	for name, font := range srcFonts {
+		if _, found := destFonts.Find(name); !found {
+			destFonts.Insert(name, font)
+		}
+	}
+	return nil
+}
+
+// uniquifyFieldNames renames top level fields that have the same names as fields in previously filled forms,
+// because viewers treat fields with the same name as one field with one value
+func (f *AcroFormFiller) uniquifyFieldNames(xRefTable *pdfcpu.XRefTable, fields pdfcpu.Array, formNumber int) error {
+	for _, field := range fields {
+		d, err := xRefTable.DereferenceDict(field)
+		if err != nil || d == nil {
+			return errors.New("template PDF has an invalid form field")
+		}
+		t, found := d.Find("T")
+		if !found {
+			continue
+		}
+		name, err := xRefTable.DereferenceText(t)
+		if err != nil {
+			return errors.Wrap(err, "template PDF has an invalid form field name")
+		}
+		if f.fieldNames[name] {
+			name = fmt.Sprintf("%s_%d", name, formNumber)
+			d.Update("T", pdfcpu.StringLiteral(escapeString(name)))
+		}
+		f.fieldNames[name] = true
+	}
+	return nil
+}
+
+// onState returns the name of the checked state of a check box, which is usually but not always "Yes"
+func onState(xRefTable *pdfcpu.XRefTable, widgets []pdfcpu.Dict) string {
+	for _, widget := range widgets {
+		ap, err := xRefTable.DereferenceDict(widget["AP"])
+		if err != nil || ap == nil {
+			continue
+		}
+		states, err := xRefTable.DereferenceDict(ap["N"])
+		if err != nil || states == nil {
+			continue
+		}
+		for state := range states {
+			if state != "Off" {
+				return state
+			}
+		}
+	}
+	return "Yes"
+}
+
+// hasAppearanceState returns true if a widget has a normal appearance for state
+func hasAppearanceState(xRefTable *pdfcpu.XRefTable, widget pdfcpu.Dict, state string) bool {
+	ap, err := xRefTable.DereferenceDict(widget["AP"])
+	if err != nil || ap == nil {
+		return false
+	}
+	states, err := xRefTable.DereferenceDict(ap["N"])
+	if err != nil || states == nil {
+		return false
+	}
+	_, found := states.Find(state)
+	return found
+}
+
+// checked returns true if a value should check a check box
+func checked(val interface{}) bool {
+	switch v := val.(type) {
+	case bool:
+		return v
+	case *bool:
+		return v != nil && *v
+	default:
+		return formatFieldValue(val) != ""
+	}
+}
+
+// defaultAppearanceFontSize returns the font size set by a default appearance string like "/Helv 12 Tf 0 g", or 0
+// if the text should be sized to fit its field
+func defaultAppearanceFontSize(da string) float64 {
+	tokens := strings.Fields(da)
+	for i, token := range tokens {
+		if token == "Tf" && i > 0 {
+			var size float64
+			if _, err := fmt.Sscanf(tokens[i-1], "%g", &size); err == nil {
+				return size
+			}
+		}
+	}
+	return 0
+}
+
+// arrayEntry returns an array in a dictionary, which may be an indirect reference
+func arrayEntry(xRefTable *pdfcpu.XRefTable, d pdfcpu.Dict, key string) (pdfcpu.Array, error) {
+	a, err := xRefTable.DereferenceArray(d[key])
+	if err != nil {
+		return nil, errors.Errorf("template PDF has an invalid %s entry", key)
+	}
+	return a, nil
+}
+
+// rectEntry returns the corners of a rectangle in a dictionary, normalized so the lower left comes first
+func rectEntry(xRefTable *pdfcpu.XRefTable, d pdfcpu.Dict, key string) (llx, lly, urx, ury float64, err error) {
+	a, err := xRefTable.DereferenceArray(d[key])
+	if err != nil || len(a) != 4 {
+		return 0, 0, 0, 0, errors.Errorf("template PDF has an invalid %s", key)
+	}
+	var corners [4]float64
+	for i, obj := range a {
+		if corners[i], err = xRefTable.DereferenceNumber(obj); err != nil {
+			return 0, 0, 0, 0, errors.Errorf("template PDF has an invalid %s", key)
+		}
+	}
+	llx, lly, urx, ury = corners[0], corners[1], corners[2], corners[3]
+	if llx > urx {
+		llx, urx = urx, llx
+	}
+	if lly > ury {
+		lly, ury = ury, lly
+	}
+	return llx, lly, urx, ury, nil
+}
+
+// newStream adds an uncompressed stream object to a PDF
+func newStream(xRefTable *pdfcpu.XRefTable, d pdfcpu.Dict, content []byte) (pdfcpu.IndirectRef, error) {
+	length := int64(len(content))
+	d.Update("Length", pdfcpu.Integer(length))
+	sd := pdfcpu.StreamDict{
+		Dict:         d,
+		Content:      content,
+		Raw:          content,
+		StreamLength: &length,
+	}
+	ref, err := xRefTable.IndRefForNewObject(sd)
+	if err != nil {
+		return pdfcpu.IndirectRef{}, errors.Wrap(err, "could not add stream to PDF")
+	}
+	return *ref, nil
+}
+
+// encodeWinAnsi converts text to the single byte encoding used by the standard fonts, replacing characters that
+// can't be shown with question marks
+func encodeWinAnsi(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r < 0x20 && r != '\n' || r > 0xff || r >= 0x7f && r < 0xa0 {
+			r = '?'
+		}
+		b = append(b, byte(r))
+	}
+	return string(b)
+}
+
+// escapeString escapes text for use in a PDF string literal
+func escapeString(s string) string {
+	escaped, _ := pdfcpu.Escape(s)
+	return *escaped
+}
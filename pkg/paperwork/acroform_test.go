@@ -0,0 +1,147 @@
+package paperwork
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/hhrutter/pdfcpu/pkg/api"
+	"github.com/hhrutter/pdfcpu/pkg/pdfcpu"
+)
+
+var acroFormTestFields = map[string]FieldPos{
+	"Name":      AcroFormField("name", nil, nil),
+	"Weight":    AcroFormField("shipment.weight", nil, nil),
+	"Certified": AcroFormField("certified", nil, nil),
+}
+
+type acroFormTestModel struct {
+	Name      string
+	Weight    string
+	Certified bool
+}
+
+func (suite *PaperworkSuite) fillAcroFormTemplate(formFiller *AcroFormFiller, times int) *pdfcpu.Context {
+	for i := 0; i < times; i++ {
+		f, err := os.Open("./testdata/acroform_template.pdf")
+		suite.FatalNil(err)
+		err = formFiller.AppendPage(f, acroFormTestFields, acroFormTestModel{
+			Name:      "Jane (Q) Smith",
+			Weight:    "2,000 lbs",
+			Certified: true,
+		})
+		f.Close()
+		suite.FatalNil(err)
+	}
+
+	var output bytes.Buffer
+	suite.FatalNil(formFiller.Output(&output))
+
+	ctx, err := api.ReadContext(bytes.NewReader(output.Bytes()), pdfcpu.NewDefaultConfiguration())
+	suite.FatalNil(err)
+	suite.FatalNil(api.ValidateContext(ctx))
+	return ctx
+}
+
+func (suite *PaperworkSuite) TestAcroFormFillerFillsFields() {
+	ctx := suite.fillAcroFormTemplate(NewAcroFormFiller(false), 1)
+	suite.Equal(1, ctx.PageCount)
+
+	acroForm, err := acroFormDict(ctx.XRefTable)
+	suite.FatalNil(err)
+	suite.Equal(pdfcpu.Boolean(true), acroForm["NeedAppearances"])
+
+	topFields, err := arrayEntry(ctx.XRefTable, acroForm, "Fields")
+	suite.FatalNil(err)
+	fields := map[string]*acroField{}
+	suite.FatalNil(collectFields(ctx.XRefTable, topFields, "", acroField{}, fields))
+
+	suite.Equal(pdfcpu.StringLiteral(`Jane \(Q\) Smith`), fields["name"].dict["V"])
+	suite.Equal(pdfcpu.StringLiteral("2,000 lbs"), fields["shipment.weight"].dict["V"])
+	suite.Equal(pdfcpu.Name("Yes"), fields["certified"].dict["V"])
+	suite.Equal(pdfcpu.Name("Yes"), fields["certified"].widgets[0]["AS"])
+
+	// Text fields get an appearance so they show up in viewers that don't draw their own
+	_, found, err := normalAppearance(ctx.XRefTable, fields["name"].widgets[0])
+	suite.NoError(err)
+	suite.True(found)
+}
+
+func (suite *PaperworkSuite) TestAcroFormFillerRenamesRepeatedFields() {
+	ctx := suite.fillAcroFormTemplate(NewAcroFormFiller(false), 2)
+	suite.Equal(2, ctx.PageCount)
+
+	acroForm, err := acroFormDict(ctx.XRefTable)
+	suite.FatalNil(err)
+	topFields, err := arrayEntry(ctx.XRefTable, acroForm, "Fields")
+	suite.FatalNil(err)
+	fields := map[string]*acroField{}
+	suite.FatalNil(collectFields(ctx.XRefTable, topFields, "", acroField{}, fields))
+
+	suite.Contains(fields, "name")
+	suite.Contains(fields, "name_2")
+	suite.Contains(fields, "shipment_2.weight")
+}
+
+func (suite *PaperworkSuite) TestAcroFormFillerFlattens() {
+	ctx := suite.fillAcroFormTemplate(NewAcroFormFiller(true), 2)
+	suite.Equal(2, ctx.PageCount)
+
+	_, err := acroFormDict(ctx.XRefTable)
+	suite.Error(err)
+
+	for page := 1; page <= ctx.PageCount; page++ {
+		pageDict, _, err := ctx.XRefTable.PageDict(page)
+		suite.FatalNil(err)
+		_, found := pageDict.Find("Annots")
+		suite.False(found)
+
+		resources, err := pageResources(ctx.XRefTable, pageDict)
+		suite.FatalNil(err)
+		xObjects, err := ctx.XRefTable.DereferenceDict(resources["XObject"])
+		suite.FatalNil(err)
+		suite.Len(xObjects, 3)
+	}
+}
+
+func (suite *PaperworkSuite) TestAcroFormFillerMissingPDFField() {
+	f, err := os.Open("./testdata/acroform_template.pdf")
+	suite.FatalNil(err)
+	defer f.Close()
+
+	fields := map[string]FieldPos{
+		"Name": AcroFormField("not_in_the_pdf", nil, nil),
+	}
+	err = NewAcroFormFiller(false).AppendPage(f, fields, map[string]string{"Name": "Jane Smith"})
+	suite.Error(err)
+}
+
+func (suite *PaperworkSuite) TestAcroFormFillerRequiresFillableForm() {
+	f, err := os.Open("./testdata/orders1.pdf")
+	suite.FatalNil(err)
+	defer f.Close()
+
+	err = NewAcroFormFiller(false).AppendPage(f, acroFormTestFields, acroFormTestModel{})
+	suite.Error(err)
+}
+
+func (suite *PaperworkSuite) TestNewFillerForLayout() {
+	imageLayout, err := LoadFormLayoutAsset(Form1203LayoutName)
+	suite.FatalNil(err)
+	suite.IsType(&FormFiller{}, NewFillerForLayout(imageLayout))
+	suite.Equal(imageLayout.TemplateImagePath, imageLayout.TemplatePath())
+
+	pdfLayout := FormLayout{TemplatePDFPath: "./testdata/acroform_template.pdf", FieldsLayout: acroFormTestFields}
+	suite.Equal(pdfLayout.TemplatePDFPath, pdfLayout.TemplatePath())
+	formFiller, ok := NewFillerForLayout(pdfLayout).(*AcroFormFiller)
+	suite.FatalFalse(!ok)
+	ctx := suite.fillAcroFormTemplate(formFiller, 1)
+	_, err = acroFormDict(ctx.XRefTable)
+	suite.NoError(err, "the filled form should stay fillable")
+
+	pdfLayout.Flatten = true
+	formFiller, ok = NewFillerForLayout(pdfLayout).(*AcroFormFiller)
+	suite.FatalFalse(!ok)
+	ctx = suite.fillAcroFormTemplate(formFiller, 1)
+	_, err = acroFormDict(ctx.XRefTable)
+	suite.Error(err, "the filled form should be flattened")
+}
@@ -93,13 +93,43 @@ func stringPtr(s string) *string {
 	return &s
 }
 
-// FormLayout houses both a background image form template and the layout of individual fields
+// FormLayout houses both a form template and the layout of individual fields. The template is either a background
+// image that FormFiller draws fields onto, or a fillable PDF whose fields AcroFormFiller fills in.
+// Layouts are defined in YAML files in pkg/paperwork/formtemplates; see ParseFormLayout.
 type FormLayout struct {
 	TemplateImagePath string
-	FieldsLayout      map[string]FieldPos
+	TemplatePDFPath   string
+	// Flatten is true if the fields of the fillable PDF are drawn onto its pages once they're filled in
+	Flatten      bool
+	FieldsLayout map[string]FieldPos
 }
 
-// FieldPos encapsulates the starting position and width of a form field
+// TemplatePath returns the path of the layout's fillable PDF, or of its background image if it doesn't have one
+func (l FormLayout) TemplatePath() string {
+	if l.TemplatePDFPath != "" {
+		return l.TemplatePDFPath
+	}
+	return l.TemplateImagePath
+}
+
+// Filler fills in a form one template at a time and writes out the result. FormFiller and AcroFormFiller are
+// Fillers.
+type Filler interface {
+	AppendPage(io.ReadSeeker, map[string]FieldPos, interface{}) error
+	Output(io.Writer) error
+}
+
+// NewFillerForLayout returns a new Filler for forms with the given layout: an AcroFormFiller if the layout has a
+// fillable PDF, flattening the form if the layout says to, and otherwise a FormFiller
+func NewFillerForLayout(layout FormLayout) Filler {
+	if layout.TemplatePDFPath != "" {
+		return NewAcroFormFiller(layout.Flatten)
+	}
+	return NewFormFiller()
+}
+
+// FieldPos encapsulates the starting position and width of a form field, or for fillable PDFs the name of the
+// PDF field it is filled into
 type FieldPos struct {
 	xPos       float64
 	yPos       float64
@@ -107,6 +137,7 @@ type FieldPos struct {
 	fontSize   *float64
 	lineHeight *float64
 	alignStr   *string
+	pdfField   string
 }
 
 // FormField returns a new field position
@@ -121,6 +152,16 @@ func FormField(xPos, yPos, width float64, fontSize, lineHeight *float64, alignSt
 	}
 }
 
+// AcroFormField returns a new field that is filled into the named field of a fillable PDF. The PDF field name is
+// fully qualified, with the names of parent fields separated by periods.
+func AcroFormField(pdfField string, fontSize *float64, alignStr *string) FieldPos {
+	return FieldPos{
+		fontSize: fontSize,
+		alignStr: alignStr,
+		pdfField: pdfField,
+	}
+}
+
 // FormFiller is a fillable pdf form
 type FormFiller struct {
 	pdf   *gofpdf.Fpdf
@@ -205,53 +246,77 @@ func (f *FormFiller) drawDebugOverlay(xPos, yPos, width, lineHeight float64, lab
 	f.pdf.SetFontSize(fs)
 }
 
+// fieldValue returns the value of a field from the data being drawn, which is either a struct with a field of
+// that name or a map of field names to values
+func fieldValue(data interface{}, name string) (interface{}, error) {
+	if values, ok := data.(map[string]string); ok {
+		return values[name], nil
+	}
+
+	r := reflect.Indirect(reflect.ValueOf(data))
+	if r.Kind() != reflect.Struct {
+		return nil, errors.Errorf("cannot fill form with data of type %T", data)
+	}
+	fieldVal := r.FieldByName(name)
+	if !fieldVal.IsValid() {
+		return nil, errors.Errorf("form field %s is not in the data of type %T", name, data)
+	}
+	return fieldVal.Interface(), nil
+}
+
+// formatFieldValue turns a value into a display string depending on type, will need
+// an explicit case for each type we're accommodating
+func formatFieldValue(val interface{}) string {
+	var displayValue string
+	switch v := val.(type) {
+	case string:
+		displayValue = v
+	case int64:
+		displayValue = strconv.FormatInt(v, 10)
+	case time.Time:
+		displayValue = v.Format("02-Jan-2006")
+	case internalmessages.ServiceMemberRank:
+		displayValue = rankDisplayValue[v]
+	case *internalmessages.ServiceMemberRank:
+		if v != nil {
+			displayValue = rankDisplayValue[*v]
+		}
+	case internalmessages.Affiliation:
+		displayValue = affiliationDisplayValue[v]
+	case *internalmessages.Affiliation:
+		if v != nil {
+			displayValue = affiliationDisplayValue[*v]
+		}
+	case internalmessages.DeptIndicator:
+		displayValue = deptIndDisplayValue[v]
+	case *internalmessages.DeptIndicator:
+		if v != nil {
+			displayValue = "DI: " + deptIndDisplayValue[*v]
+		}
+	case models.Address:
+		displayValue = v.Format()
+	case *models.Address:
+		if v != nil {
+			displayValue = v.Format()
+		}
+	default:
+		fmt.Println(v)
+	}
+	return displayValue
+}
+
 // DrawData draws the provided data set onto the form using the fields mapping
 func (f *FormFiller) drawData(fields map[string]FieldPos, data interface{}) error {
-	r := reflect.ValueOf(data)
 	for k := range fields {
-		fieldVal := reflect.Indirect(r).FieldByName(k)
-		val := fieldVal.Interface()
+		val, err := fieldValue(data, k)
+		if err != nil {
+			return err
+		}
 
 		formField := fields[k]
 		f.pdf.MoveTo(formField.xPos, formField.yPos)
 
-		// Turn value into a display string depending on type, will need
-		// an explicit case for each type we're accommodating
-		var displayValue string
-		switch v := val.(type) {
-		case string:
-			displayValue = v
-		case int64:
-			displayValue = strconv.FormatInt(v, 10)
-		case time.Time:
-			displayValue = v.Format("02-Jan-2006")
-		case internalmessages.ServiceMemberRank:
-			displayValue = rankDisplayValue[v]
-		case *internalmessages.ServiceMemberRank:
-			if v != nil {
-				displayValue = rankDisplayValue[*v]
-			}
-		case internalmessages.Affiliation:
-			displayValue = affiliationDisplayValue[v]
-		case *internalmessages.Affiliation:
-			if v != nil {
-				displayValue = affiliationDisplayValue[*v]
-			}
-		case internalmessages.DeptIndicator:
-			displayValue = deptIndDisplayValue[v]
-		case *internalmessages.DeptIndicator:
-			if v != nil {
-				displayValue = "DI: " + deptIndDisplayValue[*v]
-			}
-		case models.Address:
-			displayValue = v.Format()
-		case *models.Address:
-			if v != nil {
-				displayValue = v.Format()
-			}
-		default:
-			fmt.Println(v)
-		}
+		displayValue := formatFieldValue(val)
 
 		// Apply custom formatting options
 		if formField.fontSize != nil {
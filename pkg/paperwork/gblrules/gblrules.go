@@ -0,0 +1,373 @@
+// Package gblrules derives the remarks, bill-to party and description of shipment that go on a Government Bill of
+// Lading (DD Form 1203) from the facts of a shipment. Each rule is a plain function of Facts so that it can be
+// tested without a database.
+package gblrules
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+const (
+	// LocationOrigin is SIT at the origin
+	LocationOrigin = "ORIGIN"
+	// LocationDestination is SIT at the destination
+	LocationDestination = "DESTINATION"
+
+	// AffiliationCoastGuard is the service member affiliation for the Coast Guard
+	AffiliationCoastGuard = "COAST_GUARD"
+
+	// crateItemCode is the 400NG item for packing a crate, which counts as a container
+	crateItemCode = "105B"
+)
+
+// StorageInTransit is a request to store the shipment in transit. Denied requests are left out of Facts.
+type StorageInTransit struct {
+	Location   string
+	Authorized bool
+	StartDate  time.Time
+}
+
+// Accessorial is a pre-approval line item on the shipment
+type Accessorial struct {
+	Code     string
+	Item     string
+	Approved bool
+}
+
+// Facts are everything about a shipment, its orders and its line items that the rules look at
+type Facts struct {
+	Affiliation                  string
+	HasDependents                bool
+	WeightAllowance              unit.Pound
+	WeightEstimate               unit.Pound
+	ProgearWeightEstimate        unit.Pound
+	SpouseProgearWeightEstimate  unit.Pound
+	HasSecondaryPickupAddress    bool
+	HasDeliveryAddress           bool
+	HasPartialSITDeliveryAddress bool
+	StorageInTransits            []StorageInTransit
+	Accessorials                 []Accessorial
+	PayingOfficer                PayingOfficerFlags
+}
+
+// PayingOfficerFlags are the "For Use of Paying Officer" boxes of the GBL. The TSP checks them, and each one that
+// is checked must be explained in the remarks.
+type PayingOfficerFlags struct {
+	UnauthorizedItems bool
+	ExcessDistance    bool
+	ExcessValuation   bool
+	ExcessWeight      bool
+	Other             bool
+}
+
+func (f Facts) hasSIT(location string) bool {
+	for _, sit := range f.StorageInTransits {
+		if sit.Location == location {
+			return true
+		}
+	}
+	return false
+}
+
+// excessWeight is how far the estimated weight is over the weight allowance, or zero
+func (f Facts) excessWeight() unit.Pound {
+	if f.WeightAllowance <= 0 || f.WeightEstimate <= f.WeightAllowance {
+		return 0
+	}
+	return f.WeightEstimate - f.WeightAllowance
+}
+
+// accessorialItems lists the accessorials with one of codes, or with any code if none are given, that are or are
+// not yet approved
+func (f Facts) accessorialItems(approved bool, codes ...string) []string {
+	var items []string
+	for _, accessorial := range f.Accessorials {
+		if accessorial.Approved != approved {
+			continue
+		}
+		if len(codes) > 0 && !hasCode(codes, accessorial.Code) {
+			continue
+		}
+		items = append(items, accessorial.Code+" "+accessorial.Item)
+	}
+	return items
+}
+
+func hasCode(codes []string, code string) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func formatPounds(weight unit.Pound) string {
+	return humanize.Comma(int64(weight)) + " lbs"
+}
+
+// RemarkRule adds zero or more remarks to a GBL
+type RemarkRule struct {
+	Name   string
+	Remark func(f Facts) []string
+}
+
+// 400NG items that get a remark of their own when they're approved, because the TSP has to plan for them
+var (
+	reweighItemCodes           = []string{"4A", "4B"}
+	shuttleServiceItemCodes    = []string{"125A", "125B", "125C", "125D"}
+	bulkyArticleItemCodes      = []string{"130A", "130B", "130C", "130D", "130E", "130F", "130G", "130H", "130I", "130J"}
+	thirdPartyServiceItemCodes = []string{"35A"}
+)
+
+// RemarkRules are the rules for the remarks block of the GBL, in the order their remarks appear: the sixteen remark
+// cases, then one for each checked "For Use of Paying Officer" box. Accessorials are named as they are in the 400NG
+// tariff.
+var RemarkRules = []RemarkRule{
+	{
+		Name: "direct delivery",
+		Remark: func(f Facts) []string {
+			if f.hasSIT(LocationDestination) {
+				return nil
+			}
+			return []string{"Direct Delivery Requested"}
+		},
+	},
+	{Name: "origin SIT", Remark: sitRemarks(LocationOrigin, "Origin")},
+	{Name: "destination SIT", Remark: sitRemarks(LocationDestination, "Destination")},
+	{
+		Name: "extra pickup",
+		Remark: func(f Facts) []string {
+			if !f.HasSecondaryPickupAddress {
+				return nil
+			}
+			return []string{"Extra Pickup Authorized at Secondary Pickup Address"}
+		},
+	},
+	{
+		Name: "extra delivery",
+		Remark: func(f Facts) []string {
+			if !f.HasPartialSITDeliveryAddress {
+				return nil
+			}
+			return []string{"Extra Delivery Authorized: Partial Delivery out of SIT"}
+		},
+	},
+	{
+		Name: "delivery address",
+		Remark: func(f Facts) []string {
+			if !f.HasDeliveryAddress {
+				return nil
+			}
+			return []string{"Deliver to Member's Delivery Address, Not New Duty Station"}
+		},
+	},
+	{
+		Name: "member PBP&E",
+		Remark: func(f Facts) []string {
+			if f.ProgearWeightEstimate <= 0 {
+				return nil
+			}
+			return []string{"PBP&E: " + formatPounds(f.ProgearWeightEstimate)}
+		},
+	},
+	{
+		Name: "spouse PBP&E",
+		Remark: func(f Facts) []string {
+			if f.SpouseProgearWeightEstimate <= 0 {
+				return nil
+			}
+			return []string{"Spouse PBP&E: " + formatPounds(f.SpouseProgearWeightEstimate)}
+		},
+	},
+	{
+		Name: "weight allowance",
+		Remark: func(f Facts) []string {
+			if f.WeightAllowance <= 0 {
+				return nil
+			}
+			return []string{"Weight Allowance: " + formatPounds(f.WeightAllowance)}
+		},
+	},
+	{
+		Name: "excess weight",
+		Remark: func(f Facts) []string {
+			excess := f.excessWeight()
+			if excess <= 0 {
+				return nil
+			}
+			return []string{"Estimated Weight Exceeds Weight Allowance by " + formatPounds(excess) +
+				". Member Is Liable for Excess Costs"}
+		},
+	},
+	{
+		Name: "reweigh",
+		Remark: func(f Facts) []string {
+			if len(f.accessorialItems(true, reweighItemCodes...)) == 0 {
+				return nil
+			}
+			return []string{"Reweigh Requested"}
+		},
+	},
+	{Name: "shuttle service", Remark: accessorialItemRemarks(shuttleServiceItemCodes, "Shuttle Service Authorized")},
+	{Name: "bulky articles", Remark: accessorialItemRemarks(bulkyArticleItemCodes, "Bulky Articles Authorized")},
+	{Name: "third party service", Remark: accessorialItemRemarks(thirdPartyServiceItemCodes, "Third Party Service Authorized")},
+	{Name: "approved accessorials", Remark: accessorialRemarks(true, "Approved Accessorials")},
+	{Name: "pending accessorials", Remark: accessorialRemarks(false, "Accessorials Pending Approval")},
+	{
+		Name: "paying officer unauthorized items",
+		Remark: payingOfficerRemarks("Unauthorized Items", func(f Facts) (bool, string) {
+			return f.PayingOfficer.UnauthorizedItems, strings.Join(f.accessorialItems(false), ", ")
+		}),
+	},
+	{
+		Name: "paying officer excess distance",
+		Remark: payingOfficerRemarks("Excess Distance", func(f Facts) (bool, string) {
+			return f.PayingOfficer.ExcessDistance, ""
+		}),
+	},
+	{
+		Name: "paying officer excess valuation",
+		Remark: payingOfficerRemarks("Excess Valuation", func(f Facts) (bool, string) {
+			return f.PayingOfficer.ExcessValuation, ""
+		}),
+	},
+	{
+		Name: "paying officer excess weight",
+		Remark: payingOfficerRemarks("Excess Weight", func(f Facts) (bool, string) {
+			if excess := f.excessWeight(); excess > 0 {
+				return f.PayingOfficer.ExcessWeight, formatPounds(excess) + " over Weight Allowance"
+			}
+			return f.PayingOfficer.ExcessWeight, ""
+		}),
+	},
+	{
+		Name: "paying officer other",
+		Remark: payingOfficerRemarks("Other", func(f Facts) (bool, string) {
+			return f.PayingOfficer.Other, ""
+		}),
+	},
+}
+
+// sitRemarks returns a rule that adds a remark for each SIT request at location
+func sitRemarks(location string, place string) func(f Facts) []string {
+	return func(f Facts) []string {
+		var remarks []string
+		for _, sit := range f.StorageInTransits {
+			if sit.Location != location {
+				continue
+			}
+			state := "Requested"
+			if sit.Authorized {
+				state = "Authorized"
+			}
+			remarks = append(remarks, fmt.Sprintf("SIT %s at %s from %s", state, place, sit.StartDate.Format("02 Jan 2006")))
+		}
+		return remarks
+	}
+}
+
+// accessorialItemRemarks returns a rule that lists the approved accessorials with one of codes
+func accessorialItemRemarks(codes []string, label string) func(f Facts) []string {
+	return func(f Facts) []string {
+		items := f.accessorialItems(true, codes...)
+		if len(items) == 0 {
+			return nil
+		}
+		return []string{label + ": " + strings.Join(items, ", ")}
+	}
+}
+
+// accessorialRemarks returns a rule that lists the accessorials that are, or are not yet, approved
+func accessorialRemarks(approved bool, label string) func(f Facts) []string {
+	return func(f Facts) []string {
+		items := f.accessorialItems(approved)
+		if len(items) == 0 {
+			return nil
+		}
+		return []string{label + ": " + strings.Join(items, ", ")}
+	}
+}
+
+// payingOfficerRemarks returns a rule that calls out a checked "For Use of Paying Officer" box. checked reports
+// whether the box is checked and whatever the facts say about why, which is added to the remark.
+func payingOfficerRemarks(box string, checked func(f Facts) (bool, string)) func(f Facts) []string {
+	return func(f Facts) []string {
+		isChecked, explanation := checked(f)
+		if !isChecked {
+			return nil
+		}
+		remark := "For Use of Paying Officer - " + box
+		if explanation != "" {
+			remark += ": " + explanation
+		}
+		return []string{remark}
+	}
+}
+
+// Remarks returns the remarks from every rule, in order
+func Remarks(f Facts) []string {
+	var remarks []string
+	for _, rule := range RemarkRules {
+		remarks = append(remarks, rule.Remark(f)...)
+	}
+	return remarks
+}
+
+// DefaultBillTo is billed when no BillToRule applies
+const DefaultBillTo = "US Bank PowerTrack\n" +
+	"Minneapolis, MN\n" +
+	"800-417-1844\n" +
+	"PowerTrack@usbank.com"
+
+// BillToRule bills charges to a party other than the default when it applies
+type BillToRule struct {
+	Name    string
+	Applies func(f Facts) bool
+	BillTo  string
+}
+
+// BillToRules are checked in order, and the first that applies decides who is billed
+var BillToRules = []BillToRule{
+	{
+		Name:    "Coast Guard",
+		Applies: func(f Facts) bool { return f.Affiliation == AffiliationCoastGuard },
+		BillTo: "USCG Finance Center\n" +
+			"PO Box 4115\n" +
+			"Chesapeake, VA 23327-4115",
+	},
+}
+
+// BillTo returns the party that the shipment's charges are billed to
+func BillTo(f Facts) string {
+	for _, rule := range BillToRules {
+		if rule.Applies(f) {
+			return rule.BillTo
+		}
+	}
+	return DefaultBillTo
+}
+
+// valuation is the full replacement protection the shipment is released at
+const valuation = "Shipment is released at full replacement protection of $6.00 times the net weight in pounds of the HHG shipment or the gross weight of the UB shipment or $5,000, whichever is greater."
+
+// Description returns the description of shipment, with its number of containers and any PBP&E
+func Description(f Facts) string {
+	containers := 0
+	for _, accessorial := range f.Accessorials {
+		if accessorial.Code == crateItemCode && accessorial.Approved {
+			containers++
+		}
+	}
+	description := fmt.Sprintf("Household Goods. Containers: %d ", containers)
+	if progear := f.ProgearWeightEstimate + f.SpouseProgearWeightEstimate; progear > 0 {
+		description += "Includes " + formatPounds(progear) + " of PBP&E. "
+	}
+	return description + valuation
+}
@@ -0,0 +1,166 @@
+package gblrules
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var sitStart = time.Date(2019, time.May, 15, 0, 0, 0, 0, time.UTC)
+
+func TestRemarkRules(t *testing.T) {
+	var remarkTests = []struct {
+		rule  string
+		facts Facts
+		want  []string
+	}{
+		{"direct delivery", Facts{}, []string{"Direct Delivery Requested"}},
+		{"direct delivery", Facts{StorageInTransits: []StorageInTransit{{Location: LocationOrigin}}}, []string{"Direct Delivery Requested"}},
+		{"direct delivery", Facts{StorageInTransits: []StorageInTransit{{Location: LocationDestination}}}, nil},
+		{"origin SIT", Facts{}, nil},
+		{"origin SIT", Facts{StorageInTransits: []StorageInTransit{{Location: LocationOrigin, StartDate: sitStart}}}, []string{"SIT Requested at Origin from 15 May 2019"}},
+		{"origin SIT", Facts{StorageInTransits: []StorageInTransit{{Location: LocationOrigin, Authorized: true, StartDate: sitStart}}}, []string{"SIT Authorized at Origin from 15 May 2019"}},
+		{"destination SIT", Facts{StorageInTransits: []StorageInTransit{{Location: LocationOrigin, StartDate: sitStart}}}, nil},
+		{"destination SIT", Facts{StorageInTransits: []StorageInTransit{{Location: LocationDestination, StartDate: sitStart}}}, []string{"SIT Requested at Destination from 15 May 2019"}},
+		{"destination SIT", Facts{StorageInTransits: []StorageInTransit{{Location: LocationDestination, Authorized: true, StartDate: sitStart}}}, []string{"SIT Authorized at Destination from 15 May 2019"}},
+		{"extra pickup", Facts{}, nil},
+		{"extra pickup", Facts{HasSecondaryPickupAddress: true}, []string{"Extra Pickup Authorized at Secondary Pickup Address"}},
+		{"extra delivery", Facts{}, nil},
+		{"extra delivery", Facts{HasPartialSITDeliveryAddress: true}, []string{"Extra Delivery Authorized: Partial Delivery out of SIT"}},
+		{"delivery address", Facts{}, nil},
+		{"delivery address", Facts{HasDeliveryAddress: true}, []string{"Deliver to Member's Delivery Address, Not New Duty Station"}},
+		{"member PBP&E", Facts{}, nil},
+		{"member PBP&E", Facts{ProgearWeightEstimate: 1500}, []string{"PBP&E: 1,500 lbs"}},
+		{"spouse PBP&E", Facts{}, nil},
+		{"spouse PBP&E", Facts{SpouseProgearWeightEstimate: 500}, []string{"Spouse PBP&E: 500 lbs"}},
+		{"weight allowance", Facts{}, nil},
+		{"weight allowance", Facts{WeightAllowance: 11000}, []string{"Weight Allowance: 11,000 lbs"}},
+		{"excess weight", Facts{WeightEstimate: 9000}, nil},
+		{"excess weight", Facts{WeightAllowance: 11000, WeightEstimate: 11000}, nil},
+		{"excess weight", Facts{WeightAllowance: 11000, WeightEstimate: 12500}, []string{"Estimated Weight Exceeds Weight Allowance by 1,500 lbs. Member Is Liable for Excess Costs"}},
+		{"reweigh", Facts{Accessorials: []Accessorial{{Code: "4B", Item: "Reweigh"}}}, nil},
+		{"reweigh", Facts{Accessorials: []Accessorial{{Code: "4A", Item: "Reweigh", Approved: true}}}, []string{"Reweigh Requested"}},
+		{"shuttle service", Facts{Accessorials: []Accessorial{{Code: "125A", Item: "Shuttle Service 25 or less miles"}}}, nil},
+		{"shuttle service", Facts{Accessorials: []Accessorial{
+			{Code: "125B", Item: "Shuttle Service Over 25 Miles", Approved: true},
+			{Code: "105B", Item: "Pack Reg Crate", Approved: true},
+		}}, []string{"Shuttle Service Authorized: 125B Shuttle Service Over 25 Miles"}},
+		{"bulky articles", Facts{Accessorials: []Accessorial{{Code: "130A", Item: "Bulky Article: Automobile/Truck"}}}, nil},
+		{"bulky articles", Facts{Accessorials: []Accessorial{
+			{Code: "130A", Item: "Bulky Article: Automobile/Truck", Approved: true},
+			{Code: "130H", Item: "Bulky Article: Grand Piano", Approved: true},
+		}}, []string{"Bulky Articles Authorized: 130A Bulky Article: Automobile/Truck, 130H Bulky Article: Grand Piano"}},
+		{"third party service", Facts{Accessorials: []Accessorial{{Code: "35A", Item: "Third Party Service"}}}, nil},
+		{"third party service", Facts{Accessorials: []Accessorial{{Code: "35A", Item: "Third Party Service", Approved: true}}}, []string{"Third Party Service Authorized: 35A Third Party Service"}},
+		{"approved accessorials", Facts{Accessorials: []Accessorial{{Code: "105B", Item: "Pack Reg Crate"}}}, nil},
+		{"approved accessorials", Facts{Accessorials: []Accessorial{
+			{Code: "105B", Item: "Pack Reg Crate", Approved: true},
+			{Code: "35A", Item: "Third Party Service", Approved: true},
+		}}, []string{"Approved Accessorials: 105B Pack Reg Crate, 35A Third Party Service"}},
+		{"pending accessorials", Facts{Accessorials: []Accessorial{{Code: "105B", Item: "Pack Reg Crate", Approved: true}}}, nil},
+		{"pending accessorials", Facts{Accessorials: []Accessorial{{Code: "4A", Item: "Reweigh"}}}, []string{"Accessorials Pending Approval: 4A Reweigh"}},
+		{"paying officer unauthorized items", Facts{Accessorials: []Accessorial{{Code: "4A", Item: "Reweigh"}}}, nil},
+		{"paying officer unauthorized items", Facts{
+			PayingOfficer: PayingOfficerFlags{UnauthorizedItems: true},
+			Accessorials:  []Accessorial{{Code: "4A", Item: "Reweigh"}},
+		}, []string{"For Use of Paying Officer - Unauthorized Items: 4A Reweigh"}},
+		{"paying officer excess distance", Facts{}, nil},
+		{"paying officer excess distance", Facts{PayingOfficer: PayingOfficerFlags{ExcessDistance: true}}, []string{"For Use of Paying Officer - Excess Distance"}},
+		{"paying officer excess valuation", Facts{}, nil},
+		{"paying officer excess valuation", Facts{PayingOfficer: PayingOfficerFlags{ExcessValuation: true}}, []string{"For Use of Paying Officer - Excess Valuation"}},
+		{"paying officer excess weight", Facts{WeightAllowance: 11000, WeightEstimate: 12500}, nil},
+		{"paying officer excess weight", Facts{PayingOfficer: PayingOfficerFlags{ExcessWeight: true}}, []string{"For Use of Paying Officer - Excess Weight"}},
+		{"paying officer excess weight", Facts{
+			PayingOfficer:   PayingOfficerFlags{ExcessWeight: true},
+			WeightAllowance: 11000,
+			WeightEstimate:  12500,
+		}, []string{"For Use of Paying Officer - Excess Weight: 1,500 lbs over Weight Allowance"}},
+		{"paying officer other", Facts{}, nil},
+		{"paying officer other", Facts{PayingOfficer: PayingOfficerFlags{Other: true}}, []string{"For Use of Paying Officer - Other"}},
+	}
+
+	rules := map[string]RemarkRule{}
+	for _, rule := range RemarkRules {
+		rules[rule.Name] = rule
+	}
+	remarked := map[string]bool{}
+	for _, tt := range remarkTests {
+		rule, ok := rules[tt.rule]
+		if !ok {
+			t.Fatalf("no remark rule named %q", tt.rule)
+		}
+		if got := rule.Remark(tt.facts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.rule, got, tt.want)
+		}
+		if tt.want != nil {
+			remarked[tt.rule] = true
+		}
+	}
+	for name := range rules {
+		if !remarked[name] {
+			t.Errorf("no test case for a remark from rule %q", name)
+		}
+	}
+}
+
+func TestRemarks(t *testing.T) {
+	facts := Facts{
+		HasSecondaryPickupAddress: true,
+		WeightAllowance:           8000,
+		StorageInTransits: []StorageInTransit{
+			{Location: LocationDestination, Authorized: true, StartDate: sitStart},
+			{Location: LocationOrigin, StartDate: sitStart},
+		},
+	}
+	want := []string{
+		"SIT Requested at Origin from 15 May 2019",
+		"SIT Authorized at Destination from 15 May 2019",
+		"Extra Pickup Authorized at Secondary Pickup Address",
+		"Weight Allowance: 8,000 lbs",
+	}
+	if got := Remarks(facts); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestBillTo(t *testing.T) {
+	var billToTests = []struct {
+		affiliation string
+		want        string
+	}{
+		{"ARMY", DefaultBillTo},
+		{"AIR_FORCE", DefaultBillTo},
+		{"", DefaultBillTo},
+		{AffiliationCoastGuard, "USCG Finance Center\nPO Box 4115\nChesapeake, VA 23327-4115"},
+	}
+	for _, tt := range billToTests {
+		if got := BillTo(Facts{Affiliation: tt.affiliation}); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.affiliation, got, tt.want)
+		}
+	}
+}
+
+func TestDescription(t *testing.T) {
+	if got, want := Description(Facts{}), "Household Goods. Containers: 0 "+valuation; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	facts := Facts{
+		ProgearWeightEstimate:       1500,
+		SpouseProgearWeightEstimate: 500,
+		Accessorials: []Accessorial{
+			{Code: "105B", Approved: true},
+			{Code: "105B", Approved: true},
+			{Code: "105B"},
+			{Code: "105E", Approved: true},
+		},
+	}
+	got := Description(facts)
+	if !strings.HasPrefix(got, "Household Goods. Containers: 2 Includes 2,000 lbs of PBP&E. ") {
+		t.Errorf("got %q", got)
+	}
+	if !strings.HasSuffix(got, valuation) {
+		t.Errorf("got %q, missing valuation", got)
+	}
+}
@@ -0,0 +1,182 @@
+package paperwork
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/transcom/mymove/pkg/assets"
+)
+
+// layoutDir is where form layouts and their template images are embedded in pkg/assets
+const layoutDir = "pkg/paperwork/formtemplates"
+
+// letterHeightPageMm is the height of a letter size page
+const letterHeightPageMm float64 = 279.4
+
+// Names of the form layouts embedded in pkg/assets, to be loaded with LoadFormLayoutAsset
+const (
+	// Form1203LayoutName is the layout and template of a 1203 form
+	Form1203LayoutName = "form1203.yaml"
+	// ShipmentSummaryPage1LayoutName is the layout and template of page 1 of a Shipment Summary Worksheet
+	ShipmentSummaryPage1LayoutName = "shipment_summary_worksheet_page1.yaml"
+	// ShipmentSummaryPage2LayoutName is the layout and template of page 2 of a Shipment Summary Worksheet
+	ShipmentSummaryPage2LayoutName = "shipment_summary_worksheet_page2.yaml"
+	// ShipmentSummaryContinuationLayoutName is the layout and template of the Shipment Summary Worksheet pages
+	// listing shipments that don't fit on page 1
+	ShipmentSummaryContinuationLayoutName = "shipment_summary_worksheet_continuation.yaml"
+)
+
+// formLayoutFile is a form layout as it is written in a YAML or JSON layout file
+type formLayoutFile struct {
+	// TemplateImage is the path of the background image, relative to the layout file
+	TemplateImage string `yaml:"template_image"`
+	// TemplatePDF is the path of a fillable PDF, relative to the layout file
+	TemplatePDF string `yaml:"template_pdf"`
+	// Flatten draws the filled in fields of a fillable PDF onto its pages, so the form can't be edited
+	Flatten bool                    `yaml:"flatten"`
+	Fields  map[string]fieldPosFile `yaml:"fields"`
+}
+
+type fieldPosFile struct {
+	X          *float64 `yaml:"x"`
+	Y          *float64 `yaml:"y"`
+	Width      float64  `yaml:"width"`
+	FontSize   *float64 `yaml:"font_size"`
+	LineHeight *float64 `yaml:"line_height"`
+	Align      *string  `yaml:"align"`
+	// PDFField is the name of the field in a fillable PDF, if it differs from the name of the data field
+	PDFField string `yaml:"pdf_field"`
+}
+
+// ParseFormLayout reads a form layout in YAML or JSON from r and validates it. The layout's template image path
+// is resolved relative to dir.
+func ParseFormLayout(r io.Reader, dir string) (FormLayout, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return FormLayout{}, errors.Wrap(err, "could not read form layout")
+	}
+
+	// JSON is valid YAML, so one decoder reads both
+	var file formLayoutFile
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return FormLayout{}, errors.Wrap(err, "could not parse form layout")
+	}
+
+	if file.Flatten && file.TemplatePDF == "" {
+		return FormLayout{}, errors.New("only layouts with a template PDF can be flattened")
+	}
+
+	layout := FormLayout{
+		Flatten:      file.Flatten,
+		FieldsLayout: map[string]FieldPos{},
+	}
+	if file.TemplateImage != "" {
+		layout.TemplateImagePath = path.Join(dir, file.TemplateImage)
+	}
+	if file.TemplatePDF != "" {
+		layout.TemplatePDFPath = path.Join(dir, file.TemplatePDF)
+	}
+	for name, field := range file.Fields {
+		if layout.TemplatePDFPath != "" {
+			// Fields of a fillable PDF are positioned by the PDF itself
+			if field.X != nil || field.Y != nil || field.Width != 0 || field.LineHeight != nil {
+				return FormLayout{}, errors.Errorf("field %s is positioned by the PDF, so can't have a position, width or line height", name)
+			}
+			layout.FieldsLayout[name] = AcroFormField(field.PDFField, field.FontSize, field.Align)
+			continue
+		}
+		if field.X == nil || field.Y == nil {
+			return FormLayout{}, errors.Errorf("field %s must have an x and y position", name)
+		}
+		if field.PDFField != "" {
+			return FormLayout{}, errors.Errorf("field %s has a PDF field name, but the layout doesn't have a template PDF", name)
+		}
+		layout.FieldsLayout[name] = FormField(*field.X, *field.Y, field.Width, field.FontSize, field.LineHeight, field.Align)
+	}
+
+	if err := layout.Validate(); err != nil {
+		return FormLayout{}, err
+	}
+	return layout, nil
+}
+
+// LoadFormLayout reads and validates the form layout file at layoutPath
+func LoadFormLayout(layoutPath string) (FormLayout, error) {
+	content, err := ioutil.ReadFile(layoutPath)
+	if err != nil {
+		return FormLayout{}, errors.Wrap(err, "could not read form layout")
+	}
+	layout, err := ParseFormLayout(bytes.NewReader(content), path.Dir(layoutPath))
+	return layout, errors.Wrapf(err, "invalid form layout %s", layoutPath)
+}
+
+// LoadFormLayoutAsset reads and validates a form layout embedded in pkg/assets, such as Form1203LayoutName
+func LoadFormLayoutAsset(name string) (FormLayout, error) {
+	content, err := assets.Asset(path.Join(layoutDir, name))
+	if err != nil {
+		return FormLayout{}, errors.Wrapf(err, "could not read form layout %s", name)
+	}
+	layout, err := ParseFormLayout(bytes.NewReader(content), layoutDir)
+	return layout, errors.Wrapf(err, "invalid form layout %s", name)
+}
+
+// Validate checks that a layout has a template image or PDF and that every field is on the page and can be drawn
+func (l FormLayout) Validate() error {
+	if (l.TemplateImagePath == "") == (l.TemplatePDFPath == "") {
+		return errors.New("form layout must have either a template image or a template PDF")
+	}
+	if len(l.FieldsLayout) == 0 {
+		return errors.New("form layout must have at least one field")
+	}
+
+	// Report problems in a consistent order
+	names := make([]string, 0, len(l.FieldsLayout))
+	for name := range l.FieldsLayout {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		if err := l.FieldsLayout[name].validate(l.TemplatePDFPath != ""); err != nil {
+			problems = append(problems, name+": "+err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return errors.Errorf("invalid fields in form layout: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validate checks a field's formatting, and unless it is filled into a fillable PDF, its position
+func (p FieldPos) validate(fillable bool) error {
+	if !fillable {
+		if p.xPos < 0 || p.xPos >= letterWidthMm || p.yPos < 0 || p.yPos >= letterHeightPageMm {
+			return errors.Errorf("position %g, %g is not on the page", p.xPos, p.yPos)
+		}
+		if p.width <= 0 {
+			return errors.New("width must be greater than 0")
+		}
+	}
+	if p.fontSize != nil && *p.fontSize <= 0 {
+		return errors.New("font size must be greater than 0")
+	}
+	if p.lineHeight != nil && *p.lineHeight <= 0 {
+		return errors.New("line height must be greater than 0")
+	}
+	if p.alignStr != nil && !validAlignment(*p.alignStr) {
+		return errors.Errorf("alignment %q must be one of L, C or R followed by one of T, M, B or A", *p.alignStr)
+	}
+	return nil
+}
+
+func validAlignment(alignStr string) bool {
+	return len(alignStr) == 2 && strings.ContainsAny(alignStr[:1], "LCR") && strings.ContainsAny(alignStr[1:], "TMBA")
+}
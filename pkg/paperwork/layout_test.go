@@ -0,0 +1,129 @@
+package paperwork
+
+import (
+	"os"
+	"strings"
+)
+
+func (suite *PaperworkSuite) TestEmbeddedFormLayoutsAreValid() {
+	names := []string{Form1203LayoutName, ShipmentSummaryPage1LayoutName, ShipmentSummaryPage2LayoutName, ShipmentSummaryContinuationLayoutName}
+	for _, name := range names {
+		layout, err := LoadFormLayoutAsset(name)
+		suite.NoError(err, name)
+		suite.True(strings.HasPrefix(layout.TemplateImagePath, layoutDir), name)
+	}
+
+	form1203Layout, err := LoadFormLayoutAsset(Form1203LayoutName)
+	suite.FatalNil(err)
+	suite.Equal("pkg/paperwork/formtemplates/form1203template.png", form1203Layout.TemplateImagePath)
+	suite.Contains(form1203Layout.FieldsLayout, "GBLNumber1")
+}
+
+func (suite *PaperworkSuite) TestLoadFormLayoutAssetMissing() {
+	_, err := LoadFormLayoutAsset("not_a_form.yaml")
+	suite.Error(err)
+}
+
+func (suite *PaperworkSuite) TestParseFormLayout() {
+	layout, err := ParseFormLayout(strings.NewReader(`
+template_image: example_template.png
+fields:
+  FieldName: {x: 28, y: 11, width: 79, font_size: 8, align: "RM"}
+`), "testdata")
+	suite.FatalNil(err)
+
+	suite.Equal("testdata/example_template.png", layout.TemplateImagePath)
+	field := layout.FieldsLayout["FieldName"]
+	suite.Equal(28.0, field.xPos)
+	suite.Equal(11.0, field.yPos)
+	suite.Equal(79.0, field.width)
+	suite.Equal(8.0, *field.fontSize)
+	suite.Nil(field.lineHeight)
+	suite.Equal("RM", *field.alignStr)
+}
+
+func (suite *PaperworkSuite) TestParseFormLayoutJSON() {
+	layout, err := ParseFormLayout(strings.NewReader(`{
+	"template_image": "example_template.png",
+	"fields": {"FieldName": {"x": 28, "y": 11, "width": 79}}
+}`), "testdata")
+	suite.FatalNil(err)
+	suite.Len(layout.FieldsLayout, 1)
+}
+
+func (suite *PaperworkSuite) TestParseFormLayoutTemplatePDF() {
+	layout, err := ParseFormLayout(strings.NewReader(`
+template_pdf: acroform_template.pdf
+fields:
+  Name: {pdf_field: "name", font_size: 10}
+  certified: {}
+`), "testdata")
+	suite.FatalNil(err)
+
+	suite.Equal("testdata/acroform_template.pdf", layout.TemplatePDFPath)
+	suite.Empty(layout.TemplateImagePath)
+	suite.False(layout.Flatten)
+	suite.Equal("name", layout.FieldsLayout["Name"].pdfField)
+	suite.Equal(10.0, *layout.FieldsLayout["Name"].fontSize)
+	suite.Empty(layout.FieldsLayout["certified"].pdfField)
+}
+
+func (suite *PaperworkSuite) TestParseFormLayoutFlatten() {
+	layout, err := ParseFormLayout(strings.NewReader(`
+template_pdf: acroform_template.pdf
+flatten: true
+fields:
+  Name: {pdf_field: "name"}
+`), "testdata")
+	suite.FatalNil(err)
+	suite.True(layout.Flatten)
+}
+
+func (suite *PaperworkSuite) TestParseFormLayoutRejectsInvalidLayouts() {
+	tests := map[string]string{
+		"unknown key":        "template_image: a.png\nfields:\n  F: {x: 1, y: 1, width: 1, colour: red}\n",
+		"missing position":   "template_image: a.png\nfields:\n  F: {x: 1, width: 1}\n",
+		"missing image":      "fields:\n  F: {x: 1, y: 1, width: 1}\n",
+		"no fields":          "template_image: a.png\n",
+		"off the page":       "template_image: a.png\nfields:\n  F: {x: 1, y: 300, width: 1}\n",
+		"no width":           "template_image: a.png\nfields:\n  F: {x: 1, y: 1}\n",
+		"bad font size":      "template_image: a.png\nfields:\n  F: {x: 1, y: 1, width: 1, font_size: 0}\n",
+		"bad alignment":      "template_image: a.png\nfields:\n  F: {x: 1, y: 1, width: 1, align: \"MR\"}\n",
+		"malformed document": "template_image: [a.png\n",
+		"two templates":      "template_image: a.png\ntemplate_pdf: a.pdf\nfields:\n  F: {x: 1, y: 1, width: 1}\n",
+		"positioned pdf":     "template_pdf: a.pdf\nfields:\n  F: {x: 1, y: 1}\n",
+		"pdf field on image": "template_image: a.png\nfields:\n  F: {x: 1, y: 1, width: 1, pdf_field: f}\n",
+		"flattened image":    "template_image: a.png\nflatten: true\nfields:\n  F: {x: 1, y: 1, width: 1}\n",
+	}
+	for name, content := range tests {
+		_, err := ParseFormLayout(strings.NewReader(content), "")
+		suite.Error(err, name)
+	}
+}
+
+func (suite *PaperworkSuite) TestFormFillerWithMapData() {
+	f, err := os.Open("./testdata/example_template.png")
+	suite.FatalNil(err)
+	defer f.Close()
+
+	fields := map[string]FieldPos{
+		"FieldName": FormField(28, 11, 79, nil, nil, nil),
+	}
+
+	formFiller := NewFormFiller()
+	suite.NoError(formFiller.AppendPage(f, fields, map[string]string{"FieldName": "Data goes here"}))
+}
+
+func (suite *PaperworkSuite) TestFormFillerMissingDataField() {
+	f, err := os.Open("./testdata/example_template.png")
+	suite.FatalNil(err)
+	defer f.Close()
+
+	fields := map[string]FieldPos{
+		"NotInTheModel": FormField(28, 11, 79, nil, nil, nil),
+	}
+
+	formFiller := NewFormFiller()
+	err = formFiller.AppendPage(f, fields, fakeModel{FieldName: "Data goes here"})
+	suite.Error(err)
+}
@@ -66,10 +66,13 @@ func TestPaperworkSuite(t *testing.T) {
 	storer := storageTest.NewFakeS3Storage(true)
 
 	popSuite := testingsuite.NewPopTestSuite(testingsuite.CurrentPackage())
+	// Test fixtures don't need to wait for a virus scan before they can be downloaded
+	up := uploader.NewUploader(popSuite.DB(), logger, storer)
+	up.SetTrusted(true)
 	hs := &PaperworkSuite{
 		PopTestSuite: popSuite,
 		logger:       logger,
-		uploader:     uploader.NewUploader(popSuite.DB(), logger, storer),
+		uploader:     up,
 	}
 
 	suite.Run(t, hs)
@@ -30,13 +30,27 @@ func NewSSWPPMComputer(PPMComputer ppmComputer) *SSWPPMComputer {
 //ObligationType type corresponding to obligation sections of shipment summary worksheet
 type ObligationType int
 
-//ComputeObligations is helper function for computing the obligations section of the shipment summary worksheet
+//ComputeObligations is helper function for computing the obligations section of the shipment summary worksheet.
+//The max obligation is for moving the whole weight allotment along the first PPM's route. The actual obligation is
+//the sum of each PPM's obligation, with the remaining PPM entitlement shared between the PPMs in order. Only PPMs
+//that count toward the entitlement are included, and those that aren't paid for any weight don't add to the actual
+//obligation.
 func (sswPpmComputer *SSWPPMComputer) ComputeObligations(ssfd models.ShipmentSummaryFormData, planner route.Planner) (obligation models.Obligations, err error) {
-	firstPPM, err := sswPpmComputer.nilCheckPPM(ssfd)
-
+	var ppms models.PersonallyProcuredMoves
+	for _, ppm := range ssfd.PersonallyProcuredMoves {
+		if ppm.CountsTowardEntitlement() {
+			ppms = append(ppms, ppm)
+		}
+	}
+	if len(ppms) == 0 {
+		return models.Obligations{}, errors.New("missing ppm")
+	}
+	firstPPM := ppms[0]
+	err = nilCheckPPM(firstPPM)
 	if err != nil {
 		return models.Obligations{}, err
 	}
+
 	distanceMiles, err := planner.Zip5TransitDistance(*firstPPM.PickupPostalCode, *firstPPM.DestinationPostalCode)
 	if err != nil {
 		return models.Obligations{}, errors.New("error calculating distance")
@@ -52,40 +66,67 @@ func (sswPpmComputer *SSWPPMComputer) ComputeObligations(ssfd models.ShipmentSum
 	if err != nil {
 		return models.Obligations{}, errors.New("error calculating PPM max obligations")
 	}
-	actualCost, err := sswPpmComputer.ComputePPMIncludingLHDiscount(
-		ssfd.PPMRemainingEntitlement,
-		*firstPPM.PickupPostalCode,
-		*firstPPM.DestinationPostalCode,
-		distanceMiles,
-		*firstPPM.ActualMoveDate,
-		0,
-	)
-	if err != nil {
-		return models.Obligations{}, errors.New("error calculating PPM actual obligations")
-	}
-	var actualSIT unit.Cents
-	if firstPPM.TotalSITCost != nil {
-		actualSIT = *firstPPM.TotalSITCost
+
+	var actualGCC, actualSIT unit.Cents
+	remainingEntitlement := ssfd.PPMRemainingEntitlement
+	for i, ppm := range ppms {
+		// Each PPM is paid for up to its own weight, and the last PPM gets whatever is left
+		weight := remainingEntitlement
+		if i < len(ppms)-1 {
+			var ppmWeight unit.Pound
+			if ppm.NetWeight != nil {
+				ppmWeight = *ppm.NetWeight
+			}
+			if ppmWeight < weight {
+				weight = ppmWeight
+			}
+		}
+		remainingEntitlement -= weight
+		if ppm.TotalSITCost != nil {
+			actualSIT += *ppm.TotalSITCost
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		err = nilCheckPPM(ppm)
+		if err != nil {
+			return models.Obligations{}, err
+		}
+		if i > 0 {
+			distanceMiles, err = planner.Zip5TransitDistance(*ppm.PickupPostalCode, *ppm.DestinationPostalCode)
+			if err != nil {
+				return models.Obligations{}, errors.New("error calculating distance")
+			}
+		}
+		actualCost, err := sswPpmComputer.ComputePPMIncludingLHDiscount(
+			weight,
+			*ppm.PickupPostalCode,
+			*ppm.DestinationPostalCode,
+			distanceMiles,
+			*ppm.ActualMoveDate,
+			0,
+		)
+		if err != nil {
+			return models.Obligations{}, errors.New("error calculating PPM actual obligations")
+		}
+		actualGCC += actualCost.GCC
 	}
 	if actualSIT > maxCost.SITMax {
 		actualSIT = maxCost.SITMax
 	}
 	maxObligation := models.Obligation{Gcc: maxCost.GCC, SIT: maxCost.SITMax}
-	actualObligation := models.Obligation{Gcc: actualCost.GCC, SIT: actualSIT}
+	actualObligation := models.Obligation{Gcc: actualGCC, SIT: actualSIT}
 	obligations := models.Obligations{MaxObligation: maxObligation, ActualObligation: actualObligation}
 	return obligations, nil
 }
 
-func (sswPpmComputer *SSWPPMComputer) nilCheckPPM(ssfd models.ShipmentSummaryFormData) (models.PersonallyProcuredMove, error) {
-	if len(ssfd.PersonallyProcuredMoves) == 0 {
-		return models.PersonallyProcuredMove{}, errors.New("missing ppm")
-	}
-	firstPPM := ssfd.PersonallyProcuredMoves[0]
-	if firstPPM.PickupPostalCode == nil || firstPPM.DestinationPostalCode == nil {
-		return models.PersonallyProcuredMove{}, errors.New("missing required address parameter")
+func nilCheckPPM(ppm models.PersonallyProcuredMove) error {
+	if ppm.PickupPostalCode == nil || ppm.DestinationPostalCode == nil {
+		return errors.New("missing required address parameter")
 	}
-	if firstPPM.ActualMoveDate == nil {
-		return models.PersonallyProcuredMove{}, errors.New("missing required actual move date parameter")
+	if ppm.ActualMoveDate == nil {
+		return errors.New("missing required actual move date parameter")
 	}
-	return firstPPM, nil
+	return nil
 }
@@ -147,6 +147,104 @@ func (suite *PaperworkSuite) TestTestComputeObligations() {
 		suite.Equal(unit.Cents(0), obligations.ActualObligation.SIT)
 	})
 
+	suite.Run("TestComputeObligations with multiple PPMs", func() {
+		secondPickupPostalCode := "90210"
+		secondActualDate := time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC)
+		firstWeight := unit.Pound(500)
+		secondWeight := unit.Pound(3000)
+		secondSIT := unit.Cents(300)
+		firstPPM := ppm
+		firstPPM.NetWeight = &firstWeight
+		secondPPM := models.PersonallyProcuredMove{
+			ActualMoveDate:        &secondActualDate,
+			PickupPostalCode:      &secondPickupPostalCode,
+			DestinationPostalCode: &destinationPostalCode,
+			NetWeight:             &secondWeight,
+			TotalSITCost:          &secondSIT,
+		}
+		multiplePPMParams := params
+		multiplePPMParams.PersonallyProcuredMoves = models.PersonallyProcuredMoves{firstPPM, secondPPM}
+		mockComputer := mockPPMComputer{
+			costComputation: rateengine.CostComputation{GCC: 100, SITMax: 20000},
+		}
+		ppmComputer := NewSSWPPMComputer(&mockComputer)
+
+		obligations, err := ppmComputer.ComputeObligations(multiplePPMParams, planner)
+
+		suite.NoError(err)
+		calledWith := mockComputer.CalledWith()
+		suite.Require().Len(calledWith, 3)
+		// The first PPM is paid for its own weight, and the second PPM the rest of the remaining entitlement
+		suite.Equal(firstWeight, calledWith[1].Weight)
+		suite.Equal(ppmRemainingEntitlement-firstWeight, calledWith[2].Weight)
+		suite.Equal(secondPickupPostalCode, calledWith[2].OriginZip5)
+		suite.Equal(secondActualDate, calledWith[2].Date)
+		suite.Equal(unit.Cents(200), obligations.ActualObligation.Gcc)
+		suite.Equal(unit.Cents(100), obligations.MaxObligation.Gcc)
+		suite.Equal(*ppm.TotalSITCost+secondSIT, obligations.ActualObligation.SIT)
+	})
+
+	suite.Run("TestComputeObligations when a later PPM is missing required parameters", func() {
+		multiplePPMParams := params
+		multiplePPMParams.PersonallyProcuredMoves = models.PersonallyProcuredMoves{ppm, {}}
+		ppmComputer := NewSSWPPMComputer(&mockPPMComputer{})
+
+		_, err := ppmComputer.ComputeObligations(multiplePPMParams, planner)
+
+		suite.Error(err)
+	})
+
+	suite.Run("TestComputeObligations skips PPMs that aren't paid for any weight", func() {
+		secondWeight := unit.Pound(500)
+		firstWeight := unit.Pound(0)
+		firstPPM := ppm
+		firstPPM.NetWeight = &firstWeight
+		secondPPM := ppm
+		secondPPM.NetWeight = &secondWeight
+		// The entitlement is used up before the last PPM, so it isn't priced and may be missing its addresses
+		thirdPPM := models.PersonallyProcuredMove{}
+		zeroWeightParams := params
+		zeroWeightParams.PPMRemainingEntitlement = secondWeight
+		zeroWeightParams.PersonallyProcuredMoves = models.PersonallyProcuredMoves{firstPPM, secondPPM, thirdPPM}
+		mockComputer := mockPPMComputer{
+			costComputation: rateengine.CostComputation{GCC: 100, SITMax: 20000},
+		}
+		ppmComputer := NewSSWPPMComputer(&mockComputer)
+
+		obligations, err := ppmComputer.ComputeObligations(zeroWeightParams, planner)
+
+		suite.NoError(err)
+		calledWith := mockComputer.CalledWith()
+		suite.Require().Len(calledWith, 2)
+		suite.Equal(secondWeight, calledWith[1].Weight)
+		suite.Equal(unit.Cents(100), obligations.ActualObligation.Gcc)
+	})
+
+	suite.Run("TestComputeObligations skips canceled and draft PPMs", func() {
+		canceledPPM := models.PersonallyProcuredMove{Status: models.PPMStatusCANCELED}
+		draftPPM := models.PersonallyProcuredMove{Status: models.PPMStatusDRAFT}
+		statusParams := params
+		statusParams.PersonallyProcuredMoves = models.PersonallyProcuredMoves{canceledPPM, ppm, draftPPM}
+		mockComputer := mockPPMComputer{
+			costComputation: rateengine.CostComputation{GCC: 100, SITMax: 20000},
+		}
+		ppmComputer := NewSSWPPMComputer(&mockComputer)
+
+		obligations, err := ppmComputer.ComputeObligations(statusParams, planner)
+
+		suite.NoError(err)
+		calledWith := mockComputer.CalledWith()
+		suite.Require().Len(calledWith, 2)
+		// The max obligation follows the first PPM that counts, and the last one that counts gets what's left
+		suite.Equal(pickupPostalCode, calledWith[0].OriginZip5)
+		suite.Equal(ppmRemainingEntitlement, calledWith[1].Weight)
+		suite.Equal(*ppm.TotalSITCost, obligations.ActualObligation.SIT)
+
+		statusParams.PersonallyProcuredMoves = models.PersonallyProcuredMoves{canceledPPM, draftPPM}
+		_, err = ppmComputer.ComputeObligations(statusParams, planner)
+		suite.Error(err)
+	})
+
 	suite.Run("TestCalcError", func() {
 		mockComputer := mockPPMComputer{err: errors.New("ERROR")}
 		ppmComputer := SSWPPMComputer{&mockComputer}
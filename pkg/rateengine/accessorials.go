@@ -1,9 +1,10 @@
 package rateengine
 
 import (
+	"fmt"
+
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
-	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/unit"
@@ -14,17 +15,19 @@ var tariff400ngItemPricing = map[string]pricer{
 	"4A": newBasicQuantityPricer(),
 	"4B": newBasicQuantityPricer(),
 
-	// Below are SIT-related codes, uncomment after SIT is implemented
+	// Fuel surcharge on SIT pickup or delivery over 50 miles, priced as a percentage of the base linehaul rate
+	"16B": newFlatRatePricer(),
+
 	// Attempted delivery from SIT
-	// "17A": newFlatRatePricer(),
-	// "17B": newFlatRatePricer(),
-	// Priced using base linehaul rate, needs more work
-	// "17C": newFlatRatePricer(),
-	// "17D": newMinimumQuantityHundredweightPricer(1000),
-	// "17E": newFlatRatePricer(),
-	// "17F": newFlatRatePricer(),
-	// Priced using base linehaul rate, needs more work
-	// "17G": newFlatRatePricer(),
+	"17A": newFlatRatePricer(),
+	"17B": newFlatRatePricer(),
+	// Priced using base linehaul rate
+	"17C": newFlatRatePricer(),
+	"17D": newMinimumQuantityHundredweightPricer(1000),
+	"17E": newFlatRatePricer(),
+	"17F": newFlatRatePricer(),
+	// Priced using intra-Alaska base linehaul rate
+	"17G": newFlatRatePricer(),
 
 	// Extra pickups, diversions
 	"28A": newBasicQuantityPricer(),
@@ -75,19 +78,21 @@ var tariff400ngItemPricing = map[string]pricer{
 	// otherwise TSP is limited to billing 1,000 lbs."
 	"175A": newMinimumQuantityPricer(1000),
 
-	// Below are SIT-related codes, uncomment after SIT is implemented
-	// SIT
-	// "185A": newMinimumQuantityHundredweightPricer(1000),
-	// Priced using two quantities (weight and days), needs more work
-	// "185B": newMinimumQuantityHundredweightPricer(1000),
+	// SIT first day and warehouse handling
+	"185A": newMinimumQuantityHundredweightPricer(1000),
+	// SIT additional days, priced using two quantities (days and weight)
+	"185B": newAdditionalDaysHundredweightPricer(1000),
 
-	// Below are SIT-related codes, uncomment after SIT is implemented
-	// SIT P/D OT
+	// SIT P/D
 	"210A": newFlatRatePricer(),
 	"210B": newFlatRatePricer(),
+	// Priced using base linehaul rate
 	"210C": newFlatRatePricer(),
-	//"210D": newFlatRatePricer(),
-	//"210E": newFlatRatePricer(),
+	// SIT P/D OT
+	"210D": newFlatRatePricer(),
+	"210E": newFlatRatePricer(),
+	// Priced using intra-Alaska base linehaul rate
+	"210F": newFlatRatePricer(),
 
 	// Pickup/delivery at third-party and self-storage warehouses
 	"225A": newFlatRatePricer(),
@@ -97,20 +102,54 @@ var tariff400ngItemPricing = map[string]pricer{
 	"226A": newBasicQuantityPricer(),
 }
 
+// When only part of a shipment is placed in SIT (a split delivery), the 1,000 lb minimum weight doesn't apply to the
+// charges for storing it
+var tariff400ngSplitDeliveryItemPricing = map[string]pricer{
+	"185A": newMinimumQuantityHundredweightPricer(0),
+	"185B": newAdditionalDaysHundredweightPricer(0),
+}
+
 // Some codes (17, mainly) are explicitly priced using rates corresponding to a different item code
 var tariff400ngItemRateMap = map[string]string{
 	"17A": "210A",
+	"17B": "210B",
 	"17D": "185A",
 	"17E": "210D",
+	"17F": "210E",
+}
+
+// These codes are priced using the base linehaul rate of the given type for the mileage in their first quantity
+var tariff400ngLinehaulRateItems = map[string]string{
+	"17C":  models.Tariff400ngLinehaulRateTypeCONUS,
+	"17G":  models.Tariff400ngLinehaulRateTypeINTRAALASKA,
+	"210C": models.Tariff400ngLinehaulRateTypeCONUS,
+	"210F": models.Tariff400ngLinehaulRateTypeINTRAALASKA,
 }
 
-// These codes have charges based on weight, which will use the final measured shipment weight
+// The linehaul rate tables start at this weight, so lighter shipments are priced at it
+const minimumLinehaulRateWeight = unit.Pound(1000)
+
+// These codes use rates for the SIT P/D schedule of the service area, rather than its services schedule
+var tariff400ngSITPDScheduleItems = map[string]bool{
+	"210A": true,
+	"210B": true,
+	"210D": true,
+	"210E": true,
+}
+
+// These codes have charges based on weight, which will use the final measured shipment weight, or for line items for
+// a SIT, the weight placed in SIT
 var tariff400ngWeightBasedItems = map[string]bool{
 	"17D":  true,
 	"175A": true,
 	"185A": true,
 }
 
+// These codes have charges based on a number of days in their first quantity and weight in their second
+var tariff400ngDaysAndWeightBasedItems = map[string]bool{
+	"185B": true,
+}
+
 // ComputeShipmentLineItemCharge calculates the total charge for a supplied shipment line item and returns it and the DISCOUNTED rate
 func (re *RateEngine) ComputeShipmentLineItemCharge(shipmentLineItem models.ShipmentLineItem) (FeeAndRate, error) {
 	itemCode := shipmentLineItem.Tariff400ngItem.Code
@@ -120,62 +159,100 @@ func (re *RateEngine) ComputeShipmentLineItemCharge(shipmentLineItem models.Ship
 		return FeeAndRate{}, errors.New("Can't price a shipment line item for a shipment without NetWeight")
 	}
 
+	// Line items for a SIT are priced using the weight placed in SIT, which is less than the shipment's net weight
+	// for split deliveries
+	weight := *shipment.NetWeight
+	isSplitDelivery := false
+	var storageInTransit *models.StorageInTransit
+	if shipmentLineItem.StorageInTransitID != nil {
+		var err error
+		storageInTransit, err = models.FetchStorageInTransitByID(re.db, *shipmentLineItem.StorageInTransitID)
+		if err != nil {
+			return FeeAndRate{}, errors.Wrapf(err, "Fetching storage in transit for item code %s", itemCode)
+		}
+		weight = storageInTransit.Weight(*shipment.NetWeight)
+		isSplitDelivery = storageInTransit.IsSplitDelivery(*shipment.NetWeight)
+	}
+
 	// Defaults to origin postal code, but if location is NEITHER than this doesn't matter
 	zip := Zip5ToZip3(shipment.PickupAddress.PostalCode)
 	if shipmentLineItem.Location == models.ShipmentLineItemLocationDESTINATION {
 		zip = Zip5ToZip3(shipment.Move.Orders.NewDutyStation.Address.PostalCode)
 	}
 
-	shipDate := shipment.BookDate
-	serviceArea, err := models.FetchTariff400ngServiceAreaForZip3(re.db, zip, *shipDate)
+	shipDate := re.overrides.date(*shipment.BookDate)
+	serviceArea, err := models.FetchTariff400ngServiceAreaForZip3(re.db, zip, shipDate)
 	if err != nil {
 		return FeeAndRate{}, errors.Wrapf(err, "Fetching 400ng service area from db for zip %s", zip)
 	}
 
+	// If code is priced using rate from separate code, use that
+	effectiveItemCode := itemCode
+	if mappedCode, ok := tariff400ngItemRateMap[effectiveItemCode]; ok {
+		effectiveItemCode = mappedCode
+	}
+
 	var rateCents unit.Cents
-	if itemCode == "185A" {
+	var rateExplanation models.PriceExplanation
+	if effectiveItemCode == "185A" {
 		// Rates for SIT are stored  on the service area
 		rateCents = serviceArea.SIT185ARateCents
-	} else if itemCode == "185B" {
+		rateExplanation = explainServiceAreaRate("185A rate", rateCents.ToDollarString(), serviceArea)
+	} else if effectiveItemCode == "185B" {
 		rateCents = serviceArea.SIT185BRateCents
+		rateExplanation = explainServiceAreaRate("185B rate", rateCents.ToDollarString(), serviceArea)
 	} else if itemCode == "226A" {
 		// 226A is Misc charge, allow user to enter dollar amount as quantity
 		rateCents = unit.Cents(100)
+		rateExplanation = explainDetail("Rate", "%s, so the charge is the dollar amount entered as the quantity", rateCents.ToDollarString())
 	} else if itemCode == "35A" {
 		// 35A is a Third Party Service (TPS) charge, allow user to enter dollar amount as quantity
 		rateCents = unit.Cents(100)
-	} else if itemCode == "210C" {
-		// If both 210C and 210F are both going to follow this path, consider changing this to
-		// if DiscountType == models.Tariff400ngItemDiscountTypeHHGLINEHAUL50 but for now
-		// we are only using 210C
-		linehaul210CRate, err := models.FetchBaseLinehaulRate(
-			re.db,
-			shipmentLineItem.Quantity1.ToUnitInt(),
-			*shipment.NetWeight,
-			*shipment.ActualPickupDate)
-		rateCents = linehaul210CRate
+		rateExplanation = explainDetail("Rate", "%s, so the charge is the dollar amount entered as the quantity", rateCents.ToDollarString())
+	} else if itemCode == "16B" {
+		// 16B is the fuel surcharge on the base linehaul rate for the SIT pickup or delivery mileage in its second quantity
+		if storageInTransit == nil {
+			return FeeAndRate{}, errors.New("Can't price 16B for a shipment line item without a storage in transit")
+		}
+		linehaulType := models.Tariff400ngLinehaulRateTypeCONUS
+		if storageInTransit.IsInAlaska(shipment) {
+			linehaulType = models.Tariff400ngLinehaulRateTypeINTRAALASKA
+		}
+		linehaulRate, err := re.fetchLinehaulRateForItem(itemCode, linehaulType, shipmentLineItem.Quantity2.ToUnitInt(), weight, shipment)
+		if err != nil {
+			return FeeAndRate{}, err
+		}
+		fuelSurcharge, err := re.fuelSurchargeComputation(linehaulRate.RateCents, shipDate)
+		if err != nil {
+			return FeeAndRate{}, errors.Wrapf(err, "Computing fuel surcharge for item code %s", itemCode)
+		}
+		rateCents = fuelSurcharge.Fee
+		rateExplanation = fuelSurcharge.Explanation
+	} else if linehaulType, ok := tariff400ngLinehaulRateItems[itemCode]; ok {
+		linehaulRate, err := re.fetchLinehaulRateForItem(itemCode, linehaulType, shipmentLineItem.Quantity1.ToUnitInt(), weight, shipment)
 		if err != nil {
-			re.logger.Error("Base Linehaul query didn't complete for 210C: ", zap.Error(err))
+			return FeeAndRate{}, err
 		}
+		rateCents = linehaulRate.RateCents
+		rateExplanation = explainLinehaulRate(linehaulRate)
 	} else {
 		// Most rates should be in the tariff400ngItemRates table though
-
-		// If code is priced using rate from separate code, use that
-		effectiveItemCode := itemCode
-		if mappedCode, ok := tariff400ngItemRateMap[effectiveItemCode]; ok {
-			effectiveItemCode = mappedCode
+		schedule := serviceArea.ServicesSchedule
+		if tariff400ngSITPDScheduleItems[effectiveItemCode] {
+			schedule = serviceArea.SITPDSchedule
 		}
 
 		rate, err := models.FetchTariff400ngItemRate(re.db,
 			effectiveItemCode,
-			serviceArea.ServicesSchedule,
-			*shipment.NetWeight,
-			*shipDate,
+			schedule,
+			weight,
+			shipDate,
 		)
 		if err != nil {
 			return FeeAndRate{}, errors.Wrapf(err, "Fetching 400ng item rate from db for item code %s with effective item code %s", itemCode, effectiveItemCode)
 		}
 		rateCents = rate.RateCents
+		rateExplanation = explainItemRate(rate)
 	}
 	// Make sure we have a ShipmentOffer and TSPP if we need to apply a discount
 	hasTSPP := len(shipment.ShipmentOffers) == 0 || shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.ID == uuid.Nil
@@ -184,21 +261,36 @@ func (re *RateEngine) ComputeShipmentLineItemCharge(shipmentLineItem models.Ship
 	}
 
 	var discountRate *unit.DiscountRate
-	// Removing the check for DiscountType == models.Tariff400ngItemDiscountTypeHHGLINEHAUL50 and explicitly checking for 210C, the
-	// only other item that falls under this type is 210F and we aren't currently processing this.
-	// For 210C, the linehaul rate table is used for pricing but the SIT discount rate is used for the discount rate.
+	var discountName string
+	var discountOverridden bool
+	// Items priced using the linehaul rate tables for more than 50 miles (HHG_LINEHAUL_50) use the SIT discount rate
 	if shipmentLineItem.Tariff400ngItem.DiscountType == models.Tariff400ngItemDiscountTypeHHG {
-		discountRate = &shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.LinehaulRate
-	} else if shipmentLineItem.Tariff400ngItem.DiscountType == models.Tariff400ngItemDiscountTypeSIT || shipmentLineItem.Tariff400ngItem.Code == "210C" {
-		discountRate = &shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.SITRate
+		linehaulDiscount := re.overrides.linehaulDiscount(shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.LinehaulRate)
+		discountRate = &linehaulDiscount
+		discountName = "linehaul discount"
+		discountOverridden = re.overrides.LinehaulDiscount != nil
+	} else if shipmentLineItem.Tariff400ngItem.DiscountType == models.Tariff400ngItemDiscountTypeSIT ||
+		shipmentLineItem.Tariff400ngItem.DiscountType == models.Tariff400ngItemDiscountTypeHHGLINEHAUL50 {
+		sitDiscount := re.overrides.sitDiscount(shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.SITRate)
+		discountRate = &sitDiscount
+		discountName = "SIT discount"
+		discountOverridden = re.overrides.SITDiscount != nil
 	}
 	// Weight-based items will pull final weight values from the shipment when available
-	appliedQuantity := shipmentLineItem.Quantity1
+	appliedQuantity1 := shipmentLineItem.Quantity1
+	appliedQuantity2 := shipmentLineItem.Quantity2
+	var quantityExplanation []models.PriceExplanation
+	weightExplanation := explainDetail("Weight is the shipment's net weight", "%d lbs", weight.Int())
+	if shipmentLineItem.StorageInTransitID != nil {
+		weightExplanation = explainDetail("Weight is the weight placed in SIT", "%d lbs", weight.Int())
+	}
 	if _, ok := tariff400ngWeightBasedItems[itemCode]; ok {
-		if shipment.NetWeight == nil {
-			return FeeAndRate{}, errors.New("Can't price a weight-based accessorial without shipment net weight")
-		}
-		appliedQuantity = unit.BaseQuantityFromInt(shipment.NetWeight.Int())
+		appliedQuantity1 = unit.BaseQuantityFromInt(weight.Int())
+		quantityExplanation = append(quantityExplanation, weightExplanation)
+	}
+	if _, ok := tariff400ngDaysAndWeightBasedItems[itemCode]; ok {
+		appliedQuantity2 = unit.BaseQuantityFromInt(weight.Int())
+		quantityExplanation = append(quantityExplanation, weightExplanation)
 	}
 
 	appliedRate := rateCents
@@ -206,13 +298,53 @@ func (re *RateEngine) ComputeShipmentLineItemCharge(shipmentLineItem models.Ship
 		appliedRate = discountRate.Apply(rateCents)
 	}
 
-	if itemPricer, ok := tariff400ngItemPricing[itemCode]; ok {
-		return FeeAndRate{Fee: itemPricer.price(rateCents, appliedQuantity, discountRate), Rate: appliedRate.ToMillicents()}, nil
+	itemPricer, ok := tariff400ngItemPricing[itemCode]
+	if splitDeliveryPricer, hasSplitDeliveryPricer := tariff400ngSplitDeliveryItemPricing[itemCode]; hasSplitDeliveryPricer && isSplitDelivery {
+		itemPricer = splitDeliveryPricer
+		quantityExplanation = append(quantityExplanation, explainDetail("Split delivery",
+			"only %d of the shipment's %d lbs were placed in SIT, so the minimum weight doesn't apply", weight.Int(), shipment.NetWeight.Int()))
+	}
+	if ok {
+		fee := itemPricer.price(rateCents, appliedQuantity1, appliedQuantity2, discountRate)
+
+		children := append([]models.PriceExplanation{rateExplanation}, quantityExplanation...)
+		children = append(children, itemPricer.explain(appliedQuantity1, appliedQuantity2)...)
+		if discountRate != nil && discountOverridden {
+			children = append(children, explainDetail("Discount", explanationPercentFormat+" overridden %s", discountRate.Float64()*100, discountName))
+		} else if discountRate != nil {
+			tspp := shipment.ShipmentOffers[0].TransportationServiceProviderPerformance
+			children = append(children, explainRate("Discount", fmt.Sprintf(explanationPercentFormat, discountRate.Float64()*100),
+				tspPerformancesTable, tspp.ID, "TSP "+discountName))
+		}
+		explanation := explainAmount(fmt.Sprintf("%s %s", itemCode, shipmentLineItem.Tariff400ngItem.Item), fee, children...)
+
+		return FeeAndRate{Fee: fee, Rate: appliedRate.ToMillicents(), Explanation: explanation}, nil
 	}
 
 	return FeeAndRate{}, errors.New("Could not find pricing function for given code")
 }
 
+// fetchLinehaulRateForItem returns the base linehaul rate of linehaulType that an item code is priced with, for the
+// given mileage and weight
+func (re *RateEngine) fetchLinehaulRateForItem(itemCode string, linehaulType string, miles int, weight unit.Pound, shipment models.Shipment) (models.Tariff400ngLinehaulRate, error) {
+	if shipment.ActualPickupDate == nil {
+		return models.Tariff400ngLinehaulRate{}, errors.Errorf("Can't price %s for a shipment without an actual pickup date", itemCode)
+	}
+	if weight < minimumLinehaulRateWeight {
+		weight = minimumLinehaulRateWeight
+	}
+	linehaulRate, err := models.FetchTariff400ngLinehaulRateForType(
+		re.db,
+		linehaulType,
+		miles,
+		weight,
+		re.overrides.date(*shipment.ActualPickupDate))
+	if err != nil {
+		return models.Tariff400ngLinehaulRate{}, errors.Wrapf(err, "Fetching %s rate from db for item code %s", linehaulType, itemCode)
+	}
+	return linehaulRate, nil
+}
+
 // PriceAdditionalRequestsForShipment for a shipment, computes prices for all approved pre-approval requests and populates amount_cents field and applied_rate on those models
 func (re *RateEngine) PriceAdditionalRequestsForShipment(shipment models.Shipment, storageInTransitLineItems []models.ShipmentLineItem) ([]models.ShipmentLineItem, error) {
 
@@ -250,5 +382,6 @@ func (re *RateEngine) PriceAdditionalRequest(shipmentLineItem *models.ShipmentLi
 	}
 	shipmentLineItem.AmountCents = &feeAndRate.Fee
 	shipmentLineItem.AppliedRate = &feeAndRate.Rate
+	shipmentLineItem.PriceExplanation = &feeAndRate.Explanation
 	return nil
 }
@@ -51,6 +51,19 @@ func (suite *RateEngineSuite) TestAccessorialsPricingPackCrate() {
 	if suite.NoError(err) {
 		discountRate := shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.LinehaulRate
 		suite.Equal(discountRate.Apply(rateCents.Multiply(q1)), computedPriceAndRate.Fee)
+
+		explanation := computedPriceAndRate.Explanation
+		suite.Equal(computedPriceAndRate.Fee, *explanation.AmountCents)
+		if rate := explanation.Find("105B rate"); suite.NotNil(rate) {
+			suite.Equal("$22.75", rate.Detail)
+			suite.Equal("tariff400ng_item_rates", rate.Source.Table)
+		}
+		if quantity := explanation.Find("Quantity"); suite.NotNil(quantity) {
+			suite.Equal("5.0000", quantity.Detail)
+		}
+		if discount := explanation.Find("Discount"); suite.NotNil(discount) {
+			suite.Equal(shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.ID, discount.Source.ID)
+		}
 	}
 }
 
@@ -59,7 +72,16 @@ func (suite *RateEngineSuite) TestAccessorialsSmokeTest() {
 	rateCents := unit.Cents(100)
 	shipment := suite.createShipmentWithServiceArea()
 
+	// Codes priced using the linehaul rate tables need a rate for the mileage in their quantity
+	for _, linehaulType := range tariff400ngLinehaulRateItems {
+		suite.makeLinehaulRate(linehaulType, rateCents)
+	}
+
 	for code := range tariff400ngItemPricing {
+		// 16B is only priced for line items for a SIT, in TestAccessorialsPricingAttemptedDelivery
+		if code == "16B" {
+			continue
+		}
 		item := testdatagen.MakeShipmentLineItem(suite.DB(), testdatagen.Assertions{
 			ShipmentLineItem: models.ShipmentLineItem{
 				Quantity1: unit.BaseQuantityFromInt(1),
@@ -73,6 +95,9 @@ func (suite *RateEngineSuite) TestAccessorialsSmokeTest() {
 			},
 		})
 
+		if _, ok := tariff400ngLinehaulRateItems[code]; ok {
+			continue
+		}
 		rateCode := code
 		if newCode, ok := tariff400ngItemRateMap[code]; ok {
 			rateCode = newCode
@@ -142,5 +167,199 @@ func (suite *RateEngineSuite) TestPricePreapprovalRequest() {
 	if suite.NoError(err) {
 		suite.NotNil(item.AmountCents)
 		suite.NotNil(item.AppliedRate)
+		if suite.NotNil(item.PriceExplanation) {
+			suite.Equal(*item.AmountCents, *item.PriceExplanation.AmountCents)
+		}
+	}
+}
+
+func (suite *RateEngineSuite) makeLinehaulRate(linehaulType string, rateCents unit.Cents) models.Tariff400ngLinehaulRate {
+	rate := models.Tariff400ngLinehaulRate{
+		DistanceMilesLower: 0,
+		DistanceMilesUpper: 1000,
+		WeightLbsLower:     unit.Pound(0),
+		WeightLbsUpper:     unit.Pound(100000),
+		RateCents:          rateCents,
+		Type:               linehaulType,
+		EffectiveDateLower: testdatagen.PeakRateCycleStart,
+		EffectiveDateUpper: testdatagen.NonPeakRateCycleEnd,
+	}
+	existing, err := models.FetchTariff400ngLinehaulRateForType(suite.DB(), linehaulType, 1, unit.Pound(1000), testdatagen.DateInsidePerformancePeriod)
+	if err == nil {
+		return existing
+	}
+	suite.MustSave(&rate)
+	return rate
+}
+
+func (suite *RateEngineSuite) makeSITLineItem(shipment models.Shipment, sit models.StorageInTransit, code string, discountType models.Tariff400ngItemDiscountType, q1 unit.BaseQuantity, q2 unit.BaseQuantity) models.ShipmentLineItem {
+	return testdatagen.MakeShipmentLineItem(suite.DB(), testdatagen.Assertions{
+		ShipmentLineItem: models.ShipmentLineItem{
+			Quantity1:          q1,
+			Quantity2:          q2,
+			Shipment:           shipment,
+			Status:             models.ShipmentLineItemStatusAPPROVED,
+			Location:           models.ShipmentLineItemLocationORIGIN,
+			StorageInTransitID: &sit.ID,
+		},
+		Tariff400ngItem: models.Tariff400ngItem{
+			Code:         code,
+			DiscountType: discountType,
+		},
+	})
+}
+
+func (suite *RateEngineSuite) TestAccessorialsPricingSIT() {
+	shipment := suite.createShipmentWithServiceArea()
+	netWeight := *shipment.NetWeight
+	sit := testdatagen.MakeStorageInTransit(suite.DB(), testdatagen.Assertions{
+		StorageInTransit: models.StorageInTransit{
+			ShipmentID: shipment.ID,
+			Shipment:   shipment,
+			Location:   models.StorageInTransitLocationORIGIN,
+			Status:     models.StorageInTransitStatusDELIVERED,
+		},
+	})
+	engine := NewRateEngine(suite.DB(), suite.logger)
+	sitDiscount := shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.SITRate
+	serviceArea, err := models.FetchTariff400ngServiceAreaForZip3(suite.DB(),
+		Zip5ToZip3(shipment.PickupAddress.PostalCode), *shipment.BookDate)
+	suite.FatalNoError(err)
+
+	// 185A is priced per hundredweight of the whole shipment
+	item185A := suite.makeSITLineItem(shipment, sit, "185A", models.Tariff400ngItemDiscountTypeSIT, unit.BaseQuantity(0), unit.BaseQuantity(0))
+	feeAndRate, err := engine.ComputeShipmentLineItemCharge(item185A)
+	if suite.NoError(err) {
+		suite.Equal(sitDiscount.Apply(serviceArea.SIT185ARateCents.MultiplyFloat64(float64(netWeight)/100.0)), feeAndRate.Fee)
+		suite.NotNil(feeAndRate.Explanation.Find("Weight is the weight placed in SIT"))
+	}
+
+	// 185B is priced per hundredweight per additional day
+	item185B := suite.makeSITLineItem(shipment, sit, "185B", models.Tariff400ngItemDiscountTypeSIT, unit.BaseQuantityFromInt(3), unit.BaseQuantity(0))
+	feeAndRate, err = engine.ComputeShipmentLineItemCharge(item185B)
+	if suite.NoError(err) {
+		suite.Equal(sitDiscount.Apply(serviceArea.SIT185BRateCents.MultiplyFloat64(3*float64(netWeight)/100.0)), feeAndRate.Fee)
+	}
+
+	// 210C is priced using the base linehaul rate for its mileage, and the SIT discount
+	linehaulRate := suite.makeLinehaulRate(models.Tariff400ngLinehaulRateTypeCONUS, unit.Cents(250000))
+	item210C := suite.makeSITLineItem(shipment, sit, "210C", models.Tariff400ngItemDiscountTypeHHGLINEHAUL50, unit.BaseQuantityFromInt(75), unit.BaseQuantity(0))
+	feeAndRate, err = engine.ComputeShipmentLineItemCharge(item210C)
+	if suite.NoError(err) {
+		suite.Equal(sitDiscount.Apply(linehaulRate.RateCents), feeAndRate.Fee)
+		if rate := feeAndRate.Explanation.Find("Base linehaul rate"); suite.NotNil(rate) {
+			suite.Equal(linehaulRate.ID, rate.Source.ID)
+		}
+	}
+}
+
+func (suite *RateEngineSuite) TestAccessorialsPricingSITSplitDelivery() {
+	shipment := suite.createShipmentWithServiceArea()
+	sitWeight := unit.Pound(500)
+	sit := testdatagen.MakeStorageInTransit(suite.DB(), testdatagen.Assertions{
+		StorageInTransit: models.StorageInTransit{
+			ShipmentID: shipment.ID,
+			Shipment:   shipment,
+			Location:   models.StorageInTransitLocationORIGIN,
+			Status:     models.StorageInTransitStatusDELIVERED,
+			WeightLbs:  &sitWeight,
+		},
+	})
+	engine := NewRateEngine(suite.DB(), suite.logger)
+	sitDiscount := shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.SITRate
+	serviceArea, err := models.FetchTariff400ngServiceAreaForZip3(suite.DB(),
+		Zip5ToZip3(shipment.PickupAddress.PostalCode), *shipment.BookDate)
+	suite.FatalNoError(err)
+
+	// Only part of the shipment is in SIT, so it isn't priced at the 1,000 lb minimum
+	item185A := suite.makeSITLineItem(shipment, sit, "185A", models.Tariff400ngItemDiscountTypeSIT, unit.BaseQuantity(0), unit.BaseQuantity(0))
+	feeAndRate, err := engine.ComputeShipmentLineItemCharge(item185A)
+	if suite.NoError(err) {
+		suite.Equal(sitDiscount.Apply(serviceArea.SIT185ARateCents.MultiplyFloat64(float64(sitWeight)/100.0)), feeAndRate.Fee)
+		suite.NotNil(feeAndRate.Explanation.Find("Split delivery"))
+	}
+
+	item185B := suite.makeSITLineItem(shipment, sit, "185B", models.Tariff400ngItemDiscountTypeSIT, unit.BaseQuantityFromInt(2), unit.BaseQuantity(0))
+	feeAndRate, err = engine.ComputeShipmentLineItemCharge(item185B)
+	if suite.NoError(err) {
+		suite.Equal(sitDiscount.Apply(serviceArea.SIT185BRateCents.MultiplyFloat64(2*float64(sitWeight)/100.0)), feeAndRate.Fee)
+	}
+}
+
+func (suite *RateEngineSuite) TestAccessorialsPricingAttemptedDelivery() {
+	shipment := suite.createShipmentWithServiceArea()
+	netWeight := *shipment.NetWeight
+	sit := testdatagen.MakeStorageInTransit(suite.DB(), testdatagen.Assertions{
+		StorageInTransit: models.StorageInTransit{
+			ShipmentID: shipment.ID,
+			Shipment:   shipment,
+			Location:   models.StorageInTransitLocationORIGIN,
+			Status:     models.StorageInTransitStatusAPPROVED,
+		},
+	})
+	engine := NewRateEngine(suite.DB(), suite.logger)
+	sitDiscount := shipment.ShipmentOffers[0].TransportationServiceProviderPerformance.SITRate
+	serviceArea, err := models.FetchTariff400ngServiceAreaForZip3(suite.DB(),
+		Zip5ToZip3(shipment.PickupAddress.PostalCode), *shipment.BookDate)
+	suite.FatalNoError(err)
+
+	// Attempts within 50 miles are priced at the flat rate of the matching SIT P/D item
+	flatRates := map[string]unit.Cents{
+		"17A": unit.Cents(1100),
+		"17B": unit.Cents(1200),
+		"17E": unit.Cents(1300),
+		"17F": unit.Cents(1400),
+	}
+	for code, rateCents := range flatRates {
+		testdatagen.MakeTariff400ngItemRate(suite.DB(), testdatagen.Assertions{
+			Tariff400ngItemRate: models.Tariff400ngItemRate{
+				Code:      tariff400ngItemRateMap[code],
+				RateCents: rateCents,
+			},
+		})
+		item := suite.makeSITLineItem(shipment, sit, code, models.Tariff400ngItemDiscountTypeSIT, unit.BaseQuantityFromInt(20), unit.BaseQuantity(0))
+		feeAndRate, err := engine.ComputeShipmentLineItemCharge(item)
+		if suite.NoError(err, code) {
+			suite.Equal(sitDiscount.Apply(rateCents), feeAndRate.Fee, code)
+		}
+	}
+
+	// 17D is the first day back in the warehouse, priced like 185A with a 1,000 lb minimum
+	item17D := suite.makeSITLineItem(shipment, sit, "17D", models.Tariff400ngItemDiscountTypeSIT, unit.BaseQuantity(0), unit.BaseQuantity(0))
+	feeAndRate, err := engine.ComputeShipmentLineItemCharge(item17D)
+	if suite.NoError(err) {
+		suite.Equal(sitDiscount.Apply(serviceArea.SIT185ARateCents.MultiplyFloat64(float64(netWeight)/100.0)), feeAndRate.Fee)
+	}
+
+	// Attempts over 50 miles are priced from the linehaul rates, in Alaska from the intra-Alaska rates
+	linehaulRates := map[string]models.Tariff400ngLinehaulRate{
+		"17C": suite.makeLinehaulRate(models.Tariff400ngLinehaulRateTypeCONUS, unit.Cents(250000)),
+		"17G": suite.makeLinehaulRate(models.Tariff400ngLinehaulRateTypeINTRAALASKA, unit.Cents(310000)),
+	}
+	for code, linehaulRate := range linehaulRates {
+		item := suite.makeSITLineItem(shipment, sit, code, models.Tariff400ngItemDiscountTypeHHGLINEHAUL50, unit.BaseQuantityFromInt(75), unit.BaseQuantity(0))
+		feeAndRate, err := engine.ComputeShipmentLineItemCharge(item)
+		if suite.NoError(err, code) {
+			suite.Equal(sitDiscount.Apply(linehaulRate.RateCents), feeAndRate.Fee, code)
+			if rate := feeAndRate.Explanation.Find("Base linehaul rate"); suite.NotNil(rate, code) {
+				suite.Equal(linehaulRate.ID, rate.Source.ID, code)
+			}
+		}
+	}
+
+	// 16B is the fuel surcharge on the linehaul rate for the mileage in its second quantity
+	fuelPrice := testdatagen.MakeFuelEIADieselPriceForDate(suite.DB(), *shipment.BookDate, testdatagen.Assertions{
+		FuelEIADieselPrice: models.FuelEIADieselPrice{
+			RateStartDate: shipment.BookDate.AddDate(0, 0, -1),
+			RateEndDate:   shipment.BookDate.AddDate(0, 0, 1),
+			BaselineRate:  6,
+		},
+	})
+	item16B := suite.makeSITLineItem(shipment, sit, "16B", models.Tariff400ngItemDiscountTypeNONE, unit.BaseQuantityFromInt(netWeight.Int()), unit.BaseQuantityFromInt(75))
+	feeAndRate, err = engine.ComputeShipmentLineItemCharge(item16B)
+	if suite.NoError(err) {
+		fuelSurchargePercentage := float64(fuelPrice.BaselineRate) / 100
+		suite.Equal(linehaulRates["17C"].RateCents.MultiplyFloat64(fuelSurchargePercentage), feeAndRate.Fee)
+		suite.NotNil(feeAndRate.Explanation.Find("Fuel surcharge"))
 	}
 }
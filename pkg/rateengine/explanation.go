@@ -0,0 +1,144 @@
+package rateengine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// Tables that rates are taken from, as recorded in price explanations
+const (
+	linehaulRatesTable       = "tariff400ng_linehaul_rates"
+	shorthaulRatesTable      = "tariff400ng_shorthaul_rates"
+	serviceAreasTable        = "tariff400ng_service_areas"
+	fullPackRatesTable       = "tariff400ng_full_pack_rates"
+	fullUnpackRatesTable     = "tariff400ng_full_unpack_rates"
+	itemRatesTable           = "tariff400ng_item_rates"
+	fuelEIADieselPricesTable = "fuel_eia_diesel_prices"
+	tspPerformancesTable     = "transportation_service_provider_performances"
+)
+
+// Weights below the minimum weight are priced at the minimum weight, then prorated
+const minimumWeightForProrating = 1000
+
+const (
+	explanationDateFormat    = "2006-01-02"
+	explanationPercentFormat = "%.2f%%"
+)
+
+// explainAmount explains an amount of money in terms of what it was computed from
+func explainAmount(description string, amount unit.Cents, children ...models.PriceExplanation) models.PriceExplanation {
+	return models.PriceExplanation{
+		Description: description,
+		AmountCents: &amount,
+		Children:    children,
+	}
+}
+
+// explainDetail explains a rate, quantity or rule that an amount was computed from
+func explainDetail(description string, format string, args ...interface{}) models.PriceExplanation {
+	return models.PriceExplanation{
+		Description: description,
+		Detail:      fmt.Sprintf(format, args...),
+	}
+}
+
+// explainRate explains a rate taken from a row of a tariff table
+func explainRate(description string, rate string, table string, id uuid.UUID, row string) models.PriceExplanation {
+	return models.PriceExplanation{
+		Description: description,
+		Detail:      rate,
+		Source: &models.PriceExplanationSource{
+			Table:       table,
+			ID:          id,
+			Description: row,
+		},
+	}
+}
+
+// explainDiscount explains a discounted amount in terms of the amount before the discount and the discount rate
+func explainDiscount(undiscounted models.PriceExplanation, discount unit.DiscountRate, discounted unit.Cents) models.PriceExplanation {
+	description := undiscounted.Description
+	undiscounted.Description = "Before discount"
+	return explainAmount(description, discounted,
+		undiscounted,
+		explainDetail("Discount", explanationPercentFormat, discount.Float64()*100),
+	)
+}
+
+// explainProration explains an amount that was prorated because the weight was below the minimum weight
+func explainProration(unprorated models.PriceExplanation, weight unit.Pound, factor float64, prorated unit.Cents) models.PriceExplanation {
+	description := unprorated.Description
+	unprorated.Description = fmt.Sprintf("At the %d lb minimum weight", minimumWeightForProrating)
+	return explainAmount(description, prorated,
+		unprorated,
+		explainDetail("Prorated for weight below the minimum", "%d lbs / %d lbs = %.4f", weight.Int(), minimumWeightForProrating, factor),
+	)
+}
+
+func explainWeight(weight unit.Pound) models.PriceExplanation {
+	return explainDetail("Weight", "%d lbs", weight.Int())
+}
+
+func explainCWT(cwt unit.CWT) models.PriceExplanation {
+	return explainDetail("Hundredweight", "%d cwt", cwt.Int())
+}
+
+func explainMileage(mileage int) models.PriceExplanation {
+	return explainDetail("Mileage", "%d miles", mileage)
+}
+
+func effectiveDates(lower time.Time, upper time.Time) string {
+	return fmt.Sprintf("effective %s to %s", lower.Format(explanationDateFormat), upper.Format(explanationDateFormat))
+}
+
+func explainLinehaulRate(rate models.Tariff400ngLinehaulRate) models.PriceExplanation {
+	return explainRate("Base linehaul rate", rate.RateCents.ToDollarString(), linehaulRatesTable, rate.ID,
+		fmt.Sprintf("%s, %d-%d miles, %d-%d lbs, %s", rate.Type,
+			rate.DistanceMilesLower, rate.DistanceMilesUpper, rate.WeightLbsLower.Int(), rate.WeightLbsUpper.Int(),
+			effectiveDates(rate.EffectiveDateLower, rate.EffectiveDateUpper)))
+}
+
+func explainShorthaulRate(rate models.Tariff400ngShorthaulRate) models.PriceExplanation {
+	return explainRate("Shorthaul rate", rate.RateCents.ToDollarString(), shorthaulRatesTable, rate.ID,
+		fmt.Sprintf("%d-%d cwt-miles, %s", rate.CwtMilesLower, rate.CwtMilesUpper,
+			effectiveDates(rate.EffectiveDateLower, rate.EffectiveDateUpper)))
+}
+
+// explainServiceAreaRate explains a rate taken from a service area
+func explainServiceAreaRate(description string, rate string, serviceArea models.Tariff400ngServiceArea) models.PriceExplanation {
+	return explainRate(description, rate, serviceAreasTable, serviceArea.ID,
+		fmt.Sprintf("service area %s (%s), %s", serviceArea.ServiceArea, serviceArea.Name,
+			effectiveDates(serviceArea.EffectiveDateLower, serviceArea.EffectiveDateUpper)))
+}
+
+func explainFullPackRate(rate models.Tariff400ngFullPackRate) models.PriceExplanation {
+	return explainRate("Full pack rate", rate.RateCents.ToDollarString(), fullPackRatesTable, rate.ID,
+		fmt.Sprintf("schedule %d, %d-%d lbs, %s", rate.Schedule, rate.WeightLbsLower.Int(), rate.WeightLbsUpper.Int(),
+			effectiveDates(rate.EffectiveDateLower, rate.EffectiveDateUpper)))
+}
+
+func explainFullUnpackRate(rate models.Tariff400ngFullUnpackRate) models.PriceExplanation {
+	return explainRate("Full unpack rate", unit.Millicents(rate.RateMillicents).ToDollarString(), fullUnpackRatesTable, rate.ID,
+		fmt.Sprintf("schedule %d, %s", rate.Schedule, effectiveDates(rate.EffectiveDateLower, rate.EffectiveDateUpper)))
+}
+
+func explainItemRate(rate models.Tariff400ngItemRate) models.PriceExplanation {
+	schedule := "any schedule"
+	if rate.Schedule != nil {
+		schedule = fmt.Sprintf("schedule %d", *rate.Schedule)
+	}
+	return explainRate(fmt.Sprintf("%s rate", rate.Code), rate.RateCents.ToDollarString(), itemRatesTable, rate.ID,
+		fmt.Sprintf("%s, %s, %d-%d lbs, %s", rate.Code, schedule, rate.WeightLbsLower.Int(), rate.WeightLbsUpper.Int(),
+			effectiveDates(rate.EffectiveDateLower, rate.EffectiveDateUpper)))
+}
+
+func explainFuelPrice(price models.FuelEIADieselPrice) models.PriceExplanation {
+	return explainRate("Fuel surcharge percentage", fmt.Sprintf(explanationPercentFormat, float64(price.BaselineRate)), fuelEIADieselPricesTable, price.ID,
+		fmt.Sprintf("EIA diesel price of %s per gallon published %s, for %s to %s", price.EIAPricePerGallonMillicents.ToDollarString(),
+			price.PubDate.Format(explanationDateFormat), price.RateStartDate.Format(explanationDateFormat), price.RateEndDate.Format(explanationDateFormat)))
+}
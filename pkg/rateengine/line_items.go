@@ -38,6 +38,7 @@ func CreateBaseShipmentLineItems(db *pop.Connection, costByShipment CostByShipme
 		AmountCents:       &cost.LinehaulCostComputation.LinehaulChargeTotal,
 		AppliedRate:       &lhAppliedRate,
 		SubmittedDate:     now,
+		PriceExplanation:  &cost.LinehaulCostComputation.LinehaulChargeExplanation,
 	}
 	lineItems = append(lineItems, linehaul)
 
@@ -56,6 +57,7 @@ func CreateBaseShipmentLineItems(db *pop.Connection, costByShipment CostByShipme
 		AmountCents:       &cost.NonLinehaulCostComputation.OriginService.Fee,
 		AppliedRate:       &cost.NonLinehaulCostComputation.OriginService.Rate,
 		SubmittedDate:     now,
+		PriceExplanation:  &cost.NonLinehaulCostComputation.OriginService.Explanation,
 	}
 	lineItems = append(lineItems, originService)
 
@@ -74,6 +76,7 @@ func CreateBaseShipmentLineItems(db *pop.Connection, costByShipment CostByShipme
 		AmountCents:       &cost.NonLinehaulCostComputation.DestinationService.Fee,
 		AppliedRate:       &cost.NonLinehaulCostComputation.DestinationService.Rate,
 		SubmittedDate:     now,
+		PriceExplanation:  &cost.NonLinehaulCostComputation.DestinationService.Explanation,
 	}
 	lineItems = append(lineItems, destinationService)
 
@@ -94,6 +97,7 @@ func CreateBaseShipmentLineItems(db *pop.Connection, costByShipment CostByShipme
 		AmountCents:       &packFee,
 		AppliedRate:       &packRate,
 		SubmittedDate:     now,
+		PriceExplanation:  &cost.NonLinehaulCostComputation.Pack.Explanation,
 	}
 	lineItems = append(lineItems, fullPack)
 
@@ -114,6 +118,7 @@ func CreateBaseShipmentLineItems(db *pop.Connection, costByShipment CostByShipme
 		AmountCents:       &unpackFee,
 		AppliedRate:       &unpackRate,
 		SubmittedDate:     now,
+		PriceExplanation:  &cost.NonLinehaulCostComputation.Unpack.Explanation,
 	}
 	lineItems = append(lineItems, fullUnpack)
 
@@ -135,6 +140,7 @@ func CreateBaseShipmentLineItems(db *pop.Connection, costByShipment CostByShipme
 		AmountCents:       &cost.LinehaulCostComputation.FuelSurcharge.Fee,
 		AppliedRate:       fsAppliedRate,
 		SubmittedDate:     now,
+		PriceExplanation:  &cost.LinehaulCostComputation.FuelSurcharge.Explanation,
 	}
 	lineItems = append(lineItems, fuelSurcharge)
 
@@ -63,6 +63,16 @@ func (suite *RateEngineSuite) TestCreateBaseShipmentLineItems() {
 	if item105C != nil {
 		suite.validateLineItemFields(*item16A, unit.BaseQuantityFromInt(2000), unit.BaseQuantityFromInt(1044), models.ShipmentLineItemLocationORIGIN, unit.Cents(15651), unit.Millicents(320700))
 	}
+
+	// The linehaul explanation shows the discount applied to the sum of its parts
+	if itemLHS != nil {
+		discount := shipmentCost.Cost.LHDiscount
+		undiscounted := itemLHS.PriceExplanation.Find("Before discount")
+		if suite.NotNil(undiscounted) {
+			suite.Equal(discount.Apply(*undiscounted.AmountCents), *itemLHS.AmountCents)
+			suite.NotNil(undiscounted.Find("Base linehaul rate").Source)
+		}
+	}
 }
 
 func (suite *RateEngineSuite) findLineItem(lineItems []models.ShipmentLineItem, itemCode string) *models.ShipmentLineItem {
@@ -82,6 +92,9 @@ func (suite *RateEngineSuite) validateLineItemFields(lineItem models.ShipmentLin
 	suite.Equal(location, lineItem.Location)
 	suite.Equal(amountCents, *lineItem.AmountCents)
 	suite.Equal(appliedRate, *lineItem.AppliedRate)
+	if suite.NotNil(lineItem.PriceExplanation) {
+		suite.Equal(amountCents, *lineItem.PriceExplanation.AmountCents)
+	}
 
 	suite.Equal(models.ShipmentLineItemStatusSUBMITTED, lineItem.Status)
 }
@@ -20,6 +20,8 @@ type LinehaulCostComputation struct {
 	LinehaulChargeTotal       unit.Cents
 	Mileage                   int
 	FuelSurcharge             FeeAndRate
+	// LinehaulChargeExplanation explains how LinehaulChargeTotal was priced
+	LinehaulChargeExplanation models.PriceExplanation
 }
 
 // Scale scales a cost computation by a multiplicative factor
@@ -31,6 +33,13 @@ func (c *LinehaulCostComputation) Scale(factor float64) {
 	c.LinehaulChargeTotal = c.LinehaulChargeTotal.MultiplyFloat64(factor)
 }
 
+// applyDiscount applies a linehaul discount to the linehaul charge total
+func (c *LinehaulCostComputation) applyDiscount(discount unit.DiscountRate) {
+	total := discount.Apply(c.LinehaulChargeTotal)
+	c.LinehaulChargeExplanation = explainDiscount(c.LinehaulChargeExplanation, discount, total)
+	c.LinehaulChargeTotal = total
+}
+
 // MarshalLogObject allows LinehaulCostComputation to be logged by Zap.
 func (c LinehaulCostComputation) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
 	encoder.AddInt("BaseLinehaul", c.BaseLinehaul.Int())
@@ -42,36 +51,56 @@ func (c LinehaulCostComputation) MarshalLogObject(encoder zapcore.ObjectEncoder)
 }
 
 // Determine the Base Linehaul (BLH)
-func (re *RateEngine) baseLinehaul(mileage int, weight unit.Pound, date time.Time) (baseLinehaulChargeCents unit.Cents, err error) {
-	baseLinehaulChargeCents, err = models.FetchBaseLinehaulRate(re.db, mileage, weight, date)
+func (re *RateEngine) baseLinehaul(mileage int, weight unit.Pound, date time.Time) (baseLinehaulChargeCents unit.Cents, explanation models.PriceExplanation, err error) {
+	rate, err := models.FetchTariff400ngLinehaulRate(re.db, mileage, weight, date)
 	if err != nil {
 		re.logger.Error("Base Linehaul query didn't complete: ", zap.Error(err))
+		return 0, explanation, err
 	}
 
-	return baseLinehaulChargeCents, err
+	explanation = explainAmount("Base linehaul", rate.RateCents,
+		explainLinehaulRate(rate),
+		explainMileage(mileage),
+		explainWeight(weight),
+	)
+	return rate.RateCents, explanation, nil
 }
 
 // Determine the Linehaul Factors (OLF and DLF)
-func (re *RateEngine) linehaulFactors(cwt unit.CWT, zip3 string, date time.Time) (linehaulFactorCents unit.Cents, err error) {
+func (re *RateEngine) linehaulFactors(cwt unit.CWT, zip3 string, date time.Time) (linehaulFactorCents unit.Cents, explanation models.PriceExplanation, err error) {
 	serviceArea, err := models.FetchTariff400ngServiceAreaForZip3(re.db, zip3, date)
 	if err != nil {
-		return 0, err
+		return 0, explanation, err
 	}
-	return serviceArea.LinehaulFactor.Multiply(cwt.Int()), nil
+	linehaulFactorCents = serviceArea.LinehaulFactor.Multiply(cwt.Int())
+	explanation = explainAmount("Linehaul factor", linehaulFactorCents,
+		explainServiceAreaRate("Linehaul factor rate", serviceArea.LinehaulFactor.ToDollarString()+" per cwt", serviceArea),
+		explainCWT(cwt),
+	)
+	return linehaulFactorCents, explanation, nil
 }
 
 // Determine Shorthaul (SH) Charge (ONLY applies if shipment moves 800 miles and less)
-func (re *RateEngine) shorthaulCharge(mileage int, cwt unit.CWT, date time.Time) (shorthaulChargeCents unit.Cents, err error) {
+func (re *RateEngine) shorthaulCharge(mileage int, cwt unit.CWT, date time.Time) (shorthaulChargeCents unit.Cents, explanation models.PriceExplanation, err error) {
 	if mileage >= 800 {
-		return 0, nil
+		explanation = explainAmount("Shorthaul charge", 0,
+			explainDetail("Not applicable", "only moves under 800 miles have a shorthaul charge"))
+		return 0, explanation, nil
 	}
 	re.logger.Debug("Shipment qualifies for shorthaul fee",
 		zap.Int("miles", mileage))
 
 	cwtMiles := mileage * cwt.Int()
-	shorthaulChargeCents, err = models.FetchShorthaulRateCents(re.db, cwtMiles, date)
+	rate, err := models.FetchTariff400ngShorthaulRate(re.db, cwtMiles, date)
+	if err != nil {
+		return 0, explanation, err
+	}
 
-	return shorthaulChargeCents, err
+	explanation = explainAmount("Shorthaul charge", rate.RateCents,
+		explainShorthaulRate(rate),
+		explainDetail("Cwt-miles", "%d miles x %d cwt = %d cwt-miles", mileage, cwt.Int(), cwtMiles),
+	)
+	return rate.RateCents, explanation, nil
 }
 
 // Determine Linehaul Charge (LC) TOTAL
@@ -83,19 +112,22 @@ func (re *RateEngine) linehaulChargeComputation(weight unit.Pound, originZip5 st
 
 	cost.Mileage = distanceMiles
 
-	cost.BaseLinehaul, err = re.baseLinehaul(distanceMiles, weight, pickupDate)
+	var baseLinehaul, originLinehaulFactor, destinationLinehaulFactor, shorthaulCharge models.PriceExplanation
+	cost.BaseLinehaul, baseLinehaul, err = re.baseLinehaul(distanceMiles, weight, pickupDate)
 	if err != nil {
 		return cost, errors.Wrap(err, "Failed to determine base linehaul charge")
 	}
-	cost.OriginLinehaulFactor, err = re.linehaulFactors(cwt, originZip3, pickupDate)
+	cost.OriginLinehaulFactor, originLinehaulFactor, err = re.linehaulFactors(cwt, originZip3, pickupDate)
 	if err != nil {
 		return cost, errors.Wrap(err, "Failed to determine origin linehaul factor")
 	}
-	cost.DestinationLinehaulFactor, err = re.linehaulFactors(cwt, destinationZip3, pickupDate)
+	originLinehaulFactor.Description = "Origin linehaul factor"
+	cost.DestinationLinehaulFactor, destinationLinehaulFactor, err = re.linehaulFactors(cwt, destinationZip3, pickupDate)
 	if err != nil {
 		return cost, errors.Wrap(err, "Failed to determine destination linehaul factor")
 	}
-	cost.ShorthaulCharge, err = re.shorthaulCharge(distanceMiles, cwt, pickupDate)
+	destinationLinehaulFactor.Description = "Destination linehaul factor"
+	cost.ShorthaulCharge, shorthaulCharge, err = re.shorthaulCharge(distanceMiles, cwt, pickupDate)
 	if err != nil {
 		return cost, errors.Wrap(err, "Failed to determine shorthaul charge")
 	}
@@ -104,6 +136,12 @@ func (re *RateEngine) linehaulChargeComputation(weight unit.Pound, originZip5 st
 		cost.OriginLinehaulFactor +
 		cost.DestinationLinehaulFactor +
 		cost.ShorthaulCharge
+	cost.LinehaulChargeExplanation = explainAmount("Linehaul charge", cost.LinehaulChargeTotal,
+		baseLinehaul,
+		originLinehaulFactor,
+		destinationLinehaulFactor,
+		shorthaulCharge,
+	)
 
 	re.logger.Info("Linehaul charge total calculated",
 		zap.Int("linehaul total", cost.LinehaulChargeTotal.Int()),
@@ -135,13 +173,25 @@ func (re *RateEngine) fuelSurchargeComputation(totalLinehaulCost unit.Cents, boo
 		re.logger.Error("Got back multiple values from FuelEIADieselPrice when we should have only gotten one.")
 	}
 
-	if len(fuelEIADieselPriceSlice) == 0 {
+	var fuelEIADieselPrice models.FuelEIADieselPrice
+	if len(fuelEIADieselPriceSlice) > 0 {
+		fuelEIADieselPrice = fuelEIADieselPriceSlice[0]
+	} else if re.overrides.FuelPricePerGallon == nil {
 		re.logger.Error("Query failed to find an applicable FuelEIADieselPrice")
+		return FeeAndRate{}, errors.Errorf("No FuelEIADieselPrice found for %s", bookDateString)
 	}
-
-	fuelEIADieselPrice := fuelEIADieselPriceSlice[0]
+	fuelEIADieselPrice = re.overrides.fuelPrice(fuelEIADieselPrice)
 	fuelSurchargePercentage := float64(fuelEIADieselPrice.BaselineRate) / 100
 	fee := totalLinehaulCost.MultiplyFloat64(fuelSurchargePercentage)
 
-	return FeeAndRate{Fee: unit.Cents(fee), Rate: fuelEIADieselPrice.EIAPricePerGallonMillicents}, err
+	fuelPriceExplanation := explainFuelPrice(fuelEIADieselPrice)
+	if re.overrides.FuelPricePerGallon != nil {
+		fuelPriceExplanation = explainDetail("Fuel surcharge percentage", explanationPercentFormat+" for an overridden EIA diesel price of %s per gallon",
+			float64(fuelEIADieselPrice.BaselineRate), fuelEIADieselPrice.EIAPricePerGallonMillicents.ToDollarString())
+	}
+	explanation := explainAmount("Fuel surcharge", fee,
+		explainAmount("Linehaul charge", totalLinehaulCost),
+		fuelPriceExplanation,
+	)
+	return FeeAndRate{Fee: unit.Cents(fee), Rate: fuelEIADieselPrice.EIAPricePerGallonMillicents, Explanation: explanation}, err
 }
@@ -41,13 +41,19 @@ func (suite *RateEngineSuite) Test_CheckBaseLinehaul() {
 	weight := unit.Pound(3900)
 	date := testdatagen.DateInsidePeakRateCycle
 
-	blh, err := engine.baseLinehaul(mileage, weight, date)
+	blh, explanation, err := engine.baseLinehaul(mileage, weight, date)
 	if blh != expected {
 		t.Errorf("BaseLinehaulCents should have been %d but is %d.", expected, blh)
 	}
 	if err != nil {
 		t.Errorf("Encountered error trying to get baseLinehaul: %v", err)
 	}
+	suite.Equal(expected, *explanation.AmountCents)
+	rate := explanation.Find("Base linehaul rate")
+	if suite.NotNil(rate) && suite.NotNil(rate.Source) {
+		suite.Equal(newBaseLinehaul.ID, rate.Source.ID)
+		suite.Equal("tariff400ng_linehaul_rates", rate.Source.Table)
+	}
 }
 
 func (suite *RateEngineSuite) Test_CheckLinehaulFactors() {
@@ -78,7 +84,7 @@ func (suite *RateEngineSuite) Test_CheckLinehaulFactors() {
 	}
 	suite.MustSave(&serviceArea)
 
-	linehaulFactor, err := engine.linehaulFactors(60, "395", testdatagen.RateEngineDate)
+	linehaulFactor, explanation, err := engine.linehaulFactors(60, "395", testdatagen.RateEngineDate)
 	if err != nil {
 		t.Error("Unable to determine linehaulFactor: ", err)
 	}
@@ -86,6 +92,9 @@ func (suite *RateEngineSuite) Test_CheckLinehaulFactors() {
 	if linehaulFactor != expected {
 		t.Errorf("Determined linehaul factor incorrectly. Expected %d, got %d", expected, linehaulFactor)
 	}
+	suite.Equal(expected, *explanation.AmountCents)
+	suite.Equal("$0.57 per cwt", explanation.Find("Linehaul factor rate").Detail)
+	suite.Equal("60 cwt", explanation.Find("Hundredweight").Detail)
 }
 
 func (suite *RateEngineSuite) Test_CheckShorthaulCharge() {
@@ -104,10 +113,12 @@ func (suite *RateEngineSuite) Test_CheckShorthaulCharge() {
 	}
 	suite.MustSave(&sh)
 
-	shc, _ := engine.shorthaulCharge(mileage, cwt, testdatagen.DateInsidePeakRateCycle)
+	shc, explanation, _ := engine.shorthaulCharge(mileage, cwt, testdatagen.DateInsidePeakRateCycle)
 	if shc != rate {
 		t.Errorf("Shorthaul charge should have been %d, but is %d.", rate, shc)
 	}
+	suite.Equal("799 miles x 40 cwt = 31960 cwt-miles", explanation.Find("Cwt-miles").Detail)
+	suite.Equal(sh.ID, explanation.Find("Shorthaul rate").Source.ID)
 }
 
 func (suite *RateEngineSuite) Test_CheckLinehaulChargeTotal() {
@@ -194,6 +205,16 @@ func (suite *RateEngineSuite) Test_CheckLinehaulChargeTotal() {
 	if cost.LinehaulChargeTotal != expected {
 		t.Errorf("Determined linehaul factor incorrectly. Expected %d, got %d", expected, cost.LinehaulChargeTotal)
 	}
+
+	// Each part of the linehaul charge is explained, and the parts add up to the total
+	suite.Equal(expected, *cost.LinehaulChargeExplanation.AmountCents)
+	var sum unit.Cents
+	for _, part := range cost.LinehaulChargeExplanation.Children {
+		sum += *part.AmountCents
+	}
+	suite.Equal(expected, sum)
+	suite.Equal(cost.OriginLinehaulFactor, *cost.LinehaulChargeExplanation.Find("Origin linehaul factor").AmountCents)
+	suite.Equal(cost.DestinationLinehaulFactor, *cost.LinehaulChargeExplanation.Find("Destination linehaul factor").AmountCents)
 }
 
 func (suite *RateEngineSuite) Test_CheckFuelSurchargeComputation() {
@@ -207,4 +228,19 @@ func (suite *RateEngineSuite) Test_CheckFuelSurchargeComputation() {
 
 	suite.NoError(err)
 	suite.Equal(unit.Cents(720), fuelSurcharge.Fee)
+	suite.Equal(unit.Cents(720), *fuelSurcharge.Explanation.AmountCents)
+	suite.Equal("6.00%", fuelSurcharge.Explanation.Find("Fuel surcharge percentage").Detail)
+}
+
+func (suite *RateEngineSuite) Test_CheckFuelSurchargeComputationWithOverride() {
+	fuelPrice := unit.Millicents(450000)
+	engine := NewRateEngine(suite.DB(), suite.logger).WithOverrides(PricingOverrides{FuelPricePerGallon: &fuelPrice})
+
+	// No price is needed in the database when it is overridden
+	fuelSurcharge, err := engine.fuelSurchargeComputation(unit.Cents(12000), testdatagen.NonPeakRateCycleEnd)
+
+	suite.NoError(err)
+	suite.Equal(unit.Cents(1920), fuelSurcharge.Fee)
+	suite.Equal(fuelPrice, fuelSurcharge.Rate)
+	suite.Equal("16.00% for an overridden EIA diesel price of $4.50 per gallon", fuelSurcharge.Explanation.Find("Fuel surcharge percentage").Detail)
 }
@@ -1,6 +1,7 @@
 package rateengine
 
 import (
+	"fmt"
 	"math"
 	"time"
 
@@ -16,6 +17,15 @@ import (
 type FeeAndRate struct {
 	Fee  unit.Cents
 	Rate unit.Millicents
+	// Explanation explains how Fee was priced
+	Explanation models.PriceExplanation
+}
+
+// applyDiscount applies a discount to the fee, but not the rate
+func (f *FeeAndRate) applyDiscount(discount unit.DiscountRate) {
+	fee := discount.Apply(f.Fee)
+	f.Explanation = explainDiscount(f.Explanation, discount, fee)
+	f.Fee = fee
 }
 
 // NonLinehaulCostComputation represents the results of a computation.
@@ -41,6 +51,14 @@ func (c *NonLinehaulCostComputation) Scale(factor float64) {
 	c.Unpack.Fee = c.Unpack.Fee.MultiplyFloat64(factor)
 }
 
+// applyDiscount applies a linehaul discount to each of the fees
+func (c *NonLinehaulCostComputation) applyDiscount(discount unit.DiscountRate) {
+	c.OriginService.applyDiscount(discount)
+	c.DestinationService.applyDiscount(discount)
+	c.Pack.applyDiscount(discount)
+	c.Unpack.applyDiscount(discount)
+}
+
 // ApplyDiscount will apply the linehaul and SIT discounts to the appropriate parts of the SIT computation.
 func (s SITComputation) ApplyDiscount(linehaulDiscount unit.DiscountRate, sitDiscount unit.DiscountRate) unit.Cents {
 	return sitDiscount.Apply(s.SITPart).AddCents(linehaulDiscount.Apply(s.LinehaulPart))
@@ -63,7 +81,11 @@ func (re *RateEngine) serviceFeeCents(cwt unit.CWT, zip3 string, date time.Time)
 	}
 	rateCents := serviceArea.ServiceChargeCents
 	feeCents := rateCents.Multiply(cwt.Int())
-	return FeeAndRate{Fee: feeCents, Rate: rateCents.ToMillicents()}, nil
+	explanation := explainAmount("Service fee", feeCents,
+		explainServiceAreaRate("Service charge rate", rateCents.ToDollarString()+" per cwt", serviceArea),
+		explainCWT(cwt),
+	)
+	return FeeAndRate{Fee: feeCents, Rate: rateCents.ToMillicents(), Explanation: explanation}, nil
 }
 
 // fullPackCents Returns the NON-DISCOUNTED rate in millicents with the fee
@@ -73,12 +95,18 @@ func (re *RateEngine) fullPackCents(cwt unit.CWT, zip3 string, date time.Time) (
 		return FeeAndRate{}, err
 	}
 
-	fullPackRate, err := models.FetchTariff400ngFullPackRateCents(re.db, cwt.ToPounds(), serviceArea.ServicesSchedule, date)
+	fullPackRate, err := models.FetchTariff400ngFullPackRate(re.db, cwt.ToPounds(), serviceArea.ServicesSchedule, date)
 	if err != nil {
 		return FeeAndRate{}, err
 	}
 
-	return FeeAndRate{Fee: fullPackRate.Multiply(cwt.Int()), Rate: fullPackRate.ToMillicents()}, nil
+	feeCents := fullPackRate.RateCents.Multiply(cwt.Int())
+	explanation := explainAmount("Full pack", feeCents,
+		explainFullPackRate(fullPackRate),
+		explainServiceAreaRate("Services schedule", fmt.Sprintf("%d", serviceArea.ServicesSchedule), serviceArea),
+		explainCWT(cwt),
+	)
+	return FeeAndRate{Fee: feeCents, Rate: fullPackRate.RateCents.ToMillicents(), Explanation: explanation}, nil
 }
 
 // fullUnpackCents Returns the NON-DISCOUNTED rate in millicents with the fee
@@ -88,12 +116,18 @@ func (re *RateEngine) fullUnpackCents(cwt unit.CWT, zip3 string, date time.Time)
 		return FeeAndRate{}, err
 	}
 
-	fullUnpackRate, err := models.FetchTariff400ngFullUnpackRateMillicents(re.db, serviceArea.ServicesSchedule, date)
+	fullUnpackRate, err := models.FetchTariff400ngFullUnpackRate(re.db, serviceArea.ServicesSchedule, date)
 	if err != nil {
 		return FeeAndRate{}, err
 	}
 
-	return FeeAndRate{Fee: unit.Cents(math.Round(float64(cwt.Int()*fullUnpackRate) / 1000.0)), Rate: unit.Millicents(fullUnpackRate)}, nil
+	feeCents := unit.Cents(math.Round(float64(cwt.Int()*fullUnpackRate.RateMillicents) / 1000.0))
+	explanation := explainAmount("Full unpack", feeCents,
+		explainFullUnpackRate(fullUnpackRate),
+		explainServiceAreaRate("Services schedule", fmt.Sprintf("%d", serviceArea.ServicesSchedule), serviceArea),
+		explainCWT(cwt),
+	)
+	return FeeAndRate{Fee: feeCents, Rate: unit.Millicents(fullUnpackRate.RateMillicents), Explanation: explanation}, nil
 }
 
 // SitCharge calculates the SIT charge based on various factors.
@@ -107,8 +141,8 @@ func (re *RateEngine) SitCharge(cwt unit.CWT, daysInSIT int, zip3 string, date t
 
 	effectiveCWT := cwt
 	if !isPPM {
-		// An HHG uses a minimum weight of 1000 pounds.
-		// TODO: If an HHG shipment is delivered partially out of SIT (split deliveries), 1000 lb min does not apply.
+		// An HHG uses a minimum weight of 1000 pounds. This estimate assumes the whole shipment goes into SIT; split
+		// deliveries, where the minimum doesn't apply, are priced from their SIT line items instead.
 		minCWT := unit.Pound(1000).ToCWT()
 		if cwt < minCWT {
 			effectiveCWT = minCWT
@@ -163,22 +197,9 @@ func (re *RateEngine) SitCharge(cwt unit.CWT, daysInSIT int, zip3 string, date t
 			zap.Int("210A", rate210A.RateCents.Int()),
 			zap.Int("225A", rate225A.RateCents.Int()))
 	} else {
-		// Just return 185A and 185B parts of HHG for now.  Full implementation in later story.
-
-		// TODO: The rest of the HHG scenarios are as follows (to be added to the 185A and 185B parts):
-		//   * 30 miles or less from original delivery address to final delivery address (block 18 on GBL):
-		//       (185A SIT first day rate * CWT) +
-		//       (185B SIT additional day rate * additional days * CWT)
-		//       210A SIT PD 30 miles or less for SIT PD schedule of service area
-		//   * Between 31 and 50 miles from original delivery address to final delivery address (block 18 on GBL):
-		//       (185A SIT first day rate * CWT) +
-		//	     (185B SIT additional day rate * additional days * CWT)
-		//       210A SIT PD 30 miles or less for SIT PD schedule of service area +
-		//       210B SIT PD 30 to 50 miles SIT PD schedule of service area
-		//   * Over 50 miles from original delivery address to final delivery address (block 18 on GBL):
-		//       (185A SIT first day rate * CWT) +
-		//	     (185B SIT additional day rate * additional days * CWT)
-		//       210C SIT PD over 50 miles SIT PD schedule of service area
+		// Only the 185A and 185B parts are estimated for an HHG. Once the shipment is in SIT, its actual charges,
+		// including the 210A-C and 210F SIT P/D charges based on the distance to or from the warehouse, are priced as
+		// line items: see CreateStorageInTransitLineItems and ComputeShipmentLineItemCharge.
 	}
 
 	sitComputation := SITComputation{
@@ -201,10 +222,12 @@ func (re *RateEngine) nonLinehaulChargeComputation(weight unit.Pound, originZip5
 	if err != nil {
 		return cost, errors.Wrap(err, "Failed to  determine origin service fee")
 	}
+	cost.OriginService.Explanation.Description = "Origin service fee"
 	cost.DestinationService, err = re.serviceFeeCents(cwt, destinationZip3, date)
 	if err != nil {
 		return cost, errors.Wrap(err, "Failed to  determine destination service fee")
 	}
+	cost.DestinationService.Explanation.Description = "Destination service fee"
 	cost.Pack, err = re.fullPackCents(cwt, originZip3, date)
 	if err != nil {
 		return cost, errors.Wrap(err, "Failed to  determine full pack cost")
@@ -0,0 +1,62 @@
+package rateengine
+
+import (
+	"time"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// PricingOverrides replaces inputs to pricing a shipment, to see what it would cost under a different tariff year,
+// discount or fuel price. Nil fields are left as they are for the shipment.
+type PricingOverrides struct {
+	// Date replaces the shipment's book and pickup dates when looking up rates and fuel prices
+	Date *time.Time
+	// LinehaulDiscount replaces the TSP's linehaul discount
+	LinehaulDiscount *unit.DiscountRate
+	// SITDiscount replaces the TSP's SIT discount
+	SITDiscount *unit.DiscountRate
+	// FuelPricePerGallon replaces the EIA diesel price the fuel surcharge is based on
+	FuelPricePerGallon *unit.Millicents
+}
+
+// HasAny returns true if any input is overridden
+func (o PricingOverrides) HasAny() bool {
+	return o.Date != nil || o.LinehaulDiscount != nil || o.SITDiscount != nil || o.FuelPricePerGallon != nil
+}
+
+func (o PricingOverrides) date(date time.Time) time.Time {
+	if o.Date != nil {
+		return *o.Date
+	}
+	return date
+}
+
+func (o PricingOverrides) linehaulDiscount(discount unit.DiscountRate) unit.DiscountRate {
+	if o.LinehaulDiscount != nil {
+		return *o.LinehaulDiscount
+	}
+	return discount
+}
+
+func (o PricingOverrides) sitDiscount(discount unit.DiscountRate) unit.DiscountRate {
+	if o.SITDiscount != nil {
+		return *o.SITDiscount
+	}
+	return discount
+}
+
+// fuelPrice replaces the price and baseline rate of an EIA diesel price. The replaced price isn't from the database,
+// so its explanation has no source.
+func (o PricingOverrides) fuelPrice(price models.FuelEIADieselPrice) models.FuelEIADieselPrice {
+	if o.FuelPricePerGallon != nil {
+		price.EIAPricePerGallonMillicents = *o.FuelPricePerGallon
+		price.BaselineRate = models.FuelSurchargeBaselineRate(o.FuelPricePerGallon.ToDollarFloat())
+	}
+	return price
+}
+
+// WithOverrides returns a copy of the rate engine that prices using the given overrides
+func (re *RateEngine) WithOverrides(overrides PricingOverrides) *RateEngine {
+	return &RateEngine{db: re.db, logger: re.logger, overrides: overrides}
+}
@@ -1,11 +1,36 @@
 package rateengine
 
 import (
+	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/unit"
 )
 
 type pricer interface {
-	price(rate unit.Cents, q1 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents
+	price(rate unit.Cents, q1 unit.BaseQuantity, q2 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents
+	// explain explains how the pricer applies the rate to the quantities, not including any discount
+	explain(q1 unit.BaseQuantity, q2 unit.BaseQuantity) []models.PriceExplanation
+}
+
+func explainQuantity(q1 unit.BaseQuantity) models.PriceExplanation {
+	return explainDetail("Quantity", "%s", q1.ToUnitFloatString())
+}
+
+// explainMinimumWeight explains the minimum weight rule, if it applies to the weight
+func explainMinimumWeight(weight unit.BaseQuantity, min int) []models.PriceExplanation {
+	explanations := []models.PriceExplanation{explainDetail("Weight", "%d lbs", weight.ToUnitInt())}
+	if weight.ToUnitInt() < min {
+		explanations = append(explanations, explainDetail("Minimum weight", "%d lbs, used because the weight is below it", min))
+	}
+	return explanations
+}
+
+// explainMinimumQuantity explains the minimum quantity rule, if it applies to the quantity
+func explainMinimumQuantity(q1 unit.BaseQuantity, min int) []models.PriceExplanation {
+	explanations := []models.PriceExplanation{explainQuantity(q1)}
+	if q1.ToUnitFloat() < float64(min) {
+		explanations = append(explanations, explainDetail("Minimum quantity", "%d, used because the quantity is below it", min))
+	}
+	return explanations
 }
 
 // Basic pricer, multiplies the rate against the provided quantity
@@ -15,7 +40,7 @@ func newBasicQuantityPricer() basicQuantityPricer {
 	return basicQuantityPricer{}
 }
 
-func (m basicQuantityPricer) price(rate unit.Cents, q1 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents {
+func (m basicQuantityPricer) price(rate unit.Cents, q1 unit.BaseQuantity, q2 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents {
 	calculatedRate := rate.MultiplyFloat64(q1.ToUnitFloat())
 
 	if discount != nil {
@@ -25,6 +50,10 @@ func (m basicQuantityPricer) price(rate unit.Cents, q1 unit.BaseQuantity, discou
 	return calculatedRate
 }
 
+func (m basicQuantityPricer) explain(q1 unit.BaseQuantity, q2 unit.BaseQuantity) []models.PriceExplanation {
+	return []models.PriceExplanation{explainQuantity(q1)}
+}
+
 // Like the basic pricer, but enforces a minimum value for the quantity
 type minimumQuantityPricer struct {
 	min int
@@ -34,7 +63,7 @@ func newMinimumQuantityPricer(min int) minimumQuantityPricer {
 	return minimumQuantityPricer{min}
 }
 
-func (m minimumQuantityPricer) price(rate unit.Cents, q1 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents {
+func (m minimumQuantityPricer) price(rate unit.Cents, q1 unit.BaseQuantity, q2 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents {
 	if qConv := q1.ToUnitFloat(); qConv < float64(m.min) {
 		q1 = unit.BaseQuantityFromInt(m.min)
 	}
@@ -48,6 +77,10 @@ func (m minimumQuantityPricer) price(rate unit.Cents, q1 unit.BaseQuantity, disc
 	return calculatedRate
 }
 
+func (m minimumQuantityPricer) explain(q1 unit.BaseQuantity, q2 unit.BaseQuantity) []models.PriceExplanation {
+	return explainMinimumQuantity(q1, m.min)
+}
+
 // Line the min quantity pricer, but multiplies rate by quantity / 100
 type minimumQuantityHundredweightPricer struct {
 	min int
@@ -57,7 +90,7 @@ func newMinimumQuantityHundredweightPricer(min int) minimumQuantityHundredweight
 	return minimumQuantityHundredweightPricer{min}
 }
 
-func (m minimumQuantityHundredweightPricer) price(rate unit.Cents, q1 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents {
+func (m minimumQuantityHundredweightPricer) price(rate unit.Cents, q1 unit.BaseQuantity, q2 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents {
 	if qConv := q1.ToUnitFloat(); qConv < float64(m.min) {
 		q1 = unit.BaseQuantityFromInt(m.min)
 	}
@@ -71,6 +104,10 @@ func (m minimumQuantityHundredweightPricer) price(rate unit.Cents, q1 unit.BaseQ
 	return calculatedRate
 }
 
+func (m minimumQuantityHundredweightPricer) explain(q1 unit.BaseQuantity, q2 unit.BaseQuantity) []models.PriceExplanation {
+	return append(explainMinimumQuantity(q1, m.min), explainDetail("Priced per hundredweight", "the rate is applied to the quantity / 100"))
+}
+
 // Ignores quantity, just returns rate with discount applied
 type flatRatePricer struct{}
 
@@ -78,7 +115,7 @@ func newFlatRatePricer() flatRatePricer {
 	return flatRatePricer{}
 }
 
-func (m flatRatePricer) price(rate unit.Cents, q1 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents {
+func (m flatRatePricer) price(rate unit.Cents, q1 unit.BaseQuantity, q2 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents {
 	calculatedRate := rate
 
 	if discount != nil {
@@ -87,3 +124,37 @@ func (m flatRatePricer) price(rate unit.Cents, q1 unit.BaseQuantity, discount *u
 
 	return calculatedRate
 }
+
+func (m flatRatePricer) explain(q1 unit.BaseQuantity, q2 unit.BaseQuantity) []models.PriceExplanation {
+	return []models.PriceExplanation{explainDetail("Flat rate", "the quantity doesn't affect the charge")}
+}
+
+// Priced per hundredweight per day, like 185B SIT additional days. The first quantity is the number of days and the
+// second is the weight, which is subject to a minimum.
+type additionalDaysHundredweightPricer struct {
+	minWeight int
+}
+
+func newAdditionalDaysHundredweightPricer(minWeight int) additionalDaysHundredweightPricer {
+	return additionalDaysHundredweightPricer{minWeight}
+}
+
+func (m additionalDaysHundredweightPricer) price(rate unit.Cents, q1 unit.BaseQuantity, q2 unit.BaseQuantity, discount *unit.DiscountRate) unit.Cents {
+	if qConv := q2.ToUnitFloat(); qConv < float64(m.minWeight) {
+		q2 = unit.BaseQuantityFromInt(m.minWeight)
+	}
+
+	calculatedRate := rate.MultiplyFloat64(q1.ToUnitFloat() * q2.ToUnitFloat() / 100.0)
+
+	if discount != nil {
+		calculatedRate = discount.Apply(calculatedRate)
+	}
+
+	return calculatedRate
+}
+
+func (m additionalDaysHundredweightPricer) explain(q1 unit.BaseQuantity, q2 unit.BaseQuantity) []models.PriceExplanation {
+	explanations := []models.PriceExplanation{explainDetail("Days", "%d", q1.ToUnitInt())}
+	explanations = append(explanations, explainMinimumWeight(q2, m.minWeight)...)
+	return append(explanations, explainDetail("Priced per hundredweight per day", "the rate is applied to the days x weight / 100"))
+}
@@ -37,9 +37,35 @@ var pricersTestCases = []pricerTestCase{
 
 func (suite *RateEngineSuite) TestPricersTestCases() {
 	for i, testCase := range pricersTestCases {
-		result := testCase.pricer.price(testCase.rate, testCase.quantity, testCase.discount)
+		result := testCase.pricer.price(testCase.rate, testCase.quantity, unit.BaseQuantity(0), testCase.discount)
 		if !suite.Equal(result, testCase.expected) {
 			fmt.Printf("Failure on test case %d (0 indexed)\n", i)
 		}
 	}
 }
+
+type twoQuantityPricerTestCase struct {
+	pricer    pricer
+	rate      unit.Cents
+	quantity1 unit.BaseQuantity
+	quantity2 unit.BaseQuantity
+	discount  *unit.DiscountRate
+	expected  unit.Cents
+}
+
+var twoQuantityPricersTestCases = []twoQuantityPricerTestCase{
+	{newAdditionalDaysHundredweightPricer(1000), unit.Cents(100), unit.BaseQuantityFromInt(3), unit.BaseQuantityFromInt(2000), nil, unit.Cents(6000)},
+	{newAdditionalDaysHundredweightPricer(1000), unit.Cents(100), unit.BaseQuantityFromInt(3), unit.BaseQuantityFromInt(500), nil, unit.Cents(3000)},
+	{newAdditionalDaysHundredweightPricer(1000), unit.Cents(100), unit.BaseQuantityFromInt(3), unit.BaseQuantityFromInt(2000), discountPtr(0.5), unit.Cents(3000)},
+	{newAdditionalDaysHundredweightPricer(0), unit.Cents(100), unit.BaseQuantityFromInt(3), unit.BaseQuantityFromInt(500), nil, unit.Cents(1500)},
+	{newAdditionalDaysHundredweightPricer(1000), unit.Cents(100), unit.BaseQuantityFromInt(0), unit.BaseQuantityFromInt(2000), nil, unit.Cents(0)},
+}
+
+func (suite *RateEngineSuite) TestTwoQuantityPricersTestCases() {
+	for i, testCase := range twoQuantityPricersTestCases {
+		result := testCase.pricer.price(testCase.rate, testCase.quantity1, testCase.quantity2, testCase.discount)
+		if !suite.Equal(testCase.expected, result) {
+			fmt.Printf("Failure on test case %d (0 indexed)\n", i)
+		}
+	}
+}
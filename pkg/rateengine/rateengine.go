@@ -20,8 +20,9 @@ const MaxSITDays = 90
 
 // RateEngine encapsulates the TSP rate engine process
 type RateEngine struct {
-	db     *pop.Connection
-	logger Logger
+	db        *pop.Connection
+	logger    Logger
+	overrides PricingOverrides
 }
 
 // CostComputation represents the results of a computation.
@@ -48,6 +49,14 @@ func (c *CostComputation) Scale(factor float64) {
 	c.GCC = c.GCC.MultiplyFloat64(factor)
 }
 
+// explainProratedCharges explains the charges that were scaled because the weight was below the minimum weight
+func (c *CostComputation) explainProratedCharges(weight unit.Pound, factor float64) {
+	c.LinehaulChargeExplanation = explainProration(c.LinehaulChargeExplanation, weight, factor, c.LinehaulChargeTotal)
+	for _, fee := range []*FeeAndRate{&c.OriginService, &c.DestinationService, &c.Pack, &c.Unpack} {
+		fee.Explanation = explainProration(fee.Explanation, weight, factor, fee.Fee)
+	}
+}
+
 // MarshalLogObject allows CostComputation to be logged by Zap.
 func (c CostComputation) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
 	if err := encoder.AddObject("Linehaul Components", c.LinehaulCostComputation); err != nil {
@@ -93,6 +102,7 @@ func (re *RateEngine) ComputePPM(
 
 	// Weights below 1000lbs are prorated to the 1000lb rate
 	prorateFactor := 1.0
+	actualWeight := weight
 	if weight.Int() < 1000 {
 		prorateFactor = weight.Float64() / 1000.0
 		weight = unit.Pound(1000)
@@ -113,11 +123,8 @@ func (re *RateEngine) ComputePPM(
 	}
 
 	// Apply linehaul discounts
-	linehaulCostComputation.LinehaulChargeTotal = lhDiscount.Apply(linehaulCostComputation.LinehaulChargeTotal)
-	nonLinehaulCostComputation.OriginService.Fee = lhDiscount.Apply(nonLinehaulCostComputation.OriginService.Fee)
-	nonLinehaulCostComputation.DestinationService.Fee = lhDiscount.Apply(nonLinehaulCostComputation.DestinationService.Fee)
-	nonLinehaulCostComputation.Pack.Fee = lhDiscount.Apply(nonLinehaulCostComputation.Pack.Fee)
-	nonLinehaulCostComputation.Unpack.Fee = lhDiscount.Apply(nonLinehaulCostComputation.Unpack.Fee)
+	linehaulCostComputation.applyDiscount(lhDiscount)
+	nonLinehaulCostComputation.applyDiscount(lhDiscount)
 
 	// SIT
 	// Note that SIT has a different discount rate than [non]linehaul charges
@@ -158,6 +165,9 @@ func (re *RateEngine) ComputePPM(
 
 	// Finally, scale by prorate factor
 	cost.Scale(prorateFactor)
+	if prorateFactor != 1.0 {
+		cost.explainProratedCharges(actualWeight, prorateFactor)
+	}
 
 	re.logger.Info("PPM cost computation", zap.Object("cost", cost))
 
@@ -211,8 +221,8 @@ func (re *RateEngine) ComputeShipment(
 		weight = unit.Pound(1000)
 	}
 
-	pickupDate := time.Time(*shipment.ActualPickupDate)
-	bookDate := time.Time(*shipment.BookDate)
+	pickupDate := re.overrides.date(time.Time(*shipment.ActualPickupDate))
+	bookDate := re.overrides.date(time.Time(*shipment.BookDate))
 
 	originZip := distanceCalculation.OriginAddress.PostalCode
 	destinationZip := distanceCalculation.DestinationAddress.PostalCode
@@ -233,11 +243,8 @@ func (re *RateEngine) ComputeShipment(
 	}
 
 	// Apply linehaul discounts to fee
-	linehaulCostComputation.LinehaulChargeTotal = lhDiscount.Apply(linehaulCostComputation.LinehaulChargeTotal)
-	nonLinehaulCostComputation.OriginService.Fee = lhDiscount.Apply(nonLinehaulCostComputation.OriginService.Fee)
-	nonLinehaulCostComputation.DestinationService.Fee = lhDiscount.Apply(nonLinehaulCostComputation.DestinationService.Fee)
-	nonLinehaulCostComputation.Pack.Fee = lhDiscount.Apply(nonLinehaulCostComputation.Pack.Fee)
-	nonLinehaulCostComputation.Unpack.Fee = lhDiscount.Apply(nonLinehaulCostComputation.Unpack.Fee)
+	linehaulCostComputation.applyDiscount(lhDiscount)
+	nonLinehaulCostComputation.applyDiscount(lhDiscount)
 
 	// Apply linehaul discount to rate
 	// For rates with retrieved tariff rates in cents, must use ApplyToMillicents by dividing by 1000 to maintain cent level accuracy (and avoid millicent accuracy)
@@ -298,6 +305,9 @@ func (re *RateEngine) ComputeShipment(
 
 	// Finally, scale by prorate factor
 	cost.Scale(prorateFactor)
+	if prorateFactor != 1.0 {
+		cost.explainProratedCharges(*shipment.NetWeight, prorateFactor)
+	}
 
 	re.logger.Info("ComputeShipment() cost computation", zap.Object("cost", cost))
 
@@ -351,7 +361,7 @@ func (re *RateEngine) HandleRunOnShipment(shipment models.Shipment, distanceCalc
 	var sitDiscount unit.DiscountRate
 	sitDiscount = 0.0
 
-	lhDiscount := acceptedOffer.TransportationServiceProviderPerformance.LinehaulRate
+	lhDiscount := re.overrides.linehaulDiscount(acceptedOffer.TransportationServiceProviderPerformance.LinehaulRate)
 
 	// Apply rate engine to shipment
 	var shipmentCost CostByShipment
@@ -0,0 +1,138 @@
+package route
+
+import (
+	"math"
+
+	"github.com/gobuffalo/pop"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// earthRadiusMiles is the mean radius of the earth used for great-circle distances
+const earthRadiusMiles = 3958.8
+
+// DefaultRoadFactor is a typical ratio of road distance to great-circle distance in the continental US
+const DefaultRoadFactor = 1.2
+
+// GreatCircleDistance returns the great-circle (haversine) distance in miles between two points
+func GreatCircleDistance(source LatLong, destination LatLong) float64 {
+	toRadians := func(degrees float32) float64 {
+		return float64(degrees) * math.Pi / 180
+	}
+	sLat, dLat := toRadians(source.Latitude), toRadians(destination.Latitude)
+	deltaLat := dLat - sLat
+	deltaLong := toRadians(destination.Longitude) - toRadians(source.Longitude)
+
+	a := math.Pow(math.Sin(deltaLat/2), 2) + math.Cos(sLat)*math.Cos(dLat)*math.Pow(math.Sin(deltaLong/2), 2)
+	return 2 * earthRadiusMiles * math.Asin(math.Sqrt(a))
+}
+
+// compositePlanner answers with a primary planner, caching zip5 answers in the database and falling back to a
+// great-circle estimate when the primary planner fails
+type compositePlanner struct {
+	logger        Logger
+	db            *pop.Connection
+	primary       Planner
+	primarySource models.DistanceSource
+	roadFactor    float64
+}
+
+// NewCompositePlanner constructs a Planner that wraps primary with a zip5 distance cache and an offline
+// great-circle fallback. The cache is skipped if db is nil.
+func NewCompositePlanner(logger Logger, db *pop.Connection, primary Planner, primarySource models.DistanceSource, roadFactor float64) Planner {
+	return &compositePlanner{
+		logger:        logger,
+		db:            db,
+		primary:       primary,
+		primarySource: primarySource,
+		roadFactor:    roadFactor,
+	}
+}
+
+func (p *compositePlanner) greatCircleTransitDistance(source LatLong, destination LatLong) int {
+	return int(math.Round(GreatCircleDistance(source, destination) * p.roadFactor))
+}
+
+// TransitDistance uses the primary planner for the full addresses, falling back to the distance between the zip5s
+func (p *compositePlanner) TransitDistance(source *models.Address, destination *models.Address) (int, error) {
+	distance, err := p.primary.TransitDistance(source, destination)
+	if err == nil {
+		return distance, nil
+	}
+	p.logger.Error("Primary route planner failed for addresses, falling back to zip5 distance",
+		zap.String("source", string(p.primarySource)), zap.Error(err))
+	return p.Zip5TransitDistance(zip5(source.PostalCode), zip5(destination.PostalCode))
+}
+
+// LatLongTransitDistance uses the primary planner, falling back to a great-circle estimate
+func (p *compositePlanner) LatLongTransitDistance(source LatLong, destination LatLong) (int, error) {
+	distance, err := p.primary.LatLongTransitDistance(source, destination)
+	if err == nil {
+		return distance, nil
+	}
+	p.logger.Error("Primary route planner failed for lat/long, falling back to great-circle distance",
+		zap.String("source", string(p.primarySource)), zap.Error(err))
+	return p.greatCircleTransitDistance(source, destination), nil
+}
+
+// Zip5TransitDistance returns the cached distance between two zip5s if there is one. Otherwise, it asks the
+// primary planner, falling back to a great-circle estimate, and caches the answer along with its source.
+// Cached great-circle estimates are replaced once the primary planner is able to answer.
+func (p *compositePlanner) Zip5TransitDistance(source string, destination string) (int, error) {
+	var cached *models.Zip5DistanceCalculation
+	if p.db != nil {
+		var err error
+		cached, err = models.FetchZip5DistanceCalculation(p.db, source, destination)
+		if err != nil {
+			p.logger.Error("Failed to fetch cached zip5 distance", zap.Error(err))
+		}
+		if cached != nil && cached.Source != models.DistanceSourceGREATCIRCLE {
+			return cached.DistanceMiles, nil
+		}
+	}
+
+	sLL, err := Zip5ToLatLong(source)
+	if err != nil {
+		return 0, err
+	}
+	dLL, err := Zip5ToLatLong(destination)
+	if err != nil {
+		return 0, err
+	}
+
+	resultSource := p.primarySource
+	distance, err := p.primary.LatLongTransitDistance(sLL, dLL)
+	if err != nil {
+		if cached != nil {
+			// Still can't reach the primary planner, so the cached estimate is as good as we'll get
+			return cached.DistanceMiles, nil
+		}
+		p.logger.Error("Primary route planner failed for zip5s, falling back to great-circle distance",
+			zap.String("source", string(p.primarySource)), zap.Error(err))
+		resultSource = models.DistanceSourceGREATCIRCLE
+		distance = p.greatCircleTransitDistance(sLL, dLL)
+	}
+
+	p.logger.Info("Calculated zip5 transit distance",
+		zap.String("origin_zip5", source),
+		zap.String("destination_zip5", destination),
+		zap.Int("distance_miles", distance),
+		zap.String("distance_source", string(resultSource)))
+
+	if p.db != nil {
+		_, verrs, saveErr := models.SaveZip5DistanceCalculation(p.db, source, destination, distance, resultSource)
+		if saveErr != nil || verrs.HasAny() {
+			p.logger.Error("Failed to cache zip5 distance", zap.Error(saveErr), zap.String("verrs", verrs.String()))
+		}
+	}
+	return distance, nil
+}
+
+// zip5 trims a zip+4 postal code down to its zip5
+func zip5(postalCode string) string {
+	if len(postalCode) > 5 {
+		return postalCode[:5]
+	}
+	return postalCode
+}
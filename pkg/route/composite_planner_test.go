@@ -0,0 +1,71 @@
+package route
+
+import (
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// unavailablePlanner is a Planner that always fails, like HERE does when it is down
+type unavailablePlanner struct{}
+
+func (p unavailablePlanner) TransitDistance(source *models.Address, destination *models.Address) (int, error) {
+	return 0, NewUnknownAddressLookupError(503, source)
+}
+
+func (p unavailablePlanner) LatLongTransitDistance(source LatLong, destination LatLong) (int, error) {
+	return 0, NewUnknownRoutingError(503, source, destination)
+}
+
+func (p unavailablePlanner) Zip5TransitDistance(source string, destination string) (int, error) {
+	return zip5TransitDistanceHelper(p, source, destination)
+}
+
+func (suite *PlannerSuite) TestGreatCircleDistance() {
+	source := LatLong{Latitude: 31.150939, Longitude: -99.337237}
+	destination := LatLong{Latitude: 34.032383, Longitude: -119.1343}
+
+	distance := GreatCircleDistance(source, destination)
+	suite.InDelta(1168, distance, 5)
+	suite.Equal(float64(0), GreatCircleDistance(source, source))
+}
+
+func (suite *PlannerSuite) TestCompositePlannerUsesPrimary() {
+	planner := NewCompositePlanner(suite.logger, nil, NewTestingPlanner(1234), models.DistanceSourceTESTING, DefaultRoadFactor)
+
+	distance, err := planner.Zip5TransitDistance(bradyTXZip, venturaCAZip)
+	suite.NoError(err)
+	suite.Equal(1234, distance)
+
+	distance, err = planner.TransitDistance(&realAddressSource, &realAddressDestination)
+	suite.NoError(err)
+	suite.Equal(1234, distance)
+}
+
+func (suite *PlannerSuite) TestCompositePlannerFallsBackToGreatCircle() {
+	planner := NewCompositePlanner(suite.logger, nil, unavailablePlanner{}, models.DistanceSourceHERE, 1.5)
+
+	sLL, err := Zip5ToLatLong(bradyTXZip)
+	suite.NoError(err)
+	dLL, err := Zip5ToLatLong(venturaCAZip)
+	suite.NoError(err)
+	expected := int(GreatCircleDistance(sLL, dLL)*1.5 + 0.5)
+
+	distance, err := planner.Zip5TransitDistance(bradyTXZip, venturaCAZip)
+	suite.NoError(err)
+	suite.Equal(expected, distance)
+
+	distance, err = planner.LatLongTransitDistance(sLL, dLL)
+	suite.NoError(err)
+	suite.Equal(expected, distance)
+
+	// Full addresses fall back to the distance between their zip5s
+	source := realAddressSource
+	source.PostalCode = bradyTXZip + "-1234"
+	destination := realAddressDestination
+	destination.PostalCode = venturaCAZip
+	distance, err = planner.TransitDistance(&source, &destination)
+	suite.NoError(err)
+	suite.Equal(expected, distance)
+
+	_, err = planner.Zip5TransitDistance("00000", venturaCAZip)
+	suite.Error(err)
+}
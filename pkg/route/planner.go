@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gobuffalo/pop"
 	"github.com/spf13/viper"
 
 	"github.com/transcom/mymove/pkg/cli"
@@ -65,13 +66,17 @@ type Planner interface {
 }
 
 // InitRoutePlanner validates Route Planner command line flags
-func InitRoutePlanner(v *viper.Viper, logger Logger) Planner {
+func InitRoutePlanner(v *viper.Viper, logger Logger, db *pop.Connection) Planner {
 	hereClient := &http.Client{Timeout: hereRequestTimeout}
-	return NewHEREPlanner(
+	herePlanner := NewHEREPlanner(
 		logger,
 		hereClient,
 		v.GetString(cli.HEREMapsGeocodeEndpointFlag),
 		v.GetString(cli.HEREMapsRoutingEndpointFlag),
 		v.GetString(cli.HEREMapsAppIDFlag),
 		v.GetString(cli.HEREMapsAppCodeFlag))
+	if v.GetString(cli.RoutePlannerFlag) == "composite" {
+		return NewCompositePlanner(logger, db, herePlanner, models.DistanceSourceHERE, v.GetFloat64(cli.RouteRoadFactorFlag))
+	}
+	return herePlanner
 }
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"github.com/gobuffalo/validate"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// AwardQueuePolicyListFetcher is the exported interface for fetching multiple award queue policies
+//go:generate mockery -name AwardQueuePolicyListFetcher
+type AwardQueuePolicyListFetcher interface {
+	FetchAwardQueuePolicyList(filters []QueryFilter) (models.AwardQueuePolicies, error)
+}
+
+// AwardQueuePolicyCreator is the exported interface for creating a new version of an award queue policy
+//go:generate mockery -name AwardQueuePolicyCreator
+type AwardQueuePolicyCreator interface {
+	CreateAwardQueuePolicy(policy *models.AwardQueuePolicy) (*validate.Errors, error)
+}
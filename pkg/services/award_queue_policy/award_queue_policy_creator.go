@@ -0,0 +1,33 @@
+package awardqueuepolicy
+
+import (
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/services"
+)
+
+type awardQueuePolicyCreator struct {
+	db *pop.Connection
+}
+
+// CreateAwardQueuePolicy saves a policy as the newest version, so that it takes precedence over any
+// existing policies with overlapping effective dates. Concurrent creates are rejected by the unique
+// index on version.
+func (a *awardQueuePolicyCreator) CreateAwardQueuePolicy(policy *models.AwardQueuePolicy) (*validate.Errors, error) {
+	version, err := models.NextAwardQueuePolicyVersion(a.db)
+	if err != nil {
+		return validate.NewErrors(), err
+	}
+	policy.ID = uuid.Nil
+	policy.Version = version
+
+	return a.db.ValidateAndCreate(policy)
+}
+
+// NewAwardQueuePolicyCreator returns an implementation of AwardQueuePolicyCreator
+func NewAwardQueuePolicyCreator(db *pop.Connection) services.AwardQueuePolicyCreator {
+	return &awardQueuePolicyCreator{db}
+}
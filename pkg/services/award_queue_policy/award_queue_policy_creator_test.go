@@ -0,0 +1,35 @@
+package awardqueuepolicy
+
+import (
+	"github.com/gobuffalo/pop/slices"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *AwardQueuePolicyServiceSuite) TestCreateAwardQueuePolicy() {
+	existing := testdatagen.MakeDefaultAwardQueuePolicy(suite.DB())
+	creator := NewAwardQueuePolicyCreator(suite.DB())
+
+	policy := models.AwardQueuePolicy{
+		EffectiveStartDate:      testdatagen.PerformancePeriodStart,
+		EffectiveEndDate:        testdatagen.PerformancePeriodEnd,
+		MinimumPerformanceScore: 20,
+		OffersPerQualityBand:    slices.Int{5, 3, 2, 1},
+	}
+	verrs, err := creator.CreateAwardQueuePolicy(&policy)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+	suite.Equal(existing.Version+1, policy.Version)
+
+	invalid := models.AwardQueuePolicy{
+		EffectiveStartDate:   testdatagen.PerformancePeriodEnd,
+		EffectiveEndDate:     testdatagen.PerformancePeriodStart,
+		OffersPerQualityBand: slices.Int{},
+	}
+	verrs, err = creator.CreateAwardQueuePolicy(&invalid)
+	suite.NoError(err)
+	suite.True(verrs.HasAny())
+	suite.NotEmpty(verrs.Get("effective_start_date"))
+	suite.NotEmpty(verrs.Get("offers_per_quality_band"))
+}
@@ -0,0 +1,26 @@
+package awardqueuepolicy
+
+import (
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/services"
+)
+
+type awardQueuePolicyListQueryBuilder interface {
+	FetchMany(model interface{}, filters []services.QueryFilter) error
+}
+
+type awardQueuePolicyListFetcher struct {
+	builder awardQueuePolicyListQueryBuilder
+}
+
+// FetchAwardQueuePolicyList uses the passed query builder to fetch a list of award queue policies
+func (a *awardQueuePolicyListFetcher) FetchAwardQueuePolicyList(filters []services.QueryFilter) (models.AwardQueuePolicies, error) {
+	var policies models.AwardQueuePolicies
+	err := a.builder.FetchMany(&policies, filters)
+	return policies, err
+}
+
+// NewAwardQueuePolicyListFetcher returns an implementation of AwardQueuePolicyListFetcher
+func NewAwardQueuePolicyListFetcher(builder awardQueuePolicyListQueryBuilder) services.AwardQueuePolicyListFetcher {
+	return &awardQueuePolicyListFetcher{builder}
+}
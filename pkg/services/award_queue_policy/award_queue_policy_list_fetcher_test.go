@@ -0,0 +1,63 @@
+package awardqueuepolicy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/services"
+	"github.com/transcom/mymove/pkg/services/query"
+)
+
+type testAwardQueuePolicyListQueryBuilder struct {
+	fakeFetchMany func(model interface{}) error
+}
+
+func (t *testAwardQueuePolicyListQueryBuilder) FetchMany(model interface{}, filters []services.QueryFilter) error {
+	return t.fakeFetchMany(model)
+}
+
+func (suite *AwardQueuePolicyServiceSuite) TestFetchAwardQueuePolicyList() {
+	suite.T().Run("if policies are fetched, they should be returned", func(t *testing.T) {
+		id, err := uuid.NewV4()
+		suite.NoError(err)
+		fakeFetchMany := func(model interface{}) error {
+			value := reflect.ValueOf(model).Elem()
+			value.Set(reflect.Append(value, reflect.ValueOf(models.AwardQueuePolicy{ID: id})))
+			return nil
+		}
+		builder := &testAwardQueuePolicyListQueryBuilder{
+			fakeFetchMany: fakeFetchMany,
+		}
+
+		fetcher := NewAwardQueuePolicyListFetcher(builder)
+		filters := []services.QueryFilter{
+			query.NewQueryFilter("id", "=", id.String()),
+		}
+
+		policies, err := fetcher.FetchAwardQueuePolicyList(filters)
+
+		suite.NoError(err)
+		suite.Equal(id, policies[0].ID)
+	})
+
+	suite.T().Run("if there is an error, we get it with no policies", func(t *testing.T) {
+		fakeFetchMany := func(model interface{}) error {
+			return errors.New("Fetch error")
+		}
+		builder := &testAwardQueuePolicyListQueryBuilder{
+			fakeFetchMany: fakeFetchMany,
+		}
+
+		fetcher := NewAwardQueuePolicyListFetcher(builder)
+
+		policies, err := fetcher.FetchAwardQueuePolicyList([]services.QueryFilter{})
+
+		suite.Error(err)
+		suite.Equal("Fetch error", err.Error())
+		suite.Equal(models.AwardQueuePolicies(nil), policies)
+	})
+}
@@ -0,0 +1,24 @@
+package awardqueuepolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/transcom/mymove/pkg/testingsuite"
+)
+
+type AwardQueuePolicyServiceSuite struct {
+	testingsuite.PopTestSuite
+}
+
+func (suite *AwardQueuePolicyServiceSuite) SetupTest() {
+	suite.DB().TruncateAll()
+}
+
+func TestAwardQueuePolicyServiceSuite(t *testing.T) {
+	ts := &AwardQueuePolicyServiceSuite{
+		PopTestSuite: testingsuite.NewPopTestSuite(testingsuite.CurrentPackage()),
+	}
+	suite.Run(t, ts)
+}
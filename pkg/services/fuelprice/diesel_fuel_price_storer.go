@@ -2,33 +2,20 @@ package fuelprice
 
 import (
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
-	"log"
-
-	"go.uber.org/zap"
-
-	"math"
 	"net/http"
-	"net/url"
-	"strconv"
 	"time"
 
 	"github.com/facebookgo/clock"
 	"github.com/gobuffalo/pop"
 	"github.com/gobuffalo/validate"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/dates"
 	"github.com/transcom/mymove/pkg/models"
-	"github.com/transcom/mymove/pkg/unit"
 )
 
-type fuelData struct {
-	dateString string
-	price      float64
-}
-
 // EiaRequestData encapsulates the Request portion of returned JSON
 type EiaRequestData struct {
 	Command  string `json:"command"`
@@ -51,25 +38,21 @@ type EiaData struct {
 type FetchFuelData func(string) (EiaData, error)
 
 // NewDieselFuelPriceStorer creates a new struct
-func NewDieselFuelPriceStorer(db *pop.Connection, logger *zap.Logger, clock clock.Clock, dataFetch FetchFuelData, eiaKey string, url string) *DieselFuelPriceStorer {
+func NewDieselFuelPriceStorer(db *pop.Connection, logger *zap.Logger, clock clock.Clock, source PriceSource) *DieselFuelPriceStorer {
 	return &DieselFuelPriceStorer{
-		DB:            db,
-		logger:        logger,
-		Clock:         clock,
-		FetchFuelData: dataFetch,
-		eiaKey:        eiaKey,
-		url:           url,
+		DB:     db,
+		logger: logger,
+		Clock:  clock,
+		source: source,
 	}
 }
 
 // DieselFuelPriceStorer is a service object to add missing fuel prices to db
 type DieselFuelPriceStorer struct {
-	DB            *pop.Connection
-	logger        *zap.Logger
-	Clock         clock.Clock
-	FetchFuelData FetchFuelData
-	eiaKey        string
-	url           string
+	DB     *pop.Connection
+	logger *zap.Logger
+	Clock  clock.Clock
+	source PriceSource
 }
 
 // StoreFuelPrices retrieves data for the months we do not have prices for, calculates them, and adds them to the database
@@ -91,46 +74,95 @@ func (u DieselFuelPriceStorer) StoreFuelPrices(numMonths int) (*validate.Errors,
 
 	//Save each month's fuel values to the db
 	for _, fuelValues := range fuelValuesByMonth {
-		if len(fuelValues.dateString) == 0 {
-			break
-		}
-		pricePerGallon := fuelValues.price
-		pubDateString := fuelValues.dateString
-		pubDate, parseErr := time.Parse("20060102", pubDateString) // must use this date Jan 2 2006 for layout
-		if parseErr != nil {
-			return verrs, errors.Wrap(parseErr, "unable to convert pubDate datestring to date")
+		if _, verrs, err = u.saveMonthPrice(fuelValues); err != nil || verrs.HasAny() {
+			return verrs, err
 		}
-		year := pubDate.Year()
-		month := pubDate.Month()
-
-		startDate := time.Date(year, month, 15, 0, 0, 0, 0, time.UTC)
-		endDate := time.Date(year, month+1, 14, 0, 0, 0, 0, time.UTC)
-		baselineRate, calculateFuelSurchargeBaseLineRateErr := u.calculateFuelSurchargeBaselineRate(pricePerGallon)
-		if calculateFuelSurchargeBaseLineRateErr != nil {
-			return verrs, errors.Wrap(calculateFuelSurchargeBaseLineRateErr, "Cannot calculate baseline rate")
+	}
+	return verrs, err
+}
+
+// Backfill fetches and stores a fuel price for each month from start to end, inclusive, that is missing one. It
+// returns the prices it stored; months the source has no prices for are left missing.
+func (u DieselFuelPriceStorer) Backfill(start time.Time, end time.Time) ([]models.FuelEIADieselPrice, *validate.Errors, error) {
+	var stored []models.FuelEIADieselPrice
+	missingMonths, err := models.FetchMissingFuelPriceMonths(u.DB, start, end)
+	if err != nil {
+		return stored, validate.NewErrors(), err
+	}
+	if len(missingMonths) == 0 {
+		u.logger.Info("No fuel prices missing to backfill")
+		return stored, validate.NewErrors(), nil
+	}
+
+	// A single request covers every missing month
+	firstMonth := missingMonths[0]
+	lastMonth := missingMonths[len(missingMonths)-1]
+	prices, err := u.source.FetchWeeklyPrices(firstMonth, lastMonth.AddDate(0, 1, -1))
+	if err != nil {
+		return stored, validate.NewErrors(), err
+	}
+
+	earliestByMonth := earliestPriceByMonth(prices)
+	for _, month := range missingMonths {
+		price, ok := earliestByMonth[month]
+		if !ok {
+			u.logger.Warn("No fuel price available to backfill", zap.String("month", month.Format("2006-01")))
+			continue
 		}
-		dollarPricePerGallon := unit.Dollars(pricePerGallon)
-
-		// Insert values into fuel_eia_diesel_prices
-		fuelPrice := models.FuelEIADieselPrice{
-			CreatedAt:                   time.Now(),
-			UpdatedAt:                   time.Now(),
-			PubDate:                     pubDate,
-			RateStartDate:               startDate,
-			RateEndDate:                 endDate,
-			EIAPricePerGallonMillicents: dollarPricePerGallon.ToMillicents(),
-			BaselineRate:                baselineRate,
+		fuelPrice, verrs, err := u.saveMonthPrice(price)
+		if err != nil || verrs.HasAny() {
+			return stored, verrs, err
 		}
-		responseVErrors := validate.NewErrors()
-		validateAndSaveVerrs, validateAndSaveErr := u.DB.ValidateAndSave(&fuelPrice)
+		stored = append(stored, fuelPrice)
+	}
+	return stored, validate.NewErrors(), nil
+}
+
+// saveMonthPrice stores the first weekly price published in a month as the fuel price for the rate period starting
+// on the 15th of that month
+func (u DieselFuelPriceStorer) saveMonthPrice(price WeeklyDieselPrice) (models.FuelEIADieselPrice, *validate.Errors, error) {
+	pubDate := price.PubDate
+	year := pubDate.Year()
+	month := pubDate.Month()
+
+	startDate := time.Date(year, month, 15, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(year, month+1, 14, 0, 0, 0, 0, time.UTC)
+	baselineRate, calculateFuelSurchargeBaseLineRateErr := u.calculateFuelSurchargeBaselineRate(float64(price.PricePerGallon))
+	if calculateFuelSurchargeBaseLineRateErr != nil {
+		return models.FuelEIADieselPrice{}, validate.NewErrors(), errors.Wrap(calculateFuelSurchargeBaseLineRateErr, "Cannot calculate baseline rate")
+	}
+
+	// Insert values into fuel_eia_diesel_prices
+	fuelPrice := models.FuelEIADieselPrice{
+		CreatedAt:                   time.Now(),
+		UpdatedAt:                   time.Now(),
+		PubDate:                     pubDate,
+		RateStartDate:               startDate,
+		RateEndDate:                 endDate,
+		EIAPricePerGallonMillicents: price.PricePerGallon.ToMillicents(),
+		BaselineRate:                baselineRate,
+	}
+	responseVErrors := validate.NewErrors()
+	validateAndSaveVerrs, validateAndSaveErr := u.DB.ValidateAndSave(&fuelPrice)
 
-		if validateAndSaveErr != nil || validateAndSaveVerrs.HasAny() {
-			responseVErrors.Append(validateAndSaveVerrs)
-			return responseVErrors, errors.Wrap(validateAndSaveErr, "Cannot validate and save fuel diesel price")
+	if validateAndSaveErr != nil || validateAndSaveVerrs.HasAny() {
+		responseVErrors.Append(validateAndSaveVerrs)
+		return fuelPrice, responseVErrors, errors.Wrap(validateAndSaveErr, "Cannot validate and save fuel diesel price")
+	}
+	u.logger.Info("Fuel Data added \n", zap.String("start date month", month.String()), zap.Time("pubDate", pubDate))
+	return fuelPrice, responseVErrors, nil
+}
+
+// earliestPriceByMonth picks the first weekly price published in each month, keyed by the first day of the month
+func earliestPriceByMonth(prices []WeeklyDieselPrice) map[time.Time]WeeklyDieselPrice {
+	earliest := map[time.Time]WeeklyDieselPrice{}
+	for _, price := range prices {
+		month := time.Date(price.PubDate.Year(), price.PubDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if current, ok := earliest[month]; !ok || price.PubDate.Before(current.PubDate) {
+			earliest[month] = price
 		}
-		u.logger.Info("Fuel Data added \n", zap.String("start date month", month.String()), zap.Time("pubDate", pubDate))
 	}
-	return verrs, err
+	return earliest
 }
 
 // FetchFuelPriceData is the function that fetches the actual fuel data
@@ -154,109 +186,41 @@ func FetchFuelPriceData(url string) (resultData EiaData, err error) {
 	return resultData, err
 }
 
-// getMissingRecordsPrices gets the data for each month that doesn't have data in the db and adds the data to struct
-func (u DieselFuelPriceStorer) getMissingRecordsPrices(missingMonths []int) (fuelValues []fuelData, err error) {
+// getMissingRecordsPrices gets the first price published in each month that doesn't have data in the db
+func (u DieselFuelPriceStorer) getMissingRecordsPrices(missingMonths []int) (fuelValues []WeeklyDieselPrice, err error) {
 	currentDate := u.Clock.Now()
 
-	// Do an api query for each month that needs a fuel price record
+	// Fetch prices for each month that needs a fuel price record
 	for _, month := range missingMonths {
-		// formulate the URL
-		var startDateString string
-		var endDateString string
 		var year int
-		startDayString := "01"
-		endDayString := "28" // this will capture the first Monday or day after holiday whose rates are used for the rate period
-
 		if month <= int(currentDate.Month()) {
 			year = currentDate.Year()
 		} else {
 			year = currentDate.AddDate(-1, 0, 0).Year()
 		}
-		monthString := fmt.Sprintf("%02s", strconv.Itoa(month))
-		startDateString = fmt.Sprintf("%d%s%s", year, monthString, startDayString)
-		endDateString = fmt.Sprintf("%d%s%s", year, monthString, endDayString)
-		parsedURL, parseErr := url.Parse(u.url)
-		if parseErr != nil {
-			log.Fatal(parseErr)
-		}
+		startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		endDate := time.Date(year, time.Month(month), 28, 0, 0, 0, 0, time.UTC) // this will capture the first Monday or day after holiday whose rates are used for the rate period
 
-		query := parsedURL.Query()
-		query.Set("api_key", u.eiaKey)
-		query.Set("series_id", "PET.EMD_EPD2D_PTE_NUS_DPG.W")
-		query.Set("start", startDateString)
-		query.Set("end", endDateString)
-		parsedURL.RawQuery = query.Encode()
-		finalURL := parsedURL.String()
-
-		// fetch the data
-		result, fetchFuelDataErr := u.FetchFuelData(finalURL)
-		if fetchFuelDataErr != nil {
-			return nil, errors.Wrap(fetchFuelDataErr, "problem fetching fuel data")
+		monthFuelData, fetchErr := u.source.FetchWeeklyPrices(startDate, endDate)
+		if fetchErr != nil {
+			return nil, fetchErr
 		}
 
-		// handle all possible responses
-		if len(result.OtherData) != 0 {
-			if result.OtherData["error"] == 1 {
-				errMsg, ok := result.OtherData["error"].(string)
-				if !ok {
-					return nil, errors.New("data returned from api as error failed string type assertion")
-				}
-				return nil, errors.New(errMsg)
-			}
-			return nil, errors.New("Unexpected response from GET request to eia.gov's open data")
-		} else if len(result.SeriesData) == 0 {
-			return nil, errors.New("GET request to eia.gov's open data was unsuccessful")
+		// select the fuel data for the first week of data available for the month
+		if price, ok := earliestPriceByMonth(monthFuelData)[startDate]; ok {
+			fuelValues = append(fuelValues, price)
+			continue
 		}
-		monthFuelData := result.SeriesData[0].Data
 
-		// select the fuel data for the first week of data available for the month
-		dateString := ""
-		var ok bool
-		var price float64
-		if len(monthFuelData) >= 1 {
-			weekIndex := 0
-			var min int
-
-			// find earliest date(String) in the month
-			for i, weekData := range monthFuelData {
-				dateString, ok = weekData[0].(string)
-				if !ok {
-					return nil, errors.New("data returned from api as pub_date failed string type assertion")
-				}
-				price, ok = weekData[1].(float64)
-				if !ok {
-					return nil, errors.New("data returned as fuel price failed float64 type assertion")
-				}
-				pubDateAsInt, atoiErr := strconv.Atoi(dateString)
-				if atoiErr != nil {
-					return nil, errors.Wrap(atoiErr, "pubDate conversion from string to int")
-				}
-				if i == 0 || pubDateAsInt < min {
-					min = pubDateAsInt
-					weekIndex = i
-				}
-			}
-			dateString, ok = monthFuelData[weekIndex][0].(string)
-			if !ok {
-				return nil, errors.New("data returned from api as pub_date failed string type assertion")
+		// Throw error if data should be available but is not
+		if month == int(currentDate.Month()) {
+			firstMondayOrNonHolidayAfter := getFirstMondayOrNonHolidayAfter(startDate)
+			todayIsAfterPostingDate := !firstMondayOrNonHolidayAfter.After(currentDate)
+			if todayIsAfterPostingDate {
+				return []WeeklyDieselPrice{}, errors.Errorf("Expected data, but no fuel data available for %d %d", time.Month(month), year)
 			}
-			price, ok = monthFuelData[weekIndex][1].(float64)
-			if !ok {
-				return nil, errors.New("data returned as fuel price failed float64 type assertion")
-			}
-		} else if len(monthFuelData) == 0 {
-			// Throw error if data should be available but is not
-			if month == int(currentDate.Month()) {
-				firstMondayOrNonHolidayAfter := getFirstMondayOrNonHolidayAfter(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC))
-				todayIsAfterPostingDate := !firstMondayOrNonHolidayAfter.After(currentDate)
-				if todayIsAfterPostingDate {
-					return []fuelData{}, errors.Errorf("Expected data, but no fuel data available for %d %d", time.Month(month), year)
-				}
-			}
-			log.Printf("No fueldata available yet for %d %d \n", time.Month(month), year)
 		}
-
-		fuelValues = append(fuelValues, fuelData{dateString: dateString, price: price})
+		u.logger.Info("No fuel data available yet", zap.String("month", startDate.Format("2006-01")))
 	}
 	return fuelValues, err
 }
@@ -294,19 +258,7 @@ func (u DieselFuelPriceStorer) findMissingRecordMonths(db *pop.Connection, numMo
 }
 
 func (u DieselFuelPriceStorer) calculateFuelSurchargeBaselineRate(pricePerGallon float64) (baselineRate int64, err error) {
-	// Calculate fuel surcharge based on price per gallon based on government-provided calculation
-	// Rate formula found at https://www.sddc.army.mil/res/PublicationsAndPolicies/TR-12%20FRA%20Policy.docx
-	// Formula to get baseline rate: (fuelprice - baseline)/.13 rounded up; if the fuel price is greater than or equal to baseline, the baseline rate is 0
-	fuelPriceBaseline := 2.5
-	dividendValue := .13
-	diffPriceAndBaseline := pricePerGallon - fuelPriceBaseline
-	if diffPriceAndBaseline <= 0 {
-		baselineRate = int64(0)
-	} else {
-		rate := (diffPriceAndBaseline) / dividendValue
-		baselineRate = int64(math.Ceil(rate))
-	}
-	return baselineRate, nil
+	return models.FuelSurchargeBaselineRate(pricePerGallon), nil
 }
 
 // intInSlice checks if an integer exists within the given slice
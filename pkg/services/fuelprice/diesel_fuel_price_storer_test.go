@@ -47,7 +47,7 @@ func (suite *FuelPriceServiceSuite) TestStoreFuelPrices() {
 		dateToTest = time.Date(2010, time.January, 2, 0, 0, 0, 0, time.UTC) // first Mon 1/2010 is 4th
 		timeDiff = dateToTest.Sub(prePubDateTestClock.Now().UTC())
 		prePubDateTestClock.Add(timeDiff)
-		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, prePubDateTestClock, mockedFetchFuelPriceData, "", "No data available yet this month")
+		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, prePubDateTestClock, NewEIAPriceSource(mockedFetchFuelPriceData, "", "No data available yet this month"))
 		verrs, err := dieselFuelPriceStorer.StoreFuelPrices(numMonthsToVerify)
 		suite.NoError(err)
 		suite.Empty(verrs.Errors)
@@ -62,7 +62,7 @@ func (suite *FuelPriceServiceSuite) TestStoreFuelPrices() {
 
 	// Test case where there is no data for a given month (but should be)
 	suite.T().Run("no data available for current month (though expected)", func(t *testing.T) {
-		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, mockedFetchFuelPriceData, "", "Data missing but expected")
+		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, NewEIAPriceSource(mockedFetchFuelPriceData, "", "Data missing but expected"))
 		verrs, err := dieselFuelPriceStorer.StoreFuelPrices(numMonthsToVerify)
 		suite.Error(err)
 		suite.Empty(verrs.Errors)
@@ -75,7 +75,7 @@ func (suite *FuelPriceServiceSuite) TestStoreFuelPrices() {
 		timeDiff = dateToTest.Sub(postMonHolidayTestClock.Now().UTC())
 		postMonHolidayTestClock.Add(timeDiff)
 
-		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, postMonHolidayTestClock, mockedFetchFuelPriceData, "", "Data missing but expected")
+		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, postMonHolidayTestClock, NewEIAPriceSource(mockedFetchFuelPriceData, "", "Data missing but expected"))
 		verrs, err := dieselFuelPriceStorer.StoreFuelPrices(numMonthsToVerify)
 		suite.Error(err)
 		suite.Empty(verrs.Errors)
@@ -83,21 +83,21 @@ func (suite *FuelPriceServiceSuite) TestStoreFuelPrices() {
 
 	// Test case where an error message is returned from api
 	suite.T().Run("error message returned from api", func(t *testing.T) {
-		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, mockedFetchFuelPriceData, "", "Error")
+		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, NewEIAPriceSource(mockedFetchFuelPriceData, "", "Error"))
 		verrs, err := dieselFuelPriceStorer.StoreFuelPrices(numMonthsToVerify)
 		suite.Error(err)
 		suite.Empty(verrs.Errors)
 	})
 	// Test case where api returns unexpected JSON structure/value
 	suite.T().Run("unexpected JSON structure returned from api", func(t *testing.T) {
-		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, mockedFetchFuelPriceData, "", "Unexpected response")
+		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, NewEIAPriceSource(mockedFetchFuelPriceData, "", "Unexpected response"))
 		verrs, err := dieselFuelPriceStorer.StoreFuelPrices(numMonthsToVerify)
 		suite.Error(err)
 		suite.Empty(verrs.Errors)
 	})
 
 	suite.T().Run("stores current month missing data", func(t *testing.T) {
-		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, mockedFetchFuelPriceData, "", "Stores current month missing data")
+		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, NewEIAPriceSource(mockedFetchFuelPriceData, "", "Stores current month missing data"))
 		verrs, err := dieselFuelPriceStorer.StoreFuelPrices(numMonthsToVerify)
 		suite.NoError(err)
 		suite.Empty(verrs.Errors)
@@ -130,7 +130,7 @@ func (suite *FuelPriceServiceSuite) TestStoreFuelPrices() {
 			suite.logger.Error("Error deleting eia diesel price", zap.Error(err))
 		}
 
-		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, mockedFetchFuelPriceData, "", "Store previous month missing data")
+		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, NewEIAPriceSource(mockedFetchFuelPriceData, "", "Store previous month missing data"))
 		verrs, err := dieselFuelPriceStorer.StoreFuelPrices(numMonthsToVerify)
 		suite.NoError(err)
 		suite.Empty(verrs.Errors)
@@ -157,7 +157,7 @@ func (suite *FuelPriceServiceSuite) TestStoreFuelPrices() {
 
 	// Test case where all desired data already exists in db
 	suite.T().Run("all desired data already exists in the db", func(t *testing.T) {
-		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, mockedFetchFuelPriceData, "", "No data needed")
+		dieselFuelPriceStorer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, testClock, NewEIAPriceSource(mockedFetchFuelPriceData, "", "No data needed"))
 		verrs, err := dieselFuelPriceStorer.StoreFuelPrices(numMonthsToVerify)
 		suite.NoError(err)
 		suite.Empty(verrs.Errors)
@@ -253,3 +253,55 @@ func mockedFetchFuelPriceData(url string) (data EiaData, err error) {
 	return EiaData{}, nil
 
 }
+
+type fakePriceSource struct {
+	prices []WeeklyDieselPrice
+}
+
+func (f fakePriceSource) FetchWeeklyPrices(start time.Time, end time.Time) ([]WeeklyDieselPrice, error) {
+	var prices []WeeklyDieselPrice
+	for _, price := range f.prices {
+		if !price.PubDate.Before(start) && !price.PubDate.After(end) {
+			prices = append(prices, price)
+		}
+	}
+	return prices, nil
+}
+
+func (suite *FuelPriceServiceSuite) TestBackfillFuelPrices() {
+	assertions := testdatagen.Assertions{}
+	assertions.FuelEIADieselPrice.PubDate = time.Date(2019, time.January, 7, 0, 0, 0, 0, time.UTC)
+	assertions.FuelEIADieselPrice.RateStartDate = time.Date(2019, time.January, 15, 0, 0, 0, 0, time.UTC)
+	january := testdatagen.MakeFuelEIADieselPriceForDate(suite.DB(), assertions.FuelEIADieselPrice.RateStartDate, assertions)
+
+	source := fakePriceSource{prices: []WeeklyDieselPrice{
+		{PubDate: time.Date(2019, time.January, 7, 0, 0, 0, 0, time.UTC), PricePerGallon: 9.99},
+		{PubDate: time.Date(2019, time.February, 11, 0, 0, 0, 0, time.UTC), PricePerGallon: 3.1},
+		{PubDate: time.Date(2019, time.February, 4, 0, 0, 0, 0, time.UTC), PricePerGallon: 2.967},
+	}}
+	storer := NewDieselFuelPriceStorer(suite.DB(), suite.logger, clock.NewMock(), source)
+
+	stored, verrs, err := storer.Backfill(
+		time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, time.March, 31, 0, 0, 0, 0, time.UTC))
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	// February is stored from its first weekly price; March has no price to store
+	suite.Len(stored, 1)
+	suite.Equal(time.Date(2019, time.February, 4, 0, 0, 0, 0, time.UTC), stored[0].PubDate)
+	suite.Equal(time.Date(2019, time.February, 15, 0, 0, 0, 0, time.UTC), stored[0].RateStartDate)
+	suite.Equal(time.Date(2019, time.March, 14, 0, 0, 0, 0, time.UTC), stored[0].RateEndDate)
+	suite.Equal(int64(4), stored[0].BaselineRate)
+
+	missing, err := models.FetchMissingFuelPriceMonths(suite.DB(),
+		time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, time.March, 31, 0, 0, 0, 0, time.UTC))
+	suite.NoError(err)
+	suite.Equal([]time.Time{time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC)}, missing)
+
+	// January already had a price, which is left alone
+	fetched, err := models.FetchFuelPriceForPubMonth(suite.DB(), january.PubDate)
+	suite.NoError(err)
+	suite.Equal(january.EIAPricePerGallonMillicents, fetched.EIAPricePerGallonMillicents)
+}
@@ -0,0 +1,132 @@
+package fuelprice
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/route"
+	"github.com/transcom/mymove/pkg/services/shipment"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// FuelPriceCorrection is the outcome of correcting a published fuel price
+type FuelPriceCorrection struct {
+	FuelPrice              models.FuelEIADieselPrice
+	PreviousPricePerGallon unit.Millicents
+	PreviousBaselineRate   int64
+	// RepricedShipmentIDs are the delivered shipments booked in the price's rate period that were repriced
+	RepricedShipmentIDs []uuid.UUID
+	// InvoicedShipmentIDs are the delivered shipments booked in the price's rate period that were left alone, as
+	// they have already been invoiced
+	InvoicedShipmentIDs []uuid.UUID
+}
+
+// FuelPriceCorrector is a service object to correct a published fuel price and reprice the shipments it affects
+type FuelPriceCorrector struct {
+	db      *pop.Connection
+	logger  *zap.Logger
+	planner route.Planner
+}
+
+// NewFuelPriceCorrector creates a new struct
+func NewFuelPriceCorrector(db *pop.Connection, logger *zap.Logger, planner route.Planner) *FuelPriceCorrector {
+	return &FuelPriceCorrector{
+		db:      db,
+		logger:  logger,
+		planner: planner,
+	}
+}
+
+// CorrectFuelPrice replaces the price published in the month of pubDate and reprices the delivered shipments booked
+// in its rate period, as their fuel surcharge is based on it. Shipments that have been invoiced are not repriced.
+// Nothing is saved unless every shipment is repriced.
+func (c FuelPriceCorrector) CorrectFuelPrice(pubDate time.Time, pricePerGallon unit.Dollars) (FuelPriceCorrection, *validate.Errors, error) {
+	var correction FuelPriceCorrection
+	verrs := validate.NewErrors()
+
+	fuelPrice, err := models.FetchFuelPriceForPubMonth(c.db, pubDate)
+	if err != nil {
+		return correction, verrs, err
+	}
+	correction.PreviousPricePerGallon = fuelPrice.EIAPricePerGallonMillicents
+	correction.PreviousBaselineRate = fuelPrice.BaselineRate
+
+	fuelPrice.EIAPricePerGallonMillicents = pricePerGallon.ToMillicents()
+	fuelPrice.BaselineRate = models.FuelSurchargeBaselineRate(float64(pricePerGallon))
+
+	transactionErr := c.db.Transaction(func(tx *pop.Connection) error {
+		var updateErr error
+		verrs, updateErr = tx.ValidateAndUpdate(&fuelPrice)
+		if updateErr != nil {
+			return errors.Wrap(updateErr, "Cannot update fuel diesel price")
+		}
+		if verrs.HasAny() {
+			return errors.New("Invalid fuel diesel price")
+		}
+
+		var shipments models.Shipments
+		err := tx.Eager(models.ShipmentAssociationsDefault...).
+			Where("status = ?", models.ShipmentStatusDELIVERED).
+			Where("book_date BETWEEN ? AND ?", fuelPrice.RateStartDate, fuelPrice.RateEndDate).
+			All(&shipments)
+		if err != nil {
+			return errors.Wrap(err, "Cannot fetch shipments affected by fuel price")
+		}
+
+		engine := rateengine.NewRateEngine(tx, c.logger)
+		for i := range shipments {
+			s := &shipments[i]
+			lineItems, err := models.FetchLineItemsByShipmentID(tx, &s.ID)
+			if err != nil {
+				return err
+			}
+			if hasInvoicedLineItem(lineItems) {
+				correction.InvoicedShipmentIDs = append(correction.InvoicedShipmentIDs, s.ID)
+				continue
+			}
+
+			verrs, err = shipment.RecalculateShipment{
+				DB:      tx,
+				Logger:  c.logger,
+				Engine:  engine,
+				Planner: c.planner,
+			}.Call(s)
+			if err != nil {
+				return errors.Wrapf(err, "Cannot reprice shipment %s", s.ID)
+			}
+			if verrs.HasAny() {
+				return errors.Errorf("Cannot reprice shipment %s: %s", s.ID, verrs.String())
+			}
+			correction.RepricedShipmentIDs = append(correction.RepricedShipmentIDs, s.ID)
+		}
+		return nil
+	})
+	if transactionErr != nil {
+		return FuelPriceCorrection{}, verrs, transactionErr
+	}
+
+	correction.FuelPrice = fuelPrice
+	c.logger.Info("Corrected fuel price",
+		zap.Time("pub_date", fuelPrice.PubDate),
+		zap.Int64("previous_price_per_gallon_millicents", correction.PreviousPricePerGallon.Int64()),
+		zap.Int64("price_per_gallon_millicents", fuelPrice.EIAPricePerGallonMillicents.Int64()),
+		zap.Int("repriced_shipments", len(correction.RepricedShipmentIDs)),
+		zap.Int("invoiced_shipments", len(correction.InvoicedShipmentIDs)))
+	return correction, verrs, nil
+}
+
+func hasInvoicedLineItem(lineItems []models.ShipmentLineItem) bool {
+	for _, item := range lineItems {
+		if item.InvoiceID != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package fuelprice
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/route"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *FuelPriceServiceSuite) TestCorrectFuelPrice() {
+	statuses := []models.ShipmentStatus{models.ShipmentStatusDELIVERED, models.ShipmentStatusDELIVERED}
+	_, shipments, _, err := testdatagen.CreateShipmentOfferData(suite.DB(), 1, 2, []int{2}, statuses, models.SelectedMoveTypeHHG)
+	suite.FatalNoError(err)
+	repriced, invoiced := shipments[0], shipments[1]
+
+	// Shipments are booked inside the rate period of the price being corrected
+	bookDate := *repriced.BookDate
+	assertions := testdatagen.Assertions{}
+	assertions.FuelEIADieselPrice.PubDate = time.Date(bookDate.Year(), bookDate.Month(), 6, 0, 0, 0, 0, time.UTC)
+	assertions.FuelEIADieselPrice.RateStartDate = time.Date(bookDate.Year(), bookDate.Month(), 15, 0, 0, 0, 0, time.UTC)
+	assertions.FuelEIADieselPrice.EIAPricePerGallonMillicents = unit.Millicents(320000)
+	assertions.FuelEIADieselPrice.BaselineRate = 6
+	fuelPrice := testdatagen.MakeFuelEIADieselPriceForDate(suite.DB(), bookDate, assertions)
+
+	linehaul, err := models.FetchTariff400ngItemByCode(suite.DB(), "LHS")
+	suite.FatalNoError(err)
+	invoice := testdatagen.MakeInvoice(suite.DB(), testdatagen.Assertions{
+		Invoice: models.Invoice{Shipment: invoiced, ShipmentID: invoiced.ID},
+	})
+	testdatagen.MakeShipmentLineItem(suite.DB(), testdatagen.Assertions{
+		ShipmentLineItem: models.ShipmentLineItem{
+			Shipment:        invoiced,
+			Tariff400ngItem: linehaul,
+			InvoiceID:       &invoice.ID,
+		},
+	})
+
+	corrector := NewFuelPriceCorrector(suite.DB(), suite.logger, route.NewTestingPlanner(1044))
+	correction, verrs, err := corrector.CorrectFuelPrice(fuelPrice.PubDate, unit.Dollars(3.9))
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	suite.Equal(unit.Millicents(320000), correction.PreviousPricePerGallon)
+	suite.Equal(int64(6), correction.PreviousBaselineRate)
+	suite.Equal(unit.Millicents(390000), correction.FuelPrice.EIAPricePerGallonMillicents)
+	suite.Equal(int64(11), correction.FuelPrice.BaselineRate)
+	suite.Equal([]uuid.UUID{repriced.ID}, correction.RepricedShipmentIDs)
+	suite.Equal([]uuid.UUID{invoiced.ID}, correction.InvoicedShipmentIDs)
+
+	// The repriced shipment's fuel surcharge is based on the corrected price
+	lineItems, err := models.FetchLineItemsByShipmentID(suite.DB(), &repriced.ID)
+	suite.NoError(err)
+	var fuelSurcharge *models.ShipmentLineItem
+	for i := range lineItems {
+		if lineItems[i].Tariff400ngItem.Code == "16A" {
+			fuelSurcharge = &lineItems[i]
+		}
+	}
+	suite.FatalFalse(fuelSurcharge == nil, "missing fuel surcharge line item")
+	suite.Equal(unit.Millicents(390000), *fuelSurcharge.AppliedRate)
+
+	_, _, err = corrector.CorrectFuelPrice(fuelPrice.PubDate.AddDate(0, -3, 0), unit.Dollars(3.9))
+	suite.Equal(models.ErrFetchNotFound, err)
+}
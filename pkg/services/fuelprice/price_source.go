@@ -0,0 +1,175 @@
+package fuelprice
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// eiaDieselSeriesID is the EIA series of weekly U.S. No 2 diesel retail prices
+const eiaDieselSeriesID = "PET.EMD_EPD2D_PTE_NUS_DPG.W"
+
+// WeeklyDieselPrice is the U.S. average diesel price published for a week
+type WeeklyDieselPrice struct {
+	PubDate        time.Time
+	PricePerGallon unit.Dollars
+}
+
+// PriceSource provides the weekly diesel prices published between two dates, inclusive
+type PriceSource interface {
+	FetchWeeklyPrices(start time.Time, end time.Time) ([]WeeklyDieselPrice, error)
+}
+
+// eiaPriceSource fetches weekly diesel prices from the EIA Open Data API
+type eiaPriceSource struct {
+	fetchFuelData FetchFuelData
+	eiaKey        string
+	url           string
+}
+
+// NewEIAPriceSource returns a price source for the EIA Open Data API at url
+func NewEIAPriceSource(fetchFuelData FetchFuelData, eiaKey string, url string) PriceSource {
+	return &eiaPriceSource{
+		fetchFuelData: fetchFuelData,
+		eiaKey:        eiaKey,
+		url:           url,
+	}
+}
+
+// FetchWeeklyPrices fetches the weekly diesel prices published between start and end
+func (s *eiaPriceSource) FetchWeeklyPrices(start time.Time, end time.Time) ([]WeeklyDieselPrice, error) {
+	parsedURL, err := url.Parse(s.url)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid EIA url")
+	}
+	query := parsedURL.Query()
+	query.Set("api_key", s.eiaKey)
+	query.Set("series_id", eiaDieselSeriesID)
+	query.Set("start", start.Format("20060102"))
+	query.Set("end", end.Format("20060102"))
+	parsedURL.RawQuery = query.Encode()
+
+	result, err := s.fetchFuelData(parsedURL.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "problem fetching fuel data")
+	}
+	return weeklyPricesFromEiaData(result)
+}
+
+// weeklyPricesFromEiaData reads the weekly prices from an EIA Open Data series response
+func weeklyPricesFromEiaData(result EiaData) ([]WeeklyDieselPrice, error) {
+	// handle all possible responses
+	if len(result.OtherData) != 0 {
+		if errMsg, ok := result.OtherData["error"].(string); ok {
+			return nil, errors.New(errMsg)
+		}
+		return nil, errors.New("Unexpected response from GET request to eia.gov's open data")
+	} else if len(result.SeriesData) == 0 {
+		return nil, errors.New("GET request to eia.gov's open data was unsuccessful")
+	}
+
+	var prices []WeeklyDieselPrice
+	for _, weekData := range result.SeriesData[0].Data {
+		if len(weekData) < 2 {
+			return nil, errors.New("data returned from api is missing a pub_date or fuel price")
+		}
+		dateString, ok := weekData[0].(string)
+		if !ok {
+			return nil, errors.New("data returned from api as pub_date failed string type assertion")
+		}
+		price, ok := weekData[1].(float64)
+		if !ok {
+			return nil, errors.New("data returned as fuel price failed float64 type assertion")
+		}
+		pubDate, err := time.Parse("20060102", dateString)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to convert pubDate datestring to date")
+		}
+		prices = append(prices, WeeklyDieselPrice{PubDate: pubDate, PricePerGallon: unit.Dollars(price)})
+	}
+	return prices, nil
+}
+
+// csvPriceSource reads weekly diesel prices from a CSV file, for entering prices by hand or when the EIA API
+// can't be reached
+type csvPriceSource struct {
+	path string
+}
+
+// NewCSVPriceSource returns a price source for a CSV file with a header row naming pub_date and price_per_gallon
+// columns. Dates are like 2019-05-13 and prices are in dollars, like 3.163.
+func NewCSVPriceSource(path string) PriceSource {
+	return &csvPriceSource{path: path}
+}
+
+// FetchWeeklyPrices reads the weekly diesel prices published between start and end
+func (s *csvPriceSource) FetchWeeklyPrices(start time.Time, end time.Time) ([]WeeklyDieselPrice, error) {
+	file, err := os.Open(s.path) // #nosec
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not open fuel price file")
+	}
+	defer file.Close()
+
+	prices, err := readWeeklyPricesCSV(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read fuel price file %s", s.path)
+	}
+
+	var inRange []WeeklyDieselPrice
+	for _, price := range prices {
+		if !price.PubDate.Before(start) && !price.PubDate.After(end) {
+			inRange = append(inRange, price)
+		}
+	}
+	return inRange, nil
+}
+
+func readWeeklyPricesCSV(r io.Reader) ([]WeeklyDieselPrice, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("missing header row")
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	dateColumn, ok := columns["pub_date"]
+	if !ok {
+		return nil, errors.New("missing pub_date column")
+	}
+	priceColumn, ok := columns["price_per_gallon"]
+	if !ok {
+		return nil, errors.New("missing price_per_gallon column")
+	}
+
+	var prices []WeeklyDieselPrice
+	for i, row := range rows[1:] {
+		line := i + 2
+		if dateColumn >= len(row) || priceColumn >= len(row) {
+			return nil, fmt.Errorf("line %d: missing pub_date or price_per_gallon", line)
+		}
+		pubDate, err := time.Parse("2006-01-02", strings.TrimSpace(row[dateColumn]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: pub_date must be a date like 2019-05-13", line)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(row[priceColumn]), 64)
+		if err != nil || price <= 0 {
+			return nil, fmt.Errorf("line %d: price_per_gallon must be a positive amount in dollars", line)
+		}
+		prices = append(prices, WeeklyDieselPrice{PubDate: pubDate, PricePerGallon: unit.Dollars(price)})
+	}
+	return prices, nil
+}
@@ -0,0 +1,59 @@
+package fuelprice
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *FuelPriceServiceSuite) TestCSVPriceSource() {
+	file, err := ioutil.TempFile("", "fuel_prices*.csv")
+	suite.FatalNoError(err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("pub_date,price_per_gallon\n2019-01-07,3.048\n2019-01-14, 2.99\n2019-02-04,2.967\n")
+	suite.FatalNoError(err)
+	suite.FatalNoError(file.Close())
+
+	source := NewCSVPriceSource(file.Name())
+	prices, err := source.FetchWeeklyPrices(
+		time.Date(2019, time.January, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, time.February, 4, 0, 0, 0, 0, time.UTC))
+	suite.NoError(err)
+	suite.Equal([]WeeklyDieselPrice{
+		{PubDate: time.Date(2019, time.January, 14, 0, 0, 0, 0, time.UTC), PricePerGallon: unit.Dollars(2.99)},
+		{PubDate: time.Date(2019, time.February, 4, 0, 0, 0, 0, time.UTC), PricePerGallon: unit.Dollars(2.967)},
+	}, prices)
+
+	_, err = NewCSVPriceSource(file.Name()+".missing").FetchWeeklyPrices(time.Time{}, time.Now())
+	suite.Error(err)
+}
+
+func (suite *FuelPriceServiceSuite) TestCSVPriceSourceInvalid() {
+	for name, contents := range map[string]string{
+		"missing column": "pub_date,price\n2019-01-07,3.048\n",
+		"invalid date":   "pub_date,price_per_gallon\n01/07/2019,3.048\n",
+		"invalid price":  "pub_date,price_per_gallon\n2019-01-07,-3\n",
+	} {
+		_, err := readWeeklyPricesCSV(strings.NewReader(contents))
+		suite.Error(err, name)
+	}
+}
+
+func (suite *FuelPriceServiceSuite) TestEIAPriceSource() {
+	source := NewEIAPriceSource(mockedFetchFuelPriceData, "", "Store previous month missing data")
+	prices, err := source.FetchWeeklyPrices(time.Now(), time.Now())
+	suite.NoError(err)
+	suite.Len(prices, 4)
+
+	earliest := earliestPriceByMonth(prices)
+	suite.Equal(WeeklyDieselPrice{
+		PubDate:        time.Date(2009, time.October, 5, 0, 0, 0, 0, time.UTC),
+		PricePerGallon: unit.Dollars(2.582),
+	}, earliest[time.Date(2009, time.October, 1, 0, 0, 0, 0, time.UTC)])
+
+	_, err = NewEIAPriceSource(mockedFetchFuelPriceData, "", "Error").FetchWeeklyPrices(time.Now(), time.Now())
+	suite.Error(err)
+}
@@ -50,6 +50,9 @@ func (p ProcessInvoice) generateAndSendInvoiceData(invoice *models.Invoice, ship
 	if err != nil {
 		return nil, err
 	}
+	// Record the ICN so we can match the 997 acknowledgment GEX sends back to this invoice
+	icn := invoice858C.ISA.InterchangeControlNumber
+	invoice.InterchangeControlNumber = &icn
 
 	// send edi through gex post api
 	transactionName := "placeholder"
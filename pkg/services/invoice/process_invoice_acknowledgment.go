@@ -0,0 +1,81 @@
+package invoice
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	ediinvoice "github.com/transcom/mymove/pkg/edi/invoice"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+)
+
+// ProcessInvoiceAcknowledgment is a service object to record the result of a 997 functional acknowledgment
+// on the invoice it acknowledges.
+type ProcessInvoiceAcknowledgment struct {
+	DB     *pop.Connection
+	Logger Logger
+}
+
+// Call finds the submitted invoice matching the acknowledged group control number and moves it to
+// InvoiceStatusACCEPTED or InvoiceStatusREJECTED, storing any rejection reasons. The office user who approved a
+// rejected invoice is notified.
+func (p ProcessInvoiceAcknowledgment) Call(ack ediinvoice.FunctionalAck997) (*models.Invoice, *validate.Errors, error) {
+	// We always send the ICN as the GS group control number, so AK102 identifies the invoice
+	icn := ack.AK1.GroupControlNumber
+	invoice, err := models.FetchInvoiceByInterchangeControlNumber(p.DB, icn)
+	if err != nil {
+		return nil, validate.NewErrors(), errors.Wrapf(err, "Could not find invoice for group control number %d", icn)
+	}
+
+	if invoice.Status != models.InvoiceStatusSUBMITTED {
+		return invoice, validate.NewErrors(), errors.Errorf("Invoice %s has status %s, expected %s",
+			invoice.ID, invoice.Status, models.InvoiceStatusSUBMITTED)
+	}
+
+	if ack.Accepted() {
+		invoice.Status = models.InvoiceStatusACCEPTED
+		invoice.RejectionReasons = nil
+	} else {
+		invoice.Status = models.InvoiceStatusREJECTED
+		reasons := strings.Join(ack.RejectionReasons(), "; ")
+		if reasons == "" {
+			reasons = "Rejected without error codes"
+		}
+		invoice.RejectionReasons = &reasons
+	}
+
+	verrs := validate.NewErrors()
+	err = p.DB.Transaction(func(tx *pop.Connection) error {
+		var err error
+		verrs, err = tx.ValidateAndSave(invoice)
+		if err != nil || verrs.HasAny() {
+			return errors.New("rollback")
+		}
+		if invoice.Status == models.InvoiceStatusREJECTED {
+			// Enqueued in the same transaction, so the notification is only sent if the rejection is saved
+			err = notifications.EnqueueNotification(context.Background(), tx, notifications.NewInvoiceRejected(tx, p.Logger, invoice.ID))
+			if err != nil {
+				return errors.Wrap(err, "Could not notify the approver of the rejected invoice")
+			}
+		}
+		return nil
+	})
+	if verrs.HasAny() {
+		return invoice, verrs, nil
+	}
+	if err != nil {
+		return invoice, verrs, err
+	}
+
+	p.Logger.Info("Processed 997 for invoice",
+		zap.String("invoice_id", invoice.ID.String()),
+		zap.Int64("icn", icn),
+		zap.String("status", string(invoice.Status)),
+	)
+	return invoice, verrs, nil
+}
@@ -0,0 +1,90 @@
+package invoice
+
+import (
+	"testing"
+
+	ediinvoice "github.com/transcom/mymove/pkg/edi/invoice"
+	edisegment "github.com/transcom/mymove/pkg/edi/segment"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func helperAck997(icn int64, groupCode string, transactionSetCode string, errorCodes ...string) ediinvoice.FunctionalAck997 {
+	return ediinvoice.FunctionalAck997{
+		AK1: edisegment.AK1{FunctionalIdentifierCode: "SI", GroupControlNumber: icn},
+		TransactionSetResponses: []ediinvoice.TransactionSetResponse{
+			{
+				AK2: edisegment.AK2{TransactionSetIdentifierCode: "858", TransactionSetControlNumber: "0001"},
+				AK5: edisegment.AK5{TransactionSetAcknowledgmentCode: transactionSetCode, TransactionSetSyntaxErrorCodes: errorCodes},
+			},
+		},
+		AK9: edisegment.AK9{FunctionalGroupAcknowledgeCode: groupCode},
+	}
+}
+
+func helperSubmittedInvoice(suite *InvoiceServiceSuite, icn int64) *models.Invoice {
+	shipment := helperShipment(suite)
+	invoice := helperCreateInvoice(suite, shipment)
+	invoice.Status = models.InvoiceStatusSUBMITTED
+	invoice.InterchangeControlNumber = &icn
+	suite.MustSave(invoice)
+	return invoice
+}
+
+func (suite *InvoiceServiceSuite) TestProcessInvoiceAcknowledgmentCall() {
+	processAck := ProcessInvoiceAcknowledgment{
+		DB:     suite.DB(),
+		Logger: suite.logger,
+	}
+
+	suite.T().Run("accepted 997 accepts the invoice", func(t *testing.T) {
+		invoice := helperSubmittedInvoice(suite, 100000001)
+
+		updated, verrs, err := processAck.Call(helperAck997(100000001, "A", "A"))
+		suite.NoError(err)
+		suite.Empty(verrs.Errors)
+		suite.Equal(invoice.ID, updated.ID)
+
+		suite.DB().Reload(invoice)
+		suite.Equal(models.InvoiceStatusACCEPTED, invoice.Status)
+		suite.Nil(invoice.RejectionReasons)
+	})
+
+	suite.T().Run("rejected 997 rejects the invoice with reasons", func(t *testing.T) {
+		invoice := helperSubmittedInvoice(suite, 100000002)
+
+		_, verrs, err := processAck.Call(helperAck997(100000002, "R", "R", "4"))
+		suite.NoError(err)
+		suite.Empty(verrs.Errors)
+
+		suite.DB().Reload(invoice)
+		suite.Equal(models.InvoiceStatusREJECTED, invoice.Status)
+		if suite.NotNil(invoice.RejectionReasons) {
+			suite.Contains(*invoice.RejectionReasons, "Number of Included Segments Does Not Match Actual Count")
+		}
+
+		// The approver is notified of the rejection
+		var approver models.OfficeUser
+		suite.NoError(suite.DB().Find(&approver, invoice.ApproverID))
+		var deliveries []models.NotificationDelivery
+		err = suite.DB().Where("notification_type = ? AND recipient_email = ?", "invoice_rejected", approver.Email).All(&deliveries)
+		suite.NoError(err)
+		suite.Len(deliveries, 1)
+	})
+
+	suite.T().Run("no invoice for the control number", func(t *testing.T) {
+		_, _, err := processAck.Call(helperAck997(100000003, "A", "A"))
+		suite.Error(err)
+	})
+
+	suite.T().Run("invoice that was not submitted is left alone", func(t *testing.T) {
+		invoice := helperSubmittedInvoice(suite, 100000004)
+		invoice.Status = models.InvoiceStatusSUBMISSIONFAILURE
+		suite.MustSave(invoice)
+
+		_, _, err := processAck.Call(helperAck997(100000004, "A", "A"))
+		suite.Error(err)
+
+		suite.DB().Reload(invoice)
+		suite.Equal(models.InvoiceStatusSUBMISSIONFAILURE, invoice.Status)
+	})
+}
@@ -57,6 +57,8 @@ func (s StoreInvoice858C) Call(edi string, invoice *models.Invoice, userID uuid.
 	loader := uploader.NewUploader(s.DB, s.Logger, *s.Storer)
 	// Set Storagekey path for S3
 	loader.SetUploadStorageKey(ediTmpFile)
+	// The EDI is generated by the app, so it doesn't need to be scanned
+	loader.SetTrusted(true)
 
 	// Delete of previous upload, if it exist
 	// If Delete of Upload fails, ignoring this error because we still have a new Upload that needs to be saved
@@ -26,6 +26,10 @@ func (ft FormType) String() string {
 type FormTemplate struct {
 	Buffer       *bytes.Reader
 	FieldsLayout map[string]paperworkforms.FieldPos
+	// Fillable is true if Buffer holds a fillable PDF, rather than an image to draw the fields onto
+	Fillable bool
+	// Flatten is true if a fillable PDF's fields are drawn onto its pages once they're filled in
+	Flatten bool
 	FormType
 	FileName string
 	Data     interface{}
@@ -19,7 +19,8 @@ type FileStorer interface {
 	Create(string) (afero.File, error)
 }
 
-// FormFiller is an interface for formFiller implementation
+// FormFiller is an interface for formFiller implementation, which draws onto template images, or
+// acroFormFiller, which fills fillable PDFs
 //go:generate mockery -name FormFiller
 type FormFiller interface {
 	AppendPage(io.ReadSeeker, map[string]paperworkforms.FieldPos, interface{}) error
@@ -32,7 +33,16 @@ type createForm struct {
 	formFiller FormFiller
 }
 
-// createAssetByteReader creates a new byte reader based on the TemplateImagePath of the formLayout
+// newFormFiller returns an AcroFormFiller for fillable PDF templates, flattened if their layout says so, and a
+// FormFiller for template images. Fillers collect every page they are given, so each form gets a new one.
+func newFormFiller(template services.FormTemplate) FormFiller {
+	if template.Fillable {
+		return paperworkforms.NewAcroFormFiller(template.Flatten)
+	}
+	return paperworkforms.NewFormFiller()
+}
+
+// createAssetByteReader creates a new byte reader for a form template embedded in the assets package
 func createAssetByteReader(path string) (*bytes.Reader, error) {
 	asset, err := assets.Asset(path)
 	if err != nil {
@@ -46,22 +56,36 @@ func createAssetByteReader(path string) (*bytes.Reader, error) {
 // MakeFormTemplate creates form template with all needed parameters from handler
 func MakeFormTemplate(data interface{}, fileName string, formLayout paperworkforms.FormLayout, formType services.FormType) (services.FormTemplate, error) {
 	// Read in bytes from Asset pkg
-	templateBuffer, err := createAssetByteReader(formLayout.TemplateImagePath)
+	templateBuffer, err := createAssetByteReader(formLayout.TemplatePath())
 	if err != nil {
 		return services.FormTemplate{}, errors.Wrap(err, "Error reading template file and creating form template")
 	}
-	return services.FormTemplate{Buffer: templateBuffer, FieldsLayout: formLayout.FieldsLayout, FormType: formType, FileName: fileName, Data: data}, nil
+	return services.FormTemplate{
+		Buffer:       templateBuffer,
+		FieldsLayout: formLayout.FieldsLayout,
+		Fillable:     formLayout.TemplatePDFPath != "",
+		Flatten:      formLayout.Flatten,
+		FormType:     formType,
+		FileName:     fileName,
+		Data:         data,
+	}, nil
 }
 
-// NewFormCreator creates a new struct with service dependencies
+// NewFormCreator creates a new struct with service dependencies. If formFiller is nil, each form is filled in by
+// a new filler suited to its template.
 func NewFormCreator(fileStorer FileStorer, formFiller FormFiller) services.FormCreator {
 	return &createForm{fileStorer, formFiller}
 }
 
 // Call creates a form with the given data
 func (c createForm) CreateForm(template services.FormTemplate) (afero.File, error) {
+	formFiller := c.formFiller
+	if formFiller == nil {
+		formFiller = newFormFiller(template)
+	}
+
 	// Populate form fields with data
-	err := c.formFiller.AppendPage(template.Buffer, template.FieldsLayout, template.Data)
+	err := formFiller.AppendPage(template.Buffer, template.FieldsLayout, template.Data)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("Failure writing %s data to form.", template.FormType.String()))
 	}
@@ -73,7 +97,7 @@ func (c createForm) CreateForm(template services.FormTemplate) (afero.File, erro
 	}
 
 	// Export file from form filler
-	err = c.formFiller.Output(file)
+	err = formFiller.Output(file)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("Failure exporting %s form to file.", template.FormType.String()))
 	}
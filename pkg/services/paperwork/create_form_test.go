@@ -47,7 +47,9 @@ func (suite *PaperworkServiceSuite) TestCreateFormServiceSuccess() {
 	).Return(nil)
 
 	formCreator := NewFormCreator(FileStorer, FormFiller)
-	template, _ := MakeFormTemplate(gbl, "some-file-name", paperworkforms.Form1203Layout, services.GBL)
+	formLayout, err := paperworkforms.LoadFormLayoutAsset(paperworkforms.Form1203LayoutName)
+	suite.FatalNil(err)
+	template, _ := MakeFormTemplate(gbl, "some-file-name", formLayout, services.GBL)
 	file, err := formCreator.CreateForm(template)
 
 	suite.NotNil(file)
@@ -68,7 +70,9 @@ func (suite *PaperworkServiceSuite) TestCreateFormServiceFormFillerAppendPageFai
 	).Return(errors.New("Error for FormFiller.AppendPage()")).Times(1)
 
 	formCreator := NewFormCreator(FileStorer, FormFiller)
-	template, _ := MakeFormTemplate(gbl, "some-file-name", paperworkforms.Form1203Layout, services.GBL)
+	formLayout, err := paperworkforms.LoadFormLayoutAsset(paperworkforms.Form1203LayoutName)
+	suite.FatalNil(err)
+	template, _ := MakeFormTemplate(gbl, "some-file-name", formLayout, services.GBL)
 	file, err := formCreator.CreateForm(template)
 
 	suite.NotNil(err)
@@ -96,7 +100,9 @@ func (suite *PaperworkServiceSuite) TestCreateFormServiceFileStorerCreateFailure
 	).Return(nil, errors.New("Error for FileStorer.Create()"))
 
 	formCreator := NewFormCreator(FileStorer, FormFiller)
-	template, _ := MakeFormTemplate(gbl, "some-file-name", paperworkforms.Form1203Layout, services.GBL)
+	formLayout, err := paperworkforms.LoadFormLayoutAsset(paperworkforms.Form1203LayoutName)
+	suite.FatalNil(err)
+	template, _ := MakeFormTemplate(gbl, "some-file-name", formLayout, services.GBL)
 	file, err := formCreator.CreateForm(template)
 
 	suite.Nil(file)
@@ -131,7 +137,9 @@ func (suite *PaperworkServiceSuite) TestCreateFormServiceFormFillerOutputFailure
 	).Return(errors.New("Error for FormFiller.Output()"))
 
 	formCreator := NewFormCreator(FileStorer, FormFiller)
-	template, _ := MakeFormTemplate(gbl, "some-file-name", paperworkforms.Form1203Layout, services.GBL)
+	formLayout, err := paperworkforms.LoadFormLayoutAsset(paperworkforms.Form1203LayoutName)
+	suite.FatalNil(err)
+	template, _ := MakeFormTemplate(gbl, "some-file-name", formLayout, services.GBL)
 	file, err := formCreator.CreateForm(template)
 
 	suite.Nil(file)
@@ -149,3 +157,9 @@ func (suite *PaperworkServiceSuite) TestCreateFormServiceCreateAssetByteReaderFa
 	suite.NotNil(err)
 	suite.Equal("Error creating asset from path. Check image path.: Asset pkg/paperwork/formtemplates/someUndefinedTemplatePath.png not found", err.Error())
 }
+
+func (suite *PaperworkServiceSuite) TestNewFormFillerChoosesFillerForTemplate() {
+	suite.IsType(&paperworkforms.FormFiller{}, newFormFiller(services.FormTemplate{}))
+	suite.IsType(&paperworkforms.AcroFormFiller{}, newFormFiller(services.FormTemplate{Fillable: true}))
+	suite.IsType(&paperworkforms.AcroFormFiller{}, newFormFiller(services.FormTemplate{Fillable: true, Flatten: true}))
+}
@@ -1,5 +1,7 @@
 package services
 
+import "fmt"
+
 // QueryFilter is an interface to allow passing filter values into query interfaces
 // Ex `FetchMany` takes a list of filters
 //go:generate mockery -name QueryFilter
@@ -12,3 +14,34 @@ type QueryFilter interface {
 // NewQueryFilter is a function type definition for building a QueryFilter
 // Should allow handlers to parse query params into QueryFilters for services
 type NewQueryFilter func(column string, comparator string, value interface{}) QueryFilter
+
+// QueryOrder is an interface to allow passing sort columns into query interfaces
+// Ex `FetchManyPage` takes a list of orders, applied in turn
+//go:generate mockery -name QueryOrder
+type QueryOrder interface {
+	Column() string
+	Descending() bool
+}
+
+// NewQueryOrder is a function type definition for building a QueryOrder
+type NewQueryOrder func(column string, descending bool) QueryOrder
+
+// Pagination is an interface to allow passing a page of results to fetch into query interfaces
+//go:generate mockery -name Pagination
+type Pagination interface {
+	Page() int
+	PerPage() int
+}
+
+// NewPagination is a function type definition for building a Pagination
+// Should allow handlers to parse optional page and per_page query params, falling back to defaults
+type NewPagination func(page *int64, perPage *int64) Pagination
+
+// InvalidInputError is returned when query filters, orders or pagination can't be applied to the model being fetched
+type InvalidInputError struct {
+	InvalidFields []string
+}
+
+func (e InvalidInputError) Error() string {
+	return fmt.Sprintf("%v is not valid input", e.InvalidFields)
+}
@@ -0,0 +1,37 @@
+package query
+
+import "github.com/transcom/mymove/pkg/services"
+
+// defaults used when a page isn't requested
+const defaultPage = 1
+const defaultPerPage = 25
+
+// pagination contains the page of records to fetch
+// Fields are private and methods are exposed to satisfy query building interfaces
+type pagination struct {
+	page    int
+	perPage int
+}
+
+// Page returns the 1-based page number
+func (p pagination) Page() int {
+	return p.page
+}
+
+// PerPage returns the number of records on each page
+func (p pagination) PerPage() int {
+	return p.perPage
+}
+
+// NewPagination is a builder for paginations to be used by handlers
+// Missing page or perPage values are replaced with defaults
+func NewPagination(page *int64, perPage *int64) services.Pagination {
+	p := pagination{page: defaultPage, perPage: defaultPerPage}
+	if page != nil {
+		p.page = int(*page)
+	}
+	if perPage != nil {
+		p.perPage = int(*perPage)
+	}
+	return p
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/gobuffalo/pop"
 
@@ -12,7 +13,17 @@ import (
 
 // allowed comparators for this query builder implementation
 const equals = "="
+const notEquals = "!="
 const greaterThan = ">"
+const greaterThanOrEquals = ">="
+const lessThan = "<"
+const lessThanOrEquals = "<="
+const in = "IN"
+const iLike = "ILIKE"
+const isNull = "IS NULL"
+
+// maxPerPage is the largest page of records that can be fetched at once
+const maxPerPage = 100
 
 // Error message constants
 const fetchManyReflectionMessage = "Model should be pointer to slice of structs"
@@ -42,17 +53,29 @@ func getDBColumn(t reflect.Type, field string) (string, bool) {
 }
 
 // check that we have a valid comparator
+// SQL keyword comparators are matched case-insensitively
 func getComparator(comparator string) (string, bool) {
-	switch comparator {
-	case equals:
-		return equals, true
-	case greaterThan:
-		return greaterThan, true
+	switch strings.ToUpper(comparator) {
+	case equals, notEquals, greaterThan, greaterThanOrEquals, lessThan, lessThanOrEquals, in, iLike, isNull:
+		return strings.ToUpper(comparator), true
 	default:
 		return "", false
 	}
 }
 
+// inValues spreads the value of an IN filter into separate query arguments
+func inValues(value interface{}) ([]interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice || v.Len() == 0 {
+		return nil, false
+	}
+	values := make([]interface{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		values[i] = v.Index(i).Interface()
+	}
+	return values, true
+}
+
 func filteredQuery(query *pop.Query, filters []services.QueryFilter, t reflect.Type) (*pop.Query, error) {
 	invalidFields := make([]string, 0)
 	for _, f := range filters {
@@ -62,6 +85,7 @@ func filteredQuery(query *pop.Query, filters []services.QueryFilter, t reflect.T
 				invalidFields,
 				fmt.Sprintf("%s %s", f.Column(), f.Comparator()),
 			)
+			continue
 		}
 		comparator, ok := getComparator(f.Comparator())
 		if !ok {
@@ -73,15 +97,73 @@ func filteredQuery(query *pop.Query, filters []services.QueryFilter, t reflect.T
 		}
 		// Column lookup should always adhere to SQL injection input validations
 		// https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/SQL_Injection_Prevention_Cheat_Sheet.md#defense-option-3-whitelist-input-validation
-		columnQuery := fmt.Sprintf("%s %s ?", column, comparator)
-		query = query.Where(columnQuery, f.Value())
+		switch comparator {
+		case isNull:
+			query = query.Where(fmt.Sprintf("%s IS NULL", column))
+		case in:
+			values, ok := inValues(f.Value())
+			if !ok {
+				invalidFields = append(
+					invalidFields,
+					fmt.Sprintf("%s %s", f.Column(), f.Comparator()),
+				)
+				continue
+			}
+			query = query.Where(fmt.Sprintf("%s IN (?)", column), values...)
+		default:
+			columnQuery := fmt.Sprintf("%s %s ?", column, comparator)
+			query = query.Where(columnQuery, f.Value())
+		}
 	}
 	if len(invalidFields) != 0 {
-		return query, fmt.Errorf("%v is not valid input", invalidFields)
+		return query, services.InvalidInputError{InvalidFields: invalidFields}
 	}
 	return query, nil
 }
 
+// orderedQuery sorts by each order in turn, then by id so that pages are stable
+func orderedQuery(query *pop.Query, orders []services.QueryOrder, t reflect.Type) (*pop.Query, error) {
+	invalidFields := make([]string, 0)
+	sortedByID := false
+	for _, o := range orders {
+		column, ok := getDBColumn(t, o.Column())
+		if !ok {
+			invalidFields = append(invalidFields, fmt.Sprintf("sort %s", o.Column()))
+			continue
+		}
+		direction := "ASC"
+		if o.Descending() {
+			direction = "DESC"
+		}
+		query = query.Order(fmt.Sprintf("%s %s", column, direction))
+		sortedByID = sortedByID || column == "id"
+	}
+	if len(invalidFields) != 0 {
+		return query, services.InvalidInputError{InvalidFields: invalidFields}
+	}
+	if _, ok := getDBColumn(t, "id"); ok && !sortedByID {
+		query = query.Order("id ASC")
+	}
+	return query, nil
+}
+
+// pointer to slice of structs check, returning the struct type
+func sliceElemType(model interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(model)
+	if t.Kind() != reflect.Ptr {
+		return nil, errors.New(fetchManyReflectionMessage)
+	}
+	t = t.Elem()
+	if t.Kind() != reflect.Slice {
+		return nil, errors.New(fetchManyReflectionMessage)
+	}
+	t = t.Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, errors.New(fetchManyReflectionMessage)
+	}
+	return t, nil
+}
+
 // FetchOne fetches a single model record using pop's First method
 // Will return error if model is not pointer to struct
 func (p *Builder) FetchOne(model interface{}, filters []services.QueryFilter) error {
@@ -104,22 +186,49 @@ func (p *Builder) FetchOne(model interface{}, filters []services.QueryFilter) er
 // FetchMany fetches multiple model records using pop's All method
 // Will return error if model is not pointer to slice of structs
 func (p *Builder) FetchMany(model interface{}, filters []services.QueryFilter) error {
-	t := reflect.TypeOf(model)
-	if t.Kind() != reflect.Ptr {
-		return errors.New(fetchManyReflectionMessage)
-	}
-	t = t.Elem()
-	if t.Kind() != reflect.Slice {
-		return errors.New(fetchManyReflectionMessage)
-	}
-	t = t.Elem()
-	if t.Kind() != reflect.Struct {
-		return errors.New(fetchManyReflectionMessage)
+	t, err := sliceElemType(model)
+	if err != nil {
+		return err
 	}
 	query := p.db.Q()
-	query, err := filteredQuery(query, filters, t)
+	query, err = filteredQuery(query, filters, t)
 	if err != nil {
 		return err
 	}
 	return query.All(model)
 }
+
+// FetchManyPage fetches a sorted page of model records and returns the total number of records matching the filters
+// A nil pagination fetches every matching record
+// Will return error if model is not pointer to slice of structs
+func (p *Builder) FetchManyPage(model interface{}, filters []services.QueryFilter, orders []services.QueryOrder, pagination services.Pagination) (int, error) {
+	t, err := sliceElemType(model)
+	if err != nil {
+		return 0, err
+	}
+	query := p.db.Q()
+	query, err = filteredQuery(query, filters, t)
+	if err != nil {
+		return 0, err
+	}
+	query, err = orderedQuery(query, orders, t)
+	if err != nil {
+		return 0, err
+	}
+
+	if pagination == nil {
+		err = query.All(model)
+		return reflect.ValueOf(model).Elem().Len(), err
+	}
+
+	if pagination.Page() < 1 || pagination.PerPage() < 1 || pagination.PerPage() > maxPerPage {
+		return 0, services.InvalidInputError{
+			InvalidFields: []string{fmt.Sprintf("page %d per_page %d", pagination.Page(), pagination.PerPage())},
+		}
+	}
+	query = query.Paginate(pagination.Page(), pagination.PerPage())
+	if err = query.All(model); err != nil {
+		return 0, err
+	}
+	return query.Paginator.TotalEntriesSize, nil
+}
@@ -208,3 +208,173 @@ func (suite *QueryBuilderSuite) TestFetchMany() {
 		suite.Equal("Model should be pointer to slice of structs", err.Error())
 	})
 }
+
+func (suite *QueryBuilderSuite) TestFetchManyComparators() {
+	user := testdatagen.MakeOfficeUser(suite.DB(), testdatagen.Assertions{
+		OfficeUser: models.OfficeUser{LastName: "Spaceman", Email: "leo@example.com"},
+	})
+	user2 := testdatagen.MakeOfficeUser(suite.DB(), testdatagen.Assertions{
+		OfficeUser: models.OfficeUser{LastName: "Jones", Email: "jones@example.com"},
+	})
+	builder := NewQueryBuilder(suite.DB())
+
+	suite.T().Run("fetches many with not equals filter", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		err := builder.FetchMany(&actualUsers, []services.QueryFilter{
+			NewQueryFilter("id", notEquals, user.ID.String()),
+		})
+
+		suite.NoError(err)
+		suite.Len(actualUsers, 1)
+		suite.Equal(user2.ID, actualUsers[0].ID)
+	})
+
+	suite.T().Run("fetches many with range filters", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		err := builder.FetchMany(&actualUsers, []services.QueryFilter{
+			NewQueryFilter("created_at", lessThanOrEquals, user2.CreatedAt),
+			NewQueryFilter("created_at", greaterThanOrEquals, user.CreatedAt),
+		})
+		suite.NoError(err)
+		suite.Len(actualUsers, 2)
+
+		err = builder.FetchMany(&actualUsers, []services.QueryFilter{
+			NewQueryFilter("created_at", lessThan, user2.CreatedAt),
+		})
+		suite.NoError(err)
+		suite.Len(actualUsers, 1)
+		suite.Equal(user.ID, actualUsers[0].ID)
+	})
+
+	suite.T().Run("fetches many with in filter", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		err := builder.FetchMany(&actualUsers, []services.QueryFilter{
+			NewQueryFilter("last_name", "in", []string{"Jones", "Smith"}),
+		})
+
+		suite.NoError(err)
+		suite.Len(actualUsers, 1)
+		suite.Equal(user2.ID, actualUsers[0].ID)
+	})
+
+	suite.T().Run("fetches many with ilike filter", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		err := builder.FetchMany(&actualUsers, []services.QueryFilter{
+			NewQueryFilter("last_name", iLike, "space%"),
+		})
+
+		suite.NoError(err)
+		suite.Len(actualUsers, 1)
+		suite.Equal(user.ID, actualUsers[0].ID)
+	})
+
+	suite.T().Run("fetches many with is null filter", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		err := builder.FetchMany(&actualUsers, []services.QueryFilter{
+			NewQueryFilter("middle_initials", isNull, nil),
+		})
+
+		suite.NoError(err)
+		suite.Len(actualUsers, 2)
+	})
+
+	suite.T().Run("fails with in filter that isn't a slice", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		err := builder.FetchMany(&actualUsers, []services.QueryFilter{
+			NewQueryFilter("last_name", in, "Jones"),
+		})
+
+		suite.Error(err)
+		suite.Equal("[last_name IN] is not valid input", err.Error())
+		suite.IsType(services.InvalidInputError{}, err)
+	})
+}
+
+func (suite *QueryBuilderSuite) TestFetchManyPage() {
+	for _, lastName := range []string{"Charlie", "Alpha", "Echo", "Bravo", "Delta"} {
+		testdatagen.MakeOfficeUser(suite.DB(), testdatagen.Assertions{
+			OfficeUser: models.OfficeUser{LastName: lastName},
+		})
+	}
+	builder := NewQueryBuilder(suite.DB())
+	perPage := int64(2)
+
+	suite.T().Run("fetches a sorted page with the total count", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		page := int64(2)
+		count, err := builder.FetchManyPage(&actualUsers, nil,
+			[]services.QueryOrder{NewQueryOrder("last_name", false)},
+			NewPagination(&page, &perPage))
+
+		suite.NoError(err)
+		suite.Equal(5, count)
+		if suite.Len(actualUsers, 2) {
+			suite.Equal("Charlie", actualUsers[0].LastName)
+			suite.Equal("Delta", actualUsers[1].LastName)
+		}
+	})
+
+	suite.T().Run("counts only filtered records", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		count, err := builder.FetchManyPage(&actualUsers,
+			[]services.QueryFilter{NewQueryFilter("last_name", in, []string{"Alpha", "Bravo", "Echo"})},
+			[]services.QueryOrder{NewQueryOrder("last_name", true)},
+			NewPagination(nil, &perPage))
+
+		suite.NoError(err)
+		suite.Equal(3, count)
+		if suite.Len(actualUsers, 2) {
+			suite.Equal("Echo", actualUsers[0].LastName)
+			suite.Equal("Bravo", actualUsers[1].LastName)
+		}
+	})
+
+	suite.T().Run("fetches everything without pagination", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		count, err := builder.FetchManyPage(&actualUsers, nil, nil, nil)
+
+		suite.NoError(err)
+		suite.Equal(5, count)
+		suite.Len(actualUsers, 5)
+	})
+
+	suite.T().Run("fails with invalid sort column", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		_, err := builder.FetchManyPage(&actualUsers, nil,
+			[]services.QueryOrder{NewQueryOrder("fake_column", false)}, nil)
+
+		suite.Error(err)
+		suite.Equal("[sort fake_column] is not valid input", err.Error())
+		suite.Empty(actualUsers)
+	})
+
+	suite.T().Run("fails with invalid pagination", func(t *testing.T) {
+		var actualUsers models.OfficeUsers
+		page := int64(0)
+		_, err := builder.FetchManyPage(&actualUsers, nil, nil, NewPagination(&page, nil))
+
+		suite.Error(err)
+		suite.Equal("[page 0 per_page 25] is not valid input", err.Error())
+
+		tooMany := int64(maxPerPage + 1)
+		_, err = builder.FetchManyPage(&actualUsers, nil, nil, NewPagination(nil, &tooMany))
+		suite.Error(err)
+	})
+}
+
+func (suite *QueryBuilderSuite) TestGetComparator() {
+	for _, comparator := range []string{"=", "!=", "<", "<=", ">", ">=", "IN", "ILIKE", "IS NULL"} {
+		actual, ok := getComparator(comparator)
+		suite.True(ok, comparator)
+		suite.Equal(comparator, actual)
+	}
+
+	actual, ok := getComparator("ilike")
+	suite.True(ok)
+	suite.Equal(iLike, actual)
+
+	for _, comparator := range []string{"*", "LIKE", "; DROP TABLE office_users"} {
+		_, ok := getComparator(comparator)
+		suite.False(ok, comparator)
+	}
+}
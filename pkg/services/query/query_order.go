@@ -0,0 +1,29 @@
+package query
+
+import "github.com/transcom/mymove/pkg/services"
+
+// queryOrder contains the fields necessary to build a query order clause
+// Fields are private and methods are exposed to satisfy query building interfaces
+type queryOrder struct {
+	column     string
+	descending bool
+}
+
+// Column returns the order's column as a string
+func (o queryOrder) Column() string {
+	return o.column
+}
+
+// Descending returns true if the column is sorted from highest to lowest
+func (o queryOrder) Descending() bool {
+	return o.descending
+}
+
+// NewQueryOrder is a builder for query orders to be used by handlers
+// and talk to services that sort their results
+func NewQueryOrder(column string, descending bool) services.QueryOrder {
+	return queryOrder{
+		column,
+		descending,
+	}
+}
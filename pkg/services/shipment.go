@@ -4,8 +4,11 @@ import (
 	"time"
 
 	"github.com/gobuffalo/validate"
+	"github.com/gofrs/uuid"
 
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/unit"
 )
 
 // PricingType describe the type of pricing to do for a shipment
@@ -18,3 +21,47 @@ type ShipmentDeliverAndPricer interface {
 type ShipmentPricer interface {
 	PriceShipment(shipment *models.Shipment, price PricingType) (*validate.Errors, error)
 }
+
+// LineItemPriceComparison compares the stored price of a shipment line item with its what-if price. Either price is
+// nil if the line item only exists on one side, e.g. a base line item missing from the stored line items.
+type LineItemPriceComparison struct {
+	ShipmentLineItemID *uuid.UUID
+	Code               string
+	Item               string
+	Location           models.ShipmentLineItemLocation
+	StoredAmountCents  *unit.Cents
+	WhatIfAmountCents  *unit.Cents
+	WhatIfExplanation  *models.PriceExplanation
+}
+
+// DifferenceCents returns the what-if price less the stored price, treating a missing price as zero
+func (c LineItemPriceComparison) DifferenceCents() unit.Cents {
+	var stored, whatIf unit.Cents
+	if c.StoredAmountCents != nil {
+		stored = *c.StoredAmountCents
+	}
+	if c.WhatIfAmountCents != nil {
+		whatIf = *c.WhatIfAmountCents
+	}
+	return whatIf - stored
+}
+
+// ShipmentWhatIf is a shipment priced against overridden inputs, side by side with its stored line items
+type ShipmentWhatIf struct {
+	ShipmentID       uuid.UUID
+	Overrides        rateengine.PricingOverrides
+	LineItems        []LineItemPriceComparison
+	StoredTotalCents unit.Cents
+	WhatIfTotalCents unit.Cents
+}
+
+// DifferenceCents returns the what-if total less the stored total
+func (w ShipmentWhatIf) DifferenceCents() unit.Cents {
+	return w.WhatIfTotalCents - w.StoredTotalCents
+}
+
+// ShipmentWhatIfPricer prices a shipment against overridden inputs without saving anything
+//go:generate mockery -name ShipmentWhatIfPricer
+type ShipmentWhatIfPricer interface {
+	PriceShipmentWhatIf(shipmentID uuid.UUID, overrides rateengine.PricingOverrides) (ShipmentWhatIf, error)
+}
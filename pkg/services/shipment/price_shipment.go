@@ -85,7 +85,8 @@ func (c *shipmentPricer) PriceShipment(shipment *models.Shipment, price services
 		return validate.NewErrors(), err
 	}
 
-	// Create Storage in Transit (SIT) line items for Shipment
+	// Storage in Transit (SIT) line items are added as each SIT is placed into SIT and released. Gather them for
+	// pricing, adding those for destination SITs delivered with the shipment and any deleted for repricing.
 	createStorageInTransitLineItems := storageintransit.CreateStorageInTransitLineItems{
 		DB:      c.db,
 		Planner: c.planner,
@@ -0,0 +1,124 @@
+package shipment
+
+import (
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/services"
+)
+
+// shipmentWhatIfPricer is a service object to price a Shipment against overridden inputs
+type shipmentWhatIfPricer struct {
+	db     *pop.Connection
+	engine *rateengine.RateEngine
+}
+
+/*
+	What-if pricing answers "what would this shipment cost under a different tariff year, discount or fuel price?",
+	e.g. when a TSP disputes an invoice. Nothing is saved: the base line items are priced again from the shipment's
+	stored distance, and its approved pre-approval and SIT line items are priced again from their stored quantities.
+
+	Unlike RecalculateShipment, line items that are already invoiced are priced too, as disputes are usually about
+	invoiced shipments.
+*/
+
+// PriceShipmentWhatIf prices a shipment using the given overrides, and compares it with the stored line items
+func (p *shipmentWhatIfPricer) PriceShipmentWhatIf(shipmentID uuid.UUID, overrides rateengine.PricingOverrides) (services.ShipmentWhatIf, error) {
+	whatIf := services.ShipmentWhatIf{ShipmentID: shipmentID, Overrides: overrides}
+
+	var shipment models.Shipment
+	err := p.db.Eager(models.ShipmentAssociationsDefault...).Find(&shipment, shipmentID)
+	if err != nil {
+		if errors.Cause(err).Error() == "sql: no rows in result set" {
+			return whatIf, models.ErrFetchNotFound
+		}
+		return whatIf, err
+	}
+
+	// Shipments are priced when delivered, which is when their distance is stored
+	if shipment.ShippingDistanceID == nil {
+		return whatIf, errors.Wrap(services.InvalidInputError{InvalidFields: []string{"shipment_id"}}, "shipment has not been priced")
+	}
+
+	storedItems, err := models.FetchLineItemsByShipmentID(p.db, &shipment.ID)
+	if err != nil {
+		return whatIf, err
+	}
+
+	engine := p.engine.WithOverrides(overrides)
+	shipmentCost, err := engine.HandleRunOnShipment(shipment, shipment.ShippingDistance)
+	if err != nil {
+		return whatIf, errors.Wrap(err, "Error pricing shipment for what-if")
+	}
+	baseItems, err := rateengine.CreateBaseShipmentLineItems(p.db, shipmentCost)
+	if err != nil {
+		return whatIf, errors.Wrap(err, "Error creating base line items for what-if")
+	}
+
+	// Base line items are compared by code, as each shipment has one of each
+	storedBaseItems := map[string]models.ShipmentLineItem{}
+	for _, item := range storedItems {
+		if models.FindBaseShipmentLineItem(item.Tariff400ngItem.Code) {
+			storedBaseItems[item.Tariff400ngItem.Code] = item
+		}
+	}
+	for i := range baseItems {
+		comparison := newLineItemPriceComparison(baseItems[i])
+		if stored, ok := storedBaseItems[baseItems[i].Tariff400ngItem.Code]; ok {
+			comparison.ShipmentLineItemID = &stored.ID
+			comparison.StoredAmountCents = stored.AmountCents
+		}
+		whatIf.LineItems = append(whatIf.LineItems, comparison)
+	}
+
+	// Other line items are only charged once approved
+	for i := range storedItems {
+		stored := storedItems[i]
+		if models.FindBaseShipmentLineItem(stored.Tariff400ngItem.Code) || stored.Status != models.ShipmentLineItemStatusAPPROVED {
+			continue
+		}
+		item := stored
+		item.Shipment = shipment
+		feeAndRate, err := engine.ComputeShipmentLineItemCharge(item)
+		if err != nil {
+			return whatIf, errors.Wrapf(err, "Error pricing line item %s for what-if", stored.ID)
+		}
+		item.AmountCents = &feeAndRate.Fee
+		item.PriceExplanation = &feeAndRate.Explanation
+
+		comparison := newLineItemPriceComparison(item)
+		comparison.ShipmentLineItemID = &stored.ID
+		comparison.StoredAmountCents = stored.AmountCents
+		whatIf.LineItems = append(whatIf.LineItems, comparison)
+	}
+
+	for _, comparison := range whatIf.LineItems {
+		if comparison.StoredAmountCents != nil {
+			whatIf.StoredTotalCents += *comparison.StoredAmountCents
+		}
+		if comparison.WhatIfAmountCents != nil {
+			whatIf.WhatIfTotalCents += *comparison.WhatIfAmountCents
+		}
+	}
+
+	return whatIf, nil
+}
+
+// newLineItemPriceComparison starts a comparison from a line item priced for a what-if
+func newLineItemPriceComparison(item models.ShipmentLineItem) services.LineItemPriceComparison {
+	return services.LineItemPriceComparison{
+		Code:              item.Tariff400ngItem.Code,
+		Item:              item.Tariff400ngItem.Item,
+		Location:          item.Location,
+		WhatIfAmountCents: item.AmountCents,
+		WhatIfExplanation: item.PriceExplanation,
+	}
+}
+
+// NewShipmentWhatIfPricer returns a new shipment what-if pricer
+func NewShipmentWhatIfPricer(db *pop.Connection, engine *rateengine.RateEngine) services.ShipmentWhatIfPricer {
+	return &shipmentWhatIfPricer{db: db, engine: engine}
+}
@@ -0,0 +1,85 @@
+package shipment
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/services"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *ShipmentServiceSuite) TestPriceShipmentWhatIfWithoutOverrides() {
+	shipment := suite.helperDeliverAndPriceShipment()
+	storedItems, err := models.FetchLineItemsByShipmentID(suite.DB(), &shipment.ID)
+	suite.FatalNoError(err)
+
+	engine := rateengine.NewRateEngine(suite.DB(), suite.logger)
+	whatIf, err := NewShipmentWhatIfPricer(suite.DB(), engine).PriceShipmentWhatIf(shipment.ID, rateengine.PricingOverrides{})
+	suite.FatalNoError(err)
+
+	// Every stored item is compared, and priced the same as when it was stored
+	suite.Len(whatIf.LineItems, len(storedItems))
+	for _, comparison := range whatIf.LineItems {
+		suite.NotNil(comparison.ShipmentLineItemID, comparison.Code)
+		suite.NotNil(comparison.WhatIfExplanation, comparison.Code)
+		suite.Equal(unit.Cents(0), comparison.DifferenceCents(), comparison.Code)
+	}
+	suite.NotEqual(unit.Cents(0), whatIf.StoredTotalCents)
+	suite.Equal(whatIf.StoredTotalCents, whatIf.WhatIfTotalCents)
+}
+
+func (suite *ShipmentServiceSuite) TestPriceShipmentWhatIfWithOverrides() {
+	shipment := suite.helperDeliverAndPriceShipment()
+	storedItems, err := models.FetchLineItemsByShipmentID(suite.DB(), &shipment.ID)
+	suite.FatalNoError(err)
+
+	discount := unit.NewDiscountRateFromPercent(0)
+	fuelPrice := unit.Millicents(450000)
+	overrides := rateengine.PricingOverrides{
+		LinehaulDiscount:   &discount,
+		FuelPricePerGallon: &fuelPrice,
+	}
+	engine := rateengine.NewRateEngine(suite.DB(), suite.logger)
+	whatIf, err := NewShipmentWhatIfPricer(suite.DB(), engine).PriceShipmentWhatIf(shipment.ID, overrides)
+	suite.FatalNoError(err)
+	suite.Equal(overrides, whatIf.Overrides)
+
+	// Without a discount linehaul can't cost less, and with pricier fuel the fuel surcharge costs more
+	for _, comparison := range whatIf.LineItems {
+		switch comparison.Code {
+		case "LHS":
+			suite.True(comparison.DifferenceCents() >= 0, comparison.Code)
+		case "16A":
+			suite.True(comparison.DifferenceCents() > 0, comparison.Code)
+		}
+	}
+	suite.True(whatIf.DifferenceCents() > 0)
+
+	// Nothing is saved
+	fetchedItems, err := models.FetchLineItemsByShipmentID(suite.DB(), &shipment.ID)
+	suite.FatalNoError(err)
+	suite.Len(fetchedItems, len(storedItems))
+	storedAmounts := map[uuid.UUID]*unit.Cents{}
+	for _, item := range storedItems {
+		storedAmounts[item.ID] = item.AmountCents
+	}
+	for _, item := range fetchedItems {
+		suite.Equal(storedAmounts[item.ID], item.AmountCents, item.Tariff400ngItem.Code)
+	}
+}
+
+func (suite *ShipmentServiceSuite) TestPriceShipmentWhatIfErrors() {
+	engine := rateengine.NewRateEngine(suite.DB(), suite.logger)
+	pricer := NewShipmentWhatIfPricer(suite.DB(), engine)
+
+	_, err := pricer.PriceShipmentWhatIf(uuid.Must(uuid.NewV4()), rateengine.PricingOverrides{})
+	suite.Equal(models.ErrFetchNotFound, err)
+
+	// A shipment that hasn't been delivered has no distance to price with
+	shipment := testdatagen.MakeDefaultShipment(suite.DB())
+	_, err = pricer.PriceShipmentWhatIf(shipment.ID, rateengine.PricingOverrides{})
+	suite.IsType(services.InvalidInputError{}, errors.Cause(err))
+}
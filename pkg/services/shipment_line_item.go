@@ -11,6 +11,7 @@ import (
 // ShipmentLineItemFetcher is the service object for fetching shipment line items
 type ShipmentLineItemFetcher interface {
 	GetShipmentLineItemsByShipmentID(shipmentID uuid.UUID, session *auth.Session) ([]models.ShipmentLineItem, error)
+	GetShipmentLineItemByID(shipmentLineItemID uuid.UUID, session *auth.Session) (models.ShipmentLineItem, error)
 }
 
 // ShipmentLineItemRecalculator is the service object for recalculating shipment line items
@@ -34,6 +34,28 @@ func (i *getShipmentLineItems) GetShipmentLineItemsByShipmentID(shipmentID uuid.
 	return shipmentLineItems, nil
 }
 
+// GetShipmentLineItemByID returns a shipment line item, if the user can access its shipment
+func (i *getShipmentLineItems) GetShipmentLineItemByID(shipmentLineItemID uuid.UUID, session *auth.Session) (models.ShipmentLineItem, error) {
+	if !session.IsTspUser() && !session.IsOfficeUser() {
+		return models.ShipmentLineItem{}, models.ErrFetchForbidden
+	}
+
+	shipmentLineItem, err := models.FetchShipmentLineItemByID(i.db, &shipmentLineItemID)
+	if err != nil {
+		return models.ShipmentLineItem{}, err
+	}
+
+	if session.IsTspUser() {
+		// Check that the TSP user can access the shipment
+		_, _, err = models.FetchShipmentForVerifiedTSPUser(i.db, session.TspUserID, shipmentLineItem.ShipmentID)
+		if err != nil {
+			return models.ShipmentLineItem{}, err
+		}
+	}
+
+	return shipmentLineItem, nil
+}
+
 // NewShipmentLineItemFetcher is the public constructor for a `ShipmentLineItemFetcher`
 // using Pop
 func NewShipmentLineItemFetcher(db *pop.Connection) services.ShipmentLineItemFetcher {
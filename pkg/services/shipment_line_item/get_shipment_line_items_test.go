@@ -1,6 +1,8 @@
 package shipmentlineitem
 
 import (
+	"github.com/gofrs/uuid"
+
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/testdatagen"
@@ -70,3 +72,46 @@ func (suite *ShipmentLineItemServiceSuite) TestGetShipmentLineItems() {
 	suite.Equal(models.ErrFetchForbidden, err)
 
 }
+
+func (suite *ShipmentLineItemServiceSuite) TestGetShipmentLineItemByID() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.DB())
+	officeSession := auth.Session{
+		ApplicationName: auth.OfficeApp,
+		UserID:          *officeUser.UserID,
+		IDToken:         "fake token",
+		OfficeUserID:    officeUser.ID,
+	}
+	shipmentLineItem := testdatagen.MakeCompleteShipmentLineItem(suite.DB(), testdatagen.Assertions{})
+
+	// Happy path. This should succeed.
+	fetcher := NewShipmentLineItemFetcher(suite.DB())
+	retrievedShipmentLineItem, err := fetcher.GetShipmentLineItemByID(shipmentLineItem.ID, &officeSession)
+	suite.NoError(err)
+	suite.Equal(shipmentLineItem.ID, retrievedShipmentLineItem.ID)
+
+	// When the shipment line item doesn't exist
+	_, err = fetcher.GetShipmentLineItemByID(uuid.Must(uuid.NewV4()), &officeSession)
+	suite.Equal(models.ErrFetchNotFound, err)
+
+	// When we don't have permission
+	serviceMemberUser := testdatagen.MakeDefaultServiceMember(suite.DB())
+	serviceMemberSession := auth.Session{
+		ApplicationName: auth.MilApp,
+		UserID:          serviceMemberUser.UserID,
+		IDToken:         "fake token",
+		ServiceMemberID: serviceMemberUser.ID,
+	}
+	_, err = fetcher.GetShipmentLineItemByID(shipmentLineItem.ID, &serviceMemberSession)
+	suite.Equal(models.ErrFetchForbidden, err)
+
+	// TSP doesn't own the shipment
+	tspUser := testdatagen.MakeDefaultTspUser(suite.DB())
+	tspSession := auth.Session{
+		ApplicationName: auth.TspApp,
+		UserID:          *tspUser.UserID,
+		IDToken:         "fake token",
+		TspUserID:       tspUser.ID,
+	}
+	_, err = fetcher.GetShipmentLineItemByID(shipmentLineItem.ID, &tspSession)
+	suite.Equal(models.ErrFetchForbidden, err)
+}
@@ -26,19 +26,19 @@ type StorageInTransitsIndexer interface {
 // StorageInTransitApprover is the service object for approving a Storage In Transit
 //go:generate mockery -name StorageInTransitApprover
 type StorageInTransitApprover interface {
-	ApproveStorageInTransit(payload apimessages.StorageInTransitApprovalPayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error)
+	ApproveStorageInTransit(payload apimessages.StorageInTransitApprovalPayload, shipmentID uuid.UUID, session *auth.Session, requestID string, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error)
 }
 
 // StorageInTransitDenier is the service object for denying a Storage In Transit
 //go:generate mockery -name StorageInTransitDenier
 type StorageInTransitDenier interface {
-	DenyStorageInTransit(payload apimessages.StorageInTransitDenialPayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error)
+	DenyStorageInTransit(payload apimessages.StorageInTransitDenialPayload, shipmentID uuid.UUID, session *auth.Session, requestID string, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error)
 }
 
 // StorageInTransitInSITPlacer is the object for placing a Storage In Transit into SIT status
 //go:generate mockery -name StorageInTransitInSITPlacer
 type StorageInTransitInSITPlacer interface {
-	PlaceIntoSITStorageInTransit(payload apimessages.StorageInTransitInSitPayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error)
+	PlaceIntoSITStorageInTransit(payload apimessages.StorageInTransitInSitPayload, shipmentID uuid.UUID, session *auth.Session, requestID string, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error)
 }
 
 // StorageInTransitDeliverer is the service object for delivering a Storage In Transit
@@ -50,7 +50,13 @@ type StorageInTransitDeliverer interface {
 // StorageInTransitReleaser is the service object for releasing a Storage In Transit
 //go:generate mockery -name StorageInTransitReleaser
 type StorageInTransitReleaser interface {
-	ReleaseStorageInTransit(payload apimessages.StorageInTransitReleasePayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error)
+	ReleaseStorageInTransit(payload apimessages.StorageInTransitReleasePayload, shipmentID uuid.UUID, session *auth.Session, requestID string, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error)
+}
+
+// StorageInTransitDeliveryAttempter is the service object for recording a failed attempt to pick up or deliver a Storage In Transit
+//go:generate mockery -name StorageInTransitDeliveryAttempter
+type StorageInTransitDeliveryAttempter interface {
+	AttemptDeliveryStorageInTransit(payload apimessages.StorageInTransitDeliveryAttemptPayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error)
 }
 
 // StorageInTransitDeleter is the service object for deleting a Storage In Transit
@@ -59,10 +65,11 @@ type StorageInTransitDeleter interface {
 	DeleteStorageInTransit(shipmentID uuid.UUID, storageInTransitID uuid.UUID, session *auth.Session) (*models.StorageInTransit, error)
 }
 
-// StorageInTransitPatcher is the service object for editing a Storage In Transit
+// StorageInTransitPatcher is the service object for editing a Storage In Transit. If expectedVersions is set and the
+// Storage In Transit has changed since any of those versions, it returns the current Storage In Transit and models.ErrStaleVersion.
 //go:generate mockery -name StorageInTransitPatcher
 type StorageInTransitPatcher interface {
-	PatchStorageInTransit(payload apimessages.StorageInTransit, shipmentID uuid.UUID, storageInTransitID uuid.UUID, session *auth.Session) (*models.StorageInTransit, *validate.Errors, error)
+	PatchStorageInTransit(payload apimessages.StorageInTransit, shipmentID uuid.UUID, storageInTransitID uuid.UUID, expectedVersions []int, session *auth.Session) (*models.StorageInTransit, *validate.Errors, error)
 }
 
 // StorageInTransitByIDFetcher is the service object for fetching a Storage In Transit
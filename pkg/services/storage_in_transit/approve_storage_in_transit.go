@@ -1,24 +1,29 @@
 package storageintransit
 
 import (
+	"context"
 	"time"
 
 	"github.com/gobuffalo/pop"
 	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/gen/apimessages"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
 	"github.com/transcom/mymove/pkg/services"
+	"github.com/transcom/mymove/pkg/webhooks"
 )
 
 type approveStorageInTransit struct {
-	db *pop.Connection
+	db     *pop.Connection
+	logger Logger
 }
 
 // ApproveStorageInTransit sets the status of a Storage In Transit to approved, saves its Authorization Notes, saves its ActualDate, and returns the updated object.
-func (a *approveStorageInTransit) ApproveStorageInTransit(payload apimessages.StorageInTransitApprovalPayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error) {
+func (a *approveStorageInTransit) ApproveStorageInTransit(payload apimessages.StorageInTransitApprovalPayload, shipmentID uuid.UUID, session *auth.Session, requestID string, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error) {
 	returnVerrs := validate.NewErrors()
 
 	// Only office users are authorized to do this.
@@ -37,16 +42,34 @@ func (a *approveStorageInTransit) ApproveStorageInTransit(payload apimessages.St
 		return nil, returnVerrs, models.ErrFetchForbidden
 	}
 
-	if storageInTransit.Status == models.StorageInTransitStatusDELIVERED {
-		return nil, returnVerrs, models.ErrWriteConflict
+	storageInTransit.SetTransitionActor(models.NewTransitionActor(session, requestID))
+	err = storageInTransit.Approve(time.Time(payload.AuthorizedStartDate), payload.AuthorizationNotes)
+	if err != nil {
+		return nil, returnVerrs, err
 	}
 
-	storageInTransit.Status = models.StorageInTransitStatusAPPROVED
-	storageInTransit.AuthorizationNotes = payload.AuthorizationNotes
-	storageInTransit.AuthorizedStartDate = (*time.Time)(&payload.AuthorizedStartDate)
+	// Queue the email to the service member and the webhook with the approval, so they are only sent if it saves
+	err = a.db.Transaction(func(tx *pop.Connection) error {
+		verrs, err := models.SaveWithStateTransitions(tx, storageInTransit)
+		if verrs.HasAny() || err != nil {
+			returnVerrs.Append(verrs)
+			if err == nil {
+				err = errors.New("Rollback The transaction")
+			}
+			return err
+		}
+
+		notification := notifications.NewStorageInTransitApproved(tx, a.logger, storageInTransit.ID)
+		if err := notifications.EnqueueNotification(context.Background(), tx, notification); err != nil {
+			return err
+		}
 
-	if verrs, err := a.db.ValidateAndSave(storageInTransit); verrs.HasAny() || err != nil {
-		returnVerrs.Append(verrs)
+		return webhooks.Publish(tx, webhooks.NewStorageInTransitEvent(models.WebhookEventTypeSTORAGEINTRANSITAPPROVED, *storageInTransit))
+	})
+	if returnVerrs.HasAny() {
+		return nil, returnVerrs, nil
+	}
+	if err != nil {
 		return nil, returnVerrs, err
 	}
 
@@ -55,6 +78,6 @@ func (a *approveStorageInTransit) ApproveStorageInTransit(payload apimessages.St
 
 // NewStorageInTransitApprover is the public constructor for a `StorageInTransitApprover`
 // using Pop
-func NewStorageInTransitApprover(db *pop.Connection) services.StorageInTransitApprover {
-	return &approveStorageInTransit{db}
+func NewStorageInTransitApprover(db *pop.Connection, logger Logger) services.StorageInTransitApprover {
+	return &approveStorageInTransit{db, logger}
 }
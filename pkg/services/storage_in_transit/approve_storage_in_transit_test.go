@@ -7,6 +7,7 @@ import (
 	"github.com/transcom/mymove/pkg/gen/apimessages"
 	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
 	"github.com/transcom/mymove/pkg/testdatagen"
 )
 
@@ -24,17 +25,17 @@ func (suite *StorageInTransitServiceSuite) TestApproveStorageInTransit() {
 		AuthorizationNotes:  handlers.FmtStringPtr(swag.String("looks good to me")),
 	}
 
-	approver := NewStorageInTransitApprover(suite.DB())
+	approver := NewStorageInTransitApprover(suite.DB(), suite.logger)
 
 	// Should not work for a TSP user
-	_, _, err := approver.ApproveStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	_, _, err := approver.ApproveStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.Error(err, "FETCH_FORBIDDEN")
 
 	// Should not work if the status is already delivered
 	sit.Status = models.StorageInTransitStatusDELIVERED
 	_, _ = suite.DB().ValidateAndSave(&sit)
 
-	_, _, err = approver.ApproveStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	_, _, err = approver.ApproveStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.Error(err, "WRITE_CONFLICT")
 
 	// Happy path
@@ -48,10 +49,27 @@ func (suite *StorageInTransitServiceSuite) TestApproveStorageInTransit() {
 		OfficeUserID:    user.ID,
 	}
 
-	actualStorageInTransit, verrs, err := approver.ApproveStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	actualStorageInTransit, verrs, err := approver.ApproveStorageInTransit(payload, shipment.ID, &session, "request-123", sit.ID)
 	suite.NoError(err)
 	suite.False(verrs.HasAny())
 	suite.Equal(models.StorageInTransitStatusAPPROVED, actualStorageInTransit.Status)
 	suite.Equal(*payload.AuthorizationNotes, *actualStorageInTransit.AuthorizationNotes)
 
+	// The service member's email is queued with the approval
+	var deliveries models.NotificationDeliveries
+	err = suite.DB().Where("notification_type = ?", notifications.StorageInTransitApprovedNotification).All(&deliveries)
+	suite.NoError(err)
+	suite.Len(deliveries, 1)
+
+	// The approval is recorded against the request that made it
+	transitions, err := models.FetchStateTransitionsForShipment(suite.DB(), shipment.ID)
+	suite.NoError(err)
+	if suite.NotEmpty(transitions) {
+		latest := transitions[len(transitions)-1]
+		suite.Equal(string(models.StorageInTransitStatusAPPROVED), latest.NewStatus)
+		if suite.NotNil(latest.RequestID) {
+			suite.Equal("request-123", *latest.RequestID)
+		}
+	}
+
 }
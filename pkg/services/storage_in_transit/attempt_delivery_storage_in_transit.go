@@ -0,0 +1,68 @@
+package storageintransit
+
+import (
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/route"
+	"github.com/transcom/mymove/pkg/services"
+)
+
+type attemptDeliveryStorageInTransit struct {
+	db      *pop.Connection
+	planner route.Planner
+}
+
+// AttemptDeliveryStorageInTransit adds the line items for a failed attempt to pick up an ORIGIN Storage In Transit, or to
+// deliver a DESTINATION Storage In Transit, and returns the unchanged object.
+func (a *attemptDeliveryStorageInTransit) AttemptDeliveryStorageInTransit(payload apimessages.StorageInTransitDeliveryAttemptPayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error) {
+	returnVerrs := validate.NewErrors()
+
+	// Only TSPs are authorized to do this and they should only be able to on their own shipments
+	isAuthorized, err := authorizeStorageInTransitHTTPRequest(a.db, session, shipmentID, false)
+
+	if err != nil {
+		return nil, returnVerrs, err
+	}
+
+	if !isAuthorized {
+		return nil, returnVerrs, models.ErrFetchForbidden
+	}
+
+	storageInTransit, err := models.FetchStorageInTransitByID(a.db, storageInTransitID)
+
+	if err != nil {
+		return nil, returnVerrs, err
+	}
+
+	err = storageInTransit.CanAttemptDelivery()
+	if err != nil {
+		return nil, returnVerrs, err
+	}
+
+	_, shipment, err := models.FetchShipmentForVerifiedTSPUser(a.db, session.TspUserID, shipmentID)
+	if err != nil {
+		return nil, returnVerrs, err
+	}
+
+	err = a.db.Transaction(func(tx *pop.Connection) error {
+		lineItemCreator := CreateStorageInTransitLineItems{DB: tx, Planner: a.planner}
+		_, err := lineItemCreator.CreateAttemptedDeliveryLineItems(*storageInTransit, *shipment, payload.Overtime)
+		return err
+	})
+	if err != nil {
+		return nil, returnVerrs, err
+	}
+
+	return storageInTransit, returnVerrs, nil
+}
+
+// NewStorageInTransitDeliveryAttempter is the public constructor for a `StorageInTransitDeliveryAttempter`
+// using Pop
+func NewStorageInTransitDeliveryAttempter(db *pop.Connection, planner route.Planner) services.StorageInTransitDeliveryAttempter {
+	return &attemptDeliveryStorageInTransit{db, planner}
+}
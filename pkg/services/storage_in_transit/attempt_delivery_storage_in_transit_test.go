@@ -0,0 +1,72 @@
+package storageintransit
+
+import (
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/route"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *StorageInTransitServiceSuite) TestAttemptDeliveryStorageInTransit() {
+	shipment, sit, user := setupStorageInTransitServiceTest(suite)
+	tspUser := testdatagen.MakeDefaultTspUser(suite.DB())
+	session := auth.Session{
+		ApplicationName: auth.TspApp,
+		UserID:          *tspUser.UserID,
+		IDToken:         "fake token",
+		TspUserID:       tspUser.ID,
+	}
+	payload := apimessages.StorageInTransitDeliveryAttemptPayload{}
+
+	attempter := NewStorageInTransitDeliveryAttempter(suite.DB(), route.NewTestingPlanner(20))
+	sit.Status = models.StorageInTransitStatusAPPROVED
+	_, _ = suite.DB().ValidateAndSave(&sit)
+
+	// Should fail if TSP doesn't 'own' the storage in transit
+	_, _, err := attempter.AttemptDeliveryStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	suite.Error(err, "WRITE_CONFLICT")
+
+	// Happy path
+	assertions := testdatagen.Assertions{
+		ShipmentOffer: models.ShipmentOffer{
+			TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+			ShipmentID:                      shipment.ID,
+		},
+	}
+	// Create a shipment offer that uses our generated TSP ID and shipment ID so that our TSP has rights to
+	// attempt the pickup.
+	testdatagen.MakeShipmentOffer(suite.DB(), assertions)
+
+	actualStorageInTransit, verrs, err := attempter.AttemptDeliveryStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	suite.NoError(err)
+	suite.Equal(false, verrs.HasAny())
+	// Attempting a pickup doesn't change the status
+	suite.Equal(models.StorageInTransitStatusAPPROVED, actualStorageInTransit.Status)
+
+	// An attempted pickup from origin within 30 miles only adds its flat rate
+	lineItems, err := models.FetchShipmentLineItemsByStorageInTransitID(suite.DB(), sit.ID)
+	suite.NoError(err)
+	suite.Equal([]string{"17A"}, lineItemCodes(lineItems))
+
+	// An origin SIT can't have a pickup attempted once it's in storage
+	sit.Status = models.StorageInTransitStatusINSIT
+	_, _ = suite.DB().ValidateAndSave(&sit)
+
+	_, _, err = attempter.AttemptDeliveryStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	suite.Error(err, "WRITE_CONFLICT")
+
+	// Should fail for an office user
+	session = auth.Session{
+		ApplicationName: auth.OfficeApp,
+		UserID:          *user.UserID,
+		IDToken:         "fake token",
+		OfficeUserID:    user.ID,
+	}
+
+	sit.Status = models.StorageInTransitStatusAPPROVED
+	_, _ = suite.DB().ValidateAndSave(&sit)
+
+	_, _, err = attempter.AttemptDeliveryStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	suite.Error(err, "FETCH_FORBIDDEN")
+}
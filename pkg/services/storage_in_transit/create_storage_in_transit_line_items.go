@@ -83,23 +83,25 @@ func (c CreateStorageInTransitLineItems) saveLineItem(lineItem *models.ShipmentL
 	return nil
 }
 
-func (c CreateStorageInTransitLineItems) createShipmentLineItem(itemCode string, shipment models.Shipment, sit models.StorageInTransit, currentTime time.Time) (models.ShipmentLineItem, error) {
+func (c CreateStorageInTransitLineItems) createShipmentLineItem(itemCode string, shipment models.Shipment, sit models.StorageInTransit, quantity1 unit.BaseQuantity, quantity2 unit.BaseQuantity, currentTime time.Time) (models.ShipmentLineItem, error) {
 
 	tariffItem, err := models.FetchTariff400ngItemByCode(c.DB, itemCode)
 	if err != nil {
 		return models.ShipmentLineItem{}, errors.Wrapf(err, "Error fetching item code %s - CreateStorageInTransitLineItems()", itemCode)
 	}
 	lineItem := models.ShipmentLineItem{
-		ShipmentID:        shipment.ID,
-		Shipment:          shipment,
-		Tariff400ngItemID: tariffItem.ID,
-		Tariff400ngItem:   tariffItem,
-		Location:          c.shipmentItemLocation(sit.Location),
-		Quantity1:         unit.BaseQuantityFromInt(sit.StorageInTransitDistance.DistanceMiles),
-		Status:            models.ShipmentLineItemStatusAPPROVED,
-		SubmittedDate:     currentTime,
-		Address:           sit.WarehouseAddress,
-		AddressID:         &sit.WarehouseAddressID,
+		ShipmentID:         shipment.ID,
+		Shipment:           shipment,
+		Tariff400ngItemID:  tariffItem.ID,
+		Tariff400ngItem:    tariffItem,
+		Location:           c.shipmentItemLocation(sit.Location),
+		Quantity1:          quantity1,
+		Quantity2:          quantity2,
+		Status:             models.ShipmentLineItemStatusAPPROVED,
+		SubmittedDate:      currentTime,
+		Address:            sit.WarehouseAddress,
+		AddressID:          &sit.WarehouseAddressID,
+		StorageInTransitID: &sit.ID,
 	}
 	err = c.saveLineItem(&lineItem)
 	if err != nil {
@@ -109,89 +111,267 @@ func (c CreateStorageInTransitLineItems) createShipmentLineItem(itemCode string,
 	return lineItem, nil
 }
 
-func (c CreateStorageInTransitLineItems) CreateStorageInTransitLineItems(costByShipment rateengine.CostByShipment) ([]models.ShipmentLineItem, error) {
+// storageInTransitReleasedCodes are the codes of the line items added when a SIT comes out of storage. Every SIT that
+// comes out of storage gets exactly one of the 210 items, so they show whether these have been added.
+var storageInTransitReleasedCodes = map[string]bool{
+	"210A": true,
+	"210C": true,
+	"210F": true,
+}
+
+// pickupOrDeliveryCodes are the item codes for picking up or delivering a SIT, which depend on the distance to or
+// from the warehouse
+type pickupOrDeliveryCodes struct {
+	upTo30Miles       string
+	upTo50Miles       string
+	over50Miles       string
+	over50MilesAlaska string
+}
+
+// storageInTransitPickupOrDeliveryCodes are the codes for picking up or delivering a SIT
+var storageInTransitPickupOrDeliveryCodes = pickupOrDeliveryCodes{"210A", "210B", "210C", "210F"}
+
+// attemptedDeliveryCodes are the codes for an attempted pickup or delivery of a SIT
+var attemptedDeliveryCodes = pickupOrDeliveryCodes{"17A", "17B", "17C", "17G"}
+
+// attemptedDeliveryOvertimeCodes are the codes for an attempted pickup or delivery of a SIT on overtime. Attempts over
+// 50 miles are priced from the linehaul rates whenever they happen.
+var attemptedDeliveryOvertimeCodes = pickupOrDeliveryCodes{"17E", "17F", "17C", "17G"}
+
+// createPickupOrDeliveryLineItems adds the line items for picking up or delivering a SIT, or attempting to, using
+// codes for the distance to or from the warehouse
+func (c CreateStorageInTransitLineItems) createPickupOrDeliveryLineItems(codes pickupOrDeliveryCodes, sit models.StorageInTransit, shipment models.Shipment, weight unit.BaseQuantity, now time.Time) ([]models.ShipmentLineItem, error) {
 	var lineItems []models.ShipmentLineItem
-	shipment := costByShipment.Shipment
-	//cost := costByShipment.Cost needed for 16B Fuelsurcharge
-	now := time.Now()
-	storageInTransits, err := models.FetchStorageInTransitsOnShipment(c.DB, shipment.ID)
-	if err != nil {
-		return nil, errors.Wrap(err, "Error creating StorageInTransit line items")
+
+	// Calculate distance for storage in transit
+	distanceCalculation, err := c.storageInTransitDistance(sit, shipment)
+	if distanceCalculation == nil || err != nil {
+		return nil, errors.Wrap(err, "Error finding the distance calculation for Storage In Transit")
 	}
+	sit.StorageInTransitDistance = *distanceCalculation
+	sit.StorageInTransitDistanceID = &(*distanceCalculation).ID
+	miles := unit.BaseQuantityFromInt(sit.StorageInTransitDistance.DistanceMiles)
 
-	for _, sit := range storageInTransits {
+	/****************************************************************************
+	 * Add 210A, 210B, 210C and 210F Shipment Line Items, or for attempts 17A, 17B, 17C and 17G
+	 * 210A-E = Additional flat rate costs based on distance to/from the SIT facility. These vary based on geographical schedules.
+	 *
+	 *
+	 * Up to 30 miles: Item 210A --  SIT Pup/Del - 30 or Less Miles
+	 * Up to 50 miles: Item 201A & 210B -- SIT Pup/Del 31 - 50 Miles
+	 * Over 50 miles : Item 210C (Use the linehaul tables for computation of charges) -- SIT Pup/Del - Over 50 Miles
+	 * Over 50 miles (Alaska only) : Item 210F (Use linehaul tables section 7 Intra-AK)
+	 ****************************************************************************/
 
-		// Only add line items for storage in transits that have been released or delivered
-		if sit.Status != models.StorageInTransitStatusDELIVERED && sit.Status != models.StorageInTransitStatusRELEASED {
-			continue
+	const distance30Miles = 30
+	const distance50Miles = 50
+	if sit.StorageInTransitDistance.DistanceMiles > distance50Miles {
+		code := codes.over50Miles
+		if sit.IsInAlaska(shipment) {
+			code = codes.over50MilesAlaska
+		}
+		additionalFlatRate, err := c.createShipmentLineItem(code, shipment, sit, miles, unit.BaseQuantity(0), now)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error creating shipment line item for %s - CreateStorageInTransitLineItems()", code)
 		}
+		lineItems = append(lineItems, additionalFlatRate)
 
-		// Calculate distance for storage in transit
-		distanceCalculation, err := c.storageInTransitDistance(sit, shipment)
-		if distanceCalculation == nil || err != nil {
-			return nil, errors.Wrap(err, "Error finding the distance calculation for Storage In Transit")
+		// Fuel Surcharge (16B) - DEL to/from SIT (Deliver to and from Storage in Transit), for the linehaul over 50 miles
+		fuelSurcharge, err := c.createShipmentLineItem("16B", shipment, sit, weight, miles, now)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error creating shipment line item for 16B - CreateStorageInTransitLineItems()")
 		}
-		sit.StorageInTransitDistance = *distanceCalculation
-		sit.StorageInTransitDistanceID = &(*distanceCalculation).ID
+		lineItems = append(lineItems, fuelSurcharge)
+	} else {
+		if sit.StorageInTransitDistance.DistanceMiles > distance30Miles {
+			additionalFlatRate, err := c.createShipmentLineItem(codes.upTo50Miles, shipment, sit, miles, unit.BaseQuantity(0), now)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error creating shipment line item for %s - CreateStorageInTransitLineItems()", codes.upTo50Miles)
+			}
+			lineItems = append(lineItems, additionalFlatRate)
+		}
+		additionalFlatRate, err := c.createShipmentLineItem(codes.upTo30Miles, shipment, sit, miles, unit.BaseQuantity(0), now)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error creating shipment line item for %s - CreateStorageInTransitLineItems()", codes.upTo30Miles)
+		}
+		lineItems = append(lineItems, additionalFlatRate)
+	}
+
+	return lineItems, nil
+}
+
+// createPlacedIntoSITLineItems adds the line items for a SIT going into storage
+func (c CreateStorageInTransitLineItems) createPlacedIntoSITLineItems(sit models.StorageInTransit, shipment models.Shipment, now time.Time) ([]models.ShipmentLineItem, error) {
+	var netWeight unit.Pound
+	if shipment.NetWeight != nil {
+		netWeight = *shipment.NetWeight
+	}
+
+	/****************************************************************************
+	 * Add 185A Shipment Line Item
+	 * 185A = SIT first day and warehouse handling, priced per hundredweight placed in SIT
+	 ****************************************************************************/
+	weight := unit.BaseQuantityFromInt(sit.Weight(netWeight).Int())
+	firstDay, err := c.createShipmentLineItem("185A", shipment, sit, weight, unit.BaseQuantity(0), now)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating shipment line item for 185A - CreateStorageInTransitLineItems()")
+	}
+	return []models.ShipmentLineItem{firstDay}, nil
+}
+
+// createReleasedLineItems adds the line items for a SIT coming out of storage
+func (c CreateStorageInTransitLineItems) createReleasedLineItems(sit models.StorageInTransit, shipment models.Shipment, now time.Time) ([]models.ShipmentLineItem, error) {
+	var lineItems []models.ShipmentLineItem
+	var netWeight unit.Pound
+	if shipment.NetWeight != nil {
+		netWeight = *shipment.NetWeight
+	}
+	weight := unit.BaseQuantityFromInt(sit.Weight(netWeight).Int())
+
+	/****************************************************************************
+	 * Add 185B Shipment Line Item for each day in SIT after the first
+	 * 185B = SIT additional days, priced per hundredweight placed in SIT per day
+	 ****************************************************************************/
+	if additionalDays := sit.DaysInSIT() - 1; additionalDays > 0 {
+		additionalDaysItem, err := c.createShipmentLineItem("185B", shipment, sit, unit.BaseQuantityFromInt(additionalDays), weight, now)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error creating shipment line item for 185B - CreateStorageInTransitLineItems()")
+		}
+		lineItems = append(lineItems, additionalDaysItem)
+	}
+
+	pickupOrDeliveryItems, err := c.createPickupOrDeliveryLineItems(storageInTransitPickupOrDeliveryCodes, sit, shipment, weight, now)
+	if err != nil {
+		return nil, err
+	}
+	lineItems = append(lineItems, pickupOrDeliveryItems...)
+
+	return lineItems, nil
+}
+
+// CreateAttemptedDeliveryLineItems adds the line items for a failed attempt to pick up a SIT into storage or deliver it
+// out of storage, and returns them. Overtime attempts within 50 miles have their own codes. Goods that come back to a
+// destination warehouse are handled into storage again (17D).
+func (c CreateStorageInTransitLineItems) CreateAttemptedDeliveryLineItems(sit models.StorageInTransit, shipment models.Shipment, overtime bool) ([]models.ShipmentLineItem, error) {
+	var netWeight unit.Pound
+	if shipment.NetWeight != nil {
+		netWeight = *shipment.NetWeight
+	}
+	weight := unit.BaseQuantityFromInt(sit.Weight(netWeight).Int())
+	now := time.Now()
 
+	codes := attemptedDeliveryCodes
+	if overtime {
+		codes = attemptedDeliveryOvertimeCodes
+	}
+	lineItems, err := c.createPickupOrDeliveryLineItems(codes, sit, shipment, weight, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if sit.Location == models.StorageInTransitLocationDESTINATION {
 		/****************************************************************************
-		 * Add 210A, 210B, and 210C Shipment Line Items
-		 * 210A-E = Additional flat rate costs based on distance to/from the SIT facility. These vary based on geographical schedules.
-		 *
-		 *
-		 * Up to 30 miles: Item 210A --  SIT Pup/Del - 30 or Less Miles
-		 * Up to 50 miles: Item 201A & 210B -- SIT Pup/Del 31 - 50 Miles
-		 * Over 50 miles : Item 210C (Use the linehaul tables for computation of charges) -- SIT Pup/Del - Over 50 Miles
-		 * Over 50 miles (Alaska only) : Item 210F (Use linehaul tables section 7 Intra-AK)
+		 * Add 17D Shipment Line Item
+		 * 17D = Attempted delivery first day, priced per hundredweight like 185A
 		 ****************************************************************************/
+		firstDay, err := c.createShipmentLineItem("17D", shipment, sit, weight, unit.BaseQuantity(0), now)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error creating shipment line item for 17D - CreateStorageInTransitLineItems()")
+		}
+		lineItems = append(lineItems, firstDay)
+	}
+	return lineItems, nil
+}
 
-		const distance30Miles = 30
-		const distance50Miles = 50
-		if sit.StorageInTransitDistance.DistanceMiles > distance50Miles {
-			additionalFlatRate, err := c.createShipmentLineItem("210C", shipment, sit, now)
-			if err != nil {
-				return nil, errors.Wrapf(err, "Error creating shipment line item for 210C - CreateStorageInTransitLineItems()")
-			}
-			lineItems = append(lineItems, additionalFlatRate)
-		} else {
-			if sit.StorageInTransitDistance.DistanceMiles > distance30Miles {
-				additionalFlatRate, err := c.createShipmentLineItem("210B", shipment, sit, now)
-				if err != nil {
-					return nil, errors.Wrapf(err, "Error creating shipment line item for 210B - CreateStorageInTransitLineItems()")
-				}
-				lineItems = append(lineItems, additionalFlatRate)
-			}
-			additionalFlatRate, err := c.createShipmentLineItem("210A", shipment, sit, now)
-			if err != nil {
-				return nil, errors.Wrapf(err, "Error creating shipment line item for 210A - CreateStorageInTransitLineItems()")
-			}
-			lineItems = append(lineItems, additionalFlatRate)
+// CreateLineItemsForStorageInTransit adds the line items for the events a SIT has been through so far: the first day
+// and warehouse handling once it's placed in SIT, and the additional days and pickup or delivery once it's released
+// or delivered. It's called as the SIT goes through each event, and doesn't add line items it already has. It returns
+// all of the SIT's line items.
+func (c CreateStorageInTransitLineItems) CreateLineItemsForStorageInTransit(sit models.StorageInTransit, shipment models.Shipment) ([]models.ShipmentLineItem, error) {
+	lineItems, err := models.FetchShipmentLineItemsByStorageInTransitID(c.DB, sit.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching StorageInTransit line items")
+	}
+	hasPlacedIntoSITItems := false
+	hasReleasedItems := false
+	for _, lineItem := range lineItems {
+		if lineItem.Tariff400ngItem.Code == "185A" {
+			hasPlacedIntoSITItems = true
 		}
+		if storageInTransitReleasedCodes[lineItem.Tariff400ngItem.Code] {
+			hasReleasedItems = true
+		}
+	}
+
+	// Only storage in transits that have been placed in SIT have line items
+	if sit.ActualStartDate == nil {
+		return lineItems, nil
+	}
+	if sit.Status != models.StorageInTransitStatusINSIT &&
+		sit.Status != models.StorageInTransitStatusRELEASED &&
+		sit.Status != models.StorageInTransitStatusDELIVERED {
+		return lineItems, nil
+	}
 
-		/* TODO: Failing to load 16B from database
-		// https://www.pivotaltracker.com/story/show/166766741
-		// Fuel Surcharge (16B) - DEL to/from SIT (Deliver to and from Storage in Transit)
-		fuelSurchargeItem, err := models.FetchTariff400ngItemByCode(c.DB, "16B")
+	now := time.Now()
+	if !hasPlacedIntoSITItems {
+		placedIntoSITItems, err := c.createPlacedIntoSITLineItems(sit, shipment, now)
 		if err != nil {
 			return nil, err
 		}
+		lineItems = append(lineItems, placedIntoSITItems...)
+	}
 
-		fsAppliedRate := &cost.LinehaulCostComputation.FuelSurcharge.Rate
-		fuelSurcharge := models.ShipmentLineItem{
-			ShipmentID:        shipment.ID,
-			Tariff400ngItemID: fuelSurchargeItem.ID,
-			Tariff400ngItem:   fuelSurchargeItem,
-			Location:          models.ShipmentLineItemLocation(fuelSurchargeItem.AllowedLocation),
-			Quantity1:         unit.BaseQuantityFromInt(shipment.NetWeight.Int()),
-			Quantity2:         unit.BaseQuantityFromInt(sit.StorageInTransitDistance.DistanceMiles),
-			Status:            models.ShipmentLineItemStatusAPPROVED,
-			AmountCents:       &cost.LinehaulCostComputation.FuelSurcharge.Fee,
-			AppliedRate:       fsAppliedRate,
-			SubmittedDate:     now,
+	// The remaining line items are only added for storage in transits that have been released or delivered
+	if sit.Status != models.StorageInTransitStatusDELIVERED && sit.Status != models.StorageInTransitStatusRELEASED {
+		return lineItems, nil
+	}
+	if !hasReleasedItems {
+		releasedItems, err := c.createReleasedLineItems(sit, shipment, now)
+		if err != nil {
+			return nil, err
 		}
-		lineItems = append(lineItems, fuelSurcharge)
-		*/
+		lineItems = append(lineItems, releasedItems...)
+	}
+	return lineItems, nil
+}
 
+// CreateStorageInTransitLineItems returns the line items for all of the storage in transits on a shipment so they
+// can be priced, adding any that are missing, like those for destination SITs that were delivered with the shipment
+func (c CreateStorageInTransitLineItems) CreateStorageInTransitLineItems(costByShipment rateengine.CostByShipment) ([]models.ShipmentLineItem, error) {
+	var lineItems []models.ShipmentLineItem
+	shipment := costByShipment.Shipment
+	storageInTransits, err := models.FetchStorageInTransitsOnShipment(c.DB, shipment.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating StorageInTransit line items")
+	}
+
+	var netWeight unit.Pound
+	if shipment.NetWeight != nil {
+		netWeight = *shipment.NetWeight
+	}
+
+	for _, sit := range storageInTransits {
+		sitLineItems, err := c.CreateLineItemsForStorageInTransit(sit, shipment)
+		if err != nil {
+			return nil, err
+		}
+		// Line items added before the shipment was weighed need the weight placed in SIT
+		weight := unit.BaseQuantityFromInt(sit.Weight(netWeight).Int())
+		for _, lineItem := range sitLineItems {
+			// Line items that have been invoiced have already been paid for
+			if lineItem.InvoiceID != nil {
+				continue
+			}
+			switch lineItem.Tariff400ngItem.Code {
+			case "17D", "185A", "16B":
+				lineItem.Quantity1 = weight
+			case "185B":
+				lineItem.Quantity2 = weight
+			}
+			lineItem.Shipment = shipment
+			lineItems = append(lineItems, lineItem)
+		}
 	}
 	return lineItems, nil
 }
@@ -145,9 +145,16 @@ func (suite *StorageInTransitServiceSuite) TestCreateStorageInTransitLineItems()
 			suite.Equal(sit.ShipmentID, shipmentID, "sit.ShipmentID, shipmentID are the same")
 		}
 
+		item185A := suite.findLineItem(storageInTransitLineItems, "185A")
+		if item185A != nil {
+			suite.validateLineItemFields(*item185A, unit.BaseQuantityFromInt(shipmentCost.Shipment.NetWeight.Int()), unit.BaseQuantityFromInt(0), models.ShipmentLineItemLocationORIGIN)
+			suite.Equal(storageInTransits[0].ID, *item185A.StorageInTransitID)
+		}
+
 		item210C := suite.findLineItem(storageInTransitLineItems, "210C")
 		if item210C != nil {
 			suite.validateLineItemFields(*item210C, unit.BaseQuantityFromInt(1044), unit.BaseQuantityFromInt(0), models.ShipmentLineItemLocationORIGIN)
+			suite.Equal(storageInTransits[0].ID, *item210C.StorageInTransitID)
 		}
 
 	})
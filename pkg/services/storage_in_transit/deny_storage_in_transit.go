@@ -4,11 +4,13 @@ import (
 	"github.com/gobuffalo/pop"
 	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/gen/apimessages"
 	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/services"
+	"github.com/transcom/mymove/pkg/webhooks"
 )
 
 type denyStorageInTransit struct {
@@ -16,7 +18,7 @@ type denyStorageInTransit struct {
 }
 
 // DenyStorageInTransit sets the status of a Storage In Transit to denied, saves its Authorization Notes, and returns the updated object.
-func (d *denyStorageInTransit) DenyStorageInTransit(payload apimessages.StorageInTransitDenialPayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error) {
+func (d *denyStorageInTransit) DenyStorageInTransit(payload apimessages.StorageInTransitDenialPayload, shipmentID uuid.UUID, session *auth.Session, requestID string, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error) {
 	returnVerrs := validate.NewErrors()
 
 	// Only office users are authorized to do this.
@@ -35,15 +37,29 @@ func (d *denyStorageInTransit) DenyStorageInTransit(payload apimessages.StorageI
 		return nil, returnVerrs, models.ErrFetchForbidden
 	}
 
-	if storageInTransit.Status == models.StorageInTransitStatusDELIVERED {
-		return nil, returnVerrs, models.ErrWriteConflict
+	storageInTransit.SetTransitionActor(models.NewTransitionActor(session, requestID))
+	err = storageInTransit.Deny(payload.AuthorizationNotes)
+	if err != nil {
+		return nil, returnVerrs, err
 	}
 
-	storageInTransit.Status = models.StorageInTransitStatusDENIED
-	storageInTransit.AuthorizationNotes = &payload.AuthorizationNotes
+	// Queue the webhook with the denial, so it is only sent if it saves
+	err = d.db.Transaction(func(tx *pop.Connection) error {
+		verrs, err := models.SaveWithStateTransitions(tx, storageInTransit)
+		if verrs.HasAny() || err != nil {
+			returnVerrs.Append(verrs)
+			if err == nil {
+				err = errors.New("Rollback The transaction")
+			}
+			return err
+		}
 
-	if verrs, err := d.db.ValidateAndSave(storageInTransit); verrs.HasAny() || err != nil {
-		returnVerrs.Append(verrs)
+		return webhooks.Publish(tx, webhooks.NewStorageInTransitEvent(models.WebhookEventTypeSTORAGEINTRANSITDENIED, *storageInTransit))
+	})
+	if returnVerrs.HasAny() {
+		return nil, returnVerrs, nil
+	}
+	if err != nil {
 		return nil, returnVerrs, err
 	}
 
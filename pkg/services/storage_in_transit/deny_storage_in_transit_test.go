@@ -24,14 +24,14 @@ func (suite *StorageInTransitServiceSuite) TestDenyStorageInTransit() {
 	denier := NewStorageInTransitDenier(suite.DB())
 
 	// Should not work for a TSP user
-	_, _, err := denier.DenyStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	_, _, err := denier.DenyStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.Error(err, "FETCH_FORBIDDEN")
 
 	// Should not work if the status is already delivered
 	sit.Status = models.StorageInTransitStatusDELIVERED
 	_, _ = suite.DB().ValidateAndSave(&sit)
 
-	_, _, err = denier.DenyStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	_, _, err = denier.DenyStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.Error(err, "WRITE_CONFLICT")
 
 	// Happy path
@@ -45,7 +45,7 @@ func (suite *StorageInTransitServiceSuite) TestDenyStorageInTransit() {
 		OfficeUserID:    user.ID,
 	}
 
-	actualStorageInTransit, verrs, err := denier.DenyStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	actualStorageInTransit, verrs, err := denier.DenyStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.NoError(err)
 	suite.False(verrs.HasAny())
 	suite.Equal(models.StorageInTransitStatusDENIED, actualStorageInTransit.Status)
@@ -6,6 +6,7 @@ import (
 	"github.com/gobuffalo/pop"
 	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/gen/apimessages"
@@ -48,10 +49,11 @@ func patchStorageInTransitWithPayload(storageInTransit *models.StorageInTransit,
 
 	storageInTransit.ActualStartDate = (*time.Time)(payload.ActualStartDate)
 	storageInTransit.OutDate = (*time.Time)(payload.OutDate)
+	storageInTransit.WeightLbs = handlers.PoundPtrFromInt64Ptr(payload.WeightLbs)
 }
 
 // PatchStorageInTransit edits an existing storage in transit and returns the updated object.
-func (p *patchStorageInTransit) PatchStorageInTransit(payload apimessages.StorageInTransit, shipmentID uuid.UUID, storageInTransitID uuid.UUID, session *auth.Session) (*models.StorageInTransit, *validate.Errors, error) {
+func (p *patchStorageInTransit) PatchStorageInTransit(payload apimessages.StorageInTransit, shipmentID uuid.UUID, storageInTransitID uuid.UUID, expectedVersions []int, session *auth.Session) (*models.StorageInTransit, *validate.Errors, error) {
 	returnVerrs := validate.NewErrors()
 
 	// Both TSPs and Office users can do this. TSPs can edit based on whether or not its their shipment.
@@ -72,7 +74,15 @@ func (p *patchStorageInTransit) PatchStorageInTransit(payload apimessages.Storag
 	}
 
 	patchStorageInTransitWithPayload(storageInTransit, &payload)
+	storageInTransit.ExpectVersions(expectedVersions)
 	verrs, err := models.SaveStorageInTransitAndAddress(p.db, storageInTransit)
+	if errors.Cause(err) == models.ErrStaleVersion {
+		current, fetchErr := models.FetchStorageInTransitByID(p.db, storageInTransitID)
+		if fetchErr != nil {
+			return nil, returnVerrs, fetchErr
+		}
+		return current, returnVerrs, err
+	}
 	if err != nil || verrs.HasAny() {
 		returnVerrs.Append(verrs)
 		return nil, returnVerrs, err
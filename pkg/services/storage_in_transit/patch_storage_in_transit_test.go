@@ -2,6 +2,7 @@ package storageintransit
 
 import (
 	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/models"
@@ -26,10 +27,16 @@ func (suite *StorageInTransitServiceSuite) TestPatchStorageInTransit() {
 	patcher := NewStorageInTransitPatcher(suite.DB())
 
 	// Office happy path
-	actualStorageInTransit, verrs, err := patcher.PatchStorageInTransit(*payload, shipment.ID, sit.ID, &session)
+	actualStorageInTransit, verrs, err := patcher.PatchStorageInTransit(*payload, shipment.ID, sit.ID, nil, &session)
 	suite.NoError(err)
 	suite.False(verrs.HasAny())
 	storageInTransitCompare(suite, *actualStorageInTransit, sit)
+	suite.Equal(1, actualStorageInTransit.Version)
+
+	// Fail when the SIT has changed since the expected version, returning the current SIT
+	currentStorageInTransit, _, err := patcher.PatchStorageInTransit(*payload, shipment.ID, sit.ID, []int{0}, &session)
+	suite.Equal(models.ErrStaleVersion, errors.Cause(err))
+	suite.Equal(1, currentStorageInTransit.Version)
 
 	// Fail with service member user
 	session = auth.Session{
@@ -39,7 +46,7 @@ func (suite *StorageInTransitServiceSuite) TestPatchStorageInTransit() {
 		ServiceMemberID: user.ID,
 	}
 
-	_, _, err = patcher.PatchStorageInTransit(*payload, shipment.ID, sit.ID, &session)
+	_, _, err = patcher.PatchStorageInTransit(*payload, shipment.ID, sit.ID, nil, &session)
 	suite.Error(err, "FETCH_FORBIDDEN")
 
 	// Fail when tsp doesn't 'own' shipment
@@ -50,7 +57,7 @@ func (suite *StorageInTransitServiceSuite) TestPatchStorageInTransit() {
 		TspUserID:       tspUser.ID,
 	}
 
-	_, _, err = patcher.PatchStorageInTransit(*payload, shipment.ID, sit.ID, &session)
+	_, _, err = patcher.PatchStorageInTransit(*payload, shipment.ID, sit.ID, nil, &session)
 	suite.Error(err, "FETCH_FORBIDDEN")
 
 	// TSP Happy path
@@ -63,7 +70,7 @@ func (suite *StorageInTransitServiceSuite) TestPatchStorageInTransit() {
 	// Create a shipment offer that uses our generated TSP ID and shipment ID so that our TSP has rights to
 	// change the status to in_sit.
 	testdatagen.MakeShipmentOffer(suite.DB(), assertions)
-	actualStorageInTransit, verrs, err = patcher.PatchStorageInTransit(*payload, shipment.ID, sit.ID, &session)
+	actualStorageInTransit, verrs, err = patcher.PatchStorageInTransit(*payload, shipment.ID, sit.ID, nil, &session)
 	suite.NoError(err)
 	suite.False(verrs.HasAny())
 	storageInTransitCompare(suite, *actualStorageInTransit, sit)
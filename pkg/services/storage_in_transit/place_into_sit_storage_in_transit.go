@@ -6,6 +6,7 @@ import (
 	"github.com/gobuffalo/pop"
 	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/gen/apimessages"
@@ -17,8 +18,9 @@ type placeIntoSITStorageInTransit struct {
 	db *pop.Connection
 }
 
-// PlaceIntoSITStorageInTransit sets the status of a Storage In Transit to IN SIT, saves its ActualStartDate and the generated SIT number, and returns the updated object.
-func (p *placeIntoSITStorageInTransit) PlaceIntoSITStorageInTransit(payload apimessages.StorageInTransitInSitPayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error) {
+// PlaceIntoSITStorageInTransit sets the status of a Storage In Transit to IN SIT, saves its ActualStartDate and the generated SIT number,
+// adds its first day line item, and returns the updated object.
+func (p *placeIntoSITStorageInTransit) PlaceIntoSITStorageInTransit(payload apimessages.StorageInTransitInSitPayload, shipmentID uuid.UUID, session *auth.Session, requestID string, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error) {
 	returnVerrs := validate.NewErrors()
 
 	// Only TSP users are authorized to do this.
@@ -43,15 +45,12 @@ func (p *placeIntoSITStorageInTransit) PlaceIntoSITStorageInTransit(payload apim
 		return nil, returnVerrs, models.ErrFetchForbidden
 	}
 
-	if !(storageInTransit.Status == models.StorageInTransitStatusAPPROVED) {
-		return nil, returnVerrs, models.ErrWriteConflict
+	storageInTransit.SetTransitionActor(models.NewTransitionActor(session, requestID))
+	err = storageInTransit.PlaceIntoSIT((time.Time)(payload.ActualStartDate))
+	if err != nil {
+		return nil, returnVerrs, err
 	}
 
-	payloadActualStartDate := (time.Time)(payload.ActualStartDate)
-
-	storageInTransit.Status = models.StorageInTransitStatusINSIT
-	storageInTransit.ActualStartDate = &payloadActualStartDate
-
 	storageInTransitNumberGenerator := NewStorageInTransitNumberGenerator(p.db)
 	storageInTransitNumber, err := storageInTransitNumberGenerator.GenerateStorageInTransitNumber(*storageInTransit.ActualStartDate)
 	if err != nil {
@@ -60,8 +59,29 @@ func (p *placeIntoSITStorageInTransit) PlaceIntoSITStorageInTransit(payload apim
 
 	storageInTransit.SITNumber = &storageInTransitNumber
 
-	if verrs, err := p.db.ValidateAndSave(storageInTransit); verrs.HasAny() || err != nil {
-		returnVerrs.Append(verrs)
+	_, shipment, err := models.FetchShipmentForVerifiedTSPUser(p.db, session.TspUserID, shipmentID)
+	if err != nil {
+		return nil, returnVerrs, err
+	}
+
+	err = p.db.Transaction(func(tx *pop.Connection) error {
+		verrs, err := models.SaveWithStateTransitions(tx, storageInTransit)
+		if verrs.HasAny() || err != nil {
+			returnVerrs.Append(verrs)
+			if err == nil {
+				err = errors.New("Rollback The transaction")
+			}
+			return err
+		}
+
+		lineItemCreator := CreateStorageInTransitLineItems{DB: tx}
+		_, err = lineItemCreator.CreateLineItemsForStorageInTransit(*storageInTransit, *shipment)
+		return err
+	})
+	if returnVerrs.HasAny() {
+		return nil, returnVerrs, nil
+	}
+	if err != nil {
 		return nil, returnVerrs, err
 	}
 
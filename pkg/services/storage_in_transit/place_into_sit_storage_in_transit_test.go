@@ -39,11 +39,16 @@ func (suite *StorageInTransitServiceSuite) TestPlaceIntoSITStorageInTransit() {
 	// change the status to in_sit.
 	testdatagen.MakeShipmentOffer(suite.DB(), assertions)
 
-	actualStorageInTransit, verrs, err := inSITPlacer.PlaceIntoSITStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	actualStorageInTransit, verrs, err := inSITPlacer.PlaceIntoSITStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.NoError(err)
 	suite.False(verrs.HasAny())
 	suite.Equal(models.StorageInTransitStatusINSIT, actualStorageInTransit.Status)
 
+	// Placing it into SIT adds its first day line item
+	lineItems, err := models.FetchShipmentLineItemsByStorageInTransitID(suite.DB(), sit.ID)
+	suite.NoError(err)
+	suite.Equal([]string{"185A"}, lineItemCodes(lineItems))
+
 	// Shouldn't work with an office user
 	session = auth.Session{
 		ApplicationName: auth.OfficeApp,
@@ -52,13 +57,13 @@ func (suite *StorageInTransitServiceSuite) TestPlaceIntoSITStorageInTransit() {
 		OfficeUserID:    user.ID,
 	}
 
-	_, _, err = inSITPlacer.PlaceIntoSITStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	_, _, err = inSITPlacer.PlaceIntoSITStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.Error(err, "FETCH_FORBIDDEN")
 
 	// Shouldn't work if status is not approved
 	sit.Status = models.StorageInTransitStatusREQUESTED
 	_, _ = suite.DB().ValidateAndSave(&sit)
 
-	_, _, err = inSITPlacer.PlaceIntoSITStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	_, _, err = inSITPlacer.PlaceIntoSITStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.Error(err, "FETCH_FORBIDDEN")
 }
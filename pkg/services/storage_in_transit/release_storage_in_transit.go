@@ -6,19 +6,23 @@ import (
 	"github.com/gobuffalo/pop"
 	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/gen/apimessages"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/route"
 	"github.com/transcom/mymove/pkg/services"
 )
 
 type releaseStorageInTransit struct {
-	db *pop.Connection
+	db      *pop.Connection
+	planner route.Planner
 }
 
-// ReleaseStorageInTransit sets the status of a Storage In Transit at Origin to released, saves its released on date, and returns the updated object.
-func (r *releaseStorageInTransit) ReleaseStorageInTransit(payload apimessages.StorageInTransitReleasePayload, shipmentID uuid.UUID, session *auth.Session, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error) {
+// ReleaseStorageInTransit sets the status of a Storage In Transit at Origin to released, saves its released on date, adds its additional days
+// and pickup line items, and returns the updated object.
+func (r *releaseStorageInTransit) ReleaseStorageInTransit(payload apimessages.StorageInTransitReleasePayload, shipmentID uuid.UUID, session *auth.Session, requestID string, storageInTransitID uuid.UUID) (*models.StorageInTransit, *validate.Errors, error) {
 	returnVerrs := validate.NewErrors()
 
 	// Only TSPs are authorized to do this and they should only be able to on their own shipments
@@ -38,22 +42,36 @@ func (r *releaseStorageInTransit) ReleaseStorageInTransit(payload apimessages.St
 		return nil, returnVerrs, err
 	}
 
-	// Make sure this is a SIT at origin
-	if storageInTransit.Location != models.StorageInTransitLocationORIGIN {
-		return nil, returnVerrs, models.ErrInvalidTransition
+	// Only an origin SIT that is in SIT, or delivered by mistake, can be released
+	storageInTransit.SetTransitionActor(models.NewTransitionActor(session, requestID))
+	err = storageInTransit.Release((time.Time)(payload.ReleasedOn))
+	if err != nil {
+		return nil, returnVerrs, err
 	}
 
-	// Make sure we're not releasing something that wasn't in SIT or in delivered status.
-	// The latter is there so that we can 'undo' a mistaken deliver action.
-	if !(storageInTransit.Status == models.StorageInTransitStatusINSIT) &&
-		!(storageInTransit.Status == models.StorageInTransitStatusDELIVERED) {
-		return nil, returnVerrs, models.ErrWriteConflict
+	_, shipment, err := models.FetchShipmentForVerifiedTSPUser(r.db, session.TspUserID, shipmentID)
+	if err != nil {
+		return nil, returnVerrs, err
 	}
-	storageInTransit.Status = models.StorageInTransitStatusRELEASED
-	storageInTransit.OutDate = (*time.Time)(&payload.ReleasedOn)
 
-	if verrs, err := r.db.ValidateAndSave(storageInTransit); verrs.HasAny() || err != nil {
-		returnVerrs.Append(verrs)
+	err = r.db.Transaction(func(tx *pop.Connection) error {
+		verrs, err := models.SaveWithStateTransitions(tx, storageInTransit)
+		if verrs.HasAny() || err != nil {
+			returnVerrs.Append(verrs)
+			if err == nil {
+				err = errors.New("Rollback The transaction")
+			}
+			return err
+		}
+
+		lineItemCreator := CreateStorageInTransitLineItems{DB: tx, Planner: r.planner}
+		_, err = lineItemCreator.CreateLineItemsForStorageInTransit(*storageInTransit, *shipment)
+		return err
+	})
+	if returnVerrs.HasAny() {
+		return nil, returnVerrs, nil
+	}
+	if err != nil {
 		return nil, returnVerrs, err
 	}
 
@@ -62,6 +80,6 @@ func (r *releaseStorageInTransit) ReleaseStorageInTransit(payload apimessages.St
 
 // NewStorageInTransitInReleaser is the public constructor for a `NewStorageInTransitInReleaser`
 // using Pop
-func NewStorageInTransitInReleaser(db *pop.Connection) services.StorageInTransitReleaser {
-	return &releaseStorageInTransit{db}
+func NewStorageInTransitInReleaser(db *pop.Connection, planner route.Planner) services.StorageInTransitReleaser {
+	return &releaseStorageInTransit{db, planner}
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/transcom/mymove/pkg/gen/apimessages"
 	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/route"
 	"github.com/transcom/mymove/pkg/testdatagen"
 )
 
@@ -21,12 +22,14 @@ func (suite *StorageInTransitServiceSuite) TestReleaseStorageInTransit() {
 		ReleasedOn: *handlers.FmtDate(testdatagen.DateInsidePeakRateCycle),
 	}
 
-	releaser := NewStorageInTransitInReleaser(suite.DB())
+	releaser := NewStorageInTransitInReleaser(suite.DB(), route.NewTestingPlanner(20))
+	actualStartDate := testdatagen.DateInsidePeakRateCycle.AddDate(0, 0, -10)
+	sit.ActualStartDate = &actualStartDate
 	sit.Status = models.StorageInTransitStatusINSIT
 	_, _ = suite.DB().ValidateAndSave(&sit)
 
 	// Should fail if TSP doesn't 'own' the storage in transit
-	_, _, err := releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	_, _, err := releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.Error(err, "WRITE_CONFLICT")
 
 	// Happy path
@@ -40,25 +43,35 @@ func (suite *StorageInTransitServiceSuite) TestReleaseStorageInTransit() {
 	// change the status to in_sit.
 	testdatagen.MakeShipmentOffer(suite.DB(), assertions)
 
-	actualStorageInTransit, verrs, err := releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	actualStorageInTransit, verrs, err := releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.NoError(err)
 	suite.Equal(false, verrs.HasAny())
 	suite.Equal(models.StorageInTransitStatusRELEASED, actualStorageInTransit.Status)
 
+	// Releasing it adds its first day, additional days and pickup line items
+	lineItems, err := models.FetchShipmentLineItemsByStorageInTransitID(suite.DB(), sit.ID)
+	suite.NoError(err)
+	suite.Equal([]string{"185A", "185B", "210A"}, lineItemCodes(lineItems))
+
 	// It should also work if we're coming back from delivered status
 	sit.Status = models.StorageInTransitStatusDELIVERED
 	_, _ = suite.DB().ValidateAndSave(&sit)
 
-	actualStorageInTransit, verrs, err = releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	actualStorageInTransit, verrs, err = releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.NoError(err)
 	suite.Equal(false, verrs.HasAny())
 	suite.Equal(models.StorageInTransitStatusRELEASED, actualStorageInTransit.Status)
 
+	// The line items it already has aren't added again
+	lineItems, err = models.FetchShipmentLineItemsByStorageInTransitID(suite.DB(), sit.ID)
+	suite.NoError(err)
+	suite.Len(lineItems, 3)
+
 	// It should fail if we're skipping a step (going from requested to released)
 	sit.Status = models.StorageInTransitStatusREQUESTED
 	_, _ = suite.DB().ValidateAndSave(&sit)
 
-	actualStorageInTransit, verrs, err = releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	actualStorageInTransit, verrs, err = releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.Error(err, "WRITE_CONFLICT")
 
 	// Should fail for an office user
@@ -72,7 +85,7 @@ func (suite *StorageInTransitServiceSuite) TestReleaseStorageInTransit() {
 	sit.Status = models.StorageInTransitStatusINSIT
 	_, _ = suite.DB().ValidateAndSave(&sit)
 
-	actualStorageInTransit, verrs, err = releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, sit.ID)
+	actualStorageInTransit, verrs, err = releaser.ReleaseStorageInTransit(payload, shipment.ID, &session, "", sit.ID)
 	suite.Error(err, "FETCH_FORBIDDEN")
 
 }
@@ -1,6 +1,7 @@
 package storageintransit
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/transcom/mymove/pkg/gen/apimessages"
@@ -50,6 +51,16 @@ func setupStorageInTransitServiceTest(suite *StorageInTransitServiceSuite) (ship
 	return shipment, sit, user
 }
 
+// lineItemCodes returns the sorted item codes of line items
+func lineItemCodes(lineItems []models.ShipmentLineItem) []string {
+	codes := make([]string, len(lineItems))
+	for i, lineItem := range lineItems {
+		codes[i] = lineItem.Tariff400ngItem.Code
+	}
+	sort.Strings(codes)
+	return codes
+}
+
 func storageInTransitCompare(suite *StorageInTransitServiceSuite, expected models.StorageInTransit, actual models.StorageInTransit) {
 	suite.Equal(expected.WarehouseEmail, actual.WarehouseEmail)
 	suite.Equal(expected.Notes, actual.Notes)
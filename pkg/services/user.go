@@ -9,8 +9,8 @@ type OfficeUserFetcher interface {
 	FetchOfficeUser(filters []QueryFilter) (models.OfficeUser, error)
 }
 
-// OfficeUserListFetcher is the exported interface for fetching multiple office users
+// OfficeUserListFetcher is the exported interface for fetching a page of office users and the total number of matches
 //go:generate mockery -name OfficeUserListFetcher
 type OfficeUserListFetcher interface {
-	FetchOfficeUserList(filters []QueryFilter) (models.OfficeUsers, error)
+	FetchOfficeUserList(filters []QueryFilter, orders []QueryOrder, pagination Pagination) (models.OfficeUsers, int, error)
 }
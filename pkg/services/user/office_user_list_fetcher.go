@@ -6,18 +6,19 @@ import (
 )
 
 type officeUserListQueryBuilder interface {
-	FetchMany(model interface{}, filters []services.QueryFilter) error
+	FetchManyPage(model interface{}, filters []services.QueryFilter, orders []services.QueryOrder, pagination services.Pagination) (int, error)
 }
 
 type officeUserListFetcher struct {
 	builder officeUserListQueryBuilder
 }
 
-// FetchOfficeUserList uses the passed query builder to fetch a list of office users
-func (o *officeUserListFetcher) FetchOfficeUserList(filters []services.QueryFilter) (models.OfficeUsers, error) {
+// FetchOfficeUserList uses the passed query builder to fetch a page of office users, along with the total number
+// of office users matching the filters
+func (o *officeUserListFetcher) FetchOfficeUserList(filters []services.QueryFilter, orders []services.QueryOrder, pagination services.Pagination) (models.OfficeUsers, int, error) {
 	var officeUsers models.OfficeUsers
-	error := o.builder.FetchMany(&officeUsers, filters)
-	return officeUsers, error
+	count, err := o.builder.FetchManyPage(&officeUsers, filters, orders, pagination)
+	return officeUsers, count, err
 }
 
 // NewOfficeUserListFetcher returns an implementation of OfficeUserListFetcher
@@ -16,9 +16,9 @@ type testOfficeUserListQueryBuilder struct {
 	fakeFetchMany func(model interface{}) error
 }
 
-func (t *testOfficeUserListQueryBuilder) FetchMany(model interface{}, filters []services.QueryFilter) error {
-	m := t.fakeFetchMany(model)
-	return m
+func (t *testOfficeUserListQueryBuilder) FetchManyPage(model interface{}, filters []services.QueryFilter, orders []services.QueryOrder, pagination services.Pagination) (int, error) {
+	err := t.fakeFetchMany(model)
+	return reflect.ValueOf(model).Elem().Len(), err
 }
 
 func (suite *UserServiceSuite) TestFetchOfficeUserList() {
@@ -38,11 +38,15 @@ func (suite *UserServiceSuite) TestFetchOfficeUserList() {
 		filters := []services.QueryFilter{
 			query.NewQueryFilter("id", "=", id.String()),
 		}
+		orders := []services.QueryOrder{
+			query.NewQueryOrder("last_name", false),
+		}
 
-		officeUsers, err := fetcher.FetchOfficeUserList(filters)
+		officeUsers, count, err := fetcher.FetchOfficeUserList(filters, orders, query.NewPagination(nil, nil))
 
 		suite.NoError(err)
 		suite.Equal(id, officeUsers[0].ID)
+		suite.Equal(1, count)
 	})
 
 	suite.T().Run("if there is an error, we get it with no office users", func(t *testing.T) {
@@ -55,7 +59,7 @@ func (suite *UserServiceSuite) TestFetchOfficeUserList() {
 
 		fetcher := NewOfficeUserListFetcher(builder)
 
-		officeUsers, err := fetcher.FetchOfficeUserList([]services.QueryFilter{})
+		officeUsers, _, err := fetcher.FetchOfficeUserList([]services.QueryFilter{}, nil, nil)
 
 		suite.Error(err)
 		suite.Equal(err.Error(), "Fetch error")
@@ -0,0 +1,239 @@
+// Package statemachine declares the lifecycle of a model as states, the events that move it between them, the
+// guards that must allow each transition and the side effects of taking it. A Machine fires events against a
+// subject, returns the same error for every transition it refuses, and can describe itself as a transition table
+// or as a Graphviz graph for documentation.
+package statemachine
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidTransition is returned, wrapped with the machine, event and state, when an event can't be fired from
+// the subject's state or a guard refuses it
+var ErrInvalidTransition = errors.New("INVALID_TRANSITION")
+
+// State is a state of a lifecycle, such as a model's status
+type State string
+
+// Event is something that happens to a subject and may change its state
+type Event string
+
+// Guard must allow a transition before it is taken
+type Guard struct {
+	// Description says what the guard requires, such as "approve date is not set"
+	Description string
+	Allow       func(subject interface{}) bool
+}
+
+// Effect is a side effect of a transition, applied after the subject's state has changed. Args are the args the
+// event was fired with; an effect given args of the wrong type should return ErrInvalidTransition.
+type Effect struct {
+	// Description says what the effect does, such as "sets the actual pickup date"
+	Description string
+	Apply       func(subject interface{}, args interface{}) error
+}
+
+// Transition declares that Event moves a subject from any of From to To
+type Transition struct {
+	Event   Event
+	From    []State
+	To      State
+	Guards  []Guard
+	Effects []Effect
+}
+
+// Definition declares a lifecycle
+type Definition struct {
+	// Name is the name of the lifecycle, such as "Shipment"
+	Name string
+	// States are every state of the lifecycle, starting with the initial state
+	States      []State
+	Transitions []Transition
+	// GetState returns the subject's current state
+	GetState func(subject interface{}) State
+	// SetState changes the subject's state. It is only called by the machine.
+	SetState func(subject interface{}, to State)
+	// OnTransition, if set, is called after every transition has been taken and its effects applied
+	OnTransition func(subject interface{}, from State, to State)
+}
+
+type transitionKey struct {
+	event Event
+	from  State
+}
+
+// Machine fires events against subjects according to a Definition
+type Machine struct {
+	def         Definition
+	transitions map[transitionKey]*Transition
+}
+
+// New returns a Machine for def, or an error if def names an undeclared state or declares more than one
+// transition for the same event from the same state
+func New(def Definition) (*Machine, error) {
+	if def.Name == "" || def.GetState == nil || def.SetState == nil {
+		return nil, errors.New("state machine definitions need a name, GetState and SetState")
+	}
+	states := map[State]bool{}
+	for _, state := range def.States {
+		states[state] = true
+	}
+
+	m := &Machine{def: def, transitions: map[transitionKey]*Transition{}}
+	for i := range def.Transitions {
+		t := &def.Transitions[i]
+		if !states[t.To] {
+			return nil, errors.Errorf("%s: %s goes to undeclared state %s", def.Name, t.Event, t.To)
+		}
+		for _, from := range t.From {
+			if !states[from] {
+				return nil, errors.Errorf("%s: %s comes from undeclared state %s", def.Name, t.Event, from)
+			}
+			key := transitionKey{event: t.Event, from: from}
+			if _, ok := m.transitions[key]; ok {
+				return nil, errors.Errorf("%s: %s is declared more than once from %s", def.Name, t.Event, from)
+			}
+			m.transitions[key] = t
+		}
+	}
+	return m, nil
+}
+
+// MustNew is like New but panics if def is invalid. It is meant for declaring machines in package variables.
+func MustNew(def Definition) *Machine {
+	m, err := New(def)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Name returns the name of the machine's lifecycle
+func (m *Machine) Name() string {
+	return m.def.Name
+}
+
+// Can returns nil if event can be fired against subject, and otherwise the reason it can't
+func (m *Machine) Can(subject interface{}, event Event) error {
+	_, err := m.transition(subject, event)
+	return err
+}
+
+func (m *Machine) transition(subject interface{}, event Event) (*Transition, error) {
+	from := m.def.GetState(subject)
+	t, ok := m.transitions[transitionKey{event: event, from: from}]
+	if !ok {
+		return nil, errors.Wrapf(ErrInvalidTransition, "%s: cannot %s from %s", m.def.Name, event, from)
+	}
+	for _, guard := range t.Guards {
+		if !guard.Allow(subject) {
+			return nil, errors.Wrapf(ErrInvalidTransition, "%s: cannot %s from %s unless %s", m.def.Name, event, from, guard.Description)
+		}
+	}
+	return t, nil
+}
+
+// Fire takes the transition for event from subject's current state. The subject is left unchanged if the event
+// can't be fired. If an effect fails, the subject's state is restored, the remaining effects are not applied and
+// the effect's error is returned. Changes made by the effects applied before it are not undone, so a subject whose
+// event failed shouldn't be saved.
+func (m *Machine) Fire(subject interface{}, event Event, args interface{}) error {
+	t, err := m.transition(subject, event)
+	if err != nil {
+		return err
+	}
+
+	from := m.def.GetState(subject)
+	m.def.SetState(subject, t.To)
+	for _, effect := range t.Effects {
+		if err := effect.Apply(subject, args); err != nil {
+			m.def.SetState(subject, from)
+			return err
+		}
+	}
+	if m.def.OnTransition != nil {
+		m.def.OnTransition(subject, from, t.To)
+	}
+	return nil
+}
+
+// TableRow is one transition in a machine's transition table
+type TableRow struct {
+	Event   Event    `json:"event"`
+	From    State    `json:"from"`
+	To      State    `json:"to"`
+	Guards  []string `json:"guards,omitempty"`
+	Effects []string `json:"effects,omitempty"`
+}
+
+// Table describes a machine's lifecycle
+type Table struct {
+	Name        string     `json:"name"`
+	States      []State    `json:"states"`
+	Transitions []TableRow `json:"transitions"`
+}
+
+// Table returns the machine's transition table, with a row for each state an event can be fired from, ordered by
+// the declared order of states and then of transitions
+func (m *Machine) Table() Table {
+	order := map[State]int{}
+	for i, state := range m.def.States {
+		order[state] = i
+	}
+
+	table := Table{Name: m.def.Name, States: m.def.States, Transitions: []TableRow{}}
+	for _, t := range m.def.Transitions {
+		var guards, effects []string
+		for _, guard := range t.Guards {
+			guards = append(guards, guard.Description)
+		}
+		for _, effect := range t.Effects {
+			effects = append(effects, effect.Description)
+		}
+		for _, from := range t.From {
+			table.Transitions = append(table.Transitions, TableRow{
+				Event:   t.Event,
+				From:    from,
+				To:      t.To,
+				Guards:  guards,
+				Effects: effects,
+			})
+		}
+	}
+	sort.SliceStable(table.Transitions, func(i, j int) bool {
+		return order[table.Transitions[i].From] < order[table.Transitions[j].From]
+	})
+	return table
+}
+
+// WriteDOT writes the machine's lifecycle as a Graphviz digraph, with an edge for each transition labelled with
+// its event and any guards
+func (m *Machine) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", m.def.Name)
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	for i, state := range m.def.States {
+		if i == 0 {
+			fmt.Fprintf(&b, "  %q [peripheries=2];\n", state)
+			continue
+		}
+		fmt.Fprintf(&b, "  %q;\n", state)
+	}
+	for _, row := range m.Table().Transitions {
+		label := string(row.Event)
+		if len(row.Guards) > 0 {
+			label += " [" + strings.Join(row.Guards, ", ") + "]"
+		}
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", row.From, row.To, label)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
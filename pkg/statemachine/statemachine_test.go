@@ -0,0 +1,195 @@
+package statemachine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type door struct {
+	state   State
+	locked  bool
+	visitor string
+	history []string
+}
+
+func newDoorMachine(t *testing.T) *Machine {
+	m, err := New(Definition{
+		Name:   "Door",
+		States: []State{"CLOSED", "OPEN"},
+		Transitions: []Transition{
+			{
+				Event: "Open",
+				From:  []State{"CLOSED"},
+				To:    "OPEN",
+				Guards: []Guard{{
+					Description: "door is unlocked",
+					Allow:       func(subject interface{}) bool { return !subject.(*door).locked },
+				}},
+				Effects: []Effect{{
+					Description: "lets the visitor in",
+					Apply: func(subject interface{}, args interface{}) error {
+						visitor, ok := args.(string)
+						if !ok {
+							return errors.Wrap(ErrInvalidTransition, "expected a visitor")
+						}
+						subject.(*door).visitor = visitor
+						return nil
+					},
+				}},
+			},
+			{
+				Event: "Close",
+				From:  []State{"OPEN", "CLOSED"},
+				To:    "CLOSED",
+			},
+		},
+		GetState: func(subject interface{}) State { return subject.(*door).state },
+		SetState: func(subject interface{}, to State) { subject.(*door).state = to },
+		OnTransition: func(subject interface{}, from State, to State) {
+			d := subject.(*door)
+			d.history = append(d.history, string(from)+"->"+string(to))
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestFire(t *testing.T) {
+	m := newDoorMachine(t)
+	d := &door{state: "CLOSED"}
+
+	if err := m.Fire(d, "Open", "Bob"); err != nil {
+		t.Fatal(err)
+	}
+	if d.state != "OPEN" || d.visitor != "Bob" {
+		t.Errorf("got state %s and visitor %q", d.state, d.visitor)
+	}
+
+	err := m.Fire(d, "Open", "Alice")
+	if errors.Cause(err) != ErrInvalidTransition {
+		t.Fatalf("got %v, want ErrInvalidTransition", err)
+	}
+	if got, want := err.Error(), "Door: cannot Open from OPEN: INVALID_TRANSITION"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if d.visitor != "Bob" {
+		t.Errorf("refused transition changed the door")
+	}
+
+	if err := m.Fire(d, "Close", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(d.history, " "), "CLOSED->OPEN OPEN->CLOSED"; got != want {
+		t.Errorf("got history %q, want %q", got, want)
+	}
+}
+
+func TestFireRestoresStateWhenEffectFails(t *testing.T) {
+	m := newDoorMachine(t)
+	d := &door{state: "CLOSED"}
+
+	err := m.Fire(d, "Open", 42)
+	if errors.Cause(err) != ErrInvalidTransition {
+		t.Fatalf("got %v, want ErrInvalidTransition", err)
+	}
+	if d.state != "CLOSED" || len(d.history) != 0 {
+		t.Errorf("failed transition left the door %s with history %v", d.state, d.history)
+	}
+}
+
+func TestGuard(t *testing.T) {
+	m := newDoorMachine(t)
+	d := &door{state: "CLOSED", locked: true}
+
+	err := m.Can(d, "Open")
+	if errors.Cause(err) != ErrInvalidTransition {
+		t.Fatalf("got %v, want ErrInvalidTransition", err)
+	}
+	if got, want := err.Error(), "Door: cannot Open from CLOSED unless door is unlocked: INVALID_TRANSITION"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if err := m.Fire(d, "Open", "Bob"); err == nil || d.state != "CLOSED" || len(d.history) != 0 {
+		t.Errorf("locked door was opened")
+	}
+
+	d.locked = false
+	if err := m.Can(d, "Open"); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+func TestNewRejectsInvalidDefinitions(t *testing.T) {
+	getState := func(subject interface{}) State { return "" }
+	setState := func(subject interface{}, to State) {}
+
+	var definitionTests = []struct {
+		name        string
+		transitions []Transition
+		want        string
+	}{
+		{"undeclared to", []Transition{{Event: "Open", From: []State{"CLOSED"}, To: "AJAR"}}, "Door: Open goes to undeclared state AJAR"},
+		{"undeclared from", []Transition{{Event: "Open", From: []State{"AJAR"}, To: "OPEN"}}, "Door: Open comes from undeclared state AJAR"},
+		{"duplicate", []Transition{
+			{Event: "Open", From: []State{"CLOSED"}, To: "OPEN"},
+			{Event: "Open", From: []State{"CLOSED"}, To: "CLOSED"},
+		}, "Door: Open is declared more than once from CLOSED"},
+	}
+	for _, tt := range definitionTests {
+		_, err := New(Definition{
+			Name:        "Door",
+			States:      []State{"CLOSED", "OPEN"},
+			Transitions: tt.transitions,
+			GetState:    getState,
+			SetState:    setState,
+		})
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	table := newDoorMachine(t).Table()
+	if table.Name != "Door" || len(table.States) != 2 {
+		t.Errorf("got %+v", table)
+	}
+
+	// Rows are ordered by the state they come from
+	want := []TableRow{
+		{Event: "Open", From: "CLOSED", To: "OPEN", Guards: []string{"door is unlocked"}, Effects: []string{"lets the visitor in"}},
+		{Event: "Close", From: "CLOSED", To: "CLOSED"},
+		{Event: "Close", From: "OPEN", To: "CLOSED"},
+	}
+	if len(table.Transitions) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(table.Transitions), len(want))
+	}
+	for i, row := range table.Transitions {
+		if row.Event != want[i].Event || row.From != want[i].From || row.To != want[i].To ||
+			strings.Join(row.Guards, ",") != strings.Join(want[i].Guards, ",") ||
+			strings.Join(row.Effects, ",") != strings.Join(want[i].Effects, ",") {
+			t.Errorf("row %d: got %+v, want %+v", i, row, want[i])
+		}
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var b bytes.Buffer
+	if err := newDoorMachine(t).WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`digraph "Door" {`,
+		`"CLOSED" [peripheries=2];`,
+		`"CLOSED" -> "OPEN" [label="Open [door is unlocked]"];`,
+		`"OPEN" -> "CLOSED" [label="Close"];`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("missing %q in\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+)
+
+const (
+	// encryptedRefPrefix starts the object stored at a key, which points at the blob holding its content
+	encryptedRefPrefix = "milmove-encrypted-ref/v1\n"
+	// encryptedBlobPrefix starts a blob, and is followed by a JSON header line and the encrypted content
+	encryptedBlobPrefix = "milmove-encrypted-blob/v1\n"
+	// contentDir is where blobs are stored, named by the SHA-256 hash of their unencrypted content
+	contentDir = "content"
+	// encryptedURLTTL is how long a URL from Encrypted.PresignedURL works for
+	encryptedURLTTL = 15 * time.Minute
+)
+
+type encryptedRef struct {
+	ContentHash string `json:"content_hash"`
+	KeyID       string `json:"key_id"`
+}
+
+type encryptedBlobHeader struct {
+	KeyID        string `json:"key_id"`
+	EncryptedKey []byte `json:"encrypted_key"`
+}
+
+// Encrypted wraps another FileStorer, encrypting files before they are stored and decrypting them when they are
+// fetched. Each file is encrypted with its own data key, which is stored alongside it encrypted under a master key
+// held by a KeyManager.
+//
+// Files are stored once per unique content: the object stored at a key only refers to a blob named by the hash of
+// the file's content, so uploading the same file again stores nothing new.
+//
+// Files stored before encryption was enabled are returned from Fetch as they are.
+type Encrypted struct {
+	storer        FileStorer
+	keys          KeyManager
+	webRoot       string
+	urlSigningKey []byte
+	logger        Logger
+}
+
+// NewEncrypted creates an Encrypted storer that stores files in storer. URLs from PresignedURL are served under
+// webRoot by NewEncryptedHandler, and are signed with urlSigningKey.
+func NewEncrypted(storer FileStorer, keys KeyManager, webRoot string, urlSigningKey []byte, logger Logger) *Encrypted {
+	return &Encrypted{
+		storer:        storer,
+		keys:          keys,
+		webRoot:       "/" + strings.Trim(webRoot, "/"),
+		urlSigningKey: urlSigningKey,
+		logger:        logger,
+	}
+}
+
+// contentKey is the key of the blob holding content with the given hash
+func contentKey(contentHash string) string {
+	return path.Join(contentDir, contentHash)
+}
+
+// Store encrypts the content from an io.ReadSeeker and stores it at the specified key. The checksum is of the
+// unencrypted content; the wrapped storer is given checksums of the encrypted objects it stores.
+func (e *Encrypted) Store(key string, data io.ReadSeeker, checksum string) (*StoreResult, error) {
+	if key == "" {
+		return nil, errors.New("A valid StorageKey must be set before data can be uploaded")
+	}
+
+	plaintext, err := ioutil.ReadAll(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read file")
+	}
+	sum := sha256.Sum256(plaintext)
+	contentHash := hex.EncodeToString(sum[:])
+
+	keyID, err := e.storeContent(contentHash, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := json.Marshal(encryptedRef{ContentHash: contentHash, KeyID: keyID})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode reference")
+	}
+	if err := e.storeBytes(key, append([]byte(encryptedRefPrefix), ref...)); err != nil {
+		return nil, errors.Wrap(err, "could not store reference")
+	}
+
+	return &StoreResult{KeyID: keyID, ContentHash: contentHash}, nil
+}
+
+// storeContent encrypts and stores plaintext unless a blob with the same content already exists, and returns the
+// ID of the master key protecting the blob
+func (e *Encrypted) storeContent(contentHash string, plaintext []byte) (string, error) {
+	if header, _, err := e.fetchBlob(contentHash); err == nil {
+		e.logger.Debug("reusing stored content", zap.String("content_hash", contentHash))
+		return header.KeyID, nil
+	}
+
+	dataKey, encryptedKey, keyID, err := e.keys.GenerateDataKey()
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := sealAESGCM(dataKey, plaintext)
+	if err != nil {
+		return "", errors.Wrap(err, "could not encrypt file")
+	}
+
+	header, err := json.Marshal(encryptedBlobHeader{KeyID: keyID, EncryptedKey: encryptedKey})
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode blob header")
+	}
+
+	var blob bytes.Buffer
+	blob.WriteString(encryptedBlobPrefix)
+	blob.Write(header)
+	blob.WriteString("\n")
+	blob.Write(ciphertext)
+
+	if err := e.storeBytes(contentKey(contentHash), blob.Bytes()); err != nil {
+		return "", errors.Wrap(err, "could not store encrypted file")
+	}
+	return keyID, nil
+}
+
+func (e *Encrypted) storeBytes(key string, data []byte) error {
+	reader := bytes.NewReader(data)
+	checksum, err := ComputeChecksum(reader)
+	if err != nil {
+		return err
+	}
+	_, err = e.storer.Store(key, reader, checksum)
+	return err
+}
+
+func (e *Encrypted) fetchBytes(key string) ([]byte, error) {
+	f, err := e.storer.Fetch(key)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+// fetchBlob returns the header and encrypted content of the blob with the given content hash
+func (e *Encrypted) fetchBlob(contentHash string) (encryptedBlobHeader, []byte, error) {
+	var header encryptedBlobHeader
+
+	blob, err := e.fetchBytes(contentKey(contentHash))
+	if err != nil {
+		return header, nil, err
+	}
+	if !bytes.HasPrefix(blob, []byte(encryptedBlobPrefix)) {
+		return header, nil, errors.Errorf("content %s is not an encrypted blob", contentHash)
+	}
+	blob = blob[len(encryptedBlobPrefix):]
+
+	end := bytes.IndexByte(blob, '\n')
+	if end < 0 {
+		return header, nil, errors.Errorf("content %s is missing its header", contentHash)
+	}
+	if err := json.Unmarshal(blob[:end], &header); err != nil {
+		return header, nil, errors.Wrapf(err, "could not decode header for content %s", contentHash)
+	}
+	return header, blob[end+1:], nil
+}
+
+// Fetch retrieves and decrypts the file stored at the specified key
+func (e *Encrypted) Fetch(key string) (io.ReadCloser, error) {
+	stored, err := e.fetchBytes(key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(stored, []byte(encryptedRefPrefix)) {
+		// Stored before encryption was enabled
+		return ioutil.NopCloser(bytes.NewReader(stored)), nil
+	}
+
+	var ref encryptedRef
+	if err := json.Unmarshal(stored[len(encryptedRefPrefix):], &ref); err != nil {
+		return nil, errors.Wrap(err, "could not decode reference")
+	}
+
+	header, ciphertext, err := e.fetchBlob(ref.ContentHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch encrypted file")
+	}
+	dataKey, err := e.keys.DecryptDataKey(header.KeyID, header.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := openAESGCM(dataKey, ciphertext)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt file")
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(plaintext)), nil
+}
+
+// Delete deletes the file at the specified key. Its content is kept, as other keys may refer to it; use
+// DeleteContent once nothing does.
+func (e *Encrypted) Delete(key string) error {
+	return e.storer.Delete(key)
+}
+
+// DeleteContent deletes the stored content with the given hash
+func (e *Encrypted) DeleteContent(contentHash string) error {
+	return e.storer.Delete(contentKey(contentHash))
+}
+
+// urlSignature signs the parts of a URL that grant access to a file
+func (e *Encrypted) urlSignature(key, contentType, expires string) string {
+	mac := hmac.New(sha256.New, e.urlSigningKey)
+	mac.Write([]byte(key + "\n" + contentType + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// PresignedURL returns a URL that provides access to a file for 15 minutes. Files are decrypted by the app, so
+// the URL is served by NewEncryptedHandler rather than the wrapped storer.
+func (e *Encrypted) PresignedURL(key, contentType string) (string, error) {
+	expires := strconv.FormatInt(time.Now().Add(encryptedURLTTL).Unix(), 10)
+
+	values := url.Values{}
+	values.Add("contentType", contentType)
+	values.Add("expires", expires)
+	values.Add("signature", e.urlSignature(key, contentType, expires))
+	return e.webRoot + "/" + key + "?" + values.Encode(), nil
+}
+
+// FileSystem returns the underlying afero filesystem
+func (e *Encrypted) FileSystem() *afero.Afero {
+	return e.storer.FileSystem()
+}
+
+// TempFileSystem returns the temporary afero filesystem
+func (e *Encrypted) TempFileSystem() *afero.Afero {
+	return e.storer.TempFileSystem()
+}
+
+// NewEncryptedHandler returns a Handler that serves decrypted files at the URLs returned by storer.PresignedURL
+func NewEncryptedHandler(storer *Encrypted) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(path.Clean(r.URL.Path), storer.webRoot+"/")
+		query := r.URL.Query()
+		contentType := query.Get("contentType")
+		expires := query.Get("expires")
+
+		expiresAt, err := strconv.ParseInt(expires, 10, 64)
+		signature := storer.urlSignature(key, contentType, expires)
+		if err != nil || !hmac.Equal([]byte(signature), []byte(query.Get("signature"))) || time.Now().Unix() > expiresAt {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		f, err := storer.Fetch(key)
+		if err != nil {
+			storer.logger.Info("could not fetch encrypted file", zap.String("key", key), zap.Error(err))
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+
+		if contentType != "" {
+			w.Header().Add("Content-Type", contentType)
+		}
+		if _, err := io.Copy(w, f); err != nil {
+			storer.logger.Error("could not write encrypted file", zap.String("key", key), zap.Error(err))
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+const testMasterKeys = `
+# current key
+000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f
+# previous key
+1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100
+`
+
+func newTestEncrypted(t *testing.T) (*Encrypted, *Memory) {
+	keys, err := ParseLocalKeyManager(strings.NewReader(testMasterKeys))
+	if err != nil {
+		t.Fatalf("could not parse master keys: %s", err)
+	}
+	memory := NewMemory(NewMemoryParams("/tmp", "storage", zap.NewNop()))
+	return NewEncrypted(memory, keys, "storage", []byte("test signing key"), zap.NewNop()), memory
+}
+
+func storeString(t *testing.T, storer FileStorer, key string, content string) *StoreResult {
+	reader := strings.NewReader(content)
+	checksum, err := ComputeChecksum(reader)
+	if err != nil {
+		t.Fatalf("could not compute checksum: %s", err)
+	}
+	result, err := storer.Store(key, reader, checksum)
+	if err != nil {
+		t.Fatalf("could not store %s: %s", key, err)
+	}
+	return result
+}
+
+func fetchString(t *testing.T, storer FileStorer, key string) string {
+	f, err := storer.Fetch(key)
+	if err != nil {
+		t.Fatalf("could not fetch %s: %s", key, err)
+	}
+	defer f.Close()
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("could not read %s: %s", key, err)
+	}
+	return string(content)
+}
+
+func TestLocalKeyManager(t *testing.T) {
+	keys, err := ParseLocalKeyManager(strings.NewReader(testMasterKeys))
+	if err != nil {
+		t.Fatalf("could not parse master keys: %s", err)
+	}
+
+	plaintext, encrypted, keyID, err := keys.GenerateDataKey()
+	if err != nil {
+		t.Fatalf("could not generate data key: %s", err)
+	}
+	if len(plaintext) != dataKeySize || bytes.Contains(encrypted, plaintext) {
+		t.Errorf("data key was not encrypted")
+	}
+
+	decrypted, err := keys.DecryptDataKey(keyID, encrypted)
+	if err != nil {
+		t.Fatalf("could not decrypt data key: %s", err)
+	}
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Errorf("decrypted data key does not match")
+	}
+
+	if _, err := keys.DecryptDataKey("local:0000000000000000", encrypted); err == nil {
+		t.Errorf("expected an error decrypting with an unknown key")
+	}
+
+	for _, invalid := range []string{"", "not hex", "0001"} {
+		if _, err := ParseLocalKeyManager(strings.NewReader(invalid)); err == nil {
+			t.Errorf("expected an error parsing master keys %q", invalid)
+		}
+	}
+}
+
+func TestEncryptedStoreAndFetch(t *testing.T) {
+	encrypted, memory := newTestEncrypted(t)
+
+	content := "%PDF-1.4 orders"
+	result := storeString(t, encrypted, "user/1/uploads/1", content)
+	if result.ContentHash == "" || !strings.HasPrefix(result.KeyID, "local:") {
+		t.Errorf("expected a content hash and key ID, got %+v", result)
+	}
+
+	// Nothing is stored unencrypted
+	raw := fetchString(t, memory, contentKey(result.ContentHash))
+	if strings.Contains(raw, content) {
+		t.Errorf("content was stored unencrypted")
+	}
+
+	if got := fetchString(t, encrypted, "user/1/uploads/1"); got != content {
+		t.Errorf("wrong content: expected %q, got %q", content, got)
+	}
+
+	// Files stored before encryption was enabled are still readable
+	storeString(t, memory, "user/1/uploads/0", "plain")
+	if got := fetchString(t, encrypted, "user/1/uploads/0"); got != "plain" {
+		t.Errorf("wrong content: expected %q, got %q", "plain", got)
+	}
+}
+
+func TestEncryptedDeduplicatesContent(t *testing.T) {
+	encrypted, memory := newTestEncrypted(t)
+
+	first := storeString(t, encrypted, "user/1/uploads/1", "same orders")
+	blob := fetchString(t, memory, contentKey(first.ContentHash))
+	second := storeString(t, encrypted, "user/2/uploads/2", "same orders")
+	other := storeString(t, encrypted, "user/2/uploads/3", "other orders")
+
+	if first.ContentHash != second.ContentHash || first.ContentHash == other.ContentHash {
+		t.Errorf("expected matching content to share a hash")
+	}
+	if fetchString(t, memory, contentKey(first.ContentHash)) != blob {
+		t.Errorf("expected matching content to be stored once")
+	}
+
+	// Deleting one file leaves the shared content for the other
+	if err := encrypted.Delete("user/1/uploads/1"); err != nil {
+		t.Fatalf("could not delete: %s", err)
+	}
+	if got := fetchString(t, encrypted, "user/2/uploads/2"); got != "same orders" {
+		t.Errorf("wrong content: expected %q, got %q", "same orders", got)
+	}
+
+	if err := encrypted.DeleteContent(first.ContentHash); err != nil {
+		t.Fatalf("could not delete content: %s", err)
+	}
+	if _, err := encrypted.Fetch("user/2/uploads/2"); err == nil {
+		t.Errorf("expected an error fetching deleted content")
+	}
+}
+
+func TestEncryptedHandler(t *testing.T) {
+	encrypted, _ := newTestEncrypted(t)
+	storeString(t, encrypted, "user/1/uploads/1", "orders")
+
+	presignedURL, err := encrypted.PresignedURL("user/1/uploads/1", "application/pdf")
+	if err != nil {
+		t.Fatalf("could not get presigned url: %s", err)
+	}
+	if !strings.HasPrefix(presignedURL, "/storage/user/1/uploads/1?") {
+		t.Errorf("wrong presigned url: %s", presignedURL)
+	}
+
+	handler := NewEncryptedHandler(encrypted)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", presignedURL, nil))
+	if rr.Code != http.StatusOK || rr.Body.String() != "orders" {
+		t.Errorf("expected the decrypted file, got %d %q", rr.Code, rr.Body.String())
+	}
+	if rr.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("wrong content type %s", rr.Header().Get("Content-Type"))
+	}
+
+	// A URL for one file can't be used for another
+	u, _ := url.Parse(presignedURL)
+	u.Path = "/storage/user/2/uploads/2"
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", u.String(), nil))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	// Expired URLs are rejected even with a valid signature
+	values := url.Values{}
+	values.Add("contentType", "application/pdf")
+	values.Add("expires", "1")
+	values.Add("signature", encrypted.urlSignature("user/1/uploads/1", "application/pdf", "1"))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/storage/user/1/uploads/1?"+values.Encode(), nil))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/pkg/errors"
+)
+
+// dataKeySize is the size in bytes of the AES-256 keys used to encrypt stored files
+const dataKeySize = 32
+
+// KeyManager creates the data keys used to encrypt stored files, and decrypts them again later. Data keys are
+// only ever stored encrypted under a master key that the KeyManager holds.
+//go:generate mockery -name KeyManager
+type KeyManager interface {
+	// GenerateDataKey returns a new data key, the same key encrypted under the current master key, and the ID of
+	// that master key
+	GenerateDataKey() (plaintext []byte, encrypted []byte, keyID string, err error)
+	// DecryptDataKey decrypts a data key that was encrypted under the master key with the given ID
+	DecryptDataKey(keyID string, encrypted []byte) ([]byte, error)
+}
+
+// LocalKeyManager encrypts data keys with master keys read from a local file. It is intended for use in
+// development and testing; deployed environments should use KMS.
+type LocalKeyManager struct {
+	currentKeyID string
+	masterKeys   map[string][]byte
+}
+
+// NewLocalKeyManager reads hex-encoded 256-bit master keys from keyFile, one per line. The first key encrypts new
+// data keys, and every key in the file can decrypt existing ones, so keys can be rotated by adding a new first line.
+func NewLocalKeyManager(keyFile string) (*LocalKeyManager, error) {
+	contents, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read master key file")
+	}
+	return ParseLocalKeyManager(bytes.NewReader(contents))
+}
+
+// ParseLocalKeyManager reads hex-encoded 256-bit master keys, one per line, in the format used by NewLocalKeyManager
+func ParseLocalKeyManager(r io.Reader) (*LocalKeyManager, error) {
+	manager := &LocalKeyManager{masterKeys: map[string][]byte{}}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		masterKey, err := hex.DecodeString(line)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode master key")
+		}
+		if len(masterKey) != dataKeySize {
+			return nil, errors.Errorf("master keys must be %d bytes, got %d", dataKeySize, len(masterKey))
+		}
+
+		keyID := localKeyID(masterKey)
+		if manager.currentKeyID == "" {
+			manager.currentKeyID = keyID
+		}
+		manager.masterKeys[keyID] = masterKey
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read master keys")
+	}
+	if manager.currentKeyID == "" {
+		return nil, errors.New("no master keys found")
+	}
+
+	return manager, nil
+}
+
+// localKeyID identifies a master key without revealing it
+func localKeyID(masterKey []byte) string {
+	sum := sha256.Sum256(masterKey)
+	return "local:" + hex.EncodeToString(sum[:8])
+}
+
+// GenerateDataKey returns a new data key encrypted under the first master key
+func (m *LocalKeyManager) GenerateDataKey() ([]byte, []byte, string, error) {
+	plaintext := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+		return nil, nil, "", errors.Wrap(err, "could not generate data key")
+	}
+
+	encrypted, err := sealAESGCM(m.masterKeys[m.currentKeyID], plaintext)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "could not encrypt data key")
+	}
+	return plaintext, encrypted, m.currentKeyID, nil
+}
+
+// DecryptDataKey decrypts a data key with the master key it was encrypted under
+func (m *LocalKeyManager) DecryptDataKey(keyID string, encrypted []byte) ([]byte, error) {
+	masterKey, ok := m.masterKeys[keyID]
+	if !ok {
+		return nil, errors.Errorf("unknown master key %s", keyID)
+	}
+
+	plaintext, err := openAESGCM(masterKey, encrypted)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt data key")
+	}
+	return plaintext, nil
+}
+
+// KMSKeyManager creates and decrypts data keys with AWS KMS
+type KMSKeyManager struct {
+	client kmsiface.KMSAPI
+	keyID  string
+}
+
+// NewKMSKeyManager creates a KMSKeyManager that encrypts new data keys under the KMS key with the given ID, ARN
+// or alias
+func NewKMSKeyManager(client kmsiface.KMSAPI, keyID string) *KMSKeyManager {
+	return &KMSKeyManager{
+		client: client,
+		keyID:  keyID,
+	}
+}
+
+// GenerateDataKey asks KMS for a new data key
+func (m *KMSKeyManager) GenerateDataKey() ([]byte, []byte, string, error) {
+	output, err := m.client.GenerateDataKey(&kms.GenerateDataKeyInput{
+		KeyId:   aws.String(m.keyID),
+		KeySpec: aws.String(kms.DataKeySpecAes256),
+	})
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "could not generate data key with KMS")
+	}
+	return output.Plaintext, output.CiphertextBlob, aws.StringValue(output.KeyId), nil
+}
+
+// DecryptDataKey asks KMS to decrypt a data key. KMS records which key encrypted the data key in the encrypted
+// key itself, so keyID is only used to make sure it hasn't been swapped.
+func (m *KMSKeyManager) DecryptDataKey(keyID string, encrypted []byte) ([]byte, error) {
+	output, err := m.client.Decrypt(&kms.DecryptInput{
+		CiphertextBlob: encrypted,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt data key with KMS")
+	}
+	if aws.StringValue(output.KeyId) != keyID {
+		return nil, errors.Errorf("data key was encrypted under %s, expected %s", aws.StringValue(output.KeyId), keyID)
+	}
+	return output.Plaintext, nil
+}
+
+// sealAESGCM encrypts plaintext with AES-GCM, returning the nonce followed by the ciphertext
+func sealAESGCM(key []byte, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "could not generate nonce")
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// openAESGCM decrypts the output of sealAESGCM
+func openAESGCM(key []byte, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted data is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create cipher")
+	}
+	return gcm, nil
+}
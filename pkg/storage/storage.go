@@ -8,11 +8,13 @@ import (
 	*/
 	"crypto/md5"
 	"encoding/base64"
+	"encoding/hex"
 	"io"
 	"net/http"
 	"path"
 
 	awssession "github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"github.com/spf13/viper"
@@ -22,7 +24,12 @@ import (
 )
 
 // StoreResult represents the result of a call to Store().
-type StoreResult struct{}
+type StoreResult struct {
+	// KeyID identifies the master key that protects the stored file, if it was encrypted
+	KeyID string
+	// ContentHash is the hex-encoded SHA-256 hash of the file, if the storer shares content between files
+	ContentHash string
+}
 
 // FileStorer is the set of methods needed to store and retrieve objects.
 //go:generate mockery -name FileStorer
@@ -113,5 +120,30 @@ func InitStorage(v *viper.Viper, sess *awssession.Session, logger Logger) FileSt
 		fsParams := NewFilesystemParams(localStorageRoot, localStorageWebRoot, logger)
 		storer = NewFilesystem(fsParams)
 	}
+
+	var keys KeyManager
+	storageEncryption := v.GetString(cli.StorageEncryptionFlag)
+	if storageEncryption == "local" {
+		keyFile := v.GetString(cli.StorageEncryptionKeyFileFlag)
+		logger.Info("Using local storage encryption", zap.String("key-file", keyFile))
+		localKeys, err := NewLocalKeyManager(keyFile)
+		if err != nil {
+			logger.Fatal("could not load storage encryption keys", zap.Error(err))
+		}
+		keys = localKeys
+	} else if storageEncryption == "kms" {
+		kmsKeyID := v.GetString(cli.StorageEncryptionKMSKeyIDFlag)
+		logger.Info("Using kms storage encryption", zap.String("key-id", kmsKeyID))
+		keys = NewKMSKeyManager(kms.New(sess), kmsKeyID)
+	}
+
+	if keys != nil {
+		urlSigningKey, err := hex.DecodeString(v.GetString(cli.StorageURLSigningKeyFlag))
+		if err != nil {
+			logger.Fatal("invalid storage url signing key", zap.Error(err))
+		}
+		storer = NewEncrypted(storer, keys, localStorageWebRoot, urlSigningKey, logger)
+	}
+
 	return storer
 }
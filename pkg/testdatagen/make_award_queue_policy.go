@@ -0,0 +1,41 @@
+package testdatagen
+
+import (
+	"log"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/pop/slices"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// MakeAwardQueuePolicy creates a single AwardQueuePolicy covering the first performance period
+func MakeAwardQueuePolicy(db *pop.Connection, assertions Assertions) models.AwardQueuePolicy {
+	version := assertions.AwardQueuePolicy.Version
+	if version == 0 {
+		var err error
+		version, err = models.NextAwardQueuePolicyVersion(db)
+		if err != nil {
+			log.Panic(err)
+		}
+	}
+
+	policy := models.AwardQueuePolicy{
+		Version:                 version,
+		EffectiveStartDate:      PerformancePeriodStart,
+		EffectiveEndDate:        PerformancePeriodEnd,
+		MinimumPerformanceScore: 0,
+		OffersPerQualityBand:    slices.Int{5, 3, 2, 1},
+	}
+
+	mergeModels(&policy, assertions.AwardQueuePolicy)
+
+	mustCreate(db, &policy)
+
+	return policy
+}
+
+// MakeDefaultAwardQueuePolicy returns an AwardQueuePolicy with default values
+func MakeDefaultAwardQueuePolicy(db *pop.Connection) models.AwardQueuePolicy {
+	return MakeAwardQueuePolicy(db, Assertions{})
+}
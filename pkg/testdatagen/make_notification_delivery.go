@@ -0,0 +1,43 @@
+package testdatagen
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// MakeNotificationDelivery creates a single pending NotificationDelivery for a move
+func MakeNotificationDelivery(db *pop.Connection, assertions Assertions) models.NotificationDelivery {
+	moveID := assertions.NotificationDelivery.MoveID
+	if moveID == nil {
+		move := assertions.Move
+		if isZeroUUID(move.ID) {
+			move = MakeMove(db, assertions)
+		}
+		moveID = &move.ID
+	}
+
+	delivery := models.NotificationDelivery{
+		NotificationType: "move_submitted",
+		MoveID:           moveID,
+		RecipientEmail:   "leo_spaceman_sm@example.com",
+		Subject:          "[MilMove] You’ve submitted your move details",
+		HTMLBody:         "Your move has been submitted to your local transportation office for review.",
+		TextBody:         "Your move has been submitted to your local transportation office for review.",
+		Status:           models.NotificationDeliveryStatusPENDING,
+		NextAttemptAt:    time.Now(),
+	}
+
+	mergeModels(&delivery, assertions.NotificationDelivery)
+
+	mustCreate(db, &delivery)
+
+	return delivery
+}
+
+// MakeDefaultNotificationDelivery returns a NotificationDelivery with default values
+func MakeDefaultNotificationDelivery(db *pop.Connection) models.NotificationDelivery {
+	return MakeNotificationDelivery(db, Assertions{})
+}
@@ -537,7 +537,7 @@ func createTariffDataForRateEngine(db *pop.Connection, shipment models.Shipment)
 	code210C := models.Tariff400ngItem{
 		Code:                "210C",
 		Item:                "SIT Pup/Del Over 50 Miles",
-		DiscountType:        models.Tariff400ngItemDiscountTypeHHG,
+		DiscountType:        models.Tariff400ngItemDiscountTypeHHGLINEHAUL50,
 		AllowedLocation:     models.Tariff400ngItemAllowedLocationEITHER,
 		MeasurementUnit1:    models.Tariff400ngItemMeasurementUnitWEIGHT,
 		MeasurementUnit2:    models.Tariff400ngItemMeasurementUnitNONE,
@@ -546,4 +546,39 @@ func createTariffDataForRateEngine(db *pop.Connection, shipment models.Shipment)
 	}
 	mustSave(db, &code210C)
 
+	code210F := models.Tariff400ngItem{
+		Code:                "210F",
+		Item:                "SIT Pup/Del Over 50 Miles - Alaska",
+		DiscountType:        models.Tariff400ngItemDiscountTypeHHGLINEHAUL50,
+		AllowedLocation:     models.Tariff400ngItemAllowedLocationEITHER,
+		MeasurementUnit1:    models.Tariff400ngItemMeasurementUnitFLATRATE,
+		MeasurementUnit2:    models.Tariff400ngItemMeasurementUnitNONE,
+		RateRefCode:         models.Tariff400ngItemRateRefCodeNONE,
+		RequiresPreApproval: false,
+	}
+	mustSave(db, &code210F)
+
+	code185A := models.Tariff400ngItem{
+		Code:                "185A",
+		Item:                "SIT First Day & Whse",
+		DiscountType:        models.Tariff400ngItemDiscountTypeSIT,
+		AllowedLocation:     models.Tariff400ngItemAllowedLocationEITHER,
+		MeasurementUnit1:    models.Tariff400ngItemMeasurementUnitWEIGHT,
+		MeasurementUnit2:    models.Tariff400ngItemMeasurementUnitNONE,
+		RateRefCode:         models.Tariff400ngItemRateRefCodeNONE,
+		RequiresPreApproval: false,
+	}
+	mustSave(db, &code185A)
+
+	code185B := models.Tariff400ngItem{
+		Code:                "185B",
+		Item:                "SIT Addtl Day",
+		DiscountType:        models.Tariff400ngItemDiscountTypeSIT,
+		AllowedLocation:     models.Tariff400ngItemAllowedLocationEITHER,
+		MeasurementUnit1:    models.Tariff400ngItemMeasurementUnitDAYS,
+		MeasurementUnit2:    models.Tariff400ngItemMeasurementUnitWEIGHT,
+		RateRefCode:         models.Tariff400ngItemRateRefCodeNONE,
+		RequiresPreApproval: false,
+	}
+	mustSave(db, &code185B)
 }
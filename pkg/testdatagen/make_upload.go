@@ -27,7 +27,7 @@ func MakeUpload(db *pop.Connection, assertions Assertions) models.Upload {
 	// Users can either assert an Uploader (and a real file is used), or can optionally assert fields
 	var upload *models.Upload
 	if assertions.Uploader != nil {
-		// If an Uploader is passed in, Upload assertions are ignored
+		// If an Uploader is passed in, Upload assertions other than Status are ignored
 		var verrs *validate.Errors
 		var err error
 		file := fixture("test.pdf")
@@ -35,6 +35,16 @@ func MakeUpload(db *pop.Connection, assertions Assertions) models.Upload {
 		if verrs.HasAny() || err != nil {
 			log.Panic(fmt.Errorf("Errors encountered saving upload %v, %v", verrs, err))
 		}
+
+		// Test files are clean unless a test asks for a different status
+		status := assertions.Upload.Status
+		if status == "" {
+			status = models.UploadStatusCLEAN
+		}
+		if upload.Status != status {
+			upload.Status = status
+			mustSave(db, upload)
+		}
 	} else {
 		// If no file is being stored, use asserted fields
 		upload = &models.Upload{
@@ -45,6 +55,7 @@ func MakeUpload(db *pop.Connection, assertions Assertions) models.Upload {
 			Bytes:       2202009,
 			ContentType: "application/pdf",
 			Checksum:    "ImGQ2Ush0bDHsaQthV5BnQ==",
+			Status:      models.UploadStatusCLEAN,
 		}
 
 		mergeModels(upload, assertions.Upload)
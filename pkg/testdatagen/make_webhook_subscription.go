@@ -0,0 +1,66 @@
+package testdatagen
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/pop/slices"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// MakeWebhookSubscription creates a single active WebhookSubscription to every event type
+func MakeWebhookSubscription(db *pop.Connection, assertions Assertions) models.WebhookSubscription {
+	tspID := assertions.WebhookSubscription.TransportationServiceProviderID
+	if isZeroUUID(tspID) {
+		tsp := assertions.TransportationServiceProvider
+		if isZeroUUID(tsp.ID) {
+			tsp = MakeTSP(db, assertions)
+		}
+		tspID = tsp.ID
+	}
+
+	subscription := models.WebhookSubscription{
+		TransportationServiceProviderID: tspID,
+		URL:                             "https://tsp.example.com/milmove/events",
+		EventTypes:                      append(slices.String{}, models.WebhookEventTypes...),
+		Secret:                          "not-a-real-secret",
+		Active:                          true,
+	}
+
+	mergeModels(&subscription, assertions.WebhookSubscription)
+
+	mustCreate(db, &subscription)
+
+	return subscription
+}
+
+// MakeDefaultWebhookSubscription returns a WebhookSubscription with default values
+func MakeDefaultWebhookSubscription(db *pop.Connection) models.WebhookSubscription {
+	return MakeWebhookSubscription(db, Assertions{})
+}
+
+// MakeWebhookDelivery creates a single pending WebhookDelivery of a shipment awarded event
+func MakeWebhookDelivery(db *pop.Connection, assertions Assertions) models.WebhookDelivery {
+	subscription := assertions.WebhookSubscription
+	if isZeroUUID(subscription.ID) {
+		subscription = MakeWebhookSubscription(db, assertions)
+	}
+
+	delivery := models.WebhookDelivery{
+		WebhookSubscriptionID: subscription.ID,
+		WebhookSubscription:   subscription,
+		EventID:               uuid.Must(uuid.NewV4()),
+		EventType:             models.WebhookEventTypeSHIPMENTAWARDED,
+		Payload:               `{"type":"SHIPMENT_AWARDED"}`,
+		Status:                models.WebhookDeliveryStatusPENDING,
+		NextAttemptAt:         time.Now(),
+	}
+
+	mergeModels(&delivery, assertions.WebhookDelivery)
+
+	mustCreate(db, &delivery)
+
+	return delivery
+}
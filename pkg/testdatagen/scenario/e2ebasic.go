@@ -3747,13 +3747,13 @@ func MakeHhgWithGBL(db *pop.Connection, tspUser models.TspUser, logger Logger, s
 
 	// Create PDF for GBL
 	gbl, _ := models.FetchGovBillOfLadingFormValues(db, hhgID)
-	formLayout := paperwork.Form1203Layout
+	formLayout, _ := paperwork.LoadFormLayoutAsset(paperwork.Form1203LayoutName)
 
 	// Read in bytes from Asset pkg
-	data, _ := assets.Asset(formLayout.TemplateImagePath)
+	data, _ := assets.Asset(formLayout.TemplatePath())
 	f := bytes.NewReader(data)
 
-	formFiller := paperwork.NewFormFiller()
+	formFiller := paperwork.NewFillerForLayout(formLayout)
 	formFiller.AppendPage(f, formLayout.FieldsLayout, gbl)
 
 	// Write to a temporary file system
@@ -3761,6 +3761,7 @@ func MakeHhgWithGBL(db *pop.Connection, tspUser models.TspUser, logger Logger, s
 	formFiller.Output(aFile)
 
 	uploader := uploaderpkg.NewUploader(db, logger, storer)
+	uploader.SetTrusted(true)
 	upload, _, _ := uploader.CreateUpload(*tspUser.UserID, &aFile, uploaderpkg.AllowedTypesPDF)
 	uploads := []models.Upload{*upload}
 
@@ -3791,7 +3792,7 @@ func sitPlaceInSIT(db *pop.Connection, params placeInSITParams, tspUserSession a
 	}
 
 	inSITPlacer := storageintransit.NewStorageInTransitInSITPlacer(db)
-	_, verrs, err := inSITPlacer.PlaceIntoSITStorageInTransit(payload, params.Shipment.ID, &tspUserSession, params.SITID)
+	_, verrs, err := inSITPlacer.PlaceIntoSITStorageInTransit(payload, params.Shipment.ID, &tspUserSession, "", params.SITID)
 	if verrs.HasAny() || err != nil {
 		fmt.Println(verrs.String())
 		log.Panic(err)
@@ -3929,13 +3930,13 @@ func makeHhgReadyToInvoiceWithSIT(db *pop.Connection, params hhgReadyToInvoicePa
 
 	// Create PDF for GBL
 	gbl, _ := models.FetchGovBillOfLadingFormValues(db, hhgID)
-	formLayout := paperwork.Form1203Layout
+	formLayout, _ := paperwork.LoadFormLayoutAsset(paperwork.Form1203LayoutName)
 
 	// Read in bytes from Asset pkg
-	data, _ := assets.Asset(formLayout.TemplateImagePath)
+	data, _ := assets.Asset(formLayout.TemplatePath())
 	f := bytes.NewReader(data)
 
-	formFiller := paperwork.NewFormFiller()
+	formFiller := paperwork.NewFillerForLayout(formLayout)
 	formFiller.AppendPage(f, formLayout.FieldsLayout, gbl)
 
 	// Write to a temporary file system
@@ -3943,6 +3944,7 @@ func makeHhgReadyToInvoiceWithSIT(db *pop.Connection, params hhgReadyToInvoicePa
 	formFiller.Output(aFile)
 
 	uploader := uploaderpkg.NewUploader(db, params.Logger, params.Storer)
+	uploader.SetTrusted(true)
 	upload, _, _ := uploader.CreateUpload(*params.TspUser.UserID, &aFile, uploaderpkg.AllowedTypesPDF)
 	uploads := []models.Upload{*upload}
 
@@ -4064,13 +4066,13 @@ func makeHhgReadyToInvoice(db *pop.Connection, tspUser models.TspUser, logger Lo
 
 	// Create PDF for GBL
 	gbl, _ := models.FetchGovBillOfLadingFormValues(db, hhgID)
-	formLayout := paperwork.Form1203Layout
+	formLayout, _ := paperwork.LoadFormLayoutAsset(paperwork.Form1203LayoutName)
 
 	// Read in bytes from Asset pkg
-	data, _ := assets.Asset(formLayout.TemplateImagePath)
+	data, _ := assets.Asset(formLayout.TemplatePath())
 	f := bytes.NewReader(data)
 
-	formFiller := paperwork.NewFormFiller()
+	formFiller := paperwork.NewFillerForLayout(formLayout)
 	formFiller.AppendPage(f, formLayout.FieldsLayout, gbl)
 
 	// Write to a temporary file system
@@ -4078,6 +4080,7 @@ func makeHhgReadyToInvoice(db *pop.Connection, tspUser models.TspUser, logger Lo
 	formFiller.Output(aFile)
 
 	uploader := uploaderpkg.NewUploader(db, logger, storer)
+	uploader.SetTrusted(true)
 	upload, _, _ := uploader.CreateUpload(*tspUser.UserID, &aFile, uploaderpkg.AllowedTypesPDF)
 	uploads := []models.Upload{*upload}
 
@@ -24,6 +24,7 @@ type Assertions struct {
 	AccessCode                               models.AccessCode
 	Address                                  models.Address
 	AdminUser                                models.AdminUser
+	AwardQueuePolicy                         models.AwardQueuePolicy
 	BackupContact                            models.BackupContact
 	BlackoutDate                             models.BlackoutDate
 	DistanceCalculation                      models.DistanceCalculation
@@ -37,6 +38,7 @@ type Assertions struct {
 	MoveDocument                             models.MoveDocument
 	MovingExpenseDocument                    models.MovingExpenseDocument
 	WeightTicketSetDocument                  models.WeightTicketSetDocument
+	NotificationDelivery                     models.NotificationDelivery
 	OfficeUser                               models.OfficeUser
 	Order                                    models.Order
 	PersonallyProcuredMove                   models.PersonallyProcuredMove
@@ -61,6 +63,8 @@ type Assertions struct {
 	Upload                                   models.Upload
 	Uploader                                 *uploader.Uploader
 	User                                     models.User
+	WebhookDelivery                          models.WebhookDelivery
+	WebhookSubscription                      models.WebhookSubscription
 }
 
 func stringPointer(s string) *string {
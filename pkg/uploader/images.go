@@ -0,0 +1,240 @@
+package uploader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxImageDPI is the resolution images are downscaled to when fit to a letter size page, which is
+	// plenty for weight tickets and receipts to be legible when printed
+	maxImageDPI = 150
+	// maxImageLongSide and maxImageShortSide are the largest dimensions of a normalized image in pixels
+	maxImageLongSide  = 11 * maxImageDPI
+	maxImageShortSide = 17 * maxImageDPI / 2
+	// maxImagePixels is the largest image we'll decode, to protect against decompression bombs
+	maxImagePixels = 100 * 1000 * 1000
+	// thumbnailSize is the longest side of a thumbnail in pixels
+	thumbnailSize = 200
+	// jpegQuality is the quality normalized images and thumbnails are encoded with
+	jpegQuality = 90
+)
+
+// exifOrientationTag is the EXIF tag recording how a camera was held when it took a photo
+const exifOrientationTag = 0x0112
+
+// IsImage returns true if uploads of contentType are normalized and have thumbnails
+func IsImage(contentType string) bool {
+	return contentType == "image/jpeg" || contentType == "image/png"
+}
+
+// NormalizeImage decodes a JPEG or PNG image, rotates it upright according to its EXIF orientation, downscales it
+// to fit a letter size page at maxImageDPI, and writes it to w in its original format. Metadata, including any GPS
+// location, isn't carried over to the normalized image.
+func NormalizeImage(data io.Reader, contentType string, w io.Writer) (image.Image, error) {
+	content, err := ioutil.ReadAll(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read image")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidFileContent, err.Error())
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, errors.Wrapf(ErrInvalidFileContent, "image is too large at %dx%d", config.Width, config.Height)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidFileContent, err.Error())
+	}
+
+	img := toRGBA(decoded)
+	if contentType == "image/jpeg" {
+		img = orient(img, exifOrientation(content))
+	}
+
+	maxWidth, maxHeight := maxImageShortSide, maxImageLongSide
+	if img.Bounds().Dx() > img.Bounds().Dy() {
+		maxWidth, maxHeight = maxImageLongSide, maxImageShortSide
+	}
+	img = fitWithin(img, maxWidth, maxHeight)
+
+	if err := encodeImage(w, img, contentType); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// WriteThumbnail writes a thumbnail of img to w in the given format
+func WriteThumbnail(img image.Image, contentType string, w io.Writer) error {
+	return encodeImage(w, fitWithin(toRGBA(img), thumbnailSize, thumbnailSize), contentType)
+}
+
+func encodeImage(w io.Writer, img image.Image, contentType string) error {
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(w, img)
+	} else {
+		err = jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	return errors.Wrap(err, "could not encode image")
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
+
+// exifOrientation returns the EXIF orientation of a JPEG, from 1 (upright) to 8, or 1 if it doesn't have one
+func exifOrientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return 1
+	}
+
+	// Walk the segments before the image data, looking for the APP1 segment holding EXIF data
+	for i := 2; i+4 <= len(content); {
+		if content[i] != 0xFF {
+			return 1
+		}
+		marker := content[i+1]
+		length := int(binary.BigEndian.Uint16(content[i+2:]))
+		if marker == 0xDA || length < 2 || i+2+length > len(content) {
+			return 1
+		}
+		segment := content[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation tag from the first IFD of EXIF data, which is laid out like a TIFF file
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for e := 0; e < entries; e++ {
+		entry := ifd + 2 + e*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// orient transforms an image with the given EXIF orientation so that it is upright
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		// Orientations 5 to 8 swap the width and height
+		dw, dh = h, w
+	}
+
+	// source returns the pixel of img that belongs at x, y of the upright image
+	source := map[int]func(x, y int) (int, int){
+		2: func(x, y int) (int, int) { return w - 1 - x, y },
+		3: func(x, y int) (int, int) { return w - 1 - x, h - 1 - y },
+		4: func(x, y int) (int, int) { return x, h - 1 - y },
+		5: func(x, y int) (int, int) { return y, x },
+		6: func(x, y int) (int, int) { return y, h - 1 - x },
+		7: func(x, y int) (int, int) { return w - 1 - y, h - 1 - x },
+		8: func(x, y int) (int, int) { return w - 1 - y, x },
+	}[orientation]
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := source(x, y)
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], img.Pix[img.PixOffset(sx, sy):img.PixOffset(sx, sy)+4])
+		}
+	}
+	return dst
+}
+
+// fitWithin downscales an image, keeping its aspect ratio, so that it is no larger than maxWidth by maxHeight.
+// Each pixel of the result is the average of the pixels it covers in the original.
+func fitWithin(img *image.RGBA, maxWidth, maxHeight int) *image.RGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w <= maxWidth && h <= maxHeight {
+		return img
+	}
+
+	dw, dh := maxWidth, h*maxWidth/w
+	if dh > maxHeight {
+		dw, dh = w*maxHeight/h, maxHeight
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		y0, y1 := dy*h/dh, (dy+1)*h/dh
+		for dx := 0; dx < dw; dx++ {
+			x0, x1 := dx*w/dw, (dx+1)*w/dw
+
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					p := img.PixOffset(x, y)
+					for c := 0; c < 4; c++ {
+						sum[c] += int(img.Pix[p+c])
+					}
+				}
+			}
+			count := (y1 - y0) * (x1 - x0)
+			p := dst.PixOffset(dx, dy)
+			for c := 0; c < 4; c++ {
+				dst.Pix[p+c] = uint8(sum[c] / count)
+			}
+		}
+	}
+	return dst
+}
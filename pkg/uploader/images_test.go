@@ -0,0 +1,128 @@
+package uploader_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/transcom/mymove/pkg/uploader"
+)
+
+// twoToneImage returns an image with its left half red and its right half blue
+func twoToneImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// withEXIFOrientation adds an EXIF segment recording the given orientation to a JPEG
+func withEXIFOrientation(jpegData []byte, orientation byte) []byte {
+	tiff := []byte{
+		'M', 'M', 0, 42, 0, 0, 0, 8, // big endian header, first IFD at offset 8
+		0, 1, // one entry
+		0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0, // orientation, one SHORT
+		0, 0, 0, 0, // no more IFDs
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(payload) + 2
+
+	var out bytes.Buffer
+	out.Write(jpegData[:2])
+	out.Write([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)})
+	out.Write(payload)
+	out.Write(jpegData[2:])
+	return out.Bytes()
+}
+
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r > 0xC000 && g < 0x4000 && b < 0x4000
+}
+
+func isBlue(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return b > 0xC000 && r < 0x4000 && g < 0x4000
+}
+
+func TestNormalizeImageAppliesOrientationAndStripsMetadata(t *testing.T) {
+	var original bytes.Buffer
+	if err := jpeg.Encode(&original, twoToneImage(400, 100), nil); err != nil {
+		t.Fatal(err)
+	}
+	// Orientation 6 means the photo must be turned clockwise to be upright
+	photo := withEXIFOrientation(original.Bytes(), 6)
+
+	var normalized bytes.Buffer
+	if _, err := uploader.NormalizeImage(bytes.NewReader(photo), "image/jpeg", &normalized); err != nil {
+		t.Fatalf("failed to normalize image: %s", err)
+	}
+	if bytes.Contains(normalized.Bytes(), []byte("Exif")) {
+		t.Error("normalized image still has EXIF metadata")
+	}
+
+	img, err := jpeg.Decode(&normalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 100 || b.Dy() != 400 {
+		t.Fatalf("expected a 100x400 upright image, got %dx%d", b.Dx(), b.Dy())
+	}
+	// Turned clockwise, the left of the photo is at the top
+	if !isRed(img.At(50, 50)) || !isBlue(img.At(50, 350)) {
+		t.Errorf("image was not rotated clockwise, got %v at the top and %v at the bottom", img.At(50, 50), img.At(50, 350))
+	}
+}
+
+func TestNormalizeImageDownscales(t *testing.T) {
+	var original bytes.Buffer
+	if err := png.Encode(&original, twoToneImage(3000, 2000)); err != nil {
+		t.Fatal(err)
+	}
+
+	var normalized bytes.Buffer
+	img, err := uploader.NormalizeImage(&original, "image/png", &normalized)
+	if err != nil {
+		t.Fatalf("failed to normalize image: %s", err)
+	}
+	config, err := png.DecodeConfig(&normalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Landscape images fit an 11x8.5 inch page at 150 DPI
+	if config.Width != 1650 || config.Height != 1100 {
+		t.Errorf("expected a 1650x1100 image, got %dx%d", config.Width, config.Height)
+	}
+
+	var thumbnail bytes.Buffer
+	if err := uploader.WriteThumbnail(img, "image/png", &thumbnail); err != nil {
+		t.Fatalf("failed to write thumbnail: %s", err)
+	}
+	thumbnailImg, err := png.Decode(&thumbnail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := thumbnailImg.Bounds(); b.Dx() != 200 || b.Dy() != 133 {
+		t.Errorf("expected a 200x133 thumbnail, got %dx%d", b.Dx(), b.Dy())
+	}
+	if !isRed(thumbnailImg.At(10, 60)) || !isBlue(thumbnailImg.At(190, 60)) {
+		t.Error("thumbnail doesn't look like the image")
+	}
+}
+
+func TestNormalizeImageRejectsInvalidImages(t *testing.T) {
+	var normalized bytes.Buffer
+	if _, err := uploader.NormalizeImage(bytes.NewReader([]byte("\xFF\xD8\xFF not really a JPEG")), "image/jpeg", &normalized); err == nil {
+		t.Error("expected an error normalizing an invalid image")
+	}
+}
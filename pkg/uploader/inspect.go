@@ -0,0 +1,67 @@
+package uploader
+
+import (
+	"bytes"
+	"image"
+	// Register the image formats we accept so their headers can be decoded
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidFileContent represents an error caused by a file whose content doesn't match its type
+var ErrInvalidFileContent = errors.New("File content does not match its type")
+
+// pdfTrailerSearchSize is how far from the end of a PDF to look for its end-of-file marker
+const pdfTrailerSearchSize = 1024
+
+// inspectContent checks that a file is a well-formed example of its detected content type, so truncated files and
+// files that only start like an allowed type are rejected before they are stored. It seeks the file back to its
+// beginning when it's done.
+func inspectContent(file io.ReadSeeker, contentType string) error {
+	var err error
+	switch contentType {
+	case "application/pdf":
+		err = inspectPDF(file)
+	case "image/jpeg", "image/png":
+		_, _, err = image.DecodeConfig(file)
+		if err != nil {
+			err = errors.Wrap(ErrInvalidFileContent, err.Error())
+		}
+	}
+
+	if _, seekErr := file.Seek(0, io.SeekStart); seekErr != nil {
+		return errors.Wrap(seekErr, "could not seek to beginning of file")
+	}
+	return err
+}
+
+// inspectPDF checks that a PDF has both its header and its end-of-file marker
+func inspectPDF(file io.ReadSeeker) error {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(file, header); err != nil || !bytes.Equal(header, []byte("%PDF-")) {
+		return errors.Wrap(ErrInvalidFileContent, "missing PDF header")
+	}
+
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errors.Wrap(err, "could not seek to end of file")
+	}
+	offset := size - pdfTrailerSearchSize
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return errors.Wrap(err, "could not seek to end of file")
+	}
+	trailer := make([]byte, size-offset)
+	if _, err := io.ReadFull(file, trailer); err != nil {
+		return errors.Wrap(err, "could not read end of file")
+	}
+	if !bytes.Contains(trailer, []byte("%%EOF")) {
+		return errors.Wrap(ErrInvalidFileContent, "missing PDF end-of-file marker")
+	}
+	return nil
+}
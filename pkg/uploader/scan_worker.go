@@ -0,0 +1,157 @@
+package uploader
+
+import (
+	"context"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/gobuffalo/pop"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/antivirus"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/storage"
+)
+
+// scanBatchSize is the most uploads the worker claims and scans at once
+const scanBatchSize = 10
+
+// maxScanAttempts is the number of attempts after which an upload that can't be scanned is given up on
+const maxScanAttempts = 10
+
+// scanRetryInterval is how long an upload that couldn't be scanned waits before it is tried again. It also has to
+// outlast scanning a batch, since a claimed upload is left alone for that long.
+const scanRetryInterval = 5 * time.Minute
+
+// ScanWorker scans quarantined uploads for viruses and releases the clean ones
+type ScanWorker struct {
+	db      *pop.Connection
+	logger  Logger
+	storer  storage.FileStorer
+	scanner antivirus.Scanner
+	clock   clock.Clock
+}
+
+// NewScanWorker returns a ScanWorker that scans files fetched from storer with scanner
+func NewScanWorker(db *pop.Connection, logger Logger, storer storage.FileStorer, scanner antivirus.Scanner, clock clock.Clock) *ScanWorker {
+	return &ScanWorker{
+		db:      db,
+		logger:  logger,
+		storer:  storer,
+		scanner: scanner,
+		clock:   clock,
+	}
+}
+
+// ScanPending claims a batch of pending uploads, scans them and returns how many it claimed. Nothing is locked
+// while files are fetched and scanned. Uploads that can't be scanned, for example because the scanner is
+// unavailable, stay pending and are tried again after scanRetryInterval, until they have been attempted
+// maxScanAttempts times.
+func (w *ScanWorker) ScanPending(ctx context.Context) (int, error) {
+	now := w.clock.Now()
+	uploads, err := models.ClaimPendingUploads(w.db, now, now.Add(-scanRetryInterval), scanBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, upload := range uploads {
+		if ctx.Err() != nil {
+			// The rest of the batch is tried again after the retry interval
+			break
+		}
+		if err := w.scan(ctx, &upload); err != nil {
+			w.recordFailure(&upload, err)
+		}
+		verrs, err := w.db.ValidateAndUpdate(&upload)
+		if err != nil {
+			w.logger.Error("Failed to save upload scan", zap.String("id", upload.ID.String()), zap.Error(err))
+		} else if verrs.HasAny() {
+			w.logger.Error("Invalid upload", zap.String("id", upload.ID.String()), zap.String("verrs", verrs.String()))
+		}
+	}
+	return len(uploads), nil
+}
+
+// scan scans an upload's file and records the verdict on it
+func (w *ScanWorker) scan(ctx context.Context, upload *models.Upload) error {
+	file, err := w.storer.Fetch(upload.StorageKey)
+	if err != nil {
+		return errors.Wrap(err, "Failed to fetch upload for scanning")
+	}
+	defer file.Close()
+
+	result, err := w.scanner.Scan(ctx, file)
+	if err != nil {
+		return errors.Wrap(err, "Failed to scan upload")
+	}
+
+	now := w.clock.Now()
+	upload.ScannedAt = &now
+	upload.LastScanError = nil
+	if !result.Infected {
+		upload.Status = models.UploadStatusCLEAN
+		upload.ScanResult = nil
+		w.logger.Info("Upload is clean", zap.String("id", upload.ID.String()))
+		return nil
+	}
+
+	// Infected files stay in storage so they can be investigated, but can never be fetched by users
+	upload.Status = models.UploadStatusINFECTED
+	upload.ScanResult = &result.Signature
+	w.logger.Error("Upload is infected",
+		zap.String("id", upload.ID.String()),
+		zap.String("uploader_id", upload.UploaderID.String()),
+		zap.String("signature", result.Signature))
+	return nil
+}
+
+// recordFailure records why an upload couldn't be scanned, giving up on it after maxScanAttempts
+func (w *ScanWorker) recordFailure(upload *models.Upload, err error) {
+	lastScanError := err.Error()
+	upload.LastScanError = &lastScanError
+	if upload.ScanAttempts >= maxScanAttempts {
+		upload.Status = models.UploadStatusSCANFAILED
+		w.logger.Error("Giving up on scanning upload",
+			zap.String("id", upload.ID.String()),
+			zap.Int("scan_attempts", upload.ScanAttempts),
+			zap.Error(err))
+		return
+	}
+	w.logger.Error("Failed to scan upload, will retry",
+		zap.String("id", upload.ID.String()),
+		zap.Int("scan_attempts", upload.ScanAttempts),
+		zap.Error(err))
+}
+
+// Run scans pending uploads every interval until ctx is canceled
+func (w *ScanWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := w.clock.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep going while there's a backlog, then wait for the next tick
+		for {
+			scanned, err := w.ScanPending(ctx)
+			if err != nil {
+				w.logger.Error("Failed to scan uploads", zap.Error(err))
+				break
+			}
+			if scanned < scanBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// InitScanWorker initializes the upload scan worker and its scanner from command line flags
+func InitScanWorker(v *viper.Viper, db *pop.Connection, logger Logger, storer storage.FileStorer) *ScanWorker {
+	return NewScanWorker(db, logger, storer, antivirus.InitScanner(v, logger), clock.New())
+}
@@ -0,0 +1,99 @@
+package uploader_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+
+	antivirusTest "github.com/transcom/mymove/pkg/antivirus/test"
+	"github.com/transcom/mymove/pkg/models"
+	storageTest "github.com/transcom/mymove/pkg/storage/test"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/uploader"
+)
+
+// storePendingUpload stores content as a quarantined upload
+func (suite *UploaderSuite) storePendingUpload(storer *storageTest.FakeS3Storage, content string) models.Upload {
+	upload := testdatagen.MakeUpload(suite.DB(), testdatagen.Assertions{
+		Upload: models.Upload{
+			Status: models.UploadStatusPENDING,
+		},
+	})
+	_, err := storer.Store(upload.StorageKey, strings.NewReader(content), "")
+	suite.NoError(err)
+	return upload
+}
+
+func (suite *UploaderSuite) TestScanWorkerReleasesCleanUploads() {
+	storer := storageTest.NewFakeS3Storage(true)
+	clean := suite.storePendingUpload(storer, "%PDF-1.4 a perfectly normal file %%EOF")
+	infected := suite.storePendingUpload(storer, antivirusTest.EICAR)
+
+	scanner := antivirusTest.NewFakeScanner(nil)
+	clock := clock.NewMock()
+	worker := uploader.NewScanWorker(suite.DB(), suite.logger, storer, scanner, clock)
+
+	scanned, err := worker.ScanPending(context.Background())
+	suite.NoError(err)
+	suite.Equal(2, scanned)
+
+	suite.NoError(suite.DB().Find(&clean, clean.ID))
+	suite.Equal(models.UploadStatusCLEAN, clean.Status)
+	if suite.NotNil(clean.ScannedAt) {
+		suite.True(clock.Now().Equal(*clean.ScannedAt))
+	}
+	suite.Nil(clean.ScanResult)
+
+	suite.NoError(suite.DB().Find(&infected, infected.ID))
+	suite.Equal(models.UploadStatusINFECTED, infected.Status)
+	if suite.NotNil(infected.ScanResult) {
+		suite.Equal("Eicar-Signature", *infected.ScanResult)
+	}
+
+	// Nothing is left to scan
+	scanned, err = worker.ScanPending(context.Background())
+	suite.NoError(err)
+	suite.Equal(0, scanned)
+	suite.Equal(2, scanner.Scanned)
+}
+
+func (suite *UploaderSuite) TestScanWorkerRetriesUploadsWhenScannerFails() {
+	storer := storageTest.NewFakeS3Storage(true)
+	upload := suite.storePendingUpload(storer, "%PDF-1.4 a perfectly normal file %%EOF")
+
+	scanner := antivirusTest.NewFakeScanner(errors.New("clamd is down"))
+	clock := clock.NewMock()
+	worker := uploader.NewScanWorker(suite.DB(), suite.logger, storer, scanner, clock)
+
+	scanned, err := worker.ScanPending(context.Background())
+	suite.NoError(err)
+	suite.Equal(1, scanned)
+
+	suite.NoError(suite.DB().Find(&upload, upload.ID))
+	suite.Equal(models.UploadStatusPENDING, upload.Status)
+	suite.Equal(1, upload.ScanAttempts)
+	suite.NotNil(upload.LastScanError)
+	suite.Nil(upload.ScannedAt)
+
+	// It isn't tried again until the retry interval has passed, so it can't hold up newer uploads
+	newer := suite.storePendingUpload(storer, "%PDF-1.4 another normal file %%EOF")
+	scanned, err = worker.ScanPending(context.Background())
+	suite.NoError(err)
+	suite.Equal(1, scanned)
+	suite.NoError(suite.DB().Find(&newer, newer.ID))
+	suite.Equal(1, newer.ScanAttempts)
+
+	// It's given up on after too many attempts
+	for i := 0; i < 20; i++ {
+		clock.Add(time.Hour)
+		if _, err := worker.ScanPending(context.Background()); !suite.NoError(err) {
+			break
+		}
+	}
+	suite.NoError(suite.DB().Find(&upload, upload.ID))
+	suite.Equal(models.UploadStatusSCANFAILED, upload.Status)
+	suite.Equal(10, upload.ScanAttempts)
+}
@@ -1,6 +1,8 @@
 package uploader
 
 import (
+	"bytes"
+	"image"
 	"io"
 
 	"github.com/gobuffalo/pop"
@@ -17,13 +19,31 @@ import (
 // ErrZeroLengthFile represents an error caused by a file with no content
 var ErrZeroLengthFile = errors.New("File has length of 0")
 
+// ErrUploadNotClean represents an error caused by fetching an upload that hasn't passed its virus scan
+var ErrUploadNotClean = errors.New("Upload has not passed its virus scan")
+
+// ErrNoThumbnail represents an error caused by asking for the thumbnail of an upload that doesn't have one
+var ErrNoThumbnail = errors.New("Upload does not have a thumbnail")
+
+// thumbnailKeySuffix is appended to an upload's storage key to get the key of its thumbnail
+const thumbnailKeySuffix = "-thumbnail"
+
+// contentStorer is implemented by storers that share stored content between files with the same content
+type contentStorer interface {
+	DeleteContent(contentHash string) error
+}
+
 // Uploader encapsulates a few common processes: creating Uploads for a Document,
 // generating pre-signed URLs for file access, and deleting Uploads.
+//
+// New Uploads are quarantined until a ScanWorker has scanned them for viruses,
+// unless the Uploader is trusted.
 type Uploader struct {
 	db               *pop.Connection
 	logger           Logger
 	Storer           storage.FileStorer
 	UploadStorageKey string
+	trusted          bool
 }
 
 // NewUploader creates and returns a new uploader
@@ -41,6 +61,12 @@ func (u *Uploader) SetUploadStorageKey(key string) {
 	u.UploadStorageKey = key
 }
 
+// SetTrusted marks files uploaded by this Uploader as clean without scanning them.
+// It is for files the app generates itself, never for files from users.
+func (u *Uploader) SetTrusted(trusted bool) {
+	u.trusted = trusted
+}
+
 // CreateUploadForDocument creates a new Upload by performing validations, storing the specified
 // file using the supplied storer, and saving an Upload object to the database containing
 // the file's metadata.
@@ -69,6 +95,30 @@ func (u *Uploader) CreateUploadForDocument(documentID *uuid.UUID, userID uuid.UU
 		return nil, responseVErrors, nil
 	}
 
+	if inspectErr := inspectContent(file, contentType); inspectErr != nil {
+		u.logger.Error("Invalid file content for upload", zap.String("Filename", file.Name()), zap.String("ContentType", contentType), zap.Error(inspectErr))
+		return nil, responseVErrors, inspectErr
+	}
+
+	// Images are stored normalized, without the metadata phones add to photos
+	filename := file.Name()
+	var normalized image.Image
+	if IsImage(contentType) {
+		normalizedFile, img, normalizeErr := u.normalizeImage(file, contentType)
+		if normalizeErr != nil {
+			u.logger.Error("Could not normalize image", zap.String("Filename", filename), zap.Error(normalizeErr))
+			return nil, responseVErrors, normalizeErr
+		}
+		defer u.Storer.TempFileSystem().Remove(normalizedFile.Name())
+		defer normalizedFile.Close()
+
+		file, normalized = normalizedFile, img
+		if info, fileStatErr = file.Stat(); fileStatErr != nil {
+			u.logger.Error("Could not get normalized image info", zap.Error(fileStatErr))
+			return nil, responseVErrors, fileStatErr
+		}
+	}
+
 	checksum, computeChecksumErr := storage.ComputeChecksum(file)
 	if computeChecksumErr != nil {
 		u.logger.Error("Could not compute checksum", zap.Error(computeChecksumErr))
@@ -81,10 +131,14 @@ func (u *Uploader) CreateUploadForDocument(documentID *uuid.UUID, userID uuid.UU
 		ID:          id,
 		DocumentID:  documentID,
 		UploaderID:  userID,
-		Filename:    file.Name(),
+		Filename:    filename,
 		Bytes:       info.Size(),
 		ContentType: contentType,
 		Checksum:    checksum,
+		Status:      models.UploadStatusPENDING,
+	}
+	if u.trusted {
+		newUpload.Status = models.UploadStatusCLEAN
 	}
 
 	// Set the Upload.StorageKey if set
@@ -105,13 +159,42 @@ func (u *Uploader) CreateUploadForDocument(documentID *uuid.UUID, userID uuid.UU
 		}
 
 		// Push file to S3
-		if _, err := u.Storer.Store(newUpload.StorageKey, file, checksum); err != nil {
+		storeResult, err := u.Storer.Store(newUpload.StorageKey, file, checksum)
+		if err == nil && storeResult.ContentHash != "" {
+			err = u.claimContent(db, storeResult.ContentHash, newUpload.StorageKey, file, checksum)
+		}
+		if err != nil {
 			u.logger.Error("failed to store object", zap.Error(err))
 			responseVErrors.Append(verrs)
 			uploadError = errors.Wrap(err, "failed to store object")
 			return transactionError
 		}
 
+		if normalized != nil {
+			if err := u.storeThumbnail(db, newUpload, normalized); err != nil {
+				u.logger.Error("failed to store thumbnail", zap.Error(err))
+				uploadError = errors.Wrap(err, "failed to store thumbnail")
+				return transactionError
+			}
+		}
+
+		// Record how the file was stored, if the storer encrypted or deduplicated it or there's a thumbnail
+		if storeResult.KeyID != "" || storeResult.ContentHash != "" || newUpload.ThumbnailStorageKey != nil {
+			if storeResult.KeyID != "" {
+				newUpload.EncryptionKeyID = &storeResult.KeyID
+			}
+			if storeResult.ContentHash != "" {
+				newUpload.ContentHash = &storeResult.ContentHash
+			}
+			verrs, err := db.ValidateAndUpdate(newUpload)
+			if err != nil || verrs.HasAny() {
+				u.logger.Error("Error recording how upload was stored", zap.Error(err))
+				responseVErrors.Append(verrs)
+				uploadError = errors.Wrap(err, "Error recording how upload was stored")
+				return transactionError
+			}
+		}
+
 		u.logger.Info("created an upload with id and key ", zap.Any("new_upload_id", newUpload.ID), zap.String("key", newUpload.StorageKey))
 		return nil
 	})
@@ -122,13 +205,79 @@ func (u *Uploader) CreateUploadForDocument(documentID *uuid.UUID, userID uuid.UU
 	return newUpload, responseVErrors, nil
 }
 
+// normalizeImage writes a normalized copy of an image to a tempfile, which is returned seeked to its beginning
+// along with the normalized image
+func (u *Uploader) normalizeImage(file afero.File, contentType string) (afero.File, image.Image, error) {
+	normalizedFile, err := u.Storer.TempFileSystem().TempFile("", "normalized")
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not create tempfile")
+	}
+
+	img, err := NormalizeImage(file, contentType, normalizedFile)
+	if err == nil {
+		_, err = normalizedFile.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		normalizedFile.Close()
+		u.Storer.TempFileSystem().Remove(normalizedFile.Name())
+		return nil, nil, err
+	}
+	return normalizedFile, img, nil
+}
+
+// claimContent locks shared content that an upload being created in tx refers to, then stores the upload's file
+// again in case the last other upload that referred to the content was deleted, taking the content with it, while
+// the file was being stored. Storing content that already exists only writes the reference to it.
+func (u *Uploader) claimContent(tx *pop.Connection, contentHash string, key string, data io.ReadSeeker, checksum string) error {
+	if err := models.LockUploadContent(tx, contentHash); err != nil {
+		return err
+	}
+	if _, err := data.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := u.Storer.Store(key, data, checksum)
+	return err
+}
+
+// storeThumbnail stores a thumbnail of an upload's image alongside it and records where it is on the upload
+func (u *Uploader) storeThumbnail(tx *pop.Connection, upload *models.Upload, img image.Image) error {
+	var thumbnail bytes.Buffer
+	if err := WriteThumbnail(img, upload.ContentType, &thumbnail); err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(thumbnail.Bytes())
+	checksum, err := storage.ComputeChecksum(reader)
+	if err != nil {
+		return err
+	}
+	key := upload.StorageKey + thumbnailKeySuffix
+	storeResult, err := u.Storer.Store(key, reader, checksum)
+	if err != nil {
+		return err
+	}
+
+	upload.ThumbnailStorageKey = &key
+	if storeResult.ContentHash != "" {
+		if err := u.claimContent(tx, storeResult.ContentHash, key, reader, checksum); err != nil {
+			return err
+		}
+		upload.ThumbnailContentHash = &storeResult.ContentHash
+	}
+	return nil
+}
+
 // CreateUpload stores Upload but does not assign a Document
 func (u *Uploader) CreateUpload(userID uuid.UUID, aFile *afero.File, allowedFileTypes AllowedFileTypes) (*models.Upload, *validate.Errors, error) {
 	return u.CreateUploadForDocument(nil, userID, *aFile, allowedFileTypes)
 }
 
 // PresignedURL returns a URL that can be used to access an Upload's file.
+// Uploads that haven't passed their virus scan can't be accessed.
 func (u *Uploader) PresignedURL(upload *models.Upload) (string, error) {
+	if !upload.IsClean() {
+		return "", ErrUploadNotClean
+	}
 	url, err := u.Storer.PresignedURL(upload.StorageKey, upload.ContentType)
 	if err != nil {
 		u.logger.Error("failed to get presigned url", zap.Error(err))
@@ -137,20 +286,83 @@ func (u *Uploader) PresignedURL(upload *models.Upload) (string, error) {
 	return url, nil
 }
 
-// DeleteUpload removes an Upload from the database and deletes its file from the
-// storer.
+// ThumbnailPresignedURL returns a URL that can be used to access the thumbnail of an
+// Upload's image. Like the image itself, it can't be accessed until it has passed its
+// virus scan.
+func (u *Uploader) ThumbnailPresignedURL(upload *models.Upload) (string, error) {
+	if !upload.IsClean() {
+		return "", ErrUploadNotClean
+	}
+	if upload.ThumbnailStorageKey == nil {
+		return "", ErrNoThumbnail
+	}
+	url, err := u.Storer.PresignedURL(*upload.ThumbnailStorageKey, upload.ContentType)
+	if err != nil {
+		u.logger.Error("failed to get presigned thumbnail url", zap.Error(err))
+		return "", err
+	}
+	return url, nil
+}
+
+// DeleteUpload removes an Upload from the database and deletes its file and any
+// thumbnail from the storer. If the storer shares content between files, the content
+// is deleted once no other upload refers to it.
 func (u *Uploader) DeleteUpload(upload *models.Upload) error {
 	if err := u.Storer.Delete(upload.StorageKey); err != nil {
 		return err
 	}
+	if upload.ThumbnailStorageKey != nil {
+		if err := u.Storer.Delete(*upload.ThumbnailStorageKey); err != nil {
+			return err
+		}
+	}
 
-	return models.DeleteUpload(u.db, upload)
+	return u.db.Transaction(func(tx *pop.Connection) error {
+		if err := models.DeleteUpload(tx, upload); err != nil {
+			return err
+		}
+
+		storer, ok := u.Storer.(contentStorer)
+		if !ok {
+			return nil
+		}
+		if upload.ContentHash != nil {
+			if err := deleteUnusedContent(tx, storer, *upload.ContentHash, models.CountUploadsWithContentHash); err != nil {
+				return err
+			}
+		}
+		if upload.ThumbnailContentHash != nil {
+			return deleteUnusedContent(tx, storer, *upload.ThumbnailContentHash, models.CountUploadsWithThumbnailContentHash)
+		}
+		return nil
+	})
+}
+
+// deleteUnusedContent deletes shared content if no upload refers to it. It holds the content's lock while it
+// counts and deletes, so an upload being created that refers to the content either is counted or stores the
+// content again once it gets the lock.
+func deleteUnusedContent(tx *pop.Connection, storer contentStorer, contentHash string, count func(*pop.Connection, string) (int, error)) error {
+	if err := models.LockUploadContent(tx, contentHash); err != nil {
+		return err
+	}
+	remaining, err := count(tx, contentHash)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return nil
+	}
+	return storer.DeleteContent(contentHash)
 }
 
 // Download fetches an Upload's file and stores it in a tempfile. The path to this
 // file is returned.
 //
-// It is the caller's responsibility to delete the tempfile.
+// It is the caller's responsibility to delete the tempfile. Uploads that haven't
+// passed their virus scan can't be downloaded.
 func (u *Uploader) Download(upload *models.Upload) (io.ReadCloser, error) {
+	if !upload.IsClean() {
+		return nil, ErrUploadNotClean
+	}
 	return u.Storer.Fetch(upload.StorageKey)
 }
@@ -1,10 +1,14 @@
 package uploader_test
 
 import (
+	"bytes"
 	"io"
+	"io/ioutil"
+	"image/png"
 	"log"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -12,6 +16,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 
+	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/storage"
 	storageTest "github.com/transcom/mymove/pkg/storage/test"
 	"github.com/transcom/mymove/pkg/testdatagen"
@@ -132,6 +137,7 @@ func (suite *UploaderSuite) TestCreateUploadNoDocument() {
 	userID := document.ServiceMember.UserID
 
 	up := uploader.NewUploader(suite.DB(), suite.logger, suite.storer)
+	up.SetTrusted(true)
 	file := suite.fixture("test.pdf")
 	fixtureFileInfo, err := file.Stat()
 	suite.NoError(err)
@@ -163,3 +169,123 @@ func (suite *UploaderSuite) TestCreateUploadNoDocument() {
 	err = up.Storer.Delete(upload.StorageKey)
 	suite.NoError(err)
 }
+
+func (suite *UploaderSuite) TestEncryptedUploadsShareContent() {
+	document := testdatagen.MakeDefaultDocument(suite.DB())
+	keys, err := storage.ParseLocalKeyManager(strings.NewReader("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"))
+	suite.NoError(err)
+	encrypted := storage.NewEncrypted(storageTest.NewFakeS3Storage(true), keys, "storage", []byte("signing key"), zap.NewNop())
+	up := uploader.NewUploader(suite.DB(), suite.logger, encrypted)
+	up.SetTrusted(true)
+
+	first, verrs, err := up.CreateUploadForDocument(&document.ID, document.ServiceMember.UserID, suite.fixture("test.pdf"), uploader.AllowedTypesPDF)
+	suite.NoError(err)
+	suite.False(verrs.HasAny(), "failed to validate upload", verrs)
+	second, verrs, err := up.CreateUploadForDocument(&document.ID, document.ServiceMember.UserID, suite.fixture("test.pdf"), uploader.AllowedTypesPDF)
+	suite.NoError(err)
+	suite.False(verrs.HasAny(), "failed to validate upload", verrs)
+
+	// Both uploads record the same content, protected by the same key
+	if suite.NotNil(first.ContentHash) && suite.NotNil(second.ContentHash) {
+		suite.Equal("c3bac942ace569acc35be9b14d4f50e3441c86bdc35bb14f403edff09b3627cf", *first.ContentHash)
+		suite.Equal(*first.ContentHash, *second.ContentHash)
+	}
+	suite.Equal(first.EncryptionKeyID, second.EncryptionKeyID)
+
+	download, err := up.Download(second)
+	suite.NoError(err)
+	defer download.Close()
+	content, err := ioutil.ReadAll(download)
+	suite.NoError(err)
+	suite.True(bytes.HasPrefix(content, []byte("%PDF")))
+
+	suite.NoError(up.DeleteUpload(first))
+	count, err := models.CountUploadsWithContentHash(suite.DB(), *second.ContentHash)
+	suite.NoError(err)
+	suite.Equal(1, count)
+
+	// The content goes with the last upload that refers to it, and is stored again by the next
+	suite.NoError(up.DeleteUpload(second))
+	third, verrs, err := up.CreateUploadForDocument(&document.ID, document.ServiceMember.UserID, suite.fixture("test.pdf"), uploader.AllowedTypesPDF)
+	suite.NoError(err)
+	suite.False(verrs.HasAny(), "failed to validate upload", verrs)
+	download, err = up.Download(third)
+	suite.NoError(err)
+	defer download.Close()
+}
+
+func (suite *UploaderSuite) TestUploadsAreQuarantined() {
+	document := testdatagen.MakeDefaultDocument(suite.DB())
+
+	up := uploader.NewUploader(suite.DB(), suite.logger, suite.storer)
+	upload, verrs, err := up.CreateUploadForDocument(&document.ID, document.ServiceMember.UserID, suite.fixture("test.pdf"), uploader.AllowedTypesPDF)
+	suite.NoError(err)
+	suite.False(verrs.HasAny(), "failed to validate upload", verrs)
+	suite.Equal(models.UploadStatusPENDING, upload.Status)
+
+	_, err = up.PresignedURL(upload)
+	suite.Equal(uploader.ErrUploadNotClean, err)
+	_, err = up.Download(upload)
+	suite.Equal(uploader.ErrUploadNotClean, err)
+}
+
+func (suite *UploaderSuite) TestUploadRejectsTruncatedPDF() {
+	document := testdatagen.MakeDefaultDocument(suite.DB())
+
+	file, err := suite.helperNewTempFile()
+	suite.NoError(err)
+	defer file.Close()
+	_, err = file.WriteString("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\n")
+	suite.NoError(err)
+
+	up := uploader.NewUploader(suite.DB(), suite.logger, suite.storer)
+	upload, _, err := up.CreateUploadForDocument(&document.ID, document.ServiceMember.UserID, file, uploader.AllowedTypesPDF)
+	suite.Equal(uploader.ErrInvalidFileContent, errors.Cause(err))
+	suite.Nil(upload)
+}
+
+func (suite *UploaderSuite) TestImageUploadsAreNormalizedWithThumbnails() {
+	document := testdatagen.MakeDefaultDocument(suite.DB())
+
+	file, err := suite.fs.Create("/tmp/milmoves/receipt.png")
+	suite.NoError(err)
+	defer file.Close()
+	suite.NoError(png.Encode(file, twoToneImage(3000, 2000)))
+
+	up := uploader.NewUploader(suite.DB(), suite.logger, suite.storer)
+	up.SetTrusted(true)
+	upload, verrs, err := up.CreateUploadForDocument(&document.ID, document.ServiceMember.UserID, file, uploader.AllowedTypesServiceMember)
+	suite.NoError(err)
+	suite.False(verrs.HasAny(), "failed to validate upload", verrs)
+	suite.Equal("/tmp/milmoves/receipt.png", upload.Filename)
+
+	// The stored image is the normalized one
+	download, err := up.Download(upload)
+	suite.NoError(err)
+	defer download.Close()
+	config, err := png.DecodeConfig(download)
+	suite.NoError(err)
+	suite.Equal(1650, config.Width)
+
+	if suite.NotNil(upload.ThumbnailStorageKey) {
+		suite.Equal(upload.StorageKey+"-thumbnail", *upload.ThumbnailStorageKey)
+		url, err := up.ThumbnailPresignedURL(upload)
+		suite.NoError(err)
+		suite.Contains(url, *upload.ThumbnailStorageKey)
+	}
+
+	suite.NoError(up.DeleteUpload(upload))
+}
+
+func (suite *UploaderSuite) TestPDFUploadsHaveNoThumbnail() {
+	document := testdatagen.MakeDefaultDocument(suite.DB())
+
+	up := uploader.NewUploader(suite.DB(), suite.logger, suite.storer)
+	up.SetTrusted(true)
+	upload, _, err := up.CreateUploadForDocument(&document.ID, document.ServiceMember.UserID, suite.fixture("test.pdf"), uploader.AllowedTypesPDF)
+	suite.NoError(err)
+	suite.Nil(upload.ThumbnailStorageKey)
+
+	_, err = up.ThumbnailPresignedURL(upload)
+	suite.Equal(uploader.ErrNoThumbnail, err)
+}
@@ -0,0 +1,134 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrForbiddenDestination is returned when a webhook URL resolves to an address that isn't on the public internet
+var ErrForbiddenDestination = errors.New("webhook destination is not a public address")
+
+// forbiddenNetworks are the loopback, private, link-local and other special purpose networks webhooks may not be
+// sent to, so that a subscription can't be used to reach our own infrastructure
+var forbiddenNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// IsPublicIP returns whether ip is an address webhooks may be sent to
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range forbiddenNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// DestinationChecker checks that webhook URLs only resolve to public addresses
+type DestinationChecker struct {
+	LookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewDestinationChecker returns a DestinationChecker that resolves hosts with the default resolver
+func NewDestinationChecker() DestinationChecker {
+	return DestinationChecker{LookupIPAddr: net.DefaultResolver.LookupIPAddr}
+}
+
+// CheckURL resolves the host of rawURL and returns ErrForbiddenDestination if any of its addresses isn't public
+func (c DestinationChecker) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrap(err, "Could not parse webhook URL")
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return ErrForbiddenDestination
+		}
+		return nil
+	}
+
+	addrs, err := c.LookupIPAddr(ctx, host)
+	if err != nil {
+		return errors.Wrapf(err, "Could not resolve webhook host %s", host)
+	}
+	if len(addrs) == 0 {
+		return errors.Errorf("Webhook host %s has no addresses", host)
+	}
+	for _, addr := range addrs {
+		if !IsPublicIP(addr.IP) {
+			return ErrForbiddenDestination
+		}
+	}
+	return nil
+}
+
+// checkDialAddress is a net.Dialer Control function that refuses connections to addresses that aren't public. It
+// runs after the host has been resolved, so it also catches hosts that resolved to a public address when the
+// subscription was registered and have since been pointed somewhere else.
+func checkDialAddress(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if !IsPublicIP(net.ParseIP(host)) {
+		return ErrForbiddenDestination
+	}
+	return nil
+}
+
+// newDeliveryClient returns an HTTP client that gives up on a request after timeout and only connects to public
+// addresses
+func newDeliveryClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: checkDialAddress,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// Connect directly, since a proxy would make the connection on our behalf without the address check
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	public := []string{"93.184.216.34", "8.8.8.8", "2606:2800:220:1:248:1893:25c8:1946"}
+	for _, address := range public {
+		if !IsPublicIP(net.ParseIP(address)) {
+			t.Errorf("expected %s to be public", address)
+		}
+	}
+
+	forbidden := []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0",
+		"::1", "fd00::1", "fe80::1", "::ffff:127.0.0.1", "::ffff:169.254.169.254",
+	}
+	for _, address := range forbidden {
+		if IsPublicIP(net.ParseIP(address)) {
+			t.Errorf("expected %s not to be public", address)
+		}
+	}
+}
+
+func TestDestinationCheckerCheckURL(t *testing.T) {
+	checker := DestinationChecker{
+		LookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			switch host {
+			case "tsp.example.com":
+				return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+			case "internal.example.com":
+				return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.5")}}, nil
+			}
+			return nil, errors.New("no such host")
+		},
+	}
+	ctx := context.Background()
+
+	if err := checker.CheckURL(ctx, "https://tsp.example.com/events"); err != nil {
+		t.Errorf("expected public host to be allowed, got %v", err)
+	}
+	if err := checker.CheckURL(ctx, "https://93.184.216.34:8443/events"); err != nil {
+		t.Errorf("expected public address to be allowed, got %v", err)
+	}
+
+	forbidden := []string{
+		"https://internal.example.com/events",
+		"https://127.0.0.1/events",
+		"https://[::1]:8443/events",
+		"https://169.254.169.254/latest/meta-data",
+	}
+	for _, rawURL := range forbidden {
+		if err := checker.CheckURL(ctx, rawURL); errors.Cause(err) != ErrForbiddenDestination {
+			t.Errorf("expected %s to be forbidden, got %v", rawURL, err)
+		}
+	}
+
+	if err := checker.CheckURL(ctx, "https://unknown.example.com/events"); err == nil {
+		t.Error("expected unresolvable host to be rejected")
+	}
+}
+
+func TestCheckDialAddress(t *testing.T) {
+	if err := checkDialAddress("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("expected public address to be dialed, got %v", err)
+	}
+	if err := checkDialAddress("tcp", "127.0.0.1:443", nil); err != ErrForbiddenDestination {
+		t.Errorf("expected loopback address to be refused, got %v", err)
+	}
+	if err := checkDialAddress("tcp6", "[fe80::1]:443", nil); err != ErrForbiddenDestination {
+		t.Errorf("expected link-local address to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignatureHeader carries the signature of a webhook request, as t=<unix timestamp>,v1=<hex HMAC-SHA256>. The
+	// HMAC is of the timestamp, a ".", and the request body, keyed with the subscription's secret. Subscribers
+	// should check it with VerifySignature, or the equivalent, and reject requests with old timestamps.
+	SignatureHeader = "X-MilMove-Signature"
+	// EventTypeHeader carries the type of the event in a webhook request
+	EventTypeHeader = "X-MilMove-Event"
+	// DeliveryHeader carries the ID of the delivery, which is the same for every attempt of it
+	DeliveryHeader = "X-MilMove-Delivery"
+)
+
+// ErrInvalidSignature is returned by VerifySignature for requests that weren't signed with the secret, or not
+// recently enough
+var ErrInvalidSignature = errors.New("INVALID_SIGNATURE")
+
+// NewSecret returns a random secret to sign a subscription's events with
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "Could not generate webhook secret")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func computeSignature(secret string, timestamp int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Sign returns the SignatureHeader value for a request with body sent at timestamp
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", t, hex.EncodeToString(computeSignature(secret, t, body)))
+}
+
+// VerifySignature checks that header is a signature of body with secret, made no more than tolerance before now
+func VerifySignature(secret string, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp int64
+	var signature []byte
+	var timestampErr, signatureErr error = ErrInvalidSignature, ErrInvalidSignature
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp, timestampErr = strconv.ParseInt(kv[1], 10, 64)
+		case "v1":
+			signature, signatureErr = hex.DecodeString(kv[1])
+		}
+	}
+	if timestampErr != nil || signatureErr != nil {
+		return errors.Wrap(ErrInvalidSignature, "malformed signature header")
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return errors.Wrapf(ErrInvalidSignature, "signed %s ago", age)
+	}
+	if !hmac.Equal(signature, computeSignature(secret, timestamp, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := "a secret"
+	body := []byte(`{"type":"SHIPMENT_AWARDED"}`)
+	signedAt := time.Date(2019, time.August, 9, 12, 0, 0, 0, time.UTC)
+	header := Sign(secret, signedAt, body)
+
+	if err := VerifySignature(secret, header, body, signedAt.Add(time.Minute), 5*time.Minute); err != nil {
+		t.Errorf("expected signature to verify, got %v", err)
+	}
+
+	tests := map[string]struct {
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+	}{
+		"wrong secret":     {"another secret", header, body, signedAt},
+		"tampered body":    {secret, header, []byte(`{"type":"SHIPMENT_APPROVED"}`), signedAt},
+		"too old":          {secret, header, body, signedAt.Add(time.Hour)},
+		"from the future":  {secret, header, body, signedAt.Add(-time.Hour)},
+		"no timestamp":     {secret, header[len("t=1565352000,"):], body, signedAt},
+		"malformed digest": {secret, "t=1565352000,v1=xyz", body, signedAt},
+		"empty":            {secret, "", body, signedAt},
+	}
+	for name, test := range tests {
+		err := VerifySignature(test.secret, test.header, test.body, test.now, 5*time.Minute)
+		if errors.Cause(err) != ErrInvalidSignature {
+			t.Errorf("%s: expected ErrInvalidSignature, got %v", name, err)
+		}
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 64 || a == b {
+		t.Errorf("expected two different 32 byte hex secrets, got %q and %q", a, b)
+	}
+}
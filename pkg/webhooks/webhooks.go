@@ -0,0 +1,117 @@
+// Package webhooks sends shipment lifecycle events to the https endpoints TSPs have subscribed to them, so TSPs
+// don't have to poll for new awards, approvals, SIT decisions and pre-approval request outcomes.
+//
+// Publishing an event writes one pending delivery per matching subscription, and the DeliveryWorker POSTs them
+// as JSON, retrying failures with backoff. Every request is signed with the subscription's secret; see
+// SignatureHeader.
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// Event is the JSON body sent to subscribers. Its ID is the same in every delivery of the event, and for every
+// attempt, so subscribers can ignore events they have already processed.
+type Event struct {
+	ID        uuid.UUID               `json:"id"`
+	Type      models.WebhookEventType `json:"type"`
+	CreatedAt time.Time               `json:"created_at"`
+	Data      EventData               `json:"data"`
+}
+
+// EventData identifies what the event happened to. Subscribers fetch the details from the public API.
+type EventData struct {
+	ShipmentID         uuid.UUID  `json:"shipment_id"`
+	StorageInTransitID *uuid.UUID `json:"storage_in_transit_id,omitempty"`
+	ShipmentLineItemID *uuid.UUID `json:"shipment_line_item_id,omitempty"`
+	// Status is the new status of the shipment, SIT request or pre-approval request
+	Status string `json:"status"`
+}
+
+func newEvent(eventType models.WebhookEventType, data EventData) Event {
+	return Event{
+		ID:        uuid.Must(uuid.NewV4()),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+}
+
+// NewShipmentEvent returns an event about a change to a shipment's status
+func NewShipmentEvent(eventType models.WebhookEventType, shipmentID uuid.UUID, status models.ShipmentStatus) Event {
+	return newEvent(eventType, EventData{ShipmentID: shipmentID, Status: string(status)})
+}
+
+// NewStorageInTransitEvent returns an event about a decision on a SIT request
+func NewStorageInTransitEvent(eventType models.WebhookEventType, storageInTransit models.StorageInTransit) Event {
+	id := storageInTransit.ID
+	return newEvent(eventType, EventData{
+		ShipmentID:         storageInTransit.ShipmentID,
+		StorageInTransitID: &id,
+		Status:             string(storageInTransit.Status),
+	})
+}
+
+// NewPreApprovalRequestEvent returns an event about the approval of a pre-approval request
+func NewPreApprovalRequestEvent(shipmentLineItem models.ShipmentLineItem) Event {
+	id := shipmentLineItem.ID
+	return newEvent(models.WebhookEventTypePREAPPROVALREQUESTAPPROVED, EventData{
+		ShipmentID:         shipmentLineItem.ShipmentID,
+		ShipmentLineItemID: &id,
+		Status:             string(shipmentLineItem.Status),
+	})
+}
+
+// Publish writes a pending delivery of event to each active subscription to it of the TSP the shipment was last
+// offered to. Pass the transaction that saves the change, if there is one, so the event is only sent if it commits.
+func Publish(tx *pop.Connection, event Event) error {
+	var offers models.ShipmentOffers
+	err := tx.Where("shipment_id = ?", event.Data.ShipmentID).Order("created_at DESC").Limit(1).All(&offers)
+	if err != nil {
+		return errors.Wrap(err, "Could not find the TSP to publish to")
+	}
+	if len(offers) == 0 {
+		// No TSP has been offered the shipment yet, so there is nobody to tell
+		return nil
+	}
+
+	subscriptions, err := models.FetchActiveWebhookSubscriptionsForEvent(tx, offers[0].TransportationServiceProviderID, event.Type)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "Could not encode webhook event")
+	}
+	shipmentID := event.Data.ShipmentID
+	for _, subscription := range subscriptions {
+		delivery := models.WebhookDelivery{
+			WebhookSubscriptionID: subscription.ID,
+			EventID:               event.ID,
+			EventType:             event.Type,
+			ShipmentID:            &shipmentID,
+			Payload:               string(payload),
+			Status:                models.WebhookDeliveryStatusPENDING,
+			NextAttemptAt:         event.CreatedAt,
+		}
+		verrs, err := tx.ValidateAndCreate(&delivery)
+		if err != nil {
+			return errors.Wrap(err, "Failed to enqueue webhook delivery")
+		}
+		if verrs.HasAny() {
+			return errors.Errorf("Invalid %s webhook delivery: %s", event.Type, verrs.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/gobuffalo/pop/slices"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/testingsuite"
+)
+
+type WebhookSuite struct {
+	testingsuite.PopTestSuite
+	logger Logger
+}
+
+func TestWebhookSuite(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	s := &WebhookSuite{
+		PopTestSuite: testingsuite.NewPopTestSuite(testingsuite.CurrentPackage()),
+		logger:       logger,
+	}
+	suite.Run(t, s)
+}
+
+// receivedRequest is a webhook request received by a test receiver
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver starts an https server that records the requests it receives and responds with status
+func newReceiver(status int) (*httptest.Server, chan receivedRequest) {
+	received := make(chan receivedRequest, 10)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- receivedRequest{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	return server, received
+}
+
+func (suite *WebhookSuite) TestPublish() {
+	shipment := testdatagen.MakeDefaultShipment(suite.DB())
+	tsp := testdatagen.MakeDefaultTSP(suite.DB())
+	testdatagen.MakeShipmentOffer(suite.DB(), testdatagen.Assertions{
+		ShipmentOffer: models.ShipmentOffer{
+			ShipmentID:                      shipment.ID,
+			TransportationServiceProviderID: tsp.ID,
+		},
+	})
+	subscription := testdatagen.MakeWebhookSubscription(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: models.WebhookSubscription{TransportationServiceProviderID: tsp.ID},
+	})
+	testdatagen.MakeWebhookSubscription(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: models.WebhookSubscription{
+			TransportationServiceProviderID: tsp.ID,
+			EventTypes:                      slices.String{string(models.WebhookEventTypeSHIPMENTAWARDED)},
+		},
+	})
+	// Another TSP's subscription isn't sent events of shipments it wasn't offered
+	testdatagen.MakeDefaultWebhookSubscription(suite.DB())
+
+	event := NewShipmentEvent(models.WebhookEventTypeSHIPMENTAPPROVED, shipment.ID, models.ShipmentStatusAPPROVED)
+	suite.NoError(Publish(suite.DB(), event))
+
+	var deliveries models.WebhookDeliveries
+	suite.NoError(suite.DB().Where("event_id = ?", event.ID).All(&deliveries))
+	if suite.Len(deliveries, 1) {
+		delivery := deliveries[0]
+		suite.Equal(subscription.ID, delivery.WebhookSubscriptionID)
+		suite.Equal(models.WebhookEventTypeSHIPMENTAPPROVED, delivery.EventType)
+		suite.Equal(shipment.ID, *delivery.ShipmentID)
+		suite.Equal(models.WebhookDeliveryStatusPENDING, delivery.Status)
+
+		var sent Event
+		suite.NoError(json.Unmarshal([]byte(delivery.Payload), &sent))
+		suite.Equal(event.ID, sent.ID)
+		suite.Equal(shipment.ID, sent.Data.ShipmentID)
+		suite.Equal(string(models.ShipmentStatusAPPROVED), sent.Data.Status)
+	}
+
+	// Nobody is told about shipments that haven't been offered to a TSP
+	unoffered := testdatagen.MakeDefaultShipment(suite.DB())
+	suite.NoError(Publish(suite.DB(), NewShipmentEvent(models.WebhookEventTypeSHIPMENTAPPROVED, unoffered.ID, models.ShipmentStatusAPPROVED)))
+	count, err := suite.DB().Where("shipment_id = ?", unoffered.ID).Count(&models.WebhookDelivery{})
+	suite.NoError(err)
+	suite.Equal(0, count)
+}
+
+func (suite *WebhookSuite) TestDeliveryWorkerDelivers() {
+	ctx := context.Background()
+	server, received := newReceiver(http.StatusNoContent)
+	defer server.Close()
+
+	mockClock := clock.NewMock()
+	subscription := testdatagen.MakeWebhookSubscription(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: models.WebhookSubscription{URL: server.URL},
+	})
+	delivery := testdatagen.MakeWebhookDelivery(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: subscription,
+		WebhookDelivery:     models.WebhookDelivery{NextAttemptAt: mockClock.Now()},
+	})
+	worker := NewDeliveryWorker(suite.DB(), suite.logger, server.Client(), mockClock, DefaultRetryPolicy())
+
+	attempted, err := worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(1, attempted)
+
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(models.WebhookDeliveryStatusDELIVERED, delivery.Status)
+	suite.Equal(1, delivery.Attempts)
+	suite.Equal(http.StatusNoContent, *delivery.LastResponseStatus)
+	suite.NotNil(delivery.DeliveredAt)
+
+	// The receiver got the event, signed with the subscription's secret
+	request := <-received
+	suite.Equal(delivery.Payload, string(request.body))
+	suite.Equal(string(delivery.EventType), request.header.Get(EventTypeHeader))
+	suite.Equal(delivery.ID.String(), request.header.Get(DeliveryHeader))
+	suite.NoError(VerifySignature(subscription.Secret, request.header.Get(SignatureHeader), request.body, mockClock.Now(), time.Minute))
+
+	// Delivered events aren't sent again
+	attempted, err = worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(0, attempted)
+}
+
+func (suite *WebhookSuite) TestDeliveryWorkerRetries() {
+	ctx := context.Background()
+	server, received := newReceiver(http.StatusInternalServerError)
+	defer server.Close()
+
+	mockClock := clock.NewMock()
+	subscription := testdatagen.MakeWebhookSubscription(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: models.WebhookSubscription{URL: server.URL},
+	})
+	delivery := testdatagen.MakeWebhookDelivery(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: subscription,
+		WebhookDelivery:     models.WebhookDelivery{NextAttemptAt: mockClock.Now()},
+	})
+	retryPolicy := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Minute, MaxBackoff: time.Hour}
+	worker := NewDeliveryWorker(suite.DB(), suite.logger, server.Client(), mockClock, retryPolicy)
+
+	// The first failure is retried after the initial backoff
+	attempted, err := worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(1, attempted)
+	<-received
+
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(models.WebhookDeliveryStatusPENDING, delivery.Status)
+	suite.Equal(1, delivery.Attempts)
+	suite.Equal(http.StatusInternalServerError, *delivery.LastResponseStatus)
+	suite.NotNil(delivery.LastError)
+	suite.WithinDuration(mockClock.Now().Add(time.Minute), delivery.NextAttemptAt, time.Second)
+
+	// It isn't due until then
+	attempted, err = worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(0, attempted)
+
+	// The last failure dead-letters it
+	mockClock.Add(time.Minute)
+	attempted, err = worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(1, attempted)
+	<-received
+
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(models.WebhookDeliveryStatusDEADLETTER, delivery.Status)
+	suite.Equal(2, delivery.Attempts)
+}
+
+func (suite *WebhookSuite) TestDeliveryWorkerLeavesClaimedDeliveries() {
+	ctx := context.Background()
+	server, received := newReceiver(http.StatusOK)
+	defer server.Close()
+
+	mockClock := clock.NewMock()
+	subscription := testdatagen.MakeWebhookSubscription(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: models.WebhookSubscription{URL: server.URL},
+	})
+	delivery := testdatagen.MakeWebhookDelivery(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: subscription,
+		WebhookDelivery:     models.WebhookDelivery{NextAttemptAt: mockClock.Now()},
+	})
+
+	// Another worker claims the delivery
+	claimed, err := models.ClaimDueWebhookDeliveries(suite.DB(), mockClock.Now(), mockClock.Now().Add(time.Hour), deliveryBatchSize)
+	suite.NoError(err)
+	if suite.Len(claimed, 1) {
+		suite.Equal(delivery.ID, claimed[0].ID)
+		suite.Equal(subscription.URL, claimed[0].WebhookSubscription.URL)
+	}
+
+	worker := NewDeliveryWorker(suite.DB(), suite.logger, server.Client(), mockClock, DefaultRetryPolicy())
+	attempted, err := worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(0, attempted)
+
+	// It's picked up again once the claim expires without an outcome being saved
+	mockClock.Add(time.Hour)
+	attempted, err = worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(1, attempted)
+	<-received
+
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(models.WebhookDeliveryStatusDELIVERED, delivery.Status)
+}
+
+func (suite *WebhookSuite) TestDeliveryWorkerSkipsInactiveSubscriptions() {
+	ctx := context.Background()
+	server, received := newReceiver(http.StatusOK)
+	defer server.Close()
+
+	subscription := testdatagen.MakeWebhookSubscription(suite.DB(), testdatagen.Assertions{
+		WebhookSubscription: models.WebhookSubscription{URL: server.URL},
+	})
+	delivery := testdatagen.MakeWebhookDelivery(suite.DB(), testdatagen.Assertions{WebhookSubscription: subscription})
+	subscription.Active = false
+	suite.MustSave(&subscription)
+
+	worker := NewDeliveryWorker(suite.DB(), suite.logger, server.Client(), clock.New(), DefaultRetryPolicy())
+	attempted, err := worker.DeliverDue(ctx)
+	suite.NoError(err)
+	suite.Equal(1, attempted)
+
+	suite.NoError(suite.DB().Reload(&delivery))
+	suite.Equal(models.WebhookDeliveryStatusDEADLETTER, delivery.Status)
+	suite.Len(received, 0)
+}
+
+func (suite *WebhookSuite) TestRetryPolicyBackoff() {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Minute, MaxBackoff: 5 * time.Minute}
+	suite.Equal(time.Minute, policy.backoff(1))
+	suite.Equal(2*time.Minute, policy.backoff(2))
+	suite.Equal(4*time.Minute, policy.backoff(3))
+	suite.Equal(5*time.Minute, policy.backoff(4))
+}
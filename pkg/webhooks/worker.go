@@ -0,0 +1,245 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/gobuffalo/pop"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/cli"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// deliveryBatchSize is the most deliveries the worker claims and attempts at once
+const deliveryBatchSize = 50
+
+// defaultDeliveryLease is how long a claimed batch is left to the worker when its client has no timeout
+const defaultDeliveryLease = 30 * time.Minute
+
+// maxResponseBytes is the most of a subscriber's response the worker reads before closing it
+const maxResponseBytes = 64 * 1024
+
+// RetryPolicy controls how failed deliveries are retried
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts after which a delivery is dead-lettered
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failure, doubled after each further failure
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries for about a day before giving up on a delivery
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     6 * time.Hour,
+	}
+}
+
+// backoff returns how long to wait after a delivery has failed attempts times
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return wait
+}
+
+// DeliveryWorker sends pending webhook deliveries to their subscriptions
+type DeliveryWorker struct {
+	db          *pop.Connection
+	logger      Logger
+	client      *http.Client
+	clock       clock.Clock
+	retryPolicy RetryPolicy
+	lease       time.Duration
+}
+
+// NewDeliveryWorker returns a DeliveryWorker that sends deliveries with client
+func NewDeliveryWorker(db *pop.Connection, logger Logger, client *http.Client, clock clock.Clock, retryPolicy RetryPolicy) *DeliveryWorker {
+	// Deliveries are sent one after another, so a claim has to outlast sending the whole batch
+	lease := defaultDeliveryLease
+	if client.Timeout > 0 {
+		lease = deliveryBatchSize*client.Timeout + time.Minute
+	}
+	return &DeliveryWorker{
+		db:          db,
+		logger:      logger,
+		client:      client,
+		clock:       clock,
+		retryPolicy: retryPolicy,
+		lease:       lease,
+	}
+}
+
+// DeliverDue claims a batch of the pending deliveries that are due, attempts them and returns how many it claimed.
+// Nothing is locked while deliveries are sent, and each outcome is saved on its own, so one delivery failing to
+// save doesn't affect the others. Deliveries are sent at least once: one whose outcome fails to save is sent
+// again when its claim expires.
+func (w *DeliveryWorker) DeliverDue(ctx context.Context) (int, error) {
+	now := w.clock.Now()
+	deliveries, err := models.ClaimDueWebhookDeliveries(w.db, now, now.Add(w.lease), deliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			// The rest of the batch is picked up again when its claim expires
+			break
+		}
+		w.attempt(ctx, &delivery)
+		w.save(delivery)
+	}
+	return len(deliveries), nil
+}
+
+// save records the outcome of an attempt in its own transaction
+func (w *DeliveryWorker) save(delivery models.WebhookDelivery) {
+	err := w.db.Transaction(func(tx *pop.Connection) error {
+		verrs, err := tx.ValidateAndUpdate(&delivery)
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return errors.New(verrs.String())
+		}
+		return nil
+	})
+	if err != nil {
+		w.logger.Error("Failed to save webhook delivery",
+			zap.String("id", delivery.ID.String()),
+			zap.String("status", string(delivery.Status)),
+			zap.Error(err))
+	}
+}
+
+// send POSTs a delivery to its subscription's URL, signed with the subscription's secret, and returns the
+// response status
+func (w *DeliveryWorker) send(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, delivery.WebhookSubscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Wrap(err, "Could not create webhook request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventTypeHeader, string(delivery.EventType))
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(SignatureHeader, Sign(delivery.WebhookSubscription.Secret, w.clock.Now(), body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain some of the body so the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseBytes)) // #nosec the response is only discarded
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.Errorf("subscriber responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// attempt sends a delivery and records the outcome on it
+func (w *DeliveryWorker) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	now := w.clock.Now()
+	delivery.Attempts++
+
+	var err error
+	if !delivery.WebhookSubscription.Active {
+		err = errors.New("subscription is inactive")
+		// There's no point retrying until the subscription is reactivated
+		delivery.Attempts = w.retryPolicy.MaxAttempts
+	} else {
+		var status int
+		status, err = w.send(ctx, *delivery)
+		if status != 0 {
+			delivery.LastResponseStatus = &status
+		} else {
+			delivery.LastResponseStatus = nil
+		}
+	}
+
+	if err == nil {
+		delivery.Status = models.WebhookDeliveryStatusDELIVERED
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+		w.logger.Info("Delivered webhook",
+			zap.String("id", delivery.ID.String()),
+			zap.String("event_type", string(delivery.EventType)),
+			zap.Int("attempts", delivery.Attempts))
+		return
+	}
+
+	lastError := err.Error()
+	delivery.LastError = &lastError
+	if delivery.Attempts >= w.retryPolicy.MaxAttempts {
+		delivery.Status = models.WebhookDeliveryStatusDEADLETTER
+		w.logger.Error("Giving up on webhook",
+			zap.String("id", delivery.ID.String()),
+			zap.String("event_type", string(delivery.EventType)),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(err))
+		return
+	}
+
+	delivery.NextAttemptAt = now.Add(w.retryPolicy.backoff(delivery.Attempts))
+	w.logger.Info("Failed to deliver webhook, will retry",
+		zap.String("id", delivery.ID.String()),
+		zap.String("event_type", string(delivery.EventType)),
+		zap.Int("attempts", delivery.Attempts),
+		zap.Time("next_attempt_at", delivery.NextAttemptAt),
+		zap.Error(err))
+}
+
+// Run delivers due webhooks every interval until ctx is canceled
+func (w *DeliveryWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := w.clock.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep going while there's a backlog, then wait for the next tick
+		for {
+			attempted, err := w.DeliverDue(ctx)
+			if err != nil {
+				w.logger.Error("Failed to deliver webhooks", zap.Error(err))
+				break
+			}
+			if attempted < deliveryBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// InitDeliveryWorker initializes the webhook delivery worker from command line flags
+func InitDeliveryWorker(v *viper.Viper, db *pop.Connection, logger Logger) *DeliveryWorker {
+	retryPolicy := RetryPolicy{
+		MaxAttempts:    v.GetInt(cli.WebhookMaxAttemptsFlag),
+		InitialBackoff: v.GetDuration(cli.WebhookInitialBackoffFlag),
+		MaxBackoff:     v.GetDuration(cli.WebhookMaxBackoffFlag),
+	}
+	client := newDeliveryClient(v.GetDuration(cli.WebhookTimeoutFlag))
+	return NewDeliveryWorker(db, logger, client, clock.New(), retryPolicy)
+}